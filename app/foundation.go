@@ -16,6 +16,7 @@ package app
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"os"
 	"os/user"
@@ -23,6 +24,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/spf13/afero"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
 	"go.uber.org/zap"
 )
 
@@ -33,30 +35,35 @@ type Foundation interface {
 	Chdir(string) error
 	Getwd() (string, error)
 	DryRun() bool
+	// UpdateSnapshots returns whether the snapshot files should be rewritten instead of compared.
+	UpdateSnapshots() bool
 	User(username string) (*user.User, error)
 	UserGroup(u *user.User) (*user.Group, error)
 	UserHomeDir() (string, error)
 	LookupEnvVar(key string) (string, bool)
 	ExecCommand(ctx context.Context, name string, args []string) Command
 	HttpClient(tr *http.Transport) HttpClient
+	CustomHttpClient(client *http.Client) HttpClient
 	X509CertPool() X509CertPool
-	VegetaAttacker() VegetaAttacker
+	VegetaAttacker(opts ...func(*vegeta.Attacker)) VegetaAttacker
 	VegetaMetrics() VegetaMetrics
 	GenerateUuid() string
 	Sleep(ctx context.Context, duration time.Duration) error
+	Dial(ctx context.Context, network, address string) (net.Conn, error)
 }
 
 type DefaultFoundation struct {
-	logger *zap.SugaredLogger
-	fs     Fs
-	dryRun bool
+	logger          *zap.SugaredLogger
+	fs              Fs
+	dryRun          bool
+	updateSnapshots bool
 }
 
 var OsFs = afero.NewOsFs()
 
 var MemoryFs = afero.NewMemMapFs()
 
-func NewFoundation(logger *zap.SugaredLogger, dryRun bool) Foundation {
+func NewFoundation(logger *zap.SugaredLogger, dryRun bool, updateSnapshots bool) Foundation {
 	var fs afero.Fs
 	if dryRun {
 		fs = MemoryFs
@@ -64,9 +71,10 @@ func NewFoundation(logger *zap.SugaredLogger, dryRun bool) Foundation {
 		fs = OsFs
 	}
 	return &DefaultFoundation{
-		logger: logger,
-		fs:     fs,
-		dryRun: dryRun,
+		logger:          logger,
+		fs:              fs,
+		dryRun:          dryRun,
+		updateSnapshots: updateSnapshots,
 	}
 }
 
@@ -74,6 +82,10 @@ func (f *DefaultFoundation) DryRun() bool {
 	return f.dryRun
 }
 
+func (f *DefaultFoundation) UpdateSnapshots() bool {
+	return f.updateSnapshots
+}
+
 func (f *DefaultFoundation) Logger() *zap.SugaredLogger {
 	return f.logger
 }
@@ -124,6 +136,13 @@ func (f *DefaultFoundation) HttpClient(tr *http.Transport) HttpClient {
 	return NewRealHttpClient(tr)
 }
 
+func (f *DefaultFoundation) CustomHttpClient(client *http.Client) HttpClient {
+	if f.dryRun {
+		return NewDryRunHttpClient()
+	}
+	return NewCustomHttpClient(client)
+}
+
 func (f *DefaultFoundation) X509CertPool() X509CertPool {
 	return NewX509CertPool()
 }
@@ -132,11 +151,11 @@ func (f *DefaultFoundation) VegetaMetrics() VegetaMetrics {
 	return NewDefaultVegetaMetrics()
 }
 
-func (f *DefaultFoundation) VegetaAttacker() VegetaAttacker {
+func (f *DefaultFoundation) VegetaAttacker(opts ...func(*vegeta.Attacker)) VegetaAttacker {
 	if f.dryRun {
 		return NewDryRunVegetaAttacker()
 	}
-	return NewRealVegetaAttacker()
+	return NewRealVegetaAttacker(opts...)
 }
 
 func (f *DefaultFoundation) GenerateUuid() string {
@@ -158,3 +177,8 @@ func (f *DefaultFoundation) Sleep(ctx context.Context, duration time.Duration) e
 		return nil
 	}
 }
+
+func (f *DefaultFoundation) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, address)
+}
@@ -54,3 +54,9 @@ func NewRealHttpClient(tr *http.Transport) HttpClient {
 		},
 	}
 }
+
+func NewCustomHttpClient(client *http.Client) HttpClient {
+	return &RealHttpClient{
+		client: client,
+	}
+}
@@ -20,7 +20,7 @@ import (
 )
 
 type VegetaAttacker interface {
-	Attack(targeter vegeta.Targeter, rate vegeta.Rate, duration time.Duration, name string) <-chan *vegeta.Result
+	Attack(targeter vegeta.Targeter, pacer vegeta.Pacer, duration time.Duration, name string) <-chan *vegeta.Result
 }
 
 type RealVegetaAttacker struct {
@@ -29,16 +29,16 @@ type RealVegetaAttacker struct {
 
 func (a *RealVegetaAttacker) Attack(
 	targeter vegeta.Targeter,
-	rate vegeta.Rate,
+	pacer vegeta.Pacer,
 	duration time.Duration,
 	name string,
 ) <-chan *vegeta.Result {
-	return a.attacker.Attack(targeter, rate, duration, name)
+	return a.attacker.Attack(targeter, pacer, duration, name)
 }
 
-func NewRealVegetaAttacker() VegetaAttacker {
+func NewRealVegetaAttacker(opts ...func(*vegeta.Attacker)) VegetaAttacker {
 	return &RealVegetaAttacker{
-		attacker: vegeta.NewAttacker(),
+		attacker: vegeta.NewAttacker(opts...),
 	}
 }
 
@@ -46,7 +46,7 @@ type DryRunVegetaAttacker struct{}
 
 func (a *DryRunVegetaAttacker) Attack(
 	targeter vegeta.Targeter,
-	rate vegeta.Rate,
+	pacer vegeta.Pacer,
 	duration time.Duration,
 	name string,
 ) <-chan *vegeta.Result {
@@ -39,6 +39,7 @@ func Run() {
 			preFilter, _ := cmd.Flags().GetBool("pre-filter")
 			noEnvs, _ := cmd.Flags().GetBool("no-envs")
 			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			updateSnapshots, _ := cmd.Flags().GetBool("update-snapshots")
 
 			var err error
 			if debug {
@@ -50,7 +51,7 @@ func Run() {
 				panic(fmt.Sprintf("Cannot initialize zap logger: %v", err))
 			}
 
-			fnd := app.NewFoundation(logger.Sugar(), dryRun)
+			fnd := app.NewFoundation(logger.Sugar(), dryRun, updateSnapshots)
 
 			options := &run.Options{
 				ConfigPaths: configPaths,
@@ -79,6 +80,7 @@ func Run() {
 	runCmd.PersistentFlags().Bool("pre-filter", false, "Whether to filter instances in the initial phase for easier debugging")
 	runCmd.PersistentFlags().Bool("no-envs", false, "Prevent environment variables from superseding parameters")
 	runCmd.PersistentFlags().Bool("dry-run", false, "Activate dry-run mode")
+	runCmd.PersistentFlags().Bool("update-snapshots", false, "Rewrite snapshot files instead of comparing them")
 
 	var rootCmd = &cobra.Command{Use: "wst"}
 	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "", false,
@@ -110,8 +110,16 @@ func (f *NativeActionsFactory) parseExpectationAction(
 			structure = &types.MetricsExpectationAction{Service: meta.serviceName}
 		case "output":
 			structure = &types.OutputExpectationAction{Service: meta.serviceName}
+		case "received":
+			structure = &types.ReceivedExpectationAction{Service: meta.serviceName}
 		case "response":
 			structure = &types.ResponseExpectationAction{Service: meta.serviceName}
+		case "snapshot":
+			structure = &types.SnapshotExpectationAction{Service: meta.serviceName}
+		case "process":
+			structure = &types.ProcessExpectationAction{Service: meta.serviceName}
+		case "file":
+			structure = &types.FileExpectationAction{Service: meta.serviceName}
 		default:
 			return nil, errors.Errorf("invalid expectation key %s at %s", expKey, f.loc.String())
 		}
@@ -150,6 +158,11 @@ func (f *NativeActionsFactory) parseAction(
 		benchAction := &types.BenchAction{Service: meta.serviceName}
 		err = f.structParser(data, benchAction, path)
 		action = benchAction
+	case "eventually":
+		serviceNameAllowed = false
+		eventuallyAction := &types.EventuallyAction{}
+		err = f.structParser(data, eventuallyAction, path)
+		action = eventuallyAction
 	case "execute":
 		executeAction := &types.ExecuteAction{Service: meta.serviceName}
 		err = f.structParser(data, executeAction, path)
@@ -157,6 +170,18 @@ func (f *NativeActionsFactory) parseAction(
 	case "expect":
 		customNameAllowed = true
 		action, err = f.parseExpectationAction(meta, data, path)
+	case "fault_proxy":
+		faultProxyAction := &types.FaultProxyAction{Service: meta.serviceName}
+		err = f.structParser(data, faultProxyAction, path)
+		action = faultProxyAction
+	case "foreach":
+		foreachAction := &types.ForeachAction{Service: meta.serviceName}
+		err = f.structParser(data, foreachAction, path)
+		action = foreachAction
+	case "if":
+		ifAction := &types.IfAction{Service: meta.serviceName}
+		err = f.structParser(data, ifAction, path)
+		action = ifAction
 	case "not":
 		serviceNameAllowed = false
 		notAction := &types.NotAction{}
@@ -171,6 +196,11 @@ func (f *NativeActionsFactory) parseAction(
 		reloadAction := &types.ReloadAction{Service: meta.serviceName}
 		err = f.structParser(data, reloadAction, path)
 		action = reloadAction
+	case "repeat":
+		serviceNameAllowed = false
+		repeatAction := &types.RepeatAction{}
+		err = f.structParser(data, repeatAction, path)
+		action = repeatAction
 	case "request":
 		requestAction := &types.RequestAction{Service: meta.serviceName}
 		err = f.structParser(data, requestAction, path)
@@ -179,11 +209,19 @@ func (f *NativeActionsFactory) parseAction(
 		restartAction := &types.RestartAction{Service: meta.serviceName}
 		err = f.structParser(data, restartAction, path)
 		action = restartAction
+	case "scrape":
+		scrapeAction := &types.ScrapeAction{Service: meta.serviceName}
+		err = f.structParser(data, scrapeAction, path)
+		action = scrapeAction
 	case "sequential":
 		customNameAllowed = true
 		sequentialAction := &types.SequentialAction{Service: meta.serviceName, Name: meta.customName}
 		err = f.structParser(data, sequentialAction, path)
 		action = sequentialAction
+	case "signal":
+		signalAction := &types.SignalAction{Service: meta.serviceName}
+		err = f.structParser(data, signalAction, path)
+		action = signalAction
 	case "start":
 		startAction := &types.StartAction{Service: meta.serviceName}
 		err = f.structParser(data, startAction, path)
@@ -192,6 +230,10 @@ func (f *NativeActionsFactory) parseAction(
 		stopAction := &types.StopAction{Service: meta.serviceName}
 		err = f.structParser(data, stopAction, path)
 		action = stopAction
+	case "wait":
+		waitAction := &types.WaitAction{Service: meta.serviceName}
+		err = f.structParser(data, waitAction, path)
+		action = waitAction
 	default:
 		return nil, errors.Errorf("unknown action %s at %s", meta.actionName, f.loc.String())
 	}
@@ -91,6 +91,51 @@ func TestNativeActionsFactory_ParseActions(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Valid eventually action",
+			actions: []interface{}{
+				map[string]interface{}{
+					"eventually": map[string]interface{}{"interval": 100},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{"interval": 100},
+					path: staticPath,
+					err:  nil,
+				},
+			},
+			want: []types.Action{
+				&types.EventuallyAction{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid eventually action - service name present",
+			actions: []interface{}{
+				map[string]interface{}{
+					"eventually/serviceName": map[string]interface{}{"interval": 100},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{"interval": 100},
+					path: staticPath,
+					err:  nil,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			errMsg:  "service name not allowed for action eventually",
+		},
 		{
 			name: "Valid execute action",
 			actions: []interface{}{
@@ -397,6 +442,105 @@ func TestNativeActionsFactory_ParseActions(t *testing.T) {
 			wantErr: true,
 			errMsg:  "parsing failed",
 		},
+		{
+			name: "Valid snapshot expectation action",
+			actions: []interface{}{
+				map[string]interface{}{
+					"expect": map[string]interface{}{
+						"service": "serviceName",
+						"snapshot": map[string]interface{}{
+							"file": "snapshots/index.html",
+						},
+					},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{
+						"service": "serviceName",
+						"snapshot": map[string]interface{}{
+							"file": "snapshots/index.html",
+						},
+					},
+					path: "testPath",
+					err:  nil,
+				},
+			},
+			want: []types.Action{
+				&types.SnapshotExpectationAction{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Valid process expectation action",
+			actions: []interface{}{
+				map[string]interface{}{
+					"expect": map[string]interface{}{
+						"service": "serviceName",
+						"process": map[string]interface{}{
+							"scope": "children",
+						},
+					},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{
+						"service": "serviceName",
+						"process": map[string]interface{}{
+							"scope": "children",
+						},
+					},
+					path: "testPath",
+					err:  nil,
+				},
+			},
+			want: []types.Action{
+				&types.ProcessExpectationAction{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Valid file expectation action",
+			actions: []interface{}{
+				map[string]interface{}{
+					"expect": map[string]interface{}{
+						"service": "serviceName",
+						"file": map[string]interface{}{
+							"path": "slow.log",
+						},
+					},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{
+						"service": "serviceName",
+						"file": map[string]interface{}{
+							"path": "slow.log",
+						},
+					},
+					path: "testPath",
+					err:  nil,
+				},
+			},
+			want: []types.Action{
+				&types.FileExpectationAction{},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Invalid expectation key",
 			actions: []interface{}{
@@ -450,6 +594,41 @@ func TestNativeActionsFactory_ParseActions(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Valid received expectation action",
+			actions: []interface{}{
+				map[string]interface{}{
+					"expect": map[string]interface{}{
+						"service": "serviceName",
+						"timeout": 1000,
+						"received": map[string]interface{}{
+							"path": "/api",
+						},
+					},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{
+						"service": "serviceName",
+						"timeout": 1000,
+						"received": map[string]interface{}{
+							"path": "/api",
+						},
+					},
+					path: "testPath",
+					err:  nil,
+				},
+			},
+			want: []types.Action{
+				&types.ReceivedExpectationAction{},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Invalid expectation key",
 			actions: []interface{}{
@@ -470,6 +649,75 @@ func TestNativeActionsFactory_ParseActions(t *testing.T) {
 			wantErr: true,
 			errMsg:  "expression cannot have multiple types - additional key",
 		},
+		{
+			name: "Valid fault_proxy action",
+			actions: []interface{}{
+				map[string]interface{}{
+					"fault_proxy/serviceName": map[string]interface{}{"latency": 100},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{"latency": 100},
+					path: staticPath,
+					err:  nil,
+				},
+			},
+			want: []types.Action{
+				&types.FaultProxyAction{Service: "serviceName"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Valid foreach action",
+			actions: []interface{}{
+				map[string]interface{}{
+					"foreach/serviceName": map[string]interface{}{"parameter": "urls"},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{"parameter": "urls"},
+					path: staticPath,
+					err:  nil,
+				},
+			},
+			want: []types.Action{
+				&types.ForeachAction{Service: "serviceName"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Valid if action",
+			actions: []interface{}{
+				map[string]interface{}{
+					"if/serviceName": map[string]interface{}{"condition": "true"},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{"condition": "true"},
+					path: staticPath,
+					err:  nil,
+				},
+			},
+			want: []types.Action{
+				&types.IfAction{Service: "serviceName"},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Valid not action",
 			actions: []interface{}{
@@ -565,6 +813,51 @@ func TestNativeActionsFactory_ParseActions(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Valid repeat action",
+			actions: []interface{}{
+				map[string]interface{}{
+					"repeat": map[string]interface{}{"count": 3},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{"count": 3},
+					path: staticPath,
+					err:  nil,
+				},
+			},
+			want: []types.Action{
+				&types.RepeatAction{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid repeat action - service name present",
+			actions: []interface{}{
+				map[string]interface{}{
+					"repeat/serviceName": map[string]interface{}{"count": 3},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{"count": 3},
+					path: staticPath,
+					err:  nil,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			errMsg:  "service name not allowed for action repeat",
+		},
 		{
 			name: "Valid request action",
 			actions: []interface{}{
@@ -611,6 +904,29 @@ func TestNativeActionsFactory_ParseActions(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Valid scrape action",
+			actions: []interface{}{
+				map[string]interface{}{
+					"scrape/serviceName": map[string]interface{}{"format": "fpm"},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{"format": "fpm"},
+					path: staticPath,
+					err:  nil,
+				},
+			},
+			want: []types.Action{
+				&types.ScrapeAction{Service: "serviceName"},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Valid sequential action",
 			actions: []interface{}{
@@ -730,6 +1046,52 @@ func TestNativeActionsFactory_ParseActions(t *testing.T) {
 			wantErr: true,
 			errMsg:  "custom name not allowed for action stop",
 		},
+		{
+			name: "Valid signal action",
+			actions: []interface{}{
+				map[string]interface{}{
+					"signal/serviceName": map[string]interface{}{"signal": "SIGUSR1"},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{"signal": "SIGUSR1"},
+					path: staticPath,
+					err:  nil,
+				},
+			},
+			want: []types.Action{
+				&types.SignalAction{Service: "serviceName"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Valid wait action",
+			actions: []interface{}{
+				map[string]interface{}{
+					"wait/serviceName": map[string]interface{}{"port": 9000},
+				},
+			},
+			mockParseCalls: []struct {
+				data map[string]interface{}
+				path string
+				err  error
+			}{
+				{
+					data: map[string]interface{}{"port": 9000},
+					path: staticPath,
+					err:  nil,
+				},
+			},
+			want: []types.Action{
+				&types.WaitAction{Service: "serviceName"},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Unknown action",
 			actions: []interface{}{
@@ -48,6 +48,8 @@ const pathKey = "wst/path"
 const pathVirtual = "virtual"
 const pathCert = "cert"
 
+var jsonValueType = reflect.TypeOf(types.JSONValue{})
+
 type Parser interface {
 	ParseConfig(data map[string]interface{}, config *types.Config, configPath string) error
 	ParseStruct(data map[string]interface{}, structure interface{}, configPath string) error
@@ -442,6 +444,11 @@ func (p *ConfigParser) processMapValue(
 
 // assignField assigns the provided data to the fieldValue.
 func (p *ConfigParser) assignField(data interface{}, fieldValue reflect.Value, fieldName string, path string) error {
+	if fieldValue.Type() == jsonValueType {
+		// The raw value is kept as it is including the null value.
+		fieldValue.Set(reflect.ValueOf(types.JSONValue{Value: data, Set: true}))
+		return nil
+	}
 	switch fieldValue.Kind() {
 	case reflect.Struct:
 		dataMap, ok := data.(map[string]interface{})
@@ -499,6 +506,10 @@ func (p *ConfigParser) assignField(data interface{}, fieldValue reflect.Value, f
 		}
 		p.loc.EndArray()
 	default:
+		// The null value leaves the field unset.
+		if data == nil {
+			return nil
+		}
 		v := reflect.ValueOf(data)
 		targetType := fieldValue.Type()
 		sourceType := v.Type()
@@ -928,6 +928,8 @@ type AssignFieldTestStruct struct {
 	G int8
 	H int64
 	I []map[string]int
+	J interface{}
+	K types.JSONValue
 }
 
 type AssignFieldAnotherStruct struct {
@@ -971,6 +973,44 @@ func Test_ConfigParser_assignField(t *testing.T) {
 				B: 12,
 			},
 		},
+		{
+			name:      "assign struct interface field",
+			fieldName: "J",
+			data:      map[string]interface{}{"a": []interface{}{1, "b"}},
+			value:     &AssignFieldTestStruct{},
+			wantErr:   false,
+			expectedValue: &AssignFieldTestStruct{
+				J: map[string]interface{}{"a": []interface{}{1, "b"}},
+			},
+		},
+		{
+			name:          "assign null value keeps field unset",
+			fieldName:     "B",
+			data:          nil,
+			value:         &AssignFieldTestStruct{},
+			wantErr:       false,
+			expectedValue: &AssignFieldTestStruct{},
+		},
+		{
+			name:      "assign JSON value field",
+			fieldName: "K",
+			data:      []interface{}{1, "b"},
+			value:     &AssignFieldTestStruct{},
+			wantErr:   false,
+			expectedValue: &AssignFieldTestStruct{
+				K: types.JSONValue{Value: []interface{}{1, "b"}, Set: true},
+			},
+		},
+		{
+			name:      "assign null JSON value field marks it set",
+			fieldName: "K",
+			data:      nil,
+			value:     &AssignFieldTestStruct{},
+			wantErr:   false,
+			expectedValue: &AssignFieldTestStruct{
+				K: types.JSONValue{Set: true},
+			},
+		},
 		{
 			name:      "assign array field from array of string",
 			fieldName: "C",
@@ -1836,6 +1876,14 @@ func Test_ConfigParser_ParseConfig(t *testing.T) {
 										"response": map[string]interface{}{
 											"headers": map[string]interface{}{
 												"content-type": "application/json",
+												"x-powered-by": map[string]interface{}{
+													"absent": true,
+												},
+												"set-cookie": map[string]interface{}{
+													"values": []interface{}{"a=.*", "b=.*"},
+													"match":  "regexp",
+													"count":  2,
+												},
 											},
 											"body": map[string]interface{}{
 												"content": "{{ .Parameters.GetString \"body\" }}",
@@ -1937,9 +1985,10 @@ func Test_ConfigParser_ParseConfig(t *testing.T) {
 								},
 								map[string]interface{}{
 									"request": map[string]interface{}{
-										"service": "web_service",
-										"path":    "/upload",
-										"method":  "POST",
+										"service":  "web_service",
+										"path":     "/upload",
+										"method":   "POST",
+										"decoding": "decode",
 										"body": map[string]interface{}{
 											"content": "test",
 											"transfer": map[string]interface{}{
@@ -1954,7 +2003,50 @@ func Test_ConfigParser_ParseConfig(t *testing.T) {
 								map[string]interface{}{
 									"expect/web_service": map[string]interface{}{
 										"response": map[string]interface{}{
-											"status": 200,
+											"status":   200,
+											"encoding": "gzip",
+											"body": map[string]interface{}{
+												"file": "files/upload.bin",
+												"raw":  true,
+											},
+										},
+									},
+								},
+								map[string]interface{}{
+									"request": map[string]interface{}{
+										"service": "web_service",
+										"path":    "/upload",
+										"method":  "PUT",
+										"body": map[string]interface{}{
+											"file": "files/upload.bin",
+										},
+									},
+								},
+								map[string]interface{}{
+									"request": map[string]interface{}{
+										"service": "web_service",
+										"path":    "/upload",
+										"method":  "POST",
+										"body": map[string]interface{}{
+											"multipart": []interface{}{
+												map[string]interface{}{
+													"name": "upload",
+													"file": "files/upload.bin",
+												},
+											},
+										},
+									},
+								},
+								map[string]interface{}{
+									"expect/web_service": map[string]interface{}{
+										"snapshot": map[string]interface{}{
+											"file": "snapshots/index.html",
+											"normalize": []interface{}{
+												map[string]interface{}{
+													"pattern":     `\d+ ms`,
+													"replacement": "N ms",
+												},
+											},
 										},
 									},
 								},
@@ -2099,6 +2191,7 @@ func Test_ConfigParser_ParseConfig(t *testing.T) {
 									Path:       "/api/status",
 									EncodePath: true,
 									Method:     "GET",
+									Decoding:   "auto",
 								},
 								&types.CustomExpectationAction{
 									Service:   "web_service",
@@ -2131,7 +2224,8 @@ func Test_ConfigParser_ParseConfig(t *testing.T) {
 									When:      "on_success",
 									OnFailure: "fail",
 									Response: types.ResponseExpectation{
-										Request: "last",
+										Request:    "last",
+										Connection: "any",
 										Body: types.ResponseBody{
 											Content:        "OK",
 											Match:          "exact",
@@ -2149,6 +2243,7 @@ func Test_ConfigParser_ParseConfig(t *testing.T) {
 									Path:       "/upload",
 									EncodePath: true,
 									Method:     "POST",
+									Decoding:   "decode",
 									Body: types.RequestBody{
 										Content:        "test",
 										RenderTemplate: true,
@@ -2166,8 +2261,67 @@ func Test_ConfigParser_ParseConfig(t *testing.T) {
 									When:      "on_success",
 									OnFailure: "fail",
 									Response: types.ResponseExpectation{
+										Request:    "last",
+										Connection: "any",
+										Status:     200,
+										Encoding:   "gzip",
+										Body: types.ResponseBody{
+											Match:          "exact",
+											RenderTemplate: true,
+											File:           "/var/www/files/upload.bin",
+											Raw:            true,
+										},
+									},
+								},
+								&types.RequestAction{
+									Service:    "web_service",
+									Timeout:    0,
+									When:       "on_success",
+									OnFailure:  "fail",
+									Id:         "last",
+									Scheme:     "http",
+									Path:       "/upload",
+									EncodePath: true,
+									Method:     "PUT",
+									Decoding:   "auto",
+									Body: types.RequestBody{
+										RenderTemplate: true,
+										File:           "/var/www/files/upload.bin",
+									},
+								},
+								&types.RequestAction{
+									Service:    "web_service",
+									Timeout:    0,
+									When:       "on_success",
+									OnFailure:  "fail",
+									Id:         "last",
+									Scheme:     "http",
+									Path:       "/upload",
+									EncodePath: true,
+									Method:     "POST",
+									Decoding:   "auto",
+									Body: types.RequestBody{
+										RenderTemplate: true,
+										Multipart: []types.RequestBodyPart{
+											{
+												Name: "upload",
+												File: "/var/www/files/upload.bin",
+											},
+										},
+									},
+								},
+								&types.SnapshotExpectationAction{
+									Service:   "web_service",
+									When:      "on_success",
+									OnFailure: "fail",
+									Snapshot: types.SnapshotExpectation{
+										File:    "/var/www/snapshots/index.html",
+										Source:  "response",
 										Request: "last",
-										Status:  200,
+										Type:    "any",
+										Normalize: []types.SnapshotNormalization{
+											{Pattern: `\d+ ms`, Replacement: "N ms"},
+										},
 									},
 								},
 							},
@@ -2254,9 +2408,30 @@ func Test_ConfigParser_ParseConfig(t *testing.T) {
 											"body": "1",
 										},
 										Response: types.ResponseExpectation{
-											Request: "last",
-											Headers: map[string]string{
-												"content-type": "application/json",
+											Request:    "last",
+											Connection: "any",
+											Headers: types.ResponseHeaders{
+												"content-type": {
+													Value:         "application/json",
+													Match:         "exact",
+													Mode:          "any",
+													Count:         -1,
+													CountOperator: "eq",
+												},
+												"x-powered-by": {
+													Match:         "exact",
+													Mode:          "any",
+													Absent:        true,
+													Count:         -1,
+													CountOperator: "eq",
+												},
+												"set-cookie": {
+													Values:        []string{"a=.*", "b=.*"},
+													Match:         "regexp",
+													Mode:          "any",
+													Count:         2,
+													CountOperator: "eq",
+												},
 											},
 											Body: types.ResponseBody{
 												Content:        "{{ .Parameters.GetString \"body\" }}",
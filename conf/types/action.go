@@ -34,6 +34,13 @@ type OutputExpectation struct {
 	Type           string   `wst:"type,enum=stdout|stderr|any,default=any"`
 	RenderTemplate bool     `wst:"render_template,default=true"`
 	Messages       []string `wst:"messages"`
+	Expr           string   `wst:"expr"`
+	Mode           string   `wst:"mode,enum=expect|forbid,default=expect"`
+	Duration       int      `wst:"duration"`
+	From           string   `wst:"from"`
+	Checkpoint     string   `wst:"checkpoint"`
+	Count          int      `wst:"count,default=-1"`
+	CountOperator  string   `wst:"count_operator,enum=eq|ne|gt|ge|le|lt,default=eq"`
 }
 
 type OutputExpectationAction struct {
@@ -42,6 +49,7 @@ type OutputExpectationAction struct {
 	When      string            `wst:"when,enum=always|on_success|on_failure,default=on_success"`
 	OnFailure string            `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
 	Output    OutputExpectation `wst:"output"`
+	Actions   []Action          `wst:"actions,factory=createActions"`
 }
 
 type Headers map[string]string
@@ -50,13 +58,72 @@ type ResponseBody struct {
 	Content        string `wst:"content"`
 	Match          string `wst:"match,enum=exact|regexp|prefix|suffix|infix,default=exact"`
 	RenderTemplate bool   `wst:"render_template,default=true"`
+	SHA256         string `wst:"sha256"`
+	File           string `wst:"file,path=virtual"`
+	Raw            bool   `wst:"raw"`
+}
+
+type ResponseHeader struct {
+	Value         string   `wst:"value"`
+	Values        []string `wst:"values"`
+	Match         string   `wst:"match,enum=exact|regexp|prefix|suffix|infix,default=exact"`
+	Mode          string   `wst:"mode,enum=any|all,default=any"`
+	IgnoreCase    bool     `wst:"ignore_case"`
+	Absent        bool     `wst:"absent"`
+	Count         int      `wst:"count,default=-1"`
+	CountOperator string   `wst:"count_operator,enum=eq|ne|gt|ge|le|lt,default=eq"`
+}
+
+type ResponseHeaders map[string]ResponseHeader
+
+type ResponseTLSExpectation struct {
+	Version     string `wst:"version,enum=1.0|1.1|1.2|1.3"`
+	CipherSuite string `wst:"cipher_suite"`
+	ALPN        string `wst:"alpn"`
+	PeerSubject string `wst:"peer_subject"`
+}
+
+// JSONValue is a raw value of any type. Set is true when the value is present in the config which allows
+// an explicit null to be distinguished from a missing value.
+type JSONValue struct {
+	Value interface{}
+	Set   bool
+}
+
+type ResponseJSONCheck struct {
+	Path     string    `wst:"path"`
+	Exists   bool      `wst:"exists,default=true"`
+	Equals   JSONValue `wst:"equals"`
+	Type     string    `wst:"type,enum=null|boolean|number|string|array|object"`
+	Length   int       `wst:"length,default=-1"`
+	Regexp   string    `wst:"regexp"`
+	Operator string    `wst:"operator,enum=eq|ne|gt|lt|ge|le"`
+	Value    float64   `wst:"value"`
+}
+
+type ResponseJSON struct {
+	Checks []ResponseJSONCheck `wst:"checks"`
+	Schema string              `wst:"schema"`
 }
 
 type ResponseExpectation struct {
-	Request string       `wst:"request,default=last"`
-	Headers Headers      `wst:"headers"`
-	Body    ResponseBody `wst:"body,string=Content"`
-	Status  int          `wst:"status"`
+	Request    string                 `wst:"request,default=last"`
+	Headers    ResponseHeaders        `wst:"headers,string=Value"`
+	Body       ResponseBody           `wst:"body,string=Content"`
+	JSON       ResponseJSON           `wst:"json"`
+	Status     int                    `wst:"status"`
+	Connection string                 `wst:"connection,enum=any|reused|new,default=any"`
+	Protocol   string                 `wst:"protocol,enum=http1.1|http2|http3"`
+	Encoding   string                 `wst:"encoding,enum=identity|gzip|deflate|br|zstd"`
+	TLS        ResponseTLSExpectation `wst:"tls"`
+	Metrics    []ResponseMetric       `wst:"metrics"`
+	Expr       string                 `wst:"expr"`
+}
+
+type ResponseMetric struct {
+	Metric   string  `wst:"metric,enum=dns|connect|tls_handshake|ttfb|total|body_size"`
+	Operator string  `wst:"operator,enum=eq|ne|gt|lt|ge|le"`
+	Value    float64 `wst:"value"`
 }
 
 type ResponseExpectationAction struct {
@@ -67,15 +134,112 @@ type ResponseExpectationAction struct {
 	Response  ResponseExpectation `wst:"response"`
 }
 
+type ReceivedExpectation struct {
+	Method  string       `wst:"method"`
+	Path    string       `wst:"path"`
+	Count   int          `wst:"count,default=-1"`
+	Headers Headers      `wst:"headers"`
+	Body    ResponseBody `wst:"body,string=Content"`
+	Expr    string       `wst:"expr"`
+}
+
+type ReceivedExpectationAction struct {
+	Service   string              `wst:"service"`
+	Timeout   int                 `wst:"timeout"`
+	When      string              `wst:"when,enum=always|on_success|on_failure,default=on_success"`
+	OnFailure string              `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
+	Received  ReceivedExpectation `wst:"received"`
+}
+
+type SnapshotNormalization struct {
+	Pattern     string `wst:"pattern"`
+	Replacement string `wst:"replacement"`
+}
+
+type SnapshotExpectation struct {
+	File      string                  `wst:"file,path=virtual"`
+	Source    string                  `wst:"source,enum=response|output,default=response"`
+	Request   string                  `wst:"request,default=last"`
+	Command   string                  `wst:"command"`
+	Type      string                  `wst:"type,enum=stdout|stderr|any,default=any"`
+	Normalize []SnapshotNormalization `wst:"normalize"`
+	Expr      string                  `wst:"expr"`
+}
+
+type SnapshotExpectationAction struct {
+	Service   string              `wst:"service"`
+	Timeout   int                 `wst:"timeout"`
+	When      string              `wst:"when,enum=always|on_success|on_failure,default=on_success"`
+	OnFailure string              `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
+	Snapshot  SnapshotExpectation `wst:"snapshot"`
+}
+
+type ProcessMetric struct {
+	Metric      string  `wst:"metric,enum=count|rss|fds|uptime"`
+	Aggregation string  `wst:"aggregation,enum=max|min|avg,default=max"`
+	Operator    string  `wst:"operator,enum=eq|ne|gt|lt|ge|le"`
+	Value       float64 `wst:"value"`
+}
+
+type ProcessExpectation struct {
+	Scope      string          `wst:"scope,enum=all|main|children|descendants,default=children"`
+	Name       string          `wst:"name"`
+	Metrics    []ProcessMetric `wst:"metrics"`
+	Checkpoint string          `wst:"checkpoint"`
+	Since      string          `wst:"since"`
+	Pids       string          `wst:"pids,enum=any|changed|unchanged,default=any"`
+	Expr       string          `wst:"expr"`
+}
+
+type ProcessExpectationAction struct {
+	Service   string             `wst:"service"`
+	Timeout   int                `wst:"timeout"`
+	When      string             `wst:"when,enum=always|on_success|on_failure,default=on_success"`
+	OnFailure string             `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
+	Process   ProcessExpectation `wst:"process"`
+}
+
+type FileExpectation struct {
+	Path           string   `wst:"path"`
+	Dir            string   `wst:"dir,enum=conf|run|script,default=run"`
+	Exists         bool     `wst:"exists,default=true"`
+	Type           string   `wst:"type,enum=any|file|dir|socket,default=any"`
+	Mode           string   `wst:"mode"`
+	Owner          string   `wst:"owner"`
+	Group          string   `wst:"group"`
+	Size           int      `wst:"size,default=-1"`
+	SizeOperator   string   `wst:"size_operator,enum=eq|ne|gt|ge|le|lt,default=eq"`
+	Order          string   `wst:"order,enum=fixed|random,default=fixed"`
+	Match          string   `wst:"match,enum=exact|regexp|prefix|suffix|infix,default=exact"`
+	RenderTemplate bool     `wst:"render_template,default=true"`
+	Messages       []string `wst:"messages"`
+	Tail           bool     `wst:"tail"`
+	From           string   `wst:"from"`
+	Checkpoint     string   `wst:"checkpoint"`
+	Expr           string   `wst:"expr"`
+}
+
+type FileExpectationAction struct {
+	Service   string          `wst:"service"`
+	Timeout   int             `wst:"timeout"`
+	When      string          `wst:"when,enum=always|on_success|on_failure,default=on_success"`
+	OnFailure string          `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
+	File      FileExpectation `wst:"file"`
+}
+
 type MetricRule struct {
-	Metric   string  `wst:"metric"`
-	Operator string  `wst:"operator,enum=eq|ne|gt|lt|ge|le"`
-	Value    float64 `wst:"value"`
+	Metric      string  `wst:"metric"`
+	Operator    string  `wst:"operator,enum=eq|ne|gt|lt|ge|le"`
+	Value       float64 `wst:"value"`
+	From        int     `wst:"from"`
+	To          int     `wst:"to"`
+	Aggregation string  `wst:"aggregation,enum=max|min|avg|last"`
 }
 
 type MetricsExpectation struct {
 	Id    string       `wst:"id,default=last"`
 	Rules []MetricRule `wst:"rules"`
+	Expr  string       `wst:"expr"`
 }
 
 type MetricsExpectationAction struct {
@@ -109,8 +273,13 @@ type ExecuteAction struct {
 }
 
 type TLSClientConfig struct {
-	SkipVerify bool   `wst:"skip_verify,default=false"`
-	CACert     string `wst:"ca_certificate"`
+	SkipVerify   bool     `wst:"skip_verify,default=false"`
+	CACert       string   `wst:"ca_certificate"`
+	ClientCert   string   `wst:"client_certificate"`
+	ServerName   string   `wst:"server_name"`
+	MinVersion   string   `wst:"min_version,enum=1.0|1.1|1.2|1.3"`
+	MaxVersion   string   `wst:"max_version,enum=1.0|1.1|1.2|1.3"`
+	CipherSuites []string `wst:"cipher_suites"`
 }
 
 type TransferConfig struct {
@@ -120,10 +289,27 @@ type TransferConfig struct {
 	ContentLength int    `wst:"content_length"`
 }
 
+type RequestBodyPart struct {
+	Name     string  `wst:"name"`
+	Filename string  `wst:"filename"`
+	Headers  Headers `wst:"headers"`
+	Content  string  `wst:"content"`
+	Script   string  `wst:"script"`
+	File     string  `wst:"file,path=virtual"`
+	Size     int     `wst:"size"`
+}
+
 type RequestBody struct {
-	Content        string         `wst:"content"`
-	RenderTemplate bool           `wst:"render_template,default=true"`
-	Transfer       TransferConfig `wst:"transfer"`
+	Content        string            `wst:"content"`
+	RenderTemplate bool              `wst:"render_template,default=true"`
+	Transfer       TransferConfig    `wst:"transfer"`
+	Multipart      []RequestBodyPart `wst:"multipart"`
+	File           string            `wst:"file,path=virtual"`
+}
+
+type RedirectConfig struct {
+	Disabled bool `wst:"disabled"`
+	Max      int  `wst:"max"`
 }
 
 type RequestAction struct {
@@ -133,27 +319,66 @@ type RequestAction struct {
 	OnFailure  string          `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
 	Id         string          `wst:"id,default=last"`
 	Scheme     string          `wst:"scheme,enum=http|https,default=http"`
-	Protocols  []string        `wst:"protocols,enum=http1.1|http2"`
+	Protocols  []string        `wst:"protocols,enum=http1.1|http2|http3"`
 	Path       string          `wst:"path"`
 	EncodePath bool            `wst:"encode_path,default=true"`
 	Method     string          `wst:"method,enum=GET|HEAD|DELETE|POST|PUT|PATCH|PURGE,default=GET"`
 	Headers    Headers         `wst:"headers"`
 	Body       RequestBody     `wst:"body,string=Content"`
 	TLS        TLSClientConfig `wst:"tls"`
+	Session    string          `wst:"session"`
+	Redirects  RedirectConfig  `wst:"redirects"`
+	Decoding   string          `wst:"decoding,enum=auto|raw|decode,default=auto"`
+}
+
+type BenchTarget struct {
+	Path       string      `wst:"path"`
+	EncodePath bool        `wst:"encode_path,default=true"`
+	Method     string      `wst:"method,enum=GET|HEAD|DELETE|POST|PUT|PATCH|PURGE,default=GET"`
+	Headers    Headers     `wst:"headers"`
+	Body       RequestBody `wst:"body,string=Content"`
+	Weight     int         `wst:"weight,default=1"`
+}
+
+type BenchAttack struct {
+	Profile          string  `wst:"profile,enum=constant|ramp|step|sine|workers|max_rate"`
+	Frequency        int     `wst:"frequency"`
+	StartFrequency   int     `wst:"start_frequency"`
+	EndFrequency     int     `wst:"end_frequency"`
+	StepFrequency    int     `wst:"step_frequency"`
+	StepDuration     int     `wst:"step_duration"`
+	Amplitude        int     `wst:"amplitude"`
+	Period           int     `wst:"period"`
+	Workers          int     `wst:"workers"`
+	SuccessThreshold float64 `wst:"success_threshold"`
+}
+
+type BenchPhase struct {
+	Name     string      `wst:"name"`
+	Duration int         `wst:"duration"`
+	Attack   BenchAttack `wst:"attack"`
 }
 
 type BenchAction struct {
-	Service   string  `wst:"service"`
-	Timeout   int     `wst:"timeout"`
-	When      string  `wst:"when,enum=always|on_success|on_failure,default=on_success"`
-	OnFailure string  `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
-	Id        string  `wst:"id,default=last"`
-	Scheme    string  `wst:"scheme,enum=http|https,default=http"`
-	Path      string  `wst:"path"`
-	Method    string  `wst:"method,enum=GET|HEAD|DELETE|POST|PUT|PATCH|PURGE,default=GET"`
-	Headers   Headers `wst:"headers"`
-	Frequency int     `wst:"frequency"`
-	Duration  int     `wst:"duration"`
+	Service         string          `wst:"service"`
+	Timeout         int             `wst:"timeout"`
+	When            string          `wst:"when,enum=always|on_success|on_failure,default=on_success"`
+	OnFailure       string          `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
+	Id              string          `wst:"id,default=last"`
+	Scheme          string          `wst:"scheme,enum=http|https,default=http"`
+	Path            string          `wst:"path"`
+	EncodePath      bool            `wst:"encode_path,default=true"`
+	Method          string          `wst:"method,enum=GET|HEAD|DELETE|POST|PUT|PATCH|PURGE,default=GET"`
+	Headers         Headers         `wst:"headers"`
+	Body            RequestBody     `wst:"body,string=Content"`
+	Targets         []BenchTarget   `wst:"targets"`
+	Frequency       int             `wst:"frequency"`
+	Duration        int             `wst:"duration"`
+	Attack          BenchAttack     `wst:"attack"`
+	Phases          []BenchPhase    `wst:"phases"`
+	MetricsInterval int             `wst:"metrics_interval"`
+	Protocols       []string        `wst:"protocols,enum=http1.1|http2|http3"`
+	TLS             TLSClientConfig `wst:"tls"`
 }
 
 type ParallelAction struct {
@@ -172,6 +397,89 @@ type SequentialAction struct {
 	OnFailure string   `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
 }
 
+type RepeatAction struct {
+	Actions        []Action `wst:"actions,factory=createActions"`
+	Count          int      `wst:"count"`
+	Duration       int      `wst:"duration"`
+	Delay          int      `wst:"delay"`
+	IndexParameter string   `wst:"index_parameter,default=iteration"`
+	Timeout        int      `wst:"timeout"`
+	When           string   `wst:"when,enum=always|on_success|on_failure,default=on_success"`
+	OnFailure      string   `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
+}
+
+type ForeachAction struct {
+	Actions        []Action `wst:"actions,factory=createActions"`
+	Service        string   `wst:"service"`
+	Parameter      string   `wst:"parameter"`
+	ItemParameter  string   `wst:"item_parameter,default=item"`
+	IndexParameter string   `wst:"index_parameter,default=iteration"`
+	Timeout        int      `wst:"timeout"`
+	When           string   `wst:"when,enum=always|on_success|on_failure,default=on_success"`
+	OnFailure      string   `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
+}
+
+type EventuallyAction struct {
+	Actions        []Action `wst:"actions,factory=createActions"`
+	Interval       int      `wst:"interval,default=500"`
+	Backoff        float64  `wst:"backoff"`
+	MaxInterval    int      `wst:"max_interval"`
+	AttemptTimeout int      `wst:"attempt_timeout"`
+	Timeout        int      `wst:"timeout"`
+	When           string   `wst:"when,enum=always|on_success|on_failure,default=on_success"`
+	OnFailure      string   `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
+}
+
+type IfAction struct {
+	Service   string   `wst:"service"`
+	Condition string   `wst:"condition"`
+	Sandbox   string   `wst:"sandbox"`
+	Then      []Action `wst:"then,factory=createActions"`
+	Else      []Action `wst:"else,factory=createActions"`
+	Timeout   int      `wst:"timeout"`
+	When      string   `wst:"when,enum=always|on_success|on_failure,default=on_success"`
+	OnFailure string   `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
+}
+
+type WaitAction struct {
+	Service   string `wst:"service"`
+	Duration  int    `wst:"duration"`
+	Port      int32  `wst:"port"`
+	Socket    string `wst:"socket"`
+	File      string `wst:"file"`
+	Exit      bool   `wst:"exit,default=false"`
+	Interval  int    `wst:"interval,default=100"`
+	Timeout   int    `wst:"timeout"`
+	When      string `wst:"when,enum=always|on_success|on_failure,default=on_success"`
+	OnFailure string `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
+}
+
+type FaultProxyAction struct {
+	Service          string  `wst:"service"`
+	Port             int32   `wst:"port"`
+	Latency          int     `wst:"latency"`
+	Jitter           int     `wst:"jitter"`
+	Bandwidth        int     `wst:"bandwidth"`
+	ResetProbability float64 `wst:"reset_probability"`
+	StallProbability float64 `wst:"stall_probability"`
+	StallDuration    int     `wst:"stall_duration"`
+	Timeout          int     `wst:"timeout"`
+	When             string  `wst:"when,enum=always|on_success|on_failure,default=on_success"`
+	OnFailure        string  `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
+}
+
+type SignalAction struct {
+	Service   string `wst:"service"`
+	Signal    string `wst:"signal,enum=SIGTERM|SIGKILL|SIGINT|SIGQUIT|SIGHUP|SIGUSR1|SIGUSR2,default=SIGTERM"`
+	Process   string `wst:"process"`
+	Expect    string `wst:"expect,enum=any|exit|survive,default=any"`
+	Wait      int    `wst:"wait,default=1000"`
+	Interval  int    `wst:"interval,default=100"`
+	Timeout   int    `wst:"timeout"`
+	When      string `wst:"when,enum=always|on_success|on_failure,default=on_success"`
+	OnFailure string `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
+}
+
 type NotAction struct {
 	Action    Action `wst:"action,factory=createAction"`
 	Timeout   int    `wst:"timeout"`
@@ -203,6 +511,20 @@ type RestartAction struct {
 	OnFailure string   `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
 }
 
+type ScrapeAction struct {
+	Service   string   `wst:"service"`
+	Timeout   int      `wst:"timeout"`
+	When      string   `wst:"when,enum=always|on_success|on_failure,default=on_success"`
+	OnFailure string   `wst:"on_failure,enum=fail|ignore|skip,default=fail"`
+	Id        string   `wst:"id,default=last"`
+	Scheme    string   `wst:"scheme,enum=http|https,default=http"`
+	Path      string   `wst:"path"`
+	Headers   Headers  `wst:"headers"`
+	Format    string   `wst:"format,enum=fpm|nginx|prometheus,default=prometheus"`
+	Interval  int      `wst:"interval,default=1000"`
+	Actions   []Action `wst:"actions,factory=createActions"`
+}
+
 type StopAction struct {
 	Service   string   `wst:"service"`
 	Services  []string `wst:"services"`
@@ -50,6 +50,19 @@ type ServerTemplate struct {
 	File string `wst:"file,path"`
 }
 
+type MockRoute struct {
+	Method  string  `wst:"method"`
+	Path    string  `wst:"path"`
+	Status  int     `wst:"status,default=200"`
+	Headers Headers `wst:"headers"`
+	Body    string  `wst:"body"`
+	Delay   int     `wst:"delay"`
+}
+
+type MockServer struct {
+	Routes []MockRoute `wst:"routes"`
+}
+
 type Server struct {
 	Name       string                    `wst:"name"`
 	Tag        string                    `wst:"tag"`
@@ -62,4 +75,5 @@ type Server struct {
 	Sandboxes  map[string]Sandbox        `wst:"sandboxes,factory=createSandboxes"`
 	Parameters Parameters                `wst:"parameters,factory=createParameters"`
 	Actions    ServerActions             `wst:"actions"`
+	Mock       MockServer                `wst:"mock"`
 }
@@ -6,11 +6,13 @@ package app
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"os/user"
 	"time"
 
 	mock "github.com/stretchr/testify/mock"
+	"github.com/tsenart/vegeta/v12/lib"
 	"github.com/wstool/wst/app"
 	"go.uber.org/zap"
 )
@@ -148,6 +150,133 @@ func (_c *MockFoundation_CurrentUser_Call) RunAndReturn(run func() (*user.User,
 	return _c
 }
 
+// CustomHttpClient provides a mock function for the type MockFoundation
+func (_mock *MockFoundation) CustomHttpClient(client *http.Client) app.HttpClient {
+	ret := _mock.Called(client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CustomHttpClient")
+	}
+
+	var r0 app.HttpClient
+	if returnFunc, ok := ret.Get(0).(func(*http.Client) app.HttpClient); ok {
+		r0 = returnFunc(client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(app.HttpClient)
+		}
+	}
+	return r0
+}
+
+// MockFoundation_CustomHttpClient_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CustomHttpClient'
+type MockFoundation_CustomHttpClient_Call struct {
+	*mock.Call
+}
+
+// CustomHttpClient is a helper method to define mock.On call
+//   - client *http.Client
+func (_e *MockFoundation_Expecter) CustomHttpClient(client interface{}) *MockFoundation_CustomHttpClient_Call {
+	return &MockFoundation_CustomHttpClient_Call{Call: _e.mock.On("CustomHttpClient", client)}
+}
+
+func (_c *MockFoundation_CustomHttpClient_Call) Run(run func(client *http.Client)) *MockFoundation_CustomHttpClient_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *http.Client
+		if args[0] != nil {
+			arg0 = args[0].(*http.Client)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFoundation_CustomHttpClient_Call) Return(httpClient app.HttpClient) *MockFoundation_CustomHttpClient_Call {
+	_c.Call.Return(httpClient)
+	return _c
+}
+
+func (_c *MockFoundation_CustomHttpClient_Call) RunAndReturn(run func(client *http.Client) app.HttpClient) *MockFoundation_CustomHttpClient_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Dial provides a mock function for the type MockFoundation
+func (_mock *MockFoundation) Dial(ctx context.Context, network string, address string) (net.Conn, error) {
+	ret := _mock.Called(ctx, network, address)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Dial")
+	}
+
+	var r0 net.Conn
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (net.Conn, error)); ok {
+		return returnFunc(ctx, network, address)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) net.Conn); ok {
+		r0 = returnFunc(ctx, network, address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(net.Conn)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, network, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockFoundation_Dial_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Dial'
+type MockFoundation_Dial_Call struct {
+	*mock.Call
+}
+
+// Dial is a helper method to define mock.On call
+//   - ctx context.Context
+//   - network string
+//   - address string
+func (_e *MockFoundation_Expecter) Dial(ctx interface{}, network interface{}, address interface{}) *MockFoundation_Dial_Call {
+	return &MockFoundation_Dial_Call{Call: _e.mock.On("Dial", ctx, network, address)}
+}
+
+func (_c *MockFoundation_Dial_Call) Run(run func(ctx context.Context, network string, address string)) *MockFoundation_Dial_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFoundation_Dial_Call) Return(conn net.Conn, err error) *MockFoundation_Dial_Call {
+	_c.Call.Return(conn, err)
+	return _c
+}
+
+func (_c *MockFoundation_Dial_Call) RunAndReturn(run func(ctx context.Context, network string, address string) (net.Conn, error)) *MockFoundation_Dial_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DryRun provides a mock function for the type MockFoundation
 func (_mock *MockFoundation) DryRun() bool {
 	ret := _mock.Called()
@@ -616,6 +745,50 @@ func (_c *MockFoundation_Sleep_Call) RunAndReturn(run func(ctx context.Context,
 	return _c
 }
 
+// UpdateSnapshots provides a mock function for the type MockFoundation
+func (_mock *MockFoundation) UpdateSnapshots() bool {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSnapshots")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func() bool); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// MockFoundation_UpdateSnapshots_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSnapshots'
+type MockFoundation_UpdateSnapshots_Call struct {
+	*mock.Call
+}
+
+// UpdateSnapshots is a helper method to define mock.On call
+func (_e *MockFoundation_Expecter) UpdateSnapshots() *MockFoundation_UpdateSnapshots_Call {
+	return &MockFoundation_UpdateSnapshots_Call{Call: _e.mock.On("UpdateSnapshots")}
+}
+
+func (_c *MockFoundation_UpdateSnapshots_Call) Run(run func()) *MockFoundation_UpdateSnapshots_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockFoundation_UpdateSnapshots_Call) Return(b bool) *MockFoundation_UpdateSnapshots_Call {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *MockFoundation_UpdateSnapshots_Call) RunAndReturn(run func() bool) *MockFoundation_UpdateSnapshots_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // User provides a mock function for the type MockFoundation
 func (_mock *MockFoundation) User(username string) (*user.User, error) {
 	ret := _mock.Called(username)
@@ -794,16 +967,23 @@ func (_c *MockFoundation_UserHomeDir_Call) RunAndReturn(run func() (string, erro
 }
 
 // VegetaAttacker provides a mock function for the type MockFoundation
-func (_mock *MockFoundation) VegetaAttacker() app.VegetaAttacker {
-	ret := _mock.Called()
+func (_mock *MockFoundation) VegetaAttacker(opts ...func(*vegeta.Attacker)) app.VegetaAttacker {
+	// func(*vegeta.Attacker)
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
 
 	if len(ret) == 0 {
 		panic("no return value specified for VegetaAttacker")
 	}
 
 	var r0 app.VegetaAttacker
-	if returnFunc, ok := ret.Get(0).(func() app.VegetaAttacker); ok {
-		r0 = returnFunc()
+	if returnFunc, ok := ret.Get(0).(func(...func(*vegeta.Attacker)) app.VegetaAttacker); ok {
+		r0 = returnFunc(opts...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(app.VegetaAttacker)
@@ -818,13 +998,25 @@ type MockFoundation_VegetaAttacker_Call struct {
 }
 
 // VegetaAttacker is a helper method to define mock.On call
-func (_e *MockFoundation_Expecter) VegetaAttacker() *MockFoundation_VegetaAttacker_Call {
-	return &MockFoundation_VegetaAttacker_Call{Call: _e.mock.On("VegetaAttacker")}
+//   - opts ...func(*vegeta.Attacker)
+func (_e *MockFoundation_Expecter) VegetaAttacker(opts ...interface{}) *MockFoundation_VegetaAttacker_Call {
+	return &MockFoundation_VegetaAttacker_Call{Call: _e.mock.On("VegetaAttacker",
+		append([]interface{}{}, opts...)...)}
 }
 
-func (_c *MockFoundation_VegetaAttacker_Call) Run(run func()) *MockFoundation_VegetaAttacker_Call {
+func (_c *MockFoundation_VegetaAttacker_Call) Run(run func(opts ...func(*vegeta.Attacker))) *MockFoundation_VegetaAttacker_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 []func(*vegeta.Attacker)
+		variadicArgs := make([]func(*vegeta.Attacker), len(args)-0)
+		for i, a := range args[0:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*vegeta.Attacker))
+			}
+		}
+		arg0 = variadicArgs
+		run(
+			arg0...,
+		)
 	})
 	return _c
 }
@@ -834,7 +1026,7 @@ func (_c *MockFoundation_VegetaAttacker_Call) Return(vegetaAttacker app.VegetaAt
 	return _c
 }
 
-func (_c *MockFoundation_VegetaAttacker_Call) RunAndReturn(run func() app.VegetaAttacker) *MockFoundation_VegetaAttacker_Call {
+func (_c *MockFoundation_VegetaAttacker_Call) RunAndReturn(run func(opts ...func(*vegeta.Attacker)) app.VegetaAttacker) *MockFoundation_VegetaAttacker_Call {
 	_c.Call.Return(run)
 	return _c
 }
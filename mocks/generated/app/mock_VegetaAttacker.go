@@ -39,16 +39,16 @@ func (_m *MockVegetaAttacker) EXPECT() *MockVegetaAttacker_Expecter {
 }
 
 // Attack provides a mock function for the type MockVegetaAttacker
-func (_mock *MockVegetaAttacker) Attack(targeter vegeta.Targeter, rate vegeta.Rate, duration time.Duration, name string) <-chan *vegeta.Result {
-	ret := _mock.Called(targeter, rate, duration, name)
+func (_mock *MockVegetaAttacker) Attack(targeter vegeta.Targeter, pacer vegeta.Pacer, duration time.Duration, name string) <-chan *vegeta.Result {
+	ret := _mock.Called(targeter, pacer, duration, name)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Attack")
 	}
 
 	var r0 <-chan *vegeta.Result
-	if returnFunc, ok := ret.Get(0).(func(vegeta.Targeter, vegeta.Rate, time.Duration, string) <-chan *vegeta.Result); ok {
-		r0 = returnFunc(targeter, rate, duration, name)
+	if returnFunc, ok := ret.Get(0).(func(vegeta.Targeter, vegeta.Pacer, time.Duration, string) <-chan *vegeta.Result); ok {
+		r0 = returnFunc(targeter, pacer, duration, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(<-chan *vegeta.Result)
@@ -64,22 +64,22 @@ type MockVegetaAttacker_Attack_Call struct {
 
 // Attack is a helper method to define mock.On call
 //   - targeter vegeta.Targeter
-//   - rate vegeta.Rate
+//   - pacer vegeta.Pacer
 //   - duration time.Duration
 //   - name string
-func (_e *MockVegetaAttacker_Expecter) Attack(targeter interface{}, rate interface{}, duration interface{}, name interface{}) *MockVegetaAttacker_Attack_Call {
-	return &MockVegetaAttacker_Attack_Call{Call: _e.mock.On("Attack", targeter, rate, duration, name)}
+func (_e *MockVegetaAttacker_Expecter) Attack(targeter interface{}, pacer interface{}, duration interface{}, name interface{}) *MockVegetaAttacker_Attack_Call {
+	return &MockVegetaAttacker_Attack_Call{Call: _e.mock.On("Attack", targeter, pacer, duration, name)}
 }
 
-func (_c *MockVegetaAttacker_Attack_Call) Run(run func(targeter vegeta.Targeter, rate vegeta.Rate, duration time.Duration, name string)) *MockVegetaAttacker_Attack_Call {
+func (_c *MockVegetaAttacker_Attack_Call) Run(run func(targeter vegeta.Targeter, pacer vegeta.Pacer, duration time.Duration, name string)) *MockVegetaAttacker_Attack_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 vegeta.Targeter
 		if args[0] != nil {
 			arg0 = args[0].(vegeta.Targeter)
 		}
-		var arg1 vegeta.Rate
+		var arg1 vegeta.Pacer
 		if args[1] != nil {
-			arg1 = args[1].(vegeta.Rate)
+			arg1 = args[1].(vegeta.Pacer)
 		}
 		var arg2 time.Duration
 		if args[2] != nil {
@@ -104,7 +104,7 @@ func (_c *MockVegetaAttacker_Attack_Call) Return(resultCh <-chan *vegeta.Result)
 	return _c
 }
 
-func (_c *MockVegetaAttacker_Attack_Call) RunAndReturn(run func(targeter vegeta.Targeter, rate vegeta.Rate, duration time.Duration, name string) <-chan *vegeta.Result) *MockVegetaAttacker_Attack_Call {
+func (_c *MockVegetaAttacker_Attack_Call) RunAndReturn(run func(targeter vegeta.Targeter, pacer vegeta.Pacer, duration time.Duration, name string) <-chan *vegeta.Result) *MockVegetaAttacker_Attack_Call {
 	_c.Call.Return(run)
 	return _c
 }
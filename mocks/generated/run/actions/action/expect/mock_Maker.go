@@ -112,6 +112,80 @@ func (_c *MockMaker_MakeCustomAction_Call) RunAndReturn(run func(config *types.C
 	return _c
 }
 
+// MakeFileAction provides a mock function for the type MockMaker
+func (_mock *MockMaker) MakeFileAction(config *types.FileExpectationAction, sl services.ServiceLocator, defaultTimeout int) (action.Action, error) {
+	ret := _mock.Called(config, sl, defaultTimeout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MakeFileAction")
+	}
+
+	var r0 action.Action
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*types.FileExpectationAction, services.ServiceLocator, int) (action.Action, error)); ok {
+		return returnFunc(config, sl, defaultTimeout)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*types.FileExpectationAction, services.ServiceLocator, int) action.Action); ok {
+		r0 = returnFunc(config, sl, defaultTimeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(action.Action)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(*types.FileExpectationAction, services.ServiceLocator, int) error); ok {
+		r1 = returnFunc(config, sl, defaultTimeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMaker_MakeFileAction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MakeFileAction'
+type MockMaker_MakeFileAction_Call struct {
+	*mock.Call
+}
+
+// MakeFileAction is a helper method to define mock.On call
+//   - config *types.FileExpectationAction
+//   - sl services.ServiceLocator
+//   - defaultTimeout int
+func (_e *MockMaker_Expecter) MakeFileAction(config interface{}, sl interface{}, defaultTimeout interface{}) *MockMaker_MakeFileAction_Call {
+	return &MockMaker_MakeFileAction_Call{Call: _e.mock.On("MakeFileAction", config, sl, defaultTimeout)}
+}
+
+func (_c *MockMaker_MakeFileAction_Call) Run(run func(config *types.FileExpectationAction, sl services.ServiceLocator, defaultTimeout int)) *MockMaker_MakeFileAction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *types.FileExpectationAction
+		if args[0] != nil {
+			arg0 = args[0].(*types.FileExpectationAction)
+		}
+		var arg1 services.ServiceLocator
+		if args[1] != nil {
+			arg1 = args[1].(services.ServiceLocator)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMaker_MakeFileAction_Call) Return(action1 action.Action, err error) *MockMaker_MakeFileAction_Call {
+	_c.Call.Return(action1, err)
+	return _c
+}
+
+func (_c *MockMaker_MakeFileAction_Call) RunAndReturn(run func(config *types.FileExpectationAction, sl services.ServiceLocator, defaultTimeout int) (action.Action, error)) *MockMaker_MakeFileAction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // MakeMetricsAction provides a mock function for the type MockMaker
 func (_mock *MockMaker) MakeMetricsAction(config *types.MetricsExpectationAction, sl services.ServiceLocator, defaultTimeout int) (action.Action, error) {
 	ret := _mock.Called(config, sl, defaultTimeout)
@@ -187,8 +261,8 @@ func (_c *MockMaker_MakeMetricsAction_Call) RunAndReturn(run func(config *types.
 }
 
 // MakeOutputAction provides a mock function for the type MockMaker
-func (_mock *MockMaker) MakeOutputAction(config *types.OutputExpectationAction, sl services.ServiceLocator, defaultTimeout int) (action.Action, error) {
-	ret := _mock.Called(config, sl, defaultTimeout)
+func (_mock *MockMaker) MakeOutputAction(config *types.OutputExpectationAction, sl services.ServiceLocator, defaultTimeout int, actionMaker action.Maker) (action.Action, error) {
+	ret := _mock.Called(config, sl, defaultTimeout, actionMaker)
 
 	if len(ret) == 0 {
 		panic("no return value specified for MakeOutputAction")
@@ -196,18 +270,18 @@ func (_mock *MockMaker) MakeOutputAction(config *types.OutputExpectationAction,
 
 	var r0 action.Action
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(*types.OutputExpectationAction, services.ServiceLocator, int) (action.Action, error)); ok {
-		return returnFunc(config, sl, defaultTimeout)
+	if returnFunc, ok := ret.Get(0).(func(*types.OutputExpectationAction, services.ServiceLocator, int, action.Maker) (action.Action, error)); ok {
+		return returnFunc(config, sl, defaultTimeout, actionMaker)
 	}
-	if returnFunc, ok := ret.Get(0).(func(*types.OutputExpectationAction, services.ServiceLocator, int) action.Action); ok {
-		r0 = returnFunc(config, sl, defaultTimeout)
+	if returnFunc, ok := ret.Get(0).(func(*types.OutputExpectationAction, services.ServiceLocator, int, action.Maker) action.Action); ok {
+		r0 = returnFunc(config, sl, defaultTimeout, actionMaker)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(action.Action)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(*types.OutputExpectationAction, services.ServiceLocator, int) error); ok {
-		r1 = returnFunc(config, sl, defaultTimeout)
+	if returnFunc, ok := ret.Get(1).(func(*types.OutputExpectationAction, services.ServiceLocator, int, action.Maker) error); ok {
+		r1 = returnFunc(config, sl, defaultTimeout, actionMaker)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -223,11 +297,12 @@ type MockMaker_MakeOutputAction_Call struct {
 //   - config *types.OutputExpectationAction
 //   - sl services.ServiceLocator
 //   - defaultTimeout int
-func (_e *MockMaker_Expecter) MakeOutputAction(config interface{}, sl interface{}, defaultTimeout interface{}) *MockMaker_MakeOutputAction_Call {
-	return &MockMaker_MakeOutputAction_Call{Call: _e.mock.On("MakeOutputAction", config, sl, defaultTimeout)}
+//   - actionMaker action.Maker
+func (_e *MockMaker_Expecter) MakeOutputAction(config interface{}, sl interface{}, defaultTimeout interface{}, actionMaker interface{}) *MockMaker_MakeOutputAction_Call {
+	return &MockMaker_MakeOutputAction_Call{Call: _e.mock.On("MakeOutputAction", config, sl, defaultTimeout, actionMaker)}
 }
 
-func (_c *MockMaker_MakeOutputAction_Call) Run(run func(config *types.OutputExpectationAction, sl services.ServiceLocator, defaultTimeout int)) *MockMaker_MakeOutputAction_Call {
+func (_c *MockMaker_MakeOutputAction_Call) Run(run func(config *types.OutputExpectationAction, sl services.ServiceLocator, defaultTimeout int, actionMaker action.Maker)) *MockMaker_MakeOutputAction_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 *types.OutputExpectationAction
 		if args[0] != nil {
@@ -241,10 +316,15 @@ func (_c *MockMaker_MakeOutputAction_Call) Run(run func(config *types.OutputExpe
 		if args[2] != nil {
 			arg2 = args[2].(int)
 		}
+		var arg3 action.Maker
+		if args[3] != nil {
+			arg3 = args[3].(action.Maker)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
@@ -255,7 +335,155 @@ func (_c *MockMaker_MakeOutputAction_Call) Return(action1 action.Action, err err
 	return _c
 }
 
-func (_c *MockMaker_MakeOutputAction_Call) RunAndReturn(run func(config *types.OutputExpectationAction, sl services.ServiceLocator, defaultTimeout int) (action.Action, error)) *MockMaker_MakeOutputAction_Call {
+func (_c *MockMaker_MakeOutputAction_Call) RunAndReturn(run func(config *types.OutputExpectationAction, sl services.ServiceLocator, defaultTimeout int, actionMaker action.Maker) (action.Action, error)) *MockMaker_MakeOutputAction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MakeProcessAction provides a mock function for the type MockMaker
+func (_mock *MockMaker) MakeProcessAction(config *types.ProcessExpectationAction, sl services.ServiceLocator, defaultTimeout int) (action.Action, error) {
+	ret := _mock.Called(config, sl, defaultTimeout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MakeProcessAction")
+	}
+
+	var r0 action.Action
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*types.ProcessExpectationAction, services.ServiceLocator, int) (action.Action, error)); ok {
+		return returnFunc(config, sl, defaultTimeout)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*types.ProcessExpectationAction, services.ServiceLocator, int) action.Action); ok {
+		r0 = returnFunc(config, sl, defaultTimeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(action.Action)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(*types.ProcessExpectationAction, services.ServiceLocator, int) error); ok {
+		r1 = returnFunc(config, sl, defaultTimeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMaker_MakeProcessAction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MakeProcessAction'
+type MockMaker_MakeProcessAction_Call struct {
+	*mock.Call
+}
+
+// MakeProcessAction is a helper method to define mock.On call
+//   - config *types.ProcessExpectationAction
+//   - sl services.ServiceLocator
+//   - defaultTimeout int
+func (_e *MockMaker_Expecter) MakeProcessAction(config interface{}, sl interface{}, defaultTimeout interface{}) *MockMaker_MakeProcessAction_Call {
+	return &MockMaker_MakeProcessAction_Call{Call: _e.mock.On("MakeProcessAction", config, sl, defaultTimeout)}
+}
+
+func (_c *MockMaker_MakeProcessAction_Call) Run(run func(config *types.ProcessExpectationAction, sl services.ServiceLocator, defaultTimeout int)) *MockMaker_MakeProcessAction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *types.ProcessExpectationAction
+		if args[0] != nil {
+			arg0 = args[0].(*types.ProcessExpectationAction)
+		}
+		var arg1 services.ServiceLocator
+		if args[1] != nil {
+			arg1 = args[1].(services.ServiceLocator)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMaker_MakeProcessAction_Call) Return(action1 action.Action, err error) *MockMaker_MakeProcessAction_Call {
+	_c.Call.Return(action1, err)
+	return _c
+}
+
+func (_c *MockMaker_MakeProcessAction_Call) RunAndReturn(run func(config *types.ProcessExpectationAction, sl services.ServiceLocator, defaultTimeout int) (action.Action, error)) *MockMaker_MakeProcessAction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MakeReceivedAction provides a mock function for the type MockMaker
+func (_mock *MockMaker) MakeReceivedAction(config *types.ReceivedExpectationAction, sl services.ServiceLocator, defaultTimeout int) (action.Action, error) {
+	ret := _mock.Called(config, sl, defaultTimeout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MakeReceivedAction")
+	}
+
+	var r0 action.Action
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*types.ReceivedExpectationAction, services.ServiceLocator, int) (action.Action, error)); ok {
+		return returnFunc(config, sl, defaultTimeout)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*types.ReceivedExpectationAction, services.ServiceLocator, int) action.Action); ok {
+		r0 = returnFunc(config, sl, defaultTimeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(action.Action)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(*types.ReceivedExpectationAction, services.ServiceLocator, int) error); ok {
+		r1 = returnFunc(config, sl, defaultTimeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMaker_MakeReceivedAction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MakeReceivedAction'
+type MockMaker_MakeReceivedAction_Call struct {
+	*mock.Call
+}
+
+// MakeReceivedAction is a helper method to define mock.On call
+//   - config *types.ReceivedExpectationAction
+//   - sl services.ServiceLocator
+//   - defaultTimeout int
+func (_e *MockMaker_Expecter) MakeReceivedAction(config interface{}, sl interface{}, defaultTimeout interface{}) *MockMaker_MakeReceivedAction_Call {
+	return &MockMaker_MakeReceivedAction_Call{Call: _e.mock.On("MakeReceivedAction", config, sl, defaultTimeout)}
+}
+
+func (_c *MockMaker_MakeReceivedAction_Call) Run(run func(config *types.ReceivedExpectationAction, sl services.ServiceLocator, defaultTimeout int)) *MockMaker_MakeReceivedAction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *types.ReceivedExpectationAction
+		if args[0] != nil {
+			arg0 = args[0].(*types.ReceivedExpectationAction)
+		}
+		var arg1 services.ServiceLocator
+		if args[1] != nil {
+			arg1 = args[1].(services.ServiceLocator)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMaker_MakeReceivedAction_Call) Return(action1 action.Action, err error) *MockMaker_MakeReceivedAction_Call {
+	_c.Call.Return(action1, err)
+	return _c
+}
+
+func (_c *MockMaker_MakeReceivedAction_Call) RunAndReturn(run func(config *types.ReceivedExpectationAction, sl services.ServiceLocator, defaultTimeout int) (action.Action, error)) *MockMaker_MakeReceivedAction_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -333,3 +561,77 @@ func (_c *MockMaker_MakeResponseAction_Call) RunAndReturn(run func(config *types
 	_c.Call.Return(run)
 	return _c
 }
+
+// MakeSnapshotAction provides a mock function for the type MockMaker
+func (_mock *MockMaker) MakeSnapshotAction(config *types.SnapshotExpectationAction, sl services.ServiceLocator, defaultTimeout int) (action.Action, error) {
+	ret := _mock.Called(config, sl, defaultTimeout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MakeSnapshotAction")
+	}
+
+	var r0 action.Action
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*types.SnapshotExpectationAction, services.ServiceLocator, int) (action.Action, error)); ok {
+		return returnFunc(config, sl, defaultTimeout)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*types.SnapshotExpectationAction, services.ServiceLocator, int) action.Action); ok {
+		r0 = returnFunc(config, sl, defaultTimeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(action.Action)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(*types.SnapshotExpectationAction, services.ServiceLocator, int) error); ok {
+		r1 = returnFunc(config, sl, defaultTimeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMaker_MakeSnapshotAction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MakeSnapshotAction'
+type MockMaker_MakeSnapshotAction_Call struct {
+	*mock.Call
+}
+
+// MakeSnapshotAction is a helper method to define mock.On call
+//   - config *types.SnapshotExpectationAction
+//   - sl services.ServiceLocator
+//   - defaultTimeout int
+func (_e *MockMaker_Expecter) MakeSnapshotAction(config interface{}, sl interface{}, defaultTimeout interface{}) *MockMaker_MakeSnapshotAction_Call {
+	return &MockMaker_MakeSnapshotAction_Call{Call: _e.mock.On("MakeSnapshotAction", config, sl, defaultTimeout)}
+}
+
+func (_c *MockMaker_MakeSnapshotAction_Call) Run(run func(config *types.SnapshotExpectationAction, sl services.ServiceLocator, defaultTimeout int)) *MockMaker_MakeSnapshotAction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *types.SnapshotExpectationAction
+		if args[0] != nil {
+			arg0 = args[0].(*types.SnapshotExpectationAction)
+		}
+		var arg1 services.ServiceLocator
+		if args[1] != nil {
+			arg1 = args[1].(services.ServiceLocator)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMaker_MakeSnapshotAction_Call) Return(action1 action.Action, err error) *MockMaker_MakeSnapshotAction_Call {
+	_c.Call.Return(action1, err)
+	return _c
+}
+
+func (_c *MockMaker_MakeSnapshotAction_Call) RunAndReturn(run func(config *types.SnapshotExpectationAction, sl services.ServiceLocator, defaultTimeout int) (action.Action, error)) *MockMaker_MakeSnapshotAction_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"io"
 	"os"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 	"github.com/wstool/wst/run/environments/environment"
@@ -215,6 +216,81 @@ func (_c *MockEnvironment_ExecTaskCommand_Call) RunAndReturn(run func(ctx contex
 	return _c
 }
 
+// ExecTaskProcessesSignal provides a mock function for the type MockEnvironment
+func (_mock *MockEnvironment) ExecTaskProcessesSignal(ctx context.Context, ss *environment.ServiceSettings, target task.Task, processes []environment.Process, signal os.Signal) error {
+	ret := _mock.Called(ctx, ss, target, processes, signal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExecTaskProcessesSignal")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *environment.ServiceSettings, task.Task, []environment.Process, os.Signal) error); ok {
+		r0 = returnFunc(ctx, ss, target, processes, signal)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockEnvironment_ExecTaskProcessesSignal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExecTaskProcessesSignal'
+type MockEnvironment_ExecTaskProcessesSignal_Call struct {
+	*mock.Call
+}
+
+// ExecTaskProcessesSignal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ss *environment.ServiceSettings
+//   - target task.Task
+//   - processes []environment.Process
+//   - signal os.Signal
+func (_e *MockEnvironment_Expecter) ExecTaskProcessesSignal(ctx interface{}, ss interface{}, target interface{}, processes interface{}, signal interface{}) *MockEnvironment_ExecTaskProcessesSignal_Call {
+	return &MockEnvironment_ExecTaskProcessesSignal_Call{Call: _e.mock.On("ExecTaskProcessesSignal", ctx, ss, target, processes, signal)}
+}
+
+func (_c *MockEnvironment_ExecTaskProcessesSignal_Call) Run(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task, processes []environment.Process, signal os.Signal)) *MockEnvironment_ExecTaskProcessesSignal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *environment.ServiceSettings
+		if args[1] != nil {
+			arg1 = args[1].(*environment.ServiceSettings)
+		}
+		var arg2 task.Task
+		if args[2] != nil {
+			arg2 = args[2].(task.Task)
+		}
+		var arg3 []environment.Process
+		if args[3] != nil {
+			arg3 = args[3].([]environment.Process)
+		}
+		var arg4 os.Signal
+		if args[4] != nil {
+			arg4 = args[4].(os.Signal)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEnvironment_ExecTaskProcessesSignal_Call) Return(err error) *MockEnvironment_ExecTaskProcessesSignal_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockEnvironment_ExecTaskProcessesSignal_Call) RunAndReturn(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task, processes []environment.Process, signal os.Signal) error) *MockEnvironment_ExecTaskProcessesSignal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ExecTaskSignal provides a mock function for the type MockEnvironment
 func (_mock *MockEnvironment) ExecTaskSignal(ctx context.Context, ss *environment.ServiceSettings, target task.Task, signal os.Signal) error {
 	ret := _mock.Called(ctx, ss, target, signal)
@@ -284,6 +360,84 @@ func (_c *MockEnvironment_ExecTaskSignal_Call) RunAndReturn(run func(ctx context
 	return _c
 }
 
+// FileExists provides a mock function for the type MockEnvironment
+func (_mock *MockEnvironment) FileExists(ctx context.Context, ss *environment.ServiceSettings, target task.Task, path string) (bool, error) {
+	ret := _mock.Called(ctx, ss, target, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FileExists")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *environment.ServiceSettings, task.Task, string) (bool, error)); ok {
+		return returnFunc(ctx, ss, target, path)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *environment.ServiceSettings, task.Task, string) bool); ok {
+		r0 = returnFunc(ctx, ss, target, path)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *environment.ServiceSettings, task.Task, string) error); ok {
+		r1 = returnFunc(ctx, ss, target, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEnvironment_FileExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FileExists'
+type MockEnvironment_FileExists_Call struct {
+	*mock.Call
+}
+
+// FileExists is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ss *environment.ServiceSettings
+//   - target task.Task
+//   - path string
+func (_e *MockEnvironment_Expecter) FileExists(ctx interface{}, ss interface{}, target interface{}, path interface{}) *MockEnvironment_FileExists_Call {
+	return &MockEnvironment_FileExists_Call{Call: _e.mock.On("FileExists", ctx, ss, target, path)}
+}
+
+func (_c *MockEnvironment_FileExists_Call) Run(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task, path string)) *MockEnvironment_FileExists_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *environment.ServiceSettings
+		if args[1] != nil {
+			arg1 = args[1].(*environment.ServiceSettings)
+		}
+		var arg2 task.Task
+		if args[2] != nil {
+			arg2 = args[2].(task.Task)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEnvironment_FileExists_Call) Return(b bool, err error) *MockEnvironment_FileExists_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockEnvironment_FileExists_Call) RunAndReturn(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task, path string) (bool, error)) *MockEnvironment_FileExists_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Init provides a mock function for the type MockEnvironment
 func (_mock *MockEnvironment) Init(ctx context.Context) error {
 	ret := _mock.Called(ctx)
@@ -549,6 +703,164 @@ func (_c *MockEnvironment_Output_Call) RunAndReturn(run func(ctx context.Context
 	return _c
 }
 
+// OutputSince provides a mock function for the type MockEnvironment
+func (_mock *MockEnvironment) OutputSince(ctx context.Context, target task.Task, outputType output.Type, since time.Time) (io.Reader, error) {
+	ret := _mock.Called(ctx, target, outputType, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OutputSince")
+	}
+
+	var r0 io.Reader
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, task.Task, output.Type, time.Time) (io.Reader, error)); ok {
+		return returnFunc(ctx, target, outputType, since)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, task.Task, output.Type, time.Time) io.Reader); ok {
+		r0 = returnFunc(ctx, target, outputType, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.Reader)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, task.Task, output.Type, time.Time) error); ok {
+		r1 = returnFunc(ctx, target, outputType, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEnvironment_OutputSince_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OutputSince'
+type MockEnvironment_OutputSince_Call struct {
+	*mock.Call
+}
+
+// OutputSince is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target task.Task
+//   - outputType output.Type
+//   - since time.Time
+func (_e *MockEnvironment_Expecter) OutputSince(ctx interface{}, target interface{}, outputType interface{}, since interface{}) *MockEnvironment_OutputSince_Call {
+	return &MockEnvironment_OutputSince_Call{Call: _e.mock.On("OutputSince", ctx, target, outputType, since)}
+}
+
+func (_c *MockEnvironment_OutputSince_Call) Run(run func(ctx context.Context, target task.Task, outputType output.Type, since time.Time)) *MockEnvironment_OutputSince_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 task.Task
+		if args[1] != nil {
+			arg1 = args[1].(task.Task)
+		}
+		var arg2 output.Type
+		if args[2] != nil {
+			arg2 = args[2].(output.Type)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEnvironment_OutputSince_Call) Return(reader io.Reader, err error) *MockEnvironment_OutputSince_Call {
+	_c.Call.Return(reader, err)
+	return _c
+}
+
+func (_c *MockEnvironment_OutputSince_Call) RunAndReturn(run func(ctx context.Context, target task.Task, outputType output.Type, since time.Time) (io.Reader, error)) *MockEnvironment_OutputSince_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PortReady provides a mock function for the type MockEnvironment
+func (_mock *MockEnvironment) PortReady(ctx context.Context, ss *environment.ServiceSettings, target task.Task, port int32) (bool, error) {
+	ret := _mock.Called(ctx, ss, target, port)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PortReady")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *environment.ServiceSettings, task.Task, int32) (bool, error)); ok {
+		return returnFunc(ctx, ss, target, port)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *environment.ServiceSettings, task.Task, int32) bool); ok {
+		r0 = returnFunc(ctx, ss, target, port)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *environment.ServiceSettings, task.Task, int32) error); ok {
+		r1 = returnFunc(ctx, ss, target, port)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEnvironment_PortReady_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PortReady'
+type MockEnvironment_PortReady_Call struct {
+	*mock.Call
+}
+
+// PortReady is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ss *environment.ServiceSettings
+//   - target task.Task
+//   - port int32
+func (_e *MockEnvironment_Expecter) PortReady(ctx interface{}, ss interface{}, target interface{}, port interface{}) *MockEnvironment_PortReady_Call {
+	return &MockEnvironment_PortReady_Call{Call: _e.mock.On("PortReady", ctx, ss, target, port)}
+}
+
+func (_c *MockEnvironment_PortReady_Call) Run(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task, port int32)) *MockEnvironment_PortReady_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *environment.ServiceSettings
+		if args[1] != nil {
+			arg1 = args[1].(*environment.ServiceSettings)
+		}
+		var arg2 task.Task
+		if args[2] != nil {
+			arg2 = args[2].(task.Task)
+		}
+		var arg3 int32
+		if args[3] != nil {
+			arg3 = args[3].(int32)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEnvironment_PortReady_Call) Return(b bool, err error) *MockEnvironment_PortReady_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockEnvironment_PortReady_Call) RunAndReturn(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task, port int32) (bool, error)) *MockEnvironment_PortReady_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PortsEnd provides a mock function for the type MockEnvironment
 func (_mock *MockEnvironment) PortsEnd() int32 {
 	ret := _mock.Called()
@@ -1034,3 +1346,313 @@ func (_c *MockEnvironment_ServicePrivateAddress_Call) RunAndReturn(run func(serv
 	_c.Call.Return(run)
 	return _c
 }
+
+// TaskFile provides a mock function for the type MockEnvironment
+func (_mock *MockEnvironment) TaskFile(ctx context.Context, ss *environment.ServiceSettings, target task.Task, path string, offset int64) (*environment.File, error) {
+	ret := _mock.Called(ctx, ss, target, path, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskFile")
+	}
+
+	var r0 *environment.File
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *environment.ServiceSettings, task.Task, string, int64) (*environment.File, error)); ok {
+		return returnFunc(ctx, ss, target, path, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *environment.ServiceSettings, task.Task, string, int64) *environment.File); ok {
+		r0 = returnFunc(ctx, ss, target, path, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*environment.File)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *environment.ServiceSettings, task.Task, string, int64) error); ok {
+		r1 = returnFunc(ctx, ss, target, path, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEnvironment_TaskFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TaskFile'
+type MockEnvironment_TaskFile_Call struct {
+	*mock.Call
+}
+
+// TaskFile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ss *environment.ServiceSettings
+//   - target task.Task
+//   - path string
+//   - offset int64
+func (_e *MockEnvironment_Expecter) TaskFile(ctx interface{}, ss interface{}, target interface{}, path interface{}, offset interface{}) *MockEnvironment_TaskFile_Call {
+	return &MockEnvironment_TaskFile_Call{Call: _e.mock.On("TaskFile", ctx, ss, target, path, offset)}
+}
+
+func (_c *MockEnvironment_TaskFile_Call) Run(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task, path string, offset int64)) *MockEnvironment_TaskFile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *environment.ServiceSettings
+		if args[1] != nil {
+			arg1 = args[1].(*environment.ServiceSettings)
+		}
+		var arg2 task.Task
+		if args[2] != nil {
+			arg2 = args[2].(task.Task)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 int64
+		if args[4] != nil {
+			arg4 = args[4].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEnvironment_TaskFile_Call) Return(file *environment.File, err error) *MockEnvironment_TaskFile_Call {
+	_c.Call.Return(file, err)
+	return _c
+}
+
+func (_c *MockEnvironment_TaskFile_Call) RunAndReturn(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task, path string, offset int64) (*environment.File, error)) *MockEnvironment_TaskFile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TaskProcesses provides a mock function for the type MockEnvironment
+func (_mock *MockEnvironment) TaskProcesses(ctx context.Context, ss *environment.ServiceSettings, target task.Task) ([]environment.Process, error) {
+	ret := _mock.Called(ctx, ss, target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskProcesses")
+	}
+
+	var r0 []environment.Process
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *environment.ServiceSettings, task.Task) ([]environment.Process, error)); ok {
+		return returnFunc(ctx, ss, target)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *environment.ServiceSettings, task.Task) []environment.Process); ok {
+		r0 = returnFunc(ctx, ss, target)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]environment.Process)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *environment.ServiceSettings, task.Task) error); ok {
+		r1 = returnFunc(ctx, ss, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEnvironment_TaskProcesses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TaskProcesses'
+type MockEnvironment_TaskProcesses_Call struct {
+	*mock.Call
+}
+
+// TaskProcesses is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ss *environment.ServiceSettings
+//   - target task.Task
+func (_e *MockEnvironment_Expecter) TaskProcesses(ctx interface{}, ss interface{}, target interface{}) *MockEnvironment_TaskProcesses_Call {
+	return &MockEnvironment_TaskProcesses_Call{Call: _e.mock.On("TaskProcesses", ctx, ss, target)}
+}
+
+func (_c *MockEnvironment_TaskProcesses_Call) Run(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task)) *MockEnvironment_TaskProcesses_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *environment.ServiceSettings
+		if args[1] != nil {
+			arg1 = args[1].(*environment.ServiceSettings)
+		}
+		var arg2 task.Task
+		if args[2] != nil {
+			arg2 = args[2].(task.Task)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEnvironment_TaskProcesses_Call) Return(processs []environment.Process, err error) *MockEnvironment_TaskProcesses_Call {
+	_c.Call.Return(processs, err)
+	return _c
+}
+
+func (_c *MockEnvironment_TaskProcesses_Call) RunAndReturn(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task) ([]environment.Process, error)) *MockEnvironment_TaskProcesses_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TaskRunning provides a mock function for the type MockEnvironment
+func (_mock *MockEnvironment) TaskRunning(ctx context.Context, ss *environment.ServiceSettings, target task.Task) (bool, error) {
+	ret := _mock.Called(ctx, ss, target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskRunning")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *environment.ServiceSettings, task.Task) (bool, error)); ok {
+		return returnFunc(ctx, ss, target)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *environment.ServiceSettings, task.Task) bool); ok {
+		r0 = returnFunc(ctx, ss, target)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *environment.ServiceSettings, task.Task) error); ok {
+		r1 = returnFunc(ctx, ss, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEnvironment_TaskRunning_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TaskRunning'
+type MockEnvironment_TaskRunning_Call struct {
+	*mock.Call
+}
+
+// TaskRunning is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ss *environment.ServiceSettings
+//   - target task.Task
+func (_e *MockEnvironment_Expecter) TaskRunning(ctx interface{}, ss interface{}, target interface{}) *MockEnvironment_TaskRunning_Call {
+	return &MockEnvironment_TaskRunning_Call{Call: _e.mock.On("TaskRunning", ctx, ss, target)}
+}
+
+func (_c *MockEnvironment_TaskRunning_Call) Run(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task)) *MockEnvironment_TaskRunning_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *environment.ServiceSettings
+		if args[1] != nil {
+			arg1 = args[1].(*environment.ServiceSettings)
+		}
+		var arg2 task.Task
+		if args[2] != nil {
+			arg2 = args[2].(task.Task)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEnvironment_TaskRunning_Call) Return(b bool, err error) *MockEnvironment_TaskRunning_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockEnvironment_TaskRunning_Call) RunAndReturn(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task) (bool, error)) *MockEnvironment_TaskRunning_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UdsReady provides a mock function for the type MockEnvironment
+func (_mock *MockEnvironment) UdsReady(ctx context.Context, ss *environment.ServiceSettings, target task.Task, path string) (bool, error) {
+	ret := _mock.Called(ctx, ss, target, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UdsReady")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *environment.ServiceSettings, task.Task, string) (bool, error)); ok {
+		return returnFunc(ctx, ss, target, path)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *environment.ServiceSettings, task.Task, string) bool); ok {
+		r0 = returnFunc(ctx, ss, target, path)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *environment.ServiceSettings, task.Task, string) error); ok {
+		r1 = returnFunc(ctx, ss, target, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEnvironment_UdsReady_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UdsReady'
+type MockEnvironment_UdsReady_Call struct {
+	*mock.Call
+}
+
+// UdsReady is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ss *environment.ServiceSettings
+//   - target task.Task
+//   - path string
+func (_e *MockEnvironment_Expecter) UdsReady(ctx interface{}, ss interface{}, target interface{}, path interface{}) *MockEnvironment_UdsReady_Call {
+	return &MockEnvironment_UdsReady_Call{Call: _e.mock.On("UdsReady", ctx, ss, target, path)}
+}
+
+func (_c *MockEnvironment_UdsReady_Call) Run(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task, path string)) *MockEnvironment_UdsReady_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *environment.ServiceSettings
+		if args[1] != nil {
+			arg1 = args[1].(*environment.ServiceSettings)
+		}
+		var arg2 task.Task
+		if args[2] != nil {
+			arg2 = args[2].(task.Task)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEnvironment_UdsReady_Call) Return(b bool, err error) *MockEnvironment_UdsReady_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockEnvironment_UdsReady_Call) RunAndReturn(run func(ctx context.Context, ss *environment.ServiceSettings, target task.Task, path string) (bool, error)) *MockEnvironment_UdsReady_Call {
+	_c.Call.Return(run)
+	return _c
+}
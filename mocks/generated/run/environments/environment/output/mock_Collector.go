@@ -7,6 +7,7 @@ package output
 import (
 	"context"
 	"io"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 	"github.com/wstool/wst/run/environments/environment/output"
@@ -237,6 +238,80 @@ func (_c *MockCollector_Reader_Call) RunAndReturn(run func(ctx context.Context,
 	return _c
 }
 
+// ReaderSince provides a mock function for the type MockCollector
+func (_mock *MockCollector) ReaderSince(ctx context.Context, outputType output.Type, since time.Time) (io.Reader, error) {
+	ret := _mock.Called(ctx, outputType, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReaderSince")
+	}
+
+	var r0 io.Reader
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, output.Type, time.Time) (io.Reader, error)); ok {
+		return returnFunc(ctx, outputType, since)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, output.Type, time.Time) io.Reader); ok {
+		r0 = returnFunc(ctx, outputType, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.Reader)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, output.Type, time.Time) error); ok {
+		r1 = returnFunc(ctx, outputType, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCollector_ReaderSince_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReaderSince'
+type MockCollector_ReaderSince_Call struct {
+	*mock.Call
+}
+
+// ReaderSince is a helper method to define mock.On call
+//   - ctx context.Context
+//   - outputType output.Type
+//   - since time.Time
+func (_e *MockCollector_Expecter) ReaderSince(ctx interface{}, outputType interface{}, since interface{}) *MockCollector_ReaderSince_Call {
+	return &MockCollector_ReaderSince_Call{Call: _e.mock.On("ReaderSince", ctx, outputType, since)}
+}
+
+func (_c *MockCollector_ReaderSince_Call) Run(run func(ctx context.Context, outputType output.Type, since time.Time)) *MockCollector_ReaderSince_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 output.Type
+		if args[1] != nil {
+			arg1 = args[1].(output.Type)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCollector_ReaderSince_Call) Return(reader io.Reader, err error) *MockCollector_ReaderSince_Call {
+	_c.Call.Return(reader, err)
+	return _c
+}
+
+func (_c *MockCollector_ReaderSince_Call) RunAndReturn(run func(ctx context.Context, outputType output.Type, since time.Time) (io.Reader, error)) *MockCollector_ReaderSince_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Start provides a mock function for the type MockCollector
 func (_mock *MockCollector) Start(stdoutPipe io.ReadCloser, stderrPipe io.ReadCloser) error {
 	ret := _mock.Called(stdoutPipe, stderrPipe)
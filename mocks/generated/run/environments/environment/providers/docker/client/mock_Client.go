@@ -133,6 +133,216 @@ func (_c *MockClient_ContainerCreate_Call) RunAndReturn(run func(ctx context.Con
 	return _c
 }
 
+// ContainerExecAttach provides a mock function for the type MockClient
+func (_mock *MockClient) ContainerExecAttach(ctx context.Context, execID string, config container.ExecAttachOptions) (types.HijackedResponse, error) {
+	ret := _mock.Called(ctx, execID, config)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerExecAttach")
+	}
+
+	var r0 types.HijackedResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, container.ExecAttachOptions) (types.HijackedResponse, error)); ok {
+		return returnFunc(ctx, execID, config)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, container.ExecAttachOptions) types.HijackedResponse); ok {
+		r0 = returnFunc(ctx, execID, config)
+	} else {
+		r0 = ret.Get(0).(types.HijackedResponse)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, container.ExecAttachOptions) error); ok {
+		r1 = returnFunc(ctx, execID, config)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_ContainerExecAttach_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ContainerExecAttach'
+type MockClient_ContainerExecAttach_Call struct {
+	*mock.Call
+}
+
+// ContainerExecAttach is a helper method to define mock.On call
+//   - ctx context.Context
+//   - execID string
+//   - config container.ExecAttachOptions
+func (_e *MockClient_Expecter) ContainerExecAttach(ctx interface{}, execID interface{}, config interface{}) *MockClient_ContainerExecAttach_Call {
+	return &MockClient_ContainerExecAttach_Call{Call: _e.mock.On("ContainerExecAttach", ctx, execID, config)}
+}
+
+func (_c *MockClient_ContainerExecAttach_Call) Run(run func(ctx context.Context, execID string, config container.ExecAttachOptions)) *MockClient_ContainerExecAttach_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 container.ExecAttachOptions
+		if args[2] != nil {
+			arg2 = args[2].(container.ExecAttachOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_ContainerExecAttach_Call) Return(hijackedResponse types.HijackedResponse, err error) *MockClient_ContainerExecAttach_Call {
+	_c.Call.Return(hijackedResponse, err)
+	return _c
+}
+
+func (_c *MockClient_ContainerExecAttach_Call) RunAndReturn(run func(ctx context.Context, execID string, config container.ExecAttachOptions) (types.HijackedResponse, error)) *MockClient_ContainerExecAttach_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ContainerExecCreate provides a mock function for the type MockClient
+func (_mock *MockClient) ContainerExecCreate(ctx context.Context, containerID string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+	ret := _mock.Called(ctx, containerID, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerExecCreate")
+	}
+
+	var r0 container.ExecCreateResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, container.ExecOptions) (container.ExecCreateResponse, error)); ok {
+		return returnFunc(ctx, containerID, options)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, container.ExecOptions) container.ExecCreateResponse); ok {
+		r0 = returnFunc(ctx, containerID, options)
+	} else {
+		r0 = ret.Get(0).(container.ExecCreateResponse)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, container.ExecOptions) error); ok {
+		r1 = returnFunc(ctx, containerID, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_ContainerExecCreate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ContainerExecCreate'
+type MockClient_ContainerExecCreate_Call struct {
+	*mock.Call
+}
+
+// ContainerExecCreate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - containerID string
+//   - options container.ExecOptions
+func (_e *MockClient_Expecter) ContainerExecCreate(ctx interface{}, containerID interface{}, options interface{}) *MockClient_ContainerExecCreate_Call {
+	return &MockClient_ContainerExecCreate_Call{Call: _e.mock.On("ContainerExecCreate", ctx, containerID, options)}
+}
+
+func (_c *MockClient_ContainerExecCreate_Call) Run(run func(ctx context.Context, containerID string, options container.ExecOptions)) *MockClient_ContainerExecCreate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 container.ExecOptions
+		if args[2] != nil {
+			arg2 = args[2].(container.ExecOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_ContainerExecCreate_Call) Return(v container.ExecCreateResponse, err error) *MockClient_ContainerExecCreate_Call {
+	_c.Call.Return(v, err)
+	return _c
+}
+
+func (_c *MockClient_ContainerExecCreate_Call) RunAndReturn(run func(ctx context.Context, containerID string, options container.ExecOptions) (container.ExecCreateResponse, error)) *MockClient_ContainerExecCreate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ContainerExecInspect provides a mock function for the type MockClient
+func (_mock *MockClient) ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error) {
+	ret := _mock.Called(ctx, execID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerExecInspect")
+	}
+
+	var r0 container.ExecInspect
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (container.ExecInspect, error)); ok {
+		return returnFunc(ctx, execID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) container.ExecInspect); ok {
+		r0 = returnFunc(ctx, execID)
+	} else {
+		r0 = ret.Get(0).(container.ExecInspect)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, execID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_ContainerExecInspect_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ContainerExecInspect'
+type MockClient_ContainerExecInspect_Call struct {
+	*mock.Call
+}
+
+// ContainerExecInspect is a helper method to define mock.On call
+//   - ctx context.Context
+//   - execID string
+func (_e *MockClient_Expecter) ContainerExecInspect(ctx interface{}, execID interface{}) *MockClient_ContainerExecInspect_Call {
+	return &MockClient_ContainerExecInspect_Call{Call: _e.mock.On("ContainerExecInspect", ctx, execID)}
+}
+
+func (_c *MockClient_ContainerExecInspect_Call) Run(run func(ctx context.Context, execID string)) *MockClient_ContainerExecInspect_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_ContainerExecInspect_Call) Return(execInspect container.ExecInspect, err error) *MockClient_ContainerExecInspect_Call {
+	_c.Call.Return(execInspect, err)
+	return _c
+}
+
+func (_c *MockClient_ContainerExecInspect_Call) RunAndReturn(run func(ctx context.Context, execID string) (container.ExecInspect, error)) *MockClient_ContainerExecInspect_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ContainerInspect provides a mock function for the type MockClient
 func (_mock *MockClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
 	ret := _mock.Called(ctx, containerID)
@@ -199,6 +409,69 @@ func (_c *MockClient_ContainerInspect_Call) RunAndReturn(run func(ctx context.Co
 	return _c
 }
 
+// ContainerKill provides a mock function for the type MockClient
+func (_mock *MockClient) ContainerKill(ctx context.Context, containerID string, signal string) error {
+	ret := _mock.Called(ctx, containerID, signal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerKill")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, containerID, signal)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockClient_ContainerKill_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ContainerKill'
+type MockClient_ContainerKill_Call struct {
+	*mock.Call
+}
+
+// ContainerKill is a helper method to define mock.On call
+//   - ctx context.Context
+//   - containerID string
+//   - signal string
+func (_e *MockClient_Expecter) ContainerKill(ctx interface{}, containerID interface{}, signal interface{}) *MockClient_ContainerKill_Call {
+	return &MockClient_ContainerKill_Call{Call: _e.mock.On("ContainerKill", ctx, containerID, signal)}
+}
+
+func (_c *MockClient_ContainerKill_Call) Run(run func(ctx context.Context, containerID string, signal string)) *MockClient_ContainerKill_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_ContainerKill_Call) Return(err error) *MockClient_ContainerKill_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockClient_ContainerKill_Call) RunAndReturn(run func(ctx context.Context, containerID string, signal string) error) *MockClient_ContainerKill_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ContainerLogs provides a mock function for the type MockClient
 func (_mock *MockClient) ContainerLogs(ctx context.Context, container1 string, options container.LogsOptions) (io.ReadCloser, error) {
 	ret := _mock.Called(ctx, container1, options)
@@ -399,6 +672,78 @@ func (_c *MockClient_ContainerStart_Call) RunAndReturn(run func(ctx context.Cont
 	return _c
 }
 
+// ContainerStatPath provides a mock function for the type MockClient
+func (_mock *MockClient) ContainerStatPath(ctx context.Context, containerID string, path string) (container.PathStat, error) {
+	ret := _mock.Called(ctx, containerID, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerStatPath")
+	}
+
+	var r0 container.PathStat
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (container.PathStat, error)); ok {
+		return returnFunc(ctx, containerID, path)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) container.PathStat); ok {
+		r0 = returnFunc(ctx, containerID, path)
+	} else {
+		r0 = ret.Get(0).(container.PathStat)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, containerID, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_ContainerStatPath_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ContainerStatPath'
+type MockClient_ContainerStatPath_Call struct {
+	*mock.Call
+}
+
+// ContainerStatPath is a helper method to define mock.On call
+//   - ctx context.Context
+//   - containerID string
+//   - path string
+func (_e *MockClient_Expecter) ContainerStatPath(ctx interface{}, containerID interface{}, path interface{}) *MockClient_ContainerStatPath_Call {
+	return &MockClient_ContainerStatPath_Call{Call: _e.mock.On("ContainerStatPath", ctx, containerID, path)}
+}
+
+func (_c *MockClient_ContainerStatPath_Call) Run(run func(ctx context.Context, containerID string, path string)) *MockClient_ContainerStatPath_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_ContainerStatPath_Call) Return(pathStat container.PathStat, err error) *MockClient_ContainerStatPath_Call {
+	_c.Call.Return(pathStat, err)
+	return _c
+}
+
+func (_c *MockClient_ContainerStatPath_Call) RunAndReturn(run func(ctx context.Context, containerID string, path string) (container.PathStat, error)) *MockClient_ContainerStatPath_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ContainerStop provides a mock function for the type MockClient
 func (_mock *MockClient) ContainerStop(ctx context.Context, containerID string, options container.StopOptions) error {
 	ret := _mock.Called(ctx, containerID, options)
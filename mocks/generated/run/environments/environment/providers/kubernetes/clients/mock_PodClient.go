@@ -9,8 +9,8 @@ import (
 	"io"
 
 	mock "github.com/stretchr/testify/mock"
-	v10 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/api/core/v1"
+	v10 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // NewMockPodClient creates a new instance of MockPodClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -40,27 +40,102 @@ func (_m *MockPodClient) EXPECT() *MockPodClient_Expecter {
 	return &MockPodClient_Expecter{mock: &_m.Mock}
 }
 
+// Exec provides a mock function for the type MockPodClient
+func (_mock *MockPodClient) Exec(ctx context.Context, name string, opts *v1.PodExecOptions, stdout io.Writer, stderr io.Writer) error {
+	ret := _mock.Called(ctx, name, opts, stdout, stderr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exec")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *v1.PodExecOptions, io.Writer, io.Writer) error); ok {
+		r0 = returnFunc(ctx, name, opts, stdout, stderr)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPodClient_Exec_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Exec'
+type MockPodClient_Exec_Call struct {
+	*mock.Call
+}
+
+// Exec is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - opts *v1.PodExecOptions
+//   - stdout io.Writer
+//   - stderr io.Writer
+func (_e *MockPodClient_Expecter) Exec(ctx interface{}, name interface{}, opts interface{}, stdout interface{}, stderr interface{}) *MockPodClient_Exec_Call {
+	return &MockPodClient_Exec_Call{Call: _e.mock.On("Exec", ctx, name, opts, stdout, stderr)}
+}
+
+func (_c *MockPodClient_Exec_Call) Run(run func(ctx context.Context, name string, opts *v1.PodExecOptions, stdout io.Writer, stderr io.Writer)) *MockPodClient_Exec_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *v1.PodExecOptions
+		if args[2] != nil {
+			arg2 = args[2].(*v1.PodExecOptions)
+		}
+		var arg3 io.Writer
+		if args[3] != nil {
+			arg3 = args[3].(io.Writer)
+		}
+		var arg4 io.Writer
+		if args[4] != nil {
+			arg4 = args[4].(io.Writer)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockPodClient_Exec_Call) Return(err error) *MockPodClient_Exec_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPodClient_Exec_Call) RunAndReturn(run func(ctx context.Context, name string, opts *v1.PodExecOptions, stdout io.Writer, stderr io.Writer) error) *MockPodClient_Exec_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // List provides a mock function for the type MockPodClient
-func (_mock *MockPodClient) List(ctx context.Context, opts v1.ListOptions) (*v10.PodList, error) {
+func (_mock *MockPodClient) List(ctx context.Context, opts v10.ListOptions) (*v1.PodList, error) {
 	ret := _mock.Called(ctx, opts)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
-	var r0 *v10.PodList
+	var r0 *v1.PodList
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, v1.ListOptions) (*v10.PodList, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, v10.ListOptions) (*v1.PodList, error)); ok {
 		return returnFunc(ctx, opts)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, v1.ListOptions) *v10.PodList); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, v10.ListOptions) *v1.PodList); ok {
 		r0 = returnFunc(ctx, opts)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*v10.PodList)
+			r0 = ret.Get(0).(*v1.PodList)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, v1.ListOptions) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, v10.ListOptions) error); ok {
 		r1 = returnFunc(ctx, opts)
 	} else {
 		r1 = ret.Error(1)
@@ -75,20 +150,20 @@ type MockPodClient_List_Call struct {
 
 // List is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opts v1.ListOptions
+//   - opts v10.ListOptions
 func (_e *MockPodClient_Expecter) List(ctx interface{}, opts interface{}) *MockPodClient_List_Call {
 	return &MockPodClient_List_Call{Call: _e.mock.On("List", ctx, opts)}
 }
 
-func (_c *MockPodClient_List_Call) Run(run func(ctx context.Context, opts v1.ListOptions)) *MockPodClient_List_Call {
+func (_c *MockPodClient_List_Call) Run(run func(ctx context.Context, opts v10.ListOptions)) *MockPodClient_List_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 v1.ListOptions
+		var arg1 v10.ListOptions
 		if args[1] != nil {
-			arg1 = args[1].(v1.ListOptions)
+			arg1 = args[1].(v10.ListOptions)
 		}
 		run(
 			arg0,
@@ -98,18 +173,18 @@ func (_c *MockPodClient_List_Call) Run(run func(ctx context.Context, opts v1.Lis
 	return _c
 }
 
-func (_c *MockPodClient_List_Call) Return(podList *v10.PodList, err error) *MockPodClient_List_Call {
+func (_c *MockPodClient_List_Call) Return(podList *v1.PodList, err error) *MockPodClient_List_Call {
 	_c.Call.Return(podList, err)
 	return _c
 }
 
-func (_c *MockPodClient_List_Call) RunAndReturn(run func(ctx context.Context, opts v1.ListOptions) (*v10.PodList, error)) *MockPodClient_List_Call {
+func (_c *MockPodClient_List_Call) RunAndReturn(run func(ctx context.Context, opts v10.ListOptions) (*v1.PodList, error)) *MockPodClient_List_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // StreamLogs provides a mock function for the type MockPodClient
-func (_mock *MockPodClient) StreamLogs(ctx context.Context, name string, opts *v10.PodLogOptions) (io.ReadCloser, error) {
+func (_mock *MockPodClient) StreamLogs(ctx context.Context, name string, opts *v1.PodLogOptions) (io.ReadCloser, error) {
 	ret := _mock.Called(ctx, name, opts)
 
 	if len(ret) == 0 {
@@ -118,17 +193,17 @@ func (_mock *MockPodClient) StreamLogs(ctx context.Context, name string, opts *v
 
 	var r0 io.ReadCloser
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *v10.PodLogOptions) (io.ReadCloser, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *v1.PodLogOptions) (io.ReadCloser, error)); ok {
 		return returnFunc(ctx, name, opts)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *v10.PodLogOptions) io.ReadCloser); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *v1.PodLogOptions) io.ReadCloser); ok {
 		r0 = returnFunc(ctx, name, opts)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(io.ReadCloser)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *v10.PodLogOptions) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *v1.PodLogOptions) error); ok {
 		r1 = returnFunc(ctx, name, opts)
 	} else {
 		r1 = ret.Error(1)
@@ -144,12 +219,12 @@ type MockPodClient_StreamLogs_Call struct {
 // StreamLogs is a helper method to define mock.On call
 //   - ctx context.Context
 //   - name string
-//   - opts *v10.PodLogOptions
+//   - opts *v1.PodLogOptions
 func (_e *MockPodClient_Expecter) StreamLogs(ctx interface{}, name interface{}, opts interface{}) *MockPodClient_StreamLogs_Call {
 	return &MockPodClient_StreamLogs_Call{Call: _e.mock.On("StreamLogs", ctx, name, opts)}
 }
 
-func (_c *MockPodClient_StreamLogs_Call) Run(run func(ctx context.Context, name string, opts *v10.PodLogOptions)) *MockPodClient_StreamLogs_Call {
+func (_c *MockPodClient_StreamLogs_Call) Run(run func(ctx context.Context, name string, opts *v1.PodLogOptions)) *MockPodClient_StreamLogs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -159,9 +234,9 @@ func (_c *MockPodClient_StreamLogs_Call) Run(run func(ctx context.Context, name
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 *v10.PodLogOptions
+		var arg2 *v1.PodLogOptions
 		if args[2] != nil {
-			arg2 = args[2].(*v10.PodLogOptions)
+			arg2 = args[2].(*v1.PodLogOptions)
 		}
 		run(
 			arg0,
@@ -177,7 +252,7 @@ func (_c *MockPodClient_StreamLogs_Call) Return(readCloser io.ReadCloser, err er
 	return _c
 }
 
-func (_c *MockPodClient_StreamLogs_Call) RunAndReturn(run func(ctx context.Context, name string, opts *v10.PodLogOptions) (io.ReadCloser, error)) *MockPodClient_StreamLogs_Call {
+func (_c *MockPodClient_StreamLogs_Call) RunAndReturn(run func(ctx context.Context, name string, opts *v1.PodLogOptions) (io.ReadCloser, error)) *MockPodClient_StreamLogs_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -37,6 +37,68 @@ func (_m *MockMaker) EXPECT() *MockMaker_Expecter {
 	return &MockMaker_Expecter{mock: &_m.Mock}
 }
 
+// MakeFileExpectation provides a mock function for the type MockMaker
+func (_mock *MockMaker) MakeFileExpectation(config *types.FileExpectation) (*expectations.FileExpectation, error) {
+	ret := _mock.Called(config)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MakeFileExpectation")
+	}
+
+	var r0 *expectations.FileExpectation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*types.FileExpectation) (*expectations.FileExpectation, error)); ok {
+		return returnFunc(config)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*types.FileExpectation) *expectations.FileExpectation); ok {
+		r0 = returnFunc(config)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*expectations.FileExpectation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(*types.FileExpectation) error); ok {
+		r1 = returnFunc(config)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMaker_MakeFileExpectation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MakeFileExpectation'
+type MockMaker_MakeFileExpectation_Call struct {
+	*mock.Call
+}
+
+// MakeFileExpectation is a helper method to define mock.On call
+//   - config *types.FileExpectation
+func (_e *MockMaker_Expecter) MakeFileExpectation(config interface{}) *MockMaker_MakeFileExpectation_Call {
+	return &MockMaker_MakeFileExpectation_Call{Call: _e.mock.On("MakeFileExpectation", config)}
+}
+
+func (_c *MockMaker_MakeFileExpectation_Call) Run(run func(config *types.FileExpectation)) *MockMaker_MakeFileExpectation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *types.FileExpectation
+		if args[0] != nil {
+			arg0 = args[0].(*types.FileExpectation)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMaker_MakeFileExpectation_Call) Return(fileExpectation *expectations.FileExpectation, err error) *MockMaker_MakeFileExpectation_Call {
+	_c.Call.Return(fileExpectation, err)
+	return _c
+}
+
+func (_c *MockMaker_MakeFileExpectation_Call) RunAndReturn(run func(config *types.FileExpectation) (*expectations.FileExpectation, error)) *MockMaker_MakeFileExpectation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // MakeMetricsExpectation provides a mock function for the type MockMaker
 func (_mock *MockMaker) MakeMetricsExpectation(config *types.MetricsExpectation) (*expectations.MetricsExpectation, error) {
 	ret := _mock.Called(config)
@@ -161,6 +223,130 @@ func (_c *MockMaker_MakeOutputExpectation_Call) RunAndReturn(run func(config *ty
 	return _c
 }
 
+// MakeProcessExpectation provides a mock function for the type MockMaker
+func (_mock *MockMaker) MakeProcessExpectation(config *types.ProcessExpectation) (*expectations.ProcessExpectation, error) {
+	ret := _mock.Called(config)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MakeProcessExpectation")
+	}
+
+	var r0 *expectations.ProcessExpectation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*types.ProcessExpectation) (*expectations.ProcessExpectation, error)); ok {
+		return returnFunc(config)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*types.ProcessExpectation) *expectations.ProcessExpectation); ok {
+		r0 = returnFunc(config)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*expectations.ProcessExpectation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(*types.ProcessExpectation) error); ok {
+		r1 = returnFunc(config)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMaker_MakeProcessExpectation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MakeProcessExpectation'
+type MockMaker_MakeProcessExpectation_Call struct {
+	*mock.Call
+}
+
+// MakeProcessExpectation is a helper method to define mock.On call
+//   - config *types.ProcessExpectation
+func (_e *MockMaker_Expecter) MakeProcessExpectation(config interface{}) *MockMaker_MakeProcessExpectation_Call {
+	return &MockMaker_MakeProcessExpectation_Call{Call: _e.mock.On("MakeProcessExpectation", config)}
+}
+
+func (_c *MockMaker_MakeProcessExpectation_Call) Run(run func(config *types.ProcessExpectation)) *MockMaker_MakeProcessExpectation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *types.ProcessExpectation
+		if args[0] != nil {
+			arg0 = args[0].(*types.ProcessExpectation)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMaker_MakeProcessExpectation_Call) Return(processExpectation *expectations.ProcessExpectation, err error) *MockMaker_MakeProcessExpectation_Call {
+	_c.Call.Return(processExpectation, err)
+	return _c
+}
+
+func (_c *MockMaker_MakeProcessExpectation_Call) RunAndReturn(run func(config *types.ProcessExpectation) (*expectations.ProcessExpectation, error)) *MockMaker_MakeProcessExpectation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MakeReceivedExpectation provides a mock function for the type MockMaker
+func (_mock *MockMaker) MakeReceivedExpectation(config *types.ReceivedExpectation) (*expectations.ReceivedExpectation, error) {
+	ret := _mock.Called(config)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MakeReceivedExpectation")
+	}
+
+	var r0 *expectations.ReceivedExpectation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*types.ReceivedExpectation) (*expectations.ReceivedExpectation, error)); ok {
+		return returnFunc(config)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*types.ReceivedExpectation) *expectations.ReceivedExpectation); ok {
+		r0 = returnFunc(config)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*expectations.ReceivedExpectation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(*types.ReceivedExpectation) error); ok {
+		r1 = returnFunc(config)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMaker_MakeReceivedExpectation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MakeReceivedExpectation'
+type MockMaker_MakeReceivedExpectation_Call struct {
+	*mock.Call
+}
+
+// MakeReceivedExpectation is a helper method to define mock.On call
+//   - config *types.ReceivedExpectation
+func (_e *MockMaker_Expecter) MakeReceivedExpectation(config interface{}) *MockMaker_MakeReceivedExpectation_Call {
+	return &MockMaker_MakeReceivedExpectation_Call{Call: _e.mock.On("MakeReceivedExpectation", config)}
+}
+
+func (_c *MockMaker_MakeReceivedExpectation_Call) Run(run func(config *types.ReceivedExpectation)) *MockMaker_MakeReceivedExpectation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *types.ReceivedExpectation
+		if args[0] != nil {
+			arg0 = args[0].(*types.ReceivedExpectation)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMaker_MakeReceivedExpectation_Call) Return(receivedExpectation *expectations.ReceivedExpectation, err error) *MockMaker_MakeReceivedExpectation_Call {
+	_c.Call.Return(receivedExpectation, err)
+	return _c
+}
+
+func (_c *MockMaker_MakeReceivedExpectation_Call) RunAndReturn(run func(config *types.ReceivedExpectation) (*expectations.ReceivedExpectation, error)) *MockMaker_MakeReceivedExpectation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // MakeResponseExpectation provides a mock function for the type MockMaker
 func (_mock *MockMaker) MakeResponseExpectation(config *types.ResponseExpectation) (*expectations.ResponseExpectation, error) {
 	ret := _mock.Called(config)
@@ -222,3 +408,65 @@ func (_c *MockMaker_MakeResponseExpectation_Call) RunAndReturn(run func(config *
 	_c.Call.Return(run)
 	return _c
 }
+
+// MakeSnapshotExpectation provides a mock function for the type MockMaker
+func (_mock *MockMaker) MakeSnapshotExpectation(config *types.SnapshotExpectation) (*expectations.SnapshotExpectation, error) {
+	ret := _mock.Called(config)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MakeSnapshotExpectation")
+	}
+
+	var r0 *expectations.SnapshotExpectation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*types.SnapshotExpectation) (*expectations.SnapshotExpectation, error)); ok {
+		return returnFunc(config)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*types.SnapshotExpectation) *expectations.SnapshotExpectation); ok {
+		r0 = returnFunc(config)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*expectations.SnapshotExpectation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(*types.SnapshotExpectation) error); ok {
+		r1 = returnFunc(config)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMaker_MakeSnapshotExpectation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MakeSnapshotExpectation'
+type MockMaker_MakeSnapshotExpectation_Call struct {
+	*mock.Call
+}
+
+// MakeSnapshotExpectation is a helper method to define mock.On call
+//   - config *types.SnapshotExpectation
+func (_e *MockMaker_Expecter) MakeSnapshotExpectation(config interface{}) *MockMaker_MakeSnapshotExpectation_Call {
+	return &MockMaker_MakeSnapshotExpectation_Call{Call: _e.mock.On("MakeSnapshotExpectation", config)}
+}
+
+func (_c *MockMaker_MakeSnapshotExpectation_Call) Run(run func(config *types.SnapshotExpectation)) *MockMaker_MakeSnapshotExpectation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *types.SnapshotExpectation
+		if args[0] != nil {
+			arg0 = args[0].(*types.SnapshotExpectation)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMaker_MakeSnapshotExpectation_Call) Return(snapshotExpectation *expectations.SnapshotExpectation, err error) *MockMaker_MakeSnapshotExpectation_Call {
+	_c.Call.Return(snapshotExpectation, err)
+	return _c
+}
+
+func (_c *MockMaker_MakeSnapshotExpectation_Call) RunAndReturn(run func(config *types.SnapshotExpectation) (*expectations.SnapshotExpectation, error)) *MockMaker_MakeSnapshotExpectation_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -5,7 +5,11 @@
 package runtime
 
 import (
+	"time"
+
 	mock "github.com/stretchr/testify/mock"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/parameters"
 )
 
 // NewMockData creates a new instance of MockData. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -35,6 +39,50 @@ func (_m *MockData) EXPECT() *MockData_Expecter {
 	return &MockData_Expecter{mock: &_m.Mock}
 }
 
+// Close provides a mock function for the type MockData
+func (_mock *MockData) Close() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockData_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type MockData_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+func (_e *MockData_Expecter) Close() *MockData_Close_Call {
+	return &MockData_Close_Call{Call: _e.mock.On("Close")}
+}
+
+func (_c *MockData_Close_Call) Run(run func()) *MockData_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockData_Close_Call) Return(err error) *MockData_Close_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockData_Close_Call) RunAndReturn(run func() error) *MockData_Close_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Load provides a mock function for the type MockData
 func (_mock *MockData) Load(key string) (interface{}, bool) {
 	ret := _mock.Called(key)
@@ -97,6 +145,145 @@ func (_c *MockData_Load_Call) RunAndReturn(run func(key string) (interface{}, bo
 	return _c
 }
 
+// MarkActionStart provides a mock function for the type MockData
+func (_mock *MockData) MarkActionStart(start time.Time) {
+	_mock.Called(start)
+	return
+}
+
+// MockData_MarkActionStart_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkActionStart'
+type MockData_MarkActionStart_Call struct {
+	*mock.Call
+}
+
+// MarkActionStart is a helper method to define mock.On call
+//   - start time.Time
+func (_e *MockData_Expecter) MarkActionStart(start interface{}) *MockData_MarkActionStart_Call {
+	return &MockData_MarkActionStart_Call{Call: _e.mock.On("MarkActionStart", start)}
+}
+
+func (_c *MockData_MarkActionStart_Call) Run(run func(start time.Time)) *MockData_MarkActionStart_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 time.Time
+		if args[0] != nil {
+			arg0 = args[0].(time.Time)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockData_MarkActionStart_Call) Return() *MockData_MarkActionStart_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockData_MarkActionStart_Call) RunAndReturn(run func(start time.Time)) *MockData_MarkActionStart_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Parameters provides a mock function for the type MockData
+func (_mock *MockData) Parameters() parameters.Parameters {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Parameters")
+	}
+
+	var r0 parameters.Parameters
+	if returnFunc, ok := ret.Get(0).(func() parameters.Parameters); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(parameters.Parameters)
+		}
+	}
+	return r0
+}
+
+// MockData_Parameters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Parameters'
+type MockData_Parameters_Call struct {
+	*mock.Call
+}
+
+// Parameters is a helper method to define mock.On call
+func (_e *MockData_Expecter) Parameters() *MockData_Parameters_Call {
+	return &MockData_Parameters_Call{Call: _e.mock.On("Parameters")}
+}
+
+func (_c *MockData_Parameters_Call) Run(run func()) *MockData_Parameters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockData_Parameters_Call) Return(parameters1 parameters.Parameters) *MockData_Parameters_Call {
+	_c.Call.Return(parameters1)
+	return _c
+}
+
+func (_c *MockData_Parameters_Call) RunAndReturn(run func() parameters.Parameters) *MockData_Parameters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PreviousActionStart provides a mock function for the type MockData
+func (_mock *MockData) PreviousActionStart() (time.Time, bool) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreviousActionStart")
+	}
+
+	var r0 time.Time
+	var r1 bool
+	if returnFunc, ok := ret.Get(0).(func() (time.Time, bool)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+	if returnFunc, ok := ret.Get(1).(func() bool); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+	return r0, r1
+}
+
+// MockData_PreviousActionStart_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PreviousActionStart'
+type MockData_PreviousActionStart_Call struct {
+	*mock.Call
+}
+
+// PreviousActionStart is a helper method to define mock.On call
+func (_e *MockData_Expecter) PreviousActionStart() *MockData_PreviousActionStart_Call {
+	return &MockData_PreviousActionStart_Call{Call: _e.mock.On("PreviousActionStart")}
+}
+
+func (_c *MockData_PreviousActionStart_Call) Run(run func()) *MockData_PreviousActionStart_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockData_PreviousActionStart_Call) Return(time1 time.Time, b bool) *MockData_PreviousActionStart_Call {
+	_c.Call.Return(time1, b)
+	return _c
+}
+
+func (_c *MockData_PreviousActionStart_Call) RunAndReturn(run func() (time.Time, bool)) *MockData_PreviousActionStart_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Store provides a mock function for the type MockData
 func (_mock *MockData) Store(key string, value interface{}) error {
 	ret := _mock.Called(key, value)
@@ -153,3 +340,102 @@ func (_c *MockData_Store_Call) RunAndReturn(run func(key string, value interface
 	_c.Call.Return(run)
 	return _c
 }
+
+// WithActionSequence provides a mock function for the type MockData
+func (_mock *MockData) WithActionSequence() runtime.Data {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithActionSequence")
+	}
+
+	var r0 runtime.Data
+	if returnFunc, ok := ret.Get(0).(func() runtime.Data); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(runtime.Data)
+		}
+	}
+	return r0
+}
+
+// MockData_WithActionSequence_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithActionSequence'
+type MockData_WithActionSequence_Call struct {
+	*mock.Call
+}
+
+// WithActionSequence is a helper method to define mock.On call
+func (_e *MockData_Expecter) WithActionSequence() *MockData_WithActionSequence_Call {
+	return &MockData_WithActionSequence_Call{Call: _e.mock.On("WithActionSequence")}
+}
+
+func (_c *MockData_WithActionSequence_Call) Run(run func()) *MockData_WithActionSequence_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockData_WithActionSequence_Call) Return(data runtime.Data) *MockData_WithActionSequence_Call {
+	_c.Call.Return(data)
+	return _c
+}
+
+func (_c *MockData_WithActionSequence_Call) RunAndReturn(run func() runtime.Data) *MockData_WithActionSequence_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithParameters provides a mock function for the type MockData
+func (_mock *MockData) WithParameters(params parameters.Parameters) runtime.Data {
+	ret := _mock.Called(params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithParameters")
+	}
+
+	var r0 runtime.Data
+	if returnFunc, ok := ret.Get(0).(func(parameters.Parameters) runtime.Data); ok {
+		r0 = returnFunc(params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(runtime.Data)
+		}
+	}
+	return r0
+}
+
+// MockData_WithParameters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithParameters'
+type MockData_WithParameters_Call struct {
+	*mock.Call
+}
+
+// WithParameters is a helper method to define mock.On call
+//   - params parameters.Parameters
+func (_e *MockData_Expecter) WithParameters(params interface{}) *MockData_WithParameters_Call {
+	return &MockData_WithParameters_Call{Call: _e.mock.On("WithParameters", params)}
+}
+
+func (_c *MockData_WithParameters_Call) Run(run func(params parameters.Parameters)) *MockData_WithParameters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 parameters.Parameters
+		if args[0] != nil {
+			arg0 = args[0].(parameters.Parameters)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockData_WithParameters_Call) Return(data runtime.Data) *MockData_WithParameters_Call {
+	_c.Call.Return(data)
+	return _c
+}
+
+func (_c *MockData_WithParameters_Call) RunAndReturn(run func(params parameters.Parameters) runtime.Data) *MockData_WithParameters_Call {
+	_c.Call.Return(run)
+	return _c
+}
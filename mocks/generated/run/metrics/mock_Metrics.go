@@ -98,6 +98,80 @@ func (_c *MockMetrics_Find_Call) RunAndReturn(run func(name string) (metrics.Met
 	return _c
 }
 
+// FindWindowed provides a mock function for the type MockMetrics
+func (_mock *MockMetrics) FindWindowed(name string, window metrics.Window, aggregation metrics.Aggregation) (metrics.Metric, error) {
+	ret := _mock.Called(name, window, aggregation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindWindowed")
+	}
+
+	var r0 metrics.Metric
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, metrics.Window, metrics.Aggregation) (metrics.Metric, error)); ok {
+		return returnFunc(name, window, aggregation)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, metrics.Window, metrics.Aggregation) metrics.Metric); ok {
+		r0 = returnFunc(name, window, aggregation)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(metrics.Metric)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, metrics.Window, metrics.Aggregation) error); ok {
+		r1 = returnFunc(name, window, aggregation)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMetrics_FindWindowed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindWindowed'
+type MockMetrics_FindWindowed_Call struct {
+	*mock.Call
+}
+
+// FindWindowed is a helper method to define mock.On call
+//   - name string
+//   - window metrics.Window
+//   - aggregation metrics.Aggregation
+func (_e *MockMetrics_Expecter) FindWindowed(name interface{}, window interface{}, aggregation interface{}) *MockMetrics_FindWindowed_Call {
+	return &MockMetrics_FindWindowed_Call{Call: _e.mock.On("FindWindowed", name, window, aggregation)}
+}
+
+func (_c *MockMetrics_FindWindowed_Call) Run(run func(name string, window metrics.Window, aggregation metrics.Aggregation)) *MockMetrics_FindWindowed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 metrics.Window
+		if args[1] != nil {
+			arg1 = args[1].(metrics.Window)
+		}
+		var arg2 metrics.Aggregation
+		if args[2] != nil {
+			arg2 = args[2].(metrics.Aggregation)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMetrics_FindWindowed_Call) Return(metric metrics.Metric, err error) *MockMetrics_FindWindowed_Call {
+	_c.Call.Return(metric, err)
+	return _c
+}
+
+func (_c *MockMetrics_FindWindowed_Call) RunAndReturn(run func(name string, window metrics.Window, aggregation metrics.Aggregation) (metrics.Metric, error)) *MockMetrics_FindWindowed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // String provides a mock function for the type MockMetrics
 func (_mock *MockMetrics) String() string {
 	ret := _mock.Called()
@@ -141,3 +215,49 @@ func (_c *MockMetrics_String_Call) RunAndReturn(run func() string) *MockMetrics_
 	_c.Call.Return(run)
 	return _c
 }
+
+// Values provides a mock function for the type MockMetrics
+func (_mock *MockMetrics) Values() map[string]interface{} {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Values")
+	}
+
+	var r0 map[string]interface{}
+	if returnFunc, ok := ret.Get(0).(func() map[string]interface{}); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+	return r0
+}
+
+// MockMetrics_Values_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Values'
+type MockMetrics_Values_Call struct {
+	*mock.Call
+}
+
+// Values is a helper method to define mock.On call
+func (_e *MockMetrics_Expecter) Values() *MockMetrics_Values_Call {
+	return &MockMetrics_Values_Call{Call: _e.mock.On("Values")}
+}
+
+func (_c *MockMetrics_Values_Call) Run(run func()) *MockMetrics_Values_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMetrics_Values_Call) Return(stringToIfaceVal map[string]interface{}) *MockMetrics_Values_Call {
+	_c.Call.Return(stringToIfaceVal)
+	return _c
+}
+
+func (_c *MockMetrics_Values_Call) RunAndReturn(run func() map[string]interface{}) *MockMetrics_Values_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/wstool/wst/run/sandboxes/sandbox"
 	"github.com/wstool/wst/run/servers/actions"
 	"github.com/wstool/wst/run/servers/configs"
+	"github.com/wstool/wst/run/servers/mockserver"
 	"github.com/wstool/wst/run/servers/templates"
 )
 
@@ -255,6 +256,52 @@ func (_c *MockServer_Group_Call) RunAndReturn(run func() string) *MockServer_Gro
 	return _c
 }
 
+// MockServer provides a mock function for the type MockServer
+func (_mock *MockServer) MockServer() *mockserver.Config {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for MockServer")
+	}
+
+	var r0 *mockserver.Config
+	if returnFunc, ok := ret.Get(0).(func() *mockserver.Config); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mockserver.Config)
+		}
+	}
+	return r0
+}
+
+// MockServer_MockServer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MockServer'
+type MockServer_MockServer_Call struct {
+	*mock.Call
+}
+
+// MockServer is a helper method to define mock.On call
+func (_e *MockServer_Expecter) MockServer() *MockServer_MockServer_Call {
+	return &MockServer_MockServer_Call{Call: _e.mock.On("MockServer")}
+}
+
+func (_c *MockServer_MockServer_Call) Run(run func()) *MockServer_MockServer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockServer_MockServer_Call) Return(config *mockserver.Config) *MockServer_MockServer_Call {
+	_c.Call.Return(config)
+	return _c
+}
+
+func (_c *MockServer_MockServer_Call) RunAndReturn(run func() *mockserver.Config) *MockServer_MockServer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Parameters provides a mock function for the type MockServer
 func (_mock *MockServer) Parameters() parameters.Parameters {
 	ret := _mock.Called()
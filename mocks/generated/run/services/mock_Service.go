@@ -7,16 +7,21 @@ package services
 import (
 	"context"
 	"io"
+	"os"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 	"github.com/wstool/wst/run/environments/environment"
 	"github.com/wstool/wst/run/environments/environment/output"
+	"github.com/wstool/wst/run/environments/environment/providers"
 	"github.com/wstool/wst/run/environments/task"
 	"github.com/wstool/wst/run/parameters"
 	"github.com/wstool/wst/run/resources/certificates"
+	"github.com/wstool/wst/run/resources/scripts"
 	"github.com/wstool/wst/run/sandboxes/dir"
 	"github.com/wstool/wst/run/sandboxes/sandbox"
 	"github.com/wstool/wst/run/servers"
+	"github.com/wstool/wst/run/servers/mockserver"
 	"github.com/wstool/wst/run/services/template"
 )
 
@@ -400,6 +405,146 @@ func (_c *MockService_Executable_Call) RunAndReturn(run func() (string, error))
 	return _c
 }
 
+// File provides a mock function for the type MockService
+func (_mock *MockService) File(ctx context.Context, path string, offset int64) (*environment.File, error) {
+	ret := _mock.Called(ctx, path, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for File")
+	}
+
+	var r0 *environment.File
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) (*environment.File, error)); ok {
+		return returnFunc(ctx, path, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) *environment.File); ok {
+		r0 = returnFunc(ctx, path, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*environment.File)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = returnFunc(ctx, path, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_File_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'File'
+type MockService_File_Call struct {
+	*mock.Call
+}
+
+// File is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+//   - offset int64
+func (_e *MockService_Expecter) File(ctx interface{}, path interface{}, offset interface{}) *MockService_File_Call {
+	return &MockService_File_Call{Call: _e.mock.On("File", ctx, path, offset)}
+}
+
+func (_c *MockService_File_Call) Run(run func(ctx context.Context, path string, offset int64)) *MockService_File_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_File_Call) Return(file *environment.File, err error) *MockService_File_Call {
+	_c.Call.Return(file, err)
+	return _c
+}
+
+func (_c *MockService_File_Call) RunAndReturn(run func(ctx context.Context, path string, offset int64) (*environment.File, error)) *MockService_File_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FileExists provides a mock function for the type MockService
+func (_mock *MockService) FileExists(ctx context.Context, path string) (bool, error) {
+	ret := _mock.Called(ctx, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FileExists")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return returnFunc(ctx, path)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = returnFunc(ctx, path)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_FileExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FileExists'
+type MockService_FileExists_Call struct {
+	*mock.Call
+}
+
+// FileExists is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+func (_e *MockService_Expecter) FileExists(ctx interface{}, path interface{}) *MockService_FileExists_Call {
+	return &MockService_FileExists_Call{Call: _e.mock.On("FileExists", ctx, path)}
+}
+
+func (_c *MockService_FileExists_Call) Run(run func(ctx context.Context, path string)) *MockService_FileExists_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_FileExists_Call) Return(b bool, err error) *MockService_FileExists_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockService_FileExists_Call) RunAndReturn(run func(ctx context.Context, path string) (bool, error)) *MockService_FileExists_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindCertificate provides a mock function for the type MockService
 func (_mock *MockService) FindCertificate(name string) (*certificates.RenderedCertificate, error) {
 	ret := _mock.Called(name)
@@ -462,6 +607,68 @@ func (_c *MockService_FindCertificate_Call) RunAndReturn(run func(name string) (
 	return _c
 }
 
+// FindScript provides a mock function for the type MockService
+func (_mock *MockService) FindScript(name string) (scripts.Script, error) {
+	ret := _mock.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindScript")
+	}
+
+	var r0 scripts.Script
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (scripts.Script, error)); ok {
+		return returnFunc(name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) scripts.Script); ok {
+		r0 = returnFunc(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(scripts.Script)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_FindScript_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindScript'
+type MockService_FindScript_Call struct {
+	*mock.Call
+}
+
+// FindScript is a helper method to define mock.On call
+//   - name string
+func (_e *MockService_Expecter) FindScript(name interface{}) *MockService_FindScript_Call {
+	return &MockService_FindScript_Call{Call: _e.mock.On("FindScript", name)}
+}
+
+func (_c *MockService_FindScript_Call) Run(run func(name string)) *MockService_FindScript_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_FindScript_Call) Return(script scripts.Script, err error) *MockService_FindScript_Call {
+	_c.Call.Return(script, err)
+	return _c
+}
+
+func (_c *MockService_FindScript_Call) RunAndReturn(run func(name string) (scripts.Script, error)) *MockService_FindScript_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FullName provides a mock function for the type MockService
 func (_mock *MockService) FullName() string {
 	ret := _mock.Called()
@@ -594,6 +801,66 @@ func (_c *MockService_IsPublic_Call) RunAndReturn(run func() bool) *MockService_
 	return _c
 }
 
+// IsRunning provides a mock function for the type MockService
+func (_mock *MockService) IsRunning(ctx context.Context) (bool, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsRunning")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (bool, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) bool); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_IsRunning_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsRunning'
+type MockService_IsRunning_Call struct {
+	*mock.Call
+}
+
+// IsRunning is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) IsRunning(ctx interface{}) *MockService_IsRunning_Call {
+	return &MockService_IsRunning_Call{Call: _e.mock.On("IsRunning", ctx)}
+}
+
+func (_c *MockService_IsRunning_Call) Run(run func(ctx context.Context)) *MockService_IsRunning_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_IsRunning_Call) Return(b bool, err error) *MockService_IsRunning_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockService_IsRunning_Call) RunAndReturn(run func(ctx context.Context) (bool, error)) *MockService_IsRunning_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LocalAddress provides a mock function for the type MockService
 func (_mock *MockService) LocalAddress() string {
 	ret := _mock.Called()
@@ -682,6 +949,52 @@ func (_c *MockService_LocalPort_Call) RunAndReturn(run func() int32) *MockServic
 	return _c
 }
 
+// MockServer provides a mock function for the type MockService
+func (_mock *MockService) MockServer() *mockserver.Server {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for MockServer")
+	}
+
+	var r0 *mockserver.Server
+	if returnFunc, ok := ret.Get(0).(func() *mockserver.Server); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mockserver.Server)
+		}
+	}
+	return r0
+}
+
+// MockService_MockServer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MockServer'
+type MockService_MockServer_Call struct {
+	*mock.Call
+}
+
+// MockServer is a helper method to define mock.On call
+func (_e *MockService_Expecter) MockServer() *MockService_MockServer_Call {
+	return &MockService_MockServer_Call{Call: _e.mock.On("MockServer")}
+}
+
+func (_c *MockService_MockServer_Call) Run(run func()) *MockService_MockServer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockService_MockServer_Call) Return(server *mockserver.Server) *MockService_MockServer_Call {
+	_c.Call.Return(server)
+	return _c
+}
+
+func (_c *MockService_MockServer_Call) RunAndReturn(run func() *mockserver.Server) *MockService_MockServer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Name provides a mock function for the type MockService
 func (_mock *MockService) Name() string {
 	ret := _mock.Called()
@@ -794,6 +1107,80 @@ func (_c *MockService_OutputReader_Call) RunAndReturn(run func(ctx context.Conte
 	return _c
 }
 
+// OutputReaderSince provides a mock function for the type MockService
+func (_mock *MockService) OutputReaderSince(ctx context.Context, outputType output.Type, since time.Time) (io.Reader, error) {
+	ret := _mock.Called(ctx, outputType, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OutputReaderSince")
+	}
+
+	var r0 io.Reader
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, output.Type, time.Time) (io.Reader, error)); ok {
+		return returnFunc(ctx, outputType, since)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, output.Type, time.Time) io.Reader); ok {
+		r0 = returnFunc(ctx, outputType, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.Reader)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, output.Type, time.Time) error); ok {
+		r1 = returnFunc(ctx, outputType, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_OutputReaderSince_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OutputReaderSince'
+type MockService_OutputReaderSince_Call struct {
+	*mock.Call
+}
+
+// OutputReaderSince is a helper method to define mock.On call
+//   - ctx context.Context
+//   - outputType output.Type
+//   - since time.Time
+func (_e *MockService_Expecter) OutputReaderSince(ctx interface{}, outputType interface{}, since interface{}) *MockService_OutputReaderSince_Call {
+	return &MockService_OutputReaderSince_Call{Call: _e.mock.On("OutputReaderSince", ctx, outputType, since)}
+}
+
+func (_c *MockService_OutputReaderSince_Call) Run(run func(ctx context.Context, outputType output.Type, since time.Time)) *MockService_OutputReaderSince_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 output.Type
+		if args[1] != nil {
+			arg1 = args[1].(output.Type)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_OutputReaderSince_Call) Return(reader io.Reader, err error) *MockService_OutputReaderSince_Call {
+	_c.Call.Return(reader, err)
+	return _c
+}
+
+func (_c *MockService_OutputReaderSince_Call) RunAndReturn(run func(ctx context.Context, outputType output.Type, since time.Time) (io.Reader, error)) *MockService_OutputReaderSince_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Pid provides a mock function for the type MockService
 func (_mock *MockService) Pid() (int, error) {
 	ret := _mock.Called()
@@ -874,19 +1261,85 @@ func (_e *MockService_Expecter) Port() *MockService_Port_Call {
 	return &MockService_Port_Call{Call: _e.mock.On("Port")}
 }
 
-func (_c *MockService_Port_Call) Run(run func()) *MockService_Port_Call {
+func (_c *MockService_Port_Call) Run(run func()) *MockService_Port_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockService_Port_Call) Return(n int32) *MockService_Port_Call {
+	_c.Call.Return(n)
+	return _c
+}
+
+func (_c *MockService_Port_Call) RunAndReturn(run func() int32) *MockService_Port_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PortReady provides a mock function for the type MockService
+func (_mock *MockService) PortReady(ctx context.Context, port int32) (bool, error) {
+	ret := _mock.Called(ctx, port)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PortReady")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int32) (bool, error)); ok {
+		return returnFunc(ctx, port)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int32) bool); ok {
+		r0 = returnFunc(ctx, port)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = returnFunc(ctx, port)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_PortReady_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PortReady'
+type MockService_PortReady_Call struct {
+	*mock.Call
+}
+
+// PortReady is a helper method to define mock.On call
+//   - ctx context.Context
+//   - port int32
+func (_e *MockService_Expecter) PortReady(ctx interface{}, port interface{}) *MockService_PortReady_Call {
+	return &MockService_PortReady_Call{Call: _e.mock.On("PortReady", ctx, port)}
+}
+
+func (_c *MockService_PortReady_Call) Run(run func(ctx context.Context, port int32)) *MockService_PortReady_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int32
+		if args[1] != nil {
+			arg1 = args[1].(int32)
+		}
+		run(
+			arg0,
+			arg1,
+		)
 	})
 	return _c
 }
 
-func (_c *MockService_Port_Call) Return(n int32) *MockService_Port_Call {
-	_c.Call.Return(n)
+func (_c *MockService_PortReady_Call) Return(b bool, err error) *MockService_PortReady_Call {
+	_c.Call.Return(b, err)
 	return _c
 }
 
-func (_c *MockService_Port_Call) RunAndReturn(run func() int32) *MockService_Port_Call {
+func (_c *MockService_PortReady_Call) RunAndReturn(run func(ctx context.Context, port int32) (bool, error)) *MockService_PortReady_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -995,6 +1448,68 @@ func (_c *MockService_PrivateUrl_Call) RunAndReturn(run func(scheme string) (str
 	return _c
 }
 
+// Processes provides a mock function for the type MockService
+func (_mock *MockService) Processes(ctx context.Context) ([]environment.Process, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Processes")
+	}
+
+	var r0 []environment.Process
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]environment.Process, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []environment.Process); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]environment.Process)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_Processes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Processes'
+type MockService_Processes_Call struct {
+	*mock.Call
+}
+
+// Processes is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) Processes(ctx interface{}) *MockService_Processes_Call {
+	return &MockService_Processes_Call{Call: _e.mock.On("Processes", ctx)}
+}
+
+func (_c *MockService_Processes_Call) Run(run func(ctx context.Context)) *MockService_Processes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Processes_Call) Return(processs []environment.Process, err error) *MockService_Processes_Call {
+	_c.Call.Return(processs, err)
+	return _c
+}
+
+func (_c *MockService_Processes_Call) RunAndReturn(run func(ctx context.Context) ([]environment.Process, error)) *MockService_Processes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PublicUrl provides a mock function for the type MockService
 func (_mock *MockService) PublicUrl(scheme string, path string) (string, error) {
 	ret := _mock.Called(scheme, path)
@@ -1328,6 +1843,50 @@ func (_c *MockService_Sandbox_Call) RunAndReturn(run func() sandbox.Sandbox) *Mo
 	return _c
 }
 
+// SandboxType provides a mock function for the type MockService
+func (_mock *MockService) SandboxType() providers.Type {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SandboxType")
+	}
+
+	var r0 providers.Type
+	if returnFunc, ok := ret.Get(0).(func() providers.Type); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(providers.Type)
+	}
+	return r0
+}
+
+// MockService_SandboxType_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SandboxType'
+type MockService_SandboxType_Call struct {
+	*mock.Call
+}
+
+// SandboxType is a helper method to define mock.On call
+func (_e *MockService_Expecter) SandboxType() *MockService_SandboxType_Call {
+	return &MockService_SandboxType_Call{Call: _e.mock.On("SandboxType")}
+}
+
+func (_c *MockService_SandboxType_Call) Run(run func()) *MockService_SandboxType_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockService_SandboxType_Call) Return(typeParam providers.Type) *MockService_SandboxType_Call {
+	_c.Call.Return(typeParam)
+	return _c
+}
+
+func (_c *MockService_SandboxType_Call) RunAndReturn(run func() providers.Type) *MockService_SandboxType_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ScriptDir provides a mock function for the type MockService
 func (_mock *MockService) ScriptDir() (string, error) {
 	ret := _mock.Called()
@@ -1473,6 +2032,57 @@ func (_c *MockService_ServerParameters_Call) RunAndReturn(run func() parameters.
 	return _c
 }
 
+// SetProxyAddress provides a mock function for the type MockService
+func (_mock *MockService) SetProxyAddress(address string) error {
+	ret := _mock.Called(address)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetProxyAddress")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
+		r0 = returnFunc(address)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_SetProxyAddress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetProxyAddress'
+type MockService_SetProxyAddress_Call struct {
+	*mock.Call
+}
+
+// SetProxyAddress is a helper method to define mock.On call
+//   - address string
+func (_e *MockService_Expecter) SetProxyAddress(address interface{}) *MockService_SetProxyAddress_Call {
+	return &MockService_SetProxyAddress_Call{Call: _e.mock.On("SetProxyAddress", address)}
+}
+
+func (_c *MockService_SetProxyAddress_Call) Run(run func(address string)) *MockService_SetProxyAddress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_SetProxyAddress_Call) Return(err error) *MockService_SetProxyAddress_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_SetProxyAddress_Call) RunAndReturn(run func(address string) error) *MockService_SetProxyAddress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetTemplate provides a mock function for the type MockService
 func (_mock *MockService) SetTemplate(template1 template.Template) {
 	_mock.Called(template1)
@@ -1513,6 +2123,126 @@ func (_c *MockService_SetTemplate_Call) RunAndReturn(run func(template1 template
 	return _c
 }
 
+// Signal provides a mock function for the type MockService
+func (_mock *MockService) Signal(ctx context.Context, signal os.Signal) error {
+	ret := _mock.Called(ctx, signal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Signal")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, os.Signal) error); ok {
+		r0 = returnFunc(ctx, signal)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_Signal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Signal'
+type MockService_Signal_Call struct {
+	*mock.Call
+}
+
+// Signal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - signal os.Signal
+func (_e *MockService_Expecter) Signal(ctx interface{}, signal interface{}) *MockService_Signal_Call {
+	return &MockService_Signal_Call{Call: _e.mock.On("Signal", ctx, signal)}
+}
+
+func (_c *MockService_Signal_Call) Run(run func(ctx context.Context, signal os.Signal)) *MockService_Signal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 os.Signal
+		if args[1] != nil {
+			arg1 = args[1].(os.Signal)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Signal_Call) Return(err error) *MockService_Signal_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_Signal_Call) RunAndReturn(run func(ctx context.Context, signal os.Signal) error) *MockService_Signal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SignalProcesses provides a mock function for the type MockService
+func (_mock *MockService) SignalProcesses(ctx context.Context, processes []environment.Process, signal os.Signal) error {
+	ret := _mock.Called(ctx, processes, signal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SignalProcesses")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []environment.Process, os.Signal) error); ok {
+		r0 = returnFunc(ctx, processes, signal)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_SignalProcesses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SignalProcesses'
+type MockService_SignalProcesses_Call struct {
+	*mock.Call
+}
+
+// SignalProcesses is a helper method to define mock.On call
+//   - ctx context.Context
+//   - processes []environment.Process
+//   - signal os.Signal
+func (_e *MockService_Expecter) SignalProcesses(ctx interface{}, processes interface{}, signal interface{}) *MockService_SignalProcesses_Call {
+	return &MockService_SignalProcesses_Call{Call: _e.mock.On("SignalProcesses", ctx, processes, signal)}
+}
+
+func (_c *MockService_SignalProcesses_Call) Run(run func(ctx context.Context, processes []environment.Process, signal os.Signal)) *MockService_SignalProcesses_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []environment.Process
+		if args[1] != nil {
+			arg1 = args[1].([]environment.Process)
+		}
+		var arg2 os.Signal
+		if args[2] != nil {
+			arg2 = args[2].(os.Signal)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_SignalProcesses_Call) Return(err error) *MockService_SignalProcesses_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_SignalProcesses_Call) RunAndReturn(run func(ctx context.Context, processes []environment.Process, signal os.Signal) error) *MockService_SignalProcesses_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Start provides a mock function for the type MockService
 func (_mock *MockService) Start(ctx context.Context) error {
 	ret := _mock.Called(ctx)
@@ -1733,6 +2463,72 @@ func (_c *MockService_UdsPath_Call) RunAndReturn(run func(strings ...string) (st
 	return _c
 }
 
+// UdsReady provides a mock function for the type MockService
+func (_mock *MockService) UdsReady(ctx context.Context, path string) (bool, error) {
+	ret := _mock.Called(ctx, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UdsReady")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return returnFunc(ctx, path)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = returnFunc(ctx, path)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_UdsReady_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UdsReady'
+type MockService_UdsReady_Call struct {
+	*mock.Call
+}
+
+// UdsReady is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+func (_e *MockService_Expecter) UdsReady(ctx interface{}, path interface{}) *MockService_UdsReady_Call {
+	return &MockService_UdsReady_Call{Call: _e.mock.On("UdsReady", ctx, path)}
+}
+
+func (_c *MockService_UdsReady_Call) Run(run func(ctx context.Context, path string)) *MockService_UdsReady_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_UdsReady_Call) Return(b bool, err error) *MockService_UdsReady_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockService_UdsReady_Call) RunAndReturn(run func(ctx context.Context, path string) (bool, error)) *MockService_UdsReady_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // User provides a mock function for the type MockService
 func (_mock *MockService) User() string {
 	ret := _mock.Called()
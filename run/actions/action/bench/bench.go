@@ -16,13 +16,19 @@ package bench
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"github.com/pkg/errors"
 	vegeta "github.com/tsenart/vegeta/v12/lib"
 	"github.com/wstool/wst/app"
 	"github.com/wstool/wst/conf/types"
 	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/actions/action/request"
 	"github.com/wstool/wst/run/instances/runtime"
 	"github.com/wstool/wst/run/services"
+	"io"
+	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -54,6 +60,17 @@ func (m *ActionMaker) Make(
 		return nil, err
 	}
 
+	if len(config.Phases) > 0 {
+		if config.Attack != (types.BenchAttack{}) {
+			return nil, errors.New("bench attack cannot be combined with phases")
+		}
+		// The duration is the total duration of all phases.
+		config.Duration = 0
+		for _, phaseConfig := range config.Phases {
+			config.Duration += phaseConfig.Duration
+		}
+	}
+
 	if config.Timeout == 0 && config.Duration != 0 {
 		if defaultTimeout > config.Duration {
 			config.Timeout = defaultTimeout
@@ -70,35 +87,102 @@ func (m *ActionMaker) Make(
 		}
 	}
 
+	if config.Scheme != "https" && request.IsTLSConfigured(&config.TLS) {
+		return nil, errors.New("TLS configuration is only valid for HTTPS bench")
+	}
+	if err = request.ValidateTLSConfig(&config.TLS); err != nil {
+		return nil, err
+	}
+
+	var protocols []request.Protocol
+	for _, protoStr := range config.Protocols {
+		proto := request.Protocol(protoStr)
+		if proto == request.ProtocolHTTP3 {
+			if config.Scheme != "https" {
+				return nil, errors.New("HTTP/3 protocol is only valid for HTTPS bench")
+			}
+			if len(config.Protocols) > 1 {
+				return nil, errors.New("HTTP/3 protocol cannot be combined with other protocols in bench")
+			}
+		}
+		protocols = append(protocols, proto)
+	}
+
+	if err = request.ValidateBody(&config.Body); err != nil {
+		return nil, err
+	}
+	for i, target := range config.Targets {
+		if target.Weight <= 0 {
+			return nil, errors.Errorf("bench target %d has non-positive weight %d", i, target.Weight)
+		}
+		if err = request.ValidateBody(&config.Targets[i].Body); err != nil {
+			return nil, errors.Errorf("bench target %d: %v", i, err)
+		}
+	}
+
+	var phases []*phase
+	var maxRate *maxRateSearch
+	duration := time.Duration(config.Duration) * time.Millisecond
+	if len(config.Phases) > 0 {
+		phases, err = buildPhases(config.Phases, config.Frequency)
+	} else if attackProfile(&config.Attack) == ProfileMaxRate {
+		maxRate, err = buildMaxRateSearch(&config.Attack, duration)
+	} else if config.Attack != (types.BenchAttack{}) {
+		var p *phase
+		if p, err = buildPhase("", duration, &config.Attack, config.Frequency); err == nil {
+			phases = []*phase{p}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	return &Action{
-		fnd:       m.fnd,
-		service:   svc,
-		timeout:   time.Duration(config.Timeout) * time.Millisecond,
-		duration:  time.Duration(config.Duration) * time.Millisecond,
-		when:      action.When(config.When),
-		onFailure: action.OnFailureType(config.OnFailure),
-		freq:      config.Frequency,
-		id:        config.Id,
-		scheme:    config.Scheme,
-		path:      config.Path,
-		method:    config.Method,
-		headers:   config.Headers,
+		fnd:            m.fnd,
+		service:        svc,
+		timeout:        time.Duration(config.Timeout) * time.Millisecond,
+		duration:       duration,
+		when:           action.When(config.When),
+		onFailure:      action.OnFailureType(config.OnFailure),
+		freq:           config.Frequency,
+		id:             config.Id,
+		scheme:         config.Scheme,
+		path:           config.Path,
+		encodePath:     config.EncodePath,
+		method:         config.Method,
+		headers:        config.Headers,
+		body:           &config.Body,
+		targets:        config.Targets,
+		phases:         phases,
+		maxRate:        maxRate,
+		protocols:      protocols,
+		seriesInterval: time.Duration(config.MetricsInterval) * time.Millisecond,
+		tls:            &config.TLS,
 	}, nil
 }
 
 type Action struct {
-	fnd       app.Foundation
-	service   services.Service
-	when      action.When
-	onFailure action.OnFailureType
-	timeout   time.Duration
-	duration  time.Duration
-	freq      int
-	id        string
-	scheme    string
-	path      string
-	method    string
-	headers   types.Headers
+	fnd        app.Foundation
+	service    services.Service
+	when       action.When
+	onFailure  action.OnFailureType
+	timeout    time.Duration
+	duration   time.Duration
+	freq       int
+	id         string
+	scheme     string
+	path       string
+	encodePath bool
+	method     string
+	headers    types.Headers
+	body       *types.RequestBody
+	targets    []types.BenchTarget
+	phases     []*phase
+	maxRate    *maxRateSearch
+	// seriesInterval is the length of metrics time series buckets.
+	seriesInterval time.Duration
+	protocols      []request.Protocol
+	tls            *types.TLSClientConfig
 }
 
 func (a *Action) When() action.When {
@@ -115,51 +199,269 @@ func (a *Action) Timeout() time.Duration {
 
 func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error) {
 	a.fnd.Logger().Infof("Executing bench action")
-	url, err := a.service.PublicUrl(a.scheme, a.path)
+	targets, weights, err := a.buildTargets(runData)
 	if err != nil {
 		return false, err
 	}
-	rate := vegeta.Rate{Freq: a.freq, Per: time.Second}
-	targeter := vegeta.NewStaticTargeter(vegeta.Target{
-		Method: a.method,
-		URL:    url,
-	})
-	attacker := a.fnd.VegetaAttacker()
+	targeter := newWeightedTargeter(targets, weights)
+	opts, closer, err := a.attackerOptions()
+	if err != nil {
+		return false, err
+	}
+	if closer != nil {
+		defer closer.Close()
+	}
+
+	phases := a.phases
+	if a.maxRate != nil {
+		phases = a.maxRate.phases(a.duration)
+	} else if len(phases) == 0 {
+		for _, target := range targets {
+			a.fnd.Logger().Debugf("Starting vegeta attack equal to cmd execution: "+
+				"echo \"%s %s\" | vegeta attack -duration=%ds -rate=%d/1s | vegeta report",
+				target.Method, target.URL, a.duration/time.Second, a.freq)
+		}
+		phases = []*phase{{duration: a.duration, pacer: vegeta.Rate{Freq: a.freq, Per: time.Second}}}
+	}
+
+	total := a.newMetrics(a.fnd.VegetaMetrics(), time.Now())
+	maxRate := 0
+	for _, p := range phases {
+		phaseMetrics, err := a.attack(ctx, targeter, opts, p, total)
+		if err != nil {
+			return false, err
+		}
+		if phaseMetrics == nil {
+			continue
+		}
+		if err = a.storeMetrics(runData, fmt.Sprintf("%s/%s", a.id, p.name), phaseMetrics); err != nil {
+			return false, err
+		}
+		if a.maxRate != nil {
+			rate := int(p.pacer.Rate(0))
+			if success := phaseMetrics.metrics.Metrics().Success; success < a.maxRate.threshold {
+				a.fnd.Logger().Infof("Success ratio %.2f at rate %d is below threshold %.2f",
+					success, rate, a.maxRate.threshold)
+				break
+			}
+			maxRate = rate
+		}
+	}
+	total.Close()
 
-	a.fnd.Logger().Debugf("Starting vegeta attack equal to cmd execution: "+
-		"echo \"%s %s\" | vegeta attack -duration=%ds -rate=%d/1s | vegeta report",
-		a.method, url, a.duration/time.Second, a.freq)
-	results := attacker.Attack(targeter, rate, a.duration, a.service.Name())
+	if a.maxRate != nil {
+		total.maxRate = &maxRate
+	}
+	if err = a.storeMetrics(runData, a.id, total); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (a *Action) newMetrics(vegetaMetrics app.VegetaMetrics, start time.Time) *Metrics {
+	return &Metrics{
+		metrics: vegetaMetrics,
+		series:  newTimeSeries(start, a.seriesInterval),
+	}
+}
+
+// attack executes a single phase and adds its results to the total metrics. The metrics of the phase are
+// returned for named phases.
+func (a *Action) attack(
+	ctx context.Context,
+	targeter vegeta.Targeter,
+	opts []func(*vegeta.Attacker),
+	p *phase,
+	total *Metrics,
+) (*Metrics, error) {
+	if p.workers > 0 {
+		opts = append(append([]func(*vegeta.Attacker){}, opts...), vegeta.Workers(p.workers), vegeta.MaxWorkers(p.workers))
+	}
+	attacker := a.fnd.VegetaAttacker(opts...)
+
+	var phaseMetrics *Metrics
+	if p.name != "" {
+		a.fnd.Logger().Infof("Starting bench phase %s with pacer %v", p.name, p.pacer)
+		phaseMetrics = a.newMetrics(a.fnd.VegetaMetrics(), time.Now())
+	}
+	results := attacker.Attack(targeter, p.pacer, p.duration, a.service.Name())
 
-	metrics := a.fnd.VegetaMetrics()
 	done := make(chan struct{})
-	errChan := make(chan error)
 	go func() {
 		defer close(done)
 		for res := range results {
-			metrics.Add(res)
-		}
-		metrics.Close()
-
-		key := fmt.Sprintf("metrics/%s", a.id)
-		metricsData := &Metrics{
-			metrics: metrics,
-		}
-		a.fnd.Logger().Debugf("Storing response %s: %s", key, metricsData)
-		if err = runData.Store(key, metricsData); err != nil {
-			a.fnd.Logger().Errorf("Error storing metrics data: %v", err)
-			errChan <- err
+			total.Add(res)
+			if phaseMetrics != nil {
+				phaseMetrics.Add(res)
+			}
 		}
 	}()
 
 	select {
 	case <-ctx.Done():
-		a.fnd.Logger().Infof("Cancelling attack due to context cancellation.")
-		return false, ctx.Err()
-	case err = <-errChan:
-		return false, err
 	case <-done:
-		// Attack completed
-		return true, nil
 	}
+	if ctx.Err() != nil {
+		a.fnd.Logger().Infof("Cancelling attack due to context cancellation.")
+		return nil, ctx.Err()
+	}
+	if phaseMetrics != nil {
+		phaseMetrics.Close()
+	}
+	return phaseMetrics, nil
+}
+
+func (a *Action) storeMetrics(runData runtime.Data, id string, metricsData *Metrics) error {
+	key := fmt.Sprintf("metrics/%s", id)
+	a.fnd.Logger().Debugf("Storing response %s: %s", key, metricsData)
+	if err := runData.Store(key, metricsData); err != nil {
+		a.fnd.Logger().Errorf("Error storing metrics data: %v", err)
+		return err
+	}
+	return nil
+}
+
+// targetConfigs returns the configured targets or a single target created from the top level settings if no
+// targets are set.
+func (a *Action) targetConfigs() []types.BenchTarget {
+	if len(a.targets) > 0 {
+		return a.targets
+	}
+	target := types.BenchTarget{
+		Path:       a.path,
+		EncodePath: a.encodePath,
+		Method:     a.method,
+		Weight:     1,
+	}
+	if a.body != nil {
+		target.Body = *a.body
+	}
+	return []types.BenchTarget{target}
+}
+
+// buildTargets creates vegeta targets together with their weights. The top level headers are applied to all
+// targets and can be overridden by the target headers.
+func (a *Action) buildTargets(runData runtime.Data) ([]vegeta.Target, []int, error) {
+	configs := a.targetConfigs()
+	targets := make([]vegeta.Target, 0, len(configs))
+	weights := make([]int, 0, len(configs))
+	for _, config := range configs {
+		path, err := request.RenderRuntimeTemplate(a.service, config.Path, runData)
+		if err != nil {
+			return nil, nil, err
+		}
+		header := http.Header{}
+		for key, value := range a.headers {
+			header.Set(key, value)
+		}
+		for key, value := range config.Headers {
+			header.Set(key, value)
+		}
+		urlPath := path
+		if !config.EncodePath {
+			// The raw path is set by the raw path transport so the URL contains just the root path.
+			urlPath = "/"
+			header.Set(rawPathHeader, path)
+		}
+		targetUrl, err := a.service.PublicUrl(a.scheme, urlPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		var body []byte
+		if request.HasBody(&config.Body) {
+			var contentType string
+			body, contentType, err = request.ReadBody(a.fnd, a.service, &config.Body, runData)
+			if err != nil {
+				return nil, nil, err
+			}
+			if contentType != "" {
+				header.Set("Content-Type", contentType)
+			}
+		}
+		targets = append(targets, vegeta.Target{
+			Method: config.Method,
+			URL:    targetUrl,
+			Body:   body,
+			Header: header,
+		})
+		weights = append(weights, config.Weight)
+	}
+	return targets, weights, nil
+}
+
+// usesRawPath returns true if any of the targets disables path encoding.
+func (a *Action) usesRawPath() bool {
+	for _, config := range a.targetConfigs() {
+		if !config.EncodePath {
+			return true
+		}
+	}
+	return false
+}
+
+// attackerOptions returns attacker options with a client using the configured protocols, TLS configuration and
+// path encoding. No options are returned if the default vegeta client can be used. The returned closer is set
+// if the client uses a transport that needs closing.
+func (a *Action) attackerOptions() ([]func(*vegeta.Attacker), io.Closer, error) {
+	tlsConfigured := a.scheme == "https" && a.tls != nil && request.IsTLSConfigured(a.tls)
+	rawPath := a.usesRawPath()
+	if len(a.protocols) == 0 && !tlsConfigured && !rawPath {
+		return nil, nil, nil
+	}
+
+	// Certificate verification is skipped by default the same way as for the default vegeta transport.
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	if tlsConfigured {
+		var err error
+		tlsConfig, err = request.BuildTLSConfig(a.fnd, a.service, a.tls)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var transport http.RoundTripper
+	var closer io.Closer
+	if len(a.protocols) == 1 && a.protocols[0] == request.ProtocolHTTP3 {
+		tr := request.NewHttp3Transport(tlsConfig, "")
+		transport = tr
+		closer = tr
+	} else {
+		protocols := a.protocols
+		if len(protocols) == 0 {
+			protocols = request.DefaultProtocols(a.scheme)
+		}
+		tr := request.BuildTransport(protocols, a.scheme, tlsConfig)
+		tr.MaxIdleConnsPerHost = vegeta.DefaultConnections
+		transport = tr
+	}
+	if rawPath {
+		transport = &rawPathTransport{base: transport}
+	}
+
+	return []func(*vegeta.Attacker){
+		vegeta.Client(&http.Client{Transport: transport, Timeout: vegeta.DefaultTimeout}),
+	}, closer, nil
+}
+
+// rawPathHeader is an internal header passing the raw path of targets that do not encode path to the transport.
+const rawPathHeader = "X-Wst-Raw-Path"
+
+// rawPathTransport sends the request path without any encoding if the raw path header is set.
+type rawPathTransport struct {
+	base http.RoundTripper
+}
+
+func (t *rawPathTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rawPath := req.Header.Get(rawPathHeader)
+	if rawPath == "" {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Del(rawPathHeader)
+	req.URL = &url.URL{
+		Scheme: req.URL.Scheme,
+		Host:   req.URL.Host,
+		Opaque: fmt.Sprintf("//%s%s", req.URL.Host, rawPath),
+	}
+	return t.base.RoundTrip(req)
 }
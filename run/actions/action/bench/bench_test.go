@@ -2,9 +2,12 @@ package bench
 
 import (
 	"context"
+	"fmt"
 	"github.com/pkg/errors"
+	"github.com/quic-go/quic-go/http3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	vegeta "github.com/tsenart/vegeta/v12/lib"
 	"github.com/wstool/wst/app"
 	"github.com/wstool/wst/conf/types"
@@ -13,6 +16,9 @@ import (
 	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
 	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
 	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/actions/action/request"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -38,14 +44,15 @@ func TestCreateActionMaker(t *testing.T) {
 
 func TestActionMaker_Make(t *testing.T) {
 	tests := []struct {
-		name             string
-		config           *types.BenchAction
-		defaultTimeout   int
-		expectedTimeout  time.Duration
-		expectedDuration time.Duration
-		locatorErr       error
-		expectError      bool
-		expectedErrorMsg string
+		name              string
+		config            *types.BenchAction
+		defaultTimeout    int
+		expectedTimeout   time.Duration
+		expectedDuration  time.Duration
+		expectedProtocols []request.Protocol
+		locatorErr        error
+		expectError       bool
+		expectedErrorMsg  string
 	}{
 		{
 			name: "successful action creation with default timeout",
@@ -113,6 +120,128 @@ func TestActionMaker_Make(t *testing.T) {
 			expectedTimeout:  0,
 			expectedDuration: 0,
 		},
+		{
+			name: "successful action creation with HTTP/3 protocol",
+			config: &types.BenchAction{
+				Service:   "validService",
+				Duration:  3000,
+				Frequency: 1,
+				Id:        "testAction",
+				Scheme:    "https",
+				Path:      "/test",
+				Method:    "GET",
+				Protocols: []string{"http3"},
+			},
+			defaultTimeout:    5000,
+			expectedTimeout:   5000 * time.Millisecond,
+			expectedDuration:  3000 * time.Millisecond,
+			expectedProtocols: []request.Protocol{request.ProtocolHTTP3},
+		},
+		{
+			name: "failed action creation with HTTP/3 protocol over HTTP",
+			config: &types.BenchAction{
+				Service:   "validService",
+				Scheme:    "http",
+				Protocols: []string{"http3"},
+			},
+			defaultTimeout:   5000,
+			expectError:      true,
+			expectedErrorMsg: "HTTP/3 protocol is only valid for HTTPS bench",
+		},
+		{
+			name: "failed action creation with HTTP/3 combined with other protocols",
+			config: &types.BenchAction{
+				Service:   "validService",
+				Scheme:    "https",
+				Protocols: []string{"http2", "http3"},
+			},
+			defaultTimeout:   5000,
+			expectError:      true,
+			expectedErrorMsg: "HTTP/3 protocol cannot be combined with other protocols in bench",
+		},
+		{
+			name: "failed action creation with TLS configuration over HTTP",
+			config: &types.BenchAction{
+				Service: "validService",
+				Scheme:  "http",
+				TLS:     types.TLSClientConfig{ClientCert: "client"},
+			},
+			defaultTimeout:   5000,
+			expectError:      true,
+			expectedErrorMsg: "TLS configuration is only valid for HTTPS bench",
+		},
+		{
+			name: "failed action creation with invalid TLS version",
+			config: &types.BenchAction{
+				Service: "validService",
+				Scheme:  "https",
+				TLS:     types.TLSClientConfig{MinVersion: "2.0"},
+			},
+			defaultTimeout:   5000,
+			expectError:      true,
+			expectedErrorMsg: "invalid TLS version 2.0",
+		},
+		{
+			name: "successful action creation with targets",
+			config: &types.BenchAction{
+				Service:   "validService",
+				Duration:  3000,
+				Frequency: 1,
+				Id:        "testAction",
+				Scheme:    "http",
+				Headers:   types.Headers{"Accept": "*/*"},
+				Targets: []types.BenchTarget{
+					{Path: "/a", Method: "GET", EncodePath: true, Weight: 3},
+					{Path: "/b", Method: "POST", Body: types.RequestBody{Content: "data"}, Weight: 1},
+				},
+			},
+			defaultTimeout:   5000,
+			expectedTimeout:  5000 * time.Millisecond,
+			expectedDuration: 3000 * time.Millisecond,
+		},
+		{
+			name: "failed action creation with invalid body",
+			config: &types.BenchAction{
+				Service: "validService",
+				Scheme:  "http",
+				Body:    types.RequestBody{Content: "data", File: "body.txt"},
+			},
+			defaultTimeout:   5000,
+			expectError:      true,
+			expectedErrorMsg: "request body can set only one of content, multipart and file",
+		},
+		{
+			name: "failed action creation with non-positive target weight",
+			config: &types.BenchAction{
+				Service: "validService",
+				Scheme:  "http",
+				Targets: []types.BenchTarget{
+					{Path: "/a", Method: "GET", Weight: 1},
+					{Path: "/b", Method: "GET", Weight: 0},
+				},
+			},
+			defaultTimeout:   5000,
+			expectError:      true,
+			expectedErrorMsg: "bench target 1 has non-positive weight 0",
+		},
+		{
+			name: "failed action creation with invalid target body",
+			config: &types.BenchAction{
+				Service: "validService",
+				Scheme:  "http",
+				Targets: []types.BenchTarget{
+					{
+						Path:   "/a",
+						Method: "POST",
+						Weight: 1,
+						Body:   types.RequestBody{Multipart: []types.RequestBodyPart{{Content: "data"}}},
+					},
+				},
+			},
+			defaultTimeout:   5000,
+			expectError:      true,
+			expectedErrorMsg: "bench target 0: multipart body part 0 is missing name",
+		},
 		{
 			name:             "service locator failure",
 			config:           &types.BenchAction{Service: "invalidService"},
@@ -155,6 +284,10 @@ func TestActionMaker_Make(t *testing.T) {
 				assert.Equal(tt.config.Path, action.path)
 				assert.Equal(tt.config.Method, action.method)
 				assert.Equal(tt.config.Headers, action.headers)
+				assert.Equal(tt.config.EncodePath, action.encodePath)
+				assert.Equal(&tt.config.Body, action.body)
+				assert.Equal(tt.config.Targets, action.targets)
+				assert.Equal(tt.expectedProtocols, action.protocols)
 			}
 		})
 	}
@@ -183,6 +316,7 @@ func TestAction_Execute(t *testing.T) {
 	}
 	tests := []struct {
 		name          string
+		protocols     []request.Protocol
 		setupMocks    func(*testing.T, *appMocks.MockFoundation, *servicesMocks.MockService, *runtimeMocks.MockData)
 		contextSetup  func() context.Context
 		expectSuccess bool
@@ -229,6 +363,47 @@ func TestAction_Execute(t *testing.T) {
 			expectSuccess: true,
 			expectErr:     false,
 		},
+		{
+			name:      "successful execution with protocols",
+			protocols: []request.Protocol{request.ProtocolHTTP2},
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, runData *runtimeMocks.MockData) {
+				vegetaAttackerMock := appMocks.NewMockVegetaAttacker(t)
+				result := &vegeta.Result{}
+				results := make(chan *vegeta.Result)
+				go func() {
+					defer close(results)
+					results <- result
+				}()
+				vegetaAttackerMock.On(
+					"Attack",
+					mock.MatchedBy(func(targeter vegeta.Targeter) bool {
+						// We are checking the type using the argument type
+						return true
+					}),
+					mock.MatchedBy(func(rate vegeta.Rate) bool {
+						return assert.Equal(t, freq, rate.Freq) && assert.Equal(t, time.Second, rate.Per)
+					}),
+					duration,
+					serviceName,
+				).Return((<-chan *vegeta.Result)(results))
+				vegetaMetricsMock := appMocks.NewMockVegetaMetrics(t)
+				vegetaMetricsMock.On("Add", result).Return()
+				vegetaMetricsMock.On("Close").Return()
+				vegetaMetricsMock.On("Metrics").Return(vm)
+				fnd.On("VegetaAttacker", mock.Anything).Return(vegetaAttackerMock)
+				fnd.On("VegetaMetrics").Return(vegetaMetricsMock)
+				svc.On("Name").Return(serviceName)
+				svc.On("PublicUrl", "http", "/test").Return("http://example.com", nil)
+				runData.On("Store", "metrics/sid", mock.MatchedBy(func(metrics *Metrics) bool {
+					return assert.Equal(t, vegetaMetricsMock, metrics.metrics)
+				})).Return(nil)
+			},
+			contextSetup: func() context.Context {
+				return context.Background()
+			},
+			expectSuccess: true,
+			expectErr:     false,
+		},
 		{
 			name: "execution with context cancellation",
 			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, runData *runtimeMocks.MockData) {
@@ -339,13 +514,15 @@ func TestAction_Execute(t *testing.T) {
 			tt.setupMocks(t, fndMock, svcMock, runDataMock)
 
 			a := &Action{
-				fnd:      fndMock,
-				service:  svcMock,
-				duration: duration,
-				id:       "sid",
-				scheme:   "http",
-				path:     "/test",
-				freq:     freq,
+				fnd:        fndMock,
+				service:    svcMock,
+				duration:   duration,
+				id:         "sid",
+				scheme:     "http",
+				path:       "/test",
+				encodePath: true,
+				freq:       freq,
+				protocols:  tt.protocols,
 			}
 
 			ctx := tt.contextSetup()
@@ -368,6 +545,431 @@ func TestAction_Execute(t *testing.T) {
 	}
 }
 
+func TestActionMaker_Make_Attack(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           *types.BenchAction
+		expectedDuration time.Duration
+		expectedTimeout  time.Duration
+		expectedPhases   []*phase
+		expectedMaxRate  *maxRateSearch
+		expectedErrorMsg string
+	}{
+		{
+			name: "phases set total duration",
+			config: &types.BenchAction{
+				Service:   "validService",
+				Frequency: 10,
+				Phases: []types.BenchPhase{
+					{Name: "warmup", Duration: 2000, Attack: types.BenchAttack{Profile: "ramp", StartFrequency: 1, EndFrequency: 11}},
+					{Name: "load", Duration: 3000},
+				},
+			},
+			expectedDuration: 5000 * time.Millisecond,
+			expectedTimeout:  10000 * time.Millisecond,
+			expectedPhases: []*phase{
+				{
+					name:     "warmup",
+					duration: 2 * time.Second,
+					pacer:    vegeta.LinearPacer{StartAt: vegeta.Rate{Freq: 1, Per: time.Second}, Slope: 5},
+				},
+				{name: "load", duration: 3 * time.Second, pacer: vegeta.Rate{Freq: 10, Per: time.Second}},
+			},
+		},
+		{
+			name: "single attack profile",
+			config: &types.BenchAction{
+				Service:         "validService",
+				Duration:        1000,
+				Attack:          types.BenchAttack{Profile: "workers", Workers: 4},
+				MetricsInterval: 500,
+			},
+			expectedDuration: time.Second,
+			expectedTimeout:  5000 * time.Millisecond,
+			expectedPhases: []*phase{
+				{duration: time.Second, pacer: vegeta.Rate{Freq: 0, Per: time.Second}, workers: 4},
+			},
+		},
+		{
+			name: "max rate search",
+			config: &types.BenchAction{
+				Service:  "validService",
+				Duration: 10000,
+				Attack: types.BenchAttack{
+					Profile:          "max_rate",
+					StartFrequency:   10,
+					StepFrequency:    10,
+					StepDuration:     2000,
+					SuccessThreshold: 0.95,
+				},
+			},
+			expectedDuration: 10 * time.Second,
+			expectedTimeout:  15 * time.Second,
+			expectedMaxRate: &maxRateSearch{
+				startFrequency: 10,
+				stepFrequency:  10,
+				stepDuration:   2 * time.Second,
+				threshold:      0.95,
+			},
+		},
+		{
+			name: "attack combined with phases",
+			config: &types.BenchAction{
+				Service: "validService",
+				Attack:  types.BenchAttack{Profile: "ramp"},
+				Phases:  []types.BenchPhase{{Name: "load", Duration: 1000}},
+			},
+			expectedErrorMsg: "bench attack cannot be combined with phases",
+		},
+		{
+			name: "invalid phase",
+			config: &types.BenchAction{
+				Service: "validService",
+				Phases:  []types.BenchPhase{{Duration: 1000}},
+			},
+			expectedErrorMsg: "bench phase 0 is missing name",
+		},
+		{
+			name: "invalid attack",
+			config: &types.BenchAction{
+				Service:  "validService",
+				Duration: 1000,
+				Attack:   types.BenchAttack{Profile: "sine"},
+			},
+			expectedErrorMsg: "sine profile requires positive frequency, amplitude and period",
+		},
+		{
+			name: "invalid max rate search",
+			config: &types.BenchAction{
+				Service: "validService",
+				Attack:  types.BenchAttack{Profile: "max_rate"},
+			},
+			expectedErrorMsg: "max_rate profile requires positive start_frequency, step_frequency and step_duration",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			m := &ActionMaker{
+				fnd: fndMock,
+			}
+			svcMock := servicesMocks.NewMockService(t)
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			slMock.On("Find", tt.config.Service).Return(svcMock, nil)
+			got, err := m.Make(tt.config, slMock, 5000)
+			if tt.expectedErrorMsg != "" {
+				assert.EqualError(t, err, tt.expectedErrorMsg)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			a, ok := got.(*Action)
+			require.True(t, ok)
+			assert.Equal(t, tt.expectedDuration, a.duration)
+			assert.Equal(t, tt.expectedTimeout, a.timeout)
+			assert.Equal(t, tt.expectedPhases, a.phases)
+			assert.Equal(t, tt.expectedMaxRate, a.maxRate)
+			assert.Equal(t, time.Duration(tt.config.MetricsInterval)*time.Millisecond, a.seriesInterval)
+		})
+	}
+}
+
+func metricsMatcher(vegetaMetrics app.VegetaMetrics) interface{} {
+	return mock.MatchedBy(func(m *Metrics) bool {
+		return m.metrics == vegetaMetrics && m.series != nil
+	})
+}
+
+func TestAction_Execute_Phases(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	svcMock := servicesMocks.NewMockService(t)
+	runDataMock := runtimeMocks.NewMockData(t)
+	fndMock.On("Logger").Return(external.NewMockLogger().SugaredLogger)
+	svcMock.On("Name").Return("svc")
+	svcMock.On("PublicUrl", "http", "/test").Return("http://example.com/test", nil)
+
+	warmup := &phase{name: "warmup", duration: time.Second, pacer: vegeta.Rate{Freq: 5, Per: time.Second}}
+	load := &phase{name: "load", duration: time.Second, pacer: vegeta.Rate{Freq: 0, Per: time.Second}, workers: 2}
+	a := &Action{
+		fnd:        fndMock,
+		service:    svcMock,
+		id:         "sid",
+		scheme:     "http",
+		path:       "/test",
+		encodePath: true,
+		method:     "GET",
+		phases:     []*phase{warmup, load},
+	}
+
+	resultsFor := func(res *vegeta.Result) <-chan *vegeta.Result {
+		results := make(chan *vegeta.Result, 1)
+		results <- res
+		close(results)
+		return results
+	}
+	warmupResult := &vegeta.Result{Code: 200}
+	loadResult := &vegeta.Result{Code: 500}
+
+	warmupAttacker := appMocks.NewMockVegetaAttacker(t)
+	warmupAttacker.On("Attack", mock.Anything, warmup.pacer, time.Second, "svc").Return(resultsFor(warmupResult))
+	loadAttacker := appMocks.NewMockVegetaAttacker(t)
+	loadAttacker.On("Attack", mock.Anything, load.pacer, time.Second, "svc").Return(resultsFor(loadResult))
+	fndMock.On("VegetaAttacker").Return(warmupAttacker).Once()
+	// Workers options are added for the closed model phase.
+	fndMock.On("VegetaAttacker", mock.Anything, mock.Anything).Return(loadAttacker).Once()
+
+	totalMetrics := appMocks.NewMockVegetaMetrics(t)
+	warmupMetrics := appMocks.NewMockVegetaMetrics(t)
+	loadMetrics := appMocks.NewMockVegetaMetrics(t)
+	fndMock.On("VegetaMetrics").Return(totalMetrics).Once()
+	fndMock.On("VegetaMetrics").Return(warmupMetrics).Once()
+	fndMock.On("VegetaMetrics").Return(loadMetrics).Once()
+	totalMetrics.On("Add", warmupResult).Return().Once()
+	totalMetrics.On("Add", loadResult).Return().Once()
+	totalMetrics.On("Close").Return()
+	totalMetrics.On("Metrics").Return(&vegeta.Metrics{Requests: 2})
+	warmupMetrics.On("Add", warmupResult).Return()
+	warmupMetrics.On("Close").Return()
+	warmupMetrics.On("Metrics").Return(&vegeta.Metrics{Requests: 1})
+	loadMetrics.On("Add", loadResult).Return()
+	loadMetrics.On("Close").Return()
+	loadMetrics.On("Metrics").Return(&vegeta.Metrics{Requests: 1})
+
+	runDataMock.On("Store", "metrics/sid/warmup", metricsMatcher(warmupMetrics)).Return(nil)
+	runDataMock.On("Store", "metrics/sid/load", metricsMatcher(loadMetrics)).Return(nil)
+	runDataMock.On("Store", "metrics/sid", metricsMatcher(totalMetrics)).Return(nil)
+
+	success, err := a.Execute(context.Background(), runDataMock)
+	assert.NoError(t, err)
+	assert.True(t, success)
+}
+
+func TestAction_Execute_MaxRate(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	svcMock := servicesMocks.NewMockService(t)
+	runDataMock := runtimeMocks.NewMockData(t)
+	fndMock.On("Logger").Return(external.NewMockLogger().SugaredLogger)
+	svcMock.On("Name").Return("svc")
+	svcMock.On("PublicUrl", "http", "/test").Return("http://example.com/test", nil)
+
+	a := &Action{
+		fnd:        fndMock,
+		service:    svcMock,
+		id:         "sid",
+		scheme:     "http",
+		path:       "/test",
+		encodePath: true,
+		method:     "GET",
+		duration:   10 * time.Second,
+		maxRate: &maxRateSearch{
+			startFrequency: 10,
+			stepFrequency:  10,
+			stepDuration:   time.Second,
+			threshold:      0.9,
+		},
+	}
+
+	attacker := appMocks.NewMockVegetaAttacker(t)
+	fndMock.On("VegetaAttacker").Return(attacker)
+	totalMetrics := appMocks.NewMockVegetaMetrics(t)
+	fndMock.On("VegetaMetrics").Return(totalMetrics).Once()
+	totalMetrics.On("Add", mock.Anything).Return()
+	totalMetrics.On("Close").Return()
+	totalMetrics.On("Metrics").Return(&vegeta.Metrics{})
+
+	// The success ratio drops below the threshold in the third step.
+	for i, success := range []float64{1, 0.95, 0.5} {
+		results := make(chan *vegeta.Result, 1)
+		results <- &vegeta.Result{}
+		close(results)
+		rate := vegeta.Rate{Freq: 10 * (i + 1), Per: time.Second}
+		attacker.On("Attack", mock.Anything, rate, time.Second, "svc").Return((<-chan *vegeta.Result)(results)).Once()
+		stepMetrics := appMocks.NewMockVegetaMetrics(t)
+		stepMetrics.On("Add", mock.Anything).Return()
+		stepMetrics.On("Close").Return()
+		stepMetrics.On("Metrics").Return(&vegeta.Metrics{Success: success})
+		fndMock.On("VegetaMetrics").Return(stepMetrics).Once()
+		runDataMock.On("Store", fmt.Sprintf("metrics/sid/step_%d", i+1), metricsMatcher(stepMetrics)).Return(nil)
+	}
+	runDataMock.On("Store", "metrics/sid", mock.MatchedBy(func(m *Metrics) bool {
+		return m.metrics == totalMetrics && m.maxRate != nil && *m.maxRate == 20
+	})).Return(nil)
+
+	success, err := a.Execute(context.Background(), runDataMock)
+	assert.NoError(t, err)
+	assert.True(t, success)
+}
+
+func TestAction_buildTargets(t *testing.T) {
+	tests := []struct {
+		name            string
+		action          *Action
+		setupMocks      func(*servicesMocks.MockService)
+		expectedTargets []vegeta.Target
+		expectedWeights []int
+		expectedErr     string
+	}{
+		{
+			name: "single target from top level settings",
+			action: &Action{
+				scheme:     "http",
+				path:       "/test",
+				encodePath: true,
+				method:     "POST",
+				headers:    types.Headers{"Content-Type": "text/plain"},
+				body:       &types.RequestBody{Content: "data"},
+			},
+			setupMocks: func(svc *servicesMocks.MockService) {
+				svc.On("PublicUrl", "http", "/test").Return("http://example.com/test", nil)
+			},
+			expectedTargets: []vegeta.Target{
+				{
+					Method: "POST",
+					URL:    "http://example.com/test",
+					Body:   []byte("data"),
+					Header: http.Header{"Content-Type": []string{"text/plain"}},
+				},
+			},
+			expectedWeights: []int{1},
+		},
+		{
+			name: "multiple weighted targets with header override and raw path",
+			action: &Action{
+				scheme:     "https",
+				path:       "/ignored",
+				encodePath: true,
+				method:     "GET",
+				headers:    types.Headers{"Accept": "*/*", "X-Test": "top"},
+				targets: []types.BenchTarget{
+					{
+						Path:       "/a",
+						EncodePath: true,
+						Method:     "GET",
+						Headers:    types.Headers{"X-Test": "target"},
+						Weight:     3,
+					},
+					{
+						Path:   "/b?x=a b",
+						Method: "DELETE",
+						Weight: 1,
+					},
+				},
+			},
+			setupMocks: func(svc *servicesMocks.MockService) {
+				svc.On("PublicUrl", "https", "/a").Return("https://example.com/a", nil)
+				svc.On("PublicUrl", "https", "/").Return("https://example.com/", nil)
+			},
+			expectedTargets: []vegeta.Target{
+				{
+					Method: "GET",
+					URL:    "https://example.com/a",
+					Header: http.Header{"Accept": []string{"*/*"}, "X-Test": []string{"target"}},
+				},
+				{
+					Method: "DELETE",
+					URL:    "https://example.com/",
+					Header: http.Header{
+						"Accept":         []string{"*/*"},
+						"X-Test":         []string{"top"},
+						"X-Wst-Raw-Path": []string{"/b?x=a b"},
+					},
+				},
+			},
+			expectedWeights: []int{3, 1},
+		},
+		{
+			name: "multipart body sets content type",
+			action: &Action{
+				scheme: "http",
+				targets: []types.BenchTarget{
+					{
+						Path:       "/upload",
+						EncodePath: true,
+						Method:     "POST",
+						Weight:     2,
+						Body: types.RequestBody{
+							Multipart: []types.RequestBodyPart{{Name: "field", Content: "value"}},
+						},
+					},
+				},
+			},
+			setupMocks: func(svc *servicesMocks.MockService) {
+				svc.On("PublicUrl", "http", "/upload").Return("http://example.com/upload", nil)
+			},
+			expectedWeights: []int{2},
+		},
+		{
+			name: "public URL failure",
+			action: &Action{
+				scheme:     "http",
+				path:       "/test",
+				encodePath: true,
+				method:     "GET",
+			},
+			setupMocks: func(svc *servicesMocks.MockService) {
+				svc.On("PublicUrl", "http", "/test").Return("", errors.New("public url error"))
+			},
+			expectedErr: "public url error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			svcMock := servicesMocks.NewMockService(t)
+			runDataMock := runtimeMocks.NewMockData(t)
+			tt.setupMocks(svcMock)
+			tt.action.fnd = fndMock
+			tt.action.service = svcMock
+
+			targets, weights, err := tt.action.buildTargets(runDataMock)
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedWeights, weights)
+			if tt.expectedTargets != nil {
+				assert.Equal(t, tt.expectedTargets, targets)
+			} else {
+				require.Len(t, targets, 1)
+				assert.Contains(t, targets[0].Header.Get("Content-Type"), "multipart/form-data; boundary=")
+				assert.Contains(t, string(targets[0].Body), "name=\"field\"")
+				assert.Contains(t, string(targets[0].Body), "value")
+			}
+		})
+	}
+}
+
+func TestRawPathTransport_RoundTrip(t *testing.T) {
+	var requestUris []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestUris = append(requestUris, r.RequestURI)
+		assert.Empty(t, r.Header.Get(rawPathHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &rawPathTransport{base: http.DefaultTransport}}
+
+	req, err := http.NewRequest("GET", server.URL+"/", nil)
+	require.NoError(t, err)
+	req.Header.Set(rawPathHeader, "/path/%2e%2e/raw")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	// The original request must stay untouched.
+	assert.Equal(t, "/path/%2e%2e/raw", req.Header.Get(rawPathHeader))
+
+	resp, err = client.Get(server.URL + "/encoded%20path")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// The raw path is sent in the absolute form the same way as for the request action.
+	assert.Equal(t, []string{server.URL + "/path/%2e%2e/raw", "/encoded%20path"}, requestUris)
+}
+
 func TestAction_Timeout(t *testing.T) {
 	fndMock := appMocks.NewMockFoundation(t)
 	a := &Action{
@@ -395,3 +997,103 @@ func TestAction_OnFailure(t *testing.T) {
 	}
 	assert.Equal(t, action.Skip, a.OnFailure())
 }
+
+func TestAction_attackerOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		scheme         string
+		protocols      []request.Protocol
+		tls            *types.TLSClientConfig
+		rawPath        bool
+		expectedOpts   int
+		expectedCloser bool
+	}{
+		{
+			name:         "default protocols",
+			scheme:       "https",
+			expectedOpts: 0,
+		},
+		{
+			name:         "HTTP/1.1 only",
+			scheme:       "https",
+			protocols:    []request.Protocol{request.ProtocolHTTP11},
+			expectedOpts: 1,
+		},
+		{
+			name:         "HTTP/2 cleartext",
+			scheme:       "http",
+			protocols:    []request.Protocol{request.ProtocolHTTP2},
+			expectedOpts: 1,
+		},
+		{
+			name:         "TLS configuration with HTTP/2",
+			scheme:       "https",
+			protocols:    []request.Protocol{request.ProtocolHTTP2},
+			tls:          &types.TLSClientConfig{ServerName: "localhost", MinVersion: "1.2"},
+			expectedOpts: 1,
+		},
+		{
+			name:         "TLS configuration with default protocols",
+			scheme:       "https",
+			tls:          &types.TLSClientConfig{ServerName: "localhost"},
+			expectedOpts: 1,
+		},
+		{
+			name:         "raw path",
+			scheme:       "http",
+			rawPath:      true,
+			expectedOpts: 1,
+		},
+		{
+			name:         "TLS configuration ignored for HTTP",
+			scheme:       "http",
+			tls:          &types.TLSClientConfig{ServerName: "localhost"},
+			expectedOpts: 0,
+		},
+		{
+			name:           "HTTP/3",
+			scheme:         "https",
+			protocols:      []request.Protocol{request.ProtocolHTTP3},
+			expectedOpts:   1,
+			expectedCloser: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Action{
+				scheme:     tt.scheme,
+				encodePath: !tt.rawPath,
+				protocols:  tt.protocols,
+				tls:        tt.tls,
+			}
+			opts, closer, err := a.attackerOptions()
+			require.NoError(t, err)
+			assert.Len(t, opts, tt.expectedOpts)
+			if tt.expectedCloser {
+				require.NotNil(t, closer)
+				assert.IsType(t, &http3.Transport{}, closer)
+				assert.NoError(t, closer.Close())
+			} else {
+				assert.Nil(t, closer)
+			}
+			// Make sure that options can be applied
+			assert.NotNil(t, vegeta.NewAttacker(opts...))
+		})
+	}
+}
+
+func TestAction_attackerOptions_TLSError(t *testing.T) {
+	svcMock := servicesMocks.NewMockService(t)
+	svcMock.On("FindCertificate", "client").Return(nil, errors.New("not found"))
+	a := &Action{
+		service:    svcMock,
+		scheme:     "https",
+		encodePath: true,
+		tls:        &types.TLSClientConfig{ClientCert: "client"},
+	}
+	opts, closer, err := a.attackerOptions()
+	assert.EqualError(t, err, "client certificate client not found")
+	assert.Nil(t, opts)
+	assert.Nil(t, closer)
+}
@@ -16,13 +16,35 @@ package bench
 
 import (
 	"fmt"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
 	"github.com/wstool/wst/app"
 	"github.com/wstool/wst/run/metrics"
+	"strings"
 	"time"
 )
 
 type Metrics struct {
 	metrics app.VegetaMetrics
+	// series contains metrics in time buckets. It is nil if the time series is not recorded.
+	series *timeSeries
+	// maxRate is the maximal sustainable rate found by the max_rate attack profile.
+	maxRate *int
+}
+
+// Add adds the result to the metrics and its time series.
+func (m *Metrics) Add(res *vegeta.Result) {
+	m.metrics.Add(res)
+	if m.series != nil {
+		m.series.Add(res)
+	}
+}
+
+// Close computes the final metrics.
+func (m *Metrics) Close() {
+	m.metrics.Close()
+	if m.series != nil {
+		m.series.Close()
+	}
 }
 
 func (m *Metrics) Find(name string) (metrics.Metric, error) {
@@ -54,17 +76,74 @@ func (m *Metrics) Find(name string) (metrics.Metric, error) {
 		return metrics.GenericMetric[time.Duration]{Value: vm.Latencies.Max}, nil
 	case "LatencyMin":
 		return metrics.GenericMetric[time.Duration]{Value: vm.Latencies.Min}, nil
+	case "MaxRate":
+		if m.maxRate != nil {
+			return metrics.GenericMetric[int]{Value: *m.maxRate}, nil
+		}
+		return nil, fmt.Errorf("metric %s not found", name)
 	default:
 		return nil, fmt.Errorf("metric %s not found", name)
 	}
 }
 
+func (m *Metrics) FindWindowed(
+	name string,
+	window metrics.Window,
+	aggregation metrics.Aggregation,
+) (metrics.Metric, error) {
+	if m.series == nil {
+		return nil, fmt.Errorf("time series for metric %s not recorded", name)
+	}
+	values, ok := m.series.values(name, window)
+	if !ok {
+		return nil, fmt.Errorf("metric %s not found in time series", name)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no data for metric %s in time window", name)
+	}
+	value, err := metrics.Aggregate(aggregation, values)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(name, "Latency") {
+		return metrics.GenericMetric[time.Duration]{Value: time.Duration(value)}, nil
+	}
+	return metrics.GenericMetric[float64]{Value: value}, nil
+}
+
+func (m *Metrics) Values() map[string]interface{} {
+	vm := m.metrics.Metrics()
+	values := map[string]interface{}{
+		"Requests":     vm.Requests,
+		"Rate":         vm.Rate,
+		"Throughput":   vm.Throughput,
+		"Duration":     vm.Duration,
+		"Success":      vm.Success,
+		"LatencyTotal": vm.Latencies.Total,
+		"LatencyMean":  vm.Latencies.Mean,
+		"LatencyP50":   vm.Latencies.P50,
+		"LatencyP90":   vm.Latencies.P90,
+		"LatencyP95":   vm.Latencies.P95,
+		"LatencyP99":   vm.Latencies.P99,
+		"LatencyMax":   vm.Latencies.Max,
+		"LatencyMin":   vm.Latencies.Min,
+	}
+	if m.maxRate != nil {
+		values["MaxRate"] = *m.maxRate
+	}
+	return values
+}
+
 func (m *Metrics) String() string {
 	vm := m.metrics.Metrics()
+	maxRate := ""
+	if m.maxRate != nil {
+		maxRate = fmt.Sprintf(", MaxRate: %d", *m.maxRate)
+	}
 	return fmt.Sprintf(
 		"{Requests: %d, Rate: %.2f, Throughput: %.2f, Duration: %v, Success: %.2f, LatencyTotal: %v, "+
 			"LatencyMean: %v, LatencyP50: %v, LatencyP90: %v, LatencyP95: %v, LatencyP99: %v, "+
-			"LatencyMax: %v, LatencyMin: %v}",
+			"LatencyMax: %v, LatencyMin: %v%s}",
 		vm.Requests,
 		vm.Rate,
 		vm.Throughput,
@@ -78,5 +157,6 @@ func (m *Metrics) String() string {
 		vm.Latencies.P99,
 		vm.Latencies.Max,
 		vm.Latencies.Min,
+		maxRate,
 	)
 }
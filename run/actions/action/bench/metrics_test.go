@@ -33,6 +33,7 @@ func TestMetrics_Find(t *testing.T) {
 	tests := []struct {
 		name             string
 		metricName       string
+		maxRate          int
 		expectedMetric   metrics.Metric
 		expectError      bool
 		expectedErrorMsg string
@@ -102,6 +103,18 @@ func TestMetrics_Find(t *testing.T) {
 			metricName:     "LatencyMin",
 			expectedMetric: metrics.GenericMetric[time.Duration]{Value: time.Millisecond * 2},
 		},
+		{
+			name:           "Max rate test",
+			metricName:     "MaxRate",
+			maxRate:        50,
+			expectedMetric: metrics.GenericMetric[int]{Value: 50},
+		},
+		{
+			name:             "Max rate without max rate profile test",
+			metricName:       "MaxRate",
+			expectError:      true,
+			expectedErrorMsg: "metric MaxRate not found",
+		},
 		{
 			name:             "Invalid metric test",
 			metricName:       "InvalidMetric",
@@ -116,6 +129,9 @@ func TestMetrics_Find(t *testing.T) {
 			metricsMock := appMocks.NewMockVegetaMetrics(t)
 			metricsMock.On("Metrics").Return(vm)
 			m := Metrics{metrics: metricsMock}
+			if tt.maxRate > 0 {
+				m.maxRate = &tt.maxRate
+			}
 			result, err := m.Find(tt.metricName)
 
 			if tt.expectError {
@@ -158,4 +174,142 @@ func TestMetrics_String(t *testing.T) {
 		"LatencyP99: 30ms, LatencyMax: 40ms, LatencyMin: 2ms}"
 
 	assert.Equal(t, expected, fmt.Sprintf("%s", m))
+
+	maxRate := 40
+	m.maxRate = &maxRate
+	assert.Equal(t, expected[:len(expected)-1]+", MaxRate: 40}", fmt.Sprintf("%s", m))
+}
+
+func TestMetrics_Values(t *testing.T) {
+	vm := &vegeta.Metrics{
+		Latencies: vegeta.LatencyMetrics{
+			Total: time.Millisecond * 100,
+			Mean:  time.Millisecond * 10,
+			P50:   time.Millisecond * 5,
+			P90:   time.Millisecond * 15,
+			P95:   time.Millisecond * 20,
+			P99:   time.Millisecond * 30,
+			Max:   time.Millisecond * 40,
+			Min:   time.Millisecond * 2,
+		},
+		Duration:   5 * time.Second,
+		Requests:   100,
+		Rate:       25.0,
+		Throughput: 20.0,
+		Success:    0.95,
+	}
+
+	m := &Metrics{metrics: app.DefaultVegetaMetrics{
+		VegetaMetrics: vm,
+	}}
+
+	expected := map[string]interface{}{
+		"Requests":     uint64(100),
+		"Rate":         25.0,
+		"Throughput":   20.0,
+		"Duration":     5 * time.Second,
+		"Success":      0.95,
+		"LatencyTotal": 100 * time.Millisecond,
+		"LatencyMean":  10 * time.Millisecond,
+		"LatencyP50":   5 * time.Millisecond,
+		"LatencyP90":   15 * time.Millisecond,
+		"LatencyP95":   20 * time.Millisecond,
+		"LatencyP99":   30 * time.Millisecond,
+		"LatencyMax":   40 * time.Millisecond,
+		"LatencyMin":   2 * time.Millisecond,
+	}
+	assert.Equal(t, expected, m.Values())
+
+	maxRate := 40
+	m.maxRate = &maxRate
+	expected["MaxRate"] = 40
+	assert.Equal(t, expected, m.Values())
+}
+
+func TestMetrics_FindWindowed(t *testing.T) {
+	start := time.Now()
+	series := newTimeSeries(start, time.Second)
+	for i, latency := range []time.Duration{10, 30, 20} {
+		series.Add(&vegeta.Result{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Code:      200,
+			Latency:   latency * time.Millisecond,
+		})
+	}
+	series.Close()
+
+	tests := []struct {
+		name             string
+		series           *timeSeries
+		metricName       string
+		window           metrics.Window
+		aggregation      metrics.Aggregation
+		expectedMetric   metrics.Metric
+		expectedErrorMsg string
+	}{
+		{
+			name:           "latency max",
+			series:         series,
+			metricName:     "LatencyP99",
+			aggregation:    metrics.AggregationMax,
+			expectedMetric: metrics.GenericMetric[time.Duration]{Value: 30 * time.Millisecond},
+		},
+		{
+			name:           "latency last in window",
+			series:         series,
+			metricName:     "LatencyMean",
+			window:         metrics.Window{To: 2 * time.Second},
+			aggregation:    metrics.AggregationLast,
+			expectedMetric: metrics.GenericMetric[time.Duration]{Value: 30 * time.Millisecond},
+		},
+		{
+			name:           "rate avg",
+			series:         series,
+			metricName:     "Rate",
+			aggregation:    metrics.AggregationAvg,
+			expectedMetric: metrics.GenericMetric[float64]{Value: 1},
+		},
+		{
+			name:             "series not recorded",
+			metricName:       "Rate",
+			aggregation:      metrics.AggregationAvg,
+			expectedErrorMsg: "time series for metric Rate not recorded",
+		},
+		{
+			name:             "unsupported metric",
+			series:           series,
+			metricName:       "Duration",
+			aggregation:      metrics.AggregationAvg,
+			expectedErrorMsg: "metric Duration not found in time series",
+		},
+		{
+			name:             "empty window",
+			series:           series,
+			metricName:       "Success",
+			window:           metrics.Window{From: 5 * time.Second},
+			aggregation:      metrics.AggregationMin,
+			expectedErrorMsg: "no data for metric Success in time window",
+		},
+		{
+			name:             "invalid aggregation",
+			series:           series,
+			metricName:       "Success",
+			aggregation:      metrics.Aggregation("sum"),
+			expectedErrorMsg: "invalid aggregation sum",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Metrics{series: tt.series}
+			result, err := m.FindWindowed(tt.metricName, tt.window, tt.aggregation)
+			if tt.expectedErrorMsg != "" {
+				assert.EqualError(t, err, tt.expectedErrorMsg)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedMetric, result)
+			}
+		})
+	}
 }
@@ -0,0 +1,239 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bench
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+	"github.com/wstool/wst/conf/types"
+	"time"
+)
+
+type Profile string
+
+const (
+	ProfileConstant Profile = "constant"
+	ProfileRamp     Profile = "ramp"
+	ProfileStep     Profile = "step"
+	ProfileSine     Profile = "sine"
+	ProfileWorkers  Profile = "workers"
+	ProfileMaxRate  Profile = "max_rate"
+)
+
+// phase is a single attack with its own pacer. Named phases have their metrics stored separately.
+type phase struct {
+	name     string
+	duration time.Duration
+	pacer    vegeta.Pacer
+	// workers sets a fixed number of concurrent workers (closed model) if it is greater than zero.
+	workers uint64
+}
+
+// maxRateSearch increases the constant rate in steps until the success ratio drops below the threshold.
+type maxRateSearch struct {
+	startFrequency int
+	stepFrequency  int
+	endFrequency   int
+	stepDuration   time.Duration
+	threshold      float64
+}
+
+func attackProfile(attack *types.BenchAttack) Profile {
+	if attack.Profile == "" {
+		return ProfileConstant
+	}
+	return Profile(attack.Profile)
+}
+
+// buildPhase creates a phase from the attack configuration. The frequency is used if the attack does not set it.
+func buildPhase(name string, duration time.Duration, attack *types.BenchAttack, frequency int) (*phase, error) {
+	p := &phase{name: name, duration: duration}
+	if attack.Frequency > 0 {
+		frequency = attack.Frequency
+	}
+	profile := attackProfile(attack)
+	switch profile {
+	case ProfileConstant:
+		p.pacer = vegeta.Rate{Freq: frequency, Per: time.Second}
+	case ProfileRamp:
+		if attack.StartFrequency <= 0 || attack.EndFrequency <= 0 {
+			return nil, errors.New("ramp profile requires positive start_frequency and end_frequency")
+		}
+		if duration <= 0 {
+			return nil, errors.New("ramp profile requires positive duration")
+		}
+		p.pacer = vegeta.LinearPacer{
+			StartAt: vegeta.Rate{Freq: attack.StartFrequency, Per: time.Second},
+			Slope:   float64(attack.EndFrequency-attack.StartFrequency) / duration.Seconds(),
+		}
+	case ProfileStep:
+		if attack.StartFrequency <= 0 || attack.StepFrequency <= 0 || attack.StepDuration <= 0 {
+			return nil, errors.New("step profile requires positive start_frequency, step_frequency and step_duration")
+		}
+		p.pacer = &stepPacer{
+			startFrequency: attack.StartFrequency,
+			stepFrequency:  attack.StepFrequency,
+			endFrequency:   attack.EndFrequency,
+			stepDuration:   time.Duration(attack.StepDuration) * time.Millisecond,
+		}
+	case ProfileSine:
+		if frequency <= 0 || attack.Amplitude <= 0 || attack.Period <= 0 {
+			return nil, errors.New("sine profile requires positive frequency, amplitude and period")
+		}
+		if attack.Amplitude > frequency {
+			return nil, errors.Errorf("sine profile amplitude %d is greater than frequency %d",
+				attack.Amplitude, frequency)
+		}
+		p.pacer = vegeta.SinePacer{
+			Period:  time.Duration(attack.Period) * time.Millisecond,
+			Mean:    vegeta.Rate{Freq: frequency, Per: time.Second},
+			Amp:     vegeta.Rate{Freq: attack.Amplitude, Per: time.Second},
+			StartAt: vegeta.MeanUp,
+		}
+	case ProfileWorkers:
+		if attack.Workers <= 0 {
+			return nil, errors.New("workers profile requires positive workers")
+		}
+		// Zero frequency means that workers send requests as fast as possible.
+		p.pacer = vegeta.Rate{Freq: attack.Frequency, Per: time.Second}
+		p.workers = uint64(attack.Workers)
+	default:
+		return nil, errors.Errorf("invalid attack profile %s", profile)
+	}
+	return p, nil
+}
+
+// buildPhases creates named phases that are executed in sequence.
+func buildPhases(configs []types.BenchPhase, frequency int) ([]*phase, error) {
+	phases := make([]*phase, 0, len(configs))
+	names := make(map[string]bool, len(configs))
+	for i, config := range configs {
+		if config.Name == "" {
+			return nil, errors.Errorf("bench phase %d is missing name", i)
+		}
+		if names[config.Name] {
+			return nil, errors.Errorf("duplicate bench phase name %s", config.Name)
+		}
+		names[config.Name] = true
+		if config.Duration <= 0 {
+			return nil, errors.Errorf("bench phase %s requires positive duration", config.Name)
+		}
+		if attackProfile(&config.Attack) == ProfileMaxRate {
+			return nil, errors.Errorf("bench phase %s cannot use max_rate profile", config.Name)
+		}
+		duration := time.Duration(config.Duration) * time.Millisecond
+		p, err := buildPhase(config.Name, duration, &configs[i].Attack, frequency)
+		if err != nil {
+			return nil, errors.Errorf("bench phase %s: %v", config.Name, err)
+		}
+		phases = append(phases, p)
+	}
+	return phases, nil
+}
+
+// buildMaxRateSearch creates the maximal rate search from the attack configuration.
+func buildMaxRateSearch(attack *types.BenchAttack, duration time.Duration) (*maxRateSearch, error) {
+	if attack.StartFrequency <= 0 || attack.StepFrequency <= 0 || attack.StepDuration <= 0 {
+		return nil, errors.New("max_rate profile requires positive start_frequency, step_frequency and step_duration")
+	}
+	if attack.SuccessThreshold <= 0 || attack.SuccessThreshold > 1 {
+		return nil, errors.Errorf("max_rate profile success_threshold %v must be greater than 0 and at most 1",
+			attack.SuccessThreshold)
+	}
+	if attack.EndFrequency <= 0 && duration <= 0 {
+		return nil, errors.New("max_rate profile requires end_frequency or duration")
+	}
+	return &maxRateSearch{
+		startFrequency: attack.StartFrequency,
+		stepFrequency:  attack.StepFrequency,
+		endFrequency:   attack.EndFrequency,
+		stepDuration:   time.Duration(attack.StepDuration) * time.Millisecond,
+		threshold:      attack.SuccessThreshold,
+	}, nil
+}
+
+// phases returns the search steps that fit into the duration and do not exceed the end frequency.
+func (s *maxRateSearch) phases(duration time.Duration) []*phase {
+	var phases []*phase
+	for step := 1; ; step++ {
+		freq := s.startFrequency + (step-1)*s.stepFrequency
+		if s.endFrequency > 0 && freq > s.endFrequency {
+			break
+		}
+		if duration > 0 && time.Duration(step)*s.stepDuration > duration {
+			break
+		}
+		phases = append(phases, &phase{
+			name:     fmt.Sprintf("step_%d", step),
+			duration: s.stepDuration,
+			pacer:    vegeta.Rate{Freq: freq, Per: time.Second},
+		})
+	}
+	return phases
+}
+
+// stepPacer increases the rate by the step frequency after each step duration. The rate stops growing once it
+// reaches the end frequency if that is set.
+type stepPacer struct {
+	startFrequency int
+	stepFrequency  int
+	endFrequency   int
+	stepDuration   time.Duration
+}
+
+func (p *stepPacer) String() string {
+	return fmt.Sprintf("Step{%d hits/1s + %d hits/1s every %s}", p.startFrequency, p.stepFrequency, p.stepDuration)
+}
+
+// Pace determines the length of time to sleep until the next hit is sent.
+func (p *stepPacer) Pace(elapsed time.Duration, hits uint64) (time.Duration, bool) {
+	expectedHits := p.hits(elapsed)
+	if hits < uint64(expectedHits) {
+		// Running behind, send next hit immediately.
+		return 0, false
+	}
+	interval := 1e9 / p.Rate(elapsed)
+	delta := float64(hits+1) - expectedHits
+	return time.Duration(interval * delta), false
+}
+
+// Rate returns the hit rate per second at the given elapsed duration of an attack.
+func (p *stepPacer) Rate(elapsed time.Duration) float64 {
+	return float64(p.stepRate(int(elapsed / p.stepDuration)))
+}
+
+func (p *stepPacer) stepRate(step int) int {
+	rate := p.startFrequency + step*p.stepFrequency
+	if p.endFrequency > 0 && rate > p.endFrequency {
+		return p.endFrequency
+	}
+	return rate
+}
+
+// hits returns the number of hits that should have been sent during an attack lasting the elapsed duration.
+func (p *stepPacer) hits(elapsed time.Duration) float64 {
+	if elapsed < 0 {
+		return 0
+	}
+	steps := int(elapsed / p.stepDuration)
+	stepSeconds := p.stepDuration.Seconds()
+	hits := 0.0
+	for step := 0; step < steps; step++ {
+		hits += float64(p.stepRate(step)) * stepSeconds
+	}
+	remaining := elapsed - time.Duration(steps)*p.stepDuration
+	return hits + float64(p.stepRate(steps))*remaining.Seconds()
+}
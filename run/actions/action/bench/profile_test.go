@@ -0,0 +1,340 @@
+package bench
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+	"github.com/wstool/wst/conf/types"
+	"testing"
+	"time"
+)
+
+func Test_buildPhase(t *testing.T) {
+	tests := []struct {
+		name          string
+		duration      time.Duration
+		attack        *types.BenchAttack
+		frequency     int
+		expectedPhase *phase
+		expectedErr   string
+	}{
+		{
+			name:      "default constant profile",
+			duration:  time.Second,
+			attack:    &types.BenchAttack{},
+			frequency: 10,
+			expectedPhase: &phase{
+				name:     "test",
+				duration: time.Second,
+				pacer:    vegeta.Rate{Freq: 10, Per: time.Second},
+			},
+		},
+		{
+			name:      "constant profile with frequency",
+			duration:  time.Second,
+			attack:    &types.BenchAttack{Profile: "constant", Frequency: 20},
+			frequency: 10,
+			expectedPhase: &phase{
+				name:     "test",
+				duration: time.Second,
+				pacer:    vegeta.Rate{Freq: 20, Per: time.Second},
+			},
+		},
+		{
+			name:     "ramp profile",
+			duration: 10 * time.Second,
+			attack:   &types.BenchAttack{Profile: "ramp", StartFrequency: 10, EndFrequency: 110},
+			expectedPhase: &phase{
+				name:     "test",
+				duration: 10 * time.Second,
+				pacer: vegeta.LinearPacer{
+					StartAt: vegeta.Rate{Freq: 10, Per: time.Second},
+					Slope:   10,
+				},
+			},
+		},
+		{
+			name:        "ramp profile without end frequency",
+			duration:    10 * time.Second,
+			attack:      &types.BenchAttack{Profile: "ramp", StartFrequency: 10},
+			expectedErr: "ramp profile requires positive start_frequency and end_frequency",
+		},
+		{
+			name:        "ramp profile without duration",
+			attack:      &types.BenchAttack{Profile: "ramp", StartFrequency: 10, EndFrequency: 20},
+			expectedErr: "ramp profile requires positive duration",
+		},
+		{
+			name:     "step profile",
+			duration: 10 * time.Second,
+			attack: &types.BenchAttack{
+				Profile:        "step",
+				StartFrequency: 10,
+				StepFrequency:  5,
+				EndFrequency:   30,
+				StepDuration:   2000,
+			},
+			expectedPhase: &phase{
+				name:     "test",
+				duration: 10 * time.Second,
+				pacer: &stepPacer{
+					startFrequency: 10,
+					stepFrequency:  5,
+					endFrequency:   30,
+					stepDuration:   2 * time.Second,
+				},
+			},
+		},
+		{
+			name:        "step profile without step duration",
+			duration:    10 * time.Second,
+			attack:      &types.BenchAttack{Profile: "step", StartFrequency: 10, StepFrequency: 5},
+			expectedErr: "step profile requires positive start_frequency, step_frequency and step_duration",
+		},
+		{
+			name:      "sine profile",
+			duration:  10 * time.Second,
+			attack:    &types.BenchAttack{Profile: "sine", Amplitude: 5, Period: 4000},
+			frequency: 10,
+			expectedPhase: &phase{
+				name:     "test",
+				duration: 10 * time.Second,
+				pacer: vegeta.SinePacer{
+					Period:  4 * time.Second,
+					Mean:    vegeta.Rate{Freq: 10, Per: time.Second},
+					Amp:     vegeta.Rate{Freq: 5, Per: time.Second},
+					StartAt: vegeta.MeanUp,
+				},
+			},
+		},
+		{
+			name:        "sine profile without period",
+			duration:    10 * time.Second,
+			attack:      &types.BenchAttack{Profile: "sine", Amplitude: 5},
+			frequency:   10,
+			expectedErr: "sine profile requires positive frequency, amplitude and period",
+		},
+		{
+			name:        "sine profile with amplitude greater than frequency",
+			duration:    10 * time.Second,
+			attack:      &types.BenchAttack{Profile: "sine", Amplitude: 15, Period: 1000},
+			frequency:   10,
+			expectedErr: "sine profile amplitude 15 is greater than frequency 10",
+		},
+		{
+			name:      "workers profile",
+			duration:  time.Second,
+			attack:    &types.BenchAttack{Profile: "workers", Workers: 8},
+			frequency: 10,
+			expectedPhase: &phase{
+				name:     "test",
+				duration: time.Second,
+				pacer:    vegeta.Rate{Freq: 0, Per: time.Second},
+				workers:  8,
+			},
+		},
+		{
+			name:        "workers profile without workers",
+			duration:    time.Second,
+			attack:      &types.BenchAttack{Profile: "workers"},
+			expectedErr: "workers profile requires positive workers",
+		},
+		{
+			name:        "max rate profile",
+			duration:    time.Second,
+			attack:      &types.BenchAttack{Profile: "max_rate"},
+			expectedErr: "invalid attack profile max_rate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := buildPhase("test", tt.duration, tt.attack, tt.frequency)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, p)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedPhase, p)
+			}
+		})
+	}
+}
+
+func Test_buildPhases(t *testing.T) {
+	tests := []struct {
+		name           string
+		configs        []types.BenchPhase
+		expectedPhases []*phase
+		expectedErr    string
+	}{
+		{
+			name: "multiple phases",
+			configs: []types.BenchPhase{
+				{Name: "warmup", Duration: 1000, Attack: types.BenchAttack{Frequency: 5}},
+				{Name: "load", Duration: 2000},
+			},
+			expectedPhases: []*phase{
+				{name: "warmup", duration: time.Second, pacer: vegeta.Rate{Freq: 5, Per: time.Second}},
+				{name: "load", duration: 2 * time.Second, pacer: vegeta.Rate{Freq: 10, Per: time.Second}},
+			},
+		},
+		{
+			name:        "missing name",
+			configs:     []types.BenchPhase{{Duration: 1000}},
+			expectedErr: "bench phase 0 is missing name",
+		},
+		{
+			name: "duplicate name",
+			configs: []types.BenchPhase{
+				{Name: "load", Duration: 1000},
+				{Name: "load", Duration: 1000},
+			},
+			expectedErr: "duplicate bench phase name load",
+		},
+		{
+			name:        "missing duration",
+			configs:     []types.BenchPhase{{Name: "load"}},
+			expectedErr: "bench phase load requires positive duration",
+		},
+		{
+			name: "max rate profile",
+			configs: []types.BenchPhase{
+				{Name: "load", Duration: 1000, Attack: types.BenchAttack{Profile: "max_rate"}},
+			},
+			expectedErr: "bench phase load cannot use max_rate profile",
+		},
+		{
+			name: "invalid attack",
+			configs: []types.BenchPhase{
+				{Name: "load", Duration: 1000, Attack: types.BenchAttack{Profile: "workers"}},
+			},
+			expectedErr: "bench phase load: workers profile requires positive workers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phases, err := buildPhases(tt.configs, 10)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, phases)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedPhases, phases)
+			}
+		})
+	}
+}
+
+func Test_buildMaxRateSearch(t *testing.T) {
+	tests := []struct {
+		name           string
+		attack         *types.BenchAttack
+		duration       time.Duration
+		expectedSearch *maxRateSearch
+		expectedErr    string
+	}{
+		{
+			name: "valid search",
+			attack: &types.BenchAttack{
+				Profile:          "max_rate",
+				StartFrequency:   10,
+				StepFrequency:    10,
+				EndFrequency:     100,
+				StepDuration:     1000,
+				SuccessThreshold: 0.99,
+			},
+			expectedSearch: &maxRateSearch{
+				startFrequency: 10,
+				stepFrequency:  10,
+				endFrequency:   100,
+				stepDuration:   time.Second,
+				threshold:      0.99,
+			},
+		},
+		{
+			name:        "missing step frequency",
+			attack:      &types.BenchAttack{Profile: "max_rate", StartFrequency: 10, StepDuration: 1000},
+			expectedErr: "max_rate profile requires positive start_frequency, step_frequency and step_duration",
+		},
+		{
+			name: "invalid threshold",
+			attack: &types.BenchAttack{
+				Profile:          "max_rate",
+				StartFrequency:   10,
+				StepFrequency:    10,
+				StepDuration:     1000,
+				SuccessThreshold: 1.5,
+			},
+			duration:    10 * time.Second,
+			expectedErr: "max_rate profile success_threshold 1.5 must be greater than 0 and at most 1",
+		},
+		{
+			name: "unlimited search",
+			attack: &types.BenchAttack{
+				Profile:          "max_rate",
+				StartFrequency:   10,
+				StepFrequency:    10,
+				StepDuration:     1000,
+				SuccessThreshold: 0.9,
+			},
+			expectedErr: "max_rate profile requires end_frequency or duration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search, err := buildMaxRateSearch(tt.attack, tt.duration)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, search)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedSearch, search)
+			}
+		})
+	}
+}
+
+func Test_maxRateSearch_phases(t *testing.T) {
+	search := &maxRateSearch{startFrequency: 10, stepFrequency: 20, endFrequency: 50, stepDuration: time.Second}
+	assert.Equal(t, []*phase{
+		{name: "step_1", duration: time.Second, pacer: vegeta.Rate{Freq: 10, Per: time.Second}},
+		{name: "step_2", duration: time.Second, pacer: vegeta.Rate{Freq: 30, Per: time.Second}},
+		{name: "step_3", duration: time.Second, pacer: vegeta.Rate{Freq: 50, Per: time.Second}},
+	}, search.phases(0))
+
+	// The duration limits the number of steps.
+	assert.Len(t, search.phases(2500*time.Millisecond), 2)
+
+	search.endFrequency = 0
+	assert.Len(t, search.phases(5*time.Second), 5)
+}
+
+func Test_stepPacer(t *testing.T) {
+	p := &stepPacer{startFrequency: 10, stepFrequency: 10, endFrequency: 30, stepDuration: time.Second}
+
+	assert.Equal(t, 10.0, p.Rate(0))
+	assert.Equal(t, 10.0, p.Rate(999*time.Millisecond))
+	assert.Equal(t, 20.0, p.Rate(time.Second))
+	assert.Equal(t, 30.0, p.Rate(2*time.Second))
+	assert.Equal(t, 30.0, p.Rate(10*time.Second))
+
+	assert.Equal(t, 0.0, p.hits(0))
+	assert.InDelta(t, 5.0, p.hits(500*time.Millisecond), 1e-9)
+	assert.InDelta(t, 20.0, p.hits(1500*time.Millisecond), 1e-9)
+	assert.InDelta(t, 90.0, p.hits(4*time.Second), 1e-9)
+
+	// Running behind sends next hit immediately.
+	wait, stop := p.Pace(time.Second, 5)
+	assert.Equal(t, time.Duration(0), wait)
+	assert.False(t, stop)
+
+	// On schedule waits for the interval of the current step.
+	wait, stop = p.Pace(time.Second, 10)
+	assert.Equal(t, 50*time.Millisecond, wait)
+	assert.False(t, stop)
+
+	assert.Equal(t, "Step{10 hits/1s + 10 hits/1s every 1s}", p.String())
+}
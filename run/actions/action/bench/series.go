@@ -0,0 +1,123 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bench
+
+import (
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+	"github.com/wstool/wst/run/metrics"
+	"sync"
+	"time"
+)
+
+// defaultSeriesInterval is the default length of time series buckets.
+const defaultSeriesInterval = time.Second
+
+// timeSeries stores metrics of results in buckets of fixed interval relative to the attack start.
+type timeSeries struct {
+	mu       sync.Mutex
+	start    time.Time
+	interval time.Duration
+	buckets  []*vegeta.Metrics
+}
+
+func newTimeSeries(start time.Time, interval time.Duration) *timeSeries {
+	if interval <= 0 {
+		interval = defaultSeriesInterval
+	}
+	return &timeSeries{
+		start:    start,
+		interval: interval,
+	}
+}
+
+func (s *timeSeries) Add(res *vegeta.Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index := 0
+	if offset := res.Timestamp.Sub(s.start); offset > 0 {
+		index = int(offset / s.interval)
+	}
+	for len(s.buckets) <= index {
+		s.buckets = append(s.buckets, nil)
+	}
+	if s.buckets[index] == nil {
+		s.buckets[index] = &vegeta.Metrics{}
+	}
+	s.buckets[index].Add(res)
+}
+
+func (s *timeSeries) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, bucket := range s.buckets {
+		if bucket != nil {
+			bucket.Close()
+		}
+	}
+}
+
+// values returns the metric values of the buckets overlapping the window. Empty buckets are included only for
+// the metrics counting requests as their value is zero. It returns false if the metric is not supported.
+func (s *timeSeries) values(name string, window metrics.Window) ([]float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var values []float64
+	for i, bucket := range s.buckets {
+		if !window.Overlaps(time.Duration(i)*s.interval, s.interval) {
+			continue
+		}
+		value, counted, ok := s.bucketValue(name, bucket)
+		if !ok {
+			return nil, false
+		}
+		if bucket != nil || counted {
+			values = append(values, value)
+		}
+	}
+	return values, true
+}
+
+// bucketValue returns the metric value of the bucket and whether it is a metric counting requests.
+func (s *timeSeries) bucketValue(name string, bucket *vegeta.Metrics) (float64, bool, bool) {
+	if bucket == nil {
+		bucket = &vegeta.Metrics{}
+	}
+	switch name {
+	case "Requests":
+		return float64(bucket.Requests), true, true
+	case "Rate":
+		return float64(bucket.Requests) / s.interval.Seconds(), true, true
+	case "Throughput":
+		return float64(bucket.Requests) * bucket.Success / s.interval.Seconds(), true, true
+	case "Success":
+		return bucket.Success, false, true
+	case "LatencyMean":
+		return float64(bucket.Latencies.Mean), false, true
+	case "LatencyP50":
+		return float64(bucket.Latencies.P50), false, true
+	case "LatencyP90":
+		return float64(bucket.Latencies.P90), false, true
+	case "LatencyP95":
+		return float64(bucket.Latencies.P95), false, true
+	case "LatencyP99":
+		return float64(bucket.Latencies.P99), false, true
+	case "LatencyMax":
+		return float64(bucket.Latencies.Max), false, true
+	case "LatencyMin":
+		return float64(bucket.Latencies.Min), false, true
+	default:
+		return 0, false, false
+	}
+}
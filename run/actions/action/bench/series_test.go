@@ -0,0 +1,81 @@
+package bench
+
+import (
+	"github.com/stretchr/testify/assert"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+	"github.com/wstool/wst/run/metrics"
+	"testing"
+	"time"
+)
+
+func Test_timeSeries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := newTimeSeries(start, 0)
+	assert.Equal(t, time.Second, s.interval)
+
+	result := func(offset time.Duration, code uint16, latency time.Duration) *vegeta.Result {
+		return &vegeta.Result{Timestamp: start.Add(offset), Code: code, Latency: latency}
+	}
+	// Result before start is added to the first bucket.
+	s.Add(result(-time.Millisecond, 200, 10*time.Millisecond))
+	s.Add(result(100*time.Millisecond, 200, 20*time.Millisecond))
+	s.Add(result(1100*time.Millisecond, 200, 40*time.Millisecond))
+	s.Add(result(1200*time.Millisecond, 500, 80*time.Millisecond))
+	// The third bucket is empty.
+	s.Add(result(3500*time.Millisecond, 200, 5*time.Millisecond))
+	s.Close()
+
+	tests := []struct {
+		name     string
+		metric   string
+		window   metrics.Window
+		expected []float64
+		found    bool
+	}{
+		{
+			name:     "requests include empty buckets",
+			metric:   "Requests",
+			expected: []float64{2, 2, 0, 1},
+			found:    true,
+		},
+		{
+			name:     "rate in window",
+			metric:   "Rate",
+			window:   metrics.Window{From: time.Second, To: 3 * time.Second},
+			expected: []float64{2, 0},
+			found:    true,
+		},
+		{
+			name:     "throughput",
+			metric:   "Throughput",
+			window:   metrics.Window{To: 2 * time.Second},
+			expected: []float64{2, 1},
+			found:    true,
+		},
+		{
+			name:     "success skips empty buckets",
+			metric:   "Success",
+			expected: []float64{1, 0.5, 1},
+			found:    true,
+		},
+		{
+			name:     "latency max",
+			metric:   "LatencyMax",
+			window:   metrics.Window{From: time.Second},
+			expected: []float64{float64(80 * time.Millisecond), float64(5 * time.Millisecond)},
+			found:    true,
+		},
+		{
+			name:   "unsupported metric",
+			metric: "Duration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, found := s.values(tt.metric, tt.window)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.expected, values)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bench
+
+import (
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+	"sync"
+)
+
+// weightedTargeter distributes targets according to their weights using smooth weighted round-robin so the
+// targets are interleaved instead of being sent in bursts.
+type weightedTargeter struct {
+	mu      sync.Mutex
+	targets []vegeta.Target
+	weights []int
+	current []int
+	total   int
+}
+
+func newWeightedTargeter(targets []vegeta.Target, weights []int) vegeta.Targeter {
+	t := &weightedTargeter{
+		targets: targets,
+		weights: weights,
+		current: make([]int, len(targets)),
+	}
+	for _, weight := range weights {
+		t.total += weight
+	}
+	return t.next
+}
+
+func (t *weightedTargeter) next(tgt *vegeta.Target) error {
+	if tgt == nil {
+		return vegeta.ErrNilTarget
+	}
+	if len(t.targets) == 0 {
+		return vegeta.ErrNoTargets
+	}
+
+	t.mu.Lock()
+	selected := 0
+	for i, weight := range t.weights {
+		t.current[i] += weight
+		if t.current[i] > t.current[selected] {
+			selected = i
+		}
+	}
+	t.current[selected] -= t.total
+	t.mu.Unlock()
+
+	*tgt = t.targets[selected]
+	return nil
+}
@@ -0,0 +1,68 @@
+package bench
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+	"testing"
+)
+
+func TestWeightedTargeter(t *testing.T) {
+	tests := []struct {
+		name            string
+		targets         []vegeta.Target
+		weights         []int
+		expectedMethods []string
+	}{
+		{
+			name:            "single target",
+			targets:         []vegeta.Target{{Method: "GET", URL: "http://example.com"}},
+			weights:         []int{1},
+			expectedMethods: []string{"GET", "GET", "GET"},
+		},
+		{
+			name: "equal weights",
+			targets: []vegeta.Target{
+				{Method: "GET", URL: "http://example.com/a"},
+				{Method: "POST", URL: "http://example.com/b"},
+			},
+			weights:         []int{1, 1},
+			expectedMethods: []string{"GET", "POST", "GET", "POST"},
+		},
+		{
+			name: "smooth weighted distribution",
+			targets: []vegeta.Target{
+				{Method: "GET", URL: "http://example.com/a"},
+				{Method: "POST", URL: "http://example.com/b"},
+				{Method: "PUT", URL: "http://example.com/c"},
+			},
+			weights: []int{5, 1, 1},
+			expectedMethods: []string{
+				"GET", "GET", "POST", "GET", "PUT", "GET", "GET",
+				"GET", "GET", "POST", "GET", "PUT", "GET", "GET",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targeter := newWeightedTargeter(tt.targets, tt.weights)
+			var methods []string
+			for range tt.expectedMethods {
+				var tgt vegeta.Target
+				require.NoError(t, targeter(&tgt))
+				methods = append(methods, tgt.Method)
+			}
+			assert.Equal(t, tt.expectedMethods, methods)
+		})
+	}
+}
+
+func TestWeightedTargeter_Errors(t *testing.T) {
+	targeter := newWeightedTargeter([]vegeta.Target{{Method: "GET"}}, []int{1})
+	assert.Equal(t, vegeta.ErrNilTarget, targeter(nil))
+
+	targeter = newWeightedTargeter(nil, nil)
+	var tgt vegeta.Target
+	assert.Equal(t, vegeta.ErrNoTargets, targeter(&tgt))
+}
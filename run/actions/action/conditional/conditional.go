@@ -0,0 +1,185 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conditional
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/environments/environment/providers"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/services"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Maker interface {
+	Make(
+		config *types.IfAction,
+		sl services.ServiceLocator,
+		defaultTimeout int,
+		actionMaker action.Maker,
+	) (action.Action, error)
+}
+
+type ActionMaker struct {
+	fnd          app.Foundation
+	runtimeMaker runtime.Maker
+}
+
+func CreateActionMaker(fnd app.Foundation, runtimeMaker runtime.Maker) *ActionMaker {
+	return &ActionMaker{
+		fnd:          fnd,
+		runtimeMaker: runtimeMaker,
+	}
+}
+
+func (m *ActionMaker) makeBranch(
+	actions []types.Action,
+	sl services.ServiceLocator,
+	timeout int,
+	actionMaker action.Maker,
+) (action.Action, error) {
+	if len(actions) == 0 {
+		return nil, nil
+	}
+	return actionMaker.MakeAction(&types.SequentialAction{
+		Actions:   actions,
+		Timeout:   timeout,
+		When:      string(action.Always),
+		OnFailure: string(action.Fail),
+	}, sl, timeout)
+}
+
+func (m *ActionMaker) Make(
+	config *types.IfAction,
+	sl services.ServiceLocator,
+	defaultTimeout int,
+	actionMaker action.Maker,
+) (action.Action, error) {
+	if config.Condition == "" && config.Sandbox == "" {
+		return nil, errors.New("if action requires condition or sandbox to be set")
+	}
+	svc, err := sl.Find(config.Service)
+	if err != nil {
+		return nil, errors.Errorf("if action service not found: %v", err)
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	thenAction, err := m.makeBranch(config.Then, sl, config.Timeout, actionMaker)
+	if err != nil {
+		return nil, err
+	}
+	elseAction, err := m.makeBranch(config.Else, sl, config.Timeout, actionMaker)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Action{
+		fnd:          m.fnd,
+		runtimeMaker: m.runtimeMaker,
+		service:      svc,
+		condition:    config.Condition,
+		sandbox:      providers.Type(config.Sandbox),
+		thenAction:   thenAction,
+		elseAction:   elseAction,
+		timeout:      time.Duration(config.Timeout * 1e6),
+		when:         action.When(config.When),
+		onFailure:    action.OnFailureType(config.OnFailure),
+	}, nil
+}
+
+type Action struct {
+	fnd          app.Foundation
+	runtimeMaker runtime.Maker
+	service      services.Service
+	condition    string
+	sandbox      providers.Type
+	thenAction   action.Action
+	elseAction   action.Action
+	timeout      time.Duration
+	when         action.When
+	onFailure    action.OnFailureType
+}
+
+func (a *Action) When() action.When {
+	return a.when
+}
+
+func (a *Action) OnFailure() action.OnFailureType {
+	return a.onFailure
+}
+
+func (a *Action) Timeout() time.Duration {
+	return a.timeout
+}
+
+func (a *Action) evaluate(runData runtime.Data) (bool, error) {
+	if a.sandbox != "" && a.service.SandboxType() != a.sandbox {
+		return false, nil
+	}
+	if a.condition == "" {
+		return true, nil
+	}
+	params := make(parameters.Parameters).Inherit(runData.Parameters()).Inherit(a.service.ServerParameters())
+	rendered, err := a.service.RenderTemplate(a.condition, params)
+	if err != nil {
+		return false, err
+	}
+	rendered = strings.TrimSpace(rendered)
+	if rendered == "" {
+		return false, nil
+	}
+	result, err := strconv.ParseBool(rendered)
+	if err != nil {
+		return false, errors.Errorf("if action condition result %q is not a boolean value", rendered)
+	}
+	return result, nil
+}
+
+func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error) {
+	logger := a.fnd.Logger()
+	logger.Infof("Executing if action")
+
+	result, err := a.evaluate(runData)
+	if err != nil {
+		return false, err
+	}
+
+	branch := a.elseAction
+	if result {
+		branch = a.thenAction
+	}
+	logger.Debugf("If action condition evaluated to %t", result)
+	if branch == nil {
+		return true, nil
+	}
+
+	actCtx, cancel := a.runtimeMaker.MakeContextWithTimeout(ctx, branch.Timeout())
+	defer cancel()
+	success, err := branch.Execute(actCtx, runData)
+	if a.fnd.DryRun() {
+		return true, nil
+	}
+
+	return success, err
+}
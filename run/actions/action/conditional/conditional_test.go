@@ -0,0 +1,537 @@
+package conditional
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	actionMocks "github.com/wstool/wst/mocks/generated/run/actions/action"
+	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
+	parameterMocks "github.com/wstool/wst/mocks/generated/run/parameters/parameter"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/environments/environment/providers"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/services"
+	"testing"
+	"time"
+)
+
+func TestCreateActionMaker(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	runtimeMock := runtimeMocks.NewMockMaker(t)
+	tests := []struct {
+		name        string
+		fnd         app.Foundation
+		runtimeMock runtime.Maker
+	}{
+		{
+			name:        "create maker",
+			fnd:         fndMock,
+			runtimeMock: runtimeMock,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CreateActionMaker(tt.fnd, tt.runtimeMock)
+			assert.Equal(t, tt.fnd, got.fnd)
+			assert.Equal(t, tt.runtimeMock, got.runtimeMaker)
+		})
+	}
+}
+
+func TestActionMaker_Make(t *testing.T) {
+	thenActions := []types.Action{&types.RequestAction{Service: "s1"}}
+	elseActions := []types.Action{&types.StopAction{Service: "s1"}}
+	branchConfig := func(actions []types.Action, timeout int) *types.SequentialAction {
+		return &types.SequentialAction{
+			Actions:   actions,
+			Timeout:   timeout,
+			When:      "always",
+			OnFailure: "fail",
+		}
+	}
+	tests := []struct {
+		name              string
+		config            *types.IfAction
+		defaultTimeout    int
+		setupMocks        func(*testing.T, *servicesMocks.MockServiceLocator, *actionMocks.MockMaker) (services.Service, action.Action, action.Action)
+		expectedTimeout   time.Duration
+		expectedWhen      action.When
+		expectedOnFailure action.OnFailureType
+		expectError       bool
+		expectedErrorMsg  string
+	}{
+		{
+			name: "successful action creation with both branches",
+			config: &types.IfAction{
+				Service:   "svc",
+				Condition: "{{ .Parameters.GetString \"tls_enabled\" }}",
+				Then:      thenActions,
+				Else:      elseActions,
+				Timeout:   3000,
+				When:      "on_success",
+				OnFailure: "skip",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				sl *servicesMocks.MockServiceLocator,
+				am *actionMocks.MockMaker,
+			) (services.Service, action.Action, action.Action) {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "svc").Return(svc, nil)
+				thenAction := actionMocks.NewMockAction(t)
+				elseAction := actionMocks.NewMockAction(t)
+				am.On("MakeAction", branchConfig(thenActions, 3000), sl, 3000).Return(thenAction, nil)
+				am.On("MakeAction", branchConfig(elseActions, 3000), sl, 3000).Return(elseAction, nil)
+				return svc, thenAction, elseAction
+			},
+			expectedTimeout:   time.Duration(3000 * 1e6),
+			expectedWhen:      action.OnSuccess,
+			expectedOnFailure: action.Skip,
+		},
+		{
+			name: "successful action creation with sandbox and no else branch",
+			config: &types.IfAction{
+				Service:   "svc",
+				Sandbox:   "docker",
+				Then:      thenActions,
+				When:      "always",
+				OnFailure: "fail",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				sl *servicesMocks.MockServiceLocator,
+				am *actionMocks.MockMaker,
+			) (services.Service, action.Action, action.Action) {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "svc").Return(svc, nil)
+				thenAction := actionMocks.NewMockAction(t)
+				am.On("MakeAction", branchConfig(thenActions, 5000), sl, 5000).Return(thenAction, nil)
+				return svc, thenAction, nil
+			},
+			expectedTimeout:   time.Duration(5000 * 1e6),
+			expectedWhen:      action.Always,
+			expectedOnFailure: action.Fail,
+		},
+		{
+			name: "failed action creation due to then branch error",
+			config: &types.IfAction{
+				Service:   "svc",
+				Condition: "true",
+				Then:      thenActions,
+				Else:      elseActions,
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				sl *servicesMocks.MockServiceLocator,
+				am *actionMocks.MockMaker,
+			) (services.Service, action.Action, action.Action) {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "svc").Return(svc, nil)
+				am.On("MakeAction", branchConfig(thenActions, 5000), sl, 5000).Return(nil, errors.New("then failed"))
+				return nil, nil, nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "then failed",
+		},
+		{
+			name: "failed action creation due to else branch error",
+			config: &types.IfAction{
+				Service:   "svc",
+				Condition: "true",
+				Else:      elseActions,
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				sl *servicesMocks.MockServiceLocator,
+				am *actionMocks.MockMaker,
+			) (services.Service, action.Action, action.Action) {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "svc").Return(svc, nil)
+				am.On("MakeAction", branchConfig(elseActions, 5000), sl, 5000).Return(nil, errors.New("else failed"))
+				return nil, nil, nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "else failed",
+		},
+		{
+			name: "failed action creation due to service not found",
+			config: &types.IfAction{
+				Service:   "svc",
+				Condition: "true",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				sl *servicesMocks.MockServiceLocator,
+				am *actionMocks.MockMaker,
+			) (services.Service, action.Action, action.Action) {
+				sl.On("Find", "svc").Return(nil, errors.New("not found"))
+				return nil, nil, nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "if action service not found: not found",
+		},
+		{
+			name: "failed action creation due to missing condition",
+			config: &types.IfAction{
+				Service: "svc",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				sl *servicesMocks.MockServiceLocator,
+				am *actionMocks.MockMaker,
+			) (services.Service, action.Action, action.Action) {
+				return nil, nil, nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "if action requires condition or sandbox to be set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			runtimeMakerMock := runtimeMocks.NewMockMaker(t)
+			m := &ActionMaker{
+				fnd:          fndMock,
+				runtimeMaker: runtimeMakerMock,
+			}
+
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			amMock := actionMocks.NewMockMaker(t)
+
+			svc, thenAction, elseAction := tt.setupMocks(t, slMock, amMock)
+
+			got, err := m.Make(tt.config, slMock, tt.defaultTimeout, amMock)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				act, ok := got.(*Action)
+				assert.True(t, ok)
+				assert.Equal(t, fndMock, act.fnd)
+				assert.Equal(t, runtimeMakerMock, act.runtimeMaker)
+				assert.Equal(t, svc, act.service)
+				assert.Equal(t, tt.config.Condition, act.condition)
+				assert.Equal(t, providers.Type(tt.config.Sandbox), act.sandbox)
+				assert.Equal(t, thenAction, act.thenAction)
+				assert.Equal(t, elseAction, act.elseAction)
+				assert.Equal(t, tt.expectedTimeout, act.Timeout())
+				assert.Equal(t, tt.expectedWhen, act.When())
+				assert.Equal(t, tt.expectedOnFailure, act.OnFailure())
+			}
+		})
+	}
+}
+
+func TestAction_Execute(t *testing.T) {
+	runtimeParams := parameters.Parameters{"iteration": parameterMocks.NewMockParameter(t)}
+	serverParams := parameters.Parameters{"tls_enabled": parameterMocks.NewMockParameter(t)}
+	mergedParams := parameters.Parameters{
+		"iteration":   runtimeParams["iteration"],
+		"tls_enabled": serverParams["tls_enabled"],
+	}
+	tests := []struct {
+		name       string
+		condition  string
+		sandbox    providers.Type
+		hasThen    bool
+		hasElse    bool
+		setupMocks func(
+			*testing.T,
+			*appMocks.MockFoundation,
+			*servicesMocks.MockService,
+			*runtimeMocks.MockData,
+			*actionMocks.MockAction,
+			*actionMocks.MockAction,
+			context.Context,
+		)
+		want        bool
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:      "condition true executes then branch",
+			condition: "{{ .Parameters.GetString \"tls_enabled\" }}",
+			hasThen:   true,
+			hasElse:   true,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+				rd *runtimeMocks.MockData,
+				thenAction *actionMocks.MockAction,
+				elseAction *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				rd.On("Parameters").Return(runtimeParams)
+				svc.On("ServerParameters").Return(serverParams)
+				svc.On("RenderTemplate", "{{ .Parameters.GetString \"tls_enabled\" }}", mergedParams).Return(" true\n", nil)
+				thenAction.On("Execute", ctx, rd).Return(true, nil)
+				fnd.On("DryRun").Return(false)
+			},
+			want: true,
+		},
+		{
+			name:      "condition false executes else branch",
+			condition: "{{ .Parameters.GetString \"tls_enabled\" }}",
+			hasThen:   true,
+			hasElse:   true,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+				rd *runtimeMocks.MockData,
+				thenAction *actionMocks.MockAction,
+				elseAction *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				rd.On("Parameters").Return(runtimeParams)
+				svc.On("ServerParameters").Return(serverParams)
+				svc.On("RenderTemplate", "{{ .Parameters.GetString \"tls_enabled\" }}", mergedParams).Return("false", nil)
+				elseAction.On("Execute", ctx, rd).Return(false, nil)
+				fnd.On("DryRun").Return(false)
+			},
+			want: false,
+		},
+		{
+			name:      "empty condition result without else branch",
+			condition: "{{ if .Parameters.GetString \"tls_enabled\" }}{{ end }}",
+			hasThen:   true,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+				rd *runtimeMocks.MockData,
+				thenAction *actionMocks.MockAction,
+				elseAction *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				rd.On("Parameters").Return(runtimeParams)
+				svc.On("ServerParameters").Return(serverParams)
+				svc.On("RenderTemplate", "{{ if .Parameters.GetString \"tls_enabled\" }}{{ end }}", mergedParams).Return("", nil)
+			},
+			want: true,
+		},
+		{
+			name:    "sandbox matches executes then branch",
+			sandbox: providers.DockerType,
+			hasThen: true,
+			hasElse: true,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+				rd *runtimeMocks.MockData,
+				thenAction *actionMocks.MockAction,
+				elseAction *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				svc.On("SandboxType").Return(providers.DockerType)
+				thenAction.On("Execute", ctx, rd).Return(true, nil)
+				fnd.On("DryRun").Return(false)
+			},
+			want: true,
+		},
+		{
+			name:      "sandbox does not match skips condition and executes else branch",
+			condition: "true",
+			sandbox:   providers.DockerType,
+			hasThen:   true,
+			hasElse:   true,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+				rd *runtimeMocks.MockData,
+				thenAction *actionMocks.MockAction,
+				elseAction *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				svc.On("SandboxType").Return(providers.LocalType)
+				elseAction.On("Execute", ctx, rd).Return(true, nil)
+				fnd.On("DryRun").Return(false)
+			},
+			want: true,
+		},
+		{
+			name:      "branch failure in dry run",
+			condition: "1",
+			hasThen:   true,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+				rd *runtimeMocks.MockData,
+				thenAction *actionMocks.MockAction,
+				elseAction *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				rd.On("Parameters").Return(parameters.Parameters{})
+				svc.On("ServerParameters").Return(parameters.Parameters{})
+				svc.On("RenderTemplate", "1", parameters.Parameters{}).Return("1", nil)
+				thenAction.On("Execute", ctx, rd).Return(false, nil)
+				fnd.On("DryRun").Return(true)
+			},
+			want: true,
+		},
+		{
+			name:      "branch error",
+			condition: "true",
+			hasThen:   true,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+				rd *runtimeMocks.MockData,
+				thenAction *actionMocks.MockAction,
+				elseAction *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				rd.On("Parameters").Return(parameters.Parameters{})
+				svc.On("ServerParameters").Return(parameters.Parameters{})
+				svc.On("RenderTemplate", "true", parameters.Parameters{}).Return("true", nil)
+				thenAction.On("Execute", ctx, rd).Return(false, errors.New("branch failed"))
+				fnd.On("DryRun").Return(false)
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "branch failed",
+		},
+		{
+			name:      "condition rendering error",
+			condition: "{{ .Bad }}",
+			hasThen:   true,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+				rd *runtimeMocks.MockData,
+				thenAction *actionMocks.MockAction,
+				elseAction *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				rd.On("Parameters").Return(parameters.Parameters{})
+				svc.On("ServerParameters").Return(parameters.Parameters{})
+				svc.On("RenderTemplate", "{{ .Bad }}", parameters.Parameters{}).Return("", errors.New("render failed"))
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "render failed",
+		},
+		{
+			name:      "condition result is not boolean",
+			condition: "yes please",
+			hasThen:   true,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+				rd *runtimeMocks.MockData,
+				thenAction *actionMocks.MockAction,
+				elseAction *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				rd.On("Parameters").Return(parameters.Parameters{})
+				svc.On("ServerParameters").Return(parameters.Parameters{})
+				svc.On("RenderTemplate", "yes please", parameters.Parameters{}).Return("yes please", nil)
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "if action condition result \"yes please\" is not a boolean value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			runMakerMock := runtimeMocks.NewMockMaker(t)
+			runDataMock := runtimeMocks.NewMockData(t)
+			svcMock := servicesMocks.NewMockService(t)
+			thenMock := actionMocks.NewMockAction(t)
+			elseMock := actionMocks.NewMockAction(t)
+
+			baseCtx, baseCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer baseCancel()
+			actCtx, actCancel := context.WithTimeout(baseCtx, 3*time.Second)
+			defer actCancel()
+			timeout := 3 * time.Second
+
+			cancel := context.CancelFunc(func() {})
+			runMakerMock.On("MakeContextWithTimeout", baseCtx, timeout).Return(actCtx, cancel).Maybe()
+			thenMock.On("Timeout").Return(timeout).Maybe()
+			elseMock.On("Timeout").Return(timeout).Maybe()
+
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+
+			tt.setupMocks(t, fndMock, svcMock, runDataMock, thenMock, elseMock, actCtx)
+
+			a := &Action{
+				fnd:          fndMock,
+				runtimeMaker: runMakerMock,
+				service:      svcMock,
+				condition:    tt.condition,
+				sandbox:      tt.sandbox,
+			}
+			if tt.hasThen {
+				a.thenAction = thenMock
+			}
+			if tt.hasElse {
+				a.elseAction = elseMock
+			}
+
+			got, err := a.Execute(baseCtx, runDataMock)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAction_Timeout(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:     fndMock,
+		timeout: 2000 * time.Millisecond,
+	}
+	assert.Equal(t, 2000*time.Millisecond, a.Timeout())
+}
+
+func TestAction_OnFailure(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:       fndMock,
+		when:      action.OnSuccess,
+		onFailure: action.Skip,
+	}
+	assert.Equal(t, action.Skip, a.OnFailure())
+}
+
+func TestAction_When(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:  fndMock,
+		when: action.OnSuccess,
+	}
+	assert.Equal(t, action.OnSuccess, a.When())
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventually
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/services"
+	"time"
+)
+
+// defaultAttemptTimeoutDivisor divides the action timeout to get the default attempt timeout so a blocked attempt
+// does not use all the time and leaves space for retries.
+const defaultAttemptTimeoutDivisor = 5
+
+type Maker interface {
+	Make(
+		config *types.EventuallyAction,
+		sl services.ServiceLocator,
+		defaultTimeout int,
+		actionMaker action.Maker,
+	) (action.Action, error)
+}
+
+type ActionMaker struct {
+	fnd          app.Foundation
+	runtimeMaker runtime.Maker
+}
+
+func CreateActionMaker(fnd app.Foundation, runtimeMaker runtime.Maker) *ActionMaker {
+	return &ActionMaker{
+		fnd:          fnd,
+		runtimeMaker: runtimeMaker,
+	}
+}
+
+func (m *ActionMaker) Make(
+	config *types.EventuallyAction,
+	sl services.ServiceLocator,
+	defaultTimeout int,
+	actionMaker action.Maker,
+) (action.Action, error) {
+	if config.Interval < 0 || config.MaxInterval < 0 || config.AttemptTimeout < 0 {
+		return nil, errors.New("eventually action interval, max interval and attempt timeout cannot be negative")
+	}
+	if config.Backoff != 0 && config.Backoff < 1 {
+		return nil, errors.New("eventually action backoff must be greater or equal to 1")
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+	attemptTimeout := config.AttemptTimeout
+	if attemptTimeout == 0 {
+		attemptTimeout = config.Timeout / defaultAttemptTimeoutDivisor
+		if attemptTimeout <= 0 {
+			// The action timeout is unlimited (or too small) so the attempt is limited by the default timeout.
+			attemptTimeout = defaultTimeout
+		}
+	}
+	backoff := config.Backoff
+	if backoff == 0 {
+		backoff = 1
+	}
+
+	// The attempt is run as a single sequential action so the inner actions keep the usual when logic.
+	attempt, err := actionMaker.MakeAction(&types.SequentialAction{
+		Actions:   config.Actions,
+		Timeout:   attemptTimeout,
+		When:      string(action.Always),
+		OnFailure: string(action.Fail),
+	}, sl, attemptTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Action{
+		fnd:          m.fnd,
+		runtimeMaker: m.runtimeMaker,
+		attempt:      attempt,
+		interval:     time.Duration(config.Interval * 1e6),
+		backoff:      backoff,
+		maxInterval:  time.Duration(config.MaxInterval * 1e6),
+		timeout:      time.Duration(config.Timeout * 1e6),
+		when:         action.When(config.When),
+		onFailure:    action.OnFailureType(config.OnFailure),
+	}, nil
+}
+
+type Action struct {
+	fnd          app.Foundation
+	runtimeMaker runtime.Maker
+	attempt      action.Action
+	interval     time.Duration
+	backoff      float64
+	maxInterval  time.Duration
+	timeout      time.Duration
+	when         action.When
+	onFailure    action.OnFailureType
+}
+
+func (a *Action) When() action.When {
+	return a.when
+}
+
+func (a *Action) OnFailure() action.OnFailureType {
+	return a.onFailure
+}
+
+func (a *Action) Timeout() time.Duration {
+	return a.timeout
+}
+
+func (a *Action) nextInterval(interval time.Duration) time.Duration {
+	next := time.Duration(float64(interval) * a.backoff)
+	if a.maxInterval > 0 && next > a.maxInterval {
+		return a.maxInterval
+	}
+	return next
+}
+
+// Execute retries the attempt until it succeeds or the action times out. If the last attempt failed with an error or
+// timed out, the error with the reason is returned so the failure is not reported without the cause.
+func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error) {
+	logger := a.fnd.Logger()
+	logger.Infof("Executing eventually action")
+
+	var failures []string
+	var lastErr error
+	interval := a.interval
+	for attempt := 1; ; attempt++ {
+		logger.Debugf("Executing eventually action attempt %d", attempt)
+		actCtx, cancel := a.runtimeMaker.MakeContextWithTimeout(ctx, a.attempt.Timeout())
+		success, err := a.attempt.Execute(actCtx, runData)
+		timedOut := errors.Is(actCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+		cancel()
+
+		if success && err == nil {
+			if attempt > 1 {
+				logger.Debugf("Eventually action succeeded on attempt %d", attempt)
+			}
+			return true, nil
+		}
+		if a.fnd.DryRun() {
+			return true, nil
+		}
+
+		switch {
+		case err != nil:
+			lastErr = errors.Errorf("attempt %d failed with error: %v", attempt, err)
+		case timedOut:
+			lastErr = errors.Errorf("attempt %d timed out after %s", attempt, a.attempt.Timeout())
+		default:
+			lastErr = nil
+		}
+		if lastErr != nil {
+			failures = append(failures, lastErr.Error())
+		} else {
+			failures = append(failures, fmt.Sprintf("attempt %d failed", attempt))
+		}
+
+		if ctx.Err() != nil || a.fnd.Sleep(ctx, interval) != nil {
+			break
+		}
+		interval = a.nextInterval(interval)
+	}
+
+	logger.Infof("Eventually action gave up after %d attempts", len(failures))
+	for _, failure := range failures {
+		logger.Infof("Eventually action %s", failure)
+	}
+	if lastErr != nil {
+		return false, errors.Errorf("eventually action gave up after %d attempts, last %v", len(failures), lastErr)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,395 @@
+package eventually
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	actionMocks "github.com/wstool/wst/mocks/generated/run/actions/action"
+	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/instances/runtime"
+	"testing"
+	"time"
+)
+
+func TestCreateActionMaker(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	runtimeMock := runtimeMocks.NewMockMaker(t)
+	tests := []struct {
+		name        string
+		fnd         app.Foundation
+		runtimeMock runtime.Maker
+	}{
+		{
+			name:        "create maker",
+			fnd:         fndMock,
+			runtimeMock: runtimeMock,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CreateActionMaker(tt.fnd, tt.runtimeMock)
+			assert.Equal(t, tt.fnd, got.fnd)
+			assert.Equal(t, tt.runtimeMock, got.runtimeMaker)
+		})
+	}
+}
+
+func TestActionMaker_Make(t *testing.T) {
+	actions := []types.Action{
+		&types.RequestAction{Service: "s1"},
+		&types.ResponseExpectationAction{Service: "s1"},
+	}
+	tests := []struct {
+		name                string
+		config              *types.EventuallyAction
+		defaultTimeout      int
+		attemptTimeout      int
+		actionMakerErr      error
+		expectedTimeout     time.Duration
+		expectedInterval    time.Duration
+		expectedBackoff     float64
+		expectedMaxInterval time.Duration
+		expectedWhen        action.When
+		expectedOnFailure   action.OnFailureType
+		expectError         bool
+		expectedErrorMsg    string
+	}{
+		{
+			name: "successful action creation with all settings",
+			config: &types.EventuallyAction{
+				Actions:        actions,
+				Interval:       100,
+				Backoff:        2,
+				MaxInterval:    1000,
+				AttemptTimeout: 500,
+				Timeout:        10000,
+				When:           "on_success",
+				OnFailure:      "skip",
+			},
+			defaultTimeout:      5000,
+			attemptTimeout:      500,
+			expectedTimeout:     time.Duration(10000 * 1e6),
+			expectedInterval:    time.Duration(100 * 1e6),
+			expectedBackoff:     2,
+			expectedMaxInterval: time.Duration(1000 * 1e6),
+			expectedWhen:        action.OnSuccess,
+			expectedOnFailure:   action.Skip,
+		},
+		{
+			name: "successful action creation with defaults",
+			config: &types.EventuallyAction{
+				Actions:   actions,
+				Interval:  500,
+				When:      "always",
+				OnFailure: "fail",
+			},
+			defaultTimeout:    5000,
+			attemptTimeout:    1000,
+			expectedTimeout:   time.Duration(5000 * 1e6),
+			expectedInterval:  time.Duration(500 * 1e6),
+			expectedBackoff:   1,
+			expectedWhen:      action.Always,
+			expectedOnFailure: action.Fail,
+		},
+		{
+			name: "successful action creation with unlimited timeout",
+			config: &types.EventuallyAction{
+				Actions:   actions,
+				Interval:  500,
+				Timeout:   -1,
+				When:      "always",
+				OnFailure: "fail",
+			},
+			defaultTimeout:    5000,
+			attemptTimeout:    5000,
+			expectedTimeout:   time.Duration(-1 * 1e6),
+			expectedInterval:  time.Duration(500 * 1e6),
+			expectedBackoff:   1,
+			expectedWhen:      action.Always,
+			expectedOnFailure: action.Fail,
+		},
+		{
+			name: "failed action creation with action maker error",
+			config: &types.EventuallyAction{
+				Actions: actions,
+			},
+			defaultTimeout:   5000,
+			attemptTimeout:   1000,
+			actionMakerErr:   errors.New("action creation failed"),
+			expectError:      true,
+			expectedErrorMsg: "action creation failed",
+		},
+		{
+			name: "failed action creation with negative interval",
+			config: &types.EventuallyAction{
+				Actions:  actions,
+				Interval: -1,
+			},
+			defaultTimeout:   5000,
+			expectError:      true,
+			expectedErrorMsg: "eventually action interval, max interval and attempt timeout cannot be negative",
+		},
+		{
+			name: "failed action creation with invalid backoff",
+			config: &types.EventuallyAction{
+				Actions: actions,
+				Backoff: 0.5,
+			},
+			defaultTimeout:   5000,
+			expectError:      true,
+			expectedErrorMsg: "eventually action backoff must be greater or equal to 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			runtimeMakerMock := runtimeMocks.NewMockMaker(t)
+			m := &ActionMaker{
+				fnd:          fndMock,
+				runtimeMaker: runtimeMakerMock,
+			}
+
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			amMock := actionMocks.NewMockMaker(t)
+			attemptMock := actionMocks.NewMockAction(t)
+
+			if tt.attemptTimeout > 0 {
+				attemptConfig := &types.SequentialAction{
+					Actions:   actions,
+					Timeout:   tt.attemptTimeout,
+					When:      "always",
+					OnFailure: "fail",
+				}
+				if tt.actionMakerErr != nil {
+					amMock.On("MakeAction", attemptConfig, slMock, tt.attemptTimeout).Return(nil, tt.actionMakerErr)
+				} else {
+					amMock.On("MakeAction", attemptConfig, slMock, tt.attemptTimeout).Return(attemptMock, nil)
+				}
+			}
+
+			got, err := m.Make(tt.config, slMock, tt.defaultTimeout, amMock)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				act, ok := got.(*Action)
+				assert.True(t, ok)
+				assert.Equal(t, fndMock, act.fnd)
+				assert.Equal(t, runtimeMakerMock, act.runtimeMaker)
+				assert.Equal(t, attemptMock, act.attempt)
+				assert.Equal(t, tt.expectedInterval, act.interval)
+				assert.Equal(t, tt.expectedBackoff, act.backoff)
+				assert.Equal(t, tt.expectedMaxInterval, act.maxInterval)
+				assert.Equal(t, tt.expectedTimeout, act.Timeout())
+				assert.Equal(t, tt.expectedWhen, act.When())
+				assert.Equal(t, tt.expectedOnFailure, act.OnFailure())
+			}
+		})
+	}
+}
+
+func TestAction_Execute(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMocks func(
+			*testing.T,
+			*appMocks.MockFoundation,
+			*actionMocks.MockAction,
+			*runtimeMocks.MockData,
+			context.Context,
+			context.Context,
+		)
+		attemptTimedOut  bool
+		want             bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "successful first attempt",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				attempt *actionMocks.MockAction,
+				rd *runtimeMocks.MockData,
+				ctx context.Context,
+				actCtx context.Context,
+			) {
+				attempt.On("Execute", actCtx, rd).Return(true, nil).Once()
+			},
+			want: true,
+		},
+		{
+			name: "successful after failed attempts with backoff",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				attempt *actionMocks.MockAction,
+				rd *runtimeMocks.MockData,
+				ctx context.Context,
+				actCtx context.Context,
+			) {
+				attempt.On("Execute", actCtx, rd).Return(false, errors.New("connection refused")).Once()
+				attempt.On("Execute", actCtx, rd).Return(false, nil).Once()
+				attempt.On("Execute", actCtx, rd).Return(false, nil).Once()
+				attempt.On("Execute", actCtx, rd).Return(true, nil).Once()
+				fnd.On("DryRun").Return(false).Times(3)
+				fnd.On("Sleep", ctx, 100*time.Millisecond).Return(nil).Once()
+				fnd.On("Sleep", ctx, 200*time.Millisecond).Return(nil).Once()
+				fnd.On("Sleep", ctx, 300*time.Millisecond).Return(nil).Once()
+			},
+			want: true,
+		},
+		{
+			name: "gives up when timeout expires",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				attempt *actionMocks.MockAction,
+				rd *runtimeMocks.MockData,
+				ctx context.Context,
+				actCtx context.Context,
+			) {
+				attempt.On("Execute", actCtx, rd).Return(false, nil).Once()
+				attempt.On("Execute", actCtx, rd).Return(false, errors.New("connection refused")).Once()
+				fnd.On("DryRun").Return(false).Times(2)
+				fnd.On("Sleep", ctx, 100*time.Millisecond).Return(nil).Once()
+				fnd.On("Sleep", ctx, 200*time.Millisecond).Return(context.DeadlineExceeded).Once()
+			},
+			want:             false,
+			expectedErrorMsg: "eventually action gave up after 2 attempts, last attempt 2 failed with error: connection refused",
+		},
+		{
+			name: "gives up when last attempt fails without error",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				attempt *actionMocks.MockAction,
+				rd *runtimeMocks.MockData,
+				ctx context.Context,
+				actCtx context.Context,
+			) {
+				attempt.On("Execute", actCtx, rd).Return(false, errors.New("connection refused")).Once()
+				attempt.On("Execute", actCtx, rd).Return(false, nil).Once()
+				fnd.On("DryRun").Return(false).Times(2)
+				fnd.On("Sleep", ctx, 100*time.Millisecond).Return(nil).Once()
+				fnd.On("Sleep", ctx, 200*time.Millisecond).Return(context.DeadlineExceeded).Once()
+			},
+			want: false,
+		},
+		{
+			name: "gives up when last attempt times out",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				attempt *actionMocks.MockAction,
+				rd *runtimeMocks.MockData,
+				ctx context.Context,
+				actCtx context.Context,
+			) {
+				attempt.On("Execute", actCtx, rd).Return(false, nil).Once()
+				fnd.On("DryRun").Return(false).Once()
+				fnd.On("Sleep", ctx, 100*time.Millisecond).Return(context.DeadlineExceeded).Once()
+			},
+			attemptTimedOut:  true,
+			want:             false,
+			expectedErrorMsg: "eventually action gave up after 1 attempts, last attempt 1 timed out after 3s",
+		},
+		{
+			name: "failed attempt in dry run",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				attempt *actionMocks.MockAction,
+				rd *runtimeMocks.MockData,
+				ctx context.Context,
+				actCtx context.Context,
+			) {
+				attempt.On("Execute", actCtx, rd).Return(false, nil).Once()
+				fnd.On("DryRun").Return(true).Once()
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			runMakerMock := runtimeMocks.NewMockMaker(t)
+			runDataMock := runtimeMocks.NewMockData(t)
+			attemptMock := actionMocks.NewMockAction(t)
+
+			baseCtx, baseCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer baseCancel()
+			actCtx, actCancel := context.WithTimeout(baseCtx, 3*time.Second)
+			if tt.attemptTimedOut {
+				actCtx, actCancel = context.WithDeadline(baseCtx, time.Now().Add(-time.Second))
+			}
+			defer actCancel()
+			timeout := 3 * time.Second
+
+			cancel := context.CancelFunc(func() {})
+			runMakerMock.On("MakeContextWithTimeout", baseCtx, timeout).Return(actCtx, cancel)
+			attemptMock.On("Timeout").Return(timeout)
+
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+
+			tt.setupMocks(t, fndMock, attemptMock, runDataMock, baseCtx, actCtx)
+
+			a := &Action{
+				fnd:          fndMock,
+				runtimeMaker: runMakerMock,
+				attempt:      attemptMock,
+				interval:     100 * time.Millisecond,
+				backoff:      2,
+				maxInterval:  300 * time.Millisecond,
+			}
+
+			got, err := a.Execute(baseCtx, runDataMock)
+
+			if tt.expectedErrorMsg != "" {
+				assert.EqualError(t, err, tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAction_Timeout(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:     fndMock,
+		timeout: 2000 * time.Millisecond,
+	}
+	assert.Equal(t, 2000*time.Millisecond, a.Timeout())
+}
+
+func TestAction_OnFailure(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:       fndMock,
+		when:      action.OnSuccess,
+		onFailure: action.Skip,
+	}
+	assert.Equal(t, action.Skip, a.OnFailure())
+}
+
+func TestAction_When(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:  fndMock,
+		when: action.OnSuccess,
+	}
+	assert.Equal(t, action.OnSuccess, a.When())
+}
@@ -259,6 +259,7 @@ func Test_customAction_Execute(t *testing.T) {
 			) {
 				mockLogger := external.NewMockLogger()
 				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				rd.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "test", params).Return("test tmp", nil)
 				r := strings.NewReader("test tmp")
 				svc.On("OutputReader", ctx, outputType).Return(r, nil)
@@ -270,7 +271,7 @@ func Test_customAction_Execute(t *testing.T) {
 			name: "response expectation set",
 			responseExpectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "test",
 				BodyMatch:          expectations.MatchTypeExact,
 				BodyRenderTemplate: true,
@@ -292,6 +293,7 @@ func Test_customAction_Execute(t *testing.T) {
 					Headers: http.Header{"content-type": []string{"application/json"}},
 				}
 				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "test", params).Return("test tmp", nil)
 			},
 			expectedOutputType: output.Any,
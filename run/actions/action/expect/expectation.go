@@ -19,6 +19,7 @@ import (
 	"github.com/wstool/wst/conf/types"
 	"github.com/wstool/wst/run/actions/action"
 	"github.com/wstool/wst/run/expectations"
+	"github.com/wstool/wst/run/instances/runtime"
 	"github.com/wstool/wst/run/parameters"
 	"github.com/wstool/wst/run/services"
 	"time"
@@ -39,29 +40,53 @@ type Maker interface {
 		config *types.OutputExpectationAction,
 		sl services.ServiceLocator,
 		defaultTimeout int,
+		actionMaker action.Maker,
+	) (action.Action, error)
+	MakeReceivedAction(
+		config *types.ReceivedExpectationAction,
+		sl services.ServiceLocator,
+		defaultTimeout int,
 	) (action.Action, error)
 	MakeResponseAction(
 		config *types.ResponseExpectationAction,
 		sl services.ServiceLocator,
 		defaultTimeout int,
 	) (action.Action, error)
+	MakeSnapshotAction(
+		config *types.SnapshotExpectationAction,
+		sl services.ServiceLocator,
+		defaultTimeout int,
+	) (action.Action, error)
+	MakeProcessAction(
+		config *types.ProcessExpectationAction,
+		sl services.ServiceLocator,
+		defaultTimeout int,
+	) (action.Action, error)
+	MakeFileAction(
+		config *types.FileExpectationAction,
+		sl services.ServiceLocator,
+		defaultTimeout int,
+	) (action.Action, error)
 }
 
 type ExpectationActionMaker struct {
 	fnd               app.Foundation
 	expectationsMaker expectations.Maker
 	parametersMaker   parameters.Maker
+	runtimeMaker      runtime.Maker
 }
 
 func CreateExpectationActionMaker(
 	fnd app.Foundation,
 	expectationsMaker expectations.Maker,
 	parametersMaker parameters.Maker,
+	runtimeMaker runtime.Maker,
 ) *ExpectationActionMaker {
 	return &ExpectationActionMaker{
 		fnd:               fnd,
 		parametersMaker:   parametersMaker,
 		expectationsMaker: expectationsMaker,
+		runtimeMaker:      runtimeMaker,
 	}
 }
 
@@ -83,20 +108,22 @@ func (m *ExpectationActionMaker) MakeCommonExpectation(
 	}
 
 	return &CommonExpectation{
-		fnd:       m.fnd,
-		service:   svc,
-		timeout:   time.Duration(timeout * 1e6),
-		when:      action.When(when),
-		onFailure: action.OnFailureType(onFailure),
+		fnd:          m.fnd,
+		runtimeMaker: m.runtimeMaker,
+		service:      svc,
+		timeout:      time.Duration(timeout * 1e6),
+		when:         action.When(when),
+		onFailure:    action.OnFailureType(onFailure),
 	}, nil
 }
 
 type CommonExpectation struct {
-	fnd       app.Foundation
-	service   services.Service
-	timeout   time.Duration
-	when      action.When
-	onFailure action.OnFailureType
+	fnd          app.Foundation
+	runtimeMaker runtime.Maker
+	service      services.Service
+	timeout      time.Duration
+	when         action.When
+	onFailure    action.OnFailureType
 }
 
 func (a *CommonExpectation) When() action.When {
@@ -110,3 +137,26 @@ func (a *CommonExpectation) OnFailure() action.OnFailureType {
 func (a *CommonExpectation) Timeout() time.Duration {
 	return a.timeout
 }
+
+// renderParameters returns parameters for template rendering with runtime parameters taking precedence.
+func renderParameters(runData runtime.Data, params parameters.Parameters) parameters.Parameters {
+	return make(parameters.Parameters).Inherit(runData.Parameters()).Inherit(params)
+}
+
+// matchExpr evaluates the expression with the variables and parameters. The evaluation failure (e.g. missing map
+// key) is logged and considered as not matching.
+func (a *CommonExpectation) matchExpr(
+	expr *expectations.Expr,
+	variables map[string]interface{},
+	params parameters.Parameters,
+) bool {
+	matched, err := expr.Evaluate(variables, params)
+	if err != nil {
+		a.fnd.Logger().Infof("Expression did not match: %v", err)
+		return false
+	}
+	if !matched {
+		a.fnd.Logger().Infof("Expression %s evaluated to false", expr)
+	}
+	return matched
+}
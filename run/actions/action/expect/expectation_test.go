@@ -6,10 +6,12 @@ import (
 	"github.com/wstool/wst/app"
 	appMocks "github.com/wstool/wst/mocks/generated/app"
 	expectationsMocks "github.com/wstool/wst/mocks/generated/run/expectations"
+	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
 	parametersMocks "github.com/wstool/wst/mocks/generated/run/parameters"
 	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
 	"github.com/wstool/wst/run/actions/action"
 	"github.com/wstool/wst/run/expectations"
+	"github.com/wstool/wst/run/instances/runtime"
 	"github.com/wstool/wst/run/parameters"
 	"testing"
 )
@@ -18,25 +20,29 @@ func TestCreateExpectationActionMaker(t *testing.T) {
 	fndMock := appMocks.NewMockFoundation(t)
 	expectationsMakerMock := expectationsMocks.NewMockMaker(t)
 	parametersMakerMock := parametersMocks.NewMockMaker(t)
+	runtimeMakerMock := runtimeMocks.NewMockMaker(t)
 	tests := []struct {
 		name              string
 		fnd               app.Foundation
 		expectationsMaker expectations.Maker
 		parametersMaker   parameters.Maker
+		runtimeMaker      runtime.Maker
 	}{
 		{
 			name:              "create maker",
 			fnd:               fndMock,
 			expectationsMaker: expectationsMakerMock,
 			parametersMaker:   parametersMakerMock,
+			runtimeMaker:      runtimeMakerMock,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := CreateExpectationActionMaker(tt.fnd, tt.expectationsMaker, tt.parametersMaker)
+			got := CreateExpectationActionMaker(tt.fnd, tt.expectationsMaker, tt.parametersMaker, tt.runtimeMaker)
 			assert.Equal(t, tt.fnd, got.fnd)
 			assert.Equal(t, tt.expectationsMaker, got.expectationsMaker)
 			assert.Equal(t, tt.parametersMaker, got.parametersMaker)
+			assert.Equal(t, tt.runtimeMaker, got.runtimeMaker)
 		})
 	}
 }
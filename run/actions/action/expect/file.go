@@ -0,0 +1,352 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expect
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/environments/environment"
+	"github.com/wstool/wst/run/expectations"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/metrics"
+	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/sandboxes/dir"
+	"github.com/wstool/wst/run/services"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// fileTailInterval is the time between the file reads when the file is tailed.
+const fileTailInterval = 100 * time.Millisecond
+
+func (m *ExpectationActionMaker) MakeFileAction(
+	config *types.FileExpectationAction,
+	sl services.ServiceLocator,
+	defaultTimeout int,
+) (action.Action, error) {
+	commonExpectation, err := m.MakeCommonExpectation(
+		sl, config.Service, config.Timeout, defaultTimeout, config.When, config.OnFailure)
+	if err != nil {
+		return nil, err
+	}
+
+	fileExpectation, err := m.expectationsMaker.MakeFileExpectation(&config.File)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := fileServicePath(commonExpectation.service, fileExpectation.Dir, fileExpectation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileAction{
+		CommonExpectation: commonExpectation,
+		FileExpectation:   fileExpectation,
+		parameters:        commonExpectation.service.ServerParameters(),
+		path:              path,
+	}, nil
+}
+
+// fileServicePath resolves the path relative to the service directory of the dir type unless it is absolute.
+func fileServicePath(svc services.Service, dirType dir.DirType, path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+	var serviceDir string
+	var err error
+	switch dirType {
+	case dir.ConfDirType:
+		serviceDir, err = svc.ConfDir()
+	case dir.ScriptDirType:
+		serviceDir, err = svc.ScriptDir()
+	default:
+		serviceDir, err = svc.RunDir()
+	}
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(serviceDir, path), nil
+}
+
+type fileAction struct {
+	*CommonExpectation
+	*expectations.FileExpectation
+	parameters parameters.Parameters
+	// path is the resolved file path in the service environment.
+	path string
+}
+
+// fileCheckpointKey returns the run data key of the file checkpoint.
+func fileCheckpointKey(name string) string {
+	return fmt.Sprintf("files/%s", name)
+}
+
+// fileCheckpoint is the file size stored in the checkpoint.
+type fileCheckpoint struct {
+	path string
+	size int64
+}
+
+// fileContent is the file content read from the from position. The tailed file is read incrementally so only the
+// appended content is read and matched and the remaining messages are kept between the reads.
+type fileContent struct {
+	// start is the file offset of the content.
+	start int64
+	data  []byte
+	// matched is the length of the data prefix whose lines were matched.
+	matched   int
+	remaining []string
+}
+
+// offset returns the file offset of the next read.
+func (c *fileContent) offset() int64 {
+	return c.start + int64(len(c.data))
+}
+
+func (a *fileAction) Execute(ctx context.Context, runData runtime.Data) (bool, error) {
+	logger := a.fnd.Logger()
+	logger.Infof("Executing expectation file action")
+	if a.fnd.DryRun() {
+		logger.Debugf("Skipping file checks in dry run")
+		return true, nil
+	}
+
+	messages := a.Messages
+	if a.RenderTemplate && len(messages) > 0 {
+		var err error
+		if messages, err = renderMessages(a.service, messages, renderParameters(runData, a.parameters)); err != nil {
+			return false, err
+		}
+	}
+
+	start, err := a.fromOffset(ctx, runData)
+	if err != nil {
+		return false, err
+	}
+	// The messages are cloned as the random order matching removes them from the slice.
+	content := &fileContent{start: start, remaining: slices.Clone(messages)}
+
+	// The tailed file is read repeatedly from the last offset until it matches or the action times out.
+	var reason string
+	for {
+		file, err := a.service.File(ctx, a.path, content.offset())
+		if err != nil {
+			if ctx.Err() != nil && reason != "" {
+				break
+			}
+			if !a.Tail {
+				return false, err
+			}
+			// The tailed file read might fail only temporarily so it is read again until the action times out.
+			reason = fmt.Sprintf("cannot be read: %v", err)
+		} else {
+			if file != nil && file.Size < content.offset() {
+				logger.Debugf("File %s was truncated, reading it from the start", a.path)
+				content = &fileContent{remaining: slices.Clone(messages)}
+				continue
+			}
+			if file != nil {
+				content.data = append(content.data, file.Content...)
+			}
+			if reason, err = a.matchFile(file, content, runData); err != nil {
+				return false, err
+			}
+			if reason == "" {
+				return true, a.storeCheckpoint(runData, file)
+			}
+		}
+		if !a.Tail || a.fnd.Sleep(ctx, fileTailInterval) != nil {
+			break
+		}
+		logger.Debugf("File %s %s, reading it again", a.path, reason)
+	}
+
+	logger.Infof("File %s %s", a.path, reason)
+	return false, nil
+}
+
+// fromOffset returns the file offset that the content is read from. The now position is the file size at the action
+// start.
+func (a *fileAction) fromOffset(ctx context.Context, runData runtime.Data) (int64, error) {
+	switch a.From {
+	case "", expectations.FileFromStart:
+		return 0, nil
+	case expectations.FileFromNow:
+		file, err := a.service.File(ctx, a.path, environment.FileNoContent)
+		if err != nil || file == nil {
+			return 0, err
+		}
+		return file.Size, nil
+	default:
+		data, ok := runData.Load(fileCheckpointKey(a.From))
+		if !ok {
+			return 0, errors.Errorf("file checkpoint %s not found", a.From)
+		}
+		checkpoint, ok := data.(fileCheckpoint)
+		if !ok {
+			return 0, errors.Errorf("invalid file checkpoint %s data type", a.From)
+		}
+		if checkpoint.path != a.path {
+			return 0, errors.Errorf("file checkpoint %s is set for file %s", a.From, checkpoint.path)
+		}
+		return checkpoint.size, nil
+	}
+}
+
+// storeCheckpoint stores the file size to the checkpoint if it is set. The size of the missing file is zero.
+func (a *fileAction) storeCheckpoint(runData runtime.Data, file *environment.File) error {
+	if a.Checkpoint == "" {
+		return nil
+	}
+	checkpoint := fileCheckpoint{path: a.path}
+	if file != nil {
+		checkpoint.size = file.Size
+	}
+	return runData.Store(fileCheckpointKey(a.Checkpoint), checkpoint)
+}
+
+// matchFile returns the reason why the file does not match the expectation or an empty string if it matches. The
+// file is nil if it does not exist.
+func (a *fileAction) matchFile(
+	file *environment.File,
+	content *fileContent,
+	runData runtime.Data,
+) (string, error) {
+	if file == nil {
+		if a.Exists {
+			return "does not exist", nil
+		}
+		return a.matchFileExpr(file, content, runData), nil
+	}
+	if !a.Exists {
+		return "exists", nil
+	}
+
+	switch a.Type {
+	case expectations.FileTypeFile:
+		if !file.Mode.IsRegular() {
+			return "is not a regular file", nil
+		}
+	case expectations.FileTypeDir:
+		if !file.Mode.IsDir() {
+			return "is not a directory", nil
+		}
+	case expectations.FileTypeSocket:
+		if file.Mode&os.ModeSocket == 0 {
+			return "is not a socket", nil
+		}
+	}
+	if a.CheckMode && file.Mode.Perm() != a.Mode {
+		return fmt.Sprintf("mode %04o is not %04o", file.Mode.Perm(), a.Mode), nil
+	}
+	if a.Owner != "" && file.Owner != a.Owner {
+		return fmt.Sprintf("owner %s is not %s", file.Owner, a.Owner), nil
+	}
+	if a.Group != "" && file.Group != a.Group {
+		return fmt.Sprintf("group %s is not %s", file.Group, a.Group), nil
+	}
+	if a.SizeOperator != "" {
+		matched, err := metrics.GenericMetric[int64]{Value: file.Size}.Compare(a.SizeOperator, float64(a.Size))
+		if err != nil {
+			return "", err
+		}
+		if !matched {
+			return fmt.Sprintf("size %d is not %s %d", file.Size, a.SizeOperator, a.Size), nil
+		}
+	}
+	if len(a.Messages) == 0 {
+		return a.matchFileExpr(file, content, runData), nil
+	}
+	if !file.Mode.IsRegular() {
+		return "content cannot be checked as it is not a regular file", nil
+	}
+
+	if err := a.matchLines(content); err != nil {
+		return "", err
+	}
+	if len(content.remaining) > 0 {
+		return fmt.Sprintf("does not contain message %s", content.remaining[0]), nil
+	}
+	return a.matchFileExpr(file, content, runData), nil
+}
+
+// matchLines matches the content lines that were not matched yet with the remaining messages. The last line without
+// the new line is left for the next read if the file is tailed as it might not be completely written yet.
+func (a *fileAction) matchLines(content *fileContent) error {
+	logger := a.fnd.Logger()
+	data := content.data[content.matched:]
+	for len(content.remaining) > 0 && len(data) > 0 {
+		line, rest, found := bytes.Cut(data, []byte("\n"))
+		if !found && a.Tail {
+			break
+		}
+		var err error
+		text := strings.TrimSuffix(string(line), "\r")
+		if content.remaining, err = matchMessages(logger, a.OrderType, a.MatchType, text, content.remaining); err != nil {
+			return err
+		}
+		content.matched += len(data) - len(rest)
+		data = rest
+	}
+	return nil
+}
+
+// matchFileExpr evaluates the expression over the file and returns the reason if it does not match.
+func (a *fileAction) matchFileExpr(file *environment.File, content *fileContent, runData runtime.Data) string {
+	if a.Expr == nil {
+		return ""
+	}
+	variables := map[string]interface{}{
+		expectations.ExprVariableFile: fileExprValue(file, content.data),
+	}
+	if !a.matchExpr(a.Expr, variables, renderParameters(runData, a.parameters)) {
+		return fmt.Sprintf("does not match expression %s", a.Expr)
+	}
+	return ""
+}
+
+// fileExprValue returns the expression variable value of the file where only exists is set for a missing file. The
+// content is the file content read from the from position.
+func fileExprValue(file *environment.File, content []byte) map[string]interface{} {
+	if file == nil {
+		return map[string]interface{}{"exists": false}
+	}
+	fileType := "other"
+	switch {
+	case file.Mode.IsRegular():
+		fileType = string(expectations.FileTypeFile)
+	case file.Mode.IsDir():
+		fileType = string(expectations.FileTypeDir)
+	case file.Mode&os.ModeSocket != 0:
+		fileType = string(expectations.FileTypeSocket)
+	}
+	return map[string]interface{}{
+		"exists":  true,
+		"type":    fileType,
+		"mode":    int64(file.Mode.Perm()),
+		"owner":   file.Owner,
+		"group":   file.Group,
+		"size":    file.Size,
+		"content": string(content),
+	}
+}
@@ -0,0 +1,788 @@
+package expect
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	expectationsMocks "github.com/wstool/wst/mocks/generated/run/expectations"
+	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/environments/environment"
+	"github.com/wstool/wst/run/expectations"
+	"github.com/wstool/wst/run/metrics"
+	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/sandboxes/dir"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestExpectationActionMaker_MakeFileAction(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           *types.FileExpectationAction
+		setupMocks       func(*servicesMocks.MockServiceLocator, *servicesMocks.MockService, *expectationsMocks.MockMaker)
+		expectedPath     string
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "successful file action creation with run dir path",
+			config: &types.FileExpectationAction{
+				Service:   "svc",
+				When:      "on_success",
+				OnFailure: "fail",
+				File: types.FileExpectation{
+					Path: "slow.log",
+				},
+			},
+			setupMocks: func(
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				em *expectationsMocks.MockMaker,
+			) {
+				sl.On("Find", "svc").Return(svc, nil)
+				em.On("MakeFileExpectation", mock.Anything).Return(&expectations.FileExpectation{
+					Path:   "slow.log",
+					Dir:    dir.RunDirType,
+					Exists: true,
+				}, nil)
+				svc.On("RunDir").Return("/ws/run/svc", nil)
+				svc.On("ServerParameters").Return(parameters.Parameters{})
+			},
+			expectedPath: "/ws/run/svc/slow.log",
+		},
+		{
+			name: "successful file action creation with conf dir path",
+			config: &types.FileExpectationAction{
+				Service:   "svc",
+				When:      "on_success",
+				OnFailure: "fail",
+			},
+			setupMocks: func(
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				em *expectationsMocks.MockMaker,
+			) {
+				sl.On("Find", "svc").Return(svc, nil)
+				em.On("MakeFileExpectation", mock.Anything).Return(&expectations.FileExpectation{
+					Path:   "fpm/php-fpm.conf",
+					Dir:    dir.ConfDirType,
+					Exists: true,
+				}, nil)
+				svc.On("ConfDir").Return("/ws/conf/svc", nil)
+				svc.On("ServerParameters").Return(parameters.Parameters{})
+			},
+			expectedPath: "/ws/conf/svc/fpm/php-fpm.conf",
+		},
+		{
+			name: "successful file action creation with script dir path",
+			config: &types.FileExpectationAction{
+				Service:   "svc",
+				When:      "on_success",
+				OnFailure: "fail",
+			},
+			setupMocks: func(
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				em *expectationsMocks.MockMaker,
+			) {
+				sl.On("Find", "svc").Return(svc, nil)
+				em.On("MakeFileExpectation", mock.Anything).Return(&expectations.FileExpectation{
+					Path:   "index.php",
+					Dir:    dir.ScriptDirType,
+					Exists: true,
+				}, nil)
+				svc.On("ScriptDir").Return("/ws/script/svc", nil)
+				svc.On("ServerParameters").Return(parameters.Parameters{})
+			},
+			expectedPath: "/ws/script/svc/index.php",
+		},
+		{
+			name: "successful file action creation with absolute path",
+			config: &types.FileExpectationAction{
+				Service:   "svc",
+				When:      "on_success",
+				OnFailure: "fail",
+			},
+			setupMocks: func(
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				em *expectationsMocks.MockMaker,
+			) {
+				sl.On("Find", "svc").Return(svc, nil)
+				em.On("MakeFileExpectation", mock.Anything).Return(&expectations.FileExpectation{
+					Path:   "/var/log/fpm.log",
+					Dir:    dir.RunDirType,
+					Exists: true,
+				}, nil)
+				svc.On("ServerParameters").Return(parameters.Parameters{})
+			},
+			expectedPath: "/var/log/fpm.log",
+		},
+		{
+			name: "failed file action creation because no service found",
+			config: &types.FileExpectationAction{
+				Service: "invalid",
+			},
+			setupMocks: func(
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				em *expectationsMocks.MockMaker,
+			) {
+				sl.On("Find", "invalid").Return(nil, errors.New("svc not found"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "svc not found",
+		},
+		{
+			name: "failed file action creation because file expectation creation failed",
+			config: &types.FileExpectationAction{
+				Service: "svc",
+			},
+			setupMocks: func(
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				em *expectationsMocks.MockMaker,
+			) {
+				sl.On("Find", "svc").Return(svc, nil)
+				em.On("MakeFileExpectation", mock.Anything).Return(nil, errors.New("file path is required"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "file path is required",
+		},
+		{
+			name: "failed file action creation because service dir failed",
+			config: &types.FileExpectationAction{
+				Service:   "svc",
+				When:      "on_success",
+				OnFailure: "fail",
+			},
+			setupMocks: func(
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				em *expectationsMocks.MockMaker,
+			) {
+				sl.On("Find", "svc").Return(svc, nil)
+				em.On("MakeFileExpectation", mock.Anything).Return(&expectations.FileExpectation{
+					Path:   "slow.log",
+					Dir:    dir.RunDirType,
+					Exists: true,
+				}, nil)
+				svc.On("RunDir").Return("", errors.New("mkdir failed"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "mkdir failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			svcMock := servicesMocks.NewMockService(t)
+			expectationsMakerMock := expectationsMocks.NewMockMaker(t)
+			m := &ExpectationActionMaker{
+				fnd:               fndMock,
+				expectationsMaker: expectationsMakerMock,
+			}
+			tt.setupMocks(slMock, svcMock, expectationsMakerMock)
+
+			got, err := m.MakeFileAction(tt.config, slMock, 5000)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				actualAction, ok := got.(*fileAction)
+				require.True(t, ok)
+				assert.Equal(t, tt.expectedPath, actualAction.path)
+				assert.Equal(t, parameters.Parameters{}, actualAction.parameters)
+				assert.Equal(t, 5*time.Second, actualAction.Timeout())
+				assert.Equal(t, action.OnSuccess, actualAction.When())
+				assert.Equal(t, action.Fail, actualAction.OnFailure())
+			}
+		})
+	}
+}
+
+func Test_fileAction_Execute(t *testing.T) {
+	logFile := &environment.File{
+		Mode:    0640,
+		Size:    51,
+		Owner:   "www-data",
+		Group:   "adm",
+		Content: []byte("[pool www] pid 12\nscript_filename = /index.php\nend\n"),
+	}
+	tests := []struct {
+		name             string
+		expectation      *expectations.FileExpectation
+		dryRun           bool
+		setupMocks       func(*appMocks.MockFoundation, *servicesMocks.MockService, *runtimeMocks.MockData)
+		want             bool
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "all checks match",
+			expectation: &expectations.FileExpectation{
+				Exists:       true,
+				Type:         expectations.FileTypeFile,
+				Mode:         0640,
+				CheckMode:    true,
+				Owner:        "www-data",
+				Group:        "adm",
+				Size:         0,
+				SizeOperator: metrics.MetricGtOperator,
+				OrderType:    expectations.OrderTypeFixed,
+				MatchType:    expectations.MatchTypePrefix,
+				Messages:     []string{"[pool www]", "script_filename"},
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(logFile, nil)
+			},
+			want: true,
+		},
+		{
+			name: "rendered messages in random order match",
+			expectation: &expectations.FileExpectation{
+				Exists:         true,
+				Type:           expectations.FileTypeAny,
+				OrderType:      expectations.OrderTypeRandom,
+				MatchType:      expectations.MatchTypeRegexp,
+				Messages:       []string{"^end$", "script_filename = {{ .Parameters.GetString \"script\" }}"},
+				RenderTemplate: true,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				rd.On("Parameters").Return(parameters.Parameters{})
+				svc.On("RenderTemplate", "^end$", mock.Anything).Return("^end$", nil)
+				svc.On("RenderTemplate", "script_filename = {{ .Parameters.GetString \"script\" }}", mock.Anything).
+					Return("script_filename = /index.php", nil)
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(logFile, nil)
+			},
+			want: true,
+		},
+		{
+			name: "fixed order does not match",
+			expectation: &expectations.FileExpectation{
+				Exists:    true,
+				Type:      expectations.FileTypeAny,
+				OrderType: expectations.OrderTypeFixed,
+				MatchType: expectations.MatchTypeInfix,
+				Messages:  []string{"script_filename", "pool www"},
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(logFile, nil)
+			},
+			want: false,
+		},
+		{
+			name: "expression over file matches",
+			expectation: &expectations.FileExpectation{
+				Exists: true,
+				Type:   expectations.FileTypeAny,
+				Expr: compileExpr(
+					t,
+					"file.type == 'file' && file.mode == 416 && file.content.contains('pid 12')",
+					expectations.ExprVariableFile,
+				),
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				rd.On("Parameters").Return(parameters.Parameters{})
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(logFile, nil)
+			},
+			want: true,
+		},
+		{
+			name: "expression over file does not match",
+			expectation: &expectations.FileExpectation{
+				Exists:    true,
+				Type:      expectations.FileTypeAny,
+				OrderType: expectations.OrderTypeFixed,
+				MatchType: expectations.MatchTypePrefix,
+				Messages:  []string{"[pool www]"},
+				Expr:      compileExpr(t, "file.size < 10", expectations.ExprVariableFile),
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				rd.On("Parameters").Return(parameters.Parameters{})
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(logFile, nil)
+			},
+			want: false,
+		},
+		{
+			name: "expression over missing file matches",
+			expectation: &expectations.FileExpectation{
+				Exists: false,
+				Type:   expectations.FileTypeAny,
+				Expr:   compileExpr(t, "!file.exists", expectations.ExprVariableFile),
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				rd.On("Parameters").Return(parameters.Parameters{})
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(nil, nil)
+			},
+			want: true,
+		},
+		{
+			name: "missing file does not match",
+			expectation: &expectations.FileExpectation{
+				Exists: true,
+				Type:   expectations.FileTypeAny,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(nil, nil)
+			},
+			want: false,
+		},
+		{
+			name: "file that must not exist is missing",
+			expectation: &expectations.FileExpectation{
+				Exists: false,
+				Type:   expectations.FileTypeAny,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(nil, nil)
+			},
+			want: true,
+		},
+		{
+			name: "file that must not exist exists",
+			expectation: &expectations.FileExpectation{
+				Exists: false,
+				Type:   expectations.FileTypeAny,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(logFile, nil)
+			},
+			want: false,
+		},
+		{
+			name: "socket type matches",
+			expectation: &expectations.FileExpectation{
+				Exists:    true,
+				Type:      expectations.FileTypeSocket,
+				Mode:      0660,
+				CheckMode: true,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(&environment.File{
+					Mode: os.ModeSocket | 0660,
+				}, nil)
+			},
+			want: true,
+		},
+		{
+			name: "directory type does not match",
+			expectation: &expectations.FileExpectation{
+				Exists: true,
+				Type:   expectations.FileTypeDir,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(logFile, nil)
+			},
+			want: false,
+		},
+		{
+			name: "mode does not match",
+			expectation: &expectations.FileExpectation{
+				Exists:    true,
+				Type:      expectations.FileTypeAny,
+				Mode:      0600,
+				CheckMode: true,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(logFile, nil)
+			},
+			want: false,
+		},
+		{
+			name: "owner does not match",
+			expectation: &expectations.FileExpectation{
+				Exists: true,
+				Type:   expectations.FileTypeAny,
+				Owner:  "root",
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(logFile, nil)
+			},
+			want: false,
+		},
+		{
+			name: "group does not match",
+			expectation: &expectations.FileExpectation{
+				Exists: true,
+				Type:   expectations.FileTypeAny,
+				Group:  "root",
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(logFile, nil)
+			},
+			want: false,
+		},
+		{
+			name: "size does not match",
+			expectation: &expectations.FileExpectation{
+				Exists:       true,
+				Type:         expectations.FileTypeAny,
+				Size:         0,
+				SizeOperator: metrics.MetricEqOperator,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(logFile, nil)
+			},
+			want: false,
+		},
+		{
+			name: "content of non regular file does not match",
+			expectation: &expectations.FileExpectation{
+				Exists:    true,
+				Type:      expectations.FileTypeAny,
+				OrderType: expectations.OrderTypeFixed,
+				MatchType: expectations.MatchTypeExact,
+				Messages:  []string{"end"},
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(&environment.File{
+					Mode: os.ModeDir | 0755,
+				}, nil)
+			},
+			want: false,
+		},
+		{
+			name: "tailed file matches after it is appended",
+			expectation: &expectations.FileExpectation{
+				Exists:    true,
+				Type:      expectations.FileTypeAny,
+				OrderType: expectations.OrderTypeFixed,
+				MatchType: expectations.MatchTypeExact,
+				Messages:  []string{"[pool www] pid 12", "end"},
+				Tail:      true,
+				From:      expectations.FileFromStart,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(nil, nil).Once()
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(&environment.File{
+					Size:    18,
+					Content: []byte("[pool www] pid 12\n"),
+				}, nil).Once()
+				svc.On("File", mock.Anything, "/run/slow.log", int64(18)).Return(&environment.File{
+					Size:    22,
+					Content: []byte("end\n"),
+				}, nil).Once()
+				fnd.On("Sleep", mock.Anything, fileTailInterval).Return(nil).Twice()
+			},
+			want: true,
+		},
+		{
+			name: "tailed file matches only lines appended after action start",
+			expectation: &expectations.FileExpectation{
+				Exists:    true,
+				Type:      expectations.FileTypeAny,
+				OrderType: expectations.OrderTypeFixed,
+				MatchType: expectations.MatchTypeExact,
+				Messages:  []string{"end"},
+				Tail:      true,
+				From:      expectations.FileFromNow,
+				Expr: compileExpr(
+					t,
+					"file.content == '[pool www] pid 13\\nend\\n'",
+					expectations.ExprVariableFile,
+				),
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", environment.FileNoContent).Return(&environment.File{
+					Size: 51,
+				}, nil).Once()
+				svc.On("File", mock.Anything, "/run/slow.log", int64(51)).Return(&environment.File{
+					Size:    69,
+					Content: []byte("[pool www] pid 13\n"),
+				}, nil).Once()
+				svc.On("File", mock.Anything, "/run/slow.log", int64(69)).Return(&environment.File{
+					Size:    73,
+					Content: []byte("end\n"),
+				}, nil).Once()
+				fnd.On("Sleep", mock.Anything, fileTailInterval).Return(nil).Once()
+				rd.On("Parameters").Return(parameters.Parameters{})
+			},
+			want: true,
+		},
+		{
+			name: "tailed file does not match message written before action start",
+			expectation: &expectations.FileExpectation{
+				Exists:    true,
+				Type:      expectations.FileTypeAny,
+				OrderType: expectations.OrderTypeFixed,
+				MatchType: expectations.MatchTypeExact,
+				Messages:  []string{"end"},
+				Tail:      true,
+				From:      expectations.FileFromNow,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", environment.FileNoContent).Return(&environment.File{
+					Size: 51,
+				}, nil).Once()
+				svc.On("File", mock.Anything, "/run/slow.log", int64(51)).Return(&environment.File{
+					Size: 51,
+				}, nil).Twice()
+				fnd.On("Sleep", mock.Anything, fileTailInterval).Return(nil).Once()
+				fnd.On("Sleep", mock.Anything, fileTailInterval).Return(context.DeadlineExceeded).Once()
+			},
+			want: false,
+		},
+		{
+			name: "tailed file matches line once it is completely written",
+			expectation: &expectations.FileExpectation{
+				Exists:    true,
+				Type:      expectations.FileTypeAny,
+				OrderType: expectations.OrderTypeFixed,
+				MatchType: expectations.MatchTypeExact,
+				Messages:  []string{"end"},
+				Tail:      true,
+				From:      expectations.FileFromNow,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", environment.FileNoContent).Return(nil, nil).Once()
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(&environment.File{
+					Size:    2,
+					Content: []byte("en"),
+				}, nil).Once()
+				svc.On("File", mock.Anything, "/run/slow.log", int64(2)).Return(&environment.File{
+					Size:    4,
+					Content: []byte("d\n"),
+				}, nil).Once()
+				fnd.On("Sleep", mock.Anything, fileTailInterval).Return(nil).Once()
+			},
+			want: true,
+		},
+		{
+			name: "truncated tailed file is read from the start",
+			expectation: &expectations.FileExpectation{
+				Exists:    true,
+				Type:      expectations.FileTypeAny,
+				OrderType: expectations.OrderTypeFixed,
+				MatchType: expectations.MatchTypeExact,
+				Messages:  []string{"end"},
+				Tail:      true,
+				From:      "before",
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				rd.On("Load", "files/before").Return(fileCheckpoint{path: "/run/slow.log", size: 100}, true)
+				svc.On("File", mock.Anything, "/run/slow.log", int64(100)).Return(&environment.File{
+					Size: 4,
+				}, nil).Once()
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(&environment.File{
+					Size:    4,
+					Content: []byte("end\n"),
+				}, nil).Once()
+			},
+			want: true,
+		},
+		{
+			name: "tailed file does not match before timeout",
+			expectation: &expectations.FileExpectation{
+				Exists:    true,
+				Type:      expectations.FileTypeAny,
+				OrderType: expectations.OrderTypeFixed,
+				MatchType: expectations.MatchTypeExact,
+				Messages:  []string{"missing"},
+				Tail:      true,
+				From:      expectations.FileFromStart,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(logFile, nil).Once()
+				svc.On("File", mock.Anything, "/run/slow.log", int64(51)).Return(&environment.File{
+					Size: 51,
+				}, nil).Once()
+				fnd.On("Sleep", mock.Anything, fileTailInterval).Return(nil).Once()
+				fnd.On("Sleep", mock.Anything, fileTailInterval).Return(context.DeadlineExceeded).Once()
+			},
+			want: false,
+		},
+		{
+			name: "tailed file matches after reading error",
+			expectation: &expectations.FileExpectation{
+				Exists:    true,
+				Type:      expectations.FileTypeAny,
+				OrderType: expectations.OrderTypeFixed,
+				MatchType: expectations.MatchTypeExact,
+				Messages:  []string{"end"},
+				Tail:      true,
+				From:      expectations.FileFromStart,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(
+					nil, errors.New("permission denied")).Once()
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(&environment.File{
+					Size:    4,
+					Content: []byte("end\n"),
+				}, nil).Once()
+				fnd.On("Sleep", mock.Anything, fileTailInterval).Return(nil).Once()
+			},
+			want: true,
+		},
+		{
+			name: "tailed file reading error until timeout",
+			expectation: &expectations.FileExpectation{
+				Exists:    true,
+				Type:      expectations.FileTypeAny,
+				OrderType: expectations.OrderTypeFixed,
+				MatchType: expectations.MatchTypeExact,
+				Messages:  []string{"end"},
+				Tail:      true,
+				From:      expectations.FileFromStart,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(
+					nil, errors.New("permission denied")).Twice()
+				fnd.On("Sleep", mock.Anything, fileTailInterval).Return(nil).Once()
+				fnd.On("Sleep", mock.Anything, fileTailInterval).Return(context.DeadlineExceeded).Once()
+			},
+			want: false,
+		},
+		{
+			name: "checkpoint is stored on success",
+			expectation: &expectations.FileExpectation{
+				Exists:     true,
+				Type:       expectations.FileTypeAny,
+				Checkpoint: "after",
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(logFile, nil)
+				rd.On("Store", "files/after", fileCheckpoint{path: "/run/slow.log", size: 51}).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name: "checkpoint of missing file is stored with zero size",
+			expectation: &expectations.FileExpectation{
+				Type:       expectations.FileTypeAny,
+				Checkpoint: "after",
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(nil, nil)
+				rd.On("Store", "files/after", fileCheckpoint{path: "/run/slow.log"}).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name: "from checkpoint not found",
+			expectation: &expectations.FileExpectation{
+				Exists:   true,
+				Type:     expectations.FileTypeAny,
+				Messages: []string{"end"},
+				From:     "before",
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				rd.On("Load", "files/before").Return(nil, false)
+			},
+			expectError:      true,
+			expectedErrorMsg: "file checkpoint before not found",
+		},
+		{
+			name: "from checkpoint with invalid data type",
+			expectation: &expectations.FileExpectation{
+				Exists:   true,
+				Type:     expectations.FileTypeAny,
+				Messages: []string{"end"},
+				From:     "before",
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				rd.On("Load", "files/before").Return(int64(10), true)
+			},
+			expectError:      true,
+			expectedErrorMsg: "invalid file checkpoint before data type",
+		},
+		{
+			name: "from checkpoint of other file",
+			expectation: &expectations.FileExpectation{
+				Exists:   true,
+				Type:     expectations.FileTypeAny,
+				Messages: []string{"end"},
+				From:     "before",
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				rd.On("Load", "files/before").Return(fileCheckpoint{path: "/run/access.log", size: 10}, true)
+			},
+			expectError:      true,
+			expectedErrorMsg: "file checkpoint before is set for file /run/access.log",
+		},
+		{
+			name: "file reading error",
+			expectation: &expectations.FileExpectation{
+				Exists: true,
+				Type:   expectations.FileTypeAny,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("File", mock.Anything, "/run/slow.log", int64(0)).Return(nil, errors.New("service has not started yet"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "service has not started yet",
+		},
+		{
+			name: "message rendering error",
+			expectation: &expectations.FileExpectation{
+				Exists:         true,
+				Type:           expectations.FileTypeAny,
+				Messages:       []string{"{{ .Invalid"},
+				RenderTemplate: true,
+			},
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				rd.On("Parameters").Return(parameters.Parameters{})
+				svc.On("RenderTemplate", "{{ .Invalid", mock.Anything).Return("", errors.New("template error"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "template error",
+		},
+		{
+			name: "dry run",
+			expectation: &expectations.FileExpectation{
+				Exists: true,
+				Type:   expectations.FileTypeAny,
+			},
+			dryRun:     true,
+			setupMocks: func(fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {},
+			want:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			svcMock := servicesMocks.NewMockService(t)
+			dataMock := runtimeMocks.NewMockData(t)
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+			fndMock.On("DryRun").Return(tt.dryRun)
+			tt.setupMocks(fndMock, svcMock, dataMock)
+
+			a := &fileAction{
+				CommonExpectation: &CommonExpectation{
+					fnd:     fndMock,
+					service: svcMock,
+					timeout: 20 * 1e6,
+				},
+				FileExpectation: tt.expectation,
+				parameters:      parameters.Parameters{},
+				path:            "/run/slow.log",
+			}
+
+			got, err := a.Execute(context.Background(), dataMock)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.False(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
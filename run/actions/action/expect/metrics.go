@@ -72,7 +72,13 @@ func (a *metricsAction) Execute(ctx context.Context, runData runtime.Data) (bool
 	a.fnd.Logger().Debugf("Checking metrics %s data: %v", a.Id, metricsData)
 
 	for _, rule := range a.Rules {
-		metric, err := metricsData.Find(rule.Metric)
+		var metric metrics.Metric
+		var err error
+		if rule.Aggregation != "" {
+			metric, err = metricsData.FindWindowed(rule.Metric, rule.Window, rule.Aggregation)
+		} else {
+			metric, err = metricsData.Find(rule.Metric)
+		}
 		if err != nil {
 			return false, fmt.Errorf("failed to find metric %s: %w", rule.Metric, err)
 		}
@@ -89,5 +95,14 @@ func (a *metricsAction) Execute(ctx context.Context, runData runtime.Data) (bool
 			return false, nil
 		}
 	}
+
+	if a.Expr != nil {
+		variables := map[string]interface{}{
+			expectations.ExprVariableMetrics: metricsData.Values(),
+		}
+		if !a.matchExpr(a.Expr, variables, renderParameters(runData, a.parameters)) {
+			return a.fnd.DryRun(), nil
+		}
+	}
 	return true, nil
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/wstool/wst/run/expectations"
 	"github.com/wstool/wst/run/metrics"
 	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/parameters/parameter"
 	"testing"
 	"time"
 )
@@ -214,6 +215,7 @@ func Test_metricsAction_Execute(t *testing.T) {
 			*runtimeMocks.MockData,
 		)
 		rules            []expectations.MetricRule
+		expr             string
 		id               string
 		want             bool
 		expectErr        bool
@@ -246,6 +248,40 @@ func Test_metricsAction_Execute(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "successful windowed metrics comparison match",
+			id:   "mid",
+			rules: []expectations.MetricRule{
+				{
+					Metric:      "LatencyP99",
+					Operator:    metrics.MetricLtOperator,
+					Value:       50000000,
+					Aggregation: metrics.AggregationMax,
+					Window:      metrics.Window{From: time.Second, To: 3 * time.Second},
+				},
+			},
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				metricsMock := metricsMocks.NewMockMetrics(t)
+				metricMock := metricsMocks.NewMockMetric(t)
+				rd.On("Load", "metrics/mid").Return(metricsMock, true)
+				metricsMock.On(
+					"FindWindowed",
+					"LatencyP99",
+					metrics.Window{From: time.Second, To: 3 * time.Second},
+					metrics.AggregationMax,
+				).Return(metricMock, nil)
+				metricsMock.On("String").Return("metrics").Maybe()
+				metricMock.On("Compare", metrics.MetricLtOperator, 50000000.0).Return(true, nil)
+			},
+			want: true,
+		},
 		{
 			name: "successful metrics comparison not match dry run",
 			id:   "mid",
@@ -408,6 +444,77 @@ func Test_metricsAction_Execute(t *testing.T) {
 			expectErr:        true,
 			expectedErrorMsg: "metrics data for key metrics/mid not found",
 		},
+		{
+			name: "successful metrics expression match",
+			id:   "mid",
+			expr: "metrics.LatencyP99 < duration('50ms') && metrics.Requests == 100",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				metricsMock := metricsMocks.NewMockMetrics(t)
+				rd.On("Load", "metrics/mid").Return(metricsMock, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				metricsMock.On("Values").Return(map[string]interface{}{
+					"LatencyP99": 30 * time.Millisecond,
+					"Requests":   uint64(100),
+				})
+				metricsMock.On("String").Return("metrics").Maybe()
+			},
+			want: true,
+		},
+		{
+			name: "failed metrics expression match",
+			id:   "mid",
+			expr: "metrics.LatencyP99 < duration('10ms')",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				fnd.On("DryRun").Return(false)
+				metricsMock := metricsMocks.NewMockMetrics(t)
+				rd.On("Load", "metrics/mid").Return(metricsMock, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				metricsMock.On("Values").Return(map[string]interface{}{
+					"LatencyP99": 30 * time.Millisecond,
+					"Requests":   uint64(100),
+				})
+				metricsMock.On("String").Return("metrics").Maybe()
+			},
+			want: false,
+		},
+		{
+			name: "failed metrics expression evaluation",
+			id:   "mid",
+			expr: "metrics.MaxRate > 100",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				fnd.On("DryRun").Return(false)
+				metricsMock := metricsMocks.NewMockMetrics(t)
+				rd.On("Load", "metrics/mid").Return(metricsMock, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				metricsMock.On("Values").Return(map[string]interface{}{
+					"LatencyP99": 30 * time.Millisecond,
+					"Requests":   uint64(100),
+				})
+				metricsMock.On("String").Return("metrics").Maybe()
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -421,6 +528,14 @@ func Test_metricsAction_Execute(t *testing.T) {
 
 			tt.setupMocks(t, fndMock, ctx, dataMock)
 
+			var expr *expectations.Expr
+			if tt.expr != "" {
+				var err error
+				expr, err = expectations.CompileExpr(tt.expr, expectations.ExprVariableMetrics)
+				assert.NoError(t, err)
+				params["test"].(*parameterMocks.MockParameter).On("Type").Return(parameter.NilType)
+			}
+
 			a := &metricsAction{
 				CommonExpectation: &CommonExpectation{
 					fnd:     fndMock,
@@ -430,6 +545,7 @@ func Test_metricsAction_Execute(t *testing.T) {
 				MetricsExpectation: &expectations.MetricsExpectation{
 					Id:    tt.id,
 					Rules: tt.rules,
+					Expr:  expr,
 				},
 				parameters: params,
 			}
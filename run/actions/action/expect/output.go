@@ -24,17 +24,21 @@ import (
 	"github.com/wstool/wst/run/environments/environment/output"
 	"github.com/wstool/wst/run/expectations"
 	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/metrics"
 	"github.com/wstool/wst/run/parameters"
 	"github.com/wstool/wst/run/services"
+	"go.uber.org/zap"
 	"io"
 	"regexp"
 	"strings"
+	"time"
 )
 
 func (m *ExpectationActionMaker) MakeOutputAction(
 	config *types.OutputExpectationAction,
 	sl services.ServiceLocator,
 	defaultTimeout int,
+	actionMaker action.Maker,
 ) (action.Action, error) {
 	commonExpectation, err := m.MakeCommonExpectation(
 		sl, config.Service, config.Timeout, defaultTimeout, config.When, config.OnFailure)
@@ -47,10 +51,28 @@ func (m *ExpectationActionMaker) MakeOutputAction(
 		return nil, err
 	}
 
+	var actions action.Action
+	if len(config.Actions) > 0 {
+		if outputExpectation.Mode != expectations.OutputModeForbid {
+			return nil, errors.New("output expectation actions can be used only in forbid mode")
+		}
+		// The output is guarded in background while the inner actions are executed as a single sequential action.
+		actions, err = actionMaker.MakeAction(&types.SequentialAction{
+			Actions:   config.Actions,
+			Timeout:   int(commonExpectation.timeout.Milliseconds()),
+			When:      string(action.Always),
+			OnFailure: string(action.Fail),
+		}, sl, int(commonExpectation.timeout.Milliseconds()))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &outputAction{
 		CommonExpectation: commonExpectation,
 		OutputExpectation: outputExpectation,
 		parameters:        commonExpectation.service.ServerParameters(),
+		actions:           actions,
 	}, nil
 }
 
@@ -58,10 +80,12 @@ type outputAction struct {
 	*CommonExpectation
 	*expectations.OutputExpectation
 	parameters parameters.Parameters
+	// actions are the inner actions executed while the forbidden messages are checked or nil if not set.
+	actions action.Action
 }
 
 func (a *outputAction) getReader(ctx context.Context, runData runtime.Data) (io.Reader, error) {
-	outputType, err := a.getServiceOutputType(a.OutputType)
+	outputType, err := getServiceOutputType(a.OutputType)
 	if err != nil {
 		return nil, err
 	}
@@ -85,14 +109,76 @@ func (a *outputAction) getReader(ctx context.Context, runData runtime.Data) (io.
 	return oc.Reader(ctx, outputType)
 }
 
+// getFromTime returns the time of the output position to read from. It returns false if the output should be read
+// from the start.
+func (a *outputAction) getFromTime(runData runtime.Data) (time.Time, bool, error) {
+	switch a.From {
+	case "", expectations.OutputFromStart:
+		return time.Time{}, false, nil
+	case expectations.OutputFromNow:
+		start, ok := runData.PreviousActionStart()
+		if !ok {
+			return time.Time{}, false, errors.New("previous action position not found")
+		}
+		return start, true, nil
+	default:
+		data, ok := runData.Load(checkpointKey(a.From))
+		if !ok {
+			return time.Time{}, false, errors.Errorf("checkpoint %s not found", a.From)
+		}
+		checkpoint, ok := data.(time.Time)
+		if !ok {
+			return time.Time{}, false, errors.Errorf("invalid checkpoint %s data type", a.From)
+		}
+		return checkpoint, true, nil
+	}
+}
+
+// getCursorReader returns the reader of the output from the position set in the expectation.
+func (a *outputAction) getCursorReader(ctx context.Context, runData runtime.Data) (io.Reader, error) {
+	since, ok, err := a.getFromTime(runData)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return a.getReader(ctx, runData)
+	}
+	return a.getReaderSince(ctx, runData, since)
+}
+
+func checkpointKey(name string) string {
+	return fmt.Sprintf("checkpoint/%s", name)
+}
+
 func (a *outputAction) Execute(ctx context.Context, runData runtime.Data) (bool, error) {
+	var success bool
+	var err error
+	switch {
+	case a.Mode == expectations.OutputModeForbid:
+		success, err = a.executeForbid(ctx, runData)
+	case a.CountOperator != "":
+		success, err = a.executeCount(ctx, runData)
+	default:
+		success, err = a.executeExpect(ctx, runData)
+	}
+	if err != nil || !success || a.Checkpoint == "" {
+		return success, err
+	}
+	// The checkpoint is set after the read lines so the following expectations can continue from it.
+	if err = runData.Store(checkpointKey(a.Checkpoint), time.Now()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (a *outputAction) executeExpect(ctx context.Context, runData runtime.Data) (bool, error) {
 	logger := a.fnd.Logger()
 	logger.Infof("Executing expectation output action")
-	messages, err := a.renderMessages(a.Messages)
+	messages, err := a.renderMessages(a.Messages, runData)
 	if err != nil {
 		return false, err
 	}
-	reader, err := a.getReader(ctx, runData)
+	reader, err := a.getCursorReader(ctx, runData)
 	if err != nil {
 		return false, err
 	}
@@ -105,7 +191,7 @@ func (a *outputAction) Execute(ctx context.Context, runData runtime.Data) (bool,
 		if err != nil {
 			return false, err
 		}
-		if len(messages) == 0 {
+		if len(messages) == 0 && a.matchOutputExpr(lines, runData) {
 			return true, nil
 		}
 	}
@@ -123,6 +209,11 @@ func (a *outputAction) Execute(ctx context.Context, runData runtime.Data) (bool,
 		return false, scannerErr
 	}
 
+	// The expression is checked on the complete output too as it might not have any lines.
+	if a.Expr != nil && len(messages) == 0 && len(lines) == 0 && a.matchOutputExpr(lines, runData) {
+		return true, nil
+	}
+
 	if a.fnd.DryRun() {
 		return true, nil
 	}
@@ -130,7 +221,221 @@ func (a *outputAction) Execute(ctx context.Context, runData runtime.Data) (bool,
 	return false, nil
 }
 
-func (a *outputAction) getServiceOutputType(outputType expectations.OutputType) (output.Type, error) {
+// getReaderSince returns the independent reader of the output since the time so other readers are not affected.
+func (a *outputAction) getReaderSince(ctx context.Context, runData runtime.Data, since time.Time) (io.Reader, error) {
+	outputType, err := getServiceOutputType(a.OutputType)
+	if err != nil {
+		return nil, err
+	}
+	if a.Command == "" {
+		return a.service.OutputReaderSince(ctx, outputType, since)
+	}
+	data, ok := runData.Load(fmt.Sprintf("command/%s", a.Command))
+	if !ok {
+		return nil, errors.New("command data not found")
+	}
+	oc, ok := data.(output.Collector)
+	if !ok {
+		return nil, errors.New("invalid response data type")
+	}
+	return oc.ReaderSince(ctx, outputType, since)
+}
+
+// executeForbid checks that none of the messages appears in the output written from now for the duration or, if
+// there are inner actions, until the inner actions finish. It fails as soon as a forbidden message is found.
+func (a *outputAction) executeForbid(ctx context.Context, runData runtime.Data) (bool, error) {
+	logger := a.fnd.Logger()
+	logger.Infof("Executing forbidden output action")
+	messages, err := a.renderMessages(a.Messages, runData)
+	if err != nil {
+		return false, err
+	}
+
+	guardCtx, cancelGuard := context.WithCancel(ctx)
+	defer cancelGuard()
+	if a.actions == nil && a.Duration > 0 {
+		var cancel context.CancelFunc
+		guardCtx, cancel = a.runtimeMaker.MakeContextWithTimeout(guardCtx, a.Duration)
+		defer cancel()
+	}
+	since, ok, err := a.getFromTime(runData)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		since = time.Now()
+	}
+	reader, err := a.getReaderSince(guardCtx, runData, since)
+	if err != nil {
+		return false, err
+	}
+
+	if a.actions == nil {
+		line, found, err := a.findForbidden(guardCtx, reader, messages)
+		if err != nil {
+			return false, err
+		}
+		if found {
+			logger.Infof("Forbidden message found in line: %s", line)
+			return a.fnd.DryRun(), nil
+		}
+		return true, nil
+	}
+
+	actCtx, cancelActions := a.runtimeMaker.MakeContextWithTimeout(ctx, a.actions.Timeout())
+	defer cancelActions()
+	type guardResult struct {
+		line  string
+		found bool
+		err   error
+	}
+	done := make(chan guardResult, 1)
+	go func() {
+		line, found, err := a.findForbidden(guardCtx, reader, messages)
+		if found || err != nil {
+			// The inner actions are stopped as the result is already known.
+			cancelActions()
+		}
+		done <- guardResult{line: line, found: found, err: err}
+	}()
+
+	success, actErr := a.actions.Execute(actCtx, runData)
+	cancelGuard()
+	result := <-done
+
+	if result.err != nil {
+		return false, result.err
+	}
+	if result.found {
+		logger.Infof("Forbidden message found in line: %s", result.line)
+		return a.fnd.DryRun(), nil
+	}
+	return success, actErr
+}
+
+// findForbidden reads the output until the context is done or the reader is closed and returns the first line
+// matching any of the forbidden messages.
+func (a *outputAction) findForbidden(ctx context.Context, reader io.Reader, messages []string) (string, bool, error) {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, message := range messages {
+			matched, err := a.matchMessage(line, message)
+			if err != nil {
+				return "", false, err
+			}
+			if matched {
+				return line, true, nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return "", false, err
+	}
+	return "", false, nil
+}
+
+// executeCount counts the lines matching each message until the output ends, the duration elapses or the result cannot
+// change anymore. Each count is then compared with the expected count using the count operator.
+func (a *outputAction) executeCount(ctx context.Context, runData runtime.Data) (bool, error) {
+	logger := a.fnd.Logger()
+	logger.Infof("Executing expectation output count action")
+	messages, err := a.renderMessages(a.Messages, runData)
+	if err != nil {
+		return false, err
+	}
+
+	readCtx := ctx
+	if a.Duration > 0 {
+		var cancel context.CancelFunc
+		readCtx, cancel = a.runtimeMaker.MakeContextWithTimeout(ctx, a.Duration)
+		defer cancel()
+	}
+	reader, err := a.getCursorReader(readCtx, runData)
+	if err != nil {
+		return false, err
+	}
+
+	counts := make([]int, len(messages))
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for i, message := range messages {
+			matched, err := a.matchMessage(line, message)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				counts[i]++
+			}
+		}
+		if decided, result := a.countsResult(messages, counts, false); decided {
+			return result || a.fnd.DryRun(), nil
+		}
+	}
+	// The end of the reading window is not an error as the counts are just final.
+	if err := scanner.Err(); err != nil && readCtx.Err() == nil {
+		return false, err
+	}
+
+	_, result := a.countsResult(messages, counts, true)
+	return result || a.fnd.DryRun(), nil
+}
+
+// countsResult returns whether the result is decided and the result. If the counts are not complete, the result is
+// decided only if more matching lines cannot change it.
+func (a *outputAction) countsResult(messages []string, counts []int, complete bool) (bool, bool) {
+	logger := a.fnd.Logger()
+	allMatched := true
+	for i, count := range counts {
+		matched, err := metrics.GenericMetric[int]{Value: count}.Compare(a.CountOperator, float64(a.Count))
+		if err == nil && matched {
+			continue
+		}
+		allMatched = false
+		switch {
+		case complete:
+		case err != nil:
+		case a.CountOperator == metrics.MetricEqOperator ||
+			a.CountOperator == metrics.MetricLeOperator ||
+			a.CountOperator == metrics.MetricLtOperator:
+			// The counts only grow so exceeding the expected count cannot be undone.
+			if count < a.Count {
+				continue
+			}
+		default:
+			continue
+		}
+		logger.Infof("Message %s matched %d lines which is not %s %d", messages[i], count, a.CountOperator, a.Count)
+		return true, false
+	}
+	// The greater operators cannot stop matching with more lines so the result is known as soon as all counts match.
+	if allMatched && (complete ||
+		a.CountOperator == metrics.MetricGtOperator ||
+		a.CountOperator == metrics.MetricGeOperator) {
+		return true, true
+	}
+	return false, false
+}
+
+// matchOutputExpr evaluates the expression over the lines read so far. It matches if there is no expression.
+func (a *outputAction) matchOutputExpr(lines []string, runData runtime.Data) bool {
+	if a.Expr == nil {
+		return true
+	}
+	if lines == nil {
+		lines = []string{}
+	}
+	variables := map[string]interface{}{
+		expectations.ExprVariableOutput: map[string]interface{}{
+			"lines": lines,
+			"text":  strings.Join(lines, "\n"),
+		},
+	}
+	return a.matchExpr(a.Expr, variables, renderParameters(runData, a.parameters))
+}
+
+func getServiceOutputType(outputType expectations.OutputType) (output.Type, error) {
 	switch outputType {
 	case expectations.OutputTypeStdout:
 		return output.Stdout, nil
@@ -143,13 +448,17 @@ func (a *outputAction) getServiceOutputType(outputType expectations.OutputType)
 	}
 }
 
-func (a *outputAction) renderMessages(messages []string) ([]string, error) {
+func (a *outputAction) renderMessages(messages []string, runData runtime.Data) ([]string, error) {
 	if !a.RenderTemplate {
 		return messages, nil
 	}
+	return renderMessages(a.service, messages, renderParameters(runData, a.parameters))
+}
+
+func renderMessages(svc services.Service, messages []string, params parameters.Parameters) ([]string, error) {
 	var renderedMessages []string
 	for _, message := range messages {
-		renderedMessage, err := a.service.RenderTemplate(message, a.parameters)
+		renderedMessage, err := svc.RenderTemplate(message, params)
 		if err != nil {
 			return nil, err
 		}
@@ -160,9 +469,24 @@ func (a *outputAction) renderMessages(messages []string) ([]string, error) {
 }
 
 func (a *outputAction) matchMessages(line string, messages []string) ([]string, error) {
-	if a.OrderType == expectations.OrderTypeFixed {
+	return matchMessages(a.fnd.Logger(), a.OrderType, a.MatchType, line, messages)
+}
+
+func (a *outputAction) matchMessage(line, message string) (bool, error) {
+	return matchMessage(a.fnd.Logger(), a.MatchType, line, message)
+}
+
+// matchMessages returns the messages that remain after matching the line in the order.
+func matchMessages(
+	logger *zap.SugaredLogger,
+	orderType expectations.OrderType,
+	matchType expectations.MatchType,
+	line string,
+	messages []string,
+) ([]string, error) {
+	if orderType == expectations.OrderTypeFixed {
 		if len(messages) > 0 {
-			matched, err := a.matchMessage(line, messages[0])
+			matched, err := matchMessage(logger, matchType, line, messages[0])
 			if err != nil {
 				return nil, err
 			}
@@ -170,9 +494,9 @@ func (a *outputAction) matchMessages(line string, messages []string) ([]string,
 				return messages[1:], nil
 			}
 		}
-	} else if a.OrderType == expectations.OrderTypeRandom {
+	} else if orderType == expectations.OrderTypeRandom {
 		for index, message := range messages {
-			matched, err := a.matchMessage(line, message)
+			matched, err := matchMessage(logger, matchType, line, message)
 			if err != nil {
 				return nil, err
 			}
@@ -181,15 +505,15 @@ func (a *outputAction) matchMessages(line string, messages []string) ([]string,
 			}
 		}
 	} else {
-		return nil, fmt.Errorf("unknown order type %s", string(a.OrderType))
+		return nil, fmt.Errorf("unknown order type %s", string(orderType))
 	}
 	return messages, nil
 }
 
-func (a *outputAction) matchMessage(line, message string) (bool, error) {
-	a.fnd.Logger().Debugf("Matching '%s' against line: %s (type: %s)", message, line, a.MatchType)
+func matchMessage(logger *zap.SugaredLogger, matchType expectations.MatchType, line, message string) (bool, error) {
+	logger.Debugf("Matching '%s' against line: %s (type: %s)", message, line, matchType)
 
-	switch a.MatchType {
+	switch matchType {
 	case expectations.MatchTypeExact:
 		return line == message, nil
 
@@ -206,6 +530,6 @@ func (a *outputAction) matchMessage(line, message string) (bool, error) {
 		return strings.Contains(line, message), nil
 
 	default:
-		return false, fmt.Errorf("unknown match type %s", string(a.MatchType))
+		return false, fmt.Errorf("unknown match type %s", string(matchType))
 	}
 }
@@ -4,9 +4,12 @@ import (
 	"context"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/wstool/wst/conf/types"
 	"github.com/wstool/wst/mocks/authored/external"
 	appMocks "github.com/wstool/wst/mocks/generated/app"
+	actionMocks "github.com/wstool/wst/mocks/generated/run/actions/action"
 	outputMocks "github.com/wstool/wst/mocks/generated/run/environments/environment/output"
 	expectationsMocks "github.com/wstool/wst/mocks/generated/run/expectations"
 	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
@@ -16,9 +19,14 @@ import (
 	"github.com/wstool/wst/run/actions/action"
 	"github.com/wstool/wst/run/environments/environment/output"
 	"github.com/wstool/wst/run/expectations"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/metrics"
 	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/parameters/parameter"
+	"io"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 )
 
@@ -179,7 +187,7 @@ func TestExpectationActionMaker_MakeOutputAction(t *testing.T) {
 
 			outputExpectation, serverParams := tt.setupMocks(t, slMock, svcMock, expectationsMakerMock, tt.config)
 
-			got, err := m.MakeOutputAction(tt.config, slMock, tt.defaultTimeout)
+			got, err := m.MakeOutputAction(tt.config, slMock, tt.defaultTimeout, actionMocks.NewMockMaker(t))
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -268,6 +276,103 @@ func Test_outputAction_Execute(t *testing.T) {
 			outputType: output.Stdout,
 			want:       true,
 		},
+		{
+			name: "expression matched after all messages found",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+				outputType output.Type,
+				runData *runtimeMocks.MockData,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				params["test"].(*parameterMocks.MockParameter).On("Type").Return(parameter.NilType)
+				runData.On("Parameters").Return(parameters.Parameters{})
+
+				collector := outputMocks.NewMockCollector(t)
+				collector.On("Reader", ctx, outputType).Return(strings.NewReader("first\nsecond\nthird"), nil)
+				runData.On("Load", "command/mycmd").Return(collector, true)
+			},
+			expectation: &expectations.OutputExpectation{
+				Command:    "mycmd",
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeExact,
+				OutputType: expectations.OutputTypeStdout,
+				Messages:   []string{"first"},
+				Expr: compileExpr(
+					t,
+					`size(output.lines) == 2 && output.text.endsWith("second")`,
+					expectations.ExprVariableOutput,
+				),
+			},
+			outputType: output.Stdout,
+			want:       true,
+		},
+		{
+			name: "expression matched on empty output",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+				outputType output.Type,
+				runData *runtimeMocks.MockData,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				params["test"].(*parameterMocks.MockParameter).On("Type").Return(parameter.NilType)
+				runData.On("Parameters").Return(parameters.Parameters{})
+
+				collector := outputMocks.NewMockCollector(t)
+				collector.On("Reader", ctx, outputType).Return(strings.NewReader(""), nil)
+				runData.On("Load", "command/mycmd").Return(collector, true)
+			},
+			expectation: &expectations.OutputExpectation{
+				Command:    "mycmd",
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeExact,
+				OutputType: expectations.OutputTypeStdout,
+				Expr:       compileExpr(t, `output.text == ""`, expectations.ExprVariableOutput),
+			},
+			outputType: output.Stdout,
+			want:       true,
+		},
+		{
+			name: "expression not matched",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+				outputType output.Type,
+				runData *runtimeMocks.MockData,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				fnd.On("DryRun").Return(false)
+				params["test"].(*parameterMocks.MockParameter).On("Type").Return(parameter.NilType)
+				runData.On("Parameters").Return(parameters.Parameters{})
+
+				collector := outputMocks.NewMockCollector(t)
+				collector.On("Reader", ctx, outputType).Return(strings.NewReader("first\nsecond"), nil)
+				runData.On("Load", "command/mycmd").Return(collector, true)
+			},
+			expectation: &expectations.OutputExpectation{
+				Command:    "mycmd",
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeExact,
+				OutputType: expectations.OutputTypeStdout,
+				Messages:   []string{"first"},
+				Expr:       compileExpr(t, `output.text.contains("third")`, expectations.ExprVariableOutput),
+			},
+			outputType: output.Stdout,
+			want:       false,
+		},
 		{
 			name: "error when command data not found",
 			setupMocks: func(
@@ -335,6 +440,7 @@ func Test_outputAction_Execute(t *testing.T) {
 			) {
 				mockLogger := external.NewMockLogger()
 				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				runData.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "test", params).Return("test tmp", nil)
 				r := strings.NewReader("test tmp")
 				svc.On("OutputReader", ctx, outputType).Return(r, nil)
@@ -844,6 +950,7 @@ func Test_outputAction_Execute(t *testing.T) {
 			) {
 				mockLogger := external.NewMockLogger()
 				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				runData.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "test", params).Return("", errors.New("render err"))
 			},
 			expectation: &expectations.OutputExpectation{
@@ -870,6 +977,7 @@ func Test_outputAction_Execute(t *testing.T) {
 			) {
 				mockLogger := external.NewMockLogger()
 				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				runData.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "test", params).Return("test tmp", nil)
 			},
 			expectation: &expectations.OutputExpectation{
@@ -964,3 +1072,816 @@ func Test_outputAction_OnFailure(t *testing.T) {
 	}
 	assert.Equal(t, action.Ignore, a.OnFailure())
 }
+
+func TestExpectationActionMaker_MakeOutputAction_Actions(t *testing.T) {
+	tests := []struct {
+		name             string
+		mode             expectations.OutputMode
+		actionMakerErr   error
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "successful output action creation with actions",
+			mode: expectations.OutputModeForbid,
+		},
+		{
+			name:             "failed output action creation with actions in expect mode",
+			mode:             expectations.OutputModeExpect,
+			expectError:      true,
+			expectedErrorMsg: "output expectation actions can be used only in forbid mode",
+		},
+		{
+			name:             "failed output action creation due to actions error",
+			mode:             expectations.OutputModeForbid,
+			actionMakerErr:   errors.New("actions failed"),
+			expectError:      true,
+			expectedErrorMsg: "actions failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			svcMock := servicesMocks.NewMockService(t)
+			expectationsMakerMock := expectationsMocks.NewMockMaker(t)
+			actionMakerMock := actionMocks.NewMockMaker(t)
+			actionsMock := actionMocks.NewMockAction(t)
+			m := &ExpectationActionMaker{
+				fnd:               fndMock,
+				expectationsMaker: expectationsMakerMock,
+			}
+			config := &types.OutputExpectationAction{
+				Service:   "svc",
+				When:      "on_success",
+				OnFailure: "fail",
+				Output: types.OutputExpectation{
+					Messages: []string{"exited on signal"},
+					Mode:     string(tt.mode),
+				},
+				Actions: []types.Action{&types.RequestAction{Service: "svc"}},
+			}
+			outputExpectation := &expectations.OutputExpectation{
+				Messages: []string{"exited on signal"},
+				Mode:     tt.mode,
+			}
+
+			slMock.On("Find", "svc").Return(svcMock, nil)
+			expectationsMakerMock.On("MakeOutputExpectation", &config.Output).Return(outputExpectation, nil)
+			if tt.mode == expectations.OutputModeForbid {
+				sequentialConfig := &types.SequentialAction{
+					Actions:   config.Actions,
+					Timeout:   4000,
+					When:      "always",
+					OnFailure: "fail",
+				}
+				if tt.actionMakerErr != nil {
+					actionMakerMock.On("MakeAction", sequentialConfig, slMock, 4000).Return(nil, tt.actionMakerErr)
+				} else {
+					actionMakerMock.On("MakeAction", sequentialConfig, slMock, 4000).Return(actionsMock, nil)
+					svcMock.On("ServerParameters").Return(parameters.Parameters{})
+				}
+			}
+
+			got, err := m.MakeOutputAction(config, slMock, 4000, actionMakerMock)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				actualAction, ok := got.(*outputAction)
+				assert.True(t, ok)
+				assert.Equal(t, actionsMock, actualAction.actions)
+				assert.Equal(t, outputExpectation, actualAction.OutputExpectation)
+			}
+		})
+	}
+}
+
+// contextReader returns the data and then blocks until the context is done.
+type contextReader struct {
+	ctx  context.Context
+	data *strings.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if r.data.Len() > 0 {
+		return r.data.Read(p)
+	}
+	<-r.ctx.Done()
+	return 0, r.ctx.Err()
+}
+
+func Test_outputAction_Execute_Forbid(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMocks func(
+			t *testing.T,
+			fnd *appMocks.MockFoundation,
+			ctx context.Context,
+			svc *servicesMocks.MockService,
+			rm *runtimeMocks.MockMaker,
+			runData *runtimeMocks.MockData,
+			actions *actionMocks.MockAction,
+		)
+		expectation      *expectations.OutputExpectation
+		withActions      bool
+		want             bool
+		expectErr        bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "no forbidden message found for duration",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData *runtimeMocks.MockData,
+				actions *actionMocks.MockAction,
+			) {
+				durationCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+				rm.On("MakeContextWithTimeout", mock.Anything, 50*time.Millisecond).Return(durationCtx, cancel)
+				svc.On("OutputReaderSince", durationCtx, output.Stderr, mock.AnythingOfType("time.Time")).Return(
+					func(ctx context.Context, outputType output.Type, since time.Time) (io.Reader, error) {
+						return &contextReader{ctx: ctx, data: strings.NewReader("pool www started\nready\n")}, nil
+					},
+				)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeInfix,
+				OutputType: expectations.OutputTypeStderr,
+				Messages:   []string{"exited on signal"},
+				Mode:       expectations.OutputModeForbid,
+				Duration:   50 * time.Millisecond,
+			},
+			want: true,
+		},
+		{
+			name: "forbidden message found in command output",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData *runtimeMocks.MockData,
+				actions *actionMocks.MockAction,
+			) {
+				fnd.On("DryRun").Return(false)
+				collector := outputMocks.NewMockCollector(t)
+				collector.On("ReaderSince", mock.Anything, output.Stdout, mock.AnythingOfType("time.Time")).Return(
+					strings.NewReader("ready\nWARNING: [pool www] child 12 exited on signal 11\n"), nil)
+				runData.On("Load", "command/mycmd").Return(collector, true)
+			},
+			expectation: &expectations.OutputExpectation{
+				Command:    "mycmd",
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeRegexp,
+				OutputType: expectations.OutputTypeStdout,
+				Messages:   []string{`child \d+ exited on signal 11`},
+				Mode:       expectations.OutputModeForbid,
+			},
+			want: false,
+		},
+		{
+			name: "forbidden message found in dry run",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData *runtimeMocks.MockData,
+				actions *actionMocks.MockAction,
+			) {
+				fnd.On("DryRun").Return(true)
+				svc.On("OutputReaderSince", mock.Anything, output.Any, mock.AnythingOfType("time.Time")).Return(
+					strings.NewReader("segfault\n"), nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeExact,
+				OutputType: expectations.OutputTypeAny,
+				Messages:   []string{"segfault"},
+				Mode:       expectations.OutputModeForbid,
+			},
+			want: true,
+		},
+		{
+			name: "error when output reader fails",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData *runtimeMocks.MockData,
+				actions *actionMocks.MockAction,
+			) {
+				svc.On("OutputReaderSince", mock.Anything, output.Any, mock.AnythingOfType("time.Time")).Return(
+					nil, errors.New("reader failed"))
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeExact,
+				OutputType: expectations.OutputTypeAny,
+				Messages:   []string{"segfault"},
+				Mode:       expectations.OutputModeForbid,
+			},
+			expectErr:        true,
+			expectedErrorMsg: "reader failed",
+		},
+		{
+			name: "error when reading fails",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData *runtimeMocks.MockData,
+				actions *actionMocks.MockAction,
+			) {
+				svc.On("OutputReaderSince", mock.Anything, output.Any, mock.AnythingOfType("time.Time")).Return(
+					iotest.ErrReader(errors.New("read failed")), nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeExact,
+				OutputType: expectations.OutputTypeAny,
+				Messages:   []string{"segfault"},
+				Mode:       expectations.OutputModeForbid,
+			},
+			expectErr:        true,
+			expectedErrorMsg: "read failed",
+		},
+		{
+			name: "error when message regexp is invalid",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData *runtimeMocks.MockData,
+				actions *actionMocks.MockAction,
+			) {
+				svc.On("OutputReaderSince", mock.Anything, output.Any, mock.AnythingOfType("time.Time")).Return(
+					strings.NewReader("line\n"), nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeRegexp,
+				OutputType: expectations.OutputTypeAny,
+				Messages:   []string{"[a-"},
+				Mode:       expectations.OutputModeForbid,
+			},
+			expectErr:        true,
+			expectedErrorMsg: "missing closing ]",
+		},
+		{
+			name: "no forbidden message found until actions finish",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData *runtimeMocks.MockData,
+				actions *actionMocks.MockAction,
+			) {
+				svc.On("OutputReaderSince", mock.Anything, output.Any, mock.AnythingOfType("time.Time")).Return(
+					func(ctx context.Context, outputType output.Type, since time.Time) (io.Reader, error) {
+						return &contextReader{ctx: ctx, data: strings.NewReader("ready\n")}, nil
+					},
+				)
+				actCtx, actCancel := context.WithCancel(ctx)
+				actions.On("Timeout").Return(2 * time.Second)
+				rm.On("MakeContextWithTimeout", ctx, 2*time.Second).Return(actCtx, actCancel)
+				actions.On("Execute", actCtx, runData).Return(true, nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeInfix,
+				OutputType: expectations.OutputTypeAny,
+				Messages:   []string{"exited on signal"},
+				Mode:       expectations.OutputModeForbid,
+			},
+			withActions: true,
+			want:        true,
+		},
+		{
+			name: "failed actions without forbidden message",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData *runtimeMocks.MockData,
+				actions *actionMocks.MockAction,
+			) {
+				svc.On("OutputReaderSince", mock.Anything, output.Any, mock.AnythingOfType("time.Time")).Return(
+					func(ctx context.Context, outputType output.Type, since time.Time) (io.Reader, error) {
+						return &contextReader{ctx: ctx, data: strings.NewReader("")}, nil
+					},
+				)
+				actCtx, actCancel := context.WithCancel(ctx)
+				actions.On("Timeout").Return(2 * time.Second)
+				rm.On("MakeContextWithTimeout", ctx, 2*time.Second).Return(actCtx, actCancel)
+				actions.On("Execute", actCtx, runData).Return(false, errors.New("action failed"))
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeInfix,
+				OutputType: expectations.OutputTypeAny,
+				Messages:   []string{"exited on signal"},
+				Mode:       expectations.OutputModeForbid,
+			},
+			withActions:      true,
+			expectErr:        true,
+			expectedErrorMsg: "action failed",
+		},
+		{
+			name: "forbidden message found stops actions",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData *runtimeMocks.MockData,
+				actions *actionMocks.MockAction,
+			) {
+				fnd.On("DryRun").Return(false)
+				svc.On("OutputReaderSince", mock.Anything, output.Any, mock.AnythingOfType("time.Time")).Return(
+					func(ctx context.Context, outputType output.Type, since time.Time) (io.Reader, error) {
+						return &contextReader{ctx: ctx, data: strings.NewReader("child 12 exited on signal 11\n")}, nil
+					},
+				)
+				actCtx, actCancel := context.WithCancel(ctx)
+				actions.On("Timeout").Return(2 * time.Second)
+				rm.On("MakeContextWithTimeout", ctx, 2*time.Second).Return(actCtx, actCancel)
+				actions.On("Execute", actCtx, runData).Run(func(args mock.Arguments) {
+					// The actions run until they are cancelled by the found forbidden message.
+					<-args.Get(0).(context.Context).Done()
+				}).Return(false, nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeInfix,
+				OutputType: expectations.OutputTypeAny,
+				Messages:   []string{"exited on signal"},
+				Mode:       expectations.OutputModeForbid,
+			},
+			withActions: true,
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+			dataMock := runtimeMocks.NewMockData(t)
+			svcMock := servicesMocks.NewMockService(t)
+			rmMock := runtimeMocks.NewMockMaker(t)
+			actionsMock := actionMocks.NewMockAction(t)
+			ctx := context.Background()
+
+			tt.setupMocks(t, fndMock, ctx, svcMock, rmMock, dataMock, actionsMock)
+
+			a := &outputAction{
+				CommonExpectation: &CommonExpectation{
+					fnd:          fndMock,
+					runtimeMaker: rmMock,
+					service:      svcMock,
+					timeout:      2 * time.Second,
+				},
+				OutputExpectation: tt.expectation,
+				parameters:        parameters.Parameters{},
+			}
+			if tt.withActions {
+				a.actions = actionsMock
+			}
+
+			got, err := a.Execute(ctx, dataMock)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.False(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_outputAction_Execute_Count(t *testing.T) {
+	previousStart := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	checkpoint := time.Date(2024, 1, 1, 10, 0, 5, 0, time.UTC)
+	tests := []struct {
+		name       string
+		setupMocks func(
+			t *testing.T,
+			fnd *appMocks.MockFoundation,
+			ctx context.Context,
+			svc *servicesMocks.MockService,
+			rm *runtimeMocks.MockMaker,
+			runData runtime.Data,
+		)
+		expectation      *expectations.OutputExpectation
+		want             bool
+		expectErr        bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "exact count matched when command output ends",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData runtime.Data,
+			) {
+				collector := outputMocks.NewMockCollector(t)
+				collector.On("Reader", ctx, output.Stdout).Return(
+					strings.NewReader("child 10 started\nready\nchild 11 started\n"), nil)
+				require.NoError(t, runData.Store("command/mycmd", collector))
+			},
+			expectation: &expectations.OutputExpectation{
+				Command:       "mycmd",
+				OrderType:     expectations.OrderTypeFixed,
+				MatchType:     expectations.MatchTypeRegexp,
+				OutputType:    expectations.OutputTypeStdout,
+				Messages:      []string{`child \d+ started`},
+				Count:         2,
+				CountOperator: metrics.MetricEqOperator,
+			},
+			want: true,
+		},
+		{
+			name: "exact count not matched when output ends",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData runtime.Data,
+			) {
+				fnd.On("DryRun").Return(false)
+				svc.On("OutputReader", ctx, output.Any).Return(strings.NewReader("child 10 started\nready\n"), nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:     expectations.OrderTypeFixed,
+				MatchType:     expectations.MatchTypeInfix,
+				OutputType:    expectations.OutputTypeAny,
+				Messages:      []string{"started", "ready"},
+				Count:         2,
+				CountOperator: metrics.MetricEqOperator,
+			},
+			want: false,
+		},
+		{
+			name: "exact count not matched in dry run",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData runtime.Data,
+			) {
+				fnd.On("DryRun").Return(true)
+				svc.On("OutputReader", ctx, output.Any).Return(strings.NewReader(""), nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:     expectations.OrderTypeFixed,
+				MatchType:     expectations.MatchTypeInfix,
+				OutputType:    expectations.OutputTypeAny,
+				Messages:      []string{"started"},
+				Count:         1,
+				CountOperator: metrics.MetricEqOperator,
+			},
+			want: true,
+		},
+		{
+			name: "exact count exceeded before output ends",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData runtime.Data,
+			) {
+				fnd.On("DryRun").Return(false)
+				svc.On("OutputReader", ctx, output.Any).Return(
+					&contextReader{ctx: ctx, data: strings.NewReader("exited on signal 9\nexited on signal 9\n")}, nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:     expectations.OrderTypeFixed,
+				MatchType:     expectations.MatchTypeInfix,
+				OutputType:    expectations.OutputTypeAny,
+				Messages:      []string{"exited on signal"},
+				Count:         1,
+				CountOperator: metrics.MetricEqOperator,
+			},
+			want: false,
+		},
+		{
+			name: "minimal count reached before output ends",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData runtime.Data,
+			) {
+				svc.On("OutputReader", ctx, output.Any).Return(
+					&contextReader{ctx: ctx, data: strings.NewReader("child 1 started\nchild 2 started\nchild 3 started\n")}, nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:     expectations.OrderTypeFixed,
+				MatchType:     expectations.MatchTypeRegexp,
+				OutputType:    expectations.OutputTypeAny,
+				Messages:      []string{`child \d+ started`},
+				Count:         2,
+				CountOperator: metrics.MetricGeOperator,
+			},
+			want: true,
+		},
+		{
+			name: "count compared after duration from now",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData runtime.Data,
+			) {
+				runData.MarkActionStart(previousStart)
+				runData.MarkActionStart(previousStart.Add(time.Second))
+				durationCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+				rm.On("MakeContextWithTimeout", ctx, 20*time.Millisecond).Return(durationCtx, cancel)
+				svc.On("OutputReaderSince", durationCtx, output.Any, previousStart).Return(
+					&contextReader{ctx: durationCtx, data: strings.NewReader("child 1 started\nready\n")}, nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:     expectations.OrderTypeFixed,
+				MatchType:     expectations.MatchTypePrefix,
+				OutputType:    expectations.OutputTypeAny,
+				Messages:      []string{"child"},
+				Duration:      20 * time.Millisecond,
+				From:          expectations.OutputFromNow,
+				Count:         0,
+				CountOperator: metrics.MetricNeOperator,
+			},
+			want: true,
+		},
+		{
+			name: "error when matching fails",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData runtime.Data,
+			) {
+				svc.On("OutputReader", ctx, output.Any).Return(strings.NewReader("line\n"), nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:     expectations.OrderTypeFixed,
+				MatchType:     expectations.MatchTypeRegexp,
+				OutputType:    expectations.OutputTypeAny,
+				Messages:      []string{"[a-"},
+				Count:         1,
+				CountOperator: metrics.MetricEqOperator,
+			},
+			expectErr:        true,
+			expectedErrorMsg: "missing closing ]",
+		},
+		{
+			name: "error when reading fails",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData runtime.Data,
+			) {
+				svc.On("OutputReader", ctx, output.Any).Return(iotest.ErrReader(errors.New("read failed")), nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:     expectations.OrderTypeFixed,
+				MatchType:     expectations.MatchTypeExact,
+				OutputType:    expectations.OutputTypeAny,
+				Messages:      []string{"ready"},
+				Count:         1,
+				CountOperator: metrics.MetricEqOperator,
+			},
+			expectErr:        true,
+			expectedErrorMsg: "read failed",
+		},
+		{
+			name: "messages matched from checkpoint",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData runtime.Data,
+			) {
+				require.NoError(t, runData.Store("checkpoint/ready", checkpoint))
+				svc.On("OutputReaderSince", ctx, output.Any, checkpoint).Return(
+					strings.NewReader("child 12 exited on signal 9\n"), nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeInfix,
+				OutputType: expectations.OutputTypeAny,
+				Messages:   []string{"exited on signal 9"},
+				From:       "ready",
+			},
+			want: true,
+		},
+		{
+			name: "error when checkpoint not found",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData runtime.Data,
+			) {
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeInfix,
+				OutputType: expectations.OutputTypeAny,
+				Messages:   []string{"ready"},
+				From:       "ready",
+			},
+			expectErr:        true,
+			expectedErrorMsg: "checkpoint ready not found",
+		},
+		{
+			name: "error when checkpoint has invalid type",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData runtime.Data,
+			) {
+				require.NoError(t, runData.Store("checkpoint/ready", "invalid"))
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeInfix,
+				OutputType: expectations.OutputTypeAny,
+				Messages:   []string{"ready"},
+				From:       "ready",
+			},
+			expectErr:        true,
+			expectedErrorMsg: "invalid checkpoint ready data type",
+		},
+		{
+			name: "error when previous action position not found",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData runtime.Data,
+			) {
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:     expectations.OrderTypeFixed,
+				MatchType:     expectations.MatchTypeInfix,
+				OutputType:    expectations.OutputTypeAny,
+				Messages:      []string{"ready"},
+				From:          expectations.OutputFromNow,
+				Count:         1,
+				CountOperator: metrics.MetricEqOperator,
+			},
+			expectErr:        true,
+			expectedErrorMsg: "previous action position not found",
+		},
+		{
+			name: "forbidden message not found from checkpoint",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				svc *servicesMocks.MockService,
+				rm *runtimeMocks.MockMaker,
+				runData runtime.Data,
+			) {
+				require.NoError(t, runData.Store("checkpoint/ready", checkpoint))
+				svc.On("OutputReaderSince", mock.Anything, output.Any, checkpoint).Return(
+					strings.NewReader("child 12 started\n"), nil)
+			},
+			expectation: &expectations.OutputExpectation{
+				OrderType:  expectations.OrderTypeFixed,
+				MatchType:  expectations.MatchTypeInfix,
+				OutputType: expectations.OutputTypeAny,
+				Messages:   []string{"exited on signal"},
+				Mode:       expectations.OutputModeForbid,
+				From:       "ready",
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+			runData := runtime.CreateMaker(fndMock).MakeData()
+			svcMock := servicesMocks.NewMockService(t)
+			rmMock := runtimeMocks.NewMockMaker(t)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			tt.setupMocks(t, fndMock, ctx, svcMock, rmMock, runData)
+
+			a := &outputAction{
+				CommonExpectation: &CommonExpectation{
+					fnd:          fndMock,
+					runtimeMaker: rmMock,
+					service:      svcMock,
+					timeout:      5 * time.Second,
+				},
+				OutputExpectation: tt.expectation,
+				parameters:        parameters.Parameters{},
+			}
+
+			got, err := a.Execute(ctx, runData)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.False(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_outputAction_Execute_Checkpoint(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	mockLogger := external.NewMockLogger()
+	fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+	runData := runtime.CreateMaker(fndMock).MakeData()
+	svcMock := servicesMocks.NewMockService(t)
+	ctx := context.Background()
+	svcMock.On("OutputReader", ctx, output.Any).Return(strings.NewReader("ready\n"), nil)
+
+	a := &outputAction{
+		CommonExpectation: &CommonExpectation{
+			fnd:     fndMock,
+			service: svcMock,
+			timeout: 5 * time.Second,
+		},
+		OutputExpectation: &expectations.OutputExpectation{
+			OrderType:  expectations.OrderTypeFixed,
+			MatchType:  expectations.MatchTypeExact,
+			OutputType: expectations.OutputTypeAny,
+			Messages:   []string{"ready"},
+			Checkpoint: "ready",
+		},
+		parameters: parameters.Parameters{},
+	}
+
+	before := time.Now()
+	got, err := a.Execute(ctx, runData)
+	require.NoError(t, err)
+	assert.True(t, got)
+
+	value, ok := runData.Load("checkpoint/ready")
+	require.True(t, ok)
+	checkpoint, ok := value.(time.Time)
+	require.True(t, ok)
+	assert.False(t, checkpoint.Before(before))
+}
@@ -0,0 +1,278 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expect
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/environments/environment"
+	"github.com/wstool/wst/run/expectations"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/metrics"
+	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/services"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (m *ExpectationActionMaker) MakeProcessAction(
+	config *types.ProcessExpectationAction,
+	sl services.ServiceLocator,
+	defaultTimeout int,
+) (action.Action, error) {
+	commonExpectation, err := m.MakeCommonExpectation(
+		sl, config.Service, config.Timeout, defaultTimeout, config.When, config.OnFailure)
+	if err != nil {
+		return nil, err
+	}
+
+	processExpectation, err := m.expectationsMaker.MakeProcessExpectation(&config.Process)
+	if err != nil {
+		return nil, err
+	}
+
+	return &processAction{
+		CommonExpectation:  commonExpectation,
+		ProcessExpectation: processExpectation,
+		parameters:         commonExpectation.service.ServerParameters(),
+	}, nil
+}
+
+type processAction struct {
+	*CommonExpectation
+	*expectations.ProcessExpectation
+	parameters parameters.Parameters
+}
+
+// processCheckpointKey returns the runtime data key for the processes stored under the checkpoint name.
+func processCheckpointKey(name string) string {
+	return fmt.Sprintf("processes/%s", name)
+}
+
+// processId identifies the process in the checkpoint. Pids are unique only within the instance (e.g. pod) of the
+// task so the instance is part of the identifier.
+type processId struct {
+	instance string
+	pid      int
+}
+
+func (p processId) String() string {
+	if p.instance == "" {
+		return strconv.Itoa(p.pid)
+	}
+	return fmt.Sprintf("%s/%d", p.instance, p.pid)
+}
+
+func compareProcessIds(a, b processId) int {
+	if c := strings.Compare(a.instance, b.instance); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.pid, b.pid)
+}
+
+// processIds returns the sorted identifiers of the processes.
+func processIds(processes []environment.Process) []processId {
+	ids := make([]processId, 0, len(processes))
+	for _, process := range processes {
+		ids = append(ids, processId{instance: process.Instance, pid: process.Pid})
+	}
+	slices.SortFunc(ids, compareProcessIds)
+	return ids
+}
+
+func (a *processAction) Execute(ctx context.Context, runData runtime.Data) (bool, error) {
+	logger := a.fnd.Logger()
+	logger.Infof("Executing expectation process action")
+	if a.fnd.DryRun() {
+		logger.Debugf("Skipping process checks in dry run")
+		return true, nil
+	}
+
+	processes, err := a.service.Processes(ctx)
+	if err != nil {
+		return false, err
+	}
+	selected := a.selectProcesses(processes)
+	ids := processIds(selected)
+	logger.Debugf("Checking processes %v in scope %s", ids, a.Scope)
+
+	for _, metric := range a.Metrics {
+		matched, err := a.matchMetric(&metric, selected)
+		if err != nil {
+			return false, err
+		}
+		if !matched {
+			logger.Infof("Process metric %s did not match", metric.Metric)
+			return false, nil
+		}
+	}
+
+	if a.Pids != expectations.ProcessPidsAny {
+		matched, err := a.matchPids(runData, ids)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	// Evaluate expression over the selected processes.
+	if a.Expr != nil {
+		variables := map[string]interface{}{
+			expectations.ExprVariableProcess: processExprValue(selected),
+		}
+		if !a.matchExpr(a.Expr, variables, renderParameters(runData, a.parameters)) {
+			return false, nil
+		}
+	}
+
+	if a.Checkpoint != "" {
+		if err = runData.Store(processCheckpointKey(a.Checkpoint), ids); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// selectProcesses returns the processes in the scope whose name matches the name pattern. The scope is relative to
+// the main process of the same task instance.
+func (a *processAction) selectProcesses(processes []environment.Process) []environment.Process {
+	mainPids := make(map[string]int)
+	for _, process := range processes {
+		if process.Main {
+			mainPids[process.Instance] = process.Pid
+		}
+	}
+	var selected []environment.Process
+	for _, process := range processes {
+		var inScope bool
+		switch a.Scope {
+		case expectations.ProcessScopeMain:
+			inScope = process.Main
+		case expectations.ProcessScopeChildren:
+			mainPid, ok := mainPids[process.Instance]
+			inScope = !process.Main && ok && process.PPid == mainPid
+		case expectations.ProcessScopeDescendants:
+			inScope = !process.Main
+		default:
+			inScope = true
+		}
+		if inScope && (a.Name == nil || a.Name.MatchString(process.Name)) {
+			selected = append(selected, process)
+		}
+	}
+	return selected
+}
+
+// processExprValue returns the expression variable value with the count and the list of the processes. The fds and
+// uptime fields are omitted if they could not be read.
+func processExprValue(processes []environment.Process) map[string]interface{} {
+	values := make([]interface{}, 0, len(processes))
+	for _, process := range processes {
+		value := map[string]interface{}{
+			"pid":      process.Pid,
+			"ppid":     process.PPid,
+			"name":     process.Name,
+			"instance": process.Instance,
+			"main":     process.Main,
+			"rss":      process.RSS,
+		}
+		if process.FDsKnown {
+			value["fds"] = process.FDs
+		}
+		if process.UptimeKnown {
+			value["uptime"] = process.Uptime
+		}
+		values = append(values, value)
+	}
+	return map[string]interface{}{
+		"count":     len(processes),
+		"processes": values,
+	}
+}
+
+// matchMetric compares the number of processes or the aggregated value of their metric with the expected value.
+func (a *processAction) matchMetric(metric *expectations.ProcessMetric, processes []environment.Process) (bool, error) {
+	logger := a.fnd.Logger()
+	if metric.Metric == expectations.ProcessMetricCount {
+		logger.Debugf("Comparing processes count %d %s %v", len(processes), metric.Operator, metric.Value)
+		return metrics.GenericMetric[int]{Value: len(processes)}.Compare(metric.Operator, metric.Value)
+	}
+	if len(processes) == 0 {
+		logger.Infof("No processes selected for metric %s", metric.Metric)
+		return false, nil
+	}
+	values := make([]float64, 0, len(processes))
+	for _, process := range processes {
+		switch metric.Metric {
+		case expectations.ProcessMetricRSS:
+			values = append(values, float64(process.RSS))
+		case expectations.ProcessMetricFDs:
+			if !process.FDsKnown {
+				return false, errors.Errorf("process %d %s metric is not available", process.Pid, metric.Metric)
+			}
+			values = append(values, float64(process.FDs))
+		case expectations.ProcessMetricUptime:
+			if !process.UptimeKnown {
+				return false, errors.Errorf("process %d %s metric is not available", process.Pid, metric.Metric)
+			}
+			values = append(values, float64(process.Uptime)/float64(time.Millisecond))
+		default:
+			return false, errors.Errorf("invalid process metric %s", metric.Metric)
+		}
+	}
+	value, err := metrics.Aggregate(metric.Aggregation, values)
+	if err != nil {
+		return false, err
+	}
+	logger.Debugf("Comparing %s of processes %s %v %s %v", metric.Aggregation, metric.Metric, value,
+		metric.Operator, metric.Value)
+	return metrics.GenericMetric[float64]{Value: value}.Compare(metric.Operator, metric.Value)
+}
+
+// matchPids compares the sorted process identifiers with the ones stored in the since checkpoint. The changed check
+// requires that none of the checkpoint processes is still selected and the unchanged check requires the same
+// processes.
+func (a *processAction) matchPids(runData runtime.Data, ids []processId) (bool, error) {
+	data, ok := runData.Load(processCheckpointKey(a.Since))
+	if !ok {
+		return false, errors.Errorf("process checkpoint %s not found", a.Since)
+	}
+	checkpointIds, ok := data.([]processId)
+	if !ok {
+		return false, errors.Errorf("invalid process checkpoint %s data type", a.Since)
+	}
+	a.fnd.Logger().Debugf("Comparing processes %v with checkpoint %s processes %v", ids, a.Since, checkpointIds)
+	switch a.Pids {
+	case expectations.ProcessPidsChanged:
+		for _, id := range checkpointIds {
+			if slices.Contains(ids, id) {
+				a.fnd.Logger().Infof("Process %s from checkpoint %s is still running", id, a.Since)
+				return false, nil
+			}
+		}
+	case expectations.ProcessPidsUnchanged:
+		if !slices.Equal(ids, checkpointIds) {
+			a.fnd.Logger().Infof("Processes %v differ from checkpoint %s processes %v", ids, a.Since, checkpointIds)
+			return false, nil
+		}
+	}
+	return true, nil
+}
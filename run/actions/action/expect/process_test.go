@@ -0,0 +1,590 @@
+package expect
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	expectationsMocks "github.com/wstool/wst/mocks/generated/run/expectations"
+	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/environments/environment"
+	"github.com/wstool/wst/run/expectations"
+	"github.com/wstool/wst/run/metrics"
+	"github.com/wstool/wst/run/parameters"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestExpectationActionMaker_MakeProcessAction(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           *types.ProcessExpectationAction
+		setupMocks       func(*servicesMocks.MockServiceLocator, *servicesMocks.MockService, *expectationsMocks.MockMaker)
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "successful process action creation",
+			config: &types.ProcessExpectationAction{
+				Service:   "svc",
+				When:      "on_success",
+				OnFailure: "fail",
+				Process: types.ProcessExpectation{
+					Scope:      "children",
+					Checkpoint: "before",
+				},
+			},
+			setupMocks: func(
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				em *expectationsMocks.MockMaker,
+			) {
+				sl.On("Find", "svc").Return(svc, nil)
+				svc.On("ServerParameters").Return(parameters.Parameters{})
+				em.On("MakeProcessExpectation", mock.Anything).Return(&expectations.ProcessExpectation{
+					Scope:      expectations.ProcessScopeChildren,
+					Checkpoint: "before",
+					Pids:       expectations.ProcessPidsAny,
+				}, nil)
+			},
+		},
+		{
+			name: "failed process action creation because no service found",
+			config: &types.ProcessExpectationAction{
+				Service: "invalid",
+			},
+			setupMocks: func(
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				em *expectationsMocks.MockMaker,
+			) {
+				sl.On("Find", "invalid").Return(nil, errors.New("svc not found"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "svc not found",
+		},
+		{
+			name: "failed process action creation because process expectation creation failed",
+			config: &types.ProcessExpectationAction{
+				Service: "svc",
+			},
+			setupMocks: func(
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				em *expectationsMocks.MockMaker,
+			) {
+				sl.On("Find", "svc").Return(svc, nil)
+				em.On("MakeProcessExpectation", mock.Anything).Return(nil, errors.New("invalid process scope: x"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "invalid process scope: x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			svcMock := servicesMocks.NewMockService(t)
+			expectationsMakerMock := expectationsMocks.NewMockMaker(t)
+			m := &ExpectationActionMaker{
+				fnd:               fndMock,
+				expectationsMaker: expectationsMakerMock,
+			}
+			tt.setupMocks(slMock, svcMock, expectationsMakerMock)
+
+			got, err := m.MakeProcessAction(tt.config, slMock, 5000)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				actualAction, ok := got.(*processAction)
+				require.True(t, ok)
+				assert.Equal(t, "before", actualAction.Checkpoint)
+				assert.Equal(t, 5*time.Second, actualAction.Timeout())
+				assert.Equal(t, action.OnSuccess, actualAction.When())
+				assert.Equal(t, action.Fail, actualAction.OnFailure())
+			}
+		})
+	}
+}
+
+func Test_processAction_Execute(t *testing.T) {
+	processes := []environment.Process{
+		{Pid: 10, PPid: 1, Name: "php-fpm: master process", Main: true, RSS: 8 << 20, FDs: 10, FDsKnown: true,
+			Uptime: 60 * time.Second, UptimeKnown: true},
+		{Pid: 11, PPid: 10, Name: "php-fpm: pool www", RSS: 20 << 20, FDs: 6, FDsKnown: true,
+			Uptime: 5 * time.Second, UptimeKnown: true},
+		{Pid: 12, PPid: 10, Name: "php-fpm: pool www", RSS: 40 << 20, FDs: 8, FDsKnown: true,
+			Uptime: 3 * time.Second, UptimeKnown: true},
+		{Pid: 13, PPid: 10, Name: "php-fpm: pool api", RSS: 30 << 20, FDs: 7, FDsKnown: true,
+			Uptime: 4 * time.Second, UptimeKnown: true},
+		{Pid: 14, PPid: 11, Name: "sh", RSS: 1 << 20, FDs: 3, FDsKnown: true, Uptime: 1 * time.Second, UptimeKnown: true},
+	}
+	podProcesses := []environment.Process{
+		{Pid: 1, PPid: 0, Name: "php-fpm", Instance: "p1", Main: true},
+		{Pid: 7, PPid: 1, Name: "php-fpm", Instance: "p1"},
+		{Pid: 1, PPid: 0, Name: "php-fpm", Instance: "p2", Main: true},
+		{Pid: 7, PPid: 1, Name: "php-fpm", Instance: "p2"},
+	}
+	unknownProcesses := []environment.Process{
+		{Pid: 10, PPid: 1, Name: "php-fpm: master process", Main: true, RSS: 8 << 20},
+	}
+	tests := []struct {
+		name             string
+		expectation      *expectations.ProcessExpectation
+		dryRun           bool
+		setupMocks       func(*servicesMocks.MockService, *runtimeMocks.MockData)
+		want             bool
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "children count and name match",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Name:  regexp.MustCompile("^php-fpm: pool www$"),
+				Metrics: []expectations.ProcessMetric{
+					{Metric: expectations.ProcessMetricCount, Operator: metrics.MetricEqOperator, Value: 2},
+				},
+				Pids: expectations.ProcessPidsAny,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+			},
+			want: true,
+		},
+		{
+			name: "children of main processes in multiple pods match",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Metrics: []expectations.ProcessMetric{
+					{Metric: expectations.ProcessMetricCount, Operator: metrics.MetricEqOperator, Value: 2},
+				},
+				Pids: expectations.ProcessPidsAny,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return([]environment.Process{
+					{Pid: 1, PPid: 0, Name: "php-fpm", Instance: "p1", Main: true},
+					{Pid: 7, PPid: 1, Name: "php-fpm", Instance: "p1"},
+					{Pid: 8, PPid: 7, Name: "sh", Instance: "p1"},
+					{Pid: 1, PPid: 0, Name: "php-fpm", Instance: "p2", Main: true},
+					{Pid: 7, PPid: 1, Name: "php-fpm", Instance: "p2"},
+				}, nil)
+			},
+			want: true,
+		},
+		{
+			name: "descendants count does not match",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeDescendants,
+				Metrics: []expectations.ProcessMetric{
+					{Metric: expectations.ProcessMetricCount, Operator: metrics.MetricEqOperator, Value: 3},
+				},
+				Pids: expectations.ProcessPidsAny,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+			},
+			want: false,
+		},
+		{
+			name: "aggregated metrics match",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Metrics: []expectations.ProcessMetric{
+					{
+						Metric:      expectations.ProcessMetricRSS,
+						Aggregation: metrics.AggregationMax,
+						Operator:    metrics.MetricLtOperator,
+						Value:       64 << 20,
+					},
+					{
+						Metric:      expectations.ProcessMetricFDs,
+						Aggregation: metrics.AggregationAvg,
+						Operator:    metrics.MetricEqOperator,
+						Value:       7,
+					},
+					{
+						Metric:      expectations.ProcessMetricUptime,
+						Aggregation: metrics.AggregationMin,
+						Operator:    metrics.MetricGeOperator,
+						Value:       3000,
+					},
+				},
+				Pids: expectations.ProcessPidsAny,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+			},
+			want: true,
+		},
+		{
+			name: "main process rss does not match",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeMain,
+				Metrics: []expectations.ProcessMetric{
+					{
+						Metric:      expectations.ProcessMetricRSS,
+						Aggregation: metrics.AggregationMax,
+						Operator:    metrics.MetricLtOperator,
+						Value:       4 << 20,
+					},
+				},
+				Pids: expectations.ProcessPidsAny,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+			},
+			want: false,
+		},
+		{
+			name: "metric without selected processes does not match",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeAll,
+				Name:  regexp.MustCompile("nginx"),
+				Metrics: []expectations.ProcessMetric{
+					{
+						Metric:      expectations.ProcessMetricFDs,
+						Aggregation: metrics.AggregationMax,
+						Operator:    metrics.MetricLtOperator,
+						Value:       100,
+					},
+				},
+				Pids: expectations.ProcessPidsAny,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+			},
+			want: false,
+		},
+		{
+			name: "unavailable fds metric fails",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeMain,
+				Metrics: []expectations.ProcessMetric{
+					{
+						Metric:      expectations.ProcessMetricFDs,
+						Aggregation: metrics.AggregationMax,
+						Operator:    metrics.MetricLtOperator,
+						Value:       100,
+					},
+				},
+				Pids: expectations.ProcessPidsAny,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(unknownProcesses, nil)
+			},
+			expectError:      true,
+			expectedErrorMsg: "process 10 fds metric is not available",
+		},
+		{
+			name: "unavailable uptime metric fails",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeMain,
+				Metrics: []expectations.ProcessMetric{
+					{
+						Metric:      expectations.ProcessMetricUptime,
+						Aggregation: metrics.AggregationMin,
+						Operator:    metrics.MetricGeOperator,
+						Value:       0,
+					},
+				},
+				Pids: expectations.ProcessPidsAny,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(unknownProcesses, nil)
+			},
+			expectError:      true,
+			expectedErrorMsg: "process 10 uptime metric is not available",
+		},
+		{
+			name: "expression over unavailable metric does not match",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeMain,
+				Pids:  expectations.ProcessPidsAny,
+				Expr: compileExpr(
+					t,
+					"process.processes.all(p, p.fds < 100)",
+					expectations.ExprVariableProcess,
+				),
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(unknownProcesses, nil)
+				rd.On("Parameters").Return(parameters.Parameters{})
+			},
+			want: false,
+		},
+		{
+			name: "expression over selected processes matches",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Pids:  expectations.ProcessPidsAny,
+				Expr: compileExpr(
+					t,
+					"process.count == 3 && process.processes.all(p, p.fds < 10 && p.uptime < duration('10s'))",
+					expectations.ExprVariableProcess,
+				),
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+				rd.On("Parameters").Return(parameters.Parameters{})
+			},
+			want: true,
+		},
+		{
+			name: "expression over selected processes does not match",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Pids:  expectations.ProcessPidsAny,
+				Expr: compileExpr(
+					t,
+					"process.processes.all(p, p.rss < 32 * 1024 * 1024)",
+					expectations.ExprVariableProcess,
+				),
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+				rd.On("Parameters").Return(parameters.Parameters{})
+			},
+			want: false,
+		},
+		{
+			name: "checkpoint is stored",
+			expectation: &expectations.ProcessExpectation{
+				Scope:      expectations.ProcessScopeChildren,
+				Checkpoint: "before",
+				Pids:       expectations.ProcessPidsAny,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+				rd.On("Store", "processes/before", []processId{{pid: 11}, {pid: 12}, {pid: 13}}).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name: "pids changed since checkpoint",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Since: "before",
+				Pids:  expectations.ProcessPidsChanged,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+				rd.On("Load", "processes/before").Return([]processId{{pid: 5}, {pid: 6}, {pid: 7}}, true)
+			},
+			want: true,
+		},
+		{
+			name: "pids not changed since checkpoint",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Since: "before",
+				Pids:  expectations.ProcessPidsChanged,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+				rd.On("Load", "processes/before").Return([]processId{{pid: 5}, {pid: 12}}, true)
+			},
+			want: false,
+		},
+		{
+			name: "pids unchanged since checkpoint",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Since: "before",
+				Pids:  expectations.ProcessPidsUnchanged,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+				rd.On("Load", "processes/before").Return([]processId{{pid: 11}, {pid: 12}, {pid: 13}}, true)
+			},
+			want: true,
+		},
+		{
+			name: "pids changed when unchanged expected",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Since: "before",
+				Pids:  expectations.ProcessPidsUnchanged,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+				rd.On("Load", "processes/before").Return([]processId{{pid: 11}, {pid: 12}}, true)
+			},
+			want: false,
+		},
+		{
+			name: "checkpoint of processes in multiple pods is stored",
+			expectation: &expectations.ProcessExpectation{
+				Scope:      expectations.ProcessScopeChildren,
+				Checkpoint: "before",
+				Pids:       expectations.ProcessPidsAny,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(podProcesses, nil)
+				rd.On("Store", "processes/before", []processId{
+					{instance: "p1", pid: 7},
+					{instance: "p2", pid: 7},
+				}).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name: "pids changed since checkpoint when same pid is in other pod",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Since: "before",
+				Pids:  expectations.ProcessPidsChanged,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(podProcesses, nil)
+				rd.On("Load", "processes/before").Return([]processId{
+					{instance: "p1", pid: 8},
+					{instance: "p3", pid: 7},
+				}, true)
+			},
+			want: true,
+		},
+		{
+			name: "pids not changed since checkpoint in one of pods",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Since: "before",
+				Pids:  expectations.ProcessPidsChanged,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(podProcesses, nil)
+				rd.On("Load", "processes/before").Return([]processId{
+					{instance: "p1", pid: 8},
+					{instance: "p2", pid: 7},
+				}, true)
+			},
+			want: false,
+		},
+		{
+			name: "pids unchanged since checkpoint in multiple pods",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Since: "before",
+				Pids:  expectations.ProcessPidsUnchanged,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(podProcesses, nil)
+				rd.On("Load", "processes/before").Return([]processId{
+					{instance: "p1", pid: 7},
+					{instance: "p2", pid: 7},
+				}, true)
+			},
+			want: true,
+		},
+		{
+			name: "pids changed when process moved to other pod and unchanged expected",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Since: "before",
+				Pids:  expectations.ProcessPidsUnchanged,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(podProcesses, nil)
+				rd.On("Load", "processes/before").Return([]processId{
+					{instance: "p1", pid: 7},
+					{instance: "p3", pid: 7},
+				}, true)
+			},
+			want: false,
+		},
+		{
+			name: "invalid checkpoint data type",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Since: "before",
+				Pids:  expectations.ProcessPidsChanged,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+				rd.On("Load", "processes/before").Return([]int{11, 12}, true)
+			},
+			expectError:      true,
+			expectedErrorMsg: "invalid process checkpoint before data type",
+		},
+		{
+			name: "missing checkpoint",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Since: "before",
+				Pids:  expectations.ProcessPidsChanged,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(processes, nil)
+				rd.On("Load", "processes/before").Return(nil, false)
+			},
+			expectError:      true,
+			expectedErrorMsg: "process checkpoint before not found",
+		},
+		{
+			name: "processes listing error",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Pids:  expectations.ProcessPidsAny,
+			},
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {
+				svc.On("Processes", mock.Anything).Return(nil, errors.New("service has not started yet"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "service has not started yet",
+		},
+		{
+			name: "dry run",
+			expectation: &expectations.ProcessExpectation{
+				Scope: expectations.ProcessScopeChildren,
+				Pids:  expectations.ProcessPidsAny,
+			},
+			dryRun:     true,
+			setupMocks: func(svc *servicesMocks.MockService, rd *runtimeMocks.MockData) {},
+			want:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			svcMock := servicesMocks.NewMockService(t)
+			dataMock := runtimeMocks.NewMockData(t)
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+			fndMock.On("DryRun").Return(tt.dryRun)
+			tt.setupMocks(svcMock, dataMock)
+
+			a := &processAction{
+				CommonExpectation: &CommonExpectation{
+					fnd:     fndMock,
+					service: svcMock,
+					timeout: 20 * 1e6,
+				},
+				ProcessExpectation: tt.expectation,
+			}
+
+			got, err := a.Execute(context.Background(), dataMock)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.False(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expect
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/expectations"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/servers/mockserver"
+	"github.com/wstool/wst/run/services"
+	"regexp"
+	"strings"
+)
+
+func (m *ExpectationActionMaker) MakeReceivedAction(
+	config *types.ReceivedExpectationAction,
+	sl services.ServiceLocator,
+	defaultTimeout int,
+) (action.Action, error) {
+	commonExpectation, err := m.MakeCommonExpectation(
+		sl, config.Service, config.Timeout, defaultTimeout, config.When, config.OnFailure)
+	if err != nil {
+		return nil, err
+	}
+
+	receivedExpectation, err := m.expectationsMaker.MakeReceivedExpectation(&config.Received)
+	if err != nil {
+		return nil, err
+	}
+
+	return &receivedAction{
+		CommonExpectation:   commonExpectation,
+		ReceivedExpectation: receivedExpectation,
+		parameters:          commonExpectation.service.ServerParameters(),
+	}, nil
+}
+
+type receivedAction struct {
+	*CommonExpectation
+	*expectations.ReceivedExpectation
+	parameters parameters.Parameters
+}
+
+func (a *receivedAction) Execute(_ context.Context, runData runtime.Data) (bool, error) {
+	a.fnd.Logger().Infof("Executing expectation received action")
+	// The received requests are recorded in the runtime data under the service key.
+	data, ok := runData.Load(mockserver.RequestsDataKey(a.service.Name()))
+	if !ok {
+		return false, errors.Errorf("received requests not found for service %s", a.service.Name())
+	}
+	receivedRequests, ok := data.(*mockserver.Requests)
+	if !ok {
+		return false, errors.Errorf("invalid received requests data type for service %s", a.service.Name())
+	}
+
+	content, err := a.renderBodyContent(runData)
+	if err != nil {
+		return false, err
+	}
+
+	var requests []mockserver.Request
+	for _, req := range receivedRequests.All() {
+		matched, err := a.matchRequest(&req, content)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			requests = append(requests, req)
+		}
+	}
+	count := len(requests)
+	a.fnd.Logger().Debugf("Found %d received requests matching the expectation", count)
+
+	if (a.Count < 0 && count == 0) || (a.Count >= 0 && count != a.Count) {
+		a.fnd.Logger().Infof("Received requests count %d did not match expected count %d", count, a.Count)
+		return a.fnd.DryRun(), nil
+	}
+
+	// Evaluate expression over the matching requests.
+	if a.Expr != nil {
+		variables := map[string]interface{}{
+			expectations.ExprVariableReceived: receivedExprValue(requests),
+		}
+		if !a.matchExpr(a.Expr, variables, renderParameters(runData, a.parameters)) {
+			return a.fnd.DryRun(), nil
+		}
+	}
+
+	return true, nil
+}
+
+// receivedExprValue returns the expression variable value with the count and the list of the matching requests.
+func receivedExprValue(requests []mockserver.Request) map[string]interface{} {
+	values := make([]interface{}, 0, len(requests))
+	for _, req := range requests {
+		headers := make(map[string][]string, len(req.Headers))
+		for name, headerValues := range req.Headers {
+			headers[name] = headerValues
+		}
+		values = append(values, map[string]interface{}{
+			"method":  req.Method,
+			"path":    req.Path,
+			"query":   req.Query,
+			"headers": headers,
+			"body":    req.Body,
+			"route":   req.Route,
+		})
+	}
+	return map[string]interface{}{
+		"count":    len(requests),
+		"requests": values,
+	}
+}
+
+func (a *receivedAction) matchRequest(req *mockserver.Request, content string) (bool, error) {
+	if a.Method != "" && !strings.EqualFold(req.Method, a.Method) {
+		return false, nil
+	}
+	if a.Path != "" && req.Path != a.Path {
+		return false, nil
+	}
+	for key, expectedValue := range a.Headers {
+		if req.Headers.Get(key) != expectedValue {
+			return false, nil
+		}
+	}
+
+	// Empty body content means that the body is not checked.
+	if a.BodyContent == "" {
+		return true, nil
+	}
+	switch a.BodyMatch {
+	case expectations.MatchTypeExact:
+		return req.Body == content, nil
+	case expectations.MatchTypeRegexp:
+		return regexp.MatchString(content, req.Body)
+	case expectations.MatchTypePrefix:
+		return strings.HasPrefix(req.Body, content), nil
+	case expectations.MatchTypeSuffix:
+		return strings.HasSuffix(req.Body, content), nil
+	case expectations.MatchTypeInfix:
+		return strings.Contains(req.Body, content), nil
+	}
+
+	return true, nil
+}
+
+func (a *receivedAction) renderBodyContent(runData runtime.Data) (string, error) {
+	if a.BodyRenderTemplate && a.BodyContent != "" {
+		content, err := a.service.RenderTemplate(a.BodyContent, renderParameters(runData, a.parameters))
+		if err != nil {
+			return "", err
+		}
+		return content, nil
+	}
+
+	return a.BodyContent, nil
+}
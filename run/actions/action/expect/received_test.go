@@ -0,0 +1,428 @@
+package expect
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	expectationsMocks "github.com/wstool/wst/mocks/generated/run/expectations"
+	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
+	parametersMocks "github.com/wstool/wst/mocks/generated/run/parameters"
+	parameterMocks "github.com/wstool/wst/mocks/generated/run/parameters/parameter"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/expectations"
+	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/servers/mockserver"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpectationActionMaker_MakeReceivedAction(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         *types.ReceivedExpectationAction
+		defaultTimeout int
+		setupMocks     func(
+			*testing.T,
+			*servicesMocks.MockServiceLocator,
+			*servicesMocks.MockService,
+			*expectationsMocks.MockMaker,
+			*types.ReceivedExpectationAction,
+		) (*expectations.ReceivedExpectation, parameters.Parameters)
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "successful received action creation",
+			config: &types.ReceivedExpectationAction{
+				Service:   "backend",
+				When:      "on_success",
+				OnFailure: "ignore",
+				Received: types.ReceivedExpectation{
+					Path:  "/api",
+					Count: 1,
+				},
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				expectationMaker *expectationsMocks.MockMaker,
+				config *types.ReceivedExpectationAction,
+			) (*expectations.ReceivedExpectation, parameters.Parameters) {
+				serverParams := parameters.Parameters{
+					"param": parameterMocks.NewMockParameter(t),
+				}
+				sl.On("Find", "backend").Return(svc, nil)
+				receivedExpectation := &expectations.ReceivedExpectation{
+					Path:  "/api",
+					Count: 1,
+				}
+				expectationMaker.On("MakeReceivedExpectation", &config.Received).Return(receivedExpectation, nil)
+				svc.On("ServerParameters").Return(serverParams)
+				return receivedExpectation, serverParams
+			},
+		},
+		{
+			name: "failed received action creation because no service found",
+			config: &types.ReceivedExpectationAction{
+				Service: "invalid",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				expectationMaker *expectationsMocks.MockMaker,
+				config *types.ReceivedExpectationAction,
+			) (*expectations.ReceivedExpectation, parameters.Parameters) {
+				sl.On("Find", "invalid").Return(nil, errors.New("svc not found"))
+				return nil, nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "svc not found",
+		},
+		{
+			name: "failed received action creation because expectation creation failed",
+			config: &types.ReceivedExpectationAction{
+				Service: "backend",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				expectationMaker *expectationsMocks.MockMaker,
+				config *types.ReceivedExpectationAction,
+			) (*expectations.ReceivedExpectation, parameters.Parameters) {
+				sl.On("Find", "backend").Return(svc, nil)
+				expectationMaker.On("MakeReceivedExpectation", &config.Received).Return(nil, errors.New("received failed"))
+				return nil, nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "received failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			svcMock := servicesMocks.NewMockService(t)
+			expectationsMakerMock := expectationsMocks.NewMockMaker(t)
+			m := &ExpectationActionMaker{
+				fnd:               fndMock,
+				parametersMaker:   parametersMocks.NewMockMaker(t),
+				expectationsMaker: expectationsMakerMock,
+			}
+
+			receivedExpectation, serverParams := tt.setupMocks(t, slMock, svcMock, expectationsMakerMock, tt.config)
+
+			got, err := m.MakeReceivedAction(tt.config, slMock, tt.defaultTimeout)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				expectedAction := &receivedAction{
+					CommonExpectation: &CommonExpectation{
+						fnd:       fndMock,
+						service:   svcMock,
+						timeout:   5000 * 1e6,
+						when:      action.OnSuccess,
+						onFailure: action.Ignore,
+					},
+					ReceivedExpectation: receivedExpectation,
+					parameters:          serverParams,
+				}
+				assert.Equal(t, expectedAction, got)
+			}
+		})
+	}
+}
+
+func testMockRequests(t *testing.T) *mockserver.Requests {
+	server := mockserver.NewServer(external.NewMockLogger().SugaredLogger, &mockserver.Config{})
+	received := &mockserver.Requests{}
+	server.SetRequests(received)
+	requests := []struct {
+		method string
+		path   string
+		xff    string
+		body   string
+	}{
+		{method: "GET", path: "/api/users", xff: "10.0.0.1"},
+		{method: "POST", path: "/api/users", xff: "10.0.0.1", body: `{"name":"test"}`},
+		{method: "GET", path: "/api/users", xff: "10.0.0.2"},
+		{method: "GET", path: "/health"},
+	}
+	for _, r := range requests {
+		req := httptest.NewRequest(r.method, r.path, strings.NewReader(r.body))
+		if r.xff != "" {
+			req.Header.Set("X-Forwarded-For", r.xff)
+		}
+		server.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	return received
+}
+
+func Test_receivedAction_Execute(t *testing.T) {
+	tests := []struct {
+		name             string
+		setupMocks       func(*testing.T, *appMocks.MockFoundation, *runtimeMocks.MockData, *servicesMocks.MockService)
+		expectation      *expectations.ReceivedExpectation
+		want             bool
+		expectErr        bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "matched count of requests with path and header",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return(testMockRequests(t), true)
+			},
+			expectation: &expectations.ReceivedExpectation{
+				Path:    "/api/users",
+				Count:   2,
+				Headers: types.Headers{"X-Forwarded-For": "10.0.0.1"},
+			},
+			want: true,
+		},
+		{
+			name: "matched at least one request with method",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return(testMockRequests(t), true)
+			},
+			expectation: &expectations.ReceivedExpectation{
+				Method: "post",
+				Count:  -1,
+			},
+			want: true,
+		},
+		{
+			name: "matched request with rendered body",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return(testMockRequests(t), true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				svc.On("RenderTemplate", "{{ .Name }}", parameters.Parameters{}).Return(`"name":"test"`, nil)
+			},
+			expectation: &expectations.ReceivedExpectation{
+				Count:              1,
+				BodyContent:        "{{ .Name }}",
+				BodyMatch:          expectations.MatchTypeInfix,
+				BodyRenderTemplate: true,
+			},
+			want: true,
+		},
+		{
+			name: "matched request with body pattern",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return(testMockRequests(t), true)
+			},
+			expectation: &expectations.ReceivedExpectation{
+				Count:       1,
+				BodyContent: `^\{"name":".*"}$`,
+				BodyMatch:   expectations.MatchTypeRegexp,
+			},
+			want: true,
+		},
+		{
+			name: "matched no request as expected",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return(testMockRequests(t), true)
+			},
+			expectation: &expectations.ReceivedExpectation{
+				Path:  "/admin",
+				Count: 0,
+			},
+			want: true,
+		},
+		{
+			name: "matched requests with expression",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return(testMockRequests(t), true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+			},
+			expectation: &expectations.ReceivedExpectation{
+				Path:  "/api/users",
+				Count: -1,
+				Expr: compileExpr(
+					t,
+					"received.count == 3 && received.requests.exists(r, r.method == 'POST' && r.body.contains('test'))",
+					expectations.ExprVariableReceived,
+				),
+			},
+			want: true,
+		},
+		{
+			name: "unmatched requests expression",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return(testMockRequests(t), true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				fnd.On("DryRun").Return(false)
+			},
+			expectation: &expectations.ReceivedExpectation{
+				Path:  "/api/users",
+				Count: -1,
+				Expr: compileExpr(
+					t,
+					"received.requests.all(r, r.headers['X-Forwarded-For'][0] == '10.0.0.1')",
+					expectations.ExprVariableReceived,
+				),
+			},
+			want: false,
+		},
+		{
+			name: "unmatched count of requests",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return(testMockRequests(t), true)
+				fnd.On("DryRun").Return(false)
+			},
+			expectation: &expectations.ReceivedExpectation{
+				Path:  "/api/users",
+				Count: 1,
+			},
+			want: false,
+		},
+		{
+			name: "unmatched any request",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return(testMockRequests(t), true)
+				fnd.On("DryRun").Return(false)
+			},
+			expectation: &expectations.ReceivedExpectation{
+				Method:      "GET",
+				Count:       -1,
+				BodyContent: "data",
+				BodyMatch:   expectations.MatchTypeExact,
+			},
+			want: false,
+		},
+		{
+			name: "unmatched request in dry run",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return(testMockRequests(t), true)
+				fnd.On("DryRun").Return(true)
+			},
+			expectation: &expectations.ReceivedExpectation{
+				Path:  "/admin",
+				Count: -1,
+			},
+			want: true,
+		},
+		{
+			name: "invalid body pattern",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return(testMockRequests(t), true)
+			},
+			expectation: &expectations.ReceivedExpectation{
+				Count:       1,
+				BodyContent: "(",
+				BodyMatch:   expectations.MatchTypeRegexp,
+			},
+			expectErr:        true,
+			expectedErrorMsg: "error parsing regexp",
+		},
+		{
+			name: "failed body rendering",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return(testMockRequests(t), true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				svc.On("RenderTemplate", "{{", parameters.Parameters{}).Return("", errors.New("render failed"))
+			},
+			expectation: &expectations.ReceivedExpectation{
+				BodyContent:        "{{",
+				BodyMatch:          expectations.MatchTypeExact,
+				BodyRenderTemplate: true,
+			},
+			expectErr:        true,
+			expectedErrorMsg: "render failed",
+		},
+		{
+			name: "received requests not found",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return(nil, false)
+			},
+			expectation:      &expectations.ReceivedExpectation{},
+			expectErr:        true,
+			expectedErrorMsg: "received requests not found for service backend",
+		},
+		{
+			name: "invalid received requests data type",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, rd *runtimeMocks.MockData, svc *servicesMocks.MockService) {
+				rd.On("Load", "mock/backend/requests").Return("invalid", true)
+			},
+			expectation:      &expectations.ReceivedExpectation{},
+			expectErr:        true,
+			expectedErrorMsg: "invalid received requests data type for service backend",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			runDataMock := runtimeMocks.NewMockData(t)
+			svcMock := servicesMocks.NewMockService(t)
+			fndMock.On("Logger").Return(external.NewMockLogger().SugaredLogger)
+			svcMock.On("Name").Maybe().Return("backend")
+			params := parameters.Parameters{}
+
+			tt.setupMocks(t, fndMock, runDataMock, svcMock)
+
+			a := &receivedAction{
+				CommonExpectation: &CommonExpectation{
+					fnd:     fndMock,
+					service: svcMock,
+				},
+				ReceivedExpectation: tt.expectation,
+				parameters:          params,
+			}
+
+			got, err := a.Execute(context.Background(), runDataMock)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+				assert.False(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_receivedAction_Timeout(t *testing.T) {
+	timeout := time.Duration(50 * 1e6)
+	a := &receivedAction{
+		CommonExpectation: &CommonExpectation{
+			timeout: timeout,
+		},
+	}
+	assert.Equal(t, timeout, a.Timeout())
+}
+
+func Test_receivedAction_When(t *testing.T) {
+	a := &receivedAction{
+		CommonExpectation: &CommonExpectation{
+			when: action.OnSuccess,
+		},
+	}
+	assert.Equal(t, action.OnSuccess, a.When())
+}
+
+func Test_receivedAction_OnFailure(t *testing.T) {
+	a := &receivedAction{
+		CommonExpectation: &CommonExpectation{
+			onFailure: action.Skip,
+		},
+	}
+	assert.Equal(t, action.Skip, a.OnFailure())
+}
@@ -16,17 +16,27 @@ package expect
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/afero"
 	"github.com/wstool/wst/conf/types"
 	"github.com/wstool/wst/run/actions/action"
 	"github.com/wstool/wst/run/actions/action/request"
 	"github.com/wstool/wst/run/expectations"
 	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/metrics"
 	"github.com/wstool/wst/run/parameters"
 	"github.com/wstool/wst/run/services"
+	"github.com/xeipuuv/gojsonschema"
+	"net/http"
+	"reflect"
 	"regexp"
 	"strings"
+	"time"
 )
 
 func (m *ExpectationActionMaker) MakeResponseAction(
@@ -58,18 +68,38 @@ type responseAction struct {
 	parameters parameters.Parameters
 }
 
-func (a *responseAction) Execute(_ context.Context, runData runtime.Data) (bool, error) {
-	a.fnd.Logger().Infof("Executing expectation output action")
-	data, ok := runData.Load(fmt.Sprintf("response/%s", a.Request))
+// loadResponseData loads the stored response data of the request whose id can be a template.
+func (a *CommonExpectation) loadResponseData(
+	runData runtime.Data,
+	requestId string,
+	params parameters.Parameters,
+) (request.ResponseData, error) {
+	if strings.Contains(requestId, "{{") {
+		var err error
+		requestId, err = a.service.RenderTemplate(requestId, renderParameters(runData, params))
+		if err != nil {
+			return request.ResponseData{}, err
+		}
+	}
+	data, ok := runData.Load(fmt.Sprintf("response/%s", requestId))
 	if !ok {
-		return false, errors.New("response data not found")
+		return request.ResponseData{}, errors.New("response data not found")
 	}
 
 	responseData, ok := data.(request.ResponseData)
 	if !ok {
-		return false, errors.New("invalid response data type")
+		return request.ResponseData{}, errors.New("invalid response data type")
+	}
+	a.fnd.Logger().Debugf("Checking response %s data: %v", requestId, responseData)
+	return responseData, nil
+}
+
+func (a *responseAction) Execute(_ context.Context, runData runtime.Data) (bool, error) {
+	a.fnd.Logger().Infof("Executing expectation output action")
+	responseData, err := a.loadResponseData(runData, a.Request, a.parameters)
+	if err != nil {
+		return false, err
 	}
-	a.fnd.Logger().Debugf("Checking response %s data: %v", a.Request, responseData)
 
 	noMatchResult := false
 	if a.fnd.DryRun() {
@@ -86,18 +116,74 @@ func (a *responseAction) Execute(_ context.Context, runData runtime.Data) (bool,
 		}
 	}
 
+	// Compare negotiated protocol.
+	if a.Proto != "" {
+		a.fnd.Logger().Debugf("Comparing protocol %s against expected protocol %s", responseData.Proto, a.Proto)
+		if responseData.Proto != a.Proto {
+			a.fnd.Logger().Infof("Protocol did not match")
+			return noMatchResult, nil
+		}
+	}
+
+	// Compare negotiated TLS details.
+	if a.TLS != nil && !a.matchTLS(responseData.TLS) {
+		a.fnd.Logger().Infof("TLS details did not match")
+		return noMatchResult, nil
+	}
+
+	// Check connection reuse.
+	switch a.Connection {
+	case expectations.ConnectionTypeReused:
+		if !responseData.ConnectionReused {
+			a.fnd.Logger().Infof("Connection was not reused")
+			return noMatchResult, nil
+		}
+	case expectations.ConnectionTypeNew:
+		if responseData.ConnectionReused {
+			a.fnd.Logger().Infof("Connection was reused")
+			return noMatchResult, nil
+		}
+	}
+
+	// Compare timings and body size.
+	for _, metric := range a.Metrics {
+		matched, err := a.matchMetric(&metric, &responseData)
+		if err != nil {
+			return false, err
+		}
+		if !matched {
+			a.fnd.Logger().Infof("Response metric %s did not match", metric.Metric)
+			return noMatchResult, nil
+		}
+	}
+
 	// Compare headers.
-	for key, expectedValue := range a.Headers {
-		value, ok := responseData.Headers[key]
-		a.fnd.Logger().Debugf("Comparing header %s with value %s against expected value %s",
-			key, value, expectedValue)
-		if !ok || (len(value) > 0 && value[0] != expectedValue) {
+	for _, header := range a.Headers {
+		if !a.matchHeader(&header, responseData.Headers) {
 			a.fnd.Logger().Infof("Headers did not match")
 			return noMatchResult, nil
 		}
 	}
 
-	content, err := a.renderBodyContent()
+	// Compare content encoding.
+	if a.Encoding != "" {
+		encoding := strings.ToLower(responseData.ContentEncoding)
+		if encoding == "" {
+			encoding = "identity"
+		}
+		a.fnd.Logger().Debugf("Comparing content encoding %s against expected encoding %s", encoding, a.Encoding)
+		if encoding != a.Encoding {
+			a.fnd.Logger().Infof("Content encoding did not match")
+			return noMatchResult, nil
+		}
+	}
+
+	body := responseData.Body
+	if a.BodyRaw {
+		body = responseData.Raw()
+	}
+
+	content, err := a.renderBodyContent(runData)
 	if err != nil {
 		return false, err
 	}
@@ -105,14 +191,14 @@ func (a *responseAction) Execute(_ context.Context, runData runtime.Data) (bool,
 	// Compare body content based on bodyMatch.
 	switch a.BodyMatch {
 	case expectations.MatchTypeExact:
-		a.fnd.Logger().Debugf("Matching body %s with expected content %s", responseData.Body, content)
-		if responseData.Body != content {
+		a.fnd.Logger().Debugf("Matching body %s with expected content %s", body, content)
+		if body != content {
 			a.fnd.Logger().Infof("Body did not exactly match")
 			return noMatchResult, nil
 		}
 	case expectations.MatchTypeRegexp:
-		a.fnd.Logger().Debugf("Matching body %s with expected pattern %s", responseData.Body, content)
-		matched, err := regexp.MatchString(content, responseData.Body)
+		a.fnd.Logger().Debugf("Matching body %s with expected pattern %s", body, content)
+		matched, err := regexp.MatchString(content, body)
 		if err != nil {
 			return noMatchResult, err
 		}
@@ -121,31 +207,404 @@ func (a *responseAction) Execute(_ context.Context, runData runtime.Data) (bool,
 			return noMatchResult, nil
 		}
 	case expectations.MatchTypePrefix:
-		a.fnd.Logger().Debugf("Matching body %s with expected prefix %s", responseData.Body, content)
-		if !strings.HasPrefix(responseData.Body, content) {
+		a.fnd.Logger().Debugf("Matching body %s with expected prefix %s", body, content)
+		if !strings.HasPrefix(body, content) {
 			a.fnd.Logger().Infof("Body did not match the prefix")
 			return noMatchResult, nil
 		}
 	case expectations.MatchTypeSuffix:
-		a.fnd.Logger().Debugf("Matching body %s with expected suffix %s", responseData.Body, content)
-		if !strings.HasSuffix(responseData.Body, content) {
+		a.fnd.Logger().Debugf("Matching body %s with expected suffix %s", body, content)
+		if !strings.HasSuffix(body, content) {
 			a.fnd.Logger().Infof("Body did not match the suffix")
 			return noMatchResult, nil
 		}
 	case expectations.MatchTypeInfix:
-		a.fnd.Logger().Debugf("Matching body %s with expected infix %s", responseData.Body, content)
-		if !strings.Contains(responseData.Body, content) {
+		a.fnd.Logger().Debugf("Matching body %s with expected infix %s", body, content)
+		if !strings.Contains(body, content) {
 			a.fnd.Logger().Infof("Body did not contain the expected content")
 			return noMatchResult, nil
 		}
 	}
 
+	// Compare binary body by digest or file content.
+	if a.BodySHA256 != "" {
+		digest := sha256Hex(body)
+		a.fnd.Logger().Debugf("Comparing body SHA-256 %s against expected SHA-256 %s", digest, a.BodySHA256)
+		if digest != a.BodySHA256 {
+			a.fnd.Logger().Infof("Body SHA-256 did not match")
+			return noMatchResult, nil
+		}
+	}
+	if a.BodyFile != "" {
+		matched, err := a.matchBodyFile(body)
+		if err != nil {
+			return false, err
+		}
+		if !matched {
+			return noMatchResult, nil
+		}
+	}
+
+	// Check body decoded as JSON.
+	if a.JSON != nil {
+		matched, err := a.matchJSON(responseData.Body)
+		if err != nil {
+			return false, err
+		}
+		if !matched {
+			return noMatchResult, nil
+		}
+	}
+
+	// Evaluate expression over the response.
+	if a.Expr != nil {
+		variables := map[string]interface{}{
+			expectations.ExprVariableResponse: responseExprValue(responseData),
+		}
+		if !a.matchExpr(a.Expr, variables, renderParameters(runData, a.parameters)) {
+			return noMatchResult, nil
+		}
+	}
+
+	return true, nil
+}
+
+// headerValues returns all values of the header with the name matched case-insensitively.
+func headerValues(headers http.Header, name string) ([]string, bool) {
+	var values []string
+	found := false
+	for key, keyValues := range headers {
+		if strings.EqualFold(key, name) {
+			values = append(values, keyValues...)
+			found = true
+		}
+	}
+	return values, found
+}
+
+func (a *responseAction) matchHeader(header *expectations.HeaderExpectation, headers http.Header) bool {
+	values, found := headerValues(headers, header.Name)
+	a.fnd.Logger().Debugf("Comparing header %s with values %v against expected values %v (match: %s, mode: %s)",
+		header.Name, values, header.Values, header.Match, header.Mode)
+
+	if header.Absent {
+		if found {
+			a.fnd.Logger().Infof("Header %s is present but it should be absent", header.Name)
+			return false
+		}
+		return true
+	}
+	if !found {
+		a.fnd.Logger().Infof("Header %s is not present", header.Name)
+		return false
+	}
+
+	if header.Count >= 0 {
+		matched, err := metrics.GenericMetric[int]{Value: len(values)}.Compare(header.CountOperator, float64(header.Count))
+		if err != nil || !matched {
+			a.fnd.Logger().Infof("Header %s values count %d is not %s %d",
+				header.Name, len(values), header.CountOperator, header.Count)
+			return false
+		}
+	}
+
+	if len(header.Values) == 0 {
+		return true
+	}
+
+	if header.Mode == expectations.HeaderModeAll {
+		for _, value := range values {
+			if !a.matchHeaderValues(header, value) {
+				a.fnd.Logger().Infof("Header %s value %s does not match any expected value", header.Name, value)
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := range header.Values {
+		matched := false
+		for _, value := range values {
+			if a.matchHeaderValue(header, i, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			a.fnd.Logger().Infof("Header %s expected value %s does not match any value", header.Name, header.Values[i])
+			return false
+		}
+	}
+	return true
+}
+
+// matchHeaderValues checks whether the value matches at least one of the expected values.
+func (a *responseAction) matchHeaderValues(header *expectations.HeaderExpectation, value string) bool {
+	for i := range header.Values {
+		if a.matchHeaderValue(header, i, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHeaderValue checks whether the value matches the expected value with the index i.
+func (a *responseAction) matchHeaderValue(header *expectations.HeaderExpectation, i int, value string) bool {
+	if header.Match == expectations.MatchTypeRegexp {
+		return header.Patterns[i].MatchString(value)
+	}
+	expected := header.Values[i]
+	if header.IgnoreCase {
+		expected = strings.ToLower(expected)
+		value = strings.ToLower(value)
+	}
+	switch header.Match {
+	case expectations.MatchTypePrefix:
+		return strings.HasPrefix(value, expected)
+	case expectations.MatchTypeSuffix:
+		return strings.HasSuffix(value, expected)
+	case expectations.MatchTypeInfix:
+		return strings.Contains(value, expected)
+	default:
+		return value == expected
+	}
+}
+
+// responseExprValue returns the response variable for the expression. The json field is null if the body is not
+// a valid JSON.
+func responseExprValue(responseData request.ResponseData) map[string]interface{} {
+	var data interface{}
+	if err := json.Unmarshal([]byte(responseData.Body), &data); err != nil {
+		data = nil
+	}
+	headers := make(map[string][]string, len(responseData.Headers))
+	for name, values := range responseData.Headers {
+		headers[name] = values
+	}
+	return map[string]interface{}{
+		"status":   responseData.StatusCode,
+		"headers":  headers,
+		"body":     responseData.Body,
+		"json":     data,
+		"size":     responseData.BodySize,
+		"encoding": responseData.ContentEncoding,
+		"timing": map[string]interface{}{
+			"dns":           responseData.Timing.DNS,
+			"connect":       responseData.Timing.Connect,
+			"tls_handshake": responseData.Timing.TLSHandshake,
+			"ttfb":          responseData.Timing.TTFB,
+			"total":         responseData.Timing.Total,
+		},
+	}
+}
+
+// matchMetric compares the response timing in milliseconds or the body size in bytes with the expected value.
+func (a *responseAction) matchMetric(
+	metric *expectations.ResponseMetric,
+	responseData *request.ResponseData,
+) (bool, error) {
+	var duration time.Duration
+	switch metric.Metric {
+	case expectations.ResponseMetricDNS:
+		duration = responseData.Timing.DNS
+	case expectations.ResponseMetricConnect:
+		duration = responseData.Timing.Connect
+	case expectations.ResponseMetricTLSHandshake:
+		duration = responseData.Timing.TLSHandshake
+	case expectations.ResponseMetricTTFB:
+		duration = responseData.Timing.TTFB
+	case expectations.ResponseMetricTotal:
+		duration = responseData.Timing.Total
+	case expectations.ResponseMetricBodySize:
+		a.fnd.Logger().Debugf("Comparing body size %d %s %v", responseData.BodySize, metric.Operator, metric.Value)
+		return metrics.GenericMetric[int]{Value: responseData.BodySize}.Compare(metric.Operator, metric.Value)
+	default:
+		return false, fmt.Errorf("invalid response metric %s", metric.Metric)
+	}
+	value := float64(duration) / float64(time.Millisecond)
+	a.fnd.Logger().Debugf("Comparing %s time %vms %s %vms", metric.Metric, value, metric.Operator, metric.Value)
+	return metrics.GenericMetric[float64]{Value: value}.Compare(metric.Operator, metric.Value)
+}
+
+func (a *responseAction) matchJSON(body string) (bool, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		a.fnd.Logger().Infof("Body is not a valid JSON: %v", err)
+		return false, nil
+	}
+
+	if a.JSON.Schema != "" {
+		matched, err := a.matchJSONSchema(data)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	for _, check := range a.JSON.Checks {
+		matched, err := a.matchJSONCheck(&check, data)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (a *responseAction) matchJSONSchema(data interface{}) (bool, error) {
+	script, err := a.service.FindScript(a.JSON.Schema)
+	if err != nil {
+		return false, err
+	}
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(script.Content()))
+	if err != nil {
+		return false, fmt.Errorf("invalid JSON schema %s: %v", a.JSON.Schema, err)
+	}
+	result, err := schema.Validate(gojsonschema.NewGoLoader(data))
+	if err != nil {
+		return false, err
+	}
+	if !result.Valid() {
+		violations := make([]string, 0, len(result.Errors()))
+		for _, resultErr := range result.Errors() {
+			violations = append(violations, "  - "+resultErr.String())
+		}
+		a.fnd.Logger().Infof("Body did not match JSON schema %s:\n%s", a.JSON.Schema, strings.Join(violations, "\n"))
+		return false, nil
+	}
+	return true, nil
+}
+
+func (a *responseAction) matchJSONCheck(check *expectations.JSONCheck, data interface{}) (bool, error) {
+	logger := a.fnd.Logger()
+	value, found := check.Path.Select(data)
+	if !check.Exists {
+		if found {
+			logger.Infof("JSON value at %s exists but it was not expected: %s", check.Path, formatJSON(value))
+			return false, nil
+		}
+		return true, nil
+	}
+	if !found {
+		logger.Infof("JSON value at %s not found", check.Path)
+		return false, nil
+	}
+	logger.Debugf("Checking JSON value at %s: %s", check.Path, formatJSON(value))
+
+	if check.CompareEquals && !reflect.DeepEqual(check.Equals, value) {
+		logger.Infof("JSON value at %s did not match:\n%s", check.Path, jsonDiff(check.Equals, value))
+		return false, nil
+	}
+	if check.Type != "" {
+		if valueType := expectations.JSONTypeOf(value); valueType != check.Type {
+			logger.Infof("JSON value at %s has type %s but expected type is %s", check.Path, valueType, check.Type)
+			return false, nil
+		}
+	}
+	if check.Length >= 0 {
+		length, err := expectations.JSONLength(value)
+		if err != nil {
+			logger.Infof("JSON value at %s length could not be checked: %v", check.Path, err)
+			return false, nil
+		}
+		if length != check.Length {
+			logger.Infof("JSON value at %s has length %d but expected length is %d", check.Path, length, check.Length)
+			return false, nil
+		}
+	}
+	if check.Regexp != nil {
+		// Non string values are matched in the JSON encoded form.
+		str, ok := value.(string)
+		if !ok {
+			str = formatJSON(value)
+		}
+		if !check.Regexp.MatchString(str) {
+			logger.Infof("JSON value at %s did not match the pattern %s: %s", check.Path, check.Regexp, str)
+			return false, nil
+		}
+	}
+	if check.Operator != "" {
+		number, ok := value.(float64)
+		if !ok {
+			logger.Infof("JSON value at %s is not a number: %s", check.Path, formatJSON(value))
+			return false, nil
+		}
+		matched, err := metrics.GenericMetric[float64]{Value: number}.Compare(check.Operator, check.Value)
+		if err != nil {
+			return false, err
+		}
+		if !matched {
+			logger.Infof("JSON value at %s with value %v did not match the %s comparison with %v",
+				check.Path, number, check.Operator, check.Value)
+			return false, nil
+		}
+	}
 	return true, nil
 }
 
-func (a *responseAction) renderBodyContent() (string, error) {
+func formatJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// jsonDiff returns the unified diff of the indented JSON encoded values.
+func jsonDiff(expected, actual interface{}) string {
+	encode := func(value interface{}) []string {
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return []string{fmt.Sprintf("%v\n", value)}
+		}
+		return difflib.SplitLines(string(data))
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        encode(expected),
+		B:        encode(actual),
+		FromFile: "expected",
+		ToFile:   "actual",
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Sprintf("expected: %s\nactual: %s", formatJSON(expected), formatJSON(actual))
+	}
+	return diff
+}
+
+func (a *responseAction) matchTLS(info *request.TLSInfo) bool {
+	if info == nil {
+		a.fnd.Logger().Debugf("Response has no TLS details")
+		return false
+	}
+	a.fnd.Logger().Debugf("Comparing TLS details %+v against expected TLS details %+v", *info, *a.TLS)
+	return (a.TLS.Version == "" || a.TLS.Version == info.Version) &&
+		(a.TLS.CipherSuite == "" || a.TLS.CipherSuite == info.CipherSuite) &&
+		(a.TLS.ALPN == "" || a.TLS.ALPN == info.ALPN) &&
+		(a.TLS.PeerSubject == "" || a.TLS.PeerSubject == info.PeerSubject)
+}
+
+// matchBodyFile compares the body with the file content byte by byte.
+func (a *responseAction) matchBodyFile(body string) (bool, error) {
+	expected, err := afero.ReadFile(a.fnd.Fs(), a.BodyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read body file %s: %v", a.BodyFile, err)
+	}
+	if body != string(expected) {
+		a.fnd.Logger().Infof("Body did not match file %s (size %d, SHA-256 %s) - actual size %d, SHA-256 %s",
+			a.BodyFile, len(expected), sha256Hex(string(expected)), len(body), sha256Hex(body))
+		return false, nil
+	}
+	return true, nil
+}
+
+func sha256Hex(content string) string {
+	digest := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(digest[:])
+}
+
+func (a *responseAction) renderBodyContent(runData runtime.Data) (string, error) {
 	if a.BodyRenderTemplate {
-		content, err := a.service.RenderTemplate(a.BodyContent, a.parameters)
+		content, err := a.service.RenderTemplate(a.BodyContent, renderParameters(runData, a.parameters))
 		if err != nil {
 			return "", err
 		}
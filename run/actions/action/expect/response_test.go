@@ -3,7 +3,9 @@ package expect
 import (
 	"context"
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/wstool/wst/conf/types"
 	"github.com/wstool/wst/mocks/authored/external"
 	appMocks "github.com/wstool/wst/mocks/generated/app"
@@ -11,12 +13,16 @@ import (
 	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
 	parametersMocks "github.com/wstool/wst/mocks/generated/run/parameters"
 	parameterMocks "github.com/wstool/wst/mocks/generated/run/parameters/parameter"
+	scriptsMocks "github.com/wstool/wst/mocks/generated/run/resources/scripts"
 	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
 	"github.com/wstool/wst/run/actions/action"
 	"github.com/wstool/wst/run/actions/action/request"
 	"github.com/wstool/wst/run/expectations"
+	"github.com/wstool/wst/run/metrics"
 	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/parameters/parameter"
 	"net/http"
+	"regexp"
 	"testing"
 	"time"
 )
@@ -50,7 +56,7 @@ func TestExpectationActionMaker_MakeResponseAction(t *testing.T) {
 				OnFailure: "ignore",
 				Response: types.ResponseExpectation{
 					Request: "last",
-					Headers: types.Headers{"h1": "test"},
+					Headers: types.ResponseHeaders{"h1": {Value: "test", Count: -1}},
 					Body: types.ResponseBody{
 						Content:        "data",
 						Match:          "exact",
@@ -75,7 +81,7 @@ func TestExpectationActionMaker_MakeResponseAction(t *testing.T) {
 				sl.On("Find", "validService").Return(svc, nil)
 				responseExpectation := &expectations.ResponseExpectation{
 					Request:            "last",
-					Headers:            types.Headers{"h1": "test"},
+					Headers:            exactHeader("h1", "test"),
 					BodyContent:        "data",
 					BodyMatch:          expectations.MatchTypeExact,
 					BodyRenderTemplate: true,
@@ -114,7 +120,7 @@ func TestExpectationActionMaker_MakeResponseAction(t *testing.T) {
 				OnFailure: "fail",
 				Response: types.ResponseExpectation{
 					Request: "last",
-					Headers: types.Headers{"h1": "test"},
+					Headers: types.ResponseHeaders{"h1": {Value: "test", Count: -1}},
 					Body: types.ResponseBody{
 						Content:        "data",
 						Match:          "exact",
@@ -144,7 +150,7 @@ func TestExpectationActionMaker_MakeResponseAction(t *testing.T) {
 				OnFailure: "fail",
 				Response: types.ResponseExpectation{
 					Request: "last",
-					Headers: types.Headers{"h1": "test"},
+					Headers: types.ResponseHeaders{"h1": {Value: "test", Count: -1}},
 					Body: types.ResponseBody{
 						Content:        "data",
 						Match:          "exact",
@@ -202,6 +208,34 @@ func TestExpectationActionMaker_MakeResponseAction(t *testing.T) {
 	}
 }
 
+func exactHeader(name, value string) []expectations.HeaderExpectation {
+	return []expectations.HeaderExpectation{
+		{
+			Name:   name,
+			Values: []string{value},
+			Match:  expectations.MatchTypeExact,
+			Mode:   expectations.HeaderModeAny,
+			Count:  -1,
+		},
+	}
+}
+
+func jsonPath(t *testing.T, expr string) *expectations.JSONPath {
+	path, err := expectations.ParseJSONPath(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func compileExpr(t *testing.T, source string, variables ...string) *expectations.Expr {
+	expr, err := expectations.CompileExpr(source, variables...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return expr
+}
+
 func Test_responseAction_Execute(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -237,11 +271,12 @@ func Test_responseAction_Execute(t *testing.T) {
 					StatusCode: 200,
 				}
 				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "test", params).Return("test tmp", nil)
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "test",
 				BodyMatch:          expectations.MatchTypeExact,
 				BodyRenderTemplate: true,
@@ -267,11 +302,12 @@ func Test_responseAction_Execute(t *testing.T) {
 					StatusCode: 200,
 				}
 				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "test", params).Return("test tmp", nil)
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "test",
 				BodyMatch:          expectations.MatchTypePrefix,
 				BodyRenderTemplate: true,
@@ -297,11 +333,12 @@ func Test_responseAction_Execute(t *testing.T) {
 					StatusCode: 200,
 				}
 				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "tmp", params).Return("tmp", nil)
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "tmp",
 				BodyMatch:          expectations.MatchTypeSuffix,
 				BodyRenderTemplate: true,
@@ -327,11 +364,12 @@ func Test_responseAction_Execute(t *testing.T) {
 					StatusCode: 200,
 				}
 				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "test", params).Return("test tmp", nil)
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "test",
 				BodyMatch:          expectations.MatchTypeInfix,
 				BodyRenderTemplate: true,
@@ -357,11 +395,12 @@ func Test_responseAction_Execute(t *testing.T) {
 					StatusCode: 200,
 				}
 				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "test", params).Return("test tmp", nil)
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "test",
 				BodyMatch:          expectations.MatchTypeExact,
 				BodyRenderTemplate: true,
@@ -388,11 +427,12 @@ func Test_responseAction_Execute(t *testing.T) {
 					StatusCode: 200,
 				}
 				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "test x", params).Return("test x", nil)
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "test x",
 				BodyMatch:          expectations.MatchTypeExact,
 				BodyRenderTemplate: true,
@@ -418,11 +458,12 @@ func Test_responseAction_Execute(t *testing.T) {
 					StatusCode: 200,
 				}
 				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "test x", params).Return("test x", nil)
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "test x",
 				BodyMatch:          expectations.MatchTypeExact,
 				BodyRenderTemplate: true,
@@ -451,7 +492,7 @@ func Test_responseAction_Execute(t *testing.T) {
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "te.t\\st[mn]p",
 				BodyMatch:          expectations.MatchTypeRegexp,
 				BodyRenderTemplate: false,
@@ -480,7 +521,7 @@ func Test_responseAction_Execute(t *testing.T) {
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "te.t\\stp",
 				BodyMatch:          expectations.MatchTypeRegexp,
 				BodyRenderTemplate: false,
@@ -509,7 +550,7 @@ func Test_responseAction_Execute(t *testing.T) {
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "te.t\\stp",
 				BodyMatch:          expectations.MatchTypeRegexp,
 				BodyRenderTemplate: false,
@@ -538,7 +579,7 @@ func Test_responseAction_Execute(t *testing.T) {
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "te.a(a",
 				BodyMatch:          expectations.MatchTypeRegexp,
 				BodyRenderTemplate: false,
@@ -565,11 +606,12 @@ func Test_responseAction_Execute(t *testing.T) {
 					StatusCode: 200,
 				}
 				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "tex", params).Return("", errors.New("failed render"))
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "tex",
 				BodyMatch:          expectations.MatchTypeRegexp,
 				BodyRenderTemplate: true,
@@ -597,11 +639,12 @@ func Test_responseAction_Execute(t *testing.T) {
 					StatusCode: 200,
 				}
 				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "start", params).Return("test", nil)
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "start",
 				BodyMatch:          expectations.MatchTypePrefix,
 				BodyRenderTemplate: true,
@@ -627,11 +670,12 @@ func Test_responseAction_Execute(t *testing.T) {
 					StatusCode: 200,
 				}
 				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "ending", params).Return("test", nil)
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "ending",
 				BodyMatch:          expectations.MatchTypeSuffix,
 				BodyRenderTemplate: true,
@@ -657,11 +701,12 @@ func Test_responseAction_Execute(t *testing.T) {
 					StatusCode: 200,
 				}
 				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
 				svc.On("RenderTemplate", "test", params).Return("test", nil)
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
+				Headers:            exactHeader("content-type", "application/json"),
 				BodyContent:        "test",
 				BodyMatch:          expectations.MatchTypeInfix,
 				BodyRenderTemplate: true,
@@ -690,7 +735,7 @@ func Test_responseAction_Execute(t *testing.T) {
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"accept": "application/json"},
+				Headers:            exactHeader("accept", "application/json"),
 				BodyContent:        "tex",
 				BodyMatch:          expectations.MatchTypeRegexp,
 				BodyRenderTemplate: true,
@@ -719,7 +764,7 @@ func Test_responseAction_Execute(t *testing.T) {
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"accept": "application/json"},
+				Headers:            exactHeader("accept", "application/json"),
 				BodyContent:        "tex",
 				BodyMatch:          expectations.MatchTypeRegexp,
 				BodyRenderTemplate: true,
@@ -748,7 +793,7 @@ func Test_responseAction_Execute(t *testing.T) {
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"accept": "application/json"},
+				Headers:            exactHeader("accept", "application/json"),
 				BodyContent:        "tex",
 				BodyMatch:          expectations.MatchTypeRegexp,
 				BodyRenderTemplate: true,
@@ -778,7 +823,7 @@ func Test_responseAction_Execute(t *testing.T) {
 			},
 			expectation: &expectations.ResponseExpectation{
 				Request:            "last",
-				Headers:            types.Headers{"accept": "application/json"},
+				Headers:            exactHeader("accept", "application/json"),
 				BodyContent:        "tex",
 				BodyMatch:          expectations.MatchTypeRegexp,
 				BodyRenderTemplate: true,
@@ -787,7 +832,7 @@ func Test_responseAction_Execute(t *testing.T) {
 			want: true,
 		},
 		{
-			name: "failed response match because invalid loaded data type",
+			name: "successful response with protocol match",
 			setupMocks: func(
 				t *testing.T,
 				fnd *appMocks.MockFoundation,
@@ -797,22 +842,25 @@ func Test_responseAction_Execute(t *testing.T) {
 				params parameters.Parameters,
 			) {
 				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
 				fnd.On("Logger").Return(mockLogger.SugaredLogger)
-				rd.On("Load", "response/last").Return("data", true)
+				response := request.ResponseData{
+					Body:       "test",
+					Headers:    http.Header{},
+					StatusCode: 200,
+					Proto:      "HTTP/3.0",
+				}
+				rd.On("Load", "response/last").Return(response, true)
 			},
 			expectation: &expectations.ResponseExpectation{
-				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
-				BodyContent:        "tex",
-				BodyMatch:          expectations.MatchTypeRegexp,
-				BodyRenderTemplate: true,
+				Request:    "last",
+				StatusCode: 200,
+				Proto:      "HTTP/3.0",
 			},
-			want:             false,
-			expectErr:        true,
-			expectedErrorMsg: "invalid response data type",
+			want: true,
 		},
 		{
-			name: "failed response match because failed data loading",
+			name: "successful response with no protocol match",
 			setupMocks: func(
 				t *testing.T,
 				fnd *appMocks.MockFoundation,
@@ -822,53 +870,1724 @@ func Test_responseAction_Execute(t *testing.T) {
 				params parameters.Parameters,
 			) {
 				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
 				fnd.On("Logger").Return(mockLogger.SugaredLogger)
-				rd.On("Load", "response/last").Return(nil, false)
+				response := request.ResponseData{
+					Body:       "test",
+					Headers:    http.Header{},
+					StatusCode: 200,
+					Proto:      "HTTP/2.0",
+				}
+				rd.On("Load", "response/last").Return(response, true)
 			},
 			expectation: &expectations.ResponseExpectation{
-				Request:            "last",
-				Headers:            types.Headers{"content-type": "application/json"},
-				BodyContent:        "tex",
-				BodyMatch:          expectations.MatchTypeRegexp,
-				BodyRenderTemplate: true,
+				Request:    "last",
+				StatusCode: 200,
+				Proto:      "HTTP/3.0",
 			},
-			want:             false,
-			expectErr:        true,
-			expectedErrorMsg: "response data not found",
+			want: false,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			params := parameters.Parameters{
-				"test": parameterMocks.NewMockParameter(t),
-			}
-			fndMock := appMocks.NewMockFoundation(t)
-			dataMock := runtimeMocks.NewMockData(t)
-			svcMock := servicesMocks.NewMockService(t)
-			ctx := context.Background()
-
-			tt.setupMocks(t, fndMock, ctx, dataMock, svcMock, params)
-
-			a := &responseAction{
-				CommonExpectation: &CommonExpectation{
-					fnd:     fndMock,
-					service: svcMock,
-					timeout: 20 * 1e6,
+		{
+			name: "successful response with TLS match",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       "test",
+					Headers:    http.Header{},
+					StatusCode: 200,
+					TLS: &request.TLSInfo{
+						Version:     "TLS 1.3",
+						CipherSuite: "TLS_AES_128_GCM_SHA256",
+						ALPN:        "h2",
+						PeerSubject: "CN=localhost",
+					},
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:    "last",
+				StatusCode: 200,
+				TLS: &expectations.TLSExpectation{
+					Version:     "TLS 1.3",
+					PeerSubject: "CN=localhost",
 				},
-				ResponseExpectation: tt.expectation,
-				parameters:          params,
-			}
-
-			got, err := a.Execute(ctx, dataMock)
-
-			if tt.expectErr {
-				assert.Error(t, err)
-				assert.False(t, got)
-				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tt.want, got)
+			},
+			want: true,
+		},
+		{
+			name: "successful response with no TLS version match",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       "test",
+					Headers:    http.Header{},
+					StatusCode: 200,
+					TLS: &request.TLSInfo{
+						Version:     "TLS 1.3",
+						CipherSuite: "TLS_AES_128_GCM_SHA256",
+						ALPN:        "h2",
+						PeerSubject: "CN=localhost",
+					},
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:    "last",
+				StatusCode: 200,
+				TLS: &expectations.TLSExpectation{
+					Version: "TLS 1.2",
+				},
+			},
+			want: false,
+		},
+		{
+			name: "successful response with no TLS details",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       "test",
+					Headers:    http.Header{},
+					StatusCode: 200,
+					TLS:        nil,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:    "last",
+				StatusCode: 200,
+				TLS: &expectations.TLSExpectation{
+					ALPN: "h2",
+				},
+			},
+			want: false,
+		},
+		{
+			name: "successful response with content encoding and body SHA-256 match",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:            "\x00\xff\x01",
+					Headers:         http.Header{},
+					ContentEncoding: "br",
+					RawBody:         "\x8b\x01\x80\x00\xff\x01\x03",
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:    "last",
+				Encoding:   "br",
+				BodySHA256: "47ffa3ea45a70b8a41c2c0825df323c00a8b7a01c1ea06083cc41dddcc001123",
+			},
+			want: true,
+		},
+		{
+			name: "successful response with raw body SHA-256 match",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:            "\x00\xff\x01",
+					Headers:         http.Header{},
+					ContentEncoding: "br",
+					RawBody:         "\x8b\x01\x80\x00\xff\x01\x03",
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:    "last",
+				BodySHA256: "0066c52a9136ea4c4cfcea57c200f096958903be85a93c2ff1f05e8c72f02d91",
+				BodyRaw:    true,
+			},
+			want: true,
+		},
+		{
+			name: "failed response with body SHA-256 not matching",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:            "\x00\xff\x01",
+					Headers:         http.Header{},
+					ContentEncoding: "br",
+					RawBody:         "\x8b\x01\x80\x00\xff\x01\x03",
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:    "last",
+				BodySHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+			},
+			want: false,
+		},
+		{
+			name: "failed response with identity content encoding not matching",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:    "test",
+					Headers: http.Header{},
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:  "last",
+				Encoding: "gzip",
+			},
+			want: false,
+		},
+		{
+			name: "successful response with identity content encoding",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:    "test",
+					Headers: http.Header{},
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:  "last",
+				Encoding: "identity",
+			},
+			want: true,
+		},
+		{
+			name: "successful response with body file match",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:            "\x00\xff\x01",
+					Headers:         http.Header{},
+					ContentEncoding: "br",
+					RawBody:         "\x8b\x01\x80\x00\xff\x01\x03",
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				fs := afero.NewMemMapFs()
+				require.NoError(t, afero.WriteFile(fs, "/test/image.bin", []byte("\x00\xff\x01"), 0644))
+				fnd.On("Fs").Return(fs)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:  "last",
+				BodyFile: "/test/image.bin",
+			},
+			want: true,
+		},
+		{
+			name: "failed response with raw body file not matching",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:            "\x00\xff\x01",
+					Headers:         http.Header{},
+					ContentEncoding: "br",
+					RawBody:         "\x8b\x01\x80\x00\xff\x01\x03",
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				fs := afero.NewMemMapFs()
+				require.NoError(t, afero.WriteFile(fs, "/test/image.bin", []byte("\x00\xff\x01"), 0644))
+				fnd.On("Fs").Return(fs)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:  "last",
+				BodyFile: "/test/image.bin",
+				BodyRaw:  true,
+			},
+			want: false,
+		},
+		{
+			name: "failed response with missing body file",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:            "\x00\xff\x01",
+					Headers:         http.Header{},
+					ContentEncoding: "br",
+					RawBody:         "\x8b\x01\x80\x00\xff\x01\x03",
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				fnd.On("Fs").Return(afero.NewMemMapFs())
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:  "last",
+				BodyFile: "/test/missing.bin",
+			},
+			want:             false,
+			expectErr:        true,
+			expectedErrorMsg: "failed to read body file /test/missing.bin",
+		},
+		{
+			name: "successful response with metrics match",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:     "test",
+					Headers:  http.Header{},
+					BodySize: 4,
+					Timing: request.ResponseTiming{
+						Connect: 2 * time.Millisecond,
+						TTFB:    2100 * time.Millisecond,
+						Total:   2150 * time.Millisecond,
+					},
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				Metrics: []expectations.ResponseMetric{
+					{Metric: expectations.ResponseMetricTotal, Operator: metrics.MetricGeOperator, Value: 2000},
+					{Metric: expectations.ResponseMetricTTFB, Operator: metrics.MetricLtOperator, Value: 2500},
+					{Metric: expectations.ResponseMetricDNS, Operator: metrics.MetricEqOperator, Value: 0},
+					{Metric: expectations.ResponseMetricBodySize, Operator: metrics.MetricLtOperator, Value: 10240},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "failed response with metrics not matching",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:     "test",
+					Headers:  http.Header{},
+					BodySize: 4,
+					Timing: request.ResponseTiming{
+						Connect: 2 * time.Millisecond,
+						TTFB:    2100 * time.Millisecond,
+						Total:   2150 * time.Millisecond,
+					},
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				Metrics: []expectations.ResponseMetric{
+					{Metric: expectations.ResponseMetricConnect, Operator: metrics.MetricLtOperator, Value: 1},
+					{Metric: expectations.ResponseMetricTotal, Operator: metrics.MetricGeOperator, Value: 2000},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "failed response with body size not matching",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:     "test",
+					Headers:  http.Header{},
+					BodySize: 4,
+					Timing: request.ResponseTiming{
+						Connect: 2 * time.Millisecond,
+						TTFB:    2100 * time.Millisecond,
+						Total:   2150 * time.Millisecond,
+					},
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				Metrics: []expectations.ResponseMetric{
+					{Metric: expectations.ResponseMetricBodySize, Operator: metrics.MetricGtOperator, Value: 4},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "failed response with metrics not matching in dry run",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(true)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:     "test",
+					Headers:  http.Header{},
+					BodySize: 4,
+					Timing: request.ResponseTiming{
+						Connect: 2 * time.Millisecond,
+						TTFB:    2100 * time.Millisecond,
+						Total:   2150 * time.Millisecond,
+					},
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				Metrics: []expectations.ResponseMetric{
+					{Metric: expectations.ResponseMetricTotal, Operator: metrics.MetricLtOperator, Value: 1000},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "successful response with timing expression",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:     "test",
+					Headers:  http.Header{},
+					BodySize: 4,
+					Timing: request.ResponseTiming{
+						Connect: 2 * time.Millisecond,
+						TTFB:    2100 * time.Millisecond,
+						Total:   2150 * time.Millisecond,
+					},
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				params["test"].(*parameterMocks.MockParameter).On("Type").Return(parameter.NilType)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				Expr: compileExpr(
+					t,
+					"response.timing.total >= duration('2s') && response.timing.dns == duration('0s') && response.size == 4",
+					expectations.ExprVariableResponse,
+				),
+			},
+			want: true,
+		},
+		{
+			name: "successful response with reused connection",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:             "test",
+					Headers:          http.Header{},
+					StatusCode:       200,
+					ConnectionReused: true,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:    "last",
+				StatusCode: 200,
+				Connection: expectations.ConnectionTypeReused,
+			},
+			want: true,
+		},
+		{
+			name: "successful response with new connection",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:             "test",
+					Headers:          http.Header{},
+					StatusCode:       200,
+					ConnectionReused: false,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:    "last",
+				StatusCode: 200,
+				Connection: expectations.ConnectionTypeNew,
+			},
+			want: true,
+		},
+		{
+			name: "successful response with no reused connection match",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:             "test",
+					Headers:          http.Header{},
+					StatusCode:       200,
+					ConnectionReused: false,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:    "last",
+				StatusCode: 200,
+				Connection: expectations.ConnectionTypeReused,
+			},
+			want: false,
+		},
+		{
+			name: "successful response with no new connection match and dry run",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(true)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:             "test",
+					Headers:          http.Header{},
+					StatusCode:       200,
+					ConnectionReused: true,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:    "last",
+				StatusCode: 200,
+				Connection: expectations.ConnectionTypeNew,
+			},
+			want: true,
+		},
+		{
+			name: "failed response match because invalid loaded data type",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				rd.On("Load", "response/last").Return("data", true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:            "last",
+				Headers:            exactHeader("content-type", "application/json"),
+				BodyContent:        "tex",
+				BodyMatch:          expectations.MatchTypeRegexp,
+				BodyRenderTemplate: true,
+			},
+			want:             false,
+			expectErr:        true,
+			expectedErrorMsg: "invalid response data type",
+		},
+		{
+			name: "failed response match because failed data loading",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				rd.On("Load", "response/last").Return(nil, false)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:            "last",
+				Headers:            exactHeader("content-type", "application/json"),
+				BodyContent:        "tex",
+				BodyMatch:          expectations.MatchTypeRegexp,
+				BodyRenderTemplate: true,
+			},
+			want:             false,
+			expectErr:        true,
+			expectedErrorMsg: "response data not found",
+		},
+		{
+			name: "successful response with request rendered from runtime parameters",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       "test",
+					StatusCode: 200,
+				}
+				rd.On("Parameters").Return(parameters.Parameters{})
+				svc.On("RenderTemplate", "r{{ .Parameters.GetString \"iteration\" }}", params).Return("r1", nil)
+				rd.On("Load", "response/r1").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request:            "r{{ .Parameters.GetString \"iteration\" }}",
+				BodyContent:        "test",
+				BodyMatch:          expectations.MatchTypeExact,
+				BodyRenderTemplate: false,
+			},
+			want: true,
+		},
+		{
+			name: "failed response because request rendering failed",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				svc.On("RenderTemplate", "r{{ .Iteration }}", params).Return("", errors.New("render fail"))
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "r{{ .Iteration }}",
+			},
+			want:             false,
+			expectErr:        true,
+			expectedErrorMsg: "render fail",
+		},
+		{
+			name: "successful response with JSON checks and schema",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				script := scriptsMocks.NewMockScript(t)
+				script.On("Content").Return(`{"type":"object","required":["user"],"properties":{"user":{"type":"object","required":["name"]}}}`)
+				svc.On("FindScript", "user_schema").Return(script, nil)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{
+						{
+							Path:          jsonPath(t, "$.user.name"),
+							Exists:        true,
+							CompareEquals: true,
+							Equals:        "John",
+							Type:          expectations.JSONTypeString,
+							Length:        -1,
+						},
+						{
+							Path:          jsonPath(t, "$.user.roles"),
+							Exists:        true,
+							CompareEquals: true,
+							Equals:        []interface{}{"admin", "dev"},
+							Length:        2,
+						},
+						{
+							Path:     jsonPath(t, "$.user.age"),
+							Exists:   true,
+							Length:   -1,
+							Regexp:   regexp.MustCompile("^4[0-9]$"),
+							Operator: metrics.MetricGtOperator,
+							Value:    40,
+						},
+						{
+							Path:          jsonPath(t, "$.items[*].id"),
+							Exists:        true,
+							CompareEquals: true,
+							Equals:        []interface{}{float64(1), float64(2)},
+							Length:        -1,
+						},
+						{
+							Path:   jsonPath(t, "$.error"),
+							Length: -1,
+						},
+					},
+					Schema: "user_schema",
+				},
+			},
+			want: true,
+		},
+		{
+			name: "failed response with JSON value mismatch",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{
+						{
+							Path:          jsonPath(t, "$.user.roles"),
+							Exists:        true,
+							CompareEquals: true,
+							Equals:        []interface{}{"admin"},
+							Length:        -1,
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "successful response with explicit JSON null value",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","deleted":null}}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{
+						{
+							Path:          jsonPath(t, "$.user.deleted"),
+							Exists:        true,
+							CompareEquals: true,
+							Length:        -1,
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "failed response with JSON value not matching explicit null",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","deleted":false}}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{
+						{
+							Path:          jsonPath(t, "$.user.deleted"),
+							Exists:        true,
+							CompareEquals: true,
+							Length:        -1,
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "failed response with JSON value mismatch in dry run",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(true)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{
+						{
+							Path:          jsonPath(t, "$.user.name"),
+							Exists:        true,
+							CompareEquals: true,
+							Equals:        "Jane",
+							Length:        -1,
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "failed response with missing JSON value",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{
+						{
+							Path:   jsonPath(t, "$.user.email"),
+							Exists: true,
+							Length: -1,
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "failed response with unexpected JSON value",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{
+						{
+							Path:   jsonPath(t, "$.user"),
+							Length: -1,
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "failed response with JSON type mismatch",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{
+						{
+							Path:   jsonPath(t, "$.user.age"),
+							Exists: true,
+							Type:   expectations.JSONTypeString,
+							Length: -1,
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "failed response with JSON length mismatch",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{
+						{
+							Path:   jsonPath(t, "$.items"),
+							Exists: true,
+							Length: 3,
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "failed response with JSON length of number",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{
+						{
+							Path:   jsonPath(t, "$.user.age"),
+							Exists: true,
+							Length: 2,
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "failed response with JSON regexp mismatch",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{
+						{
+							Path:   jsonPath(t, "$.user.name"),
+							Exists: true,
+							Length: -1,
+							Regexp: regexp.MustCompile("^Jane$"),
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "failed response with JSON comparison mismatch",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{
+						{
+							Path:     jsonPath(t, "$.user.age"),
+							Exists:   true,
+							Length:   -1,
+							Operator: metrics.MetricLtOperator,
+							Value:    18,
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "failed response with JSON comparison of non number",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{
+						{
+							Path:     jsonPath(t, "$.user.name"),
+							Exists:   true,
+							Length:   -1,
+							Operator: metrics.MetricEqOperator,
+							Value:    1,
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "failed response with invalid JSON body",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       "not json",
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "failed response with JSON schema violation",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				script := scriptsMocks.NewMockScript(t)
+				script.On("Content").Return(`{"type":"object","required":["user","total"],"properties":{"user":{"properties":{"age":{"type":"string"}}}}}`)
+				svc.On("FindScript", "user_schema").Return(script, nil)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{},
+					Schema: "user_schema",
+				},
+			},
+			want: false,
+		},
+		{
+			name: "failed response with invalid JSON schema",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				script := scriptsMocks.NewMockScript(t)
+				script.On("Content").Return(`{"type": 1}`)
+				svc.On("FindScript", "user_schema").Return(script, nil)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{},
+					Schema: "user_schema",
+				},
+			},
+			want:             false,
+			expectErr:        true,
+			expectedErrorMsg: "invalid JSON schema user_schema",
+		},
+		{
+			name: "failed response with missing JSON schema",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"user":{"name":"John","age":42,"roles":["admin","dev"]},"items":[{"id":1},{"id":2}]}`,
+					StatusCode: 200,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				svc.On("FindScript", "user_schema").Return(nil, errors.New("script user_schema not found"))
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				JSON: &expectations.JSONExpectation{
+					Checks: []expectations.JSONCheck{},
+					Schema: "user_schema",
+				},
+			},
+			want:             false,
+			expectErr:        true,
+			expectedErrorMsg: "script user_schema not found",
+		},
+		{
+			name: "successful response with expression",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"items":[1,2]}`,
+					Headers:    http.Header{"Set-Cookie": []string{"a=1", "b=2"}},
+					StatusCode: 204,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				params["test"].(*parameterMocks.MockParameter).On("Type").Return(parameter.NilType)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				Expr: compileExpr(
+					t,
+					"response.status in [200, 204] && size(response.headers['Set-Cookie']) == 2 && "+
+						"size(response.json.items) == 2",
+					expectations.ExprVariableResponse,
+				),
+			},
+			want: true,
+		},
+		{
+			name: "failed response with expression",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"items":[1,2]}`,
+					Headers:    http.Header{"Set-Cookie": []string{"a=1", "b=2"}},
+					StatusCode: 204,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				params["test"].(*parameterMocks.MockParameter).On("Type").Return(parameter.NilType)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				Expr:    compileExpr(t, "response.status == 200", expectations.ExprVariableResponse),
+			},
+			want: false,
+		},
+		{
+			name: "successful response with timing expression",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       "ok",
+					StatusCode: 200,
+					Timing: request.ResponseTiming{
+						TTFB:  10 * time.Millisecond,
+						Total: 30 * time.Millisecond,
+					},
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				params["test"].(*parameterMocks.MockParameter).On("Type").Return(parameter.NilType)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				Expr: compileExpr(
+					t,
+					"response.timing.total < duration('50ms') && response.timing.ttfb <= response.timing.total",
+					expectations.ExprVariableResponse,
+				),
+			},
+			want: true,
+		},
+		{
+			name: "failed response with timing expression",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       "ok",
+					StatusCode: 200,
+					Timing: request.ResponseTiming{
+						TTFB:  40 * time.Millisecond,
+						Total: 60 * time.Millisecond,
+					},
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				params["test"].(*parameterMocks.MockParameter).On("Type").Return(parameter.NilType)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				Expr: compileExpr(
+					t,
+					"response.timing.total < duration('50ms') && response.timing.ttfb <= response.timing.total",
+					expectations.ExprVariableResponse,
+				),
+			},
+			want: false,
+		},
+		{
+			name: "failed response with expression evaluation error",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(false)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"items":[1,2]}`,
+					Headers:    http.Header{},
+					StatusCode: 204,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				params["test"].(*parameterMocks.MockParameter).On("Type").Return(parameter.NilType)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				Expr:    compileExpr(t, "size(response.headers['Set-Cookie']) == 2", expectations.ExprVariableResponse),
+			},
+			want: false,
+		},
+		{
+			name: "failed response with expression in dry run",
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				svc *servicesMocks.MockService,
+				params parameters.Parameters,
+			) {
+				mockLogger := external.NewMockLogger()
+				fnd.On("DryRun").Return(true)
+				fnd.On("Logger").Return(mockLogger.SugaredLogger)
+				response := request.ResponseData{
+					Body:       `{"items":[1,2]}`,
+					Headers:    http.Header{"Set-Cookie": []string{"a=1", "b=2"}},
+					StatusCode: 204,
+				}
+				rd.On("Load", "response/last").Return(response, true)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				params["test"].(*parameterMocks.MockParameter).On("Type").Return(parameter.NilType)
+			},
+			expectation: &expectations.ResponseExpectation{
+				Request: "last",
+				Expr:    compileExpr(t, "response.body == 'test'", expectations.ExprVariableResponse),
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := parameters.Parameters{
+				"test": parameterMocks.NewMockParameter(t),
+			}
+			fndMock := appMocks.NewMockFoundation(t)
+			dataMock := runtimeMocks.NewMockData(t)
+			svcMock := servicesMocks.NewMockService(t)
+			ctx := context.Background()
+
+			tt.setupMocks(t, fndMock, ctx, dataMock, svcMock, params)
+
+			a := &responseAction{
+				CommonExpectation: &CommonExpectation{
+					fnd:     fndMock,
+					service: svcMock,
+					timeout: 20 * 1e6,
+				},
+				ResponseExpectation: tt.expectation,
+				parameters:          params,
+			}
+
+			got, err := a.Execute(ctx, dataMock)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.False(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_responseAction_matchHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers http.Header
+		header  expectations.HeaderExpectation
+		want    bool
+	}{
+		{
+			name:    "header name matched case-insensitively",
+			headers: http.Header{"Content-Type": []string{"text/html"}},
+			header:  exactHeader("content-type", "text/html")[0],
+			want:    true,
+		},
+		{
+			name:    "missing header",
+			headers: http.Header{},
+			header:  exactHeader("Content-Type", "text/html")[0],
+			want:    false,
+		},
+		{
+			name:    "absent header not present",
+			headers: http.Header{"Server": []string{"nginx"}},
+			header:  expectations.HeaderExpectation{Name: "X-Powered-By", Absent: true, Count: -1},
+			want:    true,
+		},
+		{
+			name:    "absent header present",
+			headers: http.Header{"X-Powered-By": []string{"PHP/8.3"}},
+			header:  expectations.HeaderExpectation{Name: "x-powered-by", Absent: true, Count: -1},
+			want:    false,
+		},
+		{
+			name:    "presence only",
+			headers: http.Header{"Etag": []string{"abc"}},
+			header:  expectations.HeaderExpectation{Name: "ETag", Mode: expectations.HeaderModeAny, Count: -1},
+			want:    true,
+		},
+		{
+			name:    "any mode matches all expected values in multiple values",
+			headers: http.Header{"Set-Cookie": []string{"a=1", "b=2", "c=3"}},
+			header: expectations.HeaderExpectation{
+				Name:   "Set-Cookie",
+				Values: []string{"c=3", "a=1"},
+				Match:  expectations.MatchTypeExact,
+				Mode:   expectations.HeaderModeAny,
+				Count:  -1,
+			},
+			want: true,
+		},
+		{
+			name:    "any mode with not matched expected value",
+			headers: http.Header{"Set-Cookie": []string{"a=1", "b=2"}},
+			header: expectations.HeaderExpectation{
+				Name:   "Set-Cookie",
+				Values: []string{"a=1", "d=4"},
+				Match:  expectations.MatchTypeExact,
+				Mode:   expectations.HeaderModeAny,
+				Count:  -1,
+			},
+			want: false,
+		},
+		{
+			name:    "all mode with all values matched",
+			headers: http.Header{"Set-Cookie": []string{"a=1; Secure", "b=2; Secure"}},
+			header: expectations.HeaderExpectation{
+				Name:   "Set-Cookie",
+				Values: []string{"; Secure"},
+				Match:  expectations.MatchTypeSuffix,
+				Mode:   expectations.HeaderModeAll,
+				Count:  -1,
+			},
+			want: true,
+		},
+		{
+			name:    "all mode with a value not matched",
+			headers: http.Header{"Set-Cookie": []string{"a=1; Secure", "b=2"}},
+			header: expectations.HeaderExpectation{
+				Name:   "Set-Cookie",
+				Values: []string{"; Secure"},
+				Match:  expectations.MatchTypeSuffix,
+				Mode:   expectations.HeaderModeAll,
+				Count:  -1,
+			},
+			want: false,
+		},
+		{
+			name:    "regexp match",
+			headers: http.Header{"Server": []string{"NGINX/1.25.3"}},
+			header: expectations.HeaderExpectation{
+				Name:     "Server",
+				Values:   []string{"^nginx/1\\."},
+				Match:    expectations.MatchTypeRegexp,
+				Mode:     expectations.HeaderModeAny,
+				Count:    -1,
+				Patterns: []*regexp.Regexp{regexp.MustCompile("(?i)^nginx/1\\.")},
+			},
+			want: true,
+		},
+		{
+			name:    "regexp not matched",
+			headers: http.Header{"Server": []string{"Apache"}},
+			header: expectations.HeaderExpectation{
+				Name:     "Server",
+				Values:   []string{"^nginx"},
+				Match:    expectations.MatchTypeRegexp,
+				Mode:     expectations.HeaderModeAny,
+				Count:    -1,
+				Patterns: []*regexp.Regexp{regexp.MustCompile("^nginx")},
+			},
+			want: false,
+		},
+		{
+			name:    "prefix match ignoring case",
+			headers: http.Header{"Content-Type": []string{"Text/HTML; charset=UTF-8"}},
+			header: expectations.HeaderExpectation{
+				Name:       "Content-Type",
+				Values:     []string{"text/html"},
+				Match:      expectations.MatchTypePrefix,
+				Mode:       expectations.HeaderModeAny,
+				IgnoreCase: true,
+				Count:      -1,
+			},
+			want: true,
+		},
+		{
+			name:    "infix match is case-sensitive by default",
+			headers: http.Header{"Content-Type": []string{"text/html; charset=UTF-8"}},
+			header: expectations.HeaderExpectation{
+				Name:   "Content-Type",
+				Values: []string{"utf-8"},
+				Match:  expectations.MatchTypeInfix,
+				Mode:   expectations.HeaderModeAny,
+				Count:  -1,
+			},
+			want: false,
+		},
+		{
+			name:    "count matched",
+			headers: http.Header{"Set-Cookie": []string{"a=1", "b=2"}},
+			header: expectations.HeaderExpectation{
+				Name:          "Set-Cookie",
+				Mode:          expectations.HeaderModeAny,
+				Count:         2,
+				CountOperator: metrics.MetricEqOperator,
+			},
+			want: true,
+		},
+		{
+			name:    "count not matched",
+			headers: http.Header{"Set-Cookie": []string{"a=1", "b=2"}},
+			header: expectations.HeaderExpectation{
+				Name:          "Set-Cookie",
+				Mode:          expectations.HeaderModeAny,
+				Count:         3,
+				CountOperator: metrics.MetricGeOperator,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+			a := &responseAction{
+				CommonExpectation: &CommonExpectation{
+					fnd: fndMock,
+				},
+				ResponseExpectation: &expectations.ResponseExpectation{},
 			}
+			assert.Equal(t, tt.want, a.matchHeader(&tt.header, tt.headers))
 		})
 	}
 }
@@ -883,7 +2602,7 @@ func Test_responseAction_Timeout(t *testing.T) {
 		},
 		ResponseExpectation: &expectations.ResponseExpectation{
 			Request:            "last",
-			Headers:            types.Headers{"h1": "test"},
+			Headers:            exactHeader("h1", "test"),
 			BodyContent:        "data",
 			BodyMatch:          expectations.MatchTypeExact,
 			BodyRenderTemplate: true,
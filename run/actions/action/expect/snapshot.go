@@ -0,0 +1,165 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expect
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/afero"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/environments/environment/output"
+	"github.com/wstool/wst/run/expectations"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/services"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+func (m *ExpectationActionMaker) MakeSnapshotAction(
+	config *types.SnapshotExpectationAction,
+	sl services.ServiceLocator,
+	defaultTimeout int,
+) (action.Action, error) {
+	commonExpectation, err := m.MakeCommonExpectation(
+		sl, config.Service, config.Timeout, defaultTimeout, config.When, config.OnFailure)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotExpectation, err := m.expectationsMaker.MakeSnapshotExpectation(&config.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshotAction{
+		CommonExpectation:   commonExpectation,
+		SnapshotExpectation: snapshotExpectation,
+		parameters:          commonExpectation.service.ServerParameters(),
+	}, nil
+}
+
+type snapshotAction struct {
+	*CommonExpectation
+	*expectations.SnapshotExpectation
+	parameters parameters.Parameters
+}
+
+func (a *snapshotAction) Execute(ctx context.Context, runData runtime.Data) (bool, error) {
+	logger := a.fnd.Logger()
+	logger.Infof("Executing expectation snapshot action")
+	content, err := a.getContent(ctx, runData)
+	if err != nil {
+		return false, err
+	}
+	content = a.Normalize(content)
+
+	// Evaluate expression over the normalized content.
+	if a.Expr != nil {
+		variables := map[string]interface{}{
+			expectations.ExprVariableSnapshot: map[string]interface{}{"content": content},
+		}
+		if !a.matchExpr(a.Expr, variables, renderParameters(runData, a.parameters)) {
+			return a.fnd.DryRun(), nil
+		}
+	}
+
+	fs := a.fnd.Fs()
+	if a.fnd.UpdateSnapshots() {
+		if a.fnd.DryRun() {
+			logger.Infof("Snapshot %s would be updated", a.File)
+			return true, nil
+		}
+		if err = fs.MkdirAll(filepath.Dir(a.File), 0755); err != nil {
+			return false, errors.Errorf("failed to create snapshot directory: %v", err)
+		}
+		if err = afero.WriteFile(fs, a.File, []byte(content), 0644); err != nil {
+			return false, errors.Errorf("failed to write snapshot %s: %v", a.File, err)
+		}
+		logger.Infof("Snapshot %s updated", a.File)
+		return true, nil
+	}
+
+	expected, err := afero.ReadFile(fs, a.File)
+	if err != nil {
+		if a.fnd.DryRun() {
+			return true, nil
+		}
+		if os.IsNotExist(err) {
+			return false, errors.Errorf(
+				"snapshot %s does not exist - use --update-snapshots to create it", a.File)
+		}
+		return false, errors.Errorf("failed to read snapshot %s: %v", a.File, err)
+	}
+
+	if string(expected) == content || a.fnd.DryRun() {
+		return true, nil
+	}
+	return false, errors.Errorf("snapshot %s does not match:\n%s", a.File, snapshotDiff(string(expected), content))
+}
+
+// getContent returns the response body or the complete command output.
+func (a *snapshotAction) getContent(ctx context.Context, runData runtime.Data) (string, error) {
+	if a.Source == expectations.SnapshotSourceResponse {
+		responseData, err := a.loadResponseData(runData, a.Request, a.parameters)
+		if err != nil {
+			return "", err
+		}
+		return responseData.Body, nil
+	}
+
+	outputType, err := getServiceOutputType(a.OutputType)
+	if err != nil {
+		return "", err
+	}
+	data, ok := runData.Load(fmt.Sprintf("command/%s", a.Command))
+	if !ok {
+		return "", errors.New("command data not found")
+	}
+	oc, ok := data.(output.Collector)
+	if !ok {
+		return "", errors.New("invalid command data type")
+	}
+	// The independent reader is used so the output is still available for other expectations.
+	reader, err := oc.ReaderSince(ctx, outputType, time.Time{})
+	if err != nil {
+		return "", err
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", errors.Errorf("failed to read command %s output: %v", a.Command, err)
+	}
+	return string(content), nil
+}
+
+// snapshotDiff returns the unified diff of the snapshot and the actual content.
+func snapshotDiff(expected, actual string) string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expected),
+		B:        difflib.SplitLines(actual),
+		FromFile: "snapshot",
+		ToFile:   "actual",
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Sprintf("snapshot: %s\nactual: %s", expected, actual)
+	}
+	return diff
+}
@@ -0,0 +1,402 @@
+package expect
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	outputMocks "github.com/wstool/wst/mocks/generated/run/environments/environment/output"
+	expectationsMocks "github.com/wstool/wst/mocks/generated/run/expectations"
+	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/actions/action/request"
+	"github.com/wstool/wst/run/environments/environment/output"
+	"github.com/wstool/wst/run/expectations"
+	"github.com/wstool/wst/run/parameters"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestExpectationActionMaker_MakeSnapshotAction(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           *types.SnapshotExpectationAction
+		setupMocks       func(*servicesMocks.MockServiceLocator, *servicesMocks.MockService, *expectationsMocks.MockMaker)
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "successful snapshot action creation",
+			config: &types.SnapshotExpectationAction{
+				Service:   "svc",
+				When:      "on_success",
+				OnFailure: "fail",
+				Snapshot: types.SnapshotExpectation{
+					File:   "/test/snapshots/index.html",
+					Source: "response",
+				},
+			},
+			setupMocks: func(
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				em *expectationsMocks.MockMaker,
+			) {
+				sl.On("Find", "svc").Return(svc, nil)
+				em.On("MakeSnapshotExpectation", mock.Anything).Return(&expectations.SnapshotExpectation{
+					File:   "/test/snapshots/index.html",
+					Source: expectations.SnapshotSourceResponse,
+				}, nil)
+				svc.On("ServerParameters").Return(parameters.Parameters{})
+			},
+		},
+		{
+			name: "failed snapshot action creation because no service found",
+			config: &types.SnapshotExpectationAction{
+				Service: "invalid",
+			},
+			setupMocks: func(
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				em *expectationsMocks.MockMaker,
+			) {
+				sl.On("Find", "invalid").Return(nil, errors.New("svc not found"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "svc not found",
+		},
+		{
+			name: "failed snapshot action creation because snapshot expectation creation failed",
+			config: &types.SnapshotExpectationAction{
+				Service: "svc",
+			},
+			setupMocks: func(
+				sl *servicesMocks.MockServiceLocator,
+				svc *servicesMocks.MockService,
+				em *expectationsMocks.MockMaker,
+			) {
+				sl.On("Find", "svc").Return(svc, nil)
+				em.On("MakeSnapshotExpectation", mock.Anything).Return(nil, errors.New("snapshot file is not set"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "snapshot file is not set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			svcMock := servicesMocks.NewMockService(t)
+			expectationsMakerMock := expectationsMocks.NewMockMaker(t)
+			m := &ExpectationActionMaker{
+				fnd:               fndMock,
+				expectationsMaker: expectationsMakerMock,
+			}
+			tt.setupMocks(slMock, svcMock, expectationsMakerMock)
+
+			got, err := m.MakeSnapshotAction(tt.config, slMock, 5000)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				actualAction, ok := got.(*snapshotAction)
+				require.True(t, ok)
+				assert.Equal(t, "/test/snapshots/index.html", actualAction.File)
+				assert.Equal(t, 5*time.Second, actualAction.Timeout())
+				assert.Equal(t, action.OnSuccess, actualAction.When())
+				assert.Equal(t, action.Fail, actualAction.OnFailure())
+			}
+		})
+	}
+}
+
+func Test_snapshotAction_Execute(t *testing.T) {
+	responseExpectation := &expectations.SnapshotExpectation{
+		File:    "/test/snapshots/index.html",
+		Source:  expectations.SnapshotSourceResponse,
+		Request: "last",
+		Normalizations: []expectations.SnapshotNormalization{
+			{Pattern: regexp.MustCompile(`pid=\d+`), Replacement: "pid=PID"},
+		},
+	}
+	outputExpectation := &expectations.SnapshotExpectation{
+		File:       "/test/snapshots/php-i.txt",
+		Source:     expectations.SnapshotSourceOutput,
+		Command:    "php-i",
+		OutputType: expectations.OutputTypeStdout,
+	}
+	tests := []struct {
+		name        string
+		expectation *expectations.SnapshotExpectation
+		setupMocks  func(
+			t *testing.T,
+			fnd *appMocks.MockFoundation,
+			fs afero.Fs,
+			runData *runtimeMocks.MockData,
+		)
+		checkFs          func(t *testing.T, fs afero.Fs)
+		want             bool
+		expectErr        bool
+		expectedErrorMsg string
+	}{
+		{
+			name:        "response body matches normalized snapshot",
+			expectation: responseExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				require.NoError(t, afero.WriteFile(fs, "/test/snapshots/index.html", []byte("<p>pid=PID</p>\n"), 0644))
+				fnd.On("UpdateSnapshots").Return(false)
+				runData.On("Load", "response/last").Return(request.ResponseData{Body: "<p>pid=1234</p>\n"}, true)
+			},
+			want: true,
+		},
+		{
+			name:        "response body does not match snapshot",
+			expectation: responseExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				require.NoError(t, afero.WriteFile(fs, "/test/snapshots/index.html", []byte("<p>a</p>\n<p>b</p>\n"), 0644))
+				fnd.On("UpdateSnapshots").Return(false)
+				fnd.On("DryRun").Return(false)
+				runData.On("Load", "response/last").Return(request.ResponseData{Body: "<p>a</p>\n<p>c</p>\n"}, true)
+			},
+			expectErr: true,
+			expectedErrorMsg: "snapshot /test/snapshots/index.html does not match:\n" +
+				"--- snapshot\n+++ actual\n@@ -1,3 +1,3 @@\n <p>a</p>\n-<p>b</p>\n+<p>c</p>\n \n",
+		},
+		{
+			name:        "response body does not match snapshot in dry run",
+			expectation: responseExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				require.NoError(t, afero.WriteFile(fs, "/test/snapshots/index.html", []byte("a"), 0644))
+				fnd.On("UpdateSnapshots").Return(false)
+				fnd.On("DryRun").Return(true)
+				runData.On("Load", "response/last").Return(request.ResponseData{Body: "b"}, true)
+			},
+			want: true,
+		},
+		{
+			name:        "snapshot updated from normalized response body",
+			expectation: responseExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				require.NoError(t, afero.WriteFile(fs, "/test/snapshots/index.html", []byte("old"), 0644))
+				fnd.On("UpdateSnapshots").Return(true)
+				fnd.On("DryRun").Return(false)
+				runData.On("Load", "response/last").Return(request.ResponseData{Body: "<p>pid=42</p>\n"}, true)
+			},
+			checkFs: func(t *testing.T, fs afero.Fs) {
+				content, err := afero.ReadFile(fs, "/test/snapshots/index.html")
+				require.NoError(t, err)
+				assert.Equal(t, "<p>pid=PID</p>\n", string(content))
+			},
+			want: true,
+		},
+		{
+			name:        "snapshot not updated in dry run",
+			expectation: outputExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				fnd.On("UpdateSnapshots").Return(true)
+				fnd.On("DryRun").Return(true)
+				collector := outputMocks.NewMockCollector(t)
+				collector.On("ReaderSince", mock.Anything, output.Stdout, time.Time{}).Return(
+					strings.NewReader("PHP Version => 8.4.0\n"), nil)
+				runData.On("Load", "command/php-i").Return(collector, true)
+			},
+			checkFs: func(t *testing.T, fs afero.Fs) {
+				exists, err := afero.DirExists(fs, "/test/snapshots")
+				require.NoError(t, err)
+				assert.False(t, exists)
+			},
+			want: true,
+		},
+		{
+			name: "response body matches snapshot and expression",
+			expectation: &expectations.SnapshotExpectation{
+				File:           responseExpectation.File,
+				Source:         responseExpectation.Source,
+				Request:        responseExpectation.Request,
+				Normalizations: responseExpectation.Normalizations,
+				Expr:           compileExpr(t, "snapshot.content.contains('pid=PID')", expectations.ExprVariableSnapshot),
+			},
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				require.NoError(t, afero.WriteFile(fs, "/test/snapshots/index.html", []byte("<p>pid=PID</p>\n"), 0644))
+				fnd.On("UpdateSnapshots").Return(false)
+				runData.On("Load", "response/last").Return(request.ResponseData{Body: "<p>pid=1234</p>\n"}, true)
+				runData.On("Parameters").Return(parameters.Parameters{})
+			},
+			want: true,
+		},
+		{
+			name: "snapshot not updated when expression does not match",
+			expectation: &expectations.SnapshotExpectation{
+				File:    responseExpectation.File,
+				Source:  responseExpectation.Source,
+				Request: responseExpectation.Request,
+				Expr:    compileExpr(t, "size(snapshot.content) > 100", expectations.ExprVariableSnapshot),
+			},
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				require.NoError(t, afero.WriteFile(fs, "/test/snapshots/index.html", []byte("old"), 0644))
+				fnd.On("DryRun").Return(false)
+				runData.On("Load", "response/last").Return(request.ResponseData{Body: "<p>pid=42</p>\n"}, true)
+				runData.On("Parameters").Return(parameters.Parameters{})
+			},
+			checkFs: func(t *testing.T, fs afero.Fs) {
+				content, err := afero.ReadFile(fs, "/test/snapshots/index.html")
+				require.NoError(t, err)
+				assert.Equal(t, "old", string(content))
+			},
+			want: false,
+		},
+		{
+			name:        "snapshot created with its directory from command output",
+			expectation: outputExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				fnd.On("UpdateSnapshots").Return(true)
+				fnd.On("DryRun").Return(false)
+				collector := outputMocks.NewMockCollector(t)
+				collector.On("ReaderSince", mock.Anything, output.Stdout, time.Time{}).Return(
+					strings.NewReader("PHP Version => 8.4.0\n"), nil)
+				runData.On("Load", "command/php-i").Return(collector, true)
+			},
+			checkFs: func(t *testing.T, fs afero.Fs) {
+				content, err := afero.ReadFile(fs, "/test/snapshots/php-i.txt")
+				require.NoError(t, err)
+				assert.Equal(t, "PHP Version => 8.4.0\n", string(content))
+			},
+			want: true,
+		},
+		{
+			name:        "command output matches snapshot",
+			expectation: outputExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				require.NoError(t, afero.WriteFile(fs, "/test/snapshots/php-i.txt", []byte("PHP Version => 8.4.0\n"), 0644))
+				fnd.On("UpdateSnapshots").Return(false)
+				collector := outputMocks.NewMockCollector(t)
+				collector.On("ReaderSince", mock.Anything, output.Stdout, time.Time{}).Return(
+					strings.NewReader("PHP Version => 8.4.0\n"), nil)
+				runData.On("Load", "command/php-i").Return(collector, true)
+			},
+			want: true,
+		},
+		{
+			name:        "error when snapshot does not exist",
+			expectation: responseExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				fnd.On("UpdateSnapshots").Return(false)
+				fnd.On("DryRun").Return(false)
+				runData.On("Load", "response/last").Return(request.ResponseData{Body: "body"}, true)
+			},
+			expectErr:        true,
+			expectedErrorMsg: "snapshot /test/snapshots/index.html does not exist - use --update-snapshots to create it",
+		},
+		{
+			name:        "missing snapshot ignored in dry run",
+			expectation: responseExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				fnd.On("UpdateSnapshots").Return(false)
+				fnd.On("DryRun").Return(true)
+				runData.On("Load", "response/last").Return(request.ResponseData{Body: "body"}, true)
+			},
+			want: true,
+		},
+		{
+			name:        "error when response data not found",
+			expectation: responseExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				runData.On("Load", "response/last").Return(nil, false)
+			},
+			expectErr:        true,
+			expectedErrorMsg: "response data not found",
+		},
+		{
+			name:        "error when command data not found",
+			expectation: outputExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				runData.On("Load", "command/php-i").Return(nil, false)
+			},
+			expectErr:        true,
+			expectedErrorMsg: "command data not found",
+		},
+		{
+			name:        "error when command data has invalid type",
+			expectation: outputExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				runData.On("Load", "command/php-i").Return("invalid", true)
+			},
+			expectErr:        true,
+			expectedErrorMsg: "invalid command data type",
+		},
+		{
+			name:        "error when command output reader fails",
+			expectation: outputExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				collector := outputMocks.NewMockCollector(t)
+				collector.On("ReaderSince", mock.Anything, output.Stdout, time.Time{}).Return(
+					nil, errors.New("reader failed"))
+				runData.On("Load", "command/php-i").Return(collector, true)
+			},
+			expectErr:        true,
+			expectedErrorMsg: "reader failed",
+		},
+		{
+			name:        "error when command output reading fails",
+			expectation: outputExpectation,
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, fs afero.Fs, runData *runtimeMocks.MockData) {
+				collector := outputMocks.NewMockCollector(t)
+				collector.On("ReaderSince", mock.Anything, output.Stdout, time.Time{}).Return(
+					iotest.ErrReader(errors.New("read failed")), nil)
+				runData.On("Load", "command/php-i").Return(collector, true)
+			},
+			expectErr:        true,
+			expectedErrorMsg: "failed to read command php-i output: read failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+			fs := afero.NewMemMapFs()
+			fndMock.On("Fs").Maybe().Return(fs)
+			runDataMock := runtimeMocks.NewMockData(t)
+			svcMock := servicesMocks.NewMockService(t)
+
+			tt.setupMocks(t, fndMock, fs, runDataMock)
+
+			a := &snapshotAction{
+				CommonExpectation: &CommonExpectation{
+					fnd:     fndMock,
+					service: svcMock,
+					timeout: 5 * time.Second,
+				},
+				SnapshotExpectation: tt.expectation,
+				parameters:          parameters.Parameters{},
+			}
+
+			got, err := a.Execute(context.Background(), runDataMock)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.False(t, got)
+				assert.Equal(t, tt.expectedErrorMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			if tt.checkFs != nil {
+				tt.checkFs(t, fs)
+			}
+		})
+	}
+}
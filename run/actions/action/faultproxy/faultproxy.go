@@ -0,0 +1,189 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package faultproxy
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/environments/environment/providers"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/services"
+	"maps"
+	"slices"
+	"time"
+)
+
+type Maker interface {
+	Make(
+		config *types.FaultProxyAction,
+		sl services.ServiceLocator,
+		defaultTimeout int,
+	) (action.Action, error)
+}
+
+type ActionMaker struct {
+	fnd app.Foundation
+}
+
+func CreateActionMaker(fnd app.Foundation) *ActionMaker {
+	return &ActionMaker{
+		fnd: fnd,
+	}
+}
+
+func (m *ActionMaker) Make(
+	config *types.FaultProxyAction,
+	sl services.ServiceLocator,
+	defaultTimeout int,
+) (action.Action, error) {
+	svc, err := sl.Find(config.Service)
+	if err != nil {
+		return nil, errors.Errorf("fault proxy action service not found: %v", err)
+	}
+	if svc.SandboxType() != providers.LocalType {
+		return nil, errors.Errorf("fault proxy action supports only services in local sandbox")
+	}
+	consumers, err := proxyConsumers(sl, svc)
+	if err != nil {
+		return nil, err
+	}
+	if config.ResetProbability < 0 || config.ResetProbability > 1 {
+		return nil, errors.Errorf("fault proxy reset probability must be between 0 and 1")
+	}
+	if config.StallProbability < 0 || config.StallProbability > 1 {
+		return nil, errors.Errorf("fault proxy stall probability must be between 0 and 1")
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	return &Action{
+		fnd:       m.fnd,
+		service:   svc,
+		consumers: consumers,
+		port:      config.Port,
+		faults: &Faults{
+			Latency:          time.Duration(config.Latency) * time.Millisecond,
+			Jitter:           time.Duration(config.Jitter) * time.Millisecond,
+			Bandwidth:        config.Bandwidth,
+			ResetProbability: config.ResetProbability,
+			StallProbability: config.StallProbability,
+			StallDuration:    time.Duration(config.StallDuration) * time.Millisecond,
+		},
+		timeout:   time.Duration(config.Timeout * 1e6),
+		when:      action.When(config.When),
+		onFailure: action.OnFailureType(config.OnFailure),
+	}, nil
+}
+
+// proxyConsumers returns the other services that see the proxy address in their templates. They must be in the local
+// sandbox to reach the proxy and must not be started yet as their templates would not use the proxy address.
+func proxyConsumers(sl services.ServiceLocator, svc services.Service) ([]services.Service, error) {
+	svcs := sl.Services()
+	consumers := make([]services.Service, 0, len(svcs))
+	for _, name := range slices.Sorted(maps.Keys(svcs)) {
+		consumer := svcs[name]
+		if consumer == svc {
+			continue
+		}
+		if consumer.SandboxType() != providers.LocalType {
+			return nil, errors.Errorf("fault proxy action supports only consumer service %s in local sandbox", name)
+		}
+		if consumer.Task() != nil {
+			return nil, errors.Errorf("fault proxy action cannot be used after consumer service %s started", name)
+		}
+		consumers = append(consumers, consumer)
+	}
+	return consumers, nil
+}
+
+type Action struct {
+	fnd       app.Foundation
+	service   services.Service
+	consumers []services.Service
+	port      int32
+	faults    *Faults
+	timeout   time.Duration
+	when      action.When
+	onFailure action.OnFailureType
+}
+
+func (a *Action) When() action.When {
+	return a.when
+}
+
+func (a *Action) OnFailure() action.OnFailureType {
+	return a.onFailure
+}
+
+func (a *Action) Timeout() time.Duration {
+	return a.timeout
+}
+
+// Execute starts the proxy in front of the service or reconfigures faults of the already started proxy.
+func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error) {
+	logger := a.fnd.Logger()
+	logger.Infof("Executing fault proxy action for service %s", a.service.Name())
+	if a.fnd.DryRun() {
+		return true, nil
+	}
+
+	key := fmt.Sprintf("fault_proxy/%s", a.service.Name())
+	if stored, ok := runData.Load(key); ok {
+		proxy, ok := stored.(*Proxy)
+		if !ok {
+			return false, errors.Errorf("invalid fault proxy stored for service %s", a.service.Name())
+		}
+		proxy.SetFaults(a.faults)
+		logger.Debugf("Fault proxy for service %s reconfigured", a.service.Name())
+		return true, nil
+	}
+
+	for _, consumer := range a.consumers {
+		if consumer.Task() != nil {
+			return false, errors.Errorf(
+				"fault proxy for service %s must be started before consumer service %s starts",
+				a.service.Name(),
+				consumer.Name(),
+			)
+		}
+	}
+
+	proxy := NewProxy(logger, a.fnd.Dial, a.service.LocalAddress(), a.faults)
+	if err := proxy.Start(fmt.Sprintf("127.0.0.1:%d", a.port)); err != nil {
+		return false, err
+	}
+	if err := a.service.SetProxyAddress(proxy.Address()); err != nil {
+		_ = proxy.Close()
+		return false, err
+	}
+	if err := runData.Store(key, proxy); err != nil {
+		_ = proxy.Close()
+		return false, err
+	}
+	logger.Debugf(
+		"Fault proxy for service %s started on %s forwarding to %s",
+		a.service.Name(),
+		proxy.Address(),
+		proxy.Upstream(),
+	)
+
+	return true, nil
+}
@@ -0,0 +1,408 @@
+package faultproxy
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	taskMocks "github.com/wstool/wst/mocks/generated/run/environments/task"
+	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/environments/environment/providers"
+	"github.com/wstool/wst/run/services"
+	"testing"
+	"time"
+)
+
+func TestCreateActionMaker(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	tests := []struct {
+		name string
+		fnd  app.Foundation
+	}{
+		{
+			name: "create maker",
+			fnd:  fndMock,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CreateActionMaker(tt.fnd)
+			assert.Equal(t, tt.fnd, got.fnd)
+		})
+	}
+}
+
+func TestActionMaker_Make(t *testing.T) {
+	tests := []struct {
+		name              string
+		config            *types.FaultProxyAction
+		defaultTimeout    int
+		setupMocks        func(*testing.T, *servicesMocks.MockServiceLocator) services.Service
+		expectedPort      int32
+		expectedFaults    *Faults
+		expectedTimeout   time.Duration
+		expectedWhen      action.When
+		expectedOnFailure action.OnFailureType
+		expectError       bool
+		expectedErrorMsg  string
+	}{
+		{
+			name: "successful fault proxy action creation",
+			config: &types.FaultProxyAction{
+				Service:          "svc",
+				Port:             8081,
+				Latency:          100,
+				Jitter:           20,
+				Bandwidth:        1024,
+				ResetProbability: 0.1,
+				StallProbability: 0.2,
+				StallDuration:    3000,
+				When:             "on_success",
+				OnFailure:        "fail",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				svc.On("SandboxType").Return(providers.LocalType)
+				consumer := servicesMocks.NewMockService(t)
+				consumer.On("SandboxType").Return(providers.LocalType)
+				consumer.On("Task").Return(nil)
+				sl.On("Find", "svc").Return(svc, nil)
+				sl.On("Services").Return(services.Services{"svc": svc, "nginx": consumer})
+				return svc
+			},
+			expectedPort: 8081,
+			expectedFaults: &Faults{
+				Latency:          100 * time.Millisecond,
+				Jitter:           20 * time.Millisecond,
+				Bandwidth:        1024,
+				ResetProbability: 0.1,
+				StallProbability: 0.2,
+				StallDuration:    3 * time.Second,
+			},
+			expectedTimeout:   5000 * time.Millisecond,
+			expectedWhen:      action.OnSuccess,
+			expectedOnFailure: action.Fail,
+		},
+		{
+			name: "successful fault proxy action creation with custom timeout",
+			config: &types.FaultProxyAction{
+				Service:   "svc",
+				Timeout:   3000,
+				When:      "always",
+				OnFailure: "ignore",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				svc.On("SandboxType").Return(providers.LocalType)
+				sl.On("Find", "svc").Return(svc, nil)
+				sl.On("Services").Return(services.Services{"svc": svc})
+				return svc
+			},
+			expectedFaults:    &Faults{},
+			expectedTimeout:   3000 * time.Millisecond,
+			expectedWhen:      action.Always,
+			expectedOnFailure: action.Ignore,
+		},
+		{
+			name: "failed action creation due to service not found",
+			config: &types.FaultProxyAction{
+				Service: "svc",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				sl.On("Find", "svc").Return(nil, errors.New("not found"))
+				return nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "fault proxy action service not found: not found",
+		},
+		{
+			name: "failed action creation due to non local sandbox",
+			config: &types.FaultProxyAction{
+				Service: "svc",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				svc.On("SandboxType").Return(providers.DockerType)
+				sl.On("Find", "svc").Return(svc, nil)
+				return svc
+			},
+			expectError:      true,
+			expectedErrorMsg: "fault proxy action supports only services in local sandbox",
+		},
+		{
+			name: "failed action creation due to consumer in non local sandbox",
+			config: &types.FaultProxyAction{
+				Service: "svc",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				svc.On("SandboxType").Return(providers.LocalType)
+				consumer := servicesMocks.NewMockService(t)
+				consumer.On("SandboxType").Return(providers.DockerType)
+				sl.On("Find", "svc").Return(svc, nil)
+				sl.On("Services").Return(services.Services{"svc": svc, "nginx": consumer})
+				return svc
+			},
+			expectError:      true,
+			expectedErrorMsg: "fault proxy action supports only consumer service nginx in local sandbox",
+		},
+		{
+			name: "failed action creation due to started consumer",
+			config: &types.FaultProxyAction{
+				Service: "svc",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				svc.On("SandboxType").Return(providers.LocalType)
+				consumer := servicesMocks.NewMockService(t)
+				consumer.On("SandboxType").Return(providers.LocalType)
+				consumer.On("Task").Return(taskMocks.NewMockTask(t))
+				sl.On("Find", "svc").Return(svc, nil)
+				sl.On("Services").Return(services.Services{"svc": svc, "nginx": consumer})
+				return svc
+			},
+			expectError:      true,
+			expectedErrorMsg: "fault proxy action cannot be used after consumer service nginx started",
+		},
+		{
+			name: "failed action creation due to invalid reset probability",
+			config: &types.FaultProxyAction{
+				Service:          "svc",
+				ResetProbability: 1.5,
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				svc.On("SandboxType").Return(providers.LocalType)
+				sl.On("Find", "svc").Return(svc, nil)
+				sl.On("Services").Return(services.Services{"svc": svc})
+				return svc
+			},
+			expectError:      true,
+			expectedErrorMsg: "fault proxy reset probability must be between 0 and 1",
+		},
+		{
+			name: "failed action creation due to invalid stall probability",
+			config: &types.FaultProxyAction{
+				Service:          "svc",
+				StallProbability: -0.5,
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				svc.On("SandboxType").Return(providers.LocalType)
+				sl.On("Find", "svc").Return(svc, nil)
+				sl.On("Services").Return(services.Services{"svc": svc})
+				return svc
+			},
+			expectError:      true,
+			expectedErrorMsg: "fault proxy stall probability must be between 0 and 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			m := &ActionMaker{
+				fnd: fndMock,
+			}
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			svc := tt.setupMocks(t, slMock)
+
+			got, err := m.Make(tt.config, slMock, tt.defaultTimeout)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				act, ok := got.(*Action)
+				assert.True(t, ok)
+				assert.Equal(t, fndMock, act.fnd)
+				assert.Equal(t, svc, act.service)
+				assert.Len(t, act.consumers, len(slMock.Services())-1)
+				assert.Equal(t, tt.expectedPort, act.port)
+				assert.Equal(t, tt.expectedFaults, act.faults)
+				assert.Equal(t, tt.expectedTimeout, act.Timeout())
+				assert.Equal(t, tt.expectedWhen, act.When())
+				assert.Equal(t, tt.expectedOnFailure, act.OnFailure())
+			}
+		})
+	}
+}
+
+func TestAction_Execute(t *testing.T) {
+	faults := &Faults{Latency: 100 * time.Millisecond}
+	var consumers []services.Service
+	tests := []struct {
+		name        string
+		setupMocks  func(*testing.T, *appMocks.MockFoundation, *servicesMocks.MockService, *runtimeMocks.MockData) *Proxy
+		want        bool
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name: "proxy started",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) *Proxy {
+				fnd.On("DryRun").Return(false)
+				rd.On("Load", "fault_proxy/svc").Return(nil, false)
+				svc.On("LocalAddress").Return("127.0.0.1:9000")
+				svc.On("SetProxyAddress", mock.AnythingOfType("string")).Return(nil)
+				rd.On("Store", "fault_proxy/svc", mock.AnythingOfType("*faultproxy.Proxy")).Return(nil)
+				return nil
+			},
+			want: true,
+		},
+		{
+			name: "proxy reconfigured",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) *Proxy {
+				fnd.On("DryRun").Return(false)
+				proxy := NewProxy(nil, nil, "127.0.0.1:9000", &Faults{})
+				rd.On("Load", "fault_proxy/svc").Return(proxy, true)
+				return proxy
+			},
+			want: true,
+		},
+		{
+			name: "invalid stored proxy",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) *Proxy {
+				fnd.On("DryRun").Return(false)
+				rd.On("Load", "fault_proxy/svc").Return("invalid", true)
+				return nil
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "invalid fault proxy stored for service svc",
+		},
+		{
+			name: "consumer started before proxy",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) *Proxy {
+				fnd.On("DryRun").Return(false)
+				rd.On("Load", "fault_proxy/svc").Return(nil, false)
+				consumer := servicesMocks.NewMockService(t)
+				consumer.On("Name").Return("nginx")
+				consumer.On("Task").Return(taskMocks.NewMockTask(t))
+				consumers = append(consumers, consumer)
+				return nil
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "fault proxy for service svc must be started before consumer service nginx starts",
+		},
+		{
+			name: "proxy address setting error",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) *Proxy {
+				fnd.On("DryRun").Return(false)
+				rd.On("Load", "fault_proxy/svc").Return(nil, false)
+				svc.On("LocalAddress").Return("127.0.0.1:9000")
+				svc.On("SetProxyAddress", mock.AnythingOfType("string")).Return(errors.New("set failed"))
+				return nil
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "set failed",
+		},
+		{
+			name: "proxy storing error",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) *Proxy {
+				fnd.On("DryRun").Return(false)
+				rd.On("Load", "fault_proxy/svc").Return(nil, false)
+				svc.On("LocalAddress").Return("127.0.0.1:9000")
+				svc.On("SetProxyAddress", mock.AnythingOfType("string")).Return(nil)
+				rd.On("Store", "fault_proxy/svc", mock.AnythingOfType("*faultproxy.Proxy")).Return(errors.New("store failed"))
+				return nil
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "store failed",
+		},
+		{
+			name: "dry run",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService, rd *runtimeMocks.MockData) *Proxy {
+				fnd.On("DryRun").Return(true)
+				return nil
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			svcMock := servicesMocks.NewMockService(t)
+			runDataMock := runtimeMocks.NewMockData(t)
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+			svcMock.On("Name").Return("svc").Maybe()
+			ctx := context.Background()
+
+			consumers = nil
+			storedProxy := tt.setupMocks(t, fndMock, svcMock, runDataMock)
+
+			a := &Action{
+				fnd:       fndMock,
+				service:   svcMock,
+				consumers: consumers,
+				faults:    faults,
+			}
+
+			got, err := a.Execute(ctx, runDataMock)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+			if storedProxy != nil {
+				assert.Equal(t, faults, storedProxy.faults.Load())
+			}
+			for _, call := range runDataMock.Calls {
+				if call.Method == "Store" {
+					assert.NoError(t, call.Arguments.Get(1).(*Proxy).Close())
+				}
+			}
+		})
+	}
+}
+
+func TestAction_Timeout(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:     fndMock,
+		timeout: 2000 * time.Millisecond,
+	}
+	assert.Equal(t, 2000*time.Millisecond, a.Timeout())
+}
+
+func TestAction_OnFailure(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:       fndMock,
+		onFailure: action.Skip,
+	}
+	assert.Equal(t, action.Skip, a.OnFailure())
+}
+
+func TestAction_When(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:  fndMock,
+		when: action.OnSuccess,
+	}
+	assert.Equal(t, action.OnSuccess, a.When())
+}
@@ -0,0 +1,233 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package faultproxy
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"io"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Faults defines the faults injected by the proxy.
+type Faults struct {
+	// Latency is added before forwarding each chunk of data.
+	Latency time.Duration
+	// Jitter is the maximal random duration added to the latency.
+	Jitter time.Duration
+	// Bandwidth limits the forwarded bytes per second in each direction. Zero means unlimited.
+	Bandwidth int
+	// ResetProbability is the probability that the connection is reset after receiving the first client data.
+	ResetProbability float64
+	// StallProbability is the probability that the connection stalls before forwarding any data.
+	StallProbability float64
+	// StallDuration is the duration of the stall. Zero means stalling until the proxy is closed.
+	StallDuration time.Duration
+}
+
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// Proxy is a TCP proxy that forwards connections to the upstream address and injects faults.
+type Proxy struct {
+	logger   *zap.SugaredLogger
+	dial     DialFunc
+	random   func() float64
+	upstream string
+	listener net.Listener
+	faults   atomic.Pointer[Faults]
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func NewProxy(logger *zap.SugaredLogger, dial DialFunc, upstream string, faults *Faults) *Proxy {
+	p := &Proxy{
+		logger:   logger,
+		dial:     dial,
+		random:   rand.Float64,
+		upstream: upstream,
+	}
+	p.faults.Store(faults)
+	return p
+}
+
+// Start starts listening on the address and serving connections.
+func (p *Proxy) Start(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Errorf("failed to start fault proxy on %s: %v", address, err)
+	}
+	p.listener = listener
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	p.wg.Add(1)
+	go p.serve()
+	return nil
+}
+
+// Address returns the address that the proxy listens on.
+func (p *Proxy) Address() string {
+	return p.listener.Addr().String()
+}
+
+// Upstream returns the address that the proxy forwards to.
+func (p *Proxy) Upstream() string {
+	return p.upstream
+}
+
+// SetFaults replaces the injected faults. It applies to the data forwarded after the change and to new connections.
+func (p *Proxy) SetFaults(faults *Faults) {
+	p.faults.Store(faults)
+}
+
+// Close stops the proxy and closes all its connections.
+func (p *Proxy) Close() error {
+	if p.listener == nil {
+		return nil
+	}
+	p.cancel()
+	err := p.listener.Close()
+	p.wg.Wait()
+	if err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
+
+func (p *Proxy) serve() {
+	defer p.wg.Done()
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			if p.ctx.Err() == nil {
+				p.logger.Errorf("Fault proxy failed to accept connection: %v", err)
+			}
+			return
+		}
+		p.wg.Add(1)
+		go p.handle(conn)
+	}
+}
+
+func (p *Proxy) handle(client net.Conn) {
+	defer p.wg.Done()
+	defer client.Close()
+
+	faults := p.faults.Load()
+	reset := faults.ResetProbability > 0 && p.random() < faults.ResetProbability
+	stall := faults.StallProbability > 0 && p.random() < faults.StallProbability
+
+	upstream, err := p.dial(p.ctx, "tcp", p.upstream)
+	if err != nil {
+		p.logger.Debugf("Fault proxy failed to connect to upstream %s: %v", p.upstream, err)
+		return
+	}
+	defer upstream.Close()
+
+	// Closing connections unblocks all reads and writes when the proxy is closed.
+	stop := context.AfterFunc(p.ctx, func() {
+		_ = client.Close()
+		_ = upstream.Close()
+	})
+	defer stop()
+
+	if reset {
+		p.reset(client)
+		return
+	}
+	if stall {
+		p.logger.Debugf("Fault proxy stalling connection from %s", client.RemoteAddr())
+		if faults.StallDuration == 0 {
+			<-p.ctx.Done()
+			return
+		}
+		if err = p.sleep(faults.StallDuration); err != nil {
+			return
+		}
+	}
+
+	done := make(chan struct{}, 2)
+	go p.pipe(upstream, client, done)
+	go p.pipe(client, upstream, done)
+	<-done
+	<-done
+}
+
+// reset waits for the first client data and then resets the connection without forwarding it.
+func (p *Proxy) reset(client net.Conn) {
+	buf := make([]byte, 1)
+	_, _ = client.Read(buf)
+	if tcpConn, ok := client.(*net.TCPConn); ok {
+		_ = tcpConn.SetLinger(0)
+	}
+	p.logger.Debugf("Fault proxy resetting connection from %s", client.RemoteAddr())
+}
+
+func (p *Proxy) pipe(dst, src net.Conn, done chan<- struct{}) {
+	defer func() { done <- struct{}{} }()
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			if p.sleep(p.delay(n)) != nil {
+				return
+			}
+			if _, writeErr := dst.Write(buf[:n]); writeErr != nil {
+				return
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				_ = dst.Close()
+			} else if tcpConn, ok := dst.(*net.TCPConn); ok {
+				_ = tcpConn.CloseWrite()
+			} else {
+				_ = dst.Close()
+			}
+			return
+		}
+	}
+}
+
+func (p *Proxy) delay(size int) time.Duration {
+	faults := p.faults.Load()
+	delay := faults.Latency
+	if faults.Jitter > 0 {
+		delay += time.Duration(p.random() * float64(faults.Jitter))
+	}
+	if faults.Bandwidth > 0 {
+		delay += time.Duration(size) * time.Second / time.Duration(faults.Bandwidth)
+	}
+	return delay
+}
+
+// sleep waits for the duration or until the proxy is closed.
+func (p *Proxy) sleep(duration time.Duration) error {
+	if duration <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	}
+}
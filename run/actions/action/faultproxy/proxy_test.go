@@ -0,0 +1,192 @@
+package faultproxy
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/mocks/authored/external"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func startEchoServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func startTestProxy(t *testing.T, upstream string, faults *Faults) *Proxy {
+	var dialer net.Dialer
+	p := NewProxy(external.NewMockLogger().SugaredLogger, dialer.DialContext, upstream, faults)
+	p.random = func() float64 { return 0 }
+	require.NoError(t, p.Start("127.0.0.1:0"))
+	t.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+func roundTrip(t *testing.T, address string, msg string) (string, time.Duration, error) {
+	conn, err := net.Dial("tcp", address)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(2*time.Second)))
+	start := time.Now()
+	if _, err = conn.Write([]byte(msg)); err != nil {
+		return "", 0, err
+	}
+	buf := make([]byte, len(msg))
+	_, err = io.ReadFull(conn, buf)
+	return string(buf), time.Since(start), err
+}
+
+func TestProxy_Forwarding(t *testing.T) {
+	tests := []struct {
+		name       string
+		faults     *Faults
+		minElapsed time.Duration
+	}{
+		{
+			name:   "without faults",
+			faults: &Faults{},
+		},
+		{
+			name:       "with latency",
+			faults:     &Faults{Latency: 50 * time.Millisecond},
+			minElapsed: 100 * time.Millisecond,
+		},
+		{
+			name:       "with bandwidth limit",
+			faults:     &Faults{Bandwidth: 100},
+			minElapsed: 100 * time.Millisecond,
+		},
+		{
+			name:       "with limited stall",
+			faults:     &Faults{StallProbability: 1, StallDuration: 100 * time.Millisecond},
+			minElapsed: 100 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := startEchoServer(t)
+			p := startTestProxy(t, upstream, tt.faults)
+			assert.Equal(t, upstream, p.Upstream())
+
+			got, elapsed, err := roundTrip(t, p.Address(), "hello")
+
+			require.NoError(t, err)
+			assert.Equal(t, "hello", got)
+			assert.GreaterOrEqual(t, elapsed, tt.minElapsed)
+		})
+	}
+}
+
+func TestProxy_Reset(t *testing.T) {
+	p := startTestProxy(t, startEchoServer(t), &Faults{ResetProbability: 1})
+
+	_, _, err := roundTrip(t, p.Address(), "hello")
+
+	assert.Error(t, err)
+}
+
+func TestProxy_UpstreamUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	upstream := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	p := startTestProxy(t, upstream, &Faults{})
+
+	_, _, err = roundTrip(t, p.Address(), "hello")
+
+	assert.Error(t, err)
+}
+
+func TestProxy_SetFaults(t *testing.T) {
+	p := startTestProxy(t, startEchoServer(t), &Faults{ResetProbability: 1})
+	_, _, err := roundTrip(t, p.Address(), "hello")
+	assert.Error(t, err)
+
+	p.SetFaults(&Faults{})
+
+	got, _, err := roundTrip(t, p.Address(), "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", got)
+}
+
+func TestProxy_CloseStalledConnection(t *testing.T) {
+	p := startTestProxy(t, startEchoServer(t), &Faults{StallProbability: 1})
+	conn, err := net.Dial("tcp", p.Address())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+	_, err = conn.Read(make([]byte, 5))
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+	assert.NoError(t, p.Close())
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	_, err = conn.Read(make([]byte, 5))
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, os.ErrDeadlineExceeded)
+}
+
+func TestProxy_StartError(t *testing.T) {
+	p := NewProxy(external.NewMockLogger().SugaredLogger, nil, "127.0.0.1:9000", &Faults{})
+
+	err := p.Start("127.0.0.1:-1")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to start fault proxy on 127.0.0.1:-1")
+	assert.NoError(t, p.Close())
+}
+
+func TestProxy_delay(t *testing.T) {
+	tests := []struct {
+		name   string
+		faults *Faults
+		size   int
+		want   time.Duration
+	}{
+		{
+			name:   "no delay",
+			faults: &Faults{},
+			size:   100,
+			want:   0,
+		},
+		{
+			name:   "latency with jitter",
+			faults: &Faults{Latency: 10 * time.Millisecond, Jitter: 20 * time.Millisecond},
+			size:   100,
+			want:   20 * time.Millisecond,
+		},
+		{
+			name:   "bandwidth",
+			faults: &Faults{Bandwidth: 1000},
+			size:   500,
+			want:   500 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProxy(external.NewMockLogger().SugaredLogger, nil, "", tt.faults)
+			p.random = func() float64 { return 0.5 }
+
+			assert.Equal(t, tt.want, p.delay(tt.size))
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package foreach
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/parameters/parameter"
+	"github.com/wstool/wst/run/services"
+	"time"
+)
+
+type Maker interface {
+	Make(
+		config *types.ForeachAction,
+		sl services.ServiceLocator,
+		defaultTimeout int,
+		actionMaker action.Maker,
+	) (action.Action, error)
+}
+
+type ActionMaker struct {
+	fnd             app.Foundation
+	parametersMaker parameters.Maker
+	runtimeMaker    runtime.Maker
+}
+
+func CreateActionMaker(
+	fnd app.Foundation,
+	parametersMaker parameters.Maker,
+	runtimeMaker runtime.Maker,
+) *ActionMaker {
+	return &ActionMaker{
+		fnd:             fnd,
+		parametersMaker: parametersMaker,
+		runtimeMaker:    runtimeMaker,
+	}
+}
+
+func (m *ActionMaker) Make(
+	config *types.ForeachAction,
+	sl services.ServiceLocator,
+	defaultTimeout int,
+	actionMaker action.Maker,
+) (action.Action, error) {
+	if config.Parameter == "" {
+		return nil, errors.New("foreach action parameter cannot be empty")
+	}
+
+	var serviceParameters parameters.Parameters
+	if config.Service != "" {
+		svc, err := sl.Find(config.Service)
+		if err != nil {
+			return nil, errors.Errorf("foreach action service not found: %v", err)
+		}
+		serviceParameters = svc.ServerParameters()
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	// The body is run as a single sequential action so the inner actions keep the usual when logic.
+	body, err := actionMaker.MakeAction(&types.SequentialAction{
+		Actions:   config.Actions,
+		Service:   config.Service,
+		Timeout:   config.Timeout,
+		When:      string(action.Always),
+		OnFailure: string(action.Fail),
+	}, sl, config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Action{
+		fnd:               m.fnd,
+		parametersMaker:   m.parametersMaker,
+		runtimeMaker:      m.runtimeMaker,
+		body:              body,
+		parameter:         config.Parameter,
+		itemParameter:     config.ItemParameter,
+		indexParameter:    config.IndexParameter,
+		serviceParameters: serviceParameters,
+		timeout:           time.Duration(config.Timeout * 1e6),
+		when:              action.When(config.When),
+		onFailure:         action.OnFailureType(config.OnFailure),
+	}, nil
+}
+
+type Action struct {
+	fnd               app.Foundation
+	parametersMaker   parameters.Maker
+	runtimeMaker      runtime.Maker
+	body              action.Action
+	parameter         string
+	itemParameter     string
+	indexParameter    string
+	serviceParameters parameters.Parameters
+	timeout           time.Duration
+	when              action.When
+	onFailure         action.OnFailureType
+}
+
+func (a *Action) When() action.When {
+	return a.when
+}
+
+func (a *Action) OnFailure() action.OnFailureType {
+	return a.onFailure
+}
+
+func (a *Action) Timeout() time.Duration {
+	return a.timeout
+}
+
+func (a *Action) items(runData runtime.Data) ([]parameter.Parameter, error) {
+	// Runtime parameters take precedence so nested loops can iterate over outer loop items.
+	params := make(parameters.Parameters).Inherit(runData.Parameters()).Inherit(a.serviceParameters)
+	param, ok := params[a.parameter]
+	if !ok {
+		return nil, errors.Errorf("foreach action parameter %s not found", a.parameter)
+	}
+	if param.Type() != parameter.ArrayType {
+		return nil, errors.Errorf("foreach action parameter %s is not an array", a.parameter)
+	}
+	return param.ArrayValue(), nil
+}
+
+func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error) {
+	logger := a.fnd.Logger()
+	logger.Infof("Executing foreach action")
+
+	items, err := a.items(runData)
+	if err != nil {
+		return false, err
+	}
+
+	for i, item := range items {
+		params, err := a.parametersMaker.Make(types.Parameters{a.indexParameter: i})
+		if err != nil {
+			return false, err
+		}
+		params[a.itemParameter] = item
+
+		logger.Debugf("Executing foreach action iteration %d", i)
+		actCtx, cancel := a.runtimeMaker.MakeContextWithTimeout(ctx, a.body.Timeout())
+		success, err := a.body.Execute(actCtx, runData.WithParameters(params))
+		cancel()
+
+		if err != nil {
+			return false, errors.Errorf("foreach action iteration %d failed with error: %v", i, err)
+		}
+		if !success {
+			logger.Debugf("Foreach action iteration %d failed", i)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
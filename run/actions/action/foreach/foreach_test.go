@@ -0,0 +1,440 @@
+package foreach
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	actionMocks "github.com/wstool/wst/mocks/generated/run/actions/action"
+	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
+	parametersMocks "github.com/wstool/wst/mocks/generated/run/parameters"
+	parameterMocks "github.com/wstool/wst/mocks/generated/run/parameters/parameter"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/parameters/parameter"
+	"testing"
+	"time"
+)
+
+func TestCreateActionMaker(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	parametersMakerMock := parametersMocks.NewMockMaker(t)
+	runtimeMock := runtimeMocks.NewMockMaker(t)
+	tests := []struct {
+		name            string
+		fnd             app.Foundation
+		parametersMaker parameters.Maker
+		runtimeMock     runtime.Maker
+	}{
+		{
+			name:            "create maker",
+			fnd:             fndMock,
+			parametersMaker: parametersMakerMock,
+			runtimeMock:     runtimeMock,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CreateActionMaker(tt.fnd, tt.parametersMaker, tt.runtimeMock)
+			assert.Equal(t, tt.fnd, got.fnd)
+			assert.Equal(t, tt.parametersMaker, got.parametersMaker)
+			assert.Equal(t, tt.runtimeMock, got.runtimeMaker)
+		})
+	}
+}
+
+func TestActionMaker_Make(t *testing.T) {
+	actions := []types.Action{
+		&types.RequestAction{Service: "s1", Timeout: 4000},
+		&types.RequestAction{Service: "s2", Timeout: 4000},
+	}
+	serverParams := parameters.Parameters{
+		"urls": parameterMocks.NewMockParameter(t),
+	}
+	tests := []struct {
+		name                 string
+		config               *types.ForeachAction
+		defaultTimeout       int
+		setupMocks           func(*testing.T, *servicesMocks.MockServiceLocator, *actionMocks.MockMaker) action.Action
+		expectedTimeout      time.Duration
+		expectedServerParams parameters.Parameters
+		expectedWhen         action.When
+		expectedOnFailure    action.OnFailureType
+		expectError          bool
+		expectedErrorMsg     string
+	}{
+		{
+			name: "successful action creation with service and config timeout",
+			config: &types.ForeachAction{
+				Actions:        actions,
+				Service:        "svc",
+				Parameter:      "urls",
+				ItemParameter:  "url",
+				IndexParameter: "idx",
+				Timeout:        3000,
+				When:           "on_success",
+				OnFailure:      "skip",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator, am *actionMocks.MockMaker) action.Action {
+				svc := servicesMocks.NewMockService(t)
+				svc.On("ServerParameters").Return(serverParams)
+				sl.On("Find", "svc").Return(svc, nil)
+				body := actionMocks.NewMockAction(t)
+				am.On("MakeAction", &types.SequentialAction{
+					Actions:   actions,
+					Service:   "svc",
+					Timeout:   3000,
+					When:      "always",
+					OnFailure: "fail",
+				}, sl, 3000).Return(body, nil)
+				return body
+			},
+			expectedTimeout:      time.Duration(3000 * 1e6),
+			expectedServerParams: serverParams,
+			expectedWhen:         action.OnSuccess,
+			expectedOnFailure:    action.Skip,
+		},
+		{
+			name: "successful action creation without service and default timeout",
+			config: &types.ForeachAction{
+				Actions:        actions,
+				Parameter:      "urls",
+				ItemParameter:  "url",
+				IndexParameter: "idx",
+				When:           "always",
+				OnFailure:      "fail",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator, am *actionMocks.MockMaker) action.Action {
+				body := actionMocks.NewMockAction(t)
+				am.On("MakeAction", &types.SequentialAction{
+					Actions:   actions,
+					Timeout:   5000,
+					When:      "always",
+					OnFailure: "fail",
+				}, sl, 5000).Return(body, nil)
+				return body
+			},
+			expectedTimeout:   time.Duration(5000 * 1e6),
+			expectedWhen:      action.Always,
+			expectedOnFailure: action.Fail,
+		},
+		{
+			name: "failed action creation with action maker error",
+			config: &types.ForeachAction{
+				Actions:   actions,
+				Parameter: "urls",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator, am *actionMocks.MockMaker) action.Action {
+				am.On("MakeAction", &types.SequentialAction{
+					Actions:   actions,
+					Timeout:   5000,
+					When:      "always",
+					OnFailure: "fail",
+				}, sl, 5000).Return(nil, errors.New("action creation failed"))
+				return nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "action creation failed",
+		},
+		{
+			name: "failed action creation with service not found",
+			config: &types.ForeachAction{
+				Actions:   actions,
+				Service:   "svc",
+				Parameter: "urls",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator, am *actionMocks.MockMaker) action.Action {
+				sl.On("Find", "svc").Return(nil, errors.New("not found"))
+				return nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "foreach action service not found: not found",
+		},
+		{
+			name: "failed action creation with empty parameter",
+			config: &types.ForeachAction{
+				Actions: actions,
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator, am *actionMocks.MockMaker) action.Action {
+				return nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "foreach action parameter cannot be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			parametersMakerMock := parametersMocks.NewMockMaker(t)
+			runtimeMakerMock := runtimeMocks.NewMockMaker(t)
+			m := &ActionMaker{
+				fnd:             fndMock,
+				parametersMaker: parametersMakerMock,
+				runtimeMaker:    runtimeMakerMock,
+			}
+
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			amMock := actionMocks.NewMockMaker(t)
+
+			expectedBody := tt.setupMocks(t, slMock, amMock)
+
+			got, err := m.Make(tt.config, slMock, tt.defaultTimeout, amMock)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				act, ok := got.(*Action)
+				assert.True(t, ok)
+				assert.Equal(t, fndMock, act.fnd)
+				assert.Equal(t, parametersMakerMock, act.parametersMaker)
+				assert.Equal(t, runtimeMakerMock, act.runtimeMaker)
+				assert.Equal(t, expectedBody, act.body)
+				assert.Equal(t, "urls", act.parameter)
+				assert.Equal(t, "url", act.itemParameter)
+				assert.Equal(t, "idx", act.indexParameter)
+				assert.Equal(t, tt.expectedServerParams, act.serviceParameters)
+				assert.Equal(t, tt.expectedTimeout, act.Timeout())
+				assert.Equal(t, tt.expectedWhen, act.When())
+				assert.Equal(t, tt.expectedOnFailure, act.OnFailure())
+			}
+		})
+	}
+}
+
+func TestAction_Execute(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMocks func(
+			*testing.T,
+			*parametersMocks.MockMaker,
+			*runtimeMocks.MockData,
+			*actionMocks.MockAction,
+			context.Context,
+		) parameters.Parameters
+		want        bool
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name: "successful execution over service parameter",
+			setupMocks: func(
+				t *testing.T,
+				pm *parametersMocks.MockMaker,
+				rd *runtimeMocks.MockData,
+				body *actionMocks.MockAction,
+				ctx context.Context,
+			) parameters.Parameters {
+				items := []parameter.Parameter{
+					parameterMocks.NewMockParameter(t),
+					parameterMocks.NewMockParameter(t),
+				}
+				urls := parameterMocks.NewMockParameter(t)
+				urls.On("Type").Return(parameter.ArrayType)
+				urls.On("ArrayValue").Return(items)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				for i, item := range items {
+					idx := parameterMocks.NewMockParameter(t)
+					iterRd := runtimeMocks.NewMockData(t)
+					pm.On("Make", types.Parameters{"idx": i}).Return(parameters.Parameters{"idx": idx}, nil).Once()
+					rd.On("WithParameters", parameters.Parameters{"idx": idx, "url": item}).Return(iterRd).Once()
+					body.On("Execute", ctx, iterRd).Return(true, nil).Once()
+				}
+				return parameters.Parameters{"urls": urls}
+			},
+			want: true,
+		},
+		{
+			name: "runtime parameter takes precedence",
+			setupMocks: func(
+				t *testing.T,
+				pm *parametersMocks.MockMaker,
+				rd *runtimeMocks.MockData,
+				body *actionMocks.MockAction,
+				ctx context.Context,
+			) parameters.Parameters {
+				item := parameterMocks.NewMockParameter(t)
+				urls := parameterMocks.NewMockParameter(t)
+				urls.On("Type").Return(parameter.ArrayType)
+				urls.On("ArrayValue").Return([]parameter.Parameter{item})
+				rd.On("Parameters").Return(parameters.Parameters{"urls": urls})
+				idx := parameterMocks.NewMockParameter(t)
+				iterRd := runtimeMocks.NewMockData(t)
+				pm.On("Make", types.Parameters{"idx": 0}).Return(parameters.Parameters{"idx": idx}, nil).Once()
+				rd.On("WithParameters", parameters.Parameters{"idx": idx, "url": item}).Return(iterRd).Once()
+				body.On("Execute", ctx, iterRd).Return(true, nil).Once()
+				return parameters.Parameters{"urls": parameterMocks.NewMockParameter(t)}
+			},
+			want: true,
+		},
+		{
+			name: "failed iteration stops execution",
+			setupMocks: func(
+				t *testing.T,
+				pm *parametersMocks.MockMaker,
+				rd *runtimeMocks.MockData,
+				body *actionMocks.MockAction,
+				ctx context.Context,
+			) parameters.Parameters {
+				item := parameterMocks.NewMockParameter(t)
+				urls := parameterMocks.NewMockParameter(t)
+				urls.On("Type").Return(parameter.ArrayType)
+				urls.On("ArrayValue").Return([]parameter.Parameter{item, parameterMocks.NewMockParameter(t)})
+				rd.On("Parameters").Return(parameters.Parameters{})
+				idx := parameterMocks.NewMockParameter(t)
+				iterRd := runtimeMocks.NewMockData(t)
+				pm.On("Make", types.Parameters{"idx": 0}).Return(parameters.Parameters{"idx": idx}, nil).Once()
+				rd.On("WithParameters", parameters.Parameters{"idx": idx, "url": item}).Return(iterRd).Once()
+				body.On("Execute", ctx, iterRd).Return(false, nil).Once()
+				return parameters.Parameters{"urls": urls}
+			},
+			want: false,
+		},
+		{
+			name: "iteration error stops execution",
+			setupMocks: func(
+				t *testing.T,
+				pm *parametersMocks.MockMaker,
+				rd *runtimeMocks.MockData,
+				body *actionMocks.MockAction,
+				ctx context.Context,
+			) parameters.Parameters {
+				item := parameterMocks.NewMockParameter(t)
+				urls := parameterMocks.NewMockParameter(t)
+				urls.On("Type").Return(parameter.ArrayType)
+				urls.On("ArrayValue").Return([]parameter.Parameter{item})
+				rd.On("Parameters").Return(parameters.Parameters{})
+				idx := parameterMocks.NewMockParameter(t)
+				iterRd := runtimeMocks.NewMockData(t)
+				pm.On("Make", types.Parameters{"idx": 0}).Return(parameters.Parameters{"idx": idx}, nil).Once()
+				rd.On("WithParameters", parameters.Parameters{"idx": idx, "url": item}).Return(iterRd).Once()
+				body.On("Execute", ctx, iterRd).Return(false, errors.New("body fail")).Once()
+				return parameters.Parameters{"urls": urls}
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "foreach action iteration 0 failed with error: body fail",
+		},
+		{
+			name: "missing parameter",
+			setupMocks: func(
+				t *testing.T,
+				pm *parametersMocks.MockMaker,
+				rd *runtimeMocks.MockData,
+				body *actionMocks.MockAction,
+				ctx context.Context,
+			) parameters.Parameters {
+				rd.On("Parameters").Return(parameters.Parameters{})
+				return parameters.Parameters{}
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "foreach action parameter urls not found",
+		},
+		{
+			name: "parameter is not an array",
+			setupMocks: func(
+				t *testing.T,
+				pm *parametersMocks.MockMaker,
+				rd *runtimeMocks.MockData,
+				body *actionMocks.MockAction,
+				ctx context.Context,
+			) parameters.Parameters {
+				urls := parameterMocks.NewMockParameter(t)
+				urls.On("Type").Return(parameter.StringType)
+				rd.On("Parameters").Return(parameters.Parameters{})
+				return parameters.Parameters{"urls": urls}
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "foreach action parameter urls is not an array",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			parametersMakerMock := parametersMocks.NewMockMaker(t)
+			runMakerMock := runtimeMocks.NewMockMaker(t)
+			runDataMock := runtimeMocks.NewMockData(t)
+			bodyMock := actionMocks.NewMockAction(t)
+
+			baseCtx, baseCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer baseCancel()
+			actCtx, actCancel := context.WithTimeout(baseCtx, 3*time.Second)
+			defer actCancel()
+			timeout := 3 * time.Second
+
+			cancel := context.CancelFunc(func() {})
+			runMakerMock.On("MakeContextWithTimeout", baseCtx, timeout).Return(actCtx, cancel).Maybe()
+			bodyMock.On("Timeout").Return(timeout).Maybe()
+
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+
+			serviceParams := tt.setupMocks(t, parametersMakerMock, runDataMock, bodyMock, actCtx)
+
+			a := &Action{
+				fnd:               fndMock,
+				parametersMaker:   parametersMakerMock,
+				runtimeMaker:      runMakerMock,
+				body:              bodyMock,
+				parameter:         "urls",
+				itemParameter:     "url",
+				indexParameter:    "idx",
+				serviceParameters: serviceParams,
+			}
+
+			got, err := a.Execute(baseCtx, runDataMock)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAction_Timeout(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:     fndMock,
+		timeout: 2000 * time.Millisecond,
+	}
+	assert.Equal(t, 2000*time.Millisecond, a.Timeout())
+}
+
+func TestAction_OnFailure(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:       fndMock,
+		when:      action.OnSuccess,
+		onFailure: action.Skip,
+	}
+	assert.Equal(t, action.Skip, a.OnFailure())
+}
+
+func TestAction_When(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:  fndMock,
+		when: action.OnSuccess,
+	}
+	assert.Equal(t, action.OnSuccess, a.When())
+}
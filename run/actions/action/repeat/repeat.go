@@ -0,0 +1,173 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repeat
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/services"
+	"time"
+)
+
+type Maker interface {
+	Make(
+		config *types.RepeatAction,
+		sl services.ServiceLocator,
+		defaultTimeout int,
+		actionMaker action.Maker,
+	) (action.Action, error)
+}
+
+type ActionMaker struct {
+	fnd             app.Foundation
+	parametersMaker parameters.Maker
+	runtimeMaker    runtime.Maker
+}
+
+func CreateActionMaker(
+	fnd app.Foundation,
+	parametersMaker parameters.Maker,
+	runtimeMaker runtime.Maker,
+) *ActionMaker {
+	return &ActionMaker{
+		fnd:             fnd,
+		parametersMaker: parametersMaker,
+		runtimeMaker:    runtimeMaker,
+	}
+}
+
+func (m *ActionMaker) Make(
+	config *types.RepeatAction,
+	sl services.ServiceLocator,
+	defaultTimeout int,
+	actionMaker action.Maker,
+) (action.Action, error) {
+	if config.Count < 0 || config.Duration < 0 || config.Delay < 0 {
+		return nil, errors.New("repeat action count, duration and delay cannot be negative")
+	}
+	if config.Count == 0 && config.Duration == 0 {
+		return nil, errors.New("repeat action requires count or duration to be set")
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	// The body is run as a single sequential action so the inner actions keep the usual when logic.
+	body, err := actionMaker.MakeAction(&types.SequentialAction{
+		Actions:   config.Actions,
+		Timeout:   config.Timeout,
+		When:      string(action.Always),
+		OnFailure: string(action.Fail),
+	}, sl, config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Action{
+		fnd:             m.fnd,
+		parametersMaker: m.parametersMaker,
+		runtimeMaker:    m.runtimeMaker,
+		body:            body,
+		count:           config.Count,
+		duration:        time.Duration(config.Duration * 1e6),
+		delay:           time.Duration(config.Delay * 1e6),
+		indexParameter:  config.IndexParameter,
+		timeout:         time.Duration(config.Timeout * 1e6),
+		when:            action.When(config.When),
+		onFailure:       action.OnFailureType(config.OnFailure),
+	}, nil
+}
+
+type Action struct {
+	fnd             app.Foundation
+	parametersMaker parameters.Maker
+	runtimeMaker    runtime.Maker
+	body            action.Action
+	count           int
+	duration        time.Duration
+	delay           time.Duration
+	indexParameter  string
+	timeout         time.Duration
+	when            action.When
+	onFailure       action.OnFailureType
+}
+
+func (a *Action) When() action.When {
+	return a.when
+}
+
+func (a *Action) OnFailure() action.OnFailureType {
+	return a.onFailure
+}
+
+func (a *Action) Timeout() time.Duration {
+	return a.timeout
+}
+
+func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error) {
+	logger := a.fnd.Logger()
+	logger.Infof("Executing repeat action")
+
+	// Duration context is only used for checking whether next iteration should start.
+	durationCtx, cancel := a.runtimeMaker.MakeContextWithTimeout(ctx, a.duration)
+	defer cancel()
+
+	for i := 0; a.count == 0 || i < a.count; i++ {
+		if i > 0 {
+			if err := a.fnd.Sleep(durationCtx, a.delay); err != nil {
+				if ctx.Err() != nil {
+					return false, err
+				}
+				break
+			}
+		}
+		if durationCtx.Err() != nil {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			break
+		}
+
+		params, err := a.parametersMaker.Make(types.Parameters{a.indexParameter: i})
+		if err != nil {
+			return false, err
+		}
+
+		logger.Debugf("Executing repeat action iteration %d", i)
+		actCtx, actCancel := a.runtimeMaker.MakeContextWithTimeout(ctx, a.body.Timeout())
+		success, err := a.body.Execute(actCtx, runData.WithParameters(params))
+		actCancel()
+
+		if err != nil {
+			return false, errors.Errorf("repeat action iteration %d failed with error: %v", i, err)
+		}
+		if !success {
+			logger.Debugf("Repeat action iteration %d failed", i)
+			return false, nil
+		}
+		if a.fnd.DryRun() {
+			// Single iteration is enough for dry run.
+			break
+		}
+	}
+
+	return true, nil
+}
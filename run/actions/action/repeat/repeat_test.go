@@ -0,0 +1,449 @@
+package repeat
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	actionMocks "github.com/wstool/wst/mocks/generated/run/actions/action"
+	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
+	parametersMocks "github.com/wstool/wst/mocks/generated/run/parameters"
+	parameterMocks "github.com/wstool/wst/mocks/generated/run/parameters/parameter"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/parameters"
+	"testing"
+	"time"
+)
+
+func TestCreateActionMaker(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	parametersMakerMock := parametersMocks.NewMockMaker(t)
+	runtimeMock := runtimeMocks.NewMockMaker(t)
+	tests := []struct {
+		name            string
+		fnd             app.Foundation
+		parametersMaker parameters.Maker
+		runtimeMock     runtime.Maker
+	}{
+		{
+			name:            "create maker",
+			fnd:             fndMock,
+			parametersMaker: parametersMakerMock,
+			runtimeMock:     runtimeMock,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CreateActionMaker(tt.fnd, tt.parametersMaker, tt.runtimeMock)
+			assert.Equal(t, tt.fnd, got.fnd)
+			assert.Equal(t, tt.parametersMaker, got.parametersMaker)
+			assert.Equal(t, tt.runtimeMock, got.runtimeMaker)
+		})
+	}
+}
+
+func TestActionMaker_Make(t *testing.T) {
+	actions := []types.Action{
+		&types.RequestAction{Service: "s1", Timeout: 4000},
+		&types.RequestAction{Service: "s2", Timeout: 4000},
+	}
+	tests := []struct {
+		name              string
+		config            *types.RepeatAction
+		defaultTimeout    int
+		passedTimeout     int
+		actionMakerErr    error
+		expectedTimeout   time.Duration
+		expectedCount     int
+		expectedDuration  time.Duration
+		expectedDelay     time.Duration
+		expectedWhen      action.When
+		expectedOnFailure action.OnFailureType
+		expectError       bool
+		expectedErrorMsg  string
+	}{
+		{
+			name: "successful action creation with count and config timeout",
+			config: &types.RepeatAction{
+				Actions:        actions,
+				Count:          5,
+				Delay:          100,
+				IndexParameter: "iteration",
+				Timeout:        3000,
+				When:           "on_success",
+				OnFailure:      "skip",
+			},
+			defaultTimeout:    5000,
+			passedTimeout:     3000,
+			expectedTimeout:   time.Duration(3000 * 1e6),
+			expectedCount:     5,
+			expectedDelay:     time.Duration(100 * 1e6),
+			expectedWhen:      action.OnSuccess,
+			expectedOnFailure: action.Skip,
+		},
+		{
+			name: "successful action creation with duration and default timeout",
+			config: &types.RepeatAction{
+				Actions:        actions,
+				Duration:       2000,
+				IndexParameter: "iteration",
+				When:           "always",
+				OnFailure:      "fail",
+			},
+			defaultTimeout:    5000,
+			passedTimeout:     5000,
+			expectedTimeout:   time.Duration(5000 * 1e6),
+			expectedDuration:  time.Duration(2000 * 1e6),
+			expectedWhen:      action.Always,
+			expectedOnFailure: action.Fail,
+		},
+		{
+			name: "failed action creation with action maker error",
+			config: &types.RepeatAction{
+				Actions: actions,
+				Count:   2,
+			},
+			defaultTimeout:   5000,
+			passedTimeout:    5000,
+			actionMakerErr:   errors.New("action creation failed"),
+			expectError:      true,
+			expectedErrorMsg: "action creation failed",
+		},
+		{
+			name: "failed action creation without count and duration",
+			config: &types.RepeatAction{
+				Actions: actions,
+			},
+			defaultTimeout:   5000,
+			expectError:      true,
+			expectedErrorMsg: "repeat action requires count or duration to be set",
+		},
+		{
+			name: "failed action creation with negative delay",
+			config: &types.RepeatAction{
+				Actions: actions,
+				Count:   2,
+				Delay:   -1,
+			},
+			defaultTimeout:   5000,
+			expectError:      true,
+			expectedErrorMsg: "repeat action count, duration and delay cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			parametersMakerMock := parametersMocks.NewMockMaker(t)
+			runtimeMakerMock := runtimeMocks.NewMockMaker(t)
+			m := &ActionMaker{
+				fnd:             fndMock,
+				parametersMaker: parametersMakerMock,
+				runtimeMaker:    runtimeMakerMock,
+			}
+
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			amMock := actionMocks.NewMockMaker(t)
+			bodyMock := actionMocks.NewMockAction(t)
+
+			if tt.passedTimeout > 0 {
+				bodyConfig := &types.SequentialAction{
+					Actions:   actions,
+					Timeout:   tt.passedTimeout,
+					When:      "always",
+					OnFailure: "fail",
+				}
+				if tt.actionMakerErr != nil {
+					amMock.On("MakeAction", bodyConfig, slMock, tt.passedTimeout).Return(nil, tt.actionMakerErr)
+				} else {
+					amMock.On("MakeAction", bodyConfig, slMock, tt.passedTimeout).Return(bodyMock, nil)
+				}
+			}
+
+			got, err := m.Make(tt.config, slMock, tt.defaultTimeout, amMock)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				act, ok := got.(*Action)
+				assert.True(t, ok)
+				assert.Equal(t, fndMock, act.fnd)
+				assert.Equal(t, parametersMakerMock, act.parametersMaker)
+				assert.Equal(t, runtimeMakerMock, act.runtimeMaker)
+				assert.Equal(t, bodyMock, act.body)
+				assert.Equal(t, tt.expectedCount, act.count)
+				assert.Equal(t, tt.expectedDuration, act.duration)
+				assert.Equal(t, tt.expectedDelay, act.delay)
+				assert.Equal(t, "iteration", act.indexParameter)
+				assert.Equal(t, tt.expectedTimeout, act.Timeout())
+				assert.Equal(t, tt.expectedWhen, act.When())
+				assert.Equal(t, tt.expectedOnFailure, act.OnFailure())
+			}
+		})
+	}
+}
+
+func TestAction_Execute(t *testing.T) {
+	expiredCtx, expiredCancel := context.WithCancel(context.Background())
+	expiredCancel()
+
+	tests := []struct {
+		name        string
+		count       int
+		duration    time.Duration
+		delay       time.Duration
+		durationCtx context.Context
+		setupMocks  func(
+			*testing.T,
+			*appMocks.MockFoundation,
+			*parametersMocks.MockMaker,
+			*runtimeMocks.MockMaker,
+			*runtimeMocks.MockData,
+			*actionMocks.MockAction,
+			context.Context,
+		)
+		want        bool
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:  "successful execution of all iterations",
+			count: 3,
+			delay: 10 * time.Millisecond,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				pm *parametersMocks.MockMaker,
+				rm *runtimeMocks.MockMaker,
+				rd *runtimeMocks.MockData,
+				body *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				for i := 0; i < 3; i++ {
+					params := parameters.Parameters{"iteration": parameterMocks.NewMockParameter(t)}
+					iterRd := runtimeMocks.NewMockData(t)
+					pm.On("Make", types.Parameters{"iteration": i}).Return(params, nil).Once()
+					rd.On("WithParameters", params).Return(iterRd).Once()
+					body.On("Execute", ctx, iterRd).Return(true, nil).Once()
+				}
+				fnd.On("Sleep", ctx, 10*time.Millisecond).Return(nil).Times(2)
+				fnd.On("DryRun").Return(false).Times(3)
+			},
+			want: true,
+		},
+		{
+			name:  "single iteration in dry run",
+			count: 3,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				pm *parametersMocks.MockMaker,
+				rm *runtimeMocks.MockMaker,
+				rd *runtimeMocks.MockData,
+				body *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				params := parameters.Parameters{"iteration": parameterMocks.NewMockParameter(t)}
+				iterRd := runtimeMocks.NewMockData(t)
+				pm.On("Make", types.Parameters{"iteration": 0}).Return(params, nil).Once()
+				rd.On("WithParameters", params).Return(iterRd).Once()
+				body.On("Execute", ctx, iterRd).Return(true, nil).Once()
+				fnd.On("DryRun").Return(true).Once()
+			},
+			want: true,
+		},
+		{
+			name:  "failed iteration stops execution",
+			count: 3,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				pm *parametersMocks.MockMaker,
+				rm *runtimeMocks.MockMaker,
+				rd *runtimeMocks.MockData,
+				body *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				params0 := parameters.Parameters{"iteration": parameterMocks.NewMockParameter(t)}
+				params1 := parameters.Parameters{"iteration": parameterMocks.NewMockParameter(t)}
+				iterRd0 := runtimeMocks.NewMockData(t)
+				iterRd1 := runtimeMocks.NewMockData(t)
+				pm.On("Make", types.Parameters{"iteration": 0}).Return(params0, nil).Once()
+				pm.On("Make", types.Parameters{"iteration": 1}).Return(params1, nil).Once()
+				rd.On("WithParameters", params0).Return(iterRd0).Once()
+				rd.On("WithParameters", params1).Return(iterRd1).Once()
+				body.On("Execute", ctx, iterRd0).Return(true, nil).Once()
+				body.On("Execute", ctx, iterRd1).Return(false, nil).Once()
+				fnd.On("Sleep", ctx, time.Duration(0)).Return(nil).Once()
+				fnd.On("DryRun").Return(false).Once()
+			},
+			want: false,
+		},
+		{
+			name:  "iteration error stops execution",
+			count: 3,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				pm *parametersMocks.MockMaker,
+				rm *runtimeMocks.MockMaker,
+				rd *runtimeMocks.MockData,
+				body *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				params := parameters.Parameters{"iteration": parameterMocks.NewMockParameter(t)}
+				iterRd := runtimeMocks.NewMockData(t)
+				pm.On("Make", types.Parameters{"iteration": 0}).Return(params, nil).Once()
+				rd.On("WithParameters", params).Return(iterRd).Once()
+				body.On("Execute", ctx, iterRd).Return(false, errors.New("body fail")).Once()
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "repeat action iteration 0 failed with error: body fail",
+		},
+		{
+			name:  "parameters error stops execution",
+			count: 3,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				pm *parametersMocks.MockMaker,
+				rm *runtimeMocks.MockMaker,
+				rd *runtimeMocks.MockData,
+				body *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				pm.On("Make", types.Parameters{"iteration": 0}).Return(nil, errors.New("params fail")).Once()
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "params fail",
+		},
+		{
+			name:     "duration elapsed during delay",
+			duration: 100 * time.Millisecond,
+			delay:    50 * time.Millisecond,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				pm *parametersMocks.MockMaker,
+				rm *runtimeMocks.MockMaker,
+				rd *runtimeMocks.MockData,
+				body *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+				params := parameters.Parameters{"iteration": parameterMocks.NewMockParameter(t)}
+				iterRd := runtimeMocks.NewMockData(t)
+				pm.On("Make", types.Parameters{"iteration": 0}).Return(params, nil).Once()
+				rd.On("WithParameters", params).Return(iterRd).Once()
+				body.On("Execute", ctx, iterRd).Return(true, nil).Once()
+				fnd.On("Sleep", ctx, 50*time.Millisecond).Return(context.DeadlineExceeded).Once()
+				fnd.On("DryRun").Return(false).Once()
+			},
+			want: true,
+		},
+		{
+			name:        "duration already elapsed",
+			duration:    100 * time.Millisecond,
+			durationCtx: expiredCtx,
+			setupMocks: func(
+				t *testing.T,
+				fnd *appMocks.MockFoundation,
+				pm *parametersMocks.MockMaker,
+				rm *runtimeMocks.MockMaker,
+				rd *runtimeMocks.MockData,
+				body *actionMocks.MockAction,
+				ctx context.Context,
+			) {
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			parametersMakerMock := parametersMocks.NewMockMaker(t)
+			runMakerMock := runtimeMocks.NewMockMaker(t)
+			runDataMock := runtimeMocks.NewMockData(t)
+			bodyMock := actionMocks.NewMockAction(t)
+
+			baseCtx, baseCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer baseCancel()
+			actCtx, actCancel := context.WithTimeout(baseCtx, 3*time.Second)
+			defer actCancel()
+			timeout := 3 * time.Second
+
+			durationCtx := tt.durationCtx
+			if durationCtx == nil {
+				durationCtx = actCtx
+			}
+			cancel := context.CancelFunc(func() {})
+			runMakerMock.On("MakeContextWithTimeout", baseCtx, tt.duration).Return(durationCtx, cancel).Once()
+			runMakerMock.On("MakeContextWithTimeout", baseCtx, timeout).Return(actCtx, cancel).Maybe()
+			bodyMock.On("Timeout").Return(timeout).Maybe()
+
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+
+			tt.setupMocks(t, fndMock, parametersMakerMock, runMakerMock, runDataMock, bodyMock, actCtx)
+
+			a := &Action{
+				fnd:             fndMock,
+				parametersMaker: parametersMakerMock,
+				runtimeMaker:    runMakerMock,
+				body:            bodyMock,
+				count:           tt.count,
+				duration:        tt.duration,
+				delay:           tt.delay,
+				indexParameter:  "iteration",
+			}
+
+			got, err := a.Execute(baseCtx, runDataMock)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAction_Timeout(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:     fndMock,
+		timeout: 2000 * time.Millisecond,
+	}
+	assert.Equal(t, 2000*time.Millisecond, a.Timeout())
+}
+
+func TestAction_OnFailure(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:       fndMock,
+		when:      action.OnSuccess,
+		onFailure: action.Skip,
+	}
+	assert.Equal(t, action.Skip, a.OnFailure())
+}
+
+func TestAction_When(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:  fndMock,
+		when: action.OnSuccess,
+	}
+	assert.Equal(t, action.OnSuccess, a.When())
+}
@@ -0,0 +1,299 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package request
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/services"
+)
+
+// requestBody holds the prepared request body that is either kept in memory or streamed from a file.
+type requestBody struct {
+	content     []byte
+	file        io.ReadCloser
+	size        int64
+	contentType string
+}
+
+// ValidateBody checks that only one body mode is used and that multipart parts are valid.
+func ValidateBody(body *types.RequestBody) error {
+	modes := 0
+	if body.Content != "" {
+		modes++
+	}
+	if len(body.Multipart) > 0 {
+		modes++
+	}
+	if body.File != "" {
+		modes++
+	}
+	if modes > 1 {
+		return errors.New("request body can set only one of content, multipart and file")
+	}
+
+	for i, part := range body.Multipart {
+		if part.Name == "" {
+			return errors.Errorf("multipart body part %d is missing name", i)
+		}
+		if part.Size < 0 {
+			return errors.Errorf("multipart body part %s has negative size %d", part.Name, part.Size)
+		}
+		sources := 0
+		if part.Content != "" {
+			sources++
+		}
+		if part.Script != "" {
+			sources++
+		}
+		if part.File != "" {
+			sources++
+		}
+		if part.Size > 0 {
+			sources++
+		}
+		if sources > 1 {
+			return errors.Errorf(
+				"multipart body part %s can set only one of content, script, file and size", part.Name)
+		}
+	}
+
+	return nil
+}
+
+// HasBody returns true if the body configuration sets any body content.
+func HasBody(body *types.RequestBody) bool {
+	return body != nil && (body.Content != "" || len(body.Multipart) > 0 || body.File != "")
+}
+
+func (a *Action) hasBody() bool {
+	return HasBody(a.body)
+}
+
+func (a *Action) hasChunkControl() bool {
+	return a.body.Transfer.Encoding == "chunked" && (a.body.Transfer.ChunkSize > 0 || a.body.Transfer.ChunkDelay > 0)
+}
+
+// bodyBuilder prepares the request body from the body configuration.
+type bodyBuilder struct {
+	fnd     app.Foundation
+	service services.Service
+	body    *types.RequestBody
+	runData runtime.Data
+	// inMemory forces reading of the body file to memory instead of streaming it.
+	inMemory bool
+}
+
+// ReadBody prepares the whole body in memory and returns it together with its content type (set only for
+// multipart bodies).
+func ReadBody(
+	fnd app.Foundation,
+	svc services.Service,
+	body *types.RequestBody,
+	runData runtime.Data,
+) ([]byte, string, error) {
+	b := &bodyBuilder{fnd: fnd, service: svc, body: body, runData: runData, inMemory: true}
+	reqBody, err := b.build()
+	if err != nil {
+		return nil, "", err
+	}
+	return reqBody.content, reqBody.contentType, nil
+}
+
+// prepareBody creates the request body from the configured body mode.
+func (a *Action) prepareBody(runData runtime.Data) (*requestBody, error) {
+	b := &bodyBuilder{
+		fnd:     a.fnd,
+		service: a.service,
+		body:    a.body,
+		runData: runData,
+		// Chunk controlled transfer needs the whole content so the file is read to memory.
+		inMemory: a.hasChunkControl(),
+	}
+	return b.build()
+}
+
+func (b *bodyBuilder) build() (*requestBody, error) {
+	if len(b.body.Multipart) > 0 {
+		return b.buildMultipart()
+	}
+	if b.body.File != "" {
+		return b.buildFile()
+	}
+	content := b.body.Content
+	if b.body.RenderTemplate {
+		var err error
+		content, err = RenderRuntimeTemplate(b.service, content, b.runData)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &requestBody{
+		content: []byte(content),
+		size:    int64(len(content)),
+	}, nil
+}
+
+func (b *bodyBuilder) buildFile() (*requestBody, error) {
+	if b.fnd.DryRun() {
+		return &requestBody{content: []byte{}}, nil
+	}
+	info, err := b.fnd.Fs().Stat(b.body.File)
+	if err != nil {
+		return nil, errors.Errorf("failed to stat request body file %s: %v", b.body.File, err)
+	}
+	file, err := b.fnd.Fs().Open(b.body.File)
+	if err != nil {
+		return nil, errors.Errorf("failed to open request body file %s: %v", b.body.File, err)
+	}
+	if b.inMemory {
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		if err != nil {
+			return nil, errors.Errorf("failed to read request body file %s: %v", b.body.File, err)
+		}
+		return &requestBody{
+			content: content,
+			size:    int64(len(content)),
+		}, nil
+	}
+	b.fnd.Logger().Debugf("Streaming request body from file %s (size: %d)", b.body.File, info.Size())
+	return &requestBody{
+		file: file,
+		size: info.Size(),
+	}, nil
+}
+
+func (b *bodyBuilder) buildMultipart() (*requestBody, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for _, part := range b.body.Multipart {
+		content, err := b.partContent(&part)
+		if err != nil {
+			return nil, err
+		}
+		header := make(textproto.MIMEHeader)
+		disposition := fmt.Sprintf(`form-data; name="%s"`, quoteEscaper.Replace(part.Name))
+		if filename := multipartPartFilename(&part); filename != "" {
+			disposition += fmt.Sprintf(`; filename="%s"`, quoteEscaper.Replace(filename))
+			header.Set("Content-Type", "application/octet-stream")
+		}
+		header.Set("Content-Disposition", disposition)
+		for key, value := range part.Headers {
+			header.Set(key, value)
+		}
+		partWriter, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = partWriter.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return &requestBody{
+		content:     buf.Bytes(),
+		size:        int64(buf.Len()),
+		contentType: writer.FormDataContentType(),
+	}, nil
+}
+
+func (b *bodyBuilder) partContent(part *types.RequestBodyPart) ([]byte, error) {
+	switch {
+	case part.Script != "":
+		scriptPath, ok := b.service.WorkspaceScriptPaths()[part.Script]
+		if !ok {
+			return nil, errors.Errorf("script %s not found for multipart body part %s", part.Script, part.Name)
+		}
+		return b.readPartFile(scriptPath, part.Name)
+	case part.File != "":
+		return b.readPartFile(part.File, part.Name)
+	case part.Size > 0:
+		content := make([]byte, part.Size)
+		if _, err := rand.Read(content); err != nil {
+			return nil, errors.Errorf("failed to generate content for multipart body part %s: %v", part.Name, err)
+		}
+		return content, nil
+	default:
+		return []byte(part.Content), nil
+	}
+}
+
+func (b *bodyBuilder) readPartFile(path, partName string) ([]byte, error) {
+	if b.fnd.DryRun() {
+		return []byte{}, nil
+	}
+	content, err := afero.ReadFile(b.fnd.Fs(), path)
+	if err != nil {
+		return nil, errors.Errorf("failed to read file %s for multipart body part %s: %v", path, partName, err)
+	}
+	return content, nil
+}
+
+// multipartPartFilename returns the filename of the part. File and script parts use the base name of their path
+// if the filename is not set.
+func multipartPartFilename(part *types.RequestBodyPart) string {
+	if part.Filename != "" {
+		return part.Filename
+	}
+	if part.File != "" {
+		return filepath.Base(part.File)
+	}
+	if part.Script != "" {
+		return part.Script
+	}
+	return ""
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// createBodyReader creates an io.Reader for the request body based on transfer configuration
+func (a *Action) createBodyReader(ctx context.Context, body *requestBody) io.Reader {
+	if body.file != nil {
+		return body.file
+	}
+
+	// If chunked encoding with chunk size or delay specified, use a custom reader
+	if a.hasChunkControl() {
+		return &chunkControlledReader{
+			ctx:        ctx,
+			fnd:        a.fnd,
+			data:       body.content,
+			chunkSize:  a.body.Transfer.ChunkSize,
+			chunkDelay: time.Duration(a.body.Transfer.ChunkDelay) * time.Millisecond,
+			offset:     0,
+		}
+	}
+
+	// For normal transfers or chunked without size/delay control, use bytes.Reader
+	return bytes.NewReader(body.content)
+}
@@ -0,0 +1,293 @@
+package request
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+)
+
+func TestValidateBody(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             *types.RequestBody
+		expectedErrorMsg string
+	}{
+		{
+			name: "valid content body",
+			body: &types.RequestBody{Content: "data"},
+		},
+		{
+			name: "valid multipart body",
+			body: &types.RequestBody{
+				Multipart: []types.RequestBodyPart{
+					{Name: "field", Content: "value"},
+					{Name: "upload", Size: 1024},
+				},
+			},
+		},
+		{
+			name: "invalid multiple body modes",
+			body: &types.RequestBody{
+				Content: "data",
+				File:    "/tmp/data.bin",
+			},
+			expectedErrorMsg: "request body can set only one of content, multipart and file",
+		},
+		{
+			name: "invalid part without name",
+			body: &types.RequestBody{
+				Multipart: []types.RequestBodyPart{{Content: "value"}},
+			},
+			expectedErrorMsg: "multipart body part 0 is missing name",
+		},
+		{
+			name: "invalid part with negative size",
+			body: &types.RequestBody{
+				Multipart: []types.RequestBodyPart{{Name: "upload", Size: -1}},
+			},
+			expectedErrorMsg: "multipart body part upload has negative size -1",
+		},
+		{
+			name: "invalid part with multiple sources",
+			body: &types.RequestBody{
+				Multipart: []types.RequestBodyPart{{Name: "upload", File: "/tmp/f", Size: 10}},
+			},
+			expectedErrorMsg: "multipart body part upload can set only one of content, script, file and size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBody(tt.body)
+			if tt.expectedErrorMsg != "" {
+				assert.EqualError(t, err, tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+type testPart struct {
+	name        string
+	filename    string
+	contentType string
+	content     string
+	size        int
+}
+
+func readMultipartParts(t *testing.T, body *requestBody) []testPart {
+	mediaType, params, err := mime.ParseMediaType(body.contentType)
+	require.NoError(t, err)
+	require.Equal(t, "multipart/form-data", mediaType)
+	reader := multipart.NewReader(strings.NewReader(string(body.content)), params["boundary"])
+	var parts []testPart
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+		parts = append(parts, testPart{
+			name:        part.FormName(),
+			filename:    part.FileName(),
+			contentType: part.Header.Get("Content-Type"),
+			content:     string(content),
+			size:        len(content),
+		})
+	}
+	return parts
+}
+
+func TestAction_prepareBody(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             *types.RequestBody
+		setupMocks       func(*testing.T, *appMocks.MockFoundation, *servicesMocks.MockService)
+		expectedContent  string
+		expectedParts    []testPart
+		expectedSize     int64
+		expectFile       bool
+		expectedErrorMsg string
+	}{
+		{
+			name:            "content body",
+			body:            &types.RequestBody{Content: "hello"},
+			expectedContent: "hello",
+			expectedSize:    5,
+		},
+		{
+			name: "multipart body with all part sources",
+			body: &types.RequestBody{
+				Multipart: []types.RequestBodyPart{
+					{Name: "field", Content: "value"},
+					{Name: "script", Script: "upload.php", Headers: types.Headers{"Content-Type": "text/plain"}},
+					{Name: "file", File: "/data/photo.jpg"},
+					{Name: "blob", Filename: "blob.bin", Size: 2048},
+				},
+			},
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fs := afero.NewMemMapFs()
+				require.NoError(t, afero.WriteFile(fs, "/ws/scripts/upload.php", []byte("<?php"), 0644))
+				require.NoError(t, afero.WriteFile(fs, "/data/photo.jpg", []byte("jpeg"), 0644))
+				fnd.On("DryRun").Return(false)
+				fnd.On("Fs").Return(fs)
+				svc.On("WorkspaceScriptPaths").Return(map[string]string{"upload.php": "/ws/scripts/upload.php"})
+			},
+			expectedParts: []testPart{
+				{name: "field", content: "value", size: 5},
+				{name: "script", filename: "upload.php", contentType: "text/plain", content: "<?php", size: 5},
+				{name: "file", filename: "photo.jpg", contentType: "application/octet-stream", content: "jpeg", size: 4},
+				{name: "blob", filename: "blob.bin", contentType: "application/octet-stream", size: 2048},
+			},
+		},
+		{
+			name: "multipart body in dry run",
+			body: &types.RequestBody{
+				Multipart: []types.RequestBodyPart{
+					{Name: "file", File: "/data/photo.jpg"},
+				},
+			},
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(true)
+			},
+			expectedParts: []testPart{
+				{name: "file", filename: "photo.jpg", contentType: "application/octet-stream"},
+			},
+		},
+		{
+			name: "multipart body with missing script",
+			body: &types.RequestBody{
+				Multipart: []types.RequestBodyPart{
+					{Name: "script", Script: "missing.php"},
+				},
+			},
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				svc.On("WorkspaceScriptPaths").Return(map[string]string{})
+			},
+			expectedErrorMsg: "script missing.php not found for multipart body part script",
+		},
+		{
+			name: "multipart body with missing file",
+			body: &types.RequestBody{
+				Multipart: []types.RequestBodyPart{
+					{Name: "file", File: "/data/missing.jpg"},
+				},
+			},
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				fnd.On("Fs").Return(afero.NewMemMapFs())
+			},
+			expectedErrorMsg: "failed to read file /data/missing.jpg for multipart body part file",
+		},
+		{
+			name: "file body streamed",
+			body: &types.RequestBody{File: "/data/upload.bin"},
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fs := afero.NewMemMapFs()
+				require.NoError(t, afero.WriteFile(fs, "/data/upload.bin", []byte("binary data"), 0644))
+				fnd.On("DryRun").Return(false)
+				fnd.On("Fs").Return(fs)
+				fnd.On("Logger").Return(external.NewMockLogger().SugaredLogger)
+			},
+			expectedContent: "binary data",
+			expectedSize:    11,
+			expectFile:      true,
+		},
+		{
+			name: "file body read for chunk control",
+			body: &types.RequestBody{
+				File: "/data/upload.bin",
+				Transfer: types.TransferConfig{
+					Encoding:  "chunked",
+					ChunkSize: 4,
+				},
+			},
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fs := afero.NewMemMapFs()
+				require.NoError(t, afero.WriteFile(fs, "/data/upload.bin", []byte("binary data"), 0644))
+				fnd.On("DryRun").Return(false)
+				fnd.On("Fs").Return(fs)
+			},
+			expectedContent: "binary data",
+			expectedSize:    11,
+		},
+		{
+			name: "file body in dry run",
+			body: &types.RequestBody{File: "/data/upload.bin"},
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(true)
+			},
+			expectedContent: "",
+			expectedSize:    0,
+		},
+		{
+			name: "file body not found",
+			body: &types.RequestBody{File: "/data/missing.bin"},
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				fnd.On("Fs").Return(afero.NewMemMapFs())
+			},
+			expectedErrorMsg: "failed to stat request body file /data/missing.bin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			svcMock := servicesMocks.NewMockService(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(t, fndMock, svcMock)
+			}
+			a := &Action{
+				fnd:     fndMock,
+				service: svcMock,
+				body:    tt.body,
+			}
+
+			body, err := a.prepareBody(nil)
+
+			if tt.expectedErrorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expectedParts != nil {
+				assert.Equal(t, int64(len(body.content)), body.size)
+				parts := readMultipartParts(t, body)
+				for i := range parts {
+					if tt.expectedParts[i].content == "" {
+						parts[i].content = ""
+					}
+				}
+				assert.Equal(t, tt.expectedParts, parts)
+				return
+			}
+			assert.Equal(t, tt.expectedSize, body.size)
+			if tt.expectFile {
+				require.NotNil(t, body.file)
+				content, err := io.ReadAll(a.createBodyReader(context.Background(), body))
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedContent, string(content))
+				assert.NoError(t, body.file.Close())
+			} else {
+				assert.Nil(t, body.file)
+				assert.Equal(t, tt.expectedContent, string(body.content))
+			}
+		})
+	}
+}
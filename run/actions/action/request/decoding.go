@@ -0,0 +1,96 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package request
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// Decoding specifies how the response body content encoding is handled.
+type Decoding string
+
+const (
+	// DecodingAuto requests and decodes gzip in the same way as the transparent decoding of the transport.
+	DecodingAuto Decoding = "auto"
+	// DecodingRaw keeps the body as it was received.
+	DecodingRaw Decoding = "raw"
+	// DecodingDecode decodes the body based on its content encoding and keeps the raw body as well.
+	DecodingDecode Decoding = "decode"
+)
+
+// Accept-Encoding header values that are sent if the request does not set the header. The raw value matches the one
+// that is sent by the transport for transparent decoding and it is also used for the auto decoding.
+const (
+	rawAcceptEncoding    = "gzip"
+	decodeAcceptEncoding = "gzip, deflate, br, zstd"
+)
+
+// decodeBody decodes the body encoded by the content encodings in the order they were applied.
+func decodeBody(body string, contentEncoding string) (string, error) {
+	encodings := strings.Split(contentEncoding, ",")
+	for i := len(encodings) - 1; i >= 0; i-- {
+		encoding := strings.ToLower(strings.TrimSpace(encodings[i]))
+		decoded, err := decodeContent([]byte(body), encoding)
+		if err != nil {
+			return "", errors.Errorf("failed to decode %s response body: %v", encoding, err)
+		}
+		body = string(decoded)
+	}
+	return body, nil
+}
+
+func decodeContent(content []byte, encoding string) ([]byte, error) {
+	var reader io.Reader
+	switch encoding {
+	case "", "identity":
+		return content, nil
+	case "gzip", "x-gzip":
+		gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	case "deflate":
+		// The deflate encoding should be zlib wrapped but some servers send raw deflate data.
+		zlibReader, err := zlib.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return io.ReadAll(flate.NewReader(bytes.NewReader(content)))
+		}
+		defer zlibReader.Close()
+		reader = zlibReader
+	case "br":
+		reader = brotli.NewReader(bytes.NewReader(content))
+	case "zstd":
+		zstdReader, err := zstd.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		defer zstdReader.Close()
+		reader = zstdReader
+	default:
+		return nil, errors.New("unsupported content encoding")
+	}
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,236 @@
+package request
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/instances/runtime"
+)
+
+// encodeContent returns the content encoded by the content encoding.
+func encodeContent(t *testing.T, encoding string, content string) string {
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+	var err error
+	switch encoding {
+	case "gzip":
+		writer = gzip.NewWriter(&buf)
+	case "deflate":
+		writer = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		writer, err = flate.NewWriter(&buf, flate.DefaultCompression)
+	case "br":
+		writer = brotli.NewWriter(&buf)
+	case "zstd":
+		writer, err = zstd.NewWriter(&buf)
+	default:
+		t.Fatalf("unsupported test encoding %s", encoding)
+	}
+	require.NoError(t, err)
+	_, err = writer.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.String()
+}
+
+func Test_decodeBody(t *testing.T) {
+	binary := string([]byte{0x00, 0xff, 0xfe, 0x01})
+	tests := []struct {
+		name            string
+		body            func(t *testing.T) string
+		contentEncoding string
+		want            string
+		expectError     bool
+		errorMsg        string
+	}{
+		{
+			name:            "no encoding",
+			body:            func(t *testing.T) string { return "test" },
+			contentEncoding: "",
+			want:            "test",
+		},
+		{
+			name:            "identity encoding",
+			body:            func(t *testing.T) string { return "test" },
+			contentEncoding: "identity",
+			want:            "test",
+		},
+		{
+			name:            "gzip encoding",
+			body:            func(t *testing.T) string { return encodeContent(t, "gzip", "test") },
+			contentEncoding: "gzip",
+			want:            "test",
+		},
+		{
+			name:            "x-gzip encoding",
+			body:            func(t *testing.T) string { return encodeContent(t, "gzip", "test") },
+			contentEncoding: "x-gzip",
+			want:            "test",
+		},
+		{
+			name:            "deflate encoding",
+			body:            func(t *testing.T) string { return encodeContent(t, "deflate", "test") },
+			contentEncoding: "deflate",
+			want:            "test",
+		},
+		{
+			name:            "raw deflate encoding",
+			body:            func(t *testing.T) string { return encodeContent(t, "raw-deflate", "test") },
+			contentEncoding: "deflate",
+			want:            "test",
+		},
+		{
+			name:            "brotli encoding",
+			body:            func(t *testing.T) string { return encodeContent(t, "br", "test") },
+			contentEncoding: "BR",
+			want:            "test",
+		},
+		{
+			name:            "zstd encoding of binary content",
+			body:            func(t *testing.T) string { return encodeContent(t, "zstd", binary) },
+			contentEncoding: "zstd",
+			want:            binary,
+		},
+		{
+			name: "multiple encodings",
+			body: func(t *testing.T) string {
+				return encodeContent(t, "br", encodeContent(t, "gzip", "test"))
+			},
+			contentEncoding: "gzip, br",
+			want:            "test",
+		},
+		{
+			name:            "unsupported encoding",
+			body:            func(t *testing.T) string { return "test" },
+			contentEncoding: "compress",
+			expectError:     true,
+			errorMsg:        "failed to decode compress response body: unsupported content encoding",
+		},
+		{
+			name:            "invalid gzip content",
+			body:            func(t *testing.T) string { return "test" },
+			contentEncoding: "gzip",
+			expectError:     true,
+			errorMsg:        "failed to decode gzip response body: unexpected EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeBody(tt.body(t), tt.contentEncoding)
+			if tt.expectError {
+				assert.EqualError(t, err, tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAction_Execute_GzipResponse(t *testing.T) {
+	content := strings.Repeat("compressible content ", 100)
+	encodedBody := encodeContent(t, "gzip", content)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") == "" {
+			_, _ = w.Write([]byte(content))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write([]byte(encodedBody))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		decoding Decoding
+		expected ResponseData
+	}{
+		{
+			name:     "auto decoding measures received size",
+			decoding: DecodingAuto,
+			expected: ResponseData{
+				Body:            content,
+				BodySize:        len(encodedBody),
+				ContentEncoding: "gzip",
+			},
+		},
+		{
+			name:     "raw decoding keeps received body",
+			decoding: DecodingRaw,
+			expected: ResponseData{
+				Body:            encodedBody,
+				BodySize:        len(encodedBody),
+				ContentEncoding: "gzip",
+			},
+		},
+		{
+			name:     "decode decoding keeps raw body",
+			decoding: DecodingDecode,
+			expected: ResponseData{
+				Body:            content,
+				BodySize:        len(encodedBody),
+				ContentEncoding: "gzip",
+				RawBody:         encodedBody,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			svcMock := servicesMocks.NewMockService(t)
+			fndMock.On("Logger").Return(external.NewMockLogger().SugaredLogger)
+			fndMock.On("HttpClient", mock.Anything).Return(func(tr *http.Transport) app.HttpClient {
+				return app.NewRealHttpClient(tr)
+			})
+			svcMock.On("PublicUrl", "http", "/").Return(server.URL, nil)
+			runData := runtime.CreateMaker(fndMock).MakeData()
+
+			a := &Action{
+				fnd:        fndMock,
+				service:    svcMock,
+				id:         "last",
+				scheme:     "http",
+				path:       "/",
+				encodePath: true,
+				method:     "GET",
+				body:       &types.RequestBody{},
+				tls:        &types.TLSClientConfig{},
+				protocols:  []Protocol{ProtocolHTTP11},
+				decoding:   tt.decoding,
+			}
+
+			got, err := a.Execute(context.Background(), runData)
+
+			require.NoError(t, err)
+			assert.True(t, got)
+			data, ok := runData.Load("response/last")
+			require.True(t, ok)
+			responseData := data.(ResponseData)
+			assert.Equal(t, tt.expected.Body, responseData.Body)
+			assert.Equal(t, tt.expected.BodySize, responseData.BodySize)
+			assert.Less(t, responseData.BodySize, len(content))
+			assert.Equal(t, tt.expected.ContentEncoding, responseData.ContentEncoding)
+			assert.Equal(t, tt.expected.RawBody, responseData.RawBody)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package request
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/wstool/wst/run/instances/runtime"
+)
+
+// NewHttp3Transport creates an in-process QUIC based HTTP/3 transport. If the alternative address is set, the
+// connections are dialed to it instead of the request authority (used for Alt-Svc upgrades).
+func NewHttp3Transport(tlsConfig *tls.Config, altAddress string) *http3.Transport {
+	tr := &http3.Transport{
+		TLSClientConfig: tlsConfig,
+	}
+	if altAddress != "" {
+		tr.Dial = func(ctx context.Context, _ string, tlsCfg *tls.Config, cfg *quic.Config) (*quic.Conn, error) {
+			return quic.DialAddrEarly(ctx, altAddress, tlsCfg, cfg)
+		}
+	}
+	return tr
+}
+
+func altSvcKey(host string) string {
+	return fmt.Sprintf("alt-svc/%s", host)
+}
+
+// ParseAltSvcHttp3 returns the address advertised for h3 in the Alt-Svc header value. The authority without
+// host uses the passed request host.
+func ParseAltSvcHttp3(value string, host string) (string, bool) {
+	for _, entry := range strings.Split(value, ",") {
+		alternative := strings.TrimSpace(strings.SplitN(entry, ";", 2)[0])
+		protocol, authority, found := strings.Cut(alternative, "=")
+		if !found || strings.TrimSpace(protocol) != "h3" {
+			continue
+		}
+		authority = strings.Trim(strings.TrimSpace(authority), `"`)
+		altHost, altPort, err := net.SplitHostPort(authority)
+		if err != nil || altPort == "" {
+			continue
+		}
+		if altHost == "" {
+			altHost = host
+		}
+		return net.JoinHostPort(altHost, altPort), true
+	}
+	return "", false
+}
+
+func (a *Action) hasProtocol(protocol Protocol) bool {
+	for _, p := range a.protocols {
+		if p == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// selectHttp3 decides whether the request is sent over HTTP/3. If HTTP/3 is the only protocol, it is used
+// directly. Otherwise, it is used only if a previous response for the same origin advertised it in Alt-Svc.
+func (a *Action) selectHttp3(reqUrl *url.URL, runData runtime.Data) (bool, string) {
+	if !a.hasProtocol(ProtocolHTTP3) {
+		return false, ""
+	}
+	if len(a.protocols) == 1 {
+		return true, ""
+	}
+	if data, ok := runData.Load(altSvcKey(reqUrl.Host)); ok {
+		if altAddress, ok := data.(string); ok {
+			a.fnd.Logger().Debugf("Upgrading request to HTTP/3 using alternative service %s", altAddress)
+			return true, altAddress
+		}
+	}
+	return false, ""
+}
+
+// storeAltSvc records the HTTP/3 alternative service from the response so following requests can upgrade.
+func (a *Action) storeAltSvc(reqUrl *url.URL, altSvc string, runData runtime.Data) error {
+	if altSvc == "" {
+		return nil
+	}
+	altAddress, ok := ParseAltSvcHttp3(altSvc, reqUrl.Hostname())
+	if !ok {
+		return nil
+	}
+	a.fnd.Logger().Debugf("Storing HTTP/3 alternative service %s for %s", altAddress, reqUrl.Host)
+	return runData.Store(altSvcKey(reqUrl.Host), altAddress)
+}
@@ -0,0 +1,183 @@
+package request
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/instances/runtime"
+)
+
+func TestParseAltSvcHttp3(t *testing.T) {
+	tests := []struct {
+		name            string
+		value           string
+		host            string
+		expectedAddress string
+		expectedFound   bool
+	}{
+		{
+			name:            "port only authority",
+			value:           `h3=":443"; ma=86400`,
+			host:            "example.com",
+			expectedAddress: "example.com:443",
+			expectedFound:   true,
+		},
+		{
+			name:            "full authority after other protocols",
+			value:           `h3-29=":8443", h2="alt.example.com:443", h3="alt.example.com:8443"; ma=3600`,
+			host:            "example.com",
+			expectedAddress: "alt.example.com:8443",
+			expectedFound:   true,
+		},
+		{
+			name:          "no h3 alternative",
+			value:         `h2=":443"`,
+			host:          "example.com",
+			expectedFound: false,
+		},
+		{
+			name:          "clear",
+			value:         `clear`,
+			host:          "example.com",
+			expectedFound: false,
+		},
+		{
+			name:          "invalid authority",
+			value:         `h3="invalid"`,
+			host:          "example.com",
+			expectedFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, found := ParseAltSvcHttp3(tt.value, tt.host)
+			assert.Equal(t, tt.expectedFound, found)
+			assert.Equal(t, tt.expectedAddress, address)
+		})
+	}
+}
+
+// startHttp3TestServers starts a TLS server over TCP advertising HTTP/3 in Alt-Svc and an HTTP/3 server.
+func startHttp3TestServers(t *testing.T) (*httptest.Server, int) {
+	udpConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	udpPort := udpConn.LocalAddr().(*net.UDPAddr).Port
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Alt-Svc", `h3=":`+strconv.Itoa(udpPort)+`"; ma=60`)
+		_, _ = w.Write([]byte(r.Proto))
+	})
+	tcpServer := httptest.NewTLSServer(handler)
+
+	h3Server := &http3.Server{
+		Handler: handler,
+		TLSConfig: http3.ConfigureTLSConfig(&tls.Config{
+			Certificates: tcpServer.TLS.Certificates,
+		}),
+	}
+	go func() {
+		_ = h3Server.Serve(udpConn)
+	}()
+	t.Cleanup(func() {
+		_ = h3Server.Close()
+		_ = udpConn.Close()
+		tcpServer.Close()
+	})
+
+	return tcpServer, udpPort
+}
+
+func TestAction_Execute_Http3(t *testing.T) {
+	tcpServer, udpPort := startHttp3TestServers(t)
+	tcpUrl := tcpServer.URL
+	h3Url := "https://127.0.0.1:" + strconv.Itoa(udpPort)
+
+	tests := []struct {
+		name           string
+		protocols      []Protocol
+		url            string
+		requests       int
+		expectedProtos []string
+	}{
+		{
+			name:           "direct HTTP/3",
+			protocols:      []Protocol{ProtocolHTTP3},
+			url:            h3Url,
+			requests:       1,
+			expectedProtos: []string{"HTTP/3.0"},
+		},
+		{
+			name:           "Alt-Svc upgrade to HTTP/3",
+			protocols:      []Protocol{ProtocolHTTP11, ProtocolHTTP3},
+			url:            tcpUrl,
+			requests:       2,
+			expectedProtos: []string{"HTTP/1.1", "HTTP/3.0"},
+		},
+		{
+			name:           "no upgrade without HTTP/3",
+			protocols:      []Protocol{ProtocolHTTP11},
+			url:            tcpUrl,
+			requests:       2,
+			expectedProtos: []string{"HTTP/1.1", "HTTP/1.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			svcMock := servicesMocks.NewMockService(t)
+			fndMock.On("Logger").Return(external.NewMockLogger().SugaredLogger)
+			fndMock.On("HttpClient", mock.Anything).Return(func(tr *http.Transport) app.HttpClient {
+				return app.NewRealHttpClient(tr)
+			}).Maybe()
+			fndMock.On("CustomHttpClient", mock.Anything).Return(func(c *http.Client) app.HttpClient {
+				return app.NewCustomHttpClient(c)
+			}).Maybe()
+			svcMock.On("PublicUrl", "https", "/").Return(tt.url, nil)
+			runData := runtime.CreateMaker(fndMock).MakeData()
+
+			a := &Action{
+				fnd:        fndMock,
+				service:    svcMock,
+				timeout:    5 * time.Second,
+				id:         "last",
+				scheme:     "https",
+				path:       "/",
+				encodePath: true,
+				method:     "GET",
+				body:       &types.RequestBody{},
+				tls:        &types.TLSClientConfig{SkipVerify: true},
+				protocols:  tt.protocols,
+			}
+
+			for i := 0; i < tt.requests; i++ {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				got, err := a.Execute(ctx, runData)
+				cancel()
+				require.NoError(t, err)
+				assert.True(t, got)
+				data, ok := runData.Load("response/last")
+				require.True(t, ok)
+				responseData := data.(ResponseData)
+				assert.Equal(t, tt.expectedProtos[i], responseData.Proto)
+				assert.Equal(t, tt.expectedProtos[i], responseData.Body)
+			}
+		})
+	}
+}
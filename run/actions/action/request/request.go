@@ -15,20 +15,22 @@
 package request
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 	"github.com/wstool/wst/app"
 	"github.com/wstool/wst/conf/types"
 	"github.com/wstool/wst/run/actions/action"
 	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/parameters"
 	"github.com/wstool/wst/run/services"
 )
 
@@ -37,6 +39,7 @@ type Protocol string
 const (
 	ProtocolHTTP11 Protocol = "http1.1"
 	ProtocolHTTP2  Protocol = "http2"
+	ProtocolHTTP3  Protocol = "http3"
 )
 
 type Maker interface {
@@ -71,19 +74,26 @@ func (m *ActionMaker) Make(
 		config.Timeout = defaultTimeout
 	}
 
-	if config.Scheme != "https" && (config.TLS.SkipVerify || config.TLS.CACert != "") {
+	if config.Scheme != "https" && IsTLSConfigured(&config.TLS) {
 		return nil, errors.New("TLS configuration is only valid for HTTPS requests")
 	}
+	if err = ValidateTLSConfig(&config.TLS); err != nil {
+		return nil, err
+	}
+
+	if err = ValidateBody(&config.Body); err != nil {
+		return nil, err
+	}
+
+	if config.Redirects.Max < 0 {
+		return nil, errors.Errorf("invalid maximal number of redirects %d", config.Redirects.Max)
+	}
 
 	// Set default protocols if not specified
 	protocols := config.Protocols
 	if len(protocols) == 0 {
-		if config.Scheme == "https" {
-			// Default for HTTPS: allow both HTTP/1.1 and HTTP/2
-			protocols = []string{string(ProtocolHTTP11), string(ProtocolHTTP2)}
-		} else {
-			// Default for HTTP: only HTTP/1.1 (h2c is not commonly supported)
-			protocols = []string{string(ProtocolHTTP11)}
+		for _, proto := range DefaultProtocols(config.Scheme) {
+			protocols = append(protocols, string(proto))
 		}
 	}
 
@@ -94,6 +104,9 @@ func (m *ActionMaker) Make(
 		if proto == ProtocolHTTP2 && config.Scheme != "https" {
 			m.fnd.Logger().Infof("Using unencrypted HTTP/2 (h2c) over plain HTTP")
 		}
+		if proto == ProtocolHTTP3 && config.Scheme != "https" {
+			return nil, errors.New("HTTP/3 protocol is only valid for HTTPS requests")
+		}
 		validatedProtocols = append(validatedProtocols, proto)
 	}
 
@@ -112,6 +125,9 @@ func (m *ActionMaker) Make(
 		body:       &config.Body,
 		tls:        &config.TLS,
 		protocols:  validatedProtocols,
+		session:    config.Session,
+		redirects:  config.Redirects,
+		decoding:   Decoding(config.Decoding),
 	}, nil
 }
 
@@ -122,6 +138,27 @@ type ResponseData struct {
 	Proto      string
 	Body       string
 	Headers    http.Header
+	// ConnectionReused is set if the request was sent over a previously used connection.
+	ConnectionReused bool
+	// TLS holds the negotiated TLS connection details for HTTPS requests.
+	TLS *TLSInfo
+	// BodySize is the size of the response body in bytes as it was received before any decoding.
+	BodySize int
+	// Timing holds the durations of the request phases.
+	Timing ResponseTiming
+	// ContentEncoding is the content encoding of the received body. It is set also if the body was decoded by the auto
+	// decoding.
+	ContentEncoding string
+	// RawBody is the received body before decoding. It is set only if the body was explicitly decoded.
+	RawBody string
+}
+
+// Raw returns the body as it was received if available or the body otherwise.
+func (r ResponseData) Raw() string {
+	if r.RawBody != "" {
+		return r.RawBody
+	}
+	return r.Body
 }
 
 func (r ResponseData) String() string {
@@ -133,7 +170,9 @@ func (r ResponseData) String() string {
 	}
 
 	body := ""
-	if r.Body != "" {
+	if r.Body != "" && !utf8.ValidString(r.Body) {
+		body = fmt.Sprintf("\n\n<binary body of %d bytes>", len(r.Body))
+	} else if r.Body != "" {
 		body = "\n\n" + r.Body
 	}
 
@@ -155,6 +194,9 @@ type Action struct {
 	body       *types.RequestBody
 	tls        *types.TLSClientConfig
 	protocols  []Protocol
+	session    string
+	redirects  types.RedirectConfig
+	decoding   Decoding
 }
 
 func (a *Action) When() action.When {
@@ -173,32 +215,46 @@ func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error
 	a.fnd.Logger().Infof("Executing request action with HTTP protocols: %v", a.protocols)
 
 	// Create transport
-	tr := &http.Transport{}
-
-	// Configure protocols using the Protocols API
-	protocolConfig := a.buildProtocolConfig()
-	tr.Protocols = protocolConfig
-
+	var tlsConfig *tls.Config
 	if a.scheme == "https" {
-		tlsConfig, err := a.buildTLSConfig()
+		var err error
+		tlsConfig, err = a.buildTLSConfig()
 		if err != nil {
 			return false, err
 		}
-		tr.TLSClientConfig = tlsConfig
 	}
+	tr := BuildTransport(a.protocols, a.scheme, tlsConfig)
 
 	a.fnd.Logger().Debugf("Protocol configuration: HTTP/1=%t, HTTP/2=%t, UnencryptedHTTP/2=%t",
-		protocolConfig.HTTP1(), protocolConfig.HTTP2(), protocolConfig.UnencryptedHTTP2())
+		tr.Protocols.HTTP1(), tr.Protocols.HTTP2(), tr.Protocols.UnencryptedHTTP2())
 
-	publicUrl, err := a.service.PublicUrl(a.scheme, a.path)
+	id, err := a.renderRuntimeTemplate(a.id, runData)
+	if err != nil {
+		return false, err
+	}
+	path, err := a.renderRuntimeTemplate(a.path, runData)
+	if err != nil {
+		return false, err
+	}
+
+	publicUrl, err := a.service.PublicUrl(a.scheme, path)
 	if err != nil {
 		return false, err
 	}
 
 	// Create a request body reader
+	var reqBody *requestBody
 	var bodyReader io.Reader
-	if a.body != nil && a.body.Content != "" {
-		bodyReader = a.createBodyReader(ctx)
+	if a.hasBody() {
+		reqBody, err = a.prepareBody(runData)
+		if err != nil {
+			return false, err
+		}
+		if reqBody.file != nil {
+			// The client closes the body when sending but the request might fail before it is sent.
+			defer reqBody.file.Close()
+		}
+		bodyReader = a.createBodyReader(ctx, reqBody)
 	}
 
 	// Create the HTTP request
@@ -212,7 +268,7 @@ func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error
 		req.URL = &url.URL{
 			Scheme: parsedUrl.Scheme,
 			Host:   parsedUrl.Host,
-			Opaque: fmt.Sprintf("//%s%s", parsedUrl.Host, a.path),
+			Opaque: fmt.Sprintf("//%s%s", parsedUrl.Host, path),
 		}
 	}
 
@@ -221,16 +277,38 @@ func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error
 		req.Header.Add(key, value)
 	}
 
+	// Request the encodings unless set explicitly. The gzip encoding of the auto decoding is requested here instead of
+	// the transport so the body is decoded after its received size is measured.
+	gzipDecoding := false
+	if req.Header.Get("Accept-Encoding") == "" {
+		if accept := a.acceptEncoding(); accept != "" {
+			req.Header.Set("Accept-Encoding", accept)
+		} else if a.method != http.MethodHead && req.Header.Get("Range") == "" {
+			req.Header.Set("Accept-Encoding", rawAcceptEncoding)
+			gzipDecoding = true
+		}
+	}
+
 	// Handle transfer configuration
-	if a.body != nil && a.body.Content != "" {
-		a.applyTransferConfig(req)
+	if reqBody != nil {
+		if reqBody.contentType != "" {
+			req.Header.Set("Content-Type", reqBody.contentType)
+		}
+		a.applyTransferConfig(req, reqBody.size)
 	}
 
 	a.fnd.Logger().Debugf("Sending request: %s", requestToString(req))
 
 	// Send the request
-	client := a.fnd.HttpClient(tr)
-	resp, err := client.Do(req)
+	prepared, err := a.prepareClient(tr, req, runData)
+	if err != nil {
+		return false, err
+	}
+	if prepared.closer != nil {
+		defer prepared.closer.Close()
+	}
+	trace := newRequestTrace(time.Now())
+	resp, err := prepared.client.Do(prepared.request.WithContext(trace.withContext(prepared.request.Context())))
 	if err != nil {
 		return false, err
 	}
@@ -241,18 +319,50 @@ func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error
 	if err != nil {
 		return false, err
 	}
+	timing := trace.finish()
+	bodySize := len(body)
+
+	// Decode the body if requested
+	contentEncoding := resp.Header.Get("Content-Encoding")
+	rawBody := ""
+	if gzipDecoding && strings.EqualFold(contentEncoding, "gzip") {
+		// The body is decoded and the encoding headers are removed in the same way as the transparent decoding does.
+		if body, err = decodeBody(body, contentEncoding); err != nil {
+			return false, err
+		}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+	} else if a.decoding == DecodingDecode {
+		rawBody = body
+		if body, err = decodeBody(body, contentEncoding); err != nil {
+			return false, err
+		}
+	}
 
 	// Create a ResponseData instance to hold both body and headers
 	responseData := ResponseData{
-		Status:     resp.Status,
-		StatusCode: resp.StatusCode,
-		Proto:      resp.Proto,
-		Body:       body,
-		Headers:    resp.Header,
+		Status:          resp.Status,
+		StatusCode:      resp.StatusCode,
+		Proto:           resp.Proto,
+		Body:            body,
+		Headers:         resp.Header,
+		BodySize:        bodySize,
+		Timing:          timing,
+		ContentEncoding: contentEncoding,
+		RawBody:         rawBody,
+	}
+	responseData.ConnectionReused = trace.reused()
+	responseData.TLS = NewTLSInfo(resp.TLS)
+
+	// Record the HTTP/3 alternative service for upgrading following requests
+	if a.hasProtocol(ProtocolHTTP3) && resp.ProtoMajor < 3 {
+		if err = a.storeAltSvc(req.URL, resp.Header.Get("Alt-Svc"), runData); err != nil {
+			return false, err
+		}
 	}
 
 	// Store the ResponseData in runData
-	key := fmt.Sprintf("response/%s", a.id)
+	key := fmt.Sprintf("response/%s", id)
 	a.fnd.Logger().Debugf("Storing response %s: %s (protocol: %s)", key, responseData, resp.Proto)
 	if err := runData.Store(key, responseData); err != nil {
 		return false, err
@@ -261,28 +371,106 @@ func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error
 	return true, nil
 }
 
-// createBodyReader creates an io.Reader for the request body based on transfer configuration
-func (a *Action) createBodyReader(ctx context.Context) io.Reader {
-	content := []byte(a.body.Content)
-
-	// If chunked encoding with chunk size or delay specified, use a custom reader
-	if a.body.Transfer.Encoding == "chunked" && (a.body.Transfer.ChunkSize > 0 || a.body.Transfer.ChunkDelay > 0) {
-		return &chunkControlledReader{
-			ctx:        ctx,
-			fnd:        a.fnd,
-			data:       content,
-			chunkSize:  a.body.Transfer.ChunkSize,
-			chunkDelay: time.Duration(a.body.Transfer.ChunkDelay) * time.Millisecond,
-			offset:     0,
+// acceptEncoding returns the default Accept-Encoding header value for the decoding or empty string if the header
+// is left to the transport.
+func (a *Action) acceptEncoding() string {
+	switch a.decoding {
+	case DecodingRaw:
+		return rawAcceptEncoding
+	case DecodingDecode:
+		return decodeAcceptEncoding
+	default:
+		return ""
+	}
+}
+
+// preparedClient holds the client selected for sending the request.
+type preparedClient struct {
+	client  app.HttpClient
+	request *http.Request
+	// closer is set if the client transport needs closing after the request.
+	closer io.Closer
+}
+
+// prepareClient returns the client for sending the request. The default client is used unless the action
+// is part of a session, changes the redirect policy or uses HTTP/3. In such case the returned request also
+// carries the redirect policy.
+func (a *Action) prepareClient(
+	tr *http.Transport,
+	req *http.Request,
+	runData runtime.Data,
+) (*preparedClient, error) {
+	useHttp3, altAddress := a.selectHttp3(req.URL, runData)
+	if !useHttp3 && a.session == "" && a.redirects == (types.RedirectConfig{}) {
+		return &preparedClient{client: a.fnd.HttpClient(tr), request: req}, nil
+	}
+
+	prepared := &preparedClient{}
+	if a.session == "" {
+		var transport http.RoundTripper = tr
+		if useHttp3 {
+			h3tr := NewHttp3Transport(tr.TLSClientConfig, altAddress)
+			transport = h3tr
+			prepared.closer = h3tr
+		}
+		prepared.client = a.fnd.CustomHttpClient(&http.Client{
+			Transport:     transport,
+			CheckRedirect: checkRedirect,
+		})
+	} else {
+		key := sessionKey(a.session)
+		var session *Session
+		if data, ok := runData.Load(key); ok {
+			session, ok = data.(*Session)
+			if !ok {
+				return nil, errors.Errorf("invalid session data type for session %s", a.session)
+			}
+			a.fnd.Logger().Debugf("Reusing session %s", a.session)
+		} else {
+			a.fnd.Logger().Debugf("Creating session %s", a.session)
+			session = NewSession(a.fnd)
+			if err := runData.Store(key, session); err != nil {
+				return nil, err
+			}
+		}
+		if useHttp3 {
+			prepared.client = session.Http3Client(a.transportKey(), tr.TLSClientConfig, altAddress)
+		} else {
+			prepared.client = session.Client(a.transportKey(), tr)
 		}
 	}
 
-	// For normal transfers or chunked without size/delay control, use bytes.Reader
-	return bytes.NewReader(content)
+	prepared.request = req.WithContext(withRedirectPolicy(req.Context(), a.redirects))
+
+	return prepared, nil
+}
+
+// transportKey identifies the transport configuration of the action so the session requests share a client only if
+// they use the same transport settings.
+func (a *Action) transportKey() string {
+	key := fmt.Sprintf("%s %v", a.scheme, a.protocols)
+	if a.scheme == "https" && a.tls != nil {
+		key += fmt.Sprintf(" %+v", *a.tls)
+	}
+	return key
+}
+
+// renderRuntimeTemplate renders text that contains template markup using runtime and server parameters.
+func (a *Action) renderRuntimeTemplate(text string, runData runtime.Data) (string, error) {
+	return RenderRuntimeTemplate(a.service, text, runData)
+}
+
+// RenderRuntimeTemplate renders text that contains template markup using runtime and service server parameters.
+func RenderRuntimeTemplate(svc services.Service, text string, runData runtime.Data) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	params := make(parameters.Parameters).Inherit(runData.Parameters()).Inherit(svc.ServerParameters())
+	return svc.RenderTemplate(text, params)
 }
 
 // applyTransferConfig applies transfer configuration to the request
-func (a *Action) applyTransferConfig(req *http.Request) {
+func (a *Action) applyTransferConfig(req *http.Request, size int64) {
 	if a.body.Transfer.Encoding == "chunked" {
 		req.TransferEncoding = []string{"chunked"}
 		if a.body.Transfer.ChunkSize > 0 {
@@ -294,22 +482,40 @@ func (a *Action) applyTransferConfig(req *http.Request) {
 	if a.body.Transfer.ContentLength > 0 {
 		req.ContentLength = int64(a.body.Transfer.ContentLength)
 		a.fnd.Logger().Debugf("Setting Content-Length to: %d (actual body length: %d)",
-			a.body.Transfer.ContentLength, len(a.body.Content))
+			a.body.Transfer.ContentLength, size)
 	} else if a.body.Transfer.Encoding != "chunked" {
 		// Set actual content length if not chunked and not overridden
-		req.ContentLength = int64(len(a.body.Content))
+		req.ContentLength = size
 	}
 }
 
-func (a *Action) buildProtocolConfig() *http.Protocols {
+// DefaultProtocols returns protocols used if no protocols are specified.
+func DefaultProtocols(scheme string) []Protocol {
+	if scheme == "https" {
+		// Default for HTTPS: allow both HTTP/1.1 and HTTP/2
+		return []Protocol{ProtocolHTTP11, ProtocolHTTP2}
+	}
+	// Default for HTTP: only HTTP/1.1 (h2c is not commonly supported)
+	return []Protocol{ProtocolHTTP11}
+}
+
+// BuildTransport creates the transport for HTTP/1.1 and HTTP/2 protocols. HTTP/3 uses a separate transport.
+func BuildTransport(protocols []Protocol, scheme string, tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		Protocols:       buildProtocolConfig(protocols, scheme),
+		TLSClientConfig: tlsConfig,
+	}
+}
+
+func buildProtocolConfig(protocols []Protocol, scheme string) *http.Protocols {
 	config := new(http.Protocols)
 
-	for _, proto := range a.protocols {
+	for _, proto := range protocols {
 		switch proto {
 		case ProtocolHTTP11:
 			config.SetHTTP1(true)
 		case ProtocolHTTP2:
-			if a.scheme == "https" {
+			if scheme == "https" {
 				// HTTP/2 over TLS
 				config.SetHTTP2(true)
 			} else {
@@ -323,23 +529,7 @@ func (a *Action) buildProtocolConfig() *http.Protocols {
 }
 
 func (a *Action) buildTLSConfig() (*tls.Config, error) {
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: a.tls.SkipVerify,
-	}
-
-	if a.tls.CACert != "" {
-		caCert, err := a.service.FindCertificate(a.tls.CACert)
-		if err != nil {
-			return nil, errors.Errorf("CA certificate %s not found", a.tls.CACert)
-		}
-		caCertPool := a.fnd.X509CertPool()
-		if !caCertPool.AppendCertFromPEM(caCert.Certificate.CertificateData()) {
-			return nil, errors.New("failed to parse CA certificate")
-		}
-		tlsConfig.RootCAs = caCertPool.CertPool()
-	}
-
-	return tlsConfig, nil
+	return BuildTLSConfig(a.fnd, a.service, a.tls)
 }
 
 func requestToString(req *http.Request) string {
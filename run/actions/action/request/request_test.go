@@ -7,10 +7,13 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/wstool/wst/app"
@@ -18,9 +21,11 @@ import (
 	"github.com/wstool/wst/mocks/authored/external"
 	appMocks "github.com/wstool/wst/mocks/generated/app"
 	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
+	parameterMocks "github.com/wstool/wst/mocks/generated/run/parameters/parameter"
 	certificatesMocks "github.com/wstool/wst/mocks/generated/run/resources/certificates"
 	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
 	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/parameters"
 	"github.com/wstool/wst/run/resources/certificates"
 	"github.com/wstool/wst/run/services"
 )
@@ -371,6 +376,149 @@ func TestActionMaker_Make(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "successful request with session and redirects config",
+			config: &types.RequestAction{
+				Service:   "validService",
+				Timeout:   3000,
+				When:      "on_success",
+				OnFailure: "fail",
+				Id:        "login",
+				Scheme:    "http",
+				Path:      "/login",
+				Method:    "POST",
+				Session:   "user",
+				Redirects: types.RedirectConfig{
+					Disabled: true,
+				},
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator, fnd *appMocks.MockFoundation) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "validService").Return(svc, nil)
+				return svc
+			},
+			getExpectedAction: func(fndMock *appMocks.MockFoundation, svc services.Service) *Action {
+				return &Action{
+					fnd:       fndMock,
+					service:   svc,
+					timeout:   3000 * time.Millisecond,
+					when:      action.OnSuccess,
+					onFailure: action.Fail,
+					id:        "login",
+					scheme:    "http",
+					path:      "/login",
+					method:    "POST",
+					body:      &types.RequestBody{},
+					tls:       &types.TLSClientConfig{},
+					protocols: []Protocol{ProtocolHTTP11},
+					session:   "user",
+					redirects: types.RedirectConfig{
+						Disabled: true,
+					},
+				}
+			},
+		},
+		{
+			name: "failure invalid body",
+			config: &types.RequestAction{
+				Service: "validService",
+				Id:      "last",
+				Scheme:  "http",
+				Path:    "/",
+				Method:  "POST",
+				Body: types.RequestBody{
+					Content: "data",
+					File:    "/tmp/data.bin",
+				},
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator, fnd *appMocks.MockFoundation) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "validService").Return(svc, nil)
+				return svc
+			},
+			expectError:      true,
+			expectedErrorMsg: "request body can set only one of content, multipart and file",
+		},
+		{
+			name: "failure HTTP/3 protocol with HTTP scheme",
+			config: &types.RequestAction{
+				Service:   "validService",
+				Id:        "last",
+				Scheme:    "http",
+				Path:      "/",
+				Method:    "GET",
+				Protocols: []string{"http3"},
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator, fnd *appMocks.MockFoundation) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "validService").Return(svc, nil)
+				return svc
+			},
+			expectError:      true,
+			expectedErrorMsg: "HTTP/3 protocol is only valid for HTTPS requests",
+		},
+		{
+			name: "failure TLS config with HTTP scheme - client certificate",
+			config: &types.RequestAction{
+				Service: "validService",
+				Id:      "last",
+				Scheme:  "http",
+				Path:    "/",
+				Method:  "GET",
+				TLS:     types.TLSClientConfig{ClientCert: "client"},
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator, fnd *appMocks.MockFoundation) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "validService").Return(svc, nil)
+				return svc
+			},
+			expectError:      true,
+			expectedErrorMsg: "TLS configuration is only valid for HTTPS requests",
+		},
+		{
+			name: "failure invalid TLS cipher suite",
+			config: &types.RequestAction{
+				Service: "validService",
+				Id:      "last",
+				Scheme:  "https",
+				Path:    "/",
+				Method:  "GET",
+				TLS:     types.TLSClientConfig{CipherSuites: []string{"TLS_UNKNOWN"}},
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator, fnd *appMocks.MockFoundation) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "validService").Return(svc, nil)
+				return svc
+			},
+			expectError:      true,
+			expectedErrorMsg: "invalid TLS cipher suite TLS_UNKNOWN",
+		},
+		{
+			name: "failure negative maximal number of redirects",
+			config: &types.RequestAction{
+				Service: "validService",
+				Id:      "last",
+				Scheme:  "http",
+				Path:    "/",
+				Method:  "GET",
+				Redirects: types.RedirectConfig{
+					Max: -1,
+				},
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator, fnd *appMocks.MockFoundation) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "validService").Return(svc, nil)
+				return svc
+			},
+			expectError:      true,
+			expectedErrorMsg: "invalid maximal number of redirects -1",
+		},
 		{
 			name: "failure TLS config with HTTP scheme - skip verify",
 			config: &types.RequestAction{
@@ -508,6 +656,21 @@ func (b *bodyReader) Close() error {
 	return nil
 }
 
+// requestMatcher matches the request ignoring its context which is extended by the request trace.
+func requestMatcher(expected *http.Request) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return assert.ObjectsAreEqual(expected.WithContext(req.Context()), req)
+	})
+}
+
+// responseDataMatcher matches the response data ignoring the measured timing.
+func responseDataMatcher(expected ResponseData) interface{} {
+	return mock.MatchedBy(func(data ResponseData) bool {
+		data.Timing = ResponseTiming{}
+		return assert.ObjectsAreEqual(expected, data)
+	})
+}
+
 func TestAction_Execute(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -520,6 +683,9 @@ func TestAction_Execute(t *testing.T) {
 		body       *types.RequestBody
 		tls        *types.TLSClientConfig
 		protocols  []Protocol
+		session    string
+		redirects  types.RedirectConfig
+		decoding   Decoding
 		setupMocks func(
 			t *testing.T,
 			ctx context.Context,
@@ -560,6 +726,7 @@ func TestAction_Execute(t *testing.T) {
 				svc.On("PublicUrl", "http", "/test").Return(reqUrl, nil)
 				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
 				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
 				expectedRequest.Header.Add("content-type", "application/json")
 				expectedRequest.Header.Add("user-agent", "wst")
 				body := &bodyReader{msg: "test"}
@@ -580,14 +747,333 @@ func TestAction_Execute(t *testing.T) {
 				}
 				client := appMocks.NewMockHttpClient(t)
 				fnd.On("HttpClient", expectedTransport).Return(client)
-				client.On("Do", expectedRequest).Return(resp, nil)
-				rd.On("Store", "response/r1", ResponseData{
-					Body:    "test",
-					Headers: header,
-				}).Return(nil)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "test",
+					BodySize: 4,
+					Headers:  header,
+				})).Return(nil)
 			},
 			want: true,
 		},
+		{
+			name:       "successful execution with explicit body decoding",
+			id:         "r1",
+			scheme:     "http",
+			path:       "/test",
+			encodePath: true,
+			method:     "GET",
+			body:       &types.RequestBody{},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP11},
+			decoding:   DecodingDecode,
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				reqUrl := "http://example.com/test"
+				svc.On("PublicUrl", "http", "/test").Return(reqUrl, nil)
+				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
+				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip, deflate, br, zstd")
+				encodedBody := encodeContent(t, "br", "test")
+				header := http.Header{"Content-Encoding": []string{"br"}}
+				resp := &http.Response{
+					Body:   &bodyReader{msg: encodedBody},
+					Header: header,
+				}
+				expectedTransport := &http.Transport{
+					Protocols: func() *http.Protocols {
+						p := new(http.Protocols)
+						p.SetHTTP1(true)
+						return p
+					}(),
+				}
+				client := appMocks.NewMockHttpClient(t)
+				fnd.On("HttpClient", expectedTransport).Return(client)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:            "test",
+					BodySize:        len(encodedBody),
+					Headers:         header,
+					ContentEncoding: "br",
+					RawBody:         encodedBody,
+				})).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name:       "successful execution with raw body",
+			id:         "r1",
+			scheme:     "http",
+			path:       "/test",
+			encodePath: true,
+			method:     "GET",
+			body:       &types.RequestBody{},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP11},
+			decoding:   DecodingRaw,
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				reqUrl := "http://example.com/test"
+				svc.On("PublicUrl", "http", "/test").Return(reqUrl, nil)
+				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
+				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
+				encodedBody := encodeContent(t, "gzip", "test")
+				header := http.Header{"Content-Encoding": []string{"gzip"}}
+				resp := &http.Response{
+					Body:   &bodyReader{msg: encodedBody},
+					Header: header,
+				}
+				expectedTransport := &http.Transport{
+					Protocols: func() *http.Protocols {
+						p := new(http.Protocols)
+						p.SetHTTP1(true)
+						return p
+					}(),
+				}
+				client := appMocks.NewMockHttpClient(t)
+				fnd.On("HttpClient", expectedTransport).Return(client)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:            encodedBody,
+					BodySize:        len(encodedBody),
+					Headers:         header,
+					ContentEncoding: "gzip",
+				})).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name:       "successful execution with auto decoded gzip body",
+			id:         "r1",
+			scheme:     "http",
+			path:       "/test",
+			encodePath: true,
+			method:     "GET",
+			body:       &types.RequestBody{},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP11},
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				reqUrl := "http://example.com/test"
+				svc.On("PublicUrl", "http", "/test").Return(reqUrl, nil)
+				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
+				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
+				encodedBody := encodeContent(t, "gzip", "test")
+				header := http.Header{
+					"Content-Encoding": []string{"gzip"},
+					"Content-Length":   []string{strconv.Itoa(len(encodedBody))},
+					"Content-Type":     []string{"text/plain"},
+				}
+				resp := &http.Response{
+					Body:   &bodyReader{msg: encodedBody},
+					Header: header,
+				}
+				expectedTransport := &http.Transport{
+					Protocols: func() *http.Protocols {
+						p := new(http.Protocols)
+						p.SetHTTP1(true)
+						return p
+					}(),
+				}
+				client := appMocks.NewMockHttpClient(t)
+				fnd.On("HttpClient", expectedTransport).Return(client)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				// The size is of the received gzip body and the encoding headers are removed after decoding.
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:            "test",
+					BodySize:        len(encodedBody),
+					Headers:         http.Header{"Content-Type": []string{"text/plain"}},
+					ContentEncoding: "gzip",
+				})).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name:       "successful execution with auto decoding and explicit accept encoding",
+			id:         "r1",
+			scheme:     "http",
+			path:       "/test",
+			encodePath: true,
+			method:     "GET",
+			headers:    types.Headers{"Accept-Encoding": "gzip"},
+			body:       &types.RequestBody{},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP11},
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				reqUrl := "http://example.com/test"
+				svc.On("PublicUrl", "http", "/test").Return(reqUrl, nil)
+				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
+				assert.Nil(t, err)
+				expectedRequest.Header.Add("Accept-Encoding", "gzip")
+				encodedBody := encodeContent(t, "gzip", "test")
+				header := http.Header{"Content-Encoding": []string{"gzip"}}
+				resp := &http.Response{
+					Body:   &bodyReader{msg: encodedBody},
+					Header: header,
+				}
+				expectedTransport := &http.Transport{
+					Protocols: func() *http.Protocols {
+						p := new(http.Protocols)
+						p.SetHTTP1(true)
+						return p
+					}(),
+				}
+				client := appMocks.NewMockHttpClient(t)
+				fnd.On("HttpClient", expectedTransport).Return(client)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				// The explicitly requested encoding is not decoded as it is with the transparent decoding.
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:            encodedBody,
+					BodySize:        len(encodedBody),
+					Headers:         header,
+					ContentEncoding: "gzip",
+				})).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name:       "failed execution due to body decoding error",
+			id:         "r1",
+			scheme:     "http",
+			path:       "/test",
+			encodePath: true,
+			method:     "GET",
+			body:       &types.RequestBody{},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP11},
+			decoding:   DecodingDecode,
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				reqUrl := "http://example.com/test"
+				svc.On("PublicUrl", "http", "/test").Return(reqUrl, nil)
+				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
+				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip, deflate, br, zstd")
+				encodedBody := "test"
+				header := http.Header{"Content-Encoding": []string{"zstd"}}
+				resp := &http.Response{
+					Body:   &bodyReader{msg: encodedBody},
+					Header: header,
+				}
+				expectedTransport := &http.Transport{
+					Protocols: func() *http.Protocols {
+						p := new(http.Protocols)
+						p.SetHTTP1(true)
+						return p
+					}(),
+				}
+				client := appMocks.NewMockHttpClient(t)
+				fnd.On("HttpClient", expectedTransport).Return(client)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+			},
+			want:             false,
+			expectError:      true,
+			expectedErrorMsg: "failed to decode zstd response body",
+		},
+		{
+			name:       "successful execution with runtime parameters in id and path",
+			id:         "r{{ .Parameters.GetString \"iteration\" }}",
+			scheme:     "http",
+			path:       "/test/{{ .Parameters.GetString \"iteration\" }}",
+			encodePath: true,
+			method:     "GET",
+			body:       &types.RequestBody{},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP11},
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				params := parameters.Parameters{"iteration": parameterMocks.NewMockParameter(t)}
+				rd.On("Parameters").Return(params)
+				svc.On("ServerParameters").Return(parameters.Parameters{})
+				svc.On("RenderTemplate", "r{{ .Parameters.GetString \"iteration\" }}", params).Return("r2", nil)
+				svc.On("RenderTemplate", "/test/{{ .Parameters.GetString \"iteration\" }}", params).Return("/test/2", nil)
+				reqUrl := "http://example.com/test/2"
+				svc.On("PublicUrl", "http", "/test/2").Return(reqUrl, nil)
+				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
+				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
+				body := &bodyReader{msg: "test"}
+				resp := &http.Response{
+					Body:   body,
+					Header: http.Header{},
+				}
+				expectedTransport := &http.Transport{
+					Protocols: func() *http.Protocols {
+						p := new(http.Protocols)
+						p.SetHTTP1(true)
+						return p
+					}(),
+				}
+				client := appMocks.NewMockHttpClient(t)
+				fnd.On("HttpClient", expectedTransport).Return(client)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				rd.On("Store", "response/r2", responseDataMatcher(ResponseData{
+					Body:     "test",
+					BodySize: 4,
+					Headers:  http.Header{},
+				})).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name:       "failed execution due to id rendering error",
+			id:         "r{{ .Parameters.GetString \"iteration\" }}",
+			scheme:     "http",
+			path:       "/test",
+			encodePath: true,
+			method:     "GET",
+			body:       &types.RequestBody{},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP11},
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				params := parameters.Parameters{}
+				rd.On("Parameters").Return(params)
+				svc.On("ServerParameters").Return(params)
+				svc.On("RenderTemplate", "r{{ .Parameters.GetString \"iteration\" }}", params).Return("", errors.New("render fail"))
+			},
+			want:             false,
+			expectError:      true,
+			expectedErrorMsg: "render fail",
+		},
 		{
 			name:       "successful execution with body content",
 			id:         "r1",
@@ -642,10 +1128,11 @@ func TestAction_Execute(t *testing.T) {
 						req.ContentLength == 17 &&
 						req.Body != nil
 				})).Return(resp, nil)
-				rd.On("Store", "response/r1", ResponseData{
-					Body:    "ok",
-					Headers: header,
-				}).Return(nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "ok",
+					BodySize: 2,
+					Headers:  header,
+				})).Return(nil)
 			},
 			want: true,
 		},
@@ -709,10 +1196,11 @@ func TestAction_Execute(t *testing.T) {
 						req.TransferEncoding[0] == "chunked" &&
 						req.Body != nil
 				})).Return(resp, nil)
-				rd.On("Store", "response/r1", ResponseData{
-					Body:    "ok",
-					Headers: header,
-				}).Return(nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "ok",
+					BodySize: 2,
+					Headers:  header,
+				})).Return(nil)
 			},
 			want: true,
 		},
@@ -770,10 +1258,11 @@ func TestAction_Execute(t *testing.T) {
 						req.ContentLength == 100 &&
 						req.Body != nil
 				})).Return(resp, nil)
-				rd.On("Store", "response/r1", ResponseData{
-					Body:    "ok",
-					Headers: header,
-				}).Return(nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "ok",
+					BodySize: 2,
+					Headers:  header,
+				})).Return(nil)
 			},
 			want: true,
 		},
@@ -832,10 +1321,11 @@ func TestAction_Execute(t *testing.T) {
 						req.TransferEncoding[0] == "chunked" &&
 						req.Body != nil
 				})).Return(resp, nil)
-				rd.On("Store", "response/r1", ResponseData{
-					Body:    "ok",
-					Headers: header,
-				}).Return(nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "ok",
+					BodySize: 2,
+					Headers:  header,
+				})).Return(nil)
 			},
 			want: true,
 		},
@@ -867,6 +1357,7 @@ func TestAction_Execute(t *testing.T) {
 				svc.On("PublicUrl", "https", "/test").Return(reqUrl, nil)
 				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
 				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
 				expectedRequest.Header.Add("content-type", "application/json")
 				expectedRequest.Header.Add("user-agent", "wst")
 				body := &bodyReader{msg: "test"}
@@ -891,11 +1382,12 @@ func TestAction_Execute(t *testing.T) {
 				}
 				client := appMocks.NewMockHttpClient(t)
 				fnd.On("HttpClient", expectedTransport).Return(client)
-				client.On("Do", expectedRequest).Return(resp, nil)
-				rd.On("Store", "response/r1", ResponseData{
-					Body:    "test",
-					Headers: header,
-				}).Return(nil)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "test",
+					BodySize: 4,
+					Headers:  header,
+				})).Return(nil)
 			},
 			want: true,
 		},
@@ -926,6 +1418,7 @@ func TestAction_Execute(t *testing.T) {
 				svc.On("PublicUrl", "https", "/test").Return(reqUrl, nil)
 				expectedRequest, err := http.NewRequestWithContext(ctx, "POST", reqUrl, nil)
 				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
 				expectedRequest.Header.Add("content-type", "application/json")
 				body := &bodyReader{msg: "response"}
 				header := http.Header{}
@@ -946,11 +1439,12 @@ func TestAction_Execute(t *testing.T) {
 				}
 				client := appMocks.NewMockHttpClient(t)
 				fnd.On("HttpClient", expectedTransport).Return(client)
-				client.On("Do", expectedRequest).Return(resp, nil)
-				rd.On("Store", "response/r1", ResponseData{
-					Body:    "response",
-					Headers: header,
-				}).Return(nil)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "response",
+					BodySize: 8,
+					Headers:  header,
+				})).Return(nil)
 			},
 			want: true,
 		},
@@ -982,6 +1476,7 @@ func TestAction_Execute(t *testing.T) {
 				svc.On("PublicUrl", "http", "/test").Return(reqUrl, nil)
 				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
 				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
 				expectedRequest.Header.Add("content-type", "application/json")
 				expectedRequest.Header.Add("user-agent", "wst")
 				body := &bodyReader{msg: "test"}
@@ -1003,11 +1498,12 @@ func TestAction_Execute(t *testing.T) {
 				}
 				client := appMocks.NewMockHttpClient(t)
 				fnd.On("HttpClient", expectedTransport).Return(client)
-				client.On("Do", expectedRequest).Return(resp, nil)
-				rd.On("Store", "response/r1", ResponseData{
-					Body:    "test",
-					Headers: header,
-				}).Return(nil)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "test",
+					BodySize: 4,
+					Headers:  header,
+				})).Return(nil)
 			},
 			want: true,
 		},
@@ -1036,6 +1532,7 @@ func TestAction_Execute(t *testing.T) {
 				svc.On("PublicUrl", "http", "/api/data").Return(reqUrl, nil)
 				expectedRequest, err := http.NewRequestWithContext(ctx, "PUT", reqUrl, nil)
 				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
 				body := &bodyReader{msg: "ok"}
 				header := http.Header{}
 				resp := &http.Response{
@@ -1052,11 +1549,12 @@ func TestAction_Execute(t *testing.T) {
 				}
 				client := appMocks.NewMockHttpClient(t)
 				fnd.On("HttpClient", expectedTransport).Return(client)
-				client.On("Do", expectedRequest).Return(resp, nil)
-				rd.On("Store", "response/r2", ResponseData{
-					Body:    "ok",
-					Headers: header,
-				}).Return(nil)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				rd.On("Store", "response/r2", responseDataMatcher(ResponseData{
+					Body:     "ok",
+					BodySize: 2,
+					Headers:  header,
+				})).Return(nil)
 			},
 			want: true,
 		},
@@ -1094,6 +1592,7 @@ func TestAction_Execute(t *testing.T) {
 
 				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
 				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
 				expectedRequest.Header.Add("content-type", "application/json")
 				expectedRequest.Header.Add("user-agent", "wst")
 				body := &bodyReader{msg: "test"}
@@ -1124,11 +1623,12 @@ func TestAction_Execute(t *testing.T) {
 				}
 				client := appMocks.NewMockHttpClient(t)
 				fnd.On("HttpClient", expectedTransport).Return(client)
-				client.On("Do", expectedRequest).Return(resp, nil)
-				rd.On("Store", "response/r1", ResponseData{
-					Body:    "test",
-					Headers: header,
-				}).Return(nil)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "test",
+					BodySize: 4,
+					Headers:  header,
+				})).Return(nil)
 			},
 			want: true,
 		},
@@ -1163,6 +1663,7 @@ func TestAction_Execute(t *testing.T) {
 
 				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
 				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
 				body := &bodyReader{msg: "secure data"}
 				header := http.Header{}
 				resp := &http.Response{
@@ -1190,11 +1691,12 @@ func TestAction_Execute(t *testing.T) {
 				}
 				client := appMocks.NewMockHttpClient(t)
 				fnd.On("HttpClient", expectedTransport).Return(client)
-				client.On("Do", expectedRequest).Return(resp, nil)
-				rd.On("Store", "response/r3", ResponseData{
-					Body:    "secure data",
-					Headers: header,
-				}).Return(nil)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				rd.On("Store", "response/r3", responseDataMatcher(ResponseData{
+					Body:     "secure data",
+					BodySize: 11,
+					Headers:  header,
+				})).Return(nil)
 			},
 			want: true,
 		},
@@ -1226,6 +1728,7 @@ func TestAction_Execute(t *testing.T) {
 				svc.On("PublicUrl", "https", "/test").Return(publicUrl, nil)
 				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", publicUrl, nil)
 				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
 				expectedRequest.Header.Add("content-type", "application/json")
 				expectedRequest.Header.Add("user-agent", "wst")
 				expectedRequest.URL = &url.URL{
@@ -1254,11 +1757,12 @@ func TestAction_Execute(t *testing.T) {
 				}
 				client := appMocks.NewMockHttpClient(t)
 				fnd.On("HttpClient", expectedTransport).Return(client)
-				client.On("Do", expectedRequest).Return(resp, nil)
-				rd.On("Store", "response/r1", ResponseData{
-					Body:    "test",
-					Headers: header,
-				}).Return(nil)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "test",
+					BodySize: 4,
+					Headers:  header,
+				})).Return(nil)
 			},
 			want: true,
 		},
@@ -1287,6 +1791,7 @@ func TestAction_Execute(t *testing.T) {
 				svc.On("PublicUrl", "http", "/unencoded/path").Return(publicUrl, nil)
 				expectedRequest, err := http.NewRequestWithContext(ctx, "DELETE", publicUrl, nil)
 				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
 				expectedRequest.URL = &url.URL{
 					Scheme: "http",
 					Host:   "example.com",
@@ -1308,11 +1813,12 @@ func TestAction_Execute(t *testing.T) {
 				}
 				client := appMocks.NewMockHttpClient(t)
 				fnd.On("HttpClient", expectedTransport).Return(client)
-				client.On("Do", expectedRequest).Return(resp, nil)
-				rd.On("Store", "response/r4", ResponseData{
-					Body:    "deleted",
-					Headers: header,
-				}).Return(nil)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				rd.On("Store", "response/r4", responseDataMatcher(ResponseData{
+					Body:     "deleted",
+					BodySize: 7,
+					Headers:  header,
+				})).Return(nil)
 			},
 			want: true,
 		},
@@ -1409,6 +1915,7 @@ func TestAction_Execute(t *testing.T) {
 				svc.On("PublicUrl", "https", "/test").Return(reqUrl, nil)
 				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
 				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
 				expectedRequest.Header.Add("content-type", "application/json")
 				expectedRequest.Header.Add("user-agent", "wst")
 				body := &bodyReader{msg: "test"}
@@ -1432,11 +1939,12 @@ func TestAction_Execute(t *testing.T) {
 				}
 				client := appMocks.NewMockHttpClient(t)
 				fnd.On("HttpClient", expectedTransport).Return(client)
-				client.On("Do", expectedRequest).Return(resp, nil)
-				rd.On("Store", "response/r1", ResponseData{
-					Body:    "test",
-					Headers: header,
-				}).Return(errors.New("store failed"))
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "test",
+					BodySize: 4,
+					Headers:  header,
+				})).Return(errors.New("store failed"))
 			},
 			want:             false,
 			expectError:      true,
@@ -1470,6 +1978,7 @@ func TestAction_Execute(t *testing.T) {
 				svc.On("PublicUrl", "https", "/test").Return(reqUrl, nil)
 				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
 				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
 				expectedRequest.Header.Add("content-type", "application/json")
 				expectedRequest.Header.Add("user-agent", "wst")
 				body := &bodyReader{err: "failed read"}
@@ -1493,7 +2002,7 @@ func TestAction_Execute(t *testing.T) {
 				}
 				client := appMocks.NewMockHttpClient(t)
 				fnd.On("HttpClient", expectedTransport).Return(client)
-				client.On("Do", expectedRequest).Return(resp, nil)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
 			},
 			want:             false,
 			expectError:      true,
@@ -1527,6 +2036,7 @@ func TestAction_Execute(t *testing.T) {
 				svc.On("PublicUrl", "https", "/test").Return(reqUrl, nil)
 				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
 				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
 				expectedRequest.Header.Add("content-type", "application/json")
 				expectedRequest.Header.Add("user-agent", "wst")
 				body := &bodyReader{err: "failed read"}
@@ -1550,7 +2060,7 @@ func TestAction_Execute(t *testing.T) {
 				}
 				client := appMocks.NewMockHttpClient(t)
 				fnd.On("HttpClient", expectedTransport).Return(client)
-				client.On("Do", expectedRequest).Return(resp, nil)
+				client.On("Do", requestMatcher(expectedRequest)).Return(resp, nil)
 			},
 			contextSetup: func() context.Context {
 				ctx, cancel := context.WithCancel(context.Background())
@@ -1589,6 +2099,7 @@ func TestAction_Execute(t *testing.T) {
 				svc.On("PublicUrl", "https", "/test").Return(reqUrl, nil)
 				expectedRequest, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
 				assert.Nil(t, err)
+				expectedRequest.Header.Set("Accept-Encoding", "gzip")
 				expectedRequest.Header.Add("content-type", "application/json")
 				expectedRequest.Header.Add("user-agent", "wst")
 
@@ -1604,7 +2115,7 @@ func TestAction_Execute(t *testing.T) {
 				}
 				client := appMocks.NewMockHttpClient(t)
 				fnd.On("HttpClient", expectedTransport).Return(client)
-				client.On("Do", expectedRequest).Return(nil, errors.New("client fail"))
+				client.On("Do", requestMatcher(expectedRequest)).Return(nil, errors.New("client fail"))
 			},
 			want:             false,
 			expectError:      true,
@@ -1671,6 +2182,267 @@ func TestAction_Execute(t *testing.T) {
 			expectError:      true,
 			expectedErrorMsg: "pub url",
 		},
+		{
+			name:       "successful execution with new session",
+			id:         "r1",
+			scheme:     "http",
+			path:       "/test",
+			encodePath: true,
+			method:     "GET",
+			body:       &types.RequestBody{},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP11},
+			session:    "s1",
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				svc.On("PublicUrl", "http", "/test").Return("http://example.com/test", nil)
+				client := appMocks.NewMockHttpClient(t)
+				fnd.On("CustomHttpClient", mock.MatchedBy(func(c *http.Client) bool {
+					return c.Jar != nil && c.CheckRedirect != nil && c.Transport != nil
+				})).Return(client)
+				rd.On("Load", "session/s1").Return(nil, false)
+				rd.On("Store", "session/s1", mock.AnythingOfType("*request.Session")).Return(nil)
+				client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+					return req.URL.String() == "http://example.com/test"
+				})).Return(&http.Response{Body: &bodyReader{msg: "test"}, Header: http.Header{}}, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "test",
+					BodySize: 4,
+					Headers:  http.Header{},
+				})).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name:       "successful execution with existing session",
+			id:         "r1",
+			scheme:     "http",
+			path:       "/test",
+			encodePath: true,
+			method:     "GET",
+			body:       &types.RequestBody{},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP11},
+			session:    "s1",
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				svc.On("PublicUrl", "http", "/test").Return("http://example.com/test", nil)
+				client := appMocks.NewMockHttpClient(t)
+				rd.On("Load", "session/s1").Return(&Session{clients: map[string]*sessionClient{
+					"http [http1.1]": {client: client},
+				}}, true)
+				client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+					return req.URL.String() == "http://example.com/test"
+				})).Return(&http.Response{Body: &bodyReader{msg: "test"}, Header: http.Header{}}, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "test",
+					BodySize: 4,
+					Headers:  http.Header{},
+				})).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name:       "successful execution with existing session and different protocols",
+			id:         "r1",
+			scheme:     "http",
+			path:       "/test",
+			encodePath: true,
+			method:     "GET",
+			body:       &types.RequestBody{},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP2},
+			session:    "s1",
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				svc.On("PublicUrl", "http", "/test").Return("http://example.com/test", nil)
+				session := NewSession(fnd)
+				session.clients["http [http1.1]"] = &sessionClient{client: appMocks.NewMockHttpClient(t)}
+				rd.On("Load", "session/s1").Return(session, true)
+				client := appMocks.NewMockHttpClient(t)
+				fnd.On("CustomHttpClient", mock.MatchedBy(func(c *http.Client) bool {
+					tr, ok := c.Transport.(*http.Transport)
+					return ok && c.Jar == session.jar && tr.Protocols.UnencryptedHTTP2() && !tr.Protocols.HTTP1()
+				})).Return(client)
+				client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+					return req.URL.String() == "http://example.com/test"
+				})).Return(&http.Response{Body: &bodyReader{msg: "test"}, Header: http.Header{}}, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "test",
+					BodySize: 4,
+					Headers:  http.Header{},
+				})).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name:       "successful execution with redirects config",
+			id:         "r1",
+			scheme:     "http",
+			path:       "/test",
+			encodePath: true,
+			method:     "GET",
+			body:       &types.RequestBody{},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP11},
+			redirects: types.RedirectConfig{
+				Disabled: true,
+			},
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				svc.On("PublicUrl", "http", "/test").Return("http://example.com/test", nil)
+				client := appMocks.NewMockHttpClient(t)
+				fnd.On("CustomHttpClient", mock.MatchedBy(func(c *http.Client) bool {
+					return c.Jar == nil && c.CheckRedirect != nil && c.Transport != nil
+				})).Return(client)
+				client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+					return req.URL.String() == "http://example.com/test"
+				})).Return(&http.Response{Body: &bodyReader{msg: "test"}, Header: http.Header{}}, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "test",
+					BodySize: 4,
+					Headers:  http.Header{},
+				})).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name:       "failed execution because invalid session data type",
+			id:         "r1",
+			scheme:     "http",
+			path:       "/test",
+			encodePath: true,
+			method:     "GET",
+			body:       &types.RequestBody{},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP11},
+			session:    "s1",
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				svc.On("PublicUrl", "http", "/test").Return("http://example.com/test", nil)
+				rd.On("Load", "session/s1").Return("invalid", true)
+			},
+			want:             false,
+			expectError:      true,
+			expectedErrorMsg: "invalid session data type for session s1",
+		},
+		{
+			name:       "failed execution because session storing failed",
+			id:         "r1",
+			scheme:     "http",
+			path:       "/test",
+			encodePath: true,
+			method:     "GET",
+			body:       &types.RequestBody{},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP11},
+			session:    "s1",
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				svc.On("PublicUrl", "http", "/test").Return("http://example.com/test", nil)
+				rd.On("Load", "session/s1").Return(nil, false)
+				rd.On("Store", "session/s1", mock.AnythingOfType("*request.Session")).Return(errors.New("store failed"))
+			},
+			want:             false,
+			expectError:      true,
+			expectedErrorMsg: "store failed",
+		},
+		{
+			name:       "successful execution with multipart body",
+			id:         "r1",
+			scheme:     "http",
+			path:       "/upload",
+			encodePath: true,
+			method:     "POST",
+			body: &types.RequestBody{
+				Multipart: []types.RequestBodyPart{
+					{Name: "field", Content: "value"},
+				},
+			},
+			tls:       &types.TLSClientConfig{},
+			protocols: []Protocol{ProtocolHTTP11},
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				svc.On("PublicUrl", "http", "/upload").Return("http://example.com/upload", nil)
+				client := appMocks.NewMockHttpClient(t)
+				fnd.On("HttpClient", mock.Anything).Return(client)
+				client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+					if !strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data; boundary=") {
+						return false
+					}
+					if err := req.ParseMultipartForm(1024); err != nil {
+						return false
+					}
+					return req.FormValue("field") == "value" && req.ContentLength > 0
+				})).Return(&http.Response{Body: &bodyReader{msg: "ok"}, Header: http.Header{}}, nil)
+				rd.On("Store", "response/r1", responseDataMatcher(ResponseData{
+					Body:     "ok",
+					BodySize: 2,
+					Headers:  http.Header{},
+				})).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name:       "failed execution because body file not found",
+			id:         "r1",
+			scheme:     "http",
+			path:       "/upload",
+			encodePath: true,
+			method:     "POST",
+			body:       &types.RequestBody{File: "/data/missing.bin"},
+			tls:        &types.TLSClientConfig{},
+			protocols:  []Protocol{ProtocolHTTP11},
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				rd *runtimeMocks.MockData,
+				fnd *appMocks.MockFoundation,
+				svc *servicesMocks.MockService,
+			) {
+				svc.On("PublicUrl", "http", "/upload").Return("http://example.com/upload", nil)
+				fnd.On("DryRun").Return(false)
+				fnd.On("Fs").Return(afero.NewMemMapFs())
+			},
+			want:             false,
+			expectError:      true,
+			expectedErrorMsg: "failed to stat request body file /data/missing.bin",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1702,6 +2474,9 @@ func TestAction_Execute(t *testing.T) {
 				body:       tt.body,
 				tls:        tt.tls,
 				protocols:  tt.protocols,
+				session:    tt.session,
+				redirects:  tt.redirects,
+				decoding:   tt.decoding,
 			}
 
 			got, err := a.Execute(ctx, runDataMock)
@@ -1718,6 +2493,68 @@ func TestAction_Execute(t *testing.T) {
 	}
 }
 
+type closeTrackingFs struct {
+	afero.Fs
+	files []*closeTrackingFile
+}
+
+func (fs *closeTrackingFs) Open(name string) (afero.File, error) {
+	file, err := fs.Fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	tracked := &closeTrackingFile{File: file}
+	fs.files = append(fs.files, tracked)
+	return tracked, nil
+}
+
+type closeTrackingFile struct {
+	afero.File
+	closed bool
+}
+
+func (f *closeTrackingFile) Close() error {
+	f.closed = true
+	return f.File.Close()
+}
+
+func TestAction_Execute_ClosesBodyFileOnClientError(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	runDataMock := runtimeMocks.NewMockData(t)
+	svcMock := servicesMocks.NewMockService(t)
+	mockLogger := external.NewMockLogger()
+	fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+	fs := &closeTrackingFs{Fs: afero.NewMemMapFs()}
+	assert.NoError(t, afero.WriteFile(fs.Fs, "/data/upload.bin", []byte("data"), 0644))
+	fndMock.On("DryRun").Return(false)
+	fndMock.On("Fs").Return(fs)
+	svcMock.On("PublicUrl", "http", "/upload").Return("http://example.com/upload", nil)
+	runDataMock.On("Load", "session/s1").Return("invalid", true)
+
+	a := &Action{
+		fnd:        fndMock,
+		service:    svcMock,
+		timeout:    3000 * time.Millisecond,
+		id:         "r1",
+		scheme:     "http",
+		path:       "/upload",
+		encodePath: true,
+		method:     "POST",
+		body:       &types.RequestBody{File: "/data/upload.bin"},
+		tls:        &types.TLSClientConfig{},
+		protocols:  []Protocol{ProtocolHTTP11},
+		session:    "s1",
+	}
+
+	got, err := a.Execute(context.Background(), runDataMock)
+
+	assert.False(t, got)
+	assert.EqualError(t, err, "invalid session data type for session s1")
+	if assert.Len(t, fs.files, 1) {
+		assert.True(t, fs.files[0].closed)
+	}
+}
+
 func TestAction_Timeout(t *testing.T) {
 	fndMock := appMocks.NewMockFoundation(t)
 	a := &Action{
@@ -0,0 +1,140 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package request
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+)
+
+const defaultMaxRedirects = 10
+
+// Session holds a cookie jar and HTTP clients that are shared by all request actions using the same session name
+// within a single instance run. Requests with the same transport configuration share a client and its connection
+// pool. Requests with a different configuration (e.g. TLS settings or protocols) get their own client that uses
+// the same cookie jar.
+type Session struct {
+	fnd     app.Foundation
+	jar     http.CookieJar
+	mu      sync.Mutex
+	clients map[string]*sessionClient
+}
+
+// sessionClient is the session client for a single transport configuration.
+type sessionClient struct {
+	client app.HttpClient
+	// close closes the client transport connections.
+	close func() error
+	// http3 is set if the transport cannot be used after closing so the client is removed on close.
+	http3 bool
+}
+
+// NewSession creates a session without any clients. The clients are created on the first use of their transport
+// configuration.
+func NewSession(fnd app.Foundation) *Session {
+	// Error is ignored because cookiejar.New never fails without options.
+	jar, _ := cookiejar.New(nil)
+	return &Session{
+		fnd:     fnd,
+		jar:     jar,
+		clients: make(map[string]*sessionClient),
+	}
+}
+
+// Client returns the session client for the transport configuration key. The passed transport is used only if the
+// client for the key does not exist yet.
+func (s *Session) Client(key string, tr *http.Transport) app.HttpClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.clients[key]; ok {
+		return c.client
+	}
+	return s.addClient(key, tr, func() error {
+		tr.CloseIdleConnections()
+		return nil
+	}, false)
+}
+
+// Http3Client returns the session HTTP/3 client for the transport configuration key and the alternative address.
+func (s *Session) Http3Client(key string, tlsConfig *tls.Config, altAddress string) app.HttpClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key = fmt.Sprintf("%s %s %s", ProtocolHTTP3, key, altAddress)
+	if c, ok := s.clients[key]; ok {
+		return c.client
+	}
+	tr := NewHttp3Transport(tlsConfig, altAddress)
+	return s.addClient(key, tr, tr.Close, true)
+}
+
+func (s *Session) addClient(key string, tr http.RoundTripper, closeFunc func() error, http3 bool) app.HttpClient {
+	client := s.fnd.CustomHttpClient(&http.Client{
+		Transport:     tr,
+		Jar:           s.jar,
+		CheckRedirect: checkRedirect,
+	})
+	s.clients[key] = &sessionClient{client: client, close: closeFunc, http3: http3}
+	return client
+}
+
+// Close closes all idle connections in the session pools and the HTTP/3 transports.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var closeErr error
+	for key, c := range s.clients {
+		if err := c.close(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+		if c.http3 {
+			delete(s.clients, key)
+		}
+	}
+	return closeErr
+}
+
+func sessionKey(name string) string {
+	return fmt.Sprintf("session/%s", name)
+}
+
+type redirectPolicyKey struct{}
+
+func withRedirectPolicy(ctx context.Context, config types.RedirectConfig) context.Context {
+	return context.WithValue(ctx, redirectPolicyKey{}, config)
+}
+
+// checkRedirect applies redirect policy of the request action that initiated the request.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	config, _ := req.Context().Value(redirectPolicyKey{}).(types.RedirectConfig)
+	if config.Disabled {
+		return http.ErrUseLastResponse
+	}
+	maxRedirects := config.Max
+	if maxRedirects == 0 {
+		maxRedirects = defaultMaxRedirects
+	}
+	if len(via) > maxRedirects {
+		return errors.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return nil
+}
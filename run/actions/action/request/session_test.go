@@ -0,0 +1,204 @@
+package request
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+)
+
+func newTestSession(t *testing.T) *Session {
+	fndMock := appMocks.NewMockFoundation(t)
+	fndMock.On("CustomHttpClient", mock.Anything).Return(func(c *http.Client) app.HttpClient {
+		return app.NewCustomHttpClient(c)
+	})
+	return NewSession(fndMock)
+}
+
+func sendSessionRequest(
+	t *testing.T,
+	s *Session,
+	url string,
+	config types.RedirectConfig,
+) (*http.Response, bool, error) {
+	reused := false
+	ctx := httptrace.WithClientTrace(withRedirectPolicy(context.Background(), config), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			reused = info.Reused
+		},
+	})
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	require.NoError(t, err)
+	resp, err := s.Client("http [http1.1]", &http.Transport{}).Do(req)
+	if err == nil {
+		_, _ = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+	}
+	return resp, reused, err
+}
+
+func TestSession_CookiesAndConnectionReuse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "sid", Value: "abc", Path: "/"})
+			_, _ = w.Write([]byte("logged"))
+		case "/profile":
+			cookie, err := r.Cookie("sid")
+			if err != nil || cookie.Value != "abc" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("profile"))
+		}
+	}))
+	defer server.Close()
+
+	s := newTestSession(t)
+	defer s.Close()
+
+	resp, reused, err := sendSessionRequest(t, s, server.URL+"/login", types.RedirectConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.False(t, reused)
+
+	resp, reused, err = sendSessionRequest(t, s, server.URL+"/profile", types.RedirectConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, reused)
+
+	assert.NoError(t, s.Close())
+	_, reused, err = sendSessionRequest(t, s, server.URL+"/profile", types.RedirectConfig{})
+	require.NoError(t, err)
+	assert.False(t, reused)
+}
+
+func TestSession_Redirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusFound)
+		default:
+			_, _ = w.Write([]byte("done"))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name             string
+		config           types.RedirectConfig
+		expectedStatus   int
+		expectedErrorMsg string
+	}{
+		{
+			name:           "follows redirects by default",
+			config:         types.RedirectConfig{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "returns redirect response if disabled",
+			config:         types.RedirectConfig{Disabled: true},
+			expectedStatus: http.StatusFound,
+		},
+		{
+			name:           "follows redirects up to max",
+			config:         types.RedirectConfig{Max: 2},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:             "fails when max redirects exceeded",
+			config:           types.RedirectConfig{Max: 1},
+			expectedErrorMsg: "stopped after 1 redirects",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSession(t)
+			defer s.Close()
+
+			resp, _, err := sendSessionRequest(t, s, server.URL+"/a", tt.config)
+			if tt.expectedErrorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestSession_CloseWithoutTransport(t *testing.T) {
+	s := &Session{}
+	assert.NoError(t, s.Close())
+}
+
+func TestSession_ClientPerTransportConfig(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "sid", Value: "abc", Path: "/"})
+		case "/profile":
+			if cookie, err := r.Cookie("sid"); err != nil || cookie.Value != "abc" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+		_, _ = w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	s := newTestSession(t)
+	defer s.Close()
+
+	send := func(key string, tr *http.Transport, path string) *http.Response {
+		req, err := http.NewRequest("GET", server.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := s.Client(key, tr).Do(req)
+		require.NoError(t, err)
+		_, _ = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	http1Transport := BuildTransport([]Protocol{ProtocolHTTP11}, "https", &tls.Config{InsecureSkipVerify: true})
+	http1Client := s.Client("https [http1.1]", http1Transport)
+	resp := send("https [http1.1]", http1Transport, "/login")
+	assert.Equal(t, "HTTP/1.1", resp.Proto)
+
+	// Different protocols must not reuse the transport of the first request but keep the cookies.
+	http2Transport := BuildTransport([]Protocol{ProtocolHTTP2}, "https", &tls.Config{InsecureSkipVerify: true})
+	http2Client := s.Client("https [http2]", http2Transport)
+	assert.NotSame(t, http1Client, http2Client)
+	assert.Same(t, http1Client, s.Client("https [http1.1]", &http.Transport{}))
+	resp = send("https [http2]", http2Transport, "/profile")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "HTTP/2.0", resp.Proto)
+}
+
+func TestSession_Http3Client(t *testing.T) {
+	s := newTestSession(t)
+
+	client := s.Http3Client("https [http3]", &tls.Config{}, "")
+	assert.NotNil(t, client)
+	assert.Same(t, client, s.Http3Client("https [http3]", &tls.Config{}, ""))
+	assert.NotSame(t, client, s.Http3Client("https [http3]", &tls.Config{}, "127.0.0.1:8443"))
+	assert.Len(t, s.clients, 2)
+
+	assert.NoError(t, s.Close())
+	assert.Empty(t, s.clients)
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package request
+
+import (
+	"crypto/tls"
+
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/services"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// IsTLSConfigured returns true if any TLS client option is set.
+func IsTLSConfigured(config *types.TLSClientConfig) bool {
+	return config.SkipVerify || config.CACert != "" || config.ClientCert != "" || config.ServerName != "" ||
+		config.MinVersion != "" || config.MaxVersion != "" || len(config.CipherSuites) > 0
+}
+
+// ValidateTLSConfig checks that TLS versions and cipher suites are known.
+func ValidateTLSConfig(config *types.TLSClientConfig) error {
+	minVersion, err := parseTLSVersion(config.MinVersion)
+	if err != nil {
+		return err
+	}
+	maxVersion, err := parseTLSVersion(config.MaxVersion)
+	if err != nil {
+		return err
+	}
+	if minVersion != 0 && maxVersion != 0 && minVersion > maxVersion {
+		return errors.Errorf("TLS min version %s is greater than max version %s", config.MinVersion, config.MaxVersion)
+	}
+	_, err = parseCipherSuites(config.CipherSuites)
+	return err
+}
+
+func parseTLSVersion(version string) (uint16, error) {
+	if version == "" {
+		return 0, nil
+	}
+	v, ok := tlsVersions[version]
+	if !ok {
+		return 0, errors.Errorf("invalid TLS version %s", version)
+	}
+	return v, nil
+}
+
+func parseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	suites := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		suites[suite.Name] = suite.ID
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		suites[suite.Name] = suite.ID
+	}
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := suites[name]
+		if !ok {
+			return nil, errors.Errorf("invalid TLS cipher suite %s", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// BuildTLSConfig creates the client TLS configuration. The CA and client certificates are looked up in the
+// service certificates.
+func BuildTLSConfig(
+	fnd app.Foundation,
+	svc services.Service,
+	config *types.TLSClientConfig,
+) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.SkipVerify,
+		ServerName:         config.ServerName,
+	}
+
+	if config.CACert != "" {
+		caCert, err := svc.FindCertificate(config.CACert)
+		if err != nil {
+			return nil, errors.Errorf("CA certificate %s not found", config.CACert)
+		}
+		caCertPool := fnd.X509CertPool()
+		if !caCertPool.AppendCertFromPEM(caCert.Certificate.CertificateData()) {
+			return nil, errors.New("failed to parse CA certificate")
+		}
+		tlsConfig.RootCAs = caCertPool.CertPool()
+	}
+
+	if config.ClientCert != "" {
+		clientCert, err := svc.FindCertificate(config.ClientCert)
+		if err != nil {
+			return nil, errors.Errorf("client certificate %s not found", config.ClientCert)
+		}
+		keyPair, err := tls.X509KeyPair(
+			[]byte(clientCert.Certificate.CertificateData()),
+			[]byte(clientCert.Certificate.PrivateKeyData()),
+		)
+		if err != nil {
+			return nil, errors.Errorf("failed to load client certificate %s: %v", config.ClientCert, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{keyPair}
+	}
+
+	var err error
+	if tlsConfig.MinVersion, err = parseTLSVersion(config.MinVersion); err != nil {
+		return nil, err
+	}
+	if tlsConfig.MaxVersion, err = parseTLSVersion(config.MaxVersion); err != nil {
+		return nil, err
+	}
+	if tlsConfig.CipherSuites, err = parseCipherSuites(config.CipherSuites); err != nil {
+		return nil, err
+	}
+
+	return tlsConfig, nil
+}
+
+// TLSInfo holds the negotiated TLS connection details.
+type TLSInfo struct {
+	Version     string
+	CipherSuite string
+	ALPN        string
+	PeerSubject string
+}
+
+// NewTLSInfo creates TLS info from the connection state. It returns nil for connections without TLS.
+func NewTLSInfo(state *tls.ConnectionState) *TLSInfo {
+	if state == nil {
+		return nil
+	}
+	info := &TLSInfo{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ALPN:        state.NegotiatedProtocol,
+	}
+	if len(state.PeerCertificates) > 0 {
+		info.PeerSubject = state.PeerCertificates[0].Subject.String()
+	}
+	return info
+}
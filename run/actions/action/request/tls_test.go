@@ -0,0 +1,313 @@
+package request
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	certificatesMocks "github.com/wstool/wst/mocks/generated/run/resources/certificates"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/resources/certificates"
+)
+
+// generateTestCertificate generates a self-signed certificate and returns its PEM encoded certificate and key.
+func generateTestCertificate(t *testing.T, commonName string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	return string(certPem), string(keyPem)
+}
+
+func mockRenderedCertificate(t *testing.T, certData, keyData string) *certificates.RenderedCertificate {
+	cert := certificatesMocks.NewMockCertificate(t)
+	cert.On("CertificateData").Return(certData)
+	cert.On("PrivateKeyData").Return(keyData)
+	return &certificates.RenderedCertificate{Certificate: cert}
+}
+
+func TestIsTLSConfigured(t *testing.T) {
+	assert.False(t, IsTLSConfigured(&types.TLSClientConfig{}))
+	assert.True(t, IsTLSConfigured(&types.TLSClientConfig{SkipVerify: true}))
+	assert.True(t, IsTLSConfigured(&types.TLSClientConfig{ClientCert: "client"}))
+	assert.True(t, IsTLSConfigured(&types.TLSClientConfig{ServerName: "localhost"}))
+	assert.True(t, IsTLSConfigured(&types.TLSClientConfig{MaxVersion: "1.2"}))
+	assert.True(t, IsTLSConfigured(&types.TLSClientConfig{CipherSuites: []string{"TLS_AES_128_GCM_SHA256"}}))
+}
+
+func TestValidateTLSConfig(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           *types.TLSClientConfig
+		expectedErrorMsg string
+	}{
+		{
+			name:   "empty config",
+			config: &types.TLSClientConfig{},
+		},
+		{
+			name: "valid versions and cipher suites",
+			config: &types.TLSClientConfig{
+				MinVersion:   "1.2",
+				MaxVersion:   "1.3",
+				CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", "TLS_RSA_WITH_RC4_128_SHA"},
+			},
+		},
+		{
+			name:             "invalid min version",
+			config:           &types.TLSClientConfig{MinVersion: "0.9"},
+			expectedErrorMsg: "invalid TLS version 0.9",
+		},
+		{
+			name:             "invalid max version",
+			config:           &types.TLSClientConfig{MaxVersion: "1.4"},
+			expectedErrorMsg: "invalid TLS version 1.4",
+		},
+		{
+			name:             "min version greater than max version",
+			config:           &types.TLSClientConfig{MinVersion: "1.3", MaxVersion: "1.2"},
+			expectedErrorMsg: "TLS min version 1.3 is greater than max version 1.2",
+		},
+		{
+			name:             "invalid cipher suite",
+			config:           &types.TLSClientConfig{CipherSuites: []string{"TLS_UNKNOWN"}},
+			expectedErrorMsg: "invalid TLS cipher suite TLS_UNKNOWN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTLSConfig(tt.config)
+			if tt.expectedErrorMsg != "" {
+				assert.EqualError(t, err, tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	certData, keyData := generateTestCertificate(t, "client")
+	keyPair, err := tls.X509KeyPair([]byte(certData), []byte(keyData))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		config           *types.TLSClientConfig
+		setupMocks       func(*testing.T, *servicesMocks.MockService)
+		expectedConfig   *tls.Config
+		expectedErrorMsg string
+	}{
+		{
+			name: "full config",
+			config: &types.TLSClientConfig{
+				ClientCert:   "client",
+				ServerName:   "example.com",
+				MinVersion:   "1.2",
+				MaxVersion:   "1.3",
+				CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+			},
+			setupMocks: func(t *testing.T, svc *servicesMocks.MockService) {
+				svc.On("FindCertificate", "client").Return(mockRenderedCertificate(t, certData, keyData), nil)
+			},
+			expectedConfig: &tls.Config{
+				ServerName:   "example.com",
+				Certificates: []tls.Certificate{keyPair},
+				MinVersion:   tls.VersionTLS12,
+				MaxVersion:   tls.VersionTLS13,
+				CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+			},
+		},
+		{
+			name:   "client certificate not found",
+			config: &types.TLSClientConfig{ClientCert: "missing"},
+			setupMocks: func(t *testing.T, svc *servicesMocks.MockService) {
+				svc.On("FindCertificate", "missing").Return(nil, errors.New("not found"))
+			},
+			expectedErrorMsg: "client certificate missing not found",
+		},
+		{
+			name:   "invalid client certificate",
+			config: &types.TLSClientConfig{ClientCert: "client"},
+			setupMocks: func(t *testing.T, svc *servicesMocks.MockService) {
+				svc.On("FindCertificate", "client").Return(mockRenderedCertificate(t, "invalid", "invalid"), nil)
+			},
+			expectedErrorMsg: "failed to load client certificate client",
+		},
+		{
+			name:             "invalid version",
+			config:           &types.TLSClientConfig{MaxVersion: "1.5"},
+			expectedErrorMsg: "invalid TLS version 1.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			svcMock := servicesMocks.NewMockService(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(t, svcMock)
+			}
+
+			got, err := BuildTLSConfig(fndMock, svcMock, tt.config)
+
+			if tt.expectedErrorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+				assert.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedConfig, got)
+			}
+		})
+	}
+}
+
+func TestNewTLSInfo(t *testing.T) {
+	assert.Nil(t, NewTLSInfo(nil))
+	assert.Equal(t, &TLSInfo{
+		Version:     "TLS 1.3",
+		CipherSuite: "TLS_AES_128_GCM_SHA256",
+		ALPN:        "h2",
+		PeerSubject: "CN=server",
+	}, NewTLSInfo(&tls.ConnectionState{
+		Version:            tls.VersionTLS13,
+		CipherSuite:        tls.TLS_AES_128_GCM_SHA256,
+		NegotiatedProtocol: "h2",
+		PeerCertificates:   []*x509.Certificate{{Subject: pkix.Name{CommonName: "server"}}},
+	}))
+}
+
+func TestAction_Execute_MutualTLS(t *testing.T) {
+	certData, keyData := generateTestCertificate(t, "client")
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM([]byte(certData)))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.EnableHTTP2 = true
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name             string
+		tls              *types.TLSClientConfig
+		setupMocks       func(*testing.T, *servicesMocks.MockService)
+		expectedTLS      *TLSInfo
+		expectedErrorMsg string
+	}{
+		{
+			name: "client certificate accepted",
+			tls: &types.TLSClientConfig{
+				SkipVerify: true,
+				ClientCert: "client",
+				MaxVersion: "1.2",
+				CipherSuites: []string{
+					"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+				},
+			},
+			setupMocks: func(t *testing.T, svc *servicesMocks.MockService) {
+				svc.On("FindCertificate", "client").Return(mockRenderedCertificate(t, certData, keyData), nil)
+			},
+			expectedTLS: &TLSInfo{
+				Version:     "TLS 1.2",
+				CipherSuite: "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+				ALPN:        "h2",
+				PeerSubject: "O=Acme Co",
+			},
+		},
+		{
+			name:             "client certificate missing",
+			tls:              &types.TLSClientConfig{SkipVerify: true},
+			expectedErrorMsg: "certificate required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			svcMock := servicesMocks.NewMockService(t)
+			fndMock.On("Logger").Return(external.NewMockLogger().SugaredLogger)
+			fndMock.On("HttpClient", mock.Anything).Return(func(tr *http.Transport) app.HttpClient {
+				return app.NewRealHttpClient(tr)
+			})
+			svcMock.On("PublicUrl", "https", "/").Return(server.URL, nil)
+			if tt.setupMocks != nil {
+				tt.setupMocks(t, svcMock)
+			}
+			runData := runtime.CreateMaker(fndMock).MakeData()
+
+			a := &Action{
+				fnd:        fndMock,
+				service:    svcMock,
+				id:         "last",
+				scheme:     "https",
+				path:       "/",
+				encodePath: true,
+				method:     "GET",
+				body:       &types.RequestBody{},
+				tls:        tt.tls,
+				protocols:  []Protocol{ProtocolHTTP11, ProtocolHTTP2},
+			}
+
+			got, err := a.Execute(context.Background(), runData)
+
+			if tt.expectedErrorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+				assert.False(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, got)
+			data, ok := runData.Load("response/last")
+			require.True(t, ok)
+			responseData := data.(ResponseData)
+			assert.Equal(t, "client", responseData.Body)
+			assert.Equal(t, tt.expectedTLS, responseData.TLS)
+		})
+	}
+}
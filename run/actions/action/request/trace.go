@@ -0,0 +1,108 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package request
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// ResponseTiming holds the durations of the request phases. The phases that did not happen (e.g. DNS lookup for
+// IP address or connecting and TLS handshake for a reused connection) are zero.
+type ResponseTiming struct {
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	// TTFB is the time from the request start to receiving the first response byte.
+	TTFB time.Duration
+	// Total is the time from the request start to reading the whole response body.
+	Total time.Duration
+}
+
+// requestTrace records the connection reuse and the phase timings of the request. The trace callbacks can be called
+// from different goroutines so all fields are guarded by the mutex.
+type requestTrace struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	connReused   bool
+	timing       ResponseTiming
+}
+
+func newRequestTrace(start time.Time) *requestTrace {
+	return &requestTrace{start: start}
+}
+
+// withContext returns the context tracing the request.
+func (t *requestTrace) withContext(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.record(func() { t.dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.record(func() { t.timing.DNS = time.Since(t.dnsStart) })
+		},
+		ConnectStart: func(string, string) {
+			t.record(func() {
+				// Multiple addresses can be dialed in parallel so only the first start is used.
+				if t.connectStart.IsZero() {
+					t.connectStart = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(_ string, _ string, err error) {
+			if err == nil {
+				t.record(func() { t.timing.Connect = time.Since(t.connectStart) })
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.record(func() { t.tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.record(func() { t.timing.TLSHandshake = time.Since(t.tlsStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.record(func() { t.connReused = info.Reused })
+		},
+		GotFirstResponseByte: func() {
+			t.record(func() { t.timing.TTFB = time.Since(t.start) })
+		},
+	})
+}
+
+func (t *requestTrace) record(fn func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fn()
+}
+
+// finish sets the total duration and returns the recorded timing.
+func (t *requestTrace) finish() ResponseTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.timing.Total = time.Since(t.start)
+	return t.timing
+}
+
+func (t *requestTrace) reused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.connReused
+}
@@ -0,0 +1,82 @@
+package request
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_requestTrace(t *testing.T) {
+	tests := []struct {
+		name       string
+		tls        bool
+		requests   int
+		wantReused bool
+	}{
+		{
+			name:     "new http connection",
+			requests: 1,
+		},
+		{
+			name:     "new https connection",
+			tls:      true,
+			requests: 1,
+		},
+		{
+			name:       "reused http connection",
+			requests:   2,
+			wantReused: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("ok"))
+			})
+			var server *httptest.Server
+			if tt.tls {
+				server = httptest.NewTLSServer(handler)
+			} else {
+				server = httptest.NewServer(handler)
+			}
+			defer server.Close()
+			client := server.Client()
+
+			var trace *requestTrace
+			var timing ResponseTiming
+			for i := 0; i < tt.requests; i++ {
+				trace = newRequestTrace(time.Now())
+				req, err := http.NewRequestWithContext(trace.withContext(t.Context()), "GET", server.URL, nil)
+				require.NoError(t, err)
+				resp, err := client.Do(req)
+				require.NoError(t, err)
+				_, err = io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+				timing = trace.finish()
+			}
+
+			assert.Equal(t, tt.wantReused, trace.reused())
+			assert.Greater(t, timing.TTFB, time.Duration(0))
+			assert.GreaterOrEqual(t, timing.Total, timing.TTFB)
+			// The server address is an IP address so no DNS lookup is done.
+			assert.Equal(t, time.Duration(0), timing.DNS)
+			if tt.wantReused {
+				assert.Equal(t, time.Duration(0), timing.Connect)
+			} else {
+				assert.Greater(t, timing.Connect, time.Duration(0))
+			}
+			if tt.tls && !tt.wantReused {
+				assert.Greater(t, timing.TLSHandshake, time.Duration(0))
+			} else {
+				assert.Equal(t, time.Duration(0), timing.TLSHandshake)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scrape
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatFpm        Format = "fpm"
+	FormatNginx      Format = "nginx"
+	FormatPrometheus Format = "prometheus"
+)
+
+// Parse parses the status page content in the format to metric values.
+func Parse(format Format, data []byte) (map[string]float64, error) {
+	switch format {
+	case FormatFpm:
+		return parseFpm(data)
+	case FormatNginx:
+		return parseNginx(data)
+	case FormatPrometheus:
+		return parsePrometheus(data)
+	default:
+		return nil, errors.Errorf("invalid scrape format %s", format)
+	}
+}
+
+// parseFpm parses the PHP-FPM status page in JSON or plain text format. Only numeric values are used.
+func parseFpm(data []byte) (map[string]float64, error) {
+	data = bytes.TrimSpace(data)
+	values := make(map[string]float64)
+	if bytes.HasPrefix(data, []byte("{")) {
+		var status map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&status); err != nil {
+			return nil, errors.Errorf("invalid FPM status JSON: %v", err)
+		}
+		for key, value := range status {
+			if number, ok := value.(json.Number); ok {
+				if floatValue, err := number.Float64(); err == nil {
+					values[key] = floatValue
+				}
+			}
+		}
+		return values, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		if floatValue, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			values[strings.TrimSpace(key)] = floatValue
+		}
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no values found in FPM status")
+	}
+	return values, nil
+}
+
+// parseNginx parses the nginx stub_status page.
+func parseNginx(data []byte) (map[string]float64, error) {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		return nil, errors.Errorf("invalid nginx stub status with %d lines", len(lines))
+	}
+	values := make(map[string]float64)
+	parseNumber := func(field string) (float64, error) {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return 0, errors.Errorf("invalid nginx stub status value %s", field)
+		}
+		return value, nil
+	}
+
+	key, value, found := strings.Cut(lines[0], ":")
+	if !found || strings.TrimSpace(key) != "Active connections" {
+		return nil, errors.Errorf("invalid nginx stub status line %q", lines[0])
+	}
+	var err error
+	if values["active connections"], err = parseNumber(strings.TrimSpace(value)); err != nil {
+		return nil, err
+	}
+
+	names := strings.Fields(lines[1])
+	counts := strings.Fields(lines[2])
+	if len(names) != 4 || len(counts) != 3 {
+		return nil, errors.Errorf("invalid nginx stub status counters %q", lines[2])
+	}
+	for i, name := range names[1:] {
+		if values[name], err = parseNumber(counts[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	fields := strings.Fields(lines[3])
+	if len(fields)%2 != 0 {
+		return nil, errors.Errorf("invalid nginx stub status line %q", lines[3])
+	}
+	for i := 0; i < len(fields); i += 2 {
+		name := strings.ToLower(strings.TrimSuffix(fields[i], ":"))
+		if values[name], err = parseNumber(fields[i+1]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// parsePrometheus parses the Prometheus text exposition format. The series are identified by the metric name and
+// labels sorted by name (e.g. `http_requests_total{code="200",method="get"}`). The metric name without labels is
+// also set to the sum of all its series if it has only labeled series.
+func parsePrometheus(data []byte) (map[string]float64, error) {
+	values := make(map[string]float64)
+	sums := make(map[string]float64)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, labels, rest, err := splitPrometheusSeries(line)
+		if err != nil {
+			return nil, err
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, errors.Errorf("invalid prometheus line %q", line)
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid prometheus value in line %q", line)
+		}
+		if len(labels) == 0 {
+			values[name] = value
+		} else {
+			values[name+"{"+strings.Join(labels, ",")+"}"] = value
+			sums[name] += value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for name, sum := range sums {
+		if _, ok := values[name]; !ok {
+			values[name] = sum
+		}
+	}
+	return values, nil
+}
+
+// splitPrometheusSeries splits the line to the metric name, sorted labels and the rest with value and timestamp.
+func splitPrometheusSeries(line string) (string, []string, string, error) {
+	end := strings.IndexAny(line, "{ \t")
+	if end <= 0 {
+		return "", nil, "", errors.Errorf("invalid prometheus line %q", line)
+	}
+	name := line[:end]
+	if line[end] != '{' {
+		return name, nil, line[end:], nil
+	}
+
+	var labels []string
+	pos := end + 1
+	for {
+		for pos < len(line) && (line[pos] == ' ' || line[pos] == ',') {
+			pos++
+		}
+		if pos < len(line) && line[pos] == '}' {
+			break
+		}
+		eq := strings.IndexByte(line[pos:], '=')
+		if eq < 0 || pos+eq+1 >= len(line) || line[pos+eq+1] != '"' {
+			return "", nil, "", errors.Errorf("invalid prometheus labels in line %q", line)
+		}
+		labelName := strings.TrimSpace(line[pos : pos+eq])
+		valueStart := pos + eq + 2
+		valueEnd := valueStart
+		for valueEnd < len(line) && line[valueEnd] != '"' {
+			if line[valueEnd] == '\\' {
+				valueEnd++
+			}
+			valueEnd++
+		}
+		if valueEnd >= len(line) {
+			return "", nil, "", errors.Errorf("invalid prometheus labels in line %q", line)
+		}
+		labels = append(labels, labelName+"="+line[valueStart-1:valueEnd+1])
+		pos = valueEnd + 1
+	}
+	sort.Strings(labels)
+	return name, labels, line[pos+1:], nil
+}
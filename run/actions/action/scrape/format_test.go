@@ -0,0 +1,163 @@
+package scrape
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		data     string
+		expected map[string]float64
+		errMsg   string
+	}{
+		{
+			name:   "fpm json status",
+			format: FormatFpm,
+			data: `{"pool":"www","process manager":"dynamic","accepted conn":12,` +
+				`"listen queue":0,"active processes":1,"idle processes":2}`,
+			expected: map[string]float64{
+				"accepted conn":    12,
+				"listen queue":     0,
+				"active processes": 1,
+				"idle processes":   2,
+			},
+		},
+		{
+			name:   "fpm text status",
+			format: FormatFpm,
+			data: "pool:                 www\n" +
+				"process manager:      dynamic\n" +
+				"start time:           19/Oct/2026:10:00:00 +0000\n" +
+				"accepted conn:        12\n" +
+				"active processes:     1\n",
+			expected: map[string]float64{
+				"accepted conn":    12,
+				"active processes": 1,
+			},
+		},
+		{
+			name:   "fpm invalid json",
+			format: FormatFpm,
+			data:   `{"pool":`,
+			errMsg: "invalid FPM status JSON: unexpected EOF",
+		},
+		{
+			name:   "fpm text without values",
+			format: FormatFpm,
+			data:   "pool: www\n",
+			errMsg: "no values found in FPM status",
+		},
+		{
+			name:   "nginx stub status",
+			format: FormatNginx,
+			data: "Active connections: 2 \n" +
+				"server accepts handled requests\n" +
+				" 10 10 25 \n" +
+				"Reading: 0 Writing: 1 Waiting: 1 \n",
+			expected: map[string]float64{
+				"active connections": 2,
+				"accepts":            10,
+				"handled":            10,
+				"requests":           25,
+				"reading":            0,
+				"writing":            1,
+				"waiting":            1,
+			},
+		},
+		{
+			name:   "nginx stub status with wrong number of lines",
+			format: FormatNginx,
+			data:   "Active connections: 2\n",
+			errMsg: "invalid nginx stub status with 1 lines",
+		},
+		{
+			name:   "nginx stub status with invalid first line",
+			format: FormatNginx,
+			data: "Connections: 2\n" +
+				"server accepts handled requests\n" +
+				" 10 10 25\n" +
+				"Reading: 0 Writing: 1 Waiting: 1\n",
+			errMsg: "invalid nginx stub status line \"Connections: 2\"",
+		},
+		{
+			name:   "nginx stub status with invalid counter",
+			format: FormatNginx,
+			data: "Active connections: 2\n" +
+				"server accepts handled requests\n" +
+				" 10 x 25\n" +
+				"Reading: 0 Writing: 1 Waiting: 1\n",
+			errMsg: "invalid nginx stub status value x",
+		},
+		{
+			name:   "prometheus metrics",
+			format: FormatPrometheus,
+			data: "# HELP http_requests_total Total requests.\n" +
+				"# TYPE http_requests_total counter\n" +
+				"http_requests_total{method=\"get\",code=\"200\"} 10\n" +
+				"http_requests_total{code=\"500\", method=\"get\"} 2 1700000000000\n" +
+				"\n" +
+				"process_open_fds 7\n" +
+				"label_escape{path=\"a\\\"b\"} 1.5\n",
+			expected: map[string]float64{
+				"http_requests_total":                              12,
+				"http_requests_total{code=\"200\",method=\"get\"}": 10,
+				"http_requests_total{code=\"500\",method=\"get\"}": 2,
+				"process_open_fds":                                 7,
+				"label_escape":                                     1.5,
+				"label_escape{path=\"a\\\"b\"}":                    1.5,
+			},
+		},
+		{
+			name:   "prometheus unlabeled series is not overwritten by sum",
+			format: FormatPrometheus,
+			data: "up 1\n" +
+				"up{instance=\"a\"} 1\n" +
+				"up{instance=\"b\"} 0\n",
+			expected: map[string]float64{
+				"up":                 1,
+				"up{instance=\"a\"}": 1,
+				"up{instance=\"b\"}": 0,
+			},
+		},
+		{
+			name:   "prometheus invalid value",
+			format: FormatPrometheus,
+			data:   "up x\n",
+			errMsg: "invalid prometheus value in line \"up x\"",
+		},
+		{
+			name:   "prometheus missing value",
+			format: FormatPrometheus,
+			data:   "up{instance=\"a\"}\n",
+			errMsg: "invalid prometheus line \"up{instance=\\\"a\\\"}\"",
+		},
+		{
+			name:   "prometheus invalid labels",
+			format: FormatPrometheus,
+			data:   "up{instance=a} 1\n",
+			errMsg: "invalid prometheus labels in line \"up{instance=a} 1\"",
+		},
+		{
+			name:   "invalid format",
+			format: Format("json"),
+			data:   "{}",
+			errMsg: "invalid scrape format json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := Parse(tt.format, []byte(tt.data))
+			if tt.errMsg != "" {
+				assert.EqualError(t, err, tt.errMsg)
+				assert.Nil(t, values)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, values)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scrape
+
+import (
+	"fmt"
+	"github.com/wstool/wst/run/metrics"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sample contains values scraped at the offset from the start of scraping.
+type sample struct {
+	offset time.Duration
+	values map[string]float64
+}
+
+// Metrics contains scraped samples. The plain metric lookup uses the last scraped value and the windowed lookup
+// aggregates values of all samples in the window.
+type Metrics struct {
+	mu      sync.RWMutex
+	start   time.Time
+	samples []sample
+}
+
+func NewMetrics(start time.Time) *Metrics {
+	return &Metrics{start: start}
+}
+
+// Add adds the sample scraped at the time.
+func (m *Metrics) Add(at time.Time, values map[string]float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.samples = append(m.samples, sample{offset: at.Sub(m.start), values: values})
+}
+
+func (m *Metrics) Find(name string) (metrics.Metric, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for i := len(m.samples) - 1; i >= 0; i-- {
+		if value, ok := m.samples[i].values[name]; ok {
+			return metrics.GenericMetric[float64]{Value: value}, nil
+		}
+	}
+	return nil, fmt.Errorf("metric %s not found", name)
+}
+
+func (m *Metrics) FindWindowed(
+	name string,
+	window metrics.Window,
+	aggregation metrics.Aggregation,
+) (metrics.Metric, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var values []float64
+	for _, s := range m.samples {
+		if s.offset < window.From || (window.To != 0 && s.offset >= window.To) {
+			continue
+		}
+		if value, ok := s.values[name]; ok {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no data for metric %s in time window", name)
+	}
+	value, err := metrics.Aggregate(aggregation, values)
+	if err != nil {
+		return nil, err
+	}
+	return metrics.GenericMetric[float64]{Value: value}, nil
+}
+
+// Values returns the last scraped value of each metric.
+func (m *Metrics) Values() map[string]interface{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	values := make(map[string]interface{})
+	for _, s := range m.samples {
+		for name, value := range s.values {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+func (m *Metrics) String() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.samples) == 0 {
+		return "{}"
+	}
+	last := m.samples[len(m.samples)-1].values
+	names := make([]string, 0, len(last))
+	for name := range last {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %v", name, last[name]))
+	}
+	return fmt.Sprintf("{Samples: %d, %s}", len(m.samples), strings.Join(parts, ", "))
+}
@@ -0,0 +1,121 @@
+package scrape
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wstool/wst/run/metrics"
+	"testing"
+	"time"
+)
+
+func testMetrics() *Metrics {
+	start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	m := NewMetrics(start)
+	m.Add(start, map[string]float64{"requests": 1, "active": 4})
+	m.Add(start.Add(time.Second), map[string]float64{"requests": 5})
+	m.Add(start.Add(2*time.Second), map[string]float64{"requests": 9, "active": 2})
+	return m
+}
+
+func TestMetrics_Find(t *testing.T) {
+	tests := []struct {
+		name     string
+		metric   string
+		expected metrics.Metric
+		errMsg   string
+	}{
+		{
+			name:     "last sample value",
+			metric:   "requests",
+			expected: metrics.GenericMetric[float64]{Value: 9},
+		},
+		{
+			name:     "last sample containing the metric",
+			metric:   "active",
+			expected: metrics.GenericMetric[float64]{Value: 2},
+		},
+		{
+			name:   "metric not found",
+			metric: "missing",
+			errMsg: "metric missing not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric, err := testMetrics().Find(tt.metric)
+			if tt.errMsg != "" {
+				assert.EqualError(t, err, tt.errMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, metric)
+			}
+		})
+	}
+}
+
+func TestMetrics_FindWindowed(t *testing.T) {
+	tests := []struct {
+		name        string
+		metric      string
+		window      metrics.Window
+		aggregation metrics.Aggregation
+		expected    metrics.Metric
+		errMsg      string
+	}{
+		{
+			name:        "max in whole run",
+			metric:      "requests",
+			aggregation: metrics.AggregationMax,
+			expected:    metrics.GenericMetric[float64]{Value: 9},
+		},
+		{
+			name:        "avg in window",
+			metric:      "requests",
+			window:      metrics.Window{From: time.Second, To: 3 * time.Second},
+			aggregation: metrics.AggregationAvg,
+			expected:    metrics.GenericMetric[float64]{Value: 7},
+		},
+		{
+			name:        "min skipping samples without metric",
+			metric:      "active",
+			aggregation: metrics.AggregationMin,
+			expected:    metrics.GenericMetric[float64]{Value: 2},
+		},
+		{
+			name:        "window end is exclusive",
+			metric:      "requests",
+			window:      metrics.Window{To: time.Second},
+			aggregation: metrics.AggregationLast,
+			expected:    metrics.GenericMetric[float64]{Value: 1},
+		},
+		{
+			name:        "no data in window",
+			metric:      "active",
+			window:      metrics.Window{From: time.Second, To: 2 * time.Second},
+			aggregation: metrics.AggregationMax,
+			errMsg:      "no data for metric active in time window",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric, err := testMetrics().FindWindowed(tt.metric, tt.window, tt.aggregation)
+			if tt.errMsg != "" {
+				assert.EqualError(t, err, tt.errMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, metric)
+			}
+		})
+	}
+}
+
+func TestMetrics_Values(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{}, NewMetrics(time.Now()).Values())
+	assert.Equal(t, map[string]interface{}{"requests": 9.0, "active": 2.0}, testMetrics().Values())
+}
+
+func TestMetrics_String(t *testing.T) {
+	assert.Equal(t, "{}", NewMetrics(time.Now()).String())
+	assert.Equal(t, "{Samples: 3, active: 2, requests: 9}", testMetrics().String())
+}
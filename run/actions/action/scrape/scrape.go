@@ -0,0 +1,213 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scrape
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/services"
+	"io"
+	"net/http"
+	"time"
+)
+
+type Maker interface {
+	Make(
+		config *types.ScrapeAction,
+		sl services.ServiceLocator,
+		defaultTimeout int,
+		actionMaker action.Maker,
+	) (action.Action, error)
+}
+
+type ActionMaker struct {
+	fnd          app.Foundation
+	runtimeMaker runtime.Maker
+}
+
+func CreateActionMaker(fnd app.Foundation, runtimeMaker runtime.Maker) *ActionMaker {
+	return &ActionMaker{
+		fnd:          fnd,
+		runtimeMaker: runtimeMaker,
+	}
+}
+
+func (m *ActionMaker) Make(
+	config *types.ScrapeAction,
+	sl services.ServiceLocator,
+	defaultTimeout int,
+	actionMaker action.Maker,
+) (action.Action, error) {
+	svc, err := sl.Find(config.Service)
+	if err != nil {
+		return nil, err
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	var actions action.Action
+	if len(config.Actions) > 0 {
+		if config.Interval <= 0 {
+			return nil, errors.New("scrape action interval must be positive")
+		}
+		// The scraping runs in background while the inner actions are executed as a single sequential action.
+		actions, err = actionMaker.MakeAction(&types.SequentialAction{
+			Actions:   config.Actions,
+			Timeout:   config.Timeout,
+			When:      string(action.Always),
+			OnFailure: string(action.Fail),
+		}, sl, config.Timeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Action{
+		fnd:          m.fnd,
+		runtimeMaker: m.runtimeMaker,
+		service:      svc,
+		id:           config.Id,
+		scheme:       config.Scheme,
+		path:         config.Path,
+		headers:      config.Headers,
+		format:       Format(config.Format),
+		interval:     time.Duration(config.Interval) * time.Millisecond,
+		actions:      actions,
+		timeout:      time.Duration(config.Timeout) * time.Millisecond,
+		when:         action.When(config.When),
+		onFailure:    action.OnFailureType(config.OnFailure),
+	}, nil
+}
+
+type Action struct {
+	fnd          app.Foundation
+	runtimeMaker runtime.Maker
+	service      services.Service
+	id           string
+	scheme       string
+	path         string
+	headers      types.Headers
+	format       Format
+	interval     time.Duration
+	actions      action.Action
+	timeout      time.Duration
+	when         action.When
+	onFailure    action.OnFailureType
+}
+
+func (a *Action) When() action.When {
+	return a.when
+}
+
+func (a *Action) OnFailure() action.OnFailureType {
+	return a.onFailure
+}
+
+func (a *Action) Timeout() time.Duration {
+	return a.timeout
+}
+
+// Execute scrapes the metrics once or, if there are inner actions, periodically in background until the inner
+// actions finish.
+func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error) {
+	logger := a.fnd.Logger()
+	logger.Infof("Executing scrape action")
+
+	url, err := a.service.PublicUrl(a.scheme, a.path)
+	if err != nil {
+		return false, err
+	}
+	client := a.fnd.HttpClient(&http.Transport{})
+	metricsData := a.loadMetrics(runData)
+	if err = a.scrape(ctx, client, url, metricsData); err != nil {
+		return false, err
+	}
+	key := fmt.Sprintf("metrics/%s", a.id)
+	logger.Debugf("Storing metrics %s: %s", key, metricsData)
+	if err = runData.Store(key, metricsData); err != nil {
+		return false, err
+	}
+	if a.actions == nil {
+		return true, nil
+	}
+
+	scrapeCtx, cancelScrape := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for a.fnd.Sleep(scrapeCtx, a.interval) == nil {
+			if err := a.scrape(scrapeCtx, client, url, metricsData); err != nil && scrapeCtx.Err() == nil {
+				logger.Warnf("Scraping metrics from %s failed: %v", url, err)
+			}
+		}
+	}()
+
+	actCtx, cancel := a.runtimeMaker.MakeContextWithTimeout(ctx, a.actions.Timeout())
+	success, err := a.actions.Execute(actCtx, runData)
+	cancel()
+	cancelScrape()
+	<-done
+
+	logger.Debugf("Scraped metrics %s: %s", key, metricsData)
+	return success, err
+}
+
+// loadMetrics returns already stored scraped metrics with the same id so the samples are appended, or new metrics.
+func (a *Action) loadMetrics(runData runtime.Data) *Metrics {
+	if data, ok := runData.Load(fmt.Sprintf("metrics/%s", a.id)); ok {
+		if metricsData, ok := data.(*Metrics); ok {
+			return metricsData
+		}
+	}
+	return NewMetrics(time.Now())
+}
+
+func (a *Action) scrape(ctx context.Context, client app.HttpClient, url string, metricsData *Metrics) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for name, value := range a.headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("scraping metrics from %s failed with status %d", url, resp.StatusCode)
+	}
+	if a.fnd.DryRun() {
+		return nil
+	}
+
+	values, err := Parse(a.format, body)
+	if err != nil {
+		return err
+	}
+	metricsData.Add(time.Now(), values)
+	return nil
+}
@@ -0,0 +1,378 @@
+package scrape
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	actionMocks "github.com/wstool/wst/mocks/generated/run/actions/action"
+	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/metrics"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCreateActionMaker(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	runtimeMock := runtimeMocks.NewMockMaker(t)
+	tests := []struct {
+		name        string
+		fnd         app.Foundation
+		runtimeMock runtime.Maker
+	}{
+		{
+			name:        "create maker",
+			fnd:         fndMock,
+			runtimeMock: runtimeMock,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CreateActionMaker(tt.fnd, tt.runtimeMock)
+			assert.Equal(t, tt.fnd, got.fnd)
+			assert.Equal(t, tt.runtimeMock, got.runtimeMaker)
+		})
+	}
+}
+
+func TestActionMaker_Make(t *testing.T) {
+	actions := []types.Action{
+		&types.RequestAction{Service: "svc", Path: "/"},
+	}
+	tests := []struct {
+		name              string
+		config            *types.ScrapeAction
+		defaultTimeout    int
+		findErr           error
+		actionsTimeout    int
+		actionMakerErr    error
+		expectedTimeout   time.Duration
+		expectedInterval  time.Duration
+		expectedFormat    Format
+		expectedWhen      action.When
+		expectedOnFailure action.OnFailureType
+		expectActions     bool
+		expectedErrorMsg  string
+	}{
+		{
+			name: "successful one-shot scrape action creation",
+			config: &types.ScrapeAction{
+				Service:   "svc",
+				Id:        "status",
+				Scheme:    "http",
+				Path:      "/status",
+				Format:    "fpm",
+				Interval:  1000,
+				When:      "on_success",
+				OnFailure: "fail",
+			},
+			defaultTimeout:    5000,
+			expectedTimeout:   5000 * time.Millisecond,
+			expectedInterval:  1000 * time.Millisecond,
+			expectedFormat:    FormatFpm,
+			expectedWhen:      action.OnSuccess,
+			expectedOnFailure: action.Fail,
+		},
+		{
+			name: "successful periodic scrape action creation",
+			config: &types.ScrapeAction{
+				Service:   "svc",
+				Id:        "status",
+				Format:    "prometheus",
+				Interval:  200,
+				Timeout:   3000,
+				Actions:   actions,
+				When:      "always",
+				OnFailure: "skip",
+			},
+			defaultTimeout:    5000,
+			actionsTimeout:    3000,
+			expectedTimeout:   3000 * time.Millisecond,
+			expectedInterval:  200 * time.Millisecond,
+			expectedFormat:    FormatPrometheus,
+			expectedWhen:      action.Always,
+			expectedOnFailure: action.Skip,
+			expectActions:     true,
+		},
+		{
+			name: "failed scrape action creation due to service not found",
+			config: &types.ScrapeAction{
+				Service: "invalid",
+			},
+			defaultTimeout:   5000,
+			findErr:          errors.New("service not found"),
+			expectedErrorMsg: "service not found",
+		},
+		{
+			name: "failed periodic scrape action creation due to invalid interval",
+			config: &types.ScrapeAction{
+				Service: "svc",
+				Actions: actions,
+			},
+			defaultTimeout:   5000,
+			expectedErrorMsg: "scrape action interval must be positive",
+		},
+		{
+			name: "failed periodic scrape action creation due to action maker error",
+			config: &types.ScrapeAction{
+				Service:  "svc",
+				Interval: 1000,
+				Actions:  actions,
+			},
+			defaultTimeout:   5000,
+			actionsTimeout:   5000,
+			actionMakerErr:   errors.New("action creation failed"),
+			expectedErrorMsg: "action creation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			runtimeMakerMock := runtimeMocks.NewMockMaker(t)
+			m := &ActionMaker{
+				fnd:          fndMock,
+				runtimeMaker: runtimeMakerMock,
+			}
+
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			svcMock := servicesMocks.NewMockService(t)
+			amMock := actionMocks.NewMockMaker(t)
+			actionsMock := actionMocks.NewMockAction(t)
+
+			if tt.findErr != nil {
+				slMock.On("Find", tt.config.Service).Return(nil, tt.findErr)
+			} else {
+				slMock.On("Find", tt.config.Service).Return(svcMock, nil)
+			}
+			if tt.actionsTimeout > 0 {
+				sequentialConfig := &types.SequentialAction{
+					Actions:   actions,
+					Timeout:   tt.actionsTimeout,
+					When:      "always",
+					OnFailure: "fail",
+				}
+				if tt.actionMakerErr != nil {
+					amMock.On("MakeAction", sequentialConfig, slMock, tt.actionsTimeout).Return(nil, tt.actionMakerErr)
+				} else {
+					amMock.On("MakeAction", sequentialConfig, slMock, tt.actionsTimeout).Return(actionsMock, nil)
+				}
+			}
+
+			got, err := m.Make(tt.config, slMock, tt.defaultTimeout, amMock)
+
+			if tt.expectedErrorMsg != "" {
+				assert.EqualError(t, err, tt.expectedErrorMsg)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				act, ok := got.(*Action)
+				assert.True(t, ok)
+				assert.Equal(t, fndMock, act.fnd)
+				assert.Equal(t, runtimeMakerMock, act.runtimeMaker)
+				assert.Equal(t, svcMock, act.service)
+				assert.Equal(t, tt.config.Id, act.id)
+				assert.Equal(t, tt.config.Scheme, act.scheme)
+				assert.Equal(t, tt.config.Path, act.path)
+				assert.Equal(t, tt.expectedFormat, act.format)
+				assert.Equal(t, tt.expectedInterval, act.interval)
+				assert.Equal(t, tt.expectedTimeout, act.Timeout())
+				assert.Equal(t, tt.expectedWhen, act.When())
+				assert.Equal(t, tt.expectedOnFailure, act.OnFailure())
+				if tt.expectActions {
+					assert.Equal(t, actionsMock, act.actions)
+				} else {
+					assert.Nil(t, act.actions)
+				}
+			}
+		})
+	}
+}
+
+func TestAction_Execute(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           int
+		periodic         bool
+		actionsResult    bool
+		actionsErr       error
+		dryRun           bool
+		existingMetrics  bool
+		storeErr         error
+		expectedSamples  int
+		expectedValue    float64
+		want             bool
+		expectedErrorMsg string
+	}{
+		{
+			name:            "successful one-shot scrape",
+			status:          http.StatusOK,
+			expectedSamples: 1,
+			expectedValue:   1,
+			want:            true,
+		},
+		{
+			name:            "successful one-shot scrape appending to existing metrics",
+			status:          http.StatusOK,
+			existingMetrics: true,
+			expectedSamples: 2,
+			expectedValue:   1,
+			want:            true,
+		},
+		{
+			name:            "successful periodic scrape",
+			status:          http.StatusOK,
+			periodic:        true,
+			actionsResult:   true,
+			existingMetrics: true, expectedSamples: 3,
+			expectedValue: 2,
+			want:          true,
+		},
+		{
+			name:            "periodic scrape with failed actions",
+			status:          http.StatusOK,
+			periodic:        true,
+			actionsResult:   false,
+			actionsErr:      errors.New("request failed"),
+			existingMetrics: true, expectedSamples: 3,
+			expectedValue:    2,
+			want:             false,
+			expectedErrorMsg: "request failed",
+		},
+		{
+			name:            "dry run scrape",
+			status:          http.StatusOK,
+			dryRun:          true,
+			expectedSamples: 0,
+			want:            true,
+		},
+		{
+			name:             "failed scrape due to status",
+			status:           http.StatusNotFound,
+			want:             false,
+			expectedErrorMsg: "scraping metrics from %s/metrics failed with status 404",
+		},
+		{
+			name:             "failed scrape due to store error",
+			status:           http.StatusOK,
+			storeErr:         errors.New("store failed"),
+			want:             false,
+			expectedErrorMsg: "store failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/metrics", r.URL.Path)
+				assert.Equal(t, "token", r.Header.Get("Authorization"))
+				count := hits.Add(1)
+				w.WriteHeader(tt.status)
+				_, _ = fmt.Fprintf(w, "requests_total{code=\"200\"} %d\n", count)
+			}))
+			defer server.Close()
+
+			fndMock := appMocks.NewMockFoundation(t)
+			runMakerMock := runtimeMocks.NewMockMaker(t)
+			runDataMock := runtimeMocks.NewMockData(t)
+			svcMock := servicesMocks.NewMockService(t)
+			actionsMock := actionMocks.NewMockAction(t)
+
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+			fndMock.On("HttpClient", mock.Anything).Return(func(tr *http.Transport) app.HttpClient {
+				return app.NewRealHttpClient(tr)
+			})
+			if tt.status == http.StatusOK {
+				fndMock.On("DryRun").Return(tt.dryRun)
+			}
+			svcMock.On("PublicUrl", "http", "/metrics").Return(server.URL+"/metrics", nil)
+
+			ctx := context.Background()
+			metricsData := NewMetrics(time.Now())
+			if tt.existingMetrics {
+				metricsData.Add(time.Now(), map[string]float64{"requests_total": 0})
+				runDataMock.On("Load", "metrics/status").Return(metricsData, true)
+			} else {
+				runDataMock.On("Load", "metrics/status").Return(nil, false)
+			}
+			if tt.status == http.StatusOK {
+				runDataMock.On("Store", "metrics/status", mock.MatchedBy(func(m *Metrics) bool {
+					if tt.existingMetrics {
+						return m == metricsData
+					}
+					metricsData = m
+					return true
+				})).Return(tt.storeErr)
+			}
+
+			a := &Action{
+				fnd:          fndMock,
+				runtimeMaker: runMakerMock,
+				service:      svcMock,
+				id:           "status",
+				scheme:       "http",
+				path:         "/metrics",
+				headers:      types.Headers{"Authorization": "token"},
+				format:       FormatPrometheus,
+				interval:     100 * time.Millisecond,
+			}
+			if tt.periodic {
+				a.actions = actionsMock
+				var sleeps atomic.Int32
+				fndMock.On("Sleep", mock.Anything, 100*time.Millisecond).Return(
+					func(ctx context.Context, d time.Duration) error {
+						if sleeps.Add(1) == 1 {
+							return nil
+						}
+						<-ctx.Done()
+						return ctx.Err()
+					},
+				)
+				actCtx, actCancel := context.WithTimeout(ctx, 3*time.Second)
+				defer actCancel()
+				actionsMock.On("Timeout").Return(3 * time.Second)
+				runMakerMock.On("MakeContextWithTimeout", ctx, 3*time.Second).Return(actCtx, actCancel)
+				actionsMock.On("Execute", actCtx, runDataMock).Run(func(args mock.Arguments) {
+					// Wait for the initial and the background scrape.
+					assert.Eventually(t, func() bool {
+						metricsData.mu.RLock()
+						defer metricsData.mu.RUnlock()
+						return len(metricsData.samples) == tt.expectedSamples
+					}, time.Second, time.Millisecond)
+				}).Return(tt.actionsResult, tt.actionsErr)
+			}
+
+			got, err := a.Execute(ctx, runDataMock)
+
+			assert.Equal(t, tt.want, got)
+			if tt.expectedErrorMsg != "" {
+				expectedErrorMsg := tt.expectedErrorMsg
+				if tt.status != http.StatusOK {
+					expectedErrorMsg = fmt.Sprintf(expectedErrorMsg, server.URL)
+				}
+				assert.EqualError(t, err, expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, metricsData.samples, tt.expectedSamples)
+				if tt.expectedSamples > 0 {
+					metric, err := metricsData.Find("requests_total")
+					assert.NoError(t, err)
+					assert.Equal(t, metrics.GenericMetric[float64]{Value: tt.expectedValue}, metric)
+				}
+			}
+		})
+	}
+}
@@ -114,6 +114,8 @@ func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error
 	logger := a.fnd.Logger()
 	logger.Infof("Executing sequential action")
 
+	// The sequence keeps its own action start cursor so it is not affected by other sequences.
+	seqData := runData.WithActionSequence()
 	failedActionsCount := 0
 	var lastErr error = nil
 	for pos, act := range a.actions {
@@ -124,9 +126,10 @@ func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error
 			(failedActionsCount > 0 && when == action.OnFailure) {
 			actTimeout := act.Timeout()
 			logger.Debugf("Executing sequential action %d with timeout %s", pos, actTimeout)
+			seqData.MarkActionStart(time.Now())
 			// Create context for action
 			actCtx, cancel := a.runtimeMaker.MakeContextWithTimeout(ctx, actTimeout)
-			success, err := act.Execute(actCtx, runData)
+			success, err := act.Execute(actCtx, seqData)
 			cancel() // Cancel the context immediately after action completion
 
 			if err != nil {
@@ -16,6 +16,7 @@ import (
 	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
 	"github.com/wstool/wst/run/actions/action"
 	"github.com/wstool/wst/run/instances/runtime"
+	"sync"
 	"testing"
 	"time"
 )
@@ -374,6 +375,8 @@ func TestAction_Execute(t *testing.T) {
 			mockLogger := external.NewMockLogger()
 			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
 
+			runDataMock.On("WithActionSequence").Return(runDataMock)
+			runDataMock.On("MarkActionStart", mock.Anything).Maybe()
 			tt.setupMocks(t, fndMock, actionMocks, runDataMock, actCtx)
 
 			actions := []action.Action{actionMocks[0], actionMocks[1], actionMocks[2]}
@@ -406,6 +409,110 @@ func TestAction_Execute(t *testing.T) {
 	}
 }
 
+func TestAction_Execute_actionStart(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	mockLogger := external.NewMockLogger()
+	fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+	fndMock.On("DryRun").Return(false)
+	runMakerMock := runtimeMocks.NewMockMaker(t)
+	runData := runtime.CreateMaker(fndMock).MakeData()
+	ctx := context.Background()
+	timeout := 3 * time.Second
+	runMakerMock.On("MakeContextWithTimeout", ctx, timeout).Return(ctx, context.CancelFunc(func() {}))
+
+	var starts []time.Time
+	first := actionMocks.NewMockAction(t)
+	first.On("When").Return(action.OnSuccess)
+	first.On("Timeout").Return(timeout)
+	first.On("Execute", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		start, ok := args.Get(1).(runtime.Data).PreviousActionStart()
+		assert.True(t, ok)
+		starts = append(starts, start)
+	}).Return(true, nil)
+	second := actionMocks.NewMockAction(t)
+	second.On("When").Return(action.OnSuccess)
+	second.On("Timeout").Return(timeout)
+	second.On("Execute", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		start, ok := args.Get(1).(runtime.Data).PreviousActionStart()
+		assert.True(t, ok)
+		starts = append(starts, start)
+	}).Return(true, nil)
+
+	a := &Action{
+		fnd:          fndMock,
+		runtimeMaker: runMakerMock,
+		actions:      []action.Action{first, second},
+	}
+
+	got, err := a.Execute(ctx, runData)
+
+	assert.NoError(t, err)
+	assert.True(t, got)
+	// The second action reads the start of the first action as its previous action start.
+	assert.Len(t, starts, 2)
+	assert.Equal(t, starts[0], starts[1])
+	// The sequence cursor does not change the cursor of the parent scope.
+	_, found := runData.PreviousActionStart()
+	assert.False(t, found)
+}
+
+func TestAction_Execute_parallelActionStart(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	mockLogger := external.NewMockLogger()
+	fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+	fndMock.On("DryRun").Return(false)
+	runMakerMock := runtimeMocks.NewMockMaker(t)
+	runData := runtime.CreateMaker(fndMock).MakeData()
+	ctx := context.Background()
+	timeout := 3 * time.Second
+	runMakerMock.On("MakeContextWithTimeout", ctx, timeout).Return(ctx, context.CancelFunc(func() {}))
+
+	// The first actions of both sequences are started before the second actions so the sequences interleave.
+	var firstStarted sync.WaitGroup
+	firstStarted.Add(2)
+	makeSequence := func(starts []time.Time) *Action {
+		first := actionMocks.NewMockAction(t)
+		first.On("When").Return(action.OnSuccess)
+		first.On("Timeout").Return(timeout)
+		first.On("Execute", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			starts[0], _ = args.Get(1).(runtime.Data).PreviousActionStart()
+			firstStarted.Done()
+			firstStarted.Wait()
+		}).Return(true, nil)
+		second := actionMocks.NewMockAction(t)
+		second.On("When").Return(action.OnSuccess)
+		second.On("Timeout").Return(timeout)
+		second.On("Execute", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			starts[1], _ = args.Get(1).(runtime.Data).PreviousActionStart()
+		}).Return(true, nil)
+		return &Action{
+			fnd:          fndMock,
+			runtimeMaker: runMakerMock,
+			actions:      []action.Action{first, second},
+		}
+	}
+	branchStarts := [][]time.Time{make([]time.Time, 2), make([]time.Time, 2)}
+	sequences := []*Action{makeSequence(branchStarts[0]), makeSequence(branchStarts[1])}
+
+	var wg sync.WaitGroup
+	for _, sequence := range sequences {
+		wg.Add(1)
+		go func(sequence *Action) {
+			defer wg.Done()
+			got, err := sequence.Execute(ctx, runData)
+			assert.NoError(t, err)
+			assert.True(t, got)
+		}(sequence)
+	}
+	wg.Wait()
+
+	// Each second action reads the start of the first action of its own sequence.
+	for _, starts := range branchStarts {
+		assert.False(t, starts[0].IsZero())
+		assert.Equal(t, starts[0], starts[1])
+	}
+}
+
 func TestAction_Timeout(t *testing.T) {
 	fndMock := appMocks.NewMockFoundation(t)
 	a := &Action{
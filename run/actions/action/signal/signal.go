@@ -0,0 +1,235 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signal
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/environments/environment"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/services"
+	"os"
+	"syscall"
+	"time"
+)
+
+type Maker interface {
+	Make(
+		config *types.SignalAction,
+		sl services.ServiceLocator,
+		defaultTimeout int,
+	) (action.Action, error)
+}
+
+type ActionMaker struct {
+	fnd app.Foundation
+}
+
+func CreateActionMaker(fnd app.Foundation) *ActionMaker {
+	return &ActionMaker{
+		fnd: fnd,
+	}
+}
+
+type Expectation string
+
+const (
+	AnyExpectation     Expectation = "any"
+	ExitExpectation    Expectation = "exit"
+	SurviveExpectation Expectation = "survive"
+)
+
+var signals = map[string]syscall.Signal{
+	"SIGTERM": syscall.SIGTERM,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+}
+
+func (m *ActionMaker) Make(
+	config *types.SignalAction,
+	sl services.ServiceLocator,
+	defaultTimeout int,
+) (action.Action, error) {
+	svc, err := sl.Find(config.Service)
+	if err != nil {
+		return nil, errors.Errorf("signal action service not found: %v", err)
+	}
+
+	sig, ok := signals[config.Signal]
+	if !ok {
+		return nil, errors.Errorf("signal action has unsupported signal %s", config.Signal)
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	return &Action{
+		fnd:        m.fnd,
+		service:    svc,
+		signal:     sig,
+		signalName: config.Signal,
+		process:    config.Process,
+		expect:     Expectation(config.Expect),
+		wait:       time.Duration(config.Wait) * time.Millisecond,
+		interval:   time.Duration(config.Interval) * time.Millisecond,
+		timeout:    time.Duration(config.Timeout * 1e6),
+		when:       action.When(config.When),
+		onFailure:  action.OnFailureType(config.OnFailure),
+	}, nil
+}
+
+type Action struct {
+	fnd        app.Foundation
+	service    services.Service
+	signal     os.Signal
+	signalName string
+	process    string
+	expect     Expectation
+	wait       time.Duration
+	interval   time.Duration
+	timeout    time.Duration
+	when       action.When
+	onFailure  action.OnFailureType
+}
+
+func (a *Action) When() action.When {
+	return a.when
+}
+
+func (a *Action) OnFailure() action.OnFailureType {
+	return a.onFailure
+}
+
+func (a *Action) Timeout() time.Duration {
+	return a.timeout
+}
+
+// findProcesses returns the processes with the configured name that are descendants of the service task.
+func (a *Action) findProcesses(ctx context.Context) ([]environment.Process, error) {
+	processes, err := a.service.Processes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var found []environment.Process
+	// The main process is the service task itself so only its descendants are matched.
+	for _, process := range processes {
+		if !process.Main && process.Name == a.process {
+			found = append(found, process)
+		}
+	}
+	return found, nil
+}
+
+// alive checks whether the signaled service or any of the signaled processes is still running.
+func (a *Action) alive(ctx context.Context, processes []environment.Process) (bool, error) {
+	if a.process == "" {
+		return a.service.IsRunning(ctx)
+	}
+	current, err := a.findProcesses(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, process := range processes {
+		for _, currentProcess := range current {
+			// Pids are unique only within the instance (e.g. pod) of the task.
+			if process.Pid == currentProcess.Pid && process.Instance == currentProcess.Instance {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (a *Action) awaitExit(ctx context.Context, processes []environment.Process) (bool, error) {
+	logger := a.fnd.Logger()
+	for waited := time.Duration(0); ; waited += a.interval {
+		alive, err := a.alive(ctx, processes)
+		if err != nil {
+			return false, err
+		}
+		if !alive {
+			return true, nil
+		}
+		if waited >= a.wait {
+			logger.Errorf("Signaled target of service %s did not exit within %s", a.service.Name(), a.wait)
+			return false, nil
+		}
+		if err = a.fnd.Sleep(ctx, a.interval); err != nil {
+			logger.Errorf("Waiting for exit of signaled target of service %s interrupted: %v", a.service.Name(), err)
+			return false, nil
+		}
+	}
+}
+
+func (a *Action) checkSurvival(ctx context.Context, processes []environment.Process) (bool, error) {
+	logger := a.fnd.Logger()
+	if err := a.fnd.Sleep(ctx, a.wait); err != nil {
+		logger.Errorf("Waiting for survival of signaled target of service %s interrupted: %v", a.service.Name(), err)
+		return false, nil
+	}
+	alive, err := a.alive(ctx, processes)
+	if err != nil {
+		return false, err
+	}
+	if !alive {
+		logger.Errorf("Signaled target of service %s did not survive", a.service.Name())
+	}
+	return alive, nil
+}
+
+func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error) {
+	logger := a.fnd.Logger()
+	logger.Infof("Executing signal action sending %s to service %s", a.signalName, a.service.Name())
+	if a.fnd.DryRun() {
+		return true, nil
+	}
+
+	var processes []environment.Process
+	if a.process == "" {
+		if err := a.service.Signal(ctx, a.signal); err != nil {
+			return false, err
+		}
+	} else {
+		var err error
+		processes, err = a.findProcesses(ctx)
+		if err != nil {
+			return false, err
+		}
+		if len(processes) == 0 {
+			return false, errors.Errorf("no process %s found in service %s", a.process, a.service.Name())
+		}
+		logger.Debugf("Sending %s to process %s with pids %v", a.signalName, a.process, environment.ProcessPids(processes))
+		if err = a.service.SignalProcesses(ctx, processes, a.signal); err != nil {
+			return false, err
+		}
+	}
+
+	switch a.expect {
+	case ExitExpectation:
+		return a.awaitExit(ctx, processes)
+	case SurviveExpectation:
+		return a.checkSurvival(ctx, processes)
+	default:
+		return true, nil
+	}
+}
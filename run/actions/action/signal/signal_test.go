@@ -0,0 +1,471 @@
+package signal
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/environments/environment"
+	"github.com/wstool/wst/run/services"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCreateActionMaker(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	tests := []struct {
+		name string
+		fnd  app.Foundation
+	}{
+		{
+			name: "create maker",
+			fnd:  fndMock,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CreateActionMaker(tt.fnd)
+			assert.Equal(t, tt.fnd, got.fnd)
+		})
+	}
+}
+
+func TestActionMaker_Make(t *testing.T) {
+	tests := []struct {
+		name              string
+		config            *types.SignalAction
+		defaultTimeout    int
+		setupMocks        func(*testing.T, *servicesMocks.MockServiceLocator) services.Service
+		expectedSignal    os.Signal
+		expectedProcess   string
+		expectedExpect    Expectation
+		expectedWait      time.Duration
+		expectedInterval  time.Duration
+		expectedTimeout   time.Duration
+		expectedWhen      action.When
+		expectedOnFailure action.OnFailureType
+		expectError       bool
+		expectedErrorMsg  string
+	}{
+		{
+			name: "successful service signal action creation",
+			config: &types.SignalAction{
+				Service:   "svc",
+				Signal:    "SIGTERM",
+				Expect:    "exit",
+				Wait:      2000,
+				Interval:  100,
+				When:      "on_success",
+				OnFailure: "fail",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "svc").Return(svc, nil)
+				return svc
+			},
+			expectedSignal:    syscall.SIGTERM,
+			expectedExpect:    ExitExpectation,
+			expectedWait:      2000 * time.Millisecond,
+			expectedInterval:  100 * time.Millisecond,
+			expectedTimeout:   5000 * time.Millisecond,
+			expectedWhen:      action.OnSuccess,
+			expectedOnFailure: action.Fail,
+		},
+		{
+			name: "successful child process signal action creation",
+			config: &types.SignalAction{
+				Service:   "svc",
+				Signal:    "SIGUSR1",
+				Process:   "php-fpm",
+				Expect:    "survive",
+				Wait:      500,
+				Interval:  50,
+				Timeout:   3000,
+				When:      "always",
+				OnFailure: "ignore",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "svc").Return(svc, nil)
+				return svc
+			},
+			expectedSignal:    syscall.SIGUSR1,
+			expectedProcess:   "php-fpm",
+			expectedExpect:    SurviveExpectation,
+			expectedWait:      500 * time.Millisecond,
+			expectedInterval:  50 * time.Millisecond,
+			expectedTimeout:   3000 * time.Millisecond,
+			expectedWhen:      action.Always,
+			expectedOnFailure: action.Ignore,
+		},
+		{
+			name: "failed action creation due to service not found",
+			config: &types.SignalAction{
+				Service: "svc",
+				Signal:  "SIGTERM",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				sl.On("Find", "svc").Return(nil, errors.New("not found"))
+				return nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "signal action service not found: not found",
+		},
+		{
+			name: "failed action creation due to unsupported signal",
+			config: &types.SignalAction{
+				Service: "svc",
+				Signal:  "SIGSTOP",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "svc").Return(svc, nil)
+				return svc
+			},
+			expectError:      true,
+			expectedErrorMsg: "signal action has unsupported signal SIGSTOP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			m := &ActionMaker{
+				fnd: fndMock,
+			}
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			svc := tt.setupMocks(t, slMock)
+
+			got, err := m.Make(tt.config, slMock, tt.defaultTimeout)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				act, ok := got.(*Action)
+				assert.True(t, ok)
+				assert.Equal(t, fndMock, act.fnd)
+				assert.Equal(t, svc, act.service)
+				assert.Equal(t, tt.expectedSignal, act.signal)
+				assert.Equal(t, tt.config.Signal, act.signalName)
+				assert.Equal(t, tt.expectedProcess, act.process)
+				assert.Equal(t, tt.expectedExpect, act.expect)
+				assert.Equal(t, tt.expectedWait, act.wait)
+				assert.Equal(t, tt.expectedInterval, act.interval)
+				assert.Equal(t, tt.expectedTimeout, act.Timeout())
+				assert.Equal(t, tt.expectedWhen, act.When())
+				assert.Equal(t, tt.expectedOnFailure, act.OnFailure())
+			}
+		})
+	}
+}
+
+func TestAction_Execute(t *testing.T) {
+	processes := []environment.Process{
+		{Pid: 1, PPid: 0, Name: "php-fpm", Main: true},
+		{Pid: 7, PPid: 1, Name: "php-fpm"},
+		{Pid: 8, PPid: 1, Name: "php-fpm"},
+		{Pid: 9, PPid: 1, Name: "sh"},
+	}
+	signaled := processes[1:3]
+	podProcesses := []environment.Process{
+		{Pid: 1, PPid: 0, Name: "php-fpm", Instance: "p1", Main: true},
+		{Pid: 7, PPid: 1, Name: "php-fpm", Instance: "p1"},
+		{Pid: 1, PPid: 0, Name: "php-fpm", Instance: "p2", Main: true},
+		{Pid: 7, PPid: 1, Name: "php-fpm", Instance: "p2"},
+	}
+	tests := []struct {
+		name        string
+		process     string
+		expect      Expectation
+		setupMocks  func(*testing.T, context.Context, *appMocks.MockFoundation, *servicesMocks.MockService)
+		want        bool
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:   "service signaled without expectation",
+			expect: AnyExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Signal", ctx, syscall.SIGUSR1).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name:   "service signal error",
+			expect: AnyExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Signal", ctx, syscall.SIGUSR1).Return(errors.New("signal failed"))
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "signal failed",
+		},
+		{
+			name:   "service exited after retry",
+			expect: ExitExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Signal", ctx, syscall.SIGUSR1).Return(nil)
+				svc.On("IsRunning", ctx).Return(true, nil).Once()
+				fnd.On("Sleep", ctx, 100*time.Millisecond).Return(nil).Once()
+				svc.On("IsRunning", ctx).Return(false, nil).Once()
+			},
+			want: true,
+		},
+		{
+			name:   "service did not exit within wait",
+			expect: ExitExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Signal", ctx, syscall.SIGUSR1).Return(nil)
+				svc.On("IsRunning", ctx).Return(true, nil).Times(3)
+				fnd.On("Sleep", ctx, 100*time.Millisecond).Return(nil).Twice()
+			},
+			want: false,
+		},
+		{
+			name:   "service exit wait interrupted",
+			expect: ExitExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Signal", ctx, syscall.SIGUSR1).Return(nil)
+				svc.On("IsRunning", ctx).Return(true, nil).Once()
+				fnd.On("Sleep", ctx, 100*time.Millisecond).Return(context.DeadlineExceeded).Once()
+			},
+			want: false,
+		},
+		{
+			name:   "service running check error",
+			expect: ExitExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Signal", ctx, syscall.SIGUSR1).Return(nil)
+				svc.On("IsRunning", ctx).Return(false, errors.New("check failed"))
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "check failed",
+		},
+		{
+			name:   "service survived",
+			expect: SurviveExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Signal", ctx, syscall.SIGUSR1).Return(nil)
+				fnd.On("Sleep", ctx, 200*time.Millisecond).Return(nil)
+				svc.On("IsRunning", ctx).Return(true, nil)
+			},
+			want: true,
+		},
+		{
+			name:   "service did not survive",
+			expect: SurviveExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Signal", ctx, syscall.SIGUSR1).Return(nil)
+				fnd.On("Sleep", ctx, 200*time.Millisecond).Return(nil)
+				svc.On("IsRunning", ctx).Return(false, nil)
+			},
+			want: false,
+		},
+		{
+			name:   "service survival wait interrupted",
+			expect: SurviveExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Signal", ctx, syscall.SIGUSR1).Return(nil)
+				fnd.On("Sleep", ctx, 200*time.Millisecond).Return(context.DeadlineExceeded)
+			},
+			want: false,
+		},
+		{
+			name:    "child processes signaled and exited",
+			process: "php-fpm",
+			expect:  ExitExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Processes", ctx).Return(processes, nil).Once()
+				svc.On("SignalProcesses", ctx, signaled, syscall.SIGUSR1).Return(nil)
+				svc.On("Processes", ctx).Return(processes[:1], nil).Once()
+			},
+			want: true,
+		},
+		{
+			name:    "child processes signaled and one survived",
+			process: "php-fpm",
+			expect:  SurviveExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Processes", ctx).Return(processes, nil).Once()
+				svc.On("SignalProcesses", ctx, signaled, syscall.SIGUSR1).Return(nil)
+				fnd.On("Sleep", ctx, 200*time.Millisecond).Return(nil)
+				svc.On("Processes", ctx).Return(processes[:2], nil).Once()
+			},
+			want: true,
+		},
+		{
+			name:    "child processes signaled and none survived",
+			process: "php-fpm",
+			expect:  SurviveExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Processes", ctx).Return(processes, nil).Once()
+				svc.On("SignalProcesses", ctx, signaled, syscall.SIGUSR1).Return(nil)
+				fnd.On("Sleep", ctx, 200*time.Millisecond).Return(nil)
+				svc.On("Processes", ctx).Return(processes[:1], nil).Once()
+			},
+			want: false,
+		},
+		{
+			name:    "child processes in multiple pods signaled and exited",
+			process: "php-fpm",
+			expect:  ExitExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Processes", ctx).Return(podProcesses, nil).Once()
+				svc.On("SignalProcesses", ctx, []environment.Process{podProcesses[1], podProcesses[3]}, syscall.SIGUSR1).
+					Return(nil)
+				svc.On("Processes", ctx).Return([]environment.Process{podProcesses[0], podProcesses[2]}, nil).Once()
+			},
+			want: true,
+		},
+		{
+			name:    "child processes in multiple pods signaled and one pod process survived",
+			process: "php-fpm",
+			expect:  SurviveExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Processes", ctx).Return(podProcesses, nil).Once()
+				svc.On("SignalProcesses", ctx, []environment.Process{podProcesses[1], podProcesses[3]}, syscall.SIGUSR1).
+					Return(nil)
+				fnd.On("Sleep", ctx, 200*time.Millisecond).Return(nil)
+				svc.On("Processes", ctx).Return(podProcesses[:3], nil).Once()
+			},
+			want: true,
+		},
+		{
+			name:    "child process not found",
+			process: "nginx",
+			expect:  AnyExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Processes", ctx).Return(processes, nil)
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "no process nginx found in service svc",
+		},
+		{
+			name:    "child processes listing error",
+			process: "php-fpm",
+			expect:  AnyExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Processes", ctx).Return(nil, errors.New("list failed"))
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "list failed",
+		},
+		{
+			name:    "child processes kill error",
+			process: "php-fpm",
+			expect:  AnyExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Processes", ctx).Return(processes, nil)
+				svc.On("SignalProcesses", ctx, signaled, syscall.SIGUSR1).Return(errors.New("kill failed"))
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "kill failed",
+		},
+		{
+			name:   "dry run",
+			expect: ExitExpectation,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(true)
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			svcMock := servicesMocks.NewMockService(t)
+			runDataMock := runtimeMocks.NewMockData(t)
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+			svcMock.On("Name").Return("svc").Maybe()
+			ctx := context.Background()
+
+			tt.setupMocks(t, ctx, fndMock, svcMock)
+
+			a := &Action{
+				fnd:        fndMock,
+				service:    svcMock,
+				signal:     syscall.SIGUSR1,
+				signalName: "SIGUSR1",
+				process:    tt.process,
+				expect:     tt.expect,
+				wait:       200 * time.Millisecond,
+				interval:   100 * time.Millisecond,
+			}
+
+			got, err := a.Execute(ctx, runDataMock)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAction_Timeout(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:     fndMock,
+		timeout: 2000 * time.Millisecond,
+	}
+	assert.Equal(t, 2000*time.Millisecond, a.Timeout())
+}
+
+func TestAction_OnFailure(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:       fndMock,
+		onFailure: action.Skip,
+	}
+	assert.Equal(t, action.Skip, a.OnFailure())
+}
+
+func TestAction_When(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:  fndMock,
+		when: action.OnSuccess,
+	}
+	assert.Equal(t, action.OnSuccess, a.When())
+}
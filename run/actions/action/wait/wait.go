@@ -0,0 +1,211 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/instances/runtime"
+	"github.com/wstool/wst/run/services"
+	"path/filepath"
+	"time"
+)
+
+type Maker interface {
+	Make(
+		config *types.WaitAction,
+		sl services.ServiceLocator,
+		defaultTimeout int,
+	) (action.Action, error)
+}
+
+type ActionMaker struct {
+	fnd app.Foundation
+}
+
+func CreateActionMaker(fnd app.Foundation) *ActionMaker {
+	return &ActionMaker{
+		fnd: fnd,
+	}
+}
+
+type Condition string
+
+const (
+	DurationCondition Condition = "duration"
+	PortCondition     Condition = "port"
+	SocketCondition   Condition = "socket"
+	FileCondition     Condition = "file"
+	ExitCondition     Condition = "exit"
+)
+
+func configCondition(config *types.WaitAction) (Condition, error) {
+	var conditions []Condition
+	if config.Duration > 0 {
+		conditions = append(conditions, DurationCondition)
+	}
+	if config.Port > 0 {
+		conditions = append(conditions, PortCondition)
+	}
+	if config.Socket != "" {
+		conditions = append(conditions, SocketCondition)
+	}
+	if config.File != "" {
+		conditions = append(conditions, FileCondition)
+	}
+	if config.Exit {
+		conditions = append(conditions, ExitCondition)
+	}
+	if len(conditions) != 1 {
+		return "", errors.New("wait action requires exactly one of duration, port, socket, file or exit to be set")
+	}
+	return conditions[0], nil
+}
+
+// servicePath resolves the path relative to the service run directory unless it is absolute.
+func servicePath(svc services.Service, path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+	runDir, err := svc.RunDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(runDir, path), nil
+}
+
+func (m *ActionMaker) Make(
+	config *types.WaitAction,
+	sl services.ServiceLocator,
+	defaultTimeout int,
+) (action.Action, error) {
+	condition, err := configCondition(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var svc services.Service
+	if config.Service != "" {
+		svc, err = sl.Find(config.Service)
+		if err != nil {
+			return nil, errors.Errorf("wait action service not found: %v", err)
+		}
+	} else if condition != DurationCondition {
+		return nil, errors.Errorf("wait action requires service for %s condition", condition)
+	}
+
+	var path string
+	switch condition {
+	case SocketCondition:
+		path, err = servicePath(svc, config.Socket)
+	case FileCondition:
+		path, err = servicePath(svc, config.File)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	return &Action{
+		fnd:       m.fnd,
+		service:   svc,
+		condition: condition,
+		duration:  time.Duration(config.Duration) * time.Millisecond,
+		port:      config.Port,
+		path:      path,
+		interval:  time.Duration(config.Interval) * time.Millisecond,
+		timeout:   time.Duration(config.Timeout * 1e6),
+		when:      action.When(config.When),
+		onFailure: action.OnFailureType(config.OnFailure),
+	}, nil
+}
+
+type Action struct {
+	fnd       app.Foundation
+	service   services.Service
+	condition Condition
+	duration  time.Duration
+	port      int32
+	path      string
+	interval  time.Duration
+	timeout   time.Duration
+	when      action.When
+	onFailure action.OnFailureType
+}
+
+func (a *Action) When() action.When {
+	return a.when
+}
+
+func (a *Action) OnFailure() action.OnFailureType {
+	return a.onFailure
+}
+
+func (a *Action) Timeout() time.Duration {
+	return a.timeout
+}
+
+func (a *Action) check(ctx context.Context) (bool, error) {
+	switch a.condition {
+	case PortCondition:
+		return a.service.PortReady(ctx, a.port)
+	case SocketCondition:
+		return a.service.UdsReady(ctx, a.path)
+	case FileCondition:
+		return a.service.FileExists(ctx, a.path)
+	case ExitCondition:
+		running, err := a.service.IsRunning(ctx)
+		return !running, err
+	default:
+		return false, errors.Errorf("unsupported wait condition %s", a.condition)
+	}
+}
+
+func (a *Action) Execute(ctx context.Context, runData runtime.Data) (bool, error) {
+	logger := a.fnd.Logger()
+	logger.Infof("Executing wait action for %s condition", a.condition)
+	if a.fnd.DryRun() {
+		return true, nil
+	}
+
+	if a.condition == DurationCondition {
+		if err := a.fnd.Sleep(ctx, a.duration); err != nil {
+			logger.Errorf("Wait action interrupted before duration %s elapsed: %v", a.duration, err)
+			return false, nil
+		}
+		return true, nil
+	}
+
+	for {
+		ready, err := a.check(ctx)
+		if err != nil {
+			return false, err
+		}
+		if ready {
+			logger.Debugf("Wait action %s condition for service %s met", a.condition, a.service.Name())
+			return true, nil
+		}
+		if err = a.fnd.Sleep(ctx, a.interval); err != nil {
+			logger.Errorf("Wait action %s condition for service %s not met: %v", a.condition, a.service.Name(), err)
+			return false, nil
+		}
+	}
+}
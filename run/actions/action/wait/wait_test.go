@@ -0,0 +1,424 @@
+package wait
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
+	servicesMocks "github.com/wstool/wst/mocks/generated/run/services"
+	"github.com/wstool/wst/run/actions/action"
+	"github.com/wstool/wst/run/services"
+	"testing"
+	"time"
+)
+
+func TestCreateActionMaker(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	tests := []struct {
+		name string
+		fnd  app.Foundation
+	}{
+		{
+			name: "create maker",
+			fnd:  fndMock,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CreateActionMaker(tt.fnd)
+			assert.Equal(t, tt.fnd, got.fnd)
+		})
+	}
+}
+
+func TestActionMaker_Make(t *testing.T) {
+	tests := []struct {
+		name              string
+		config            *types.WaitAction
+		defaultTimeout    int
+		setupMocks        func(*testing.T, *servicesMocks.MockServiceLocator) services.Service
+		expectedCondition Condition
+		expectedDuration  time.Duration
+		expectedPort      int32
+		expectedPath      string
+		expectedInterval  time.Duration
+		expectedTimeout   time.Duration
+		expectedWhen      action.When
+		expectedOnFailure action.OnFailureType
+		expectError       bool
+		expectedErrorMsg  string
+	}{
+		{
+			name: "successful duration action creation without service",
+			config: &types.WaitAction{
+				Duration:  1500,
+				Interval:  100,
+				When:      "on_success",
+				OnFailure: "fail",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				return nil
+			},
+			expectedCondition: DurationCondition,
+			expectedDuration:  1500 * time.Millisecond,
+			expectedInterval:  100 * time.Millisecond,
+			expectedTimeout:   5000 * time.Millisecond,
+			expectedWhen:      action.OnSuccess,
+			expectedOnFailure: action.Fail,
+		},
+		{
+			name: "successful port action creation",
+			config: &types.WaitAction{
+				Service:   "svc",
+				Port:      9000,
+				Interval:  200,
+				Timeout:   3000,
+				When:      "always",
+				OnFailure: "ignore",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "svc").Return(svc, nil)
+				return svc
+			},
+			expectedCondition: PortCondition,
+			expectedPort:      9000,
+			expectedInterval:  200 * time.Millisecond,
+			expectedTimeout:   3000 * time.Millisecond,
+			expectedWhen:      action.Always,
+			expectedOnFailure: action.Ignore,
+		},
+		{
+			name: "successful socket action creation with relative path",
+			config: &types.WaitAction{
+				Service:   "svc",
+				Socket:    "fpm.sock",
+				Interval:  100,
+				When:      "on_success",
+				OnFailure: "fail",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				svc.On("RunDir").Return("/var/run/svc", nil)
+				sl.On("Find", "svc").Return(svc, nil)
+				return svc
+			},
+			expectedCondition: SocketCondition,
+			expectedPath:      "/var/run/svc/fpm.sock",
+			expectedInterval:  100 * time.Millisecond,
+			expectedTimeout:   5000 * time.Millisecond,
+			expectedWhen:      action.OnSuccess,
+			expectedOnFailure: action.Fail,
+		},
+		{
+			name: "successful file action creation with absolute path",
+			config: &types.WaitAction{
+				Service:   "svc",
+				File:      "/var/log/slow.log",
+				Interval:  100,
+				When:      "on_success",
+				OnFailure: "fail",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "svc").Return(svc, nil)
+				return svc
+			},
+			expectedCondition: FileCondition,
+			expectedPath:      "/var/log/slow.log",
+			expectedInterval:  100 * time.Millisecond,
+			expectedTimeout:   5000 * time.Millisecond,
+			expectedWhen:      action.OnSuccess,
+			expectedOnFailure: action.Fail,
+		},
+		{
+			name: "successful exit action creation",
+			config: &types.WaitAction{
+				Service:   "svc",
+				Exit:      true,
+				Interval:  100,
+				When:      "on_success",
+				OnFailure: "fail",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				sl.On("Find", "svc").Return(svc, nil)
+				return svc
+			},
+			expectedCondition: ExitCondition,
+			expectedInterval:  100 * time.Millisecond,
+			expectedTimeout:   5000 * time.Millisecond,
+			expectedWhen:      action.OnSuccess,
+			expectedOnFailure: action.Fail,
+		},
+		{
+			name: "failed action creation due to run dir error",
+			config: &types.WaitAction{
+				Service: "svc",
+				File:    "slow.log",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				svc := servicesMocks.NewMockService(t)
+				svc.On("RunDir").Return("", errors.New("run dir not set"))
+				sl.On("Find", "svc").Return(svc, nil)
+				return svc
+			},
+			expectError:      true,
+			expectedErrorMsg: "run dir not set",
+		},
+		{
+			name: "failed action creation due to service not found",
+			config: &types.WaitAction{
+				Service: "svc",
+				Exit:    true,
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				sl.On("Find", "svc").Return(nil, errors.New("not found"))
+				return nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "wait action service not found: not found",
+		},
+		{
+			name: "failed action creation due to missing service",
+			config: &types.WaitAction{
+				Port: 9000,
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				return nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "wait action requires service for port condition",
+		},
+		{
+			name: "failed action creation due to no condition",
+			config: &types.WaitAction{
+				Service: "svc",
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				return nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "wait action requires exactly one of duration, port, socket, file or exit to be set",
+		},
+		{
+			name: "failed action creation due to multiple conditions",
+			config: &types.WaitAction{
+				Service: "svc",
+				Port:    9000,
+				Exit:    true,
+			},
+			defaultTimeout: 5000,
+			setupMocks: func(t *testing.T, sl *servicesMocks.MockServiceLocator) services.Service {
+				return nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "wait action requires exactly one of duration, port, socket, file or exit to be set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			m := &ActionMaker{
+				fnd: fndMock,
+			}
+			slMock := servicesMocks.NewMockServiceLocator(t)
+			svc := tt.setupMocks(t, slMock)
+
+			got, err := m.Make(tt.config, slMock, tt.defaultTimeout)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				act, ok := got.(*Action)
+				assert.True(t, ok)
+				assert.Equal(t, fndMock, act.fnd)
+				assert.Equal(t, svc, act.service)
+				assert.Equal(t, tt.expectedCondition, act.condition)
+				assert.Equal(t, tt.expectedDuration, act.duration)
+				assert.Equal(t, tt.expectedPort, act.port)
+				assert.Equal(t, tt.expectedPath, act.path)
+				assert.Equal(t, tt.expectedInterval, act.interval)
+				assert.Equal(t, tt.expectedTimeout, act.Timeout())
+				assert.Equal(t, tt.expectedWhen, act.When())
+				assert.Equal(t, tt.expectedOnFailure, act.OnFailure())
+			}
+		})
+	}
+}
+
+func TestAction_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		condition   Condition
+		port        int32
+		path        string
+		setupMocks  func(*testing.T, context.Context, *appMocks.MockFoundation, *servicesMocks.MockService)
+		want        bool
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:      "duration elapsed",
+			condition: DurationCondition,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				fnd.On("Sleep", ctx, 500*time.Millisecond).Return(nil)
+			},
+			want: true,
+		},
+		{
+			name:      "duration interrupted",
+			condition: DurationCondition,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				fnd.On("Sleep", ctx, 500*time.Millisecond).Return(context.DeadlineExceeded)
+			},
+			want: false,
+		},
+		{
+			name:      "port ready after retry",
+			condition: PortCondition,
+			port:      9000,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Name").Return("svc")
+				svc.On("PortReady", ctx, int32(9000)).Return(false, nil).Once()
+				fnd.On("Sleep", ctx, 100*time.Millisecond).Return(nil).Once()
+				svc.On("PortReady", ctx, int32(9000)).Return(true, nil).Once()
+			},
+			want: true,
+		},
+		{
+			name:      "socket never ready",
+			condition: SocketCondition,
+			path:      "/run/fpm.sock",
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Name").Return("svc")
+				svc.On("UdsReady", ctx, "/run/fpm.sock").Return(false, nil).Twice()
+				fnd.On("Sleep", ctx, 100*time.Millisecond).Return(nil).Once()
+				fnd.On("Sleep", ctx, 100*time.Millisecond).Return(context.DeadlineExceeded).Once()
+			},
+			want: false,
+		},
+		{
+			name:      "file exists",
+			condition: FileCondition,
+			path:      "/var/log/slow.log",
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Name").Return("svc")
+				svc.On("FileExists", ctx, "/var/log/slow.log").Return(true, nil)
+			},
+			want: true,
+		},
+		{
+			name:      "file check error",
+			condition: FileCondition,
+			path:      "/var/log/slow.log",
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("FileExists", ctx, "/var/log/slow.log").Return(false, errors.New("check failed"))
+			},
+			want:        false,
+			expectError: true,
+			errorMsg:    "check failed",
+		},
+		{
+			name:      "task exited",
+			condition: ExitCondition,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(false)
+				svc.On("Name").Return("svc")
+				svc.On("IsRunning", ctx).Return(false, nil)
+			},
+			want: true,
+		},
+		{
+			name:      "dry run",
+			condition: ExitCondition,
+			setupMocks: func(t *testing.T, ctx context.Context, fnd *appMocks.MockFoundation, svc *servicesMocks.MockService) {
+				fnd.On("DryRun").Return(true)
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			svcMock := servicesMocks.NewMockService(t)
+			runDataMock := runtimeMocks.NewMockData(t)
+			mockLogger := external.NewMockLogger()
+			fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+			ctx := context.Background()
+
+			tt.setupMocks(t, ctx, fndMock, svcMock)
+
+			a := &Action{
+				fnd:       fndMock,
+				service:   svcMock,
+				condition: tt.condition,
+				duration:  500 * time.Millisecond,
+				port:      tt.port,
+				path:      tt.path,
+				interval:  100 * time.Millisecond,
+			}
+
+			got, err := a.Execute(ctx, runDataMock)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAction_Timeout(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:     fndMock,
+		timeout: 2000 * time.Millisecond,
+	}
+	assert.Equal(t, 2000*time.Millisecond, a.Timeout())
+}
+
+func TestAction_OnFailure(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:       fndMock,
+		onFailure: action.Skip,
+	}
+	assert.Equal(t, action.Skip, a.OnFailure())
+}
+
+func TestAction_When(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	a := &Action{
+		fnd:  fndMock,
+		when: action.OnSuccess,
+	}
+	assert.Equal(t, action.OnSuccess, a.When())
+}
@@ -20,16 +20,24 @@ import (
 	"github.com/wstool/wst/conf/types"
 	"github.com/wstool/wst/run/actions/action"
 	"github.com/wstool/wst/run/actions/action/bench"
+	"github.com/wstool/wst/run/actions/action/conditional"
+	"github.com/wstool/wst/run/actions/action/eventually"
 	"github.com/wstool/wst/run/actions/action/execute"
 	"github.com/wstool/wst/run/actions/action/expect"
+	"github.com/wstool/wst/run/actions/action/faultproxy"
+	"github.com/wstool/wst/run/actions/action/foreach"
 	"github.com/wstool/wst/run/actions/action/not"
 	"github.com/wstool/wst/run/actions/action/parallel"
 	"github.com/wstool/wst/run/actions/action/reload"
+	"github.com/wstool/wst/run/actions/action/repeat"
 	"github.com/wstool/wst/run/actions/action/request"
 	"github.com/wstool/wst/run/actions/action/restart"
+	"github.com/wstool/wst/run/actions/action/scrape"
 	"github.com/wstool/wst/run/actions/action/sequential"
+	"github.com/wstool/wst/run/actions/action/signal"
 	"github.com/wstool/wst/run/actions/action/start"
 	"github.com/wstool/wst/run/actions/action/stop"
+	"github.com/wstool/wst/run/actions/action/wait"
 	"github.com/wstool/wst/run/expectations"
 	"github.com/wstool/wst/run/instances/runtime"
 	"github.com/wstool/wst/run/parameters"
@@ -44,16 +52,24 @@ type nativeActionMaker struct {
 	fnd             app.Foundation
 	runtimeMaker    runtime.Maker
 	benchMaker      bench.Maker
+	ifMaker         conditional.Maker
+	eventuallyMaker eventually.Maker
 	executeMaker    execute.Maker
 	expectMaker     expect.Maker
+	faultProxyMaker faultproxy.Maker
+	foreachMaker    foreach.Maker
 	notMaker        not.Maker
 	parallelMaker   parallel.Maker
 	requestMaker    request.Maker
 	reloadMaker     reload.Maker
+	repeatMaker     repeat.Maker
 	restartMaker    restart.Maker
+	scrapeMaker     scrape.Maker
 	sequentialMaker sequential.Maker
+	signalMaker     signal.Maker
 	startMaker      start.Maker
 	stopMaker       stop.Maker
+	waitMaker       wait.Maker
 }
 
 func CreateActionMaker(
@@ -66,16 +82,24 @@ func CreateActionMaker(
 		fnd:             fnd,
 		runtimeMaker:    runtimeMaker,
 		benchMaker:      bench.CreateActionMaker(fnd),
+		ifMaker:         conditional.CreateActionMaker(fnd, runtimeMaker),
+		eventuallyMaker: eventually.CreateActionMaker(fnd, runtimeMaker),
 		executeMaker:    execute.CreateActionMaker(fnd),
-		expectMaker:     expect.CreateExpectationActionMaker(fnd, expectationsMaker, parametersMaker),
+		expectMaker:     expect.CreateExpectationActionMaker(fnd, expectationsMaker, parametersMaker, runtimeMaker),
+		faultProxyMaker: faultproxy.CreateActionMaker(fnd),
+		foreachMaker:    foreach.CreateActionMaker(fnd, parametersMaker, runtimeMaker),
 		notMaker:        not.CreateActionMaker(fnd, runtimeMaker),
 		parallelMaker:   parallel.CreateActionMaker(fnd, runtimeMaker),
 		requestMaker:    request.CreateActionMaker(fnd),
 		reloadMaker:     reload.CreateActionMaker(fnd),
+		repeatMaker:     repeat.CreateActionMaker(fnd, parametersMaker, runtimeMaker),
 		restartMaker:    restart.CreateActionMaker(fnd),
+		scrapeMaker:     scrape.CreateActionMaker(fnd, runtimeMaker),
 		sequentialMaker: sequential.CreateActionMaker(fnd, runtimeMaker),
+		signalMaker:     signal.CreateActionMaker(fnd),
 		startMaker:      start.CreateActionMaker(fnd),
 		stopMaker:       stop.CreateActionMaker(fnd),
+		waitMaker:       wait.CreateActionMaker(fnd),
 	}
 }
 
@@ -87,6 +111,8 @@ func (m *nativeActionMaker) MakeAction(
 	switch action := config.(type) {
 	case *types.BenchAction:
 		return m.benchMaker.Make(action, sl, defaultTimeout)
+	case *types.EventuallyAction:
+		return m.eventuallyMaker.Make(action, sl, defaultTimeout, m)
 	case *types.ExecuteAction:
 		return m.executeMaker.Make(action, sl, defaultTimeout)
 	case *types.CustomExpectationAction:
@@ -94,9 +120,23 @@ func (m *nativeActionMaker) MakeAction(
 	case *types.MetricsExpectationAction:
 		return m.expectMaker.MakeMetricsAction(action, sl, defaultTimeout)
 	case *types.OutputExpectationAction:
-		return m.expectMaker.MakeOutputAction(action, sl, defaultTimeout)
+		return m.expectMaker.MakeOutputAction(action, sl, defaultTimeout, m)
+	case *types.ReceivedExpectationAction:
+		return m.expectMaker.MakeReceivedAction(action, sl, defaultTimeout)
 	case *types.ResponseExpectationAction:
 		return m.expectMaker.MakeResponseAction(action, sl, defaultTimeout)
+	case *types.SnapshotExpectationAction:
+		return m.expectMaker.MakeSnapshotAction(action, sl, defaultTimeout)
+	case *types.ProcessExpectationAction:
+		return m.expectMaker.MakeProcessAction(action, sl, defaultTimeout)
+	case *types.FileExpectationAction:
+		return m.expectMaker.MakeFileAction(action, sl, defaultTimeout)
+	case *types.FaultProxyAction:
+		return m.faultProxyMaker.Make(action, sl, defaultTimeout)
+	case *types.ForeachAction:
+		return m.foreachMaker.Make(action, sl, defaultTimeout, m)
+	case *types.IfAction:
+		return m.ifMaker.Make(action, sl, defaultTimeout, m)
 	case *types.NotAction:
 		return m.notMaker.Make(action, sl, defaultTimeout, m)
 	case *types.ParallelAction:
@@ -105,14 +145,22 @@ func (m *nativeActionMaker) MakeAction(
 		return m.requestMaker.Make(action, sl, defaultTimeout)
 	case *types.ReloadAction:
 		return m.reloadMaker.Make(action, sl, defaultTimeout)
+	case *types.RepeatAction:
+		return m.repeatMaker.Make(action, sl, defaultTimeout, m)
 	case *types.RestartAction:
 		return m.restartMaker.Make(action, sl, defaultTimeout)
+	case *types.ScrapeAction:
+		return m.scrapeMaker.Make(action, sl, defaultTimeout, m)
 	case *types.SequentialAction:
 		return m.sequentialMaker.Make(action, sl, defaultTimeout, m)
+	case *types.SignalAction:
+		return m.signalMaker.Make(action, sl, defaultTimeout)
 	case *types.StartAction:
 		return m.startMaker.Make(action, sl, defaultTimeout)
 	case *types.StopAction:
 		return m.stopMaker.Make(action, sl, defaultTimeout)
+	case *types.WaitAction:
+		return m.waitMaker.Make(action, sl, defaultTimeout)
 	default:
 		return nil, errors.Errorf("unsupported action type: %T", config)
 	}
@@ -7,16 +7,24 @@ import (
 	appMocks "github.com/wstool/wst/mocks/generated/app"
 	actionMocks "github.com/wstool/wst/mocks/generated/run/actions/action"
 	benchMocks "github.com/wstool/wst/mocks/generated/run/actions/action/bench"
+	conditionalMocks "github.com/wstool/wst/mocks/generated/run/actions/action/conditional"
+	eventuallyMocks "github.com/wstool/wst/mocks/generated/run/actions/action/eventually"
 	executeMocks "github.com/wstool/wst/mocks/generated/run/actions/action/execute"
 	expectMocks "github.com/wstool/wst/mocks/generated/run/actions/action/expect"
+	faultProxyMocks "github.com/wstool/wst/mocks/generated/run/actions/action/faultproxy"
+	foreachMocks "github.com/wstool/wst/mocks/generated/run/actions/action/foreach"
 	notMocks "github.com/wstool/wst/mocks/generated/run/actions/action/not"
 	parallelMocks "github.com/wstool/wst/mocks/generated/run/actions/action/parallel"
 	reloadMocks "github.com/wstool/wst/mocks/generated/run/actions/action/reload"
+	repeatMocks "github.com/wstool/wst/mocks/generated/run/actions/action/repeat"
 	requestMocks "github.com/wstool/wst/mocks/generated/run/actions/action/request"
 	restartMocks "github.com/wstool/wst/mocks/generated/run/actions/action/restart"
+	scrapeMocks "github.com/wstool/wst/mocks/generated/run/actions/action/scrape"
 	sequentialMocks "github.com/wstool/wst/mocks/generated/run/actions/action/sequential"
+	signalMocks "github.com/wstool/wst/mocks/generated/run/actions/action/signal"
 	startMocks "github.com/wstool/wst/mocks/generated/run/actions/action/start"
 	stopMocks "github.com/wstool/wst/mocks/generated/run/actions/action/stop"
+	waitMocks "github.com/wstool/wst/mocks/generated/run/actions/action/wait"
 	expectationsMocks "github.com/wstool/wst/mocks/generated/run/expectations"
 	runtimeMocks "github.com/wstool/wst/mocks/generated/run/instances/runtime"
 	parametersMocks "github.com/wstool/wst/mocks/generated/run/parameters"
@@ -56,16 +64,24 @@ func TestCreateActionMaker(t *testing.T) {
 			assert.Equal(t, tt.fnd, m.fnd)
 			assert.Equal(t, tt.runtimeMaker, m.runtimeMaker)
 			assert.NotNil(t, m.benchMaker)
+			assert.NotNil(t, m.ifMaker)
+			assert.NotNil(t, m.eventuallyMaker)
 			assert.NotNil(t, m.executeMaker)
 			assert.NotNil(t, m.expectMaker)
+			assert.NotNil(t, m.faultProxyMaker)
+			assert.NotNil(t, m.foreachMaker)
 			assert.NotNil(t, m.notMaker)
 			assert.NotNil(t, m.parallelMaker)
 			assert.NotNil(t, m.requestMaker)
 			assert.NotNil(t, m.reloadMaker)
+			assert.NotNil(t, m.repeatMaker)
 			assert.NotNil(t, m.restartMaker)
+			assert.NotNil(t, m.scrapeMaker)
 			assert.NotNil(t, m.sequentialMaker)
+			assert.NotNil(t, m.signalMaker)
 			assert.NotNil(t, m.startMaker)
 			assert.NotNil(t, m.stopMaker)
+			assert.NotNil(t, m.waitMaker)
 		})
 	}
 }
@@ -81,16 +97,24 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 			action.Action,
 			*servicesMocks.MockServiceLocator,
 			*benchMocks.MockMaker,
+			*conditionalMocks.MockMaker,
+			*eventuallyMocks.MockMaker,
 			*executeMocks.MockMaker,
 			*expectMocks.MockMaker,
+			*faultProxyMocks.MockMaker,
+			*foreachMocks.MockMaker,
 			*notMocks.MockMaker,
 			*parallelMocks.MockMaker,
 			*requestMocks.MockMaker,
 			*reloadMocks.MockMaker,
+			*repeatMocks.MockMaker,
 			*restartMocks.MockMaker,
+			*scrapeMocks.MockMaker,
 			*sequentialMocks.MockMaker,
+			*signalMocks.MockMaker,
 			*startMocks.MockMaker,
 			*stopMocks.MockMaker,
+			*waitMocks.MockMaker,
 		)
 		expectError      bool
 		expectedErrorMsg string
@@ -105,16 +129,24 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				benchMaker.On("Make", &types.BenchAction{Service: "svc"}, sl, 5000).Return(a, nil)
 			},
@@ -129,16 +161,24 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				commandMaker.On("Make", &types.ExecuteAction{Service: "svc"}, sl, 5000).Return(a, nil)
 			},
@@ -153,16 +193,24 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				cfg := &types.CustomExpectationAction{Service: "svc"}
 				expectMaker.On("MakeCustomAction", cfg, sl, 5000).Return(a, nil)
@@ -178,16 +226,24 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				cfg := &types.MetricsExpectationAction{Service: "svc"}
 				expectMaker.On("MakeMetricsAction", cfg, sl, 5000).Return(a, nil)
@@ -203,19 +259,60 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				cfg := &types.OutputExpectationAction{Service: "svc"}
-				expectMaker.On("MakeOutputAction", cfg, sl, 5000).Return(a, nil)
+				expectMaker.On("MakeOutputAction", cfg, sl, 5000, m).Return(a, nil)
+			},
+		},
+		{
+			name:           "successful received expectation action creation",
+			config:         &types.ReceivedExpectationAction{Service: "svc"},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				m *nativeActionMaker,
+				a action.Action,
+				sl *servicesMocks.MockServiceLocator,
+				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
+				commandMaker *executeMocks.MockMaker,
+				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
+				notMaker *notMocks.MockMaker,
+				parallelMaker *parallelMocks.MockMaker,
+				requestMaker *requestMocks.MockMaker,
+				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
+				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
+				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
+				startMaker *startMocks.MockMaker,
+				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
+			) {
+				cfg := &types.ReceivedExpectationAction{Service: "svc"}
+				expectMaker.On("MakeReceivedAction", cfg, sl, 5000).Return(a, nil)
 			},
 		},
 		{
@@ -228,21 +325,161 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				cfg := &types.ResponseExpectationAction{Service: "svc"}
 				expectMaker.On("MakeResponseAction", cfg, sl, 5000).Return(a, nil)
 			},
 		},
+		{
+			name:           "successful snapshot expectation action creation",
+			config:         &types.SnapshotExpectationAction{Service: "svc"},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				m *nativeActionMaker,
+				a action.Action,
+				sl *servicesMocks.MockServiceLocator,
+				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
+				commandMaker *executeMocks.MockMaker,
+				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
+				notMaker *notMocks.MockMaker,
+				parallelMaker *parallelMocks.MockMaker,
+				requestMaker *requestMocks.MockMaker,
+				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
+				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
+				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
+				startMaker *startMocks.MockMaker,
+				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
+			) {
+				cfg := &types.SnapshotExpectationAction{Service: "svc"}
+				expectMaker.On("MakeSnapshotAction", cfg, sl, 5000).Return(a, nil)
+			},
+		},
+		{
+			name:           "successful process expectation action creation",
+			config:         &types.ProcessExpectationAction{Service: "svc"},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				m *nativeActionMaker,
+				a action.Action,
+				sl *servicesMocks.MockServiceLocator,
+				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
+				commandMaker *executeMocks.MockMaker,
+				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
+				notMaker *notMocks.MockMaker,
+				parallelMaker *parallelMocks.MockMaker,
+				requestMaker *requestMocks.MockMaker,
+				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
+				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
+				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
+				startMaker *startMocks.MockMaker,
+				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
+			) {
+				cfg := &types.ProcessExpectationAction{Service: "svc"}
+				expectMaker.On("MakeProcessAction", cfg, sl, 5000).Return(a, nil)
+			},
+		},
+		{
+			name:           "successful file expectation action creation",
+			config:         &types.FileExpectationAction{Service: "svc"},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				m *nativeActionMaker,
+				a action.Action,
+				sl *servicesMocks.MockServiceLocator,
+				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
+				commandMaker *executeMocks.MockMaker,
+				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
+				notMaker *notMocks.MockMaker,
+				parallelMaker *parallelMocks.MockMaker,
+				requestMaker *requestMocks.MockMaker,
+				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
+				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
+				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
+				startMaker *startMocks.MockMaker,
+				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
+			) {
+				cfg := &types.FileExpectationAction{Service: "svc"}
+				expectMaker.On("MakeFileAction", cfg, sl, 5000).Return(a, nil)
+			},
+		},
+		{
+			name:           "successful foreach action creation",
+			config:         &types.ForeachAction{Timeout: 2000},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				m *nativeActionMaker,
+				a action.Action,
+				sl *servicesMocks.MockServiceLocator,
+				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
+				commandMaker *executeMocks.MockMaker,
+				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
+				notMaker *notMocks.MockMaker,
+				parallelMaker *parallelMocks.MockMaker,
+				requestMaker *requestMocks.MockMaker,
+				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
+				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
+				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
+				startMaker *startMocks.MockMaker,
+				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
+			) {
+				cfg := &types.ForeachAction{Timeout: 2000}
+				foreachMaker.On("Make", cfg, sl, 5000, m).Return(a, nil)
+			},
+		},
 		{
 			name:           "successful not action creation",
 			config:         &types.NotAction{Timeout: 2000},
@@ -253,16 +490,24 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				cfg := &types.NotAction{Timeout: 2000}
 				notMaker.On("Make", cfg, sl, 5000, m).Return(a, nil)
@@ -278,16 +523,24 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				cfg := &types.ParallelAction{Timeout: 2000}
 				parallelMaker.On("Make", cfg, sl, 5000, m).Return(a, nil)
@@ -303,16 +556,24 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				cfg := &types.RequestAction{Timeout: 2000}
 				requestMaker.On("Make", cfg, sl, 5000).Return(a, nil)
@@ -328,21 +589,62 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				cfg := &types.ReloadAction{Timeout: 2000}
 				reloadMaker.On("Make", cfg, sl, 5000).Return(a, nil)
 			},
 		},
+		{
+			name:           "successful repeat action creation",
+			config:         &types.RepeatAction{Timeout: 2000},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				m *nativeActionMaker,
+				a action.Action,
+				sl *servicesMocks.MockServiceLocator,
+				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
+				commandMaker *executeMocks.MockMaker,
+				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
+				notMaker *notMocks.MockMaker,
+				parallelMaker *parallelMocks.MockMaker,
+				requestMaker *requestMocks.MockMaker,
+				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
+				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
+				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
+				startMaker *startMocks.MockMaker,
+				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
+			) {
+				cfg := &types.RepeatAction{Timeout: 2000}
+				repeatMaker.On("Make", cfg, sl, 5000, m).Return(a, nil)
+			},
+		},
 		{
 			name:           "successful restart action creation",
 			config:         &types.RestartAction{Timeout: 2000},
@@ -353,21 +655,62 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				cfg := &types.RestartAction{Timeout: 2000}
 				restartMaker.On("Make", cfg, sl, 5000).Return(a, nil)
 			},
 		},
+		{
+			name:           "successful scrape action creation",
+			config:         &types.ScrapeAction{Timeout: 2000},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				m *nativeActionMaker,
+				a action.Action,
+				sl *servicesMocks.MockServiceLocator,
+				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
+				commandMaker *executeMocks.MockMaker,
+				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
+				notMaker *notMocks.MockMaker,
+				parallelMaker *parallelMocks.MockMaker,
+				requestMaker *requestMocks.MockMaker,
+				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
+				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
+				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
+				startMaker *startMocks.MockMaker,
+				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
+			) {
+				cfg := &types.ScrapeAction{Timeout: 2000}
+				scrapeMaker.On("Make", cfg, sl, 5000, m).Return(a, nil)
+			},
+		},
 		{
 			name:           "successful sequential action creation",
 			config:         &types.SequentialAction{Timeout: 2000},
@@ -378,16 +721,24 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				cfg := &types.SequentialAction{Timeout: 2000}
 				sequentialMaker.On("Make", cfg, sl, 5000, m).Return(a, nil)
@@ -403,16 +754,24 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				cfg := &types.StartAction{Timeout: 2000}
 				startMaker.On("Make", cfg, sl, 5000).Return(a, nil)
@@ -428,21 +787,194 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 				cfg := &types.StopAction{Timeout: 2000}
 				stopMaker.On("Make", cfg, sl, 5000).Return(a, nil)
 			},
 		},
+		{
+			name:           "successful eventually action creation",
+			config:         &types.EventuallyAction{Timeout: 2000},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				m *nativeActionMaker,
+				a action.Action,
+				sl *servicesMocks.MockServiceLocator,
+				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
+				commandMaker *executeMocks.MockMaker,
+				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
+				notMaker *notMocks.MockMaker,
+				parallelMaker *parallelMocks.MockMaker,
+				requestMaker *requestMocks.MockMaker,
+				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
+				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
+				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
+				startMaker *startMocks.MockMaker,
+				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
+			) {
+				cfg := &types.EventuallyAction{Timeout: 2000}
+				eventuallyMaker.On("Make", cfg, sl, 5000, m).Return(a, nil)
+			},
+		},
+		{
+			name:           "successful if action creation",
+			config:         &types.IfAction{Timeout: 2000},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				m *nativeActionMaker,
+				a action.Action,
+				sl *servicesMocks.MockServiceLocator,
+				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
+				commandMaker *executeMocks.MockMaker,
+				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
+				notMaker *notMocks.MockMaker,
+				parallelMaker *parallelMocks.MockMaker,
+				requestMaker *requestMocks.MockMaker,
+				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
+				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
+				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
+				startMaker *startMocks.MockMaker,
+				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
+			) {
+				cfg := &types.IfAction{Timeout: 2000}
+				ifMaker.On("Make", cfg, sl, 5000, m).Return(a, nil)
+			},
+		},
+		{
+			name:           "successful wait action creation",
+			config:         &types.WaitAction{Timeout: 2000},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				m *nativeActionMaker,
+				a action.Action,
+				sl *servicesMocks.MockServiceLocator,
+				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
+				commandMaker *executeMocks.MockMaker,
+				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
+				notMaker *notMocks.MockMaker,
+				parallelMaker *parallelMocks.MockMaker,
+				requestMaker *requestMocks.MockMaker,
+				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
+				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
+				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
+				startMaker *startMocks.MockMaker,
+				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
+			) {
+				cfg := &types.WaitAction{Timeout: 2000}
+				waitMaker.On("Make", cfg, sl, 5000).Return(a, nil)
+			},
+		},
+		{
+			name:           "successful signal action creation",
+			config:         &types.SignalAction{Timeout: 2000},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				m *nativeActionMaker,
+				a action.Action,
+				sl *servicesMocks.MockServiceLocator,
+				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
+				commandMaker *executeMocks.MockMaker,
+				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
+				notMaker *notMocks.MockMaker,
+				parallelMaker *parallelMocks.MockMaker,
+				requestMaker *requestMocks.MockMaker,
+				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
+				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
+				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
+				startMaker *startMocks.MockMaker,
+				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
+			) {
+				cfg := &types.SignalAction{Timeout: 2000}
+				signalMaker.On("Make", cfg, sl, 5000).Return(a, nil)
+			},
+		},
+		{
+			name:           "successful faultproxy action creation",
+			config:         &types.FaultProxyAction{Timeout: 2000},
+			defaultTimeout: 5000,
+			setupMocks: func(
+				t *testing.T,
+				m *nativeActionMaker,
+				a action.Action,
+				sl *servicesMocks.MockServiceLocator,
+				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
+				commandMaker *executeMocks.MockMaker,
+				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
+				notMaker *notMocks.MockMaker,
+				parallelMaker *parallelMocks.MockMaker,
+				requestMaker *requestMocks.MockMaker,
+				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
+				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
+				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
+				startMaker *startMocks.MockMaker,
+				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
+			) {
+				cfg := &types.FaultProxyAction{Timeout: 2000}
+				faultProxyMaker.On("Make", cfg, sl, 5000).Return(a, nil)
+			},
+		},
 		{
 			name:           "failed action creation due to invalid config type",
 			config:         "test",
@@ -453,16 +985,24 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				a action.Action,
 				sl *servicesMocks.MockServiceLocator,
 				benchMaker *benchMocks.MockMaker,
+				ifMaker *conditionalMocks.MockMaker,
+				eventuallyMaker *eventuallyMocks.MockMaker,
 				commandMaker *executeMocks.MockMaker,
 				expectMaker *expectMocks.MockMaker,
+				faultProxyMaker *faultProxyMocks.MockMaker,
+				foreachMaker *foreachMocks.MockMaker,
 				notMaker *notMocks.MockMaker,
 				parallelMaker *parallelMocks.MockMaker,
 				requestMaker *requestMocks.MockMaker,
 				reloadMaker *reloadMocks.MockMaker,
+				repeatMaker *repeatMocks.MockMaker,
 				restartMaker *restartMocks.MockMaker,
+				scrapeMaker *scrapeMocks.MockMaker,
 				sequentialMaker *sequentialMocks.MockMaker,
+				signalMaker *signalMocks.MockMaker,
 				startMaker *startMocks.MockMaker,
 				stopMaker *stopMocks.MockMaker,
+				waitMaker *waitMocks.MockMaker,
 			) {
 			},
 			expectError:      true,
@@ -475,31 +1015,47 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 			fndMock := appMocks.NewMockFoundation(t)
 			slMock := servicesMocks.NewMockServiceLocator(t)
 			benchMakerMock := benchMocks.NewMockMaker(t)
+			ifMakerMock := conditionalMocks.NewMockMaker(t)
+			eventuallyMakerMock := eventuallyMocks.NewMockMaker(t)
 			commandMakerMock := executeMocks.NewMockMaker(t)
 			expectMakerMock := expectMocks.NewMockMaker(t)
+			faultProxyMakerMock := faultProxyMocks.NewMockMaker(t)
+			foreachMakerMock := foreachMocks.NewMockMaker(t)
 			notMakerMock := notMocks.NewMockMaker(t)
 			parallelMakerMock := parallelMocks.NewMockMaker(t)
 			requestMakerMock := requestMocks.NewMockMaker(t)
 			reloadMakerMock := reloadMocks.NewMockMaker(t)
+			repeatMakerMock := repeatMocks.NewMockMaker(t)
 			restartMakerMock := restartMocks.NewMockMaker(t)
+			scrapeMakerMock := scrapeMocks.NewMockMaker(t)
 			sequentialMakerMock := sequentialMocks.NewMockMaker(t)
+			signalMakerMock := signalMocks.NewMockMaker(t)
 			startMakerMock := startMocks.NewMockMaker(t)
 			stopMakerMock := stopMocks.NewMockMaker(t)
+			waitMakerMock := waitMocks.NewMockMaker(t)
 			actionMock := actionMocks.NewMockAction(t)
 
 			m := &nativeActionMaker{
 				fnd:             fndMock,
 				benchMaker:      benchMakerMock,
+				ifMaker:         ifMakerMock,
+				eventuallyMaker: eventuallyMakerMock,
 				executeMaker:    commandMakerMock,
 				expectMaker:     expectMakerMock,
+				faultProxyMaker: faultProxyMakerMock,
+				foreachMaker:    foreachMakerMock,
 				notMaker:        notMakerMock,
 				parallelMaker:   parallelMakerMock,
 				requestMaker:    requestMakerMock,
 				reloadMaker:     reloadMakerMock,
+				repeatMaker:     repeatMakerMock,
 				restartMaker:    restartMakerMock,
+				scrapeMaker:     scrapeMakerMock,
 				sequentialMaker: sequentialMakerMock,
+				signalMaker:     signalMakerMock,
 				startMaker:      startMakerMock,
 				stopMaker:       stopMakerMock,
+				waitMaker:       waitMakerMock,
 			}
 
 			tt.setupMocks(
@@ -508,16 +1064,24 @@ func Test_nativeActionMaker_MakeAction(t *testing.T) {
 				actionMock,
 				slMock,
 				benchMakerMock,
+				ifMakerMock,
+				eventuallyMakerMock,
 				commandMakerMock,
 				expectMakerMock,
+				faultProxyMakerMock,
+				foreachMakerMock,
 				notMakerMock,
 				parallelMakerMock,
 				requestMakerMock,
 				reloadMakerMock,
+				repeatMakerMock,
 				restartMakerMock,
+				scrapeMakerMock,
 				sequentialMakerMock,
+				signalMakerMock,
 				startMakerMock,
 				stopMakerMock,
+				waitMakerMock,
 			)
 
 			got, err := m.MakeAction(tt.config, slMock, tt.defaultTimeout)
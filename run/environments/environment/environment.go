@@ -26,6 +26,7 @@ import (
 	"github.com/wstool/wst/run/sandboxes/containers"
 	"io"
 	"os"
+	"time"
 )
 
 type Command struct {
@@ -75,7 +76,15 @@ type Environment interface {
 	RunTask(ctx context.Context, ss *ServiceSettings, cmd *Command) (task.Task, error)
 	ExecTaskCommand(ctx context.Context, ss *ServiceSettings, target task.Task, cmd *Command, oc output.Collector) error
 	ExecTaskSignal(ctx context.Context, ss *ServiceSettings, target task.Task, signal os.Signal) error
+	ExecTaskProcessesSignal(ctx context.Context, ss *ServiceSettings, target task.Task, processes []Process, signal os.Signal) error
+	FileExists(ctx context.Context, ss *ServiceSettings, target task.Task, path string) (bool, error)
+	PortReady(ctx context.Context, ss *ServiceSettings, target task.Task, port int32) (bool, error)
+	UdsReady(ctx context.Context, ss *ServiceSettings, target task.Task, path string) (bool, error)
+	TaskRunning(ctx context.Context, ss *ServiceSettings, target task.Task) (bool, error)
+	TaskProcesses(ctx context.Context, ss *ServiceSettings, target task.Task) ([]Process, error)
+	TaskFile(ctx context.Context, ss *ServiceSettings, target task.Task, path string, offset int64) (*File, error)
 	Output(ctx context.Context, target task.Task, outputType output.Type) (io.Reader, error)
+	OutputSince(ctx context.Context, target task.Task, outputType output.Type, since time.Time) (io.Reader, error)
 	PortsStart() int32
 	PortsEnd() int32
 	ReservePort() int32
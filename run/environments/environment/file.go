@@ -0,0 +1,109 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package environment
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// fileMissingLine is printed by the file stat command if the file does not exist.
+const fileMissingLine = "missing"
+
+// FileNoContent is the file read offset for reading only the file stat without the content.
+const FileNoContent int64 = -1
+
+// FileStatCommand returns the command printing the file stat line followed by the file content from the offset if it
+// is a regular file. It is used for reading files in containers.
+func FileStatCommand(path string, offset int64) []string {
+	return []string{"sh", "-c", "if [ ! -e \"$1\" ]; then echo " + fileMissingLine + "; exit 0; fi; " +
+		"stat -L -c 'stat %f %s %U %G' \"$1\" && if [ -f \"$1\" ] && [ \"$2\" -ge 0 ]; then " +
+		"tail -c +$(($2 + 1)) \"$1\"; fi", "sh", path, strconv.FormatInt(offset, 10)}
+}
+
+// File describes a file in the service environment.
+type File struct {
+	Mode  os.FileMode
+	Size  int64
+	Owner string
+	Group string
+	// Content is the file content from the read offset. It is set only for regular files and it is empty if the offset
+	// is not lower than the size.
+	Content []byte
+}
+
+// ParseFileStat parses the output of the file stat command. It returns nil if the file does not exist.
+func ParseFileStat(data []byte) (*File, error) {
+	line, content, _ := bytes.Cut(data, []byte("\n"))
+	statLine := strings.TrimSpace(string(line))
+	if statLine == fileMissingLine {
+		return nil, nil
+	}
+	fields := strings.Fields(statLine)
+	if len(fields) != 5 || fields[0] != "stat" {
+		return nil, errors.Errorf("invalid file stat format: %s", statLine)
+	}
+	mode, err := strconv.ParseUint(fields[1], 16, 32)
+	if err != nil {
+		return nil, errors.Errorf("invalid file stat mode: %v", err)
+	}
+	size, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("invalid file stat size: %v", err)
+	}
+	file := &File{
+		Mode:  FileModeFromUnix(uint32(mode)),
+		Size:  size,
+		Owner: fields[3],
+		Group: fields[4],
+	}
+	if file.Mode.IsRegular() {
+		file.Content = content
+	}
+	return file, nil
+}
+
+// FileModeFromUnix converts the raw unix file mode including the file type to the file mode.
+func FileModeFromUnix(mode uint32) os.FileMode {
+	fileMode := os.FileMode(mode & 0777)
+	switch mode & syscall.S_IFMT {
+	case syscall.S_IFDIR:
+		fileMode |= os.ModeDir
+	case syscall.S_IFLNK:
+		fileMode |= os.ModeSymlink
+	case syscall.S_IFSOCK:
+		fileMode |= os.ModeSocket
+	case syscall.S_IFIFO:
+		fileMode |= os.ModeNamedPipe
+	case syscall.S_IFCHR:
+		fileMode |= os.ModeDevice | os.ModeCharDevice
+	case syscall.S_IFBLK:
+		fileMode |= os.ModeDevice
+	}
+	if mode&syscall.S_ISUID != 0 {
+		fileMode |= os.ModeSetuid
+	}
+	if mode&syscall.S_ISGID != 0 {
+		fileMode |= os.ModeSetgid
+	}
+	if mode&syscall.S_ISVTX != 0 {
+		fileMode |= os.ModeSticky
+	}
+	return fileMode
+}
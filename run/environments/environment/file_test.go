@@ -0,0 +1,144 @@
+package environment
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStatCommand(t *testing.T) {
+	command := FileStatCommand("/var/run/php-fpm.sock", 10)
+	assert.Equal(t, "sh", command[0])
+	assert.Equal(t, []string{"/var/run/php-fpm.sock", "10"}, command[len(command)-2:])
+}
+
+func TestFileStatCommand_offset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slow.log")
+	require.NoError(t, os.WriteFile(path, []byte("line1\nline2\n"), 0644))
+	tests := []struct {
+		name    string
+		offset  int64
+		content string
+	}{
+		{name: "from start", offset: 0, content: "line1\nline2\n"},
+		{name: "from offset", offset: 6, content: "line2\n"},
+		{name: "from end", offset: 12, content: ""},
+		{name: "beyond end", offset: 20, content: ""},
+		{name: "without content", offset: FileNoContent, content: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := FileStatCommand(path, tt.offset)
+			data, err := exec.Command(command[0], command[1:]...).Output()
+			require.NoError(t, err)
+			file, err := ParseFileStat(data)
+			require.NoError(t, err)
+			require.NotNil(t, file)
+			assert.Equal(t, int64(12), file.Size)
+			assert.Equal(t, tt.content, string(file.Content))
+		})
+	}
+}
+
+func TestParseFileStat(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		want        *File
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name: "regular file with content",
+			data: "stat 81a4 12 www-data www\nline1\nline2\n",
+			want: &File{
+				Mode:    0644,
+				Size:    12,
+				Owner:   "www-data",
+				Group:   "www",
+				Content: []byte("line1\nline2\n"),
+			},
+		},
+		{
+			name: "empty regular file",
+			data: "stat 8180 0 root root\n",
+			want: &File{
+				Mode:    0600,
+				Owner:   "root",
+				Group:   "root",
+				Content: []byte{},
+			},
+		},
+		{
+			name: "socket without content",
+			data: "stat c1b6 0 www-data www-data\n",
+			want: &File{
+				Mode:  os.ModeSocket | 0666,
+				Owner: "www-data",
+				Group: "www-data",
+			},
+		},
+		{
+			name: "missing file",
+			data: "missing\n",
+			want: nil,
+		},
+		{
+			name:        "invalid format",
+			data:        "stat 81a4 12\n",
+			expectError: true,
+			errorMsg:    "invalid file stat format: stat 81a4 12",
+		},
+		{
+			name:        "invalid mode",
+			data:        "stat xyz 12 root root\n",
+			expectError: true,
+			errorMsg:    "invalid file stat mode",
+		},
+		{
+			name:        "invalid size",
+			data:        "stat 81a4 big root root\n",
+			expectError: true,
+			errorMsg:    "invalid file stat size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFileStat([]byte(tt.data))
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFileModeFromUnix(t *testing.T) {
+	tests := []struct {
+		name string
+		mode uint32
+		want os.FileMode
+	}{
+		{name: "regular file", mode: 0100644, want: 0644},
+		{name: "directory", mode: 040755, want: os.ModeDir | 0755},
+		{name: "symlink", mode: 0120777, want: os.ModeSymlink | 0777},
+		{name: "socket", mode: 0140660, want: os.ModeSocket | 0660},
+		{name: "named pipe", mode: 010600, want: os.ModeNamedPipe | 0600},
+		{name: "char device", mode: 020666, want: os.ModeDevice | os.ModeCharDevice | 0666},
+		{name: "block device", mode: 060660, want: os.ModeDevice | 0660},
+		{name: "special bits", mode: 0107755, want: os.ModeSetuid | os.ModeSetgid | os.ModeSticky | 0755},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FileModeFromUnix(tt.mode))
+		})
+	}
+}
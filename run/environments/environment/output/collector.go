@@ -24,6 +24,7 @@ import (
 	"io"
 	"strings"
 	"sync"
+	"time"
 )
 
 type Collector interface {
@@ -32,6 +33,7 @@ type Collector interface {
 	StderrReader(ctx context.Context) io.Reader
 	StdoutReader(ctx context.Context) io.Reader
 	Reader(ctx context.Context, outputType Type) (io.Reader, error)
+	ReaderSince(ctx context.Context, outputType Type, since time.Time) (io.Reader, error)
 	Start(stdoutPipe, stderrPipe io.ReadCloser) error
 	StdoutWriter() io.Writer
 	StderrWriter() io.Writer
@@ -39,10 +41,19 @@ type Collector interface {
 	Wait()
 }
 
+// outputChunk is a written data chunk with the time when it was written.
+type outputChunk struct {
+	time time.Time
+	data []byte
+}
+
 // blockingBufferReader is a custom reader that blocks until data is available in the buffer.
 type blockingBufferReader struct {
-	buffer    *bytes.Buffer
+	buffer *bytes.Buffer
+	// chunks keep all written data so independent readers can read it from any time.
+	chunks    []outputChunk
 	dataCh    chan struct{}
+	writeCh   chan struct{}
 	closeCh   chan struct{}
 	closed    bool
 	mu        sync.Mutex
@@ -54,6 +65,7 @@ func newBlockingBufferReader(buffer *bytes.Buffer) *blockingBufferReader {
 	return &blockingBufferReader{
 		buffer:  buffer,
 		dataCh:  make(chan struct{}, 1),
+		writeCh: make(chan struct{}),
 		closeCh: make(chan struct{}),
 	}
 }
@@ -64,6 +76,12 @@ func (r *blockingBufferReader) Write(data []byte) (int, error) {
 	defer r.mu.Unlock()
 
 	n, err := r.buffer.Write(data)
+	if n > 0 {
+		r.chunks = append(r.chunks, outputChunk{time: time.Now(), data: append([]byte(nil), data[:n]...)})
+		// Closing the channel notifies all independent readers waiting for the data.
+		close(r.writeCh)
+		r.writeCh = make(chan struct{})
+	}
 	select {
 	case r.dataCh <- struct{}{}: // Notify readers that data is available
 	default: // Non-blocking send
@@ -96,6 +114,44 @@ func (r *blockingBufferReader) readWithContext(ctx context.Context, p []byte) (i
 	}
 }
 
+// readSince reads data written since the time into p starting from the position of the sinceReader. It does not
+// consume the buffer so other readers are not affected.
+func (r *blockingBufferReader) readSince(ctx context.Context, sr *sinceReader, p []byte) (int, error) {
+	for {
+		r.mu.Lock()
+		for sr.chunk < len(r.chunks) && r.chunks[sr.chunk].time.Before(sr.since) {
+			sr.chunk++
+		}
+		if sr.chunk < len(r.chunks) {
+			data := r.chunks[sr.chunk].data[sr.offset:]
+			n := copy(p, data)
+			if n == len(data) {
+				sr.chunk++
+				sr.offset = 0
+			} else {
+				sr.offset += n
+			}
+			r.mu.Unlock()
+			return n, nil
+		}
+		if r.closed {
+			r.mu.Unlock()
+			return 0, io.EOF
+		}
+		writeCh := r.writeCh
+		r.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return 0, errors.Errorf("read cancelled: %v", ctx.Err())
+		case <-writeCh: // Wait for data to be written
+			continue
+		case <-r.closeCh: // Wait for the reader to be closed
+			continue
+		}
+	}
+}
+
 // Read reads data from the buffer, blocking if no data is available.
 func (r *blockingBufferReader) Read(p []byte) (int, error) {
 	return r.readWithContext(context.Background(), p)
@@ -181,6 +237,21 @@ func (bc *BufferedCollector) Reader(ctx context.Context, outputType Type) (io.Re
 	}
 }
 
+// ReaderSince returns an io.Reader for the logs of the specified type that were collected since the time. The reader
+// is independent, so it does not consume the logs read by other readers.
+func (bc *BufferedCollector) ReaderSince(ctx context.Context, outputType Type, since time.Time) (io.Reader, error) {
+	switch outputType {
+	case Stdout:
+		return newSinceReader(ctx, bc.stdoutBuffer, since), nil
+	case Stderr:
+		return newSinceReader(ctx, bc.stderrBuffer, since), nil
+	case Any:
+		return newSinceReader(ctx, bc.mixedBuffer, since), nil
+	default:
+		return nil, errors.Errorf("unsupported output type")
+	}
+}
+
 // StdoutReader returns an io.Reader for the collected stdout logs.
 func (bc *BufferedCollector) StdoutReader(ctx context.Context) io.Reader {
 	return newContextAwareReader(ctx, bc.stdoutBuffer)
@@ -273,3 +344,26 @@ func newContextAwareReader(ctx context.Context, reader *blockingBufferReader) *c
 func (r *contextAwareReader) Read(p []byte) (n int, err error) {
 	return r.reader.readWithContext(r.ctx, p)
 }
+
+// sinceReader reads data written to the buffer since the specified time with its own position.
+type sinceReader struct {
+	ctx    context.Context
+	reader *blockingBufferReader
+	since  time.Time
+	chunk  int
+	offset int
+}
+
+// newSinceReader creates a new sinceReader.
+func newSinceReader(ctx context.Context, reader *blockingBufferReader, since time.Time) *sinceReader {
+	return &sinceReader{
+		ctx:    ctx,
+		reader: reader,
+		since:  since,
+	}
+}
+
+// Read reads from the underlying reader with context.
+func (r *sinceReader) Read(p []byte) (n int, err error) {
+	return r.reader.readSince(r.ctx, r, p)
+}
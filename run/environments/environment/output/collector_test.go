@@ -317,6 +317,115 @@ func TestBufferedCollector_Reader_dispatch(t *testing.T) {
 	collector.Wait()
 }
 
+func TestBufferedCollector_ReaderSince(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	collector := NewBufferedCollector(fndMock, "tid")
+
+	_, err := collector.StdoutWriter().Write([]byte("stdout old\n"))
+	assert.NoError(t, err)
+	_, err = collector.StderrWriter().Write([]byte("stderr old\n"))
+	assert.NoError(t, err)
+	time.Sleep(2 * time.Millisecond)
+	since := time.Now()
+	time.Sleep(2 * time.Millisecond)
+	_, err = collector.StdoutWriter().Write([]byte("stdout new\n"))
+	assert.NoError(t, err)
+	_, err = collector.StderrWriter().Write([]byte("stderr new\n"))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		outputType Type
+		since      time.Time
+		expectErr  bool
+		expected   string
+	}{
+		{
+			name:       "stdout since time",
+			outputType: Stdout,
+			since:      since,
+			expected:   "stdout new\n",
+		},
+		{
+			name:       "stderr since time",
+			outputType: Stderr,
+			since:      since,
+			expected:   "stderr new\n",
+		},
+		{
+			name:       "any since time",
+			outputType: Any,
+			since:      since,
+			expected:   "stdout new\nstderr new\n",
+		},
+		{
+			name:       "stdout since zero time",
+			outputType: Stdout,
+			expected:   "stdout old\nstdout new\n",
+		},
+		{
+			name:       "unknown type",
+			outputType: Type(8),
+			expectErr:  true,
+		},
+	}
+
+	collector.Close()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := collector.ReaderSince(context.Background(), tt.outputType, tt.since)
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.Nil(t, reader)
+				return
+			}
+			assert.NoError(t, err)
+			buf, err := io.ReadAll(reader)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(buf))
+		})
+	}
+
+	// The independent readers do not consume the output of the shared reader.
+	buf, err := io.ReadAll(collector.StdoutReader(context.Background()))
+	assert.NoError(t, err)
+	assert.Equal(t, "stdout old\nstdout new\n", string(buf))
+}
+
+func TestBufferedCollector_ReaderSince_waiting(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	collector := NewBufferedCollector(fndMock, "tid")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first, err := collector.ReaderSince(ctx, Stdout, time.Now())
+	assert.NoError(t, err)
+	second, err := collector.ReaderSince(ctx, Stdout, time.Now())
+	assert.NoError(t, err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = collector.StdoutWriter().Write([]byte("line 1\n"))
+		time.Sleep(20 * time.Millisecond)
+		_, _ = collector.StdoutWriter().Write([]byte("line 2\n"))
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	// Both readers get all lines written while they are waiting.
+	var wg sync.WaitGroup
+	for _, reader := range []io.Reader{first, second} {
+		wg.Add(1)
+		go func(reader io.Reader) {
+			defer wg.Done()
+			buf, err := io.ReadAll(reader)
+			assert.ErrorContains(t, err, "read cancelled: context canceled")
+			assert.Equal(t, "line 1\nline 2\n", string(buf))
+		}(reader)
+	}
+	wg.Wait()
+}
+
 func TestBufferedCollector_stderrBuffer_Read(t *testing.T) {
 	// Define the events with delays
 	stderrEvents := []event{
@@ -0,0 +1,47 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package environment
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PortListenCommand prints the TCP sockets of the network namespace. It is used for checking ports in containers
+// whose addresses might not be reachable from the host.
+var PortListenCommand = []string{"sh", "-c", "cat /proc/net/tcp /proc/net/tcp6 2>/dev/null; true"}
+
+// tcpListenState is the state of the listening sockets in /proc/net/tcp.
+const tcpListenState = "0A"
+
+// ParsePortListening returns true if the content of /proc/net/tcp contains a listening socket on the port.
+func ParsePortListening(content string, port int32) bool {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[3] != tcpListenState {
+			continue
+		}
+		// The local address is the hex address and port separated by colon.
+		index := strings.LastIndexByte(fields[1], ':')
+		if index < 0 {
+			continue
+		}
+		localPort, err := strconv.ParseUint(fields[1][index+1:], 16, 16)
+		if err == nil && int32(localPort) == port {
+			return true
+		}
+	}
+	return false
+}
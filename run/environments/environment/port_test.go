@@ -0,0 +1,30 @@
+package environment
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParsePortListening(t *testing.T) {
+	content := "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n" +
+		"   0: 00000000:2328 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1\n" +
+		"   1: 0100007F:1F90 0100007F:9C40 01 00000000:00000000 00:00000000 00000000     0        0 2 1\n" +
+		"  sl  local_address                         remote_address                        st\n" +
+		"   0: 00000000000000000000000000000000:0050 00000000000000000000000000000000:0000 0A\n"
+	tests := []struct {
+		name string
+		port int32
+		want bool
+	}{
+		{name: "listening ipv4 port", port: 9000, want: true},
+		{name: "listening ipv6 port", port: 80, want: true},
+		{name: "established connection port", port: 8080, want: false},
+		{name: "unknown port", port: 443, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParsePortListening(content, tt.port))
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package environment
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ProcStatCommand prints the system uptime followed by the open file descriptors count and stat of all processes.
+// The descriptors are counted by the shell glob without running a command per process and the count line is omitted
+// if the descriptors directory cannot be read. It is used for listing processes in containers.
+var ProcStatCommand = []string{"sh", "-c", "echo \"uptime $(cat /proc/uptime)\"; for d in /proc/[0-9]*; do " +
+	"if [ -r $d/fd ]; then set -- $d/fd/*; [ -L \"$1\" ] || set --; echo \"fd ${d#/proc/} $#\"; fi; " +
+	"cat $d/stat 2>/dev/null; done; true"}
+
+// clockTicks is the number of clock ticks per second used in /proc (USER_HZ).
+const clockTicks = 100
+
+// pageSize is the memory page size used for resident set size in /proc.
+var pageSize = int64(os.Getpagesize())
+
+// Process describes a process running in the service environment.
+type Process struct {
+	Pid  int
+	PPid int
+	Name string
+	// Instance is the name of the task instance (e.g. pod) that runs the process. It is empty for single instance tasks.
+	Instance string
+	// Main is set for the task main process that is the root of the process tree.
+	Main bool
+	// RSS is the resident set size in bytes.
+	RSS int64
+	// FDs is the number of open file descriptors. It is valid only if FDsKnown is set.
+	FDs int
+	// FDsKnown is set if the open file descriptors could be read.
+	FDsKnown bool
+	// StartTime is the time of the process start after the system boot.
+	StartTime time.Duration
+	// Uptime is the time since the process start. It is valid only if UptimeKnown is set.
+	Uptime time.Duration
+	// UptimeKnown is set if the process start time and the system uptime could be read.
+	UptimeKnown bool
+}
+
+// ParseProcStat parses the content of /proc/<pid>/stat.
+func ParseProcStat(line string) (*Process, error) {
+	// The comm field is in parentheses and can contain spaces and parentheses so the last one is used.
+	start := strings.IndexByte(line, '(')
+	end := strings.LastIndexByte(line, ')')
+	if start < 0 || end < start {
+		return nil, errors.Errorf("invalid process stat format: %s", line)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(line[:start]))
+	if err != nil {
+		return nil, errors.Errorf("invalid process stat pid: %v", err)
+	}
+	fields := strings.Fields(line[end+1:])
+	if len(fields) < 2 {
+		return nil, errors.Errorf("invalid process stat format: %s", line)
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return nil, errors.Errorf("invalid process stat ppid: %v", err)
+	}
+	process := &Process{
+		Pid:  pid,
+		PPid: ppid,
+		Name: line[start+1 : end],
+	}
+	// The start time and RSS are the 22nd and 24th fields so only present in the full stat.
+	if len(fields) >= 22 {
+		startTicks, err := strconv.ParseUint(fields[19], 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid process stat start time: %v", err)
+		}
+		rssPages, err := strconv.ParseInt(fields[21], 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid process stat rss: %v", err)
+		}
+		process.StartTime = time.Duration(startTicks) * time.Second / clockTicks
+		process.RSS = rssPages * pageSize
+	}
+	return process, nil
+}
+
+// ParseProcUptime parses the system uptime from the content of /proc/uptime.
+func ParseProcUptime(content string) (time.Duration, error) {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return 0, errors.New("empty process uptime")
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid process uptime: %v", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// SetProcessesUptime sets the uptime of the processes from the system uptime. The processes without the start time
+// are left unknown.
+func SetProcessesUptime(processes []Process, uptime time.Duration) {
+	for i := range processes {
+		if processes[i].StartTime > 0 && processes[i].StartTime <= uptime {
+			processes[i].Uptime = uptime - processes[i].StartTime
+			processes[i].UptimeKnown = true
+		}
+	}
+}
+
+// ParseProcStats parses the output of ProcStatCommand which contains concatenated /proc/<pid>/stat lines optionally
+// mixed with the system uptime line and the open file descriptors count lines. Invalid lines are skipped as processes
+// can disappear while they are being read.
+func ParseProcStats(r io.Reader) ([]Process, error) {
+	var processes []Process
+	var uptime time.Duration
+	hasUptime := false
+	fds := make(map[int]int)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "uptime ") {
+			if value, err := ParseProcUptime(strings.TrimPrefix(line, "uptime ")); err == nil {
+				uptime = value
+				hasUptime = true
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "fd ") {
+			fields := strings.Fields(line)
+			if len(fields) == 3 {
+				pid, pidErr := strconv.Atoi(fields[1])
+				count, countErr := strconv.Atoi(fields[2])
+				if pidErr == nil && countErr == nil {
+					fds[pid] = count
+				}
+			}
+			continue
+		}
+		process, err := ParseProcStat(line)
+		if err != nil {
+			continue
+		}
+		process.FDs, process.FDsKnown = fds[process.Pid]
+		processes = append(processes, *process)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if hasUptime {
+		SetProcessesUptime(processes, uptime)
+	}
+	return processes, nil
+}
+
+// ProcessTree returns the process with the supplied pid followed by all its descendants.
+func ProcessTree(processes []Process, pid int) []Process {
+	var tree []Process
+	for _, process := range processes {
+		if process.Pid == pid {
+			process.Main = true
+			tree = append(tree, process)
+			break
+		}
+	}
+	if tree == nil {
+		return nil
+	}
+	for i := 0; i < len(tree); i++ {
+		for _, process := range processes {
+			if process.PPid == tree[i].Pid && process.Pid != tree[i].Pid {
+				tree = append(tree, process)
+			}
+		}
+	}
+	return tree
+}
+
+// ProcessPids returns pids of the supplied processes.
+func ProcessPids(processes []Process) []int {
+	pids := make([]int, 0, len(processes))
+	for _, process := range processes {
+		pids = append(pids, process.Pid)
+	}
+	return pids
+}
+
+// SignalCommand returns the kill command sending the signal to the processes with the supplied pids.
+func SignalCommand(signal os.Signal, pids []int) ([]string, error) {
+	signalNumber, err := SignalNumber(signal)
+	if err != nil {
+		return nil, err
+	}
+	command := []string{"kill", fmt.Sprintf("-%d", signalNumber)}
+	for _, pid := range pids {
+		command = append(command, strconv.Itoa(pid))
+	}
+	return command, nil
+}
+
+// SignalNumber returns the number of the supplied signal.
+func SignalNumber(signal os.Signal) (int, error) {
+	sig, ok := signal.(syscall.Signal)
+	if !ok {
+		return 0, errors.Errorf("unsupported signal %v", signal)
+	}
+	return int(sig), nil
+}
@@ -0,0 +1,298 @@
+package environment
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestParseProcStat(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		want        *Process
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name: "simple process",
+			line: "123 (php-fpm) S 1 123 123 0 -1 4194560 1000 0 0 0",
+			want: &Process{Pid: 123, PPid: 1, Name: "php-fpm"},
+		},
+		{
+			name: "full process stat",
+			line: "123 (php-fpm) S 1 123 123 0 -1 4194560 1000 0 0 0 0 0 0 0 20 0 1 0 1250 2703360 10 " +
+				"18446744073709551615 0 0",
+			want: &Process{
+				Pid:       123,
+				PPid:      1,
+				Name:      "php-fpm",
+				RSS:       10 * pageSize,
+				StartTime: 12500 * time.Millisecond,
+			},
+		},
+		{
+			name: "process name with spaces and parentheses",
+			line: "124 (php-fpm: pool (www)) S 123 123 123 0 -1",
+			want: &Process{Pid: 124, PPid: 123, Name: "php-fpm: pool (www)"},
+		},
+		{
+			name:        "missing name",
+			line:        "124 S 123",
+			expectError: true,
+			errorMsg:    "invalid process stat format: 124 S 123",
+		},
+		{
+			name:        "invalid pid",
+			line:        "abc (php-fpm) S 1",
+			expectError: true,
+			errorMsg:    "invalid process stat pid",
+		},
+		{
+			name:        "missing ppid",
+			line:        "123 (php-fpm) S",
+			expectError: true,
+			errorMsg:    "invalid process stat format",
+		},
+		{
+			name:        "invalid ppid",
+			line:        "123 (php-fpm) S x",
+			expectError: true,
+			errorMsg:    "invalid process stat ppid",
+		},
+		{
+			name:        "invalid start time",
+			line:        "123 (php-fpm) S 1 123 123 0 -1 4194560 1000 0 0 0 0 0 0 0 20 0 1 0 x 2703360 10",
+			expectError: true,
+			errorMsg:    "invalid process stat start time",
+		},
+		{
+			name:        "invalid rss",
+			line:        "123 (php-fpm) S 1 123 123 0 -1 4194560 1000 0 0 0 0 0 0 0 20 0 1 0 1250 2703360 x",
+			expectError: true,
+			errorMsg:    "invalid process stat rss",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProcStat(tt.line)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseProcStats(t *testing.T) {
+	input := "1 (sh) S 0 1 1\n\n12 (php-fpm) S 1 12 12\ninvalid\n13 (php-fpm) S 12 12 12\n"
+	got, err := ParseProcStats(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, []Process{
+		{Pid: 1, PPid: 0, Name: "sh"},
+		{Pid: 12, PPid: 1, Name: "php-fpm"},
+		{Pid: 13, PPid: 12, Name: "php-fpm"},
+	}, got)
+}
+
+func TestParseProcStats_withStats(t *testing.T) {
+	input := "uptime 100.50 20.00\nfd 12 7\n" +
+		"12 (php-fpm) S 1 12 12 0 -1 4194560 1000 0 0 0 0 0 0 0 20 0 1 0 500 2703360 100 0\n" +
+		"fd 13 5\nfd 14 invalid\n" +
+		"13 (php-fpm) S 12 12 12 0 -1 4194560 1000 0 0 0 0 0 0 0 20 0 1 0 9050 2703360 50 0\n"
+	got, err := ParseProcStats(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, []Process{
+		{Pid: 12, PPid: 1, Name: "php-fpm", RSS: 100 * pageSize, FDs: 7, FDsKnown: true, StartTime: 5 * time.Second,
+			Uptime: 95500 * time.Millisecond, UptimeKnown: true},
+		{Pid: 13, PPid: 12, Name: "php-fpm", RSS: 50 * pageSize, FDs: 5, FDsKnown: true,
+			StartTime: 90500 * time.Millisecond, Uptime: 10 * time.Second, UptimeKnown: true},
+	}, got)
+}
+
+func TestProcStatCommand(t *testing.T) {
+	if _, err := os.Stat("/proc/self/fd"); err != nil {
+		t.Skip("proc file system is not available")
+	}
+	cmd := exec.Command(ProcStatCommand[0], ProcStatCommand[1:]...)
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+	processes, err := ParseProcStats(stdout)
+	require.NoError(t, err)
+	require.NoError(t, cmd.Wait())
+
+	var self *Process
+	for i := range processes {
+		if processes[i].Pid == os.Getpid() {
+			self = &processes[i]
+		}
+	}
+	require.NotNil(t, self)
+	assert.True(t, self.FDsKnown)
+	assert.GreaterOrEqual(t, self.FDs, 3)
+	assert.True(t, self.UptimeKnown)
+	assert.Greater(t, self.Uptime, time.Duration(0))
+}
+
+func TestParseProcUptime(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		want        time.Duration
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:    "valid uptime",
+			content: "16599.41 9598.97\n",
+			want:    16599410 * time.Millisecond,
+		},
+		{
+			name:        "empty uptime",
+			content:     "",
+			expectError: true,
+			errorMsg:    "empty process uptime",
+		},
+		{
+			name:        "invalid uptime",
+			content:     "x 1.0",
+			expectError: true,
+			errorMsg:    "invalid process uptime",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProcUptime(tt.content)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProcessTree(t *testing.T) {
+	processes := []Process{
+		{Pid: 1, PPid: 0, Name: "init"},
+		{Pid: 10, PPid: 1, Name: "php-fpm"},
+		{Pid: 11, PPid: 10, Name: "php-fpm"},
+		{Pid: 12, PPid: 10, Name: "php-fpm"},
+		{Pid: 13, PPid: 11, Name: "sh"},
+		{Pid: 20, PPid: 1, Name: "nginx"},
+	}
+	tests := []struct {
+		name string
+		pid  int
+		want []Process
+	}{
+		{
+			name: "process with descendants",
+			pid:  10,
+			want: []Process{
+				{Pid: 10, PPid: 1, Name: "php-fpm", Main: true},
+				{Pid: 11, PPid: 10, Name: "php-fpm"},
+				{Pid: 12, PPid: 10, Name: "php-fpm"},
+				{Pid: 13, PPid: 11, Name: "sh"},
+			},
+		},
+		{
+			name: "process without descendants",
+			pid:  20,
+			want: []Process{
+				{Pid: 20, PPid: 1, Name: "nginx", Main: true},
+			},
+		},
+		{
+			name: "missing process",
+			pid:  30,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ProcessTree(processes, tt.pid))
+		})
+	}
+}
+
+func TestSignalCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		signal      os.Signal
+		pids        []int
+		want        []string
+		expectError bool
+	}{
+		{
+			name:   "multiple processes",
+			signal: syscall.SIGUSR1,
+			pids:   []int{11, 12},
+			want:   []string{"kill", "-10", "11", "12"},
+		},
+		{
+			name:        "unknown signal",
+			signal:      unknownSignal{},
+			pids:        []int{11},
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SignalCommand(tt.signal, tt.pids)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+type unknownSignal struct{}
+
+func (s unknownSignal) String() string { return "unknown" }
+func (s unknownSignal) Signal()        {}
+
+func TestSignalNumber(t *testing.T) {
+	tests := []struct {
+		name        string
+		signal      os.Signal
+		want        int
+		expectError bool
+	}{
+		{
+			name:   "syscall signal",
+			signal: syscall.SIGUSR1,
+			want:   int(syscall.SIGUSR1),
+		},
+		{
+			name:        "unknown signal",
+			signal:      unknownSignal{},
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SignalNumber(tt.signal)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
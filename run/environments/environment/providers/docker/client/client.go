@@ -36,10 +36,23 @@ type Client interface {
 		platform *ocispec.Platform,
 		containerName string,
 	) (container.CreateResponse, error)
+	ContainerExecAttach(
+		ctx context.Context,
+		execID string,
+		config container.ExecAttachOptions,
+	) (types.HijackedResponse, error)
+	ContainerExecCreate(
+		ctx context.Context,
+		containerID string,
+		options container.ExecOptions,
+	) (container.ExecCreateResponse, error)
+	ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error)
 	ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error)
+	ContainerKill(ctx context.Context, containerID, signal string) error
 	ContainerLogs(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error)
 	ContainerRemove(ctx context.Context, containerID string, options container.RemoveOptions) error
 	ContainerStart(ctx context.Context, containerID string, options container.StartOptions) error
+	ContainerStatPath(ctx context.Context, containerID, path string) (container.PathStat, error)
 	ContainerStop(ctx context.Context, containerID string, options container.StopOptions) error
 	ContainerWait(
 		ctx context.Context,
@@ -83,11 +96,31 @@ func (d dockerClient) ContainerCreate(ctx context.Context, config *container.Con
 	return d.cli.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, containerName)
 }
 
+// ContainerExecAttach attaches to the exec process streams.
+func (d dockerClient) ContainerExecAttach(ctx context.Context, execID string, config container.ExecAttachOptions) (types.HijackedResponse, error) {
+	return d.cli.ContainerExecAttach(ctx, execID, config)
+}
+
+// ContainerExecCreate creates a new exec process in the container.
+func (d dockerClient) ContainerExecCreate(ctx context.Context, containerID string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+	return d.cli.ContainerExecCreate(ctx, containerID, options)
+}
+
+// ContainerExecInspect returns information about the exec process.
+func (d dockerClient) ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error) {
+	return d.cli.ContainerExecInspect(ctx, execID)
+}
+
 // ContainerInspect returns detailed information about the specified container.
 func (d dockerClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
 	return d.cli.ContainerInspect(ctx, containerID)
 }
 
+// ContainerKill sends the signal to the container main process.
+func (d dockerClient) ContainerKill(ctx context.Context, containerID, signal string) error {
+	return d.cli.ContainerKill(ctx, containerID, signal)
+}
+
 // ContainerLogs fetches the logs of a container.
 func (d dockerClient) ContainerLogs(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error) {
 	return d.cli.ContainerLogs(ctx, container, options)
@@ -103,6 +136,11 @@ func (d dockerClient) ContainerStart(ctx context.Context, containerID string, op
 	return d.cli.ContainerStart(ctx, containerID, options)
 }
 
+// ContainerStatPath returns stat information about a path inside the container filesystem.
+func (d dockerClient) ContainerStatPath(ctx context.Context, containerID, path string) (container.PathStat, error) {
+	return d.cli.ContainerStatPath(ctx, containerID, path)
+}
+
 // ContainerStop stops a running container with a specified timeout.
 func (d dockerClient) ContainerStop(ctx context.Context, containerID string, options container.StopOptions) error {
 	return d.cli.ContainerStop(ctx, containerID, options)
@@ -15,11 +15,14 @@
 package docker
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 	"github.com/pkg/errors"
 	"github.com/wstool/wst/app"
@@ -32,6 +35,7 @@ import (
 	"github.com/wstool/wst/run/resources"
 	"io"
 	"os"
+	"reflect"
 	"strconv"
 	"sync"
 	"time"
@@ -324,6 +328,39 @@ func (e *dockerEnvironment) RunTask(ctx context.Context, ss *environment.Service
 	}
 }
 
+// exec runs the command in the container and copies its output to the supplied writers.
+func (e *dockerEnvironment) exec(ctx context.Context, target task.Task, command []string, stdout, stderr io.Writer) error {
+	execResp, err := e.cli.ContainerExecCreate(ctx, target.Id(), container.ExecOptions{
+		Cmd:          command,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return errors.Errorf("failed to create exec in container %s: %v", target.Name(), err)
+	}
+	attachResp, err := e.cli.ContainerExecAttach(ctx, execResp.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return errors.Errorf("failed to attach exec in container %s: %v", target.Name(), err)
+	}
+	defer attachResp.Close()
+	if _, err = stdcopy.StdCopy(stdout, stderr, attachResp.Reader); err != nil {
+		return errors.Errorf("failed to read exec output in container %s: %v", target.Name(), err)
+	}
+	inspectResp, err := e.cli.ContainerExecInspect(ctx, execResp.ID)
+	if err != nil {
+		return errors.Errorf("failed to inspect exec in container %s: %v", target.Name(), err)
+	}
+	if inspectResp.ExitCode != 0 {
+		return errors.Errorf(
+			"command %s in container %s exited with code %d",
+			command[0],
+			target.Name(),
+			inspectResp.ExitCode,
+		)
+	}
+	return nil
+}
+
 func (e *dockerEnvironment) ExecTaskCommand(
 	ctx context.Context,
 	ss *environment.ServiceSettings,
@@ -331,11 +368,136 @@ func (e *dockerEnvironment) ExecTaskCommand(
 	cmd *environment.Command,
 	oc output.Collector,
 ) error {
-	return errors.Errorf("executing command is not currently supported in Docker environment")
+	if e.Fnd.DryRun() {
+		return nil
+	}
+	stdout, stderr := io.Discard, io.Discard
+	if oc != nil && !reflect.ValueOf(oc).IsNil() {
+		stdout = oc.StdoutWriter()
+		stderr = oc.StderrWriter()
+	}
+	return e.exec(ctx, target, append([]string{cmd.Name}, cmd.Args...), stdout, stderr)
 }
 
 func (e *dockerEnvironment) ExecTaskSignal(ctx context.Context, ss *environment.ServiceSettings, target task.Task, signal os.Signal) error {
-	return errors.Errorf("executing signal is not currently supported in Kubernetes environment")
+	if e.Fnd.DryRun() {
+		return nil
+	}
+	signalNumber, err := environment.SignalNumber(signal)
+	if err != nil {
+		return err
+	}
+	if err = e.cli.ContainerKill(ctx, target.Id(), strconv.Itoa(signalNumber)); err != nil {
+		return errors.Errorf("failed to send signal to container %s: %v", target.Name(), err)
+	}
+	return nil
+}
+
+// ExecTaskProcessesSignal sends the signal to the supplied processes in the container.
+func (e *dockerEnvironment) ExecTaskProcessesSignal(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	processes []environment.Process,
+	signal os.Signal,
+) error {
+	if e.Fnd.DryRun() {
+		return nil
+	}
+	command, err := environment.SignalCommand(signal, environment.ProcessPids(processes))
+	if err != nil {
+		return err
+	}
+	return e.exec(ctx, target, command, io.Discard, io.Discard)
+}
+
+func (e *dockerEnvironment) statPath(ctx context.Context, target task.Task, path string) (*container.PathStat, error) {
+	stat, err := e.cli.ContainerStatPath(ctx, target.Id(), path)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Errorf("failed to check path %s in container %s: %v", path, target.Name(), err)
+	}
+	return &stat, nil
+}
+
+func (e *dockerEnvironment) FileExists(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	path string,
+) (bool, error) {
+	stat, err := e.statPath(ctx, target, path)
+	if err != nil {
+		return false, err
+	}
+	return stat != nil, nil
+}
+
+// PortReady checks that the port is listening in the container as the container address might not be reachable from
+// the host (e.g. on Docker Desktop).
+func (e *dockerEnvironment) PortReady(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	port int32,
+) (bool, error) {
+	var stdout bytes.Buffer
+	if err := e.exec(ctx, target, environment.PortListenCommand, &stdout, io.Discard); err != nil {
+		return false, err
+	}
+	return environment.ParsePortListening(stdout.String(), port), nil
+}
+
+// UdsReady checks that the socket file exists in the container as it is not reachable from the host.
+func (e *dockerEnvironment) UdsReady(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	path string,
+) (bool, error) {
+	stat, err := e.statPath(ctx, target, path)
+	if err != nil {
+		return false, err
+	}
+	return stat != nil && stat.Mode&os.ModeSocket != 0, nil
+}
+
+func (e *dockerEnvironment) TaskRunning(ctx context.Context, ss *environment.ServiceSettings, target task.Task) (bool, error) {
+	return e.isContainerReady(ctx, target.Id())
+}
+
+// TaskProcesses returns the container main process and all its descendants.
+func (e *dockerEnvironment) TaskProcesses(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+) ([]environment.Process, error) {
+	var stdout bytes.Buffer
+	if err := e.exec(ctx, target, environment.ProcStatCommand, &stdout, io.Discard); err != nil {
+		return nil, err
+	}
+	processes, err := environment.ParseProcStats(&stdout)
+	if err != nil {
+		return nil, err
+	}
+	return environment.ProcessTree(processes, target.Pid()), nil
+}
+
+// TaskFile returns the file stat and content read from the container. It returns nil if the file does not exist.
+func (e *dockerEnvironment) TaskFile(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	path string,
+	offset int64,
+) (*environment.File, error) {
+	var stdout bytes.Buffer
+	if err := e.exec(ctx, target, environment.FileStatCommand(path, offset), &stdout, io.Discard); err != nil {
+		return nil, err
+	}
+	return environment.ParseFileStat(stdout.Bytes())
 }
 
 func (e *dockerEnvironment) Output(ctx context.Context, target task.Task, outputType output.Type) (io.Reader, error) {
@@ -356,6 +518,30 @@ func (e *dockerEnvironment) Output(ctx context.Context, target task.Task, output
 	return reader, nil
 }
 
+func (e *dockerEnvironment) OutputSince(
+	ctx context.Context,
+	target task.Task,
+	outputType output.Type,
+	since time.Time,
+) (io.Reader, error) {
+	if e.Fnd.DryRun() {
+		return &app.DummyReaderCloser{}, nil
+	}
+
+	containerID := target.Id()
+	reader, err := e.cli.ContainerLogs(ctx, containerID, container.LogsOptions{
+		ShowStdout: outputType == output.Stdout || outputType == output.Any,
+		ShowStderr: outputType == output.Stderr || outputType == output.Any,
+		Since:      since.Format(time.RFC3339Nano),
+		Follow:     true,
+	})
+	if err != nil {
+		return nil, errors.Errorf("failed to get container logs: %v", err)
+	}
+
+	return reader, nil
+}
+
 func (e *dockerEnvironment) RootPath(workspace string) string {
 	return ""
 }
@@ -1,11 +1,15 @@
 package docker
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	cerrdefs "github.com/containerd/errdefs"
 	apitypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
@@ -15,6 +19,7 @@ import (
 	"github.com/wstool/wst/conf/types"
 	"github.com/wstool/wst/mocks/authored/external"
 	appMocks "github.com/wstool/wst/mocks/generated/app"
+	outputMocks "github.com/wstool/wst/mocks/generated/run/environments/environment/output"
 	dockerClientMocks "github.com/wstool/wst/mocks/generated/run/environments/environment/providers/docker/client"
 	resourcesMocks "github.com/wstool/wst/mocks/generated/run/resources"
 	certificatesMocks "github.com/wstool/wst/mocks/generated/run/resources/certificates"
@@ -27,7 +32,10 @@ import (
 	"github.com/wstool/wst/run/resources/scripts"
 	"github.com/wstool/wst/run/sandboxes/containers"
 	"io"
+	"net"
 	"os"
+	"strconv"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -1931,36 +1939,760 @@ func Test_dockerEnvironment_RunTask(t *testing.T) {
 	}
 }
 
+func execAttachResponse(t *testing.T, stdout, stderr string) apitypes.HijackedResponse {
+	var buf bytes.Buffer
+	_, err := stdcopy.NewStdWriter(&buf, stdcopy.Stdout).Write([]byte(stdout))
+	assert.NoError(t, err)
+	if stderr != "" {
+		_, err = stdcopy.NewStdWriter(&buf, stdcopy.Stderr).Write([]byte(stderr))
+		assert.NoError(t, err)
+	}
+	conn, peer := net.Pipe()
+	t.Cleanup(func() { _ = peer.Close() })
+	return apitypes.HijackedResponse{
+		Conn:   conn,
+		Reader: bufio.NewReader(&buf),
+	}
+}
+
 func Test_dockerEnvironment_ExecTaskCommand(t *testing.T) {
-	env := &dockerEnvironment{}
-	ctx := context.Background()
-	ss := &environment.ServiceSettings{
-		Name:       "svc",
-		FullName:   "mysvc",
-		Port:       8080,
-		ServerPort: 1234,
+	execOptions := container.ExecOptions{
+		Cmd:          []string{"kill", "-10", "12"},
+		AttachStdout: true,
+		AttachStderr: true,
+	}
+	tests := []struct {
+		name             string
+		dryRun           bool
+		setupMocks       func(*testing.T, context.Context, *dockerClientMocks.MockClient)
+		expectedStdout   string
+		expectedStderr   string
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "successful execution",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{ID: "eid1"}, nil)
+				cli.On("ContainerExecAttach", ctx, "eid1", container.ExecAttachOptions{}).Return(
+					execAttachResponse(t, "out", "err"), nil)
+				cli.On("ContainerExecInspect", ctx, "eid1").Return(container.ExecInspect{ExitCode: 0}, nil)
+			},
+			expectedStdout: "out",
+			expectedStderr: "err",
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+			},
+		},
+		{
+			name: "exec create error",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{}, errors.New("create err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to create exec in container cn1: create err",
+		},
+		{
+			name: "exec attach error",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{ID: "eid1"}, nil)
+				cli.On("ContainerExecAttach", ctx, "eid1", container.ExecAttachOptions{}).Return(
+					apitypes.HijackedResponse{}, errors.New("attach err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to attach exec in container cn1: attach err",
+		},
+		{
+			name: "exec inspect error",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{ID: "eid1"}, nil)
+				cli.On("ContainerExecAttach", ctx, "eid1", container.ExecAttachOptions{}).Return(
+					execAttachResponse(t, "", ""), nil)
+				cli.On("ContainerExecInspect", ctx, "eid1").Return(
+					container.ExecInspect{}, errors.New("inspect err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to inspect exec in container cn1: inspect err",
+		},
+		{
+			name: "non zero exit code",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{ID: "eid1"}, nil)
+				cli.On("ContainerExecAttach", ctx, "eid1", container.ExecAttachOptions{}).Return(
+					execAttachResponse(t, "", "no such process"), nil)
+				cli.On("ContainerExecInspect", ctx, "eid1").Return(container.ExecInspect{ExitCode: 1}, nil)
+			},
+			expectedStderr:   "no such process",
+			expectError:      true,
+			expectedErrorMsg: "command kill in container cn1 exited with code 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			fndMock.On("DryRun").Return(tt.dryRun)
+			clientMock := dockerClientMocks.NewMockClient(t)
+			ctx := context.Background()
+			e := &dockerEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: fndMock,
+					},
+				},
+				cli: clientMock,
+			}
+			target := &dockerTask{
+				containerName: "cn1",
+				containerId:   "cid1",
+			}
+			var stdout, stderr bytes.Buffer
+			oc := outputMocks.NewMockCollector(t)
+			if !tt.dryRun {
+				oc.On("StdoutWriter").Return(&stdout)
+				oc.On("StderrWriter").Return(&stderr)
+			}
+
+			tt.setupMocks(t, ctx, clientMock)
+			cmd := &environment.Command{Name: "kill", Args: []string{"-10", "12"}}
+			err := e.ExecTaskCommand(ctx, &environment.ServiceSettings{}, target, cmd, oc)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedStdout, stdout.String())
+			assert.Equal(t, tt.expectedStderr, stderr.String())
+		})
+	}
+}
+
+type testSignal struct{}
+
+func (s testSignal) String() string { return "test" }
+func (s testSignal) Signal()        {}
+
+func Test_dockerEnvironment_ExecTaskSignal(t *testing.T) {
+	tests := []struct {
+		name             string
+		dryRun           bool
+		signal           os.Signal
+		setupMocks       func(*testing.T, context.Context, *dockerClientMocks.MockClient)
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name:   "successful signal",
+			signal: syscall.SIGUSR1,
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerKill", ctx, "cid1", strconv.Itoa(int(syscall.SIGUSR1))).Return(nil)
+			},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			signal: syscall.SIGUSR1,
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+			},
+		},
+		{
+			name:   "unsupported signal",
+			signal: testSignal{},
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+			},
+			expectError:      true,
+			expectedErrorMsg: "unsupported signal test",
+		},
+		{
+			name:   "kill error",
+			signal: syscall.SIGTERM,
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerKill", ctx, "cid1", strconv.Itoa(int(syscall.SIGTERM))).Return(errors.New("kill err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to send signal to container cn1: kill err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			fndMock.On("DryRun").Return(tt.dryRun)
+			clientMock := dockerClientMocks.NewMockClient(t)
+			ctx := context.Background()
+			e := &dockerEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: fndMock,
+					},
+				},
+				cli: clientMock,
+			}
+			target := &dockerTask{
+				containerName: "cn1",
+				containerId:   "cid1",
+			}
+
+			tt.setupMocks(t, ctx, clientMock)
+			err := e.ExecTaskSignal(ctx, &environment.ServiceSettings{}, target, tt.signal)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_dockerEnvironment_ExecTaskProcessesSignal(t *testing.T) {
+	processes := []environment.Process{
+		{Pid: 7, PPid: 1, Name: "php-fpm"},
+		{Pid: 8, PPid: 1, Name: "php-fpm"},
+	}
+	execOptions := container.ExecOptions{
+		Cmd:          []string{"kill", "-10", "7", "8"},
+		AttachStdout: true,
+		AttachStderr: true,
+	}
+	tests := []struct {
+		name             string
+		dryRun           bool
+		setupMocks       func(*testing.T, context.Context, *dockerClientMocks.MockClient)
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "successful signal",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{ID: "eid1"}, nil)
+				cli.On("ContainerExecAttach", ctx, "eid1", container.ExecAttachOptions{}).Return(
+					execAttachResponse(t, "", ""), nil)
+				cli.On("ContainerExecInspect", ctx, "eid1").Return(container.ExecInspect{ExitCode: 0}, nil)
+			},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+			},
+		},
+		{
+			name: "exec error",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{}, errors.New("create err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to create exec in container cn1: create err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			fndMock.On("DryRun").Return(tt.dryRun)
+			clientMock := dockerClientMocks.NewMockClient(t)
+			ctx := context.Background()
+			e := &dockerEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: fndMock,
+					},
+				},
+				cli: clientMock,
+			}
+			target := &dockerTask{
+				containerName: "cn1",
+				containerId:   "cid1",
+			}
+
+			tt.setupMocks(t, ctx, clientMock)
+			err := e.ExecTaskProcessesSignal(ctx, &environment.ServiceSettings{}, target, processes, syscall.SIGUSR1)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_dockerEnvironment_TaskProcesses(t *testing.T) {
+	execOptions := container.ExecOptions{
+		Cmd:          environment.ProcStatCommand,
+		AttachStdout: true,
+		AttachStderr: true,
+	}
+	tests := []struct {
+		name             string
+		setupMocks       func(*testing.T, context.Context, *dockerClientMocks.MockClient)
+		want             []environment.Process
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "successful listing",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{ID: "eid1"}, nil)
+				cli.On("ContainerExecAttach", ctx, "eid1", container.ExecAttachOptions{}).Return(
+					execAttachResponse(t, "uptime 30.00 5.00\nfd 1 4\n1 (php-fpm) S 0 1 1\nfd 7 3\n"+
+						"7 (php-fpm) S 1 1 1 0 -1 4194560 1000 0 0 0 0 0 0 0 20 0 1 0 1000 2703360 2 0\n"+
+						"9 (sh) S 0 9 9\n", ""), nil)
+				cli.On("ContainerExecInspect", ctx, "eid1").Return(container.ExecInspect{ExitCode: 0}, nil)
+			},
+			want: []environment.Process{
+				{Pid: 1, PPid: 0, Name: "php-fpm", Main: true, FDs: 4, FDsKnown: true},
+				{Pid: 7, PPid: 1, Name: "php-fpm", FDs: 3, FDsKnown: true, RSS: 2 * int64(os.Getpagesize()),
+					StartTime: 10 * time.Second, Uptime: 20 * time.Second, UptimeKnown: true},
+			},
+		},
+		{
+			name: "exec error",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{}, errors.New("create err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to create exec in container cn1: create err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientMock := dockerClientMocks.NewMockClient(t)
+			ctx := context.Background()
+			e := &dockerEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: appMocks.NewMockFoundation(t),
+					},
+				},
+				cli: clientMock,
+			}
+			target := &dockerTask{
+				containerName: "cn1",
+				containerId:   "cid1",
+			}
+
+			tt.setupMocks(t, ctx, clientMock)
+			got, err := e.TaskProcesses(ctx, &environment.ServiceSettings{}, target)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_dockerEnvironment_TaskFile(t *testing.T) {
+	execOptions := container.ExecOptions{
+		Cmd:          environment.FileStatCommand("/var/log/fpm.log", 0),
+		AttachStdout: true,
+		AttachStderr: true,
+	}
+	tests := []struct {
+		name             string
+		setupMocks       func(*testing.T, context.Context, *dockerClientMocks.MockClient)
+		want             *environment.File
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "existing file",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{ID: "eid1"}, nil)
+				cli.On("ContainerExecAttach", ctx, "eid1", container.ExecAttachOptions{}).Return(
+					execAttachResponse(t, "stat 81a4 9 www-data www-data\nslow log\n", ""), nil)
+				cli.On("ContainerExecInspect", ctx, "eid1").Return(container.ExecInspect{ExitCode: 0}, nil)
+			},
+			want: &environment.File{
+				Mode:    0644,
+				Size:    9,
+				Owner:   "www-data",
+				Group:   "www-data",
+				Content: []byte("slow log\n"),
+			},
+		},
+		{
+			name: "missing file",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{ID: "eid1"}, nil)
+				cli.On("ContainerExecAttach", ctx, "eid1", container.ExecAttachOptions{}).Return(
+					execAttachResponse(t, "missing\n", ""), nil)
+				cli.On("ContainerExecInspect", ctx, "eid1").Return(container.ExecInspect{ExitCode: 0}, nil)
+			},
+			want: nil,
+		},
+		{
+			name: "exec error",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{}, errors.New("create err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to create exec in container cn1: create err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientMock := dockerClientMocks.NewMockClient(t)
+			ctx := context.Background()
+			e := &dockerEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: appMocks.NewMockFoundation(t),
+					},
+				},
+				cli: clientMock,
+			}
+			target := &dockerTask{
+				containerName: "cn1",
+				containerId:   "cid1",
+			}
+
+			tt.setupMocks(t, ctx, clientMock)
+			got, err := e.TaskFile(ctx, &environment.ServiceSettings{}, target, "/var/log/fpm.log", 0)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_dockerEnvironment_FileExists(t *testing.T) {
+	tests := []struct {
+		name             string
+		setupMocks       func(*testing.T, context.Context, *dockerClientMocks.MockClient)
+		want             bool
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "file exists",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerStatPath", ctx, "cid1", "/var/log/slow.log").Return(container.PathStat{
+					Name: "slow.log",
+					Mode: 0644,
+				}, nil)
+			},
+			want: true,
+		},
+		{
+			name: "file does not exist",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerStatPath", ctx, "cid1", "/var/log/slow.log").Return(
+					container.PathStat{},
+					cerrdefs.ErrNotFound,
+				)
+			},
+			want: false,
+		},
+		{
+			name: "stat error",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerStatPath", ctx, "cid1", "/var/log/slow.log").Return(
+					container.PathStat{},
+					errors.New("stat err"),
+				)
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to check path /var/log/slow.log in container cn1: stat err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientMock := dockerClientMocks.NewMockClient(t)
+			ctx := context.Background()
+			e := &dockerEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: appMocks.NewMockFoundation(t),
+					},
+				},
+				cli: clientMock,
+			}
+			target := &dockerTask{
+				containerName: "cn1",
+				containerId:   "cid1",
+			}
+
+			tt.setupMocks(t, ctx, clientMock)
+			got, err := e.FileExists(ctx, &environment.ServiceSettings{}, target, "/var/log/slow.log")
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
 	}
-	cmd := &environment.Command{Name: "test"}
-	tsk := &dockerTask{}
-	oc := &output.BufferedCollector{}
-	err := env.ExecTaskCommand(ctx, ss, tsk, cmd, oc)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "executing command is not currently supported in Docker environment")
 }
 
-func Test_dockerEnvironment_ExecTaskSignal(t *testing.T) {
-	env := &dockerEnvironment{}
-	ctx := context.Background()
-	ss := &environment.ServiceSettings{
-		Name:       "svc",
-		FullName:   "mysvc",
-		Port:       8080,
-		ServerPort: 1234,
+func Test_dockerEnvironment_PortReady(t *testing.T) {
+	execOptions := container.ExecOptions{
+		Cmd:          environment.PortListenCommand,
+		AttachStdout: true,
+		AttachStderr: true,
+	}
+	procNetTcp := "  sl  local_address rem_address   st\n" +
+		"   0: 00000000:2328 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1\n"
+	tests := []struct {
+		name             string
+		setupMocks       func(*testing.T, context.Context, *dockerClientMocks.MockClient)
+		want             bool
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "port is listening",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{ID: "eid1"}, nil)
+				cli.On("ContainerExecAttach", ctx, "eid1", container.ExecAttachOptions{}).Return(
+					execAttachResponse(t, procNetTcp, ""), nil)
+				cli.On("ContainerExecInspect", ctx, "eid1").Return(container.ExecInspect{ExitCode: 0}, nil)
+			},
+			want: true,
+		},
+		{
+			name: "port is not listening",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{ID: "eid1"}, nil)
+				cli.On("ContainerExecAttach", ctx, "eid1", container.ExecAttachOptions{}).Return(
+					execAttachResponse(t, "  sl  local_address rem_address   st\n", ""), nil)
+				cli.On("ContainerExecInspect", ctx, "eid1").Return(container.ExecInspect{ExitCode: 0}, nil)
+			},
+			want: false,
+		},
+		{
+			name: "exec error",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerExecCreate", ctx, "cid1", execOptions).Return(
+					container.ExecCreateResponse{}, errors.New("create err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to create exec in container cn1: create err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientMock := dockerClientMocks.NewMockClient(t)
+			ctx := context.Background()
+			e := &dockerEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: appMocks.NewMockFoundation(t),
+					},
+				},
+				cli: clientMock,
+			}
+			target := &dockerTask{
+				containerName: "cn1",
+				containerId:   "cid1",
+			}
+
+			tt.setupMocks(t, ctx, clientMock)
+			got, err := e.PortReady(ctx, &environment.ServiceSettings{}, target, 9000)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_dockerEnvironment_UdsReady(t *testing.T) {
+	tests := []struct {
+		name             string
+		setupMocks       func(*testing.T, context.Context, *dockerClientMocks.MockClient)
+		want             bool
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "socket exists",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerStatPath", ctx, "cid1", "/run/fpm.sock").Return(container.PathStat{
+					Name: "fpm.sock",
+					Mode: os.ModeSocket | 0666,
+				}, nil)
+			},
+			want: true,
+		},
+		{
+			name: "path is not a socket",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerStatPath", ctx, "cid1", "/run/fpm.sock").Return(container.PathStat{
+					Name: "fpm.sock",
+					Mode: 0644,
+				}, nil)
+			},
+			want: false,
+		},
+		{
+			name: "socket does not exist",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerStatPath", ctx, "cid1", "/run/fpm.sock").Return(
+					container.PathStat{},
+					cerrdefs.ErrNotFound,
+				)
+			},
+			want: false,
+		},
+		{
+			name: "stat error",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerStatPath", ctx, "cid1", "/run/fpm.sock").Return(
+					container.PathStat{},
+					errors.New("stat err"),
+				)
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to check path /run/fpm.sock in container cn1: stat err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientMock := dockerClientMocks.NewMockClient(t)
+			ctx := context.Background()
+			e := &dockerEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: appMocks.NewMockFoundation(t),
+					},
+				},
+				cli: clientMock,
+			}
+			target := &dockerTask{
+				containerName: "cn1",
+				containerId:   "cid1",
+			}
+
+			tt.setupMocks(t, ctx, clientMock)
+			got, err := e.UdsReady(ctx, &environment.ServiceSettings{}, target, "/run/fpm.sock")
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_dockerEnvironment_TaskRunning(t *testing.T) {
+	tests := []struct {
+		name             string
+		setupMocks       func(*testing.T, context.Context, *dockerClientMocks.MockClient)
+		want             bool
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "container is running",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerInspect", ctx, "cid1").Return(apitypes.ContainerJSON{
+					ContainerJSONBase: &apitypes.ContainerJSONBase{
+						State: &apitypes.ContainerState{
+							Running: true,
+						},
+					},
+				}, nil)
+			},
+			want: true,
+		},
+		{
+			name: "container has exited",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerInspect", ctx, "cid1").Return(apitypes.ContainerJSON{
+					ContainerJSONBase: &apitypes.ContainerJSONBase{
+						State: &apitypes.ContainerState{
+							Running: false,
+						},
+					},
+				}, nil)
+			},
+			want: false,
+		},
+		{
+			name: "inspect error",
+			setupMocks: func(t *testing.T, ctx context.Context, cli *dockerClientMocks.MockClient) {
+				cli.On("ContainerInspect", ctx, "cid1").Return(apitypes.ContainerJSON{}, errors.New("inspect err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to inspect container: inspect err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientMock := dockerClientMocks.NewMockClient(t)
+			ctx := context.Background()
+			e := &dockerEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: appMocks.NewMockFoundation(t),
+					},
+				},
+				cli: clientMock,
+			}
+			target := &dockerTask{
+				containerName: "cn1",
+				containerId:   "cid1",
+			}
+
+			tt.setupMocks(t, ctx, clientMock)
+			got, err := e.TaskRunning(ctx, &environment.ServiceSettings{}, target)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
 	}
-	tsk := &dockerTask{}
-	err := env.ExecTaskSignal(ctx, ss, tsk, os.Interrupt)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "executing signal is not currently supported in Kubernetes environment")
 }
 
 func Test_dockerEnvironment_Output(t *testing.T) {
@@ -2127,6 +2859,110 @@ func Test_dockerEnvironment_Output(t *testing.T) {
 	}
 }
 
+func Test_dockerEnvironment_OutputSince(t *testing.T) {
+	since := time.Date(2024, 5, 1, 10, 20, 30, 123000000, time.UTC)
+	tests := []struct {
+		name       string
+		outputType output.Type
+		setupMocks func(
+			*testing.T,
+			context.Context,
+			*appMocks.MockFoundation,
+			*dockerClientMocks.MockClient,
+		) io.Reader
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name:       "successful output since time for stdout type",
+			outputType: output.Stdout,
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				fnd *appMocks.MockFoundation,
+				cli *dockerClientMocks.MockClient,
+			) io.Reader {
+				reader := &pullReaderCloser{
+					msg: "data",
+				}
+				fnd.On("DryRun").Return(false)
+				cli.On("ContainerLogs", ctx, "cid1", container.LogsOptions{
+					ShowStdout: true,
+					ShowStderr: false,
+					Since:      "2024-05-01T10:20:30.123Z",
+					Follow:     true,
+				}).Return(reader, nil)
+				return reader
+			},
+		},
+		{
+			name:       "successful output since time with dry run",
+			outputType: output.Any,
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				fnd *appMocks.MockFoundation,
+				cli *dockerClientMocks.MockClient,
+			) io.Reader {
+				fnd.On("DryRun").Return(true)
+				return &app.DummyReaderCloser{}
+			},
+		},
+		{
+			name:       "failed output since time on container logs",
+			outputType: output.Any,
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				fnd *appMocks.MockFoundation,
+				cli *dockerClientMocks.MockClient,
+			) io.Reader {
+				fnd.On("DryRun").Return(false)
+				cli.On("ContainerLogs", ctx, "cid1", container.LogsOptions{
+					ShowStdout: true,
+					ShowStderr: true,
+					Since:      "2024-05-01T10:20:30.123Z",
+					Follow:     true,
+				}).Return(nil, errors.New("log err"))
+				return nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to get container logs: log err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			clientMock := dockerClientMocks.NewMockClient(t)
+			ctx := context.Background()
+			e := &dockerEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: fndMock,
+					},
+				},
+				cli: clientMock,
+			}
+			target := &dockerTask{
+				containerName: "cn1",
+				containerId:   "cid1",
+			}
+
+			expectedReader := tt.setupMocks(t, ctx, fndMock, clientMock)
+			actualReader, err := e.OutputSince(ctx, target, tt.outputType, since)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, expectedReader, actualReader)
+			}
+		})
+	}
+}
+
 func Test_dockerEnvironment_RootPath(t *testing.T) {
 	env := &dockerEnvironment{}
 	assert.Equal(t, "", env.RootPath("/www/ws"))
@@ -24,9 +24,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
 	clientappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
 	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/remotecommand"
 )
 
 type Maker interface {
@@ -42,6 +45,7 @@ func CreateMaker(fnd app.Foundation) Maker {
 
 type nativeMaker struct {
 	fnd            app.Foundation
+	restConfig     *rest.Config
 	clientSet      *kubernetes.Clientset
 	clientSetError error
 }
@@ -53,6 +57,7 @@ func (m *nativeMaker) getClientSet(kubeconfigPath string) (*kubernetes.Clientset
 			m.clientSetError = err
 		} else {
 			// Create a clientset for interacting with the Kubernetes API
+			m.restConfig = kubeConfig
 			m.clientSet, m.clientSetError = kubernetes.NewForConfig(kubeConfig)
 		}
 
@@ -101,6 +106,7 @@ type DeploymentClient interface {
 }
 
 type PodClient interface {
+	Exec(ctx context.Context, name string, opts *corev1.PodExecOptions, stdout, stderr io.Writer) error
 	StreamLogs(ctx context.Context, name string, opts *corev1.PodLogOptions) (io.ReadCloser, error)
 	List(ctx context.Context, opts metav1.ListOptions) (*corev1.PodList, error)
 }
@@ -217,6 +223,32 @@ func (p *podClient) getClient() (clientcorev1.PodInterface, error) {
 	return p.client, nil
 }
 
+func (p *podClient) Exec(
+	ctx context.Context,
+	name string,
+	opts *corev1.PodExecOptions,
+	stdout, stderr io.Writer,
+) error {
+	clientSet, err := p.getConfigSet()
+	if err != nil {
+		return err
+	}
+	req := clientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(name).
+		Namespace(p.namespace).
+		SubResource("exec").
+		VersionedParams(opts, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(p.maker.restConfig, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+}
+
 func (p *podClient) StreamLogs(ctx context.Context, name string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
 	client, err := p.getClient()
 	if err != nil {
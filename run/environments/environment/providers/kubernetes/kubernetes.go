@@ -15,6 +15,7 @@
 package kubernetes
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"github.com/pkg/errors"
@@ -35,8 +36,11 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Maker interface {
@@ -578,6 +582,48 @@ func (e *kubernetesEnvironment) RunTask(ctx context.Context, ss *environment.Ser
 	return kubeTask, nil
 }
 
+// exec runs the command in the task container of the pod and copies its output to the supplied writers.
+func (e *kubernetesEnvironment) exec(
+	ctx context.Context,
+	target task.Task,
+	pod *corev1.Pod,
+	command []string,
+	stdout, stderr io.Writer,
+) error {
+	err := e.podClient.Exec(ctx, pod.Name, &corev1.PodExecOptions{
+		Container: target.Name(),
+		Command:   command,
+		Stdout:    true,
+		Stderr:    true,
+	}, stdout, stderr)
+	if err != nil {
+		return errors.Errorf("failed to execute command %s in pod %s: %v", command[0], pod.Name, err)
+	}
+	return nil
+}
+
+// execAll runs the command in all running pods of the task.
+func (e *kubernetesEnvironment) execAll(ctx context.Context, target task.Task, command []string, oc output.Collector) error {
+	pods, err := e.runningPods(ctx, target)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return errors.Errorf("no running pod found for %s", target.Name())
+	}
+	stdout, stderr := io.Discard, io.Discard
+	if oc != nil && !reflect.ValueOf(oc).IsNil() {
+		stdout = oc.StdoutWriter()
+		stderr = oc.StderrWriter()
+	}
+	for i := range pods {
+		if err = e.exec(ctx, target, &pods[i], command, stdout, stderr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (e *kubernetesEnvironment) ExecTaskCommand(
 	ctx context.Context,
 	ss *environment.ServiceSettings,
@@ -585,11 +631,226 @@ func (e *kubernetesEnvironment) ExecTaskCommand(
 	cmd *environment.Command,
 	oc output.Collector,
 ) error {
-	return errors.Errorf("executing command is not currently supported in Kubernetes environment")
+	if e.Fnd.DryRun() {
+		return nil
+	}
+	return e.execAll(ctx, target, append([]string{cmd.Name}, cmd.Args...), oc)
 }
 
+// ExecTaskSignal sends the signal to the main process of all running pods.
 func (e *kubernetesEnvironment) ExecTaskSignal(ctx context.Context, ss *environment.ServiceSettings, target task.Task, signal os.Signal) error {
-	return errors.Errorf("executing signal is not currently supported in Kubernetes environment")
+	if e.Fnd.DryRun() {
+		return nil
+	}
+	signalNumber, err := environment.SignalNumber(signal)
+	if err != nil {
+		return err
+	}
+	return e.execAll(ctx, target, []string{"kill", fmt.Sprintf("-%d", signalNumber), strconv.Itoa(target.Pid())}, nil)
+}
+
+// ExecTaskProcessesSignal sends the signal to the supplied processes in the pods that they run in.
+func (e *kubernetesEnvironment) ExecTaskProcessesSignal(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	processes []environment.Process,
+	signal os.Signal,
+) error {
+	if e.Fnd.DryRun() {
+		return nil
+	}
+	pods, err := e.runningPods(ctx, target)
+	if err != nil {
+		return err
+	}
+	podPids := make(map[string][]int)
+	for _, process := range processes {
+		podPids[process.Instance] = append(podPids[process.Instance], process.Pid)
+	}
+	for i := range pods {
+		pids, ok := podPids[pods[i].Name]
+		if !ok {
+			continue
+		}
+		delete(podPids, pods[i].Name)
+		command, err := environment.SignalCommand(signal, pids)
+		if err != nil {
+			return err
+		}
+		if err = e.exec(ctx, target, &pods[i], command, io.Discard, io.Discard); err != nil {
+			return err
+		}
+	}
+	if len(podPids) > 0 {
+		return errors.Errorf("some signaled processes of %s are in pods that are not running", target.Name())
+	}
+	return nil
+}
+
+func (e *kubernetesEnvironment) runningPods(ctx context.Context, target task.Task) ([]corev1.Pod, error) {
+	pods, err := e.podClient.List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=%s", target.Name()),
+	})
+	if err != nil {
+		return nil, errors.Errorf("failed to list pods: %v", err)
+	}
+	runningPods := make([]corev1.Pod, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			runningPods = append(runningPods, pod)
+		}
+	}
+	return runningPods, nil
+}
+
+// checkPods returns true only if there is at least one running pod and the check passes for all running pods.
+func (e *kubernetesEnvironment) checkPods(
+	ctx context.Context,
+	target task.Task,
+	check func(pod *corev1.Pod) (bool, error),
+) (bool, error) {
+	pods, err := e.runningPods(ctx, target)
+	if err != nil {
+		return false, err
+	}
+	if len(pods) == 0 {
+		return false, nil
+	}
+	for i := range pods {
+		ok, err := check(&pods[i])
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (e *kubernetesEnvironment) testPath(
+	ctx context.Context,
+	target task.Task,
+	pod *corev1.Pod,
+	testFlag string,
+	path string,
+) (bool, error) {
+	var stdout, stderr bytes.Buffer
+	err := e.podClient.Exec(ctx, pod.Name, &corev1.PodExecOptions{
+		Container: target.Name(),
+		Command:   []string{"sh", "-c", `if test "$1" "$2"; then echo 1; else echo 0; fi`, "sh", testFlag, path},
+		Stdout:    true,
+		Stderr:    true,
+	}, &stdout, &stderr)
+	if err != nil {
+		return false, errors.Errorf("failed to check path %s in pod %s: %v", path, pod.Name, err)
+	}
+	return strings.TrimSpace(stdout.String()) == "1", nil
+}
+
+func (e *kubernetesEnvironment) FileExists(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	path string,
+) (bool, error) {
+	return e.checkPods(ctx, target, func(pod *corev1.Pod) (bool, error) {
+		return e.testPath(ctx, target, pod, "-e", path)
+	})
+}
+
+// PortReady checks that the port is listening in all pods as the pod addresses are usually not reachable from the host.
+func (e *kubernetesEnvironment) PortReady(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	port int32,
+) (bool, error) {
+	return e.checkPods(ctx, target, func(pod *corev1.Pod) (bool, error) {
+		var stdout bytes.Buffer
+		if err := e.exec(ctx, target, pod, environment.PortListenCommand, &stdout, io.Discard); err != nil {
+			return false, err
+		}
+		return environment.ParsePortListening(stdout.String(), port), nil
+	})
+}
+
+// UdsReady checks that the socket file exists in all pods as it is not reachable from the host.
+func (e *kubernetesEnvironment) UdsReady(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	path string,
+) (bool, error) {
+	return e.checkPods(ctx, target, func(pod *corev1.Pod) (bool, error) {
+		return e.testPath(ctx, target, pod, "-S", path)
+	})
+}
+
+func (e *kubernetesEnvironment) TaskRunning(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+) (bool, error) {
+	pods, err := e.runningPods(ctx, target)
+	if err != nil {
+		return false, err
+	}
+	return len(pods) > 0, nil
+}
+
+// TaskProcesses returns the main process and all its descendants of all running pods. Each process is tagged with the
+// name of its pod.
+func (e *kubernetesEnvironment) TaskProcesses(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+) ([]environment.Process, error) {
+	pods, err := e.runningPods(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return nil, errors.Errorf("no running pod found for %s", target.Name())
+	}
+	var processes []environment.Process
+	for i := range pods {
+		var stdout bytes.Buffer
+		if err = e.exec(ctx, target, &pods[i], environment.ProcStatCommand, &stdout, io.Discard); err != nil {
+			return nil, err
+		}
+		podProcesses, err := environment.ParseProcStats(&stdout)
+		if err != nil {
+			return nil, err
+		}
+		tree := environment.ProcessTree(podProcesses, target.Pid())
+		for j := range tree {
+			tree[j].Instance = pods[i].Name
+		}
+		processes = append(processes, tree...)
+	}
+	return processes, nil
+}
+
+// TaskFile returns the file stat and content read from the first running pod. It returns nil if the file does not
+// exist.
+func (e *kubernetesEnvironment) TaskFile(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	path string,
+	offset int64,
+) (*environment.File, error) {
+	pods, err := e.runningPods(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return nil, errors.Errorf("no running pod found for %s", target.Name())
+	}
+	var stdout bytes.Buffer
+	if err = e.exec(ctx, target, &pods[0], environment.FileStatCommand(path, offset), &stdout, io.Discard); err != nil {
+		return nil, err
+	}
+	return environment.ParseFileStat(stdout.Bytes())
 }
 
 func (e *kubernetesEnvironment) Output(ctx context.Context, target task.Task, outputType output.Type) (io.Reader, error) {
@@ -631,6 +892,49 @@ func (e *kubernetesEnvironment) Output(ctx context.Context, target task.Task, ou
 	return combinedReader, nil
 }
 
+// OutputSince returns a new reader of the pods logs since the time. The time precision is limited to seconds.
+func (e *kubernetesEnvironment) OutputSince(
+	ctx context.Context,
+	target task.Task,
+	outputType output.Type,
+	since time.Time,
+) (io.Reader, error) {
+	if outputType != output.Any {
+		return nil, errors.Errorf("only any output type is supported by Kubernetes environment")
+	}
+	kubeTask, ok := target.(*kubernetesTask)
+	if !ok {
+		return nil, errors.Errorf("task in not a Kubernetes task")
+	}
+
+	if e.Fnd.DryRun() {
+		return &CombinedReader{readers: []io.ReadCloser{&app.DummyReaderCloser{}}}, nil
+	}
+
+	pods, err := e.podClient.List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=%s", kubeTask.Name()),
+	})
+	if err != nil {
+		return nil, errors.Errorf("failed to list pods: %v", err)
+	}
+
+	combinedReader := &CombinedReader{readers: make([]io.ReadCloser, 0, len(pods.Items))}
+	sinceTime := metav1.NewTime(since)
+	for _, pod := range pods.Items {
+		podLogs, err := e.podClient.StreamLogs(ctx, pod.Name, &corev1.PodLogOptions{
+			Follow:    true,
+			SinceTime: &sinceTime,
+		})
+		if err != nil {
+			combinedReader.Close()
+			return nil, errors.Errorf("error in opening stream: %v", err)
+		}
+		combinedReader.readers = append(combinedReader.readers, podLogs)
+	}
+
+	return combinedReader, nil
+}
+
 func (e *kubernetesEnvironment) RootPath(workspace string) string {
 	return ""
 }
@@ -1,7 +1,9 @@
 package kubernetes
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
@@ -11,6 +13,7 @@ import (
 	"github.com/wstool/wst/conf/types"
 	"github.com/wstool/wst/mocks/authored/external"
 	appMocks "github.com/wstool/wst/mocks/generated/app"
+	outputMocks "github.com/wstool/wst/mocks/generated/run/environments/environment/output"
 	k8sClientMocks "github.com/wstool/wst/mocks/generated/run/environments/environment/providers/kubernetes/clients"
 	resourcesMocks "github.com/wstool/wst/mocks/generated/run/resources"
 	certificatesMocks "github.com/wstool/wst/mocks/generated/run/resources/certificates"
@@ -31,7 +34,9 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	"os"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 )
 
 func TestCreateMaker(t *testing.T) {
@@ -2470,36 +2475,451 @@ func Test_kubernetesEnvironment_RunTask(t *testing.T) {
 	}
 }
 
+func mockExec(
+	pc *k8sClientMocks.MockPodClient,
+	ctx context.Context,
+	pod string,
+	command []string,
+	stdout, stderr string,
+	err error,
+) {
+	pc.On("Exec", ctx, pod, &corev1.PodExecOptions{
+		Container: "sn1",
+		Command:   command,
+		Stdout:    true,
+		Stderr:    true,
+	}, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		_, _ = args.Get(3).(io.Writer).Write([]byte(stdout))
+		_, _ = args.Get(4).(io.Writer).Write([]byte(stderr))
+	}).Return(err)
+}
+
 func Test_kubernetesEnvironment_ExecTaskCommand(t *testing.T) {
-	env := &kubernetesEnvironment{}
-	ctx := context.Background()
-	ss := &environment.ServiceSettings{
-		Name:       "svc",
-		FullName:   "mysvc",
-		Port:       8080,
-		ServerPort: 1234,
+	command := []string{"kill", "-10", "12"}
+	tests := []struct {
+		name             string
+		dryRun           bool
+		setupMocks       func(*testing.T, context.Context, *k8sClientMocks.MockPodClient)
+		expectedStdout   string
+		expectedStderr   string
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "successful execution in all pods",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1"), runningPod("p2", "10.0.0.2")},
+				}, nil)
+				mockExec(pc, ctx, "p1", command, "out1", "err1", nil)
+				mockExec(pc, ctx, "p2", command, "out2", "", nil)
+			},
+			expectedStdout: "out1out2",
+			expectedStderr: "err1",
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+			},
+		},
+		{
+			name: "no running pods",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{}, nil)
+			},
+			expectError:      true,
+			expectedErrorMsg: "no running pod found for sn1",
+		},
+		{
+			name: "list error",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(nil, errors.New("list err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to list pods: list err",
+		},
+		{
+			name: "exec error",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1")},
+				}, nil)
+				mockExec(pc, ctx, "p1", command, "", "no such process", errors.New("exit code 1"))
+			},
+			expectedStderr:   "no such process",
+			expectError:      true,
+			expectedErrorMsg: "failed to execute command kill in pod p1: exit code 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			fndMock.On("DryRun").Return(tt.dryRun)
+			podClientMock := k8sClientMocks.NewMockPodClient(t)
+			ctx := context.Background()
+			e := &kubernetesEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: fndMock,
+					},
+				},
+				podClient: podClientMock,
+			}
+			target := &kubernetesTask{serviceName: "sn1"}
+			var stdout, stderr bytes.Buffer
+			oc := outputMocks.NewMockCollector(t)
+			oc.On("StdoutWriter").Return(&stdout).Maybe()
+			oc.On("StderrWriter").Return(&stderr).Maybe()
+
+			tt.setupMocks(t, ctx, podClientMock)
+			cmd := &environment.Command{Name: "kill", Args: []string{"-10", "12"}}
+			err := e.ExecTaskCommand(ctx, &environment.ServiceSettings{}, target, cmd, oc)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedStdout, stdout.String())
+			assert.Equal(t, tt.expectedStderr, stderr.String())
+		})
 	}
-	cmd := &environment.Command{Name: "test"}
-	tsk := &kubernetesTask{}
-	oc := &output.BufferedCollector{}
-	err := env.ExecTaskCommand(ctx, ss, tsk, cmd, oc)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "executing command is not currently supported in Kubernetes environment")
 }
 
+type testSignal struct{}
+
+func (s testSignal) String() string { return "test" }
+func (s testSignal) Signal()        {}
+
 func Test_kubernetesEnvironment_ExecTaskSignal(t *testing.T) {
-	env := &kubernetesEnvironment{}
-	ctx := context.Background()
-	ss := &environment.ServiceSettings{
-		Name:       "svc",
-		FullName:   "mysvc",
-		Port:       8080,
-		ServerPort: 1234,
+	tests := []struct {
+		name             string
+		dryRun           bool
+		signal           os.Signal
+		setupMocks       func(*testing.T, context.Context, *k8sClientMocks.MockPodClient)
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name:   "successful signal to all pods",
+			signal: syscall.SIGUSR2,
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1"), runningPod("p2", "10.0.0.2")},
+				}, nil)
+				command := []string{"kill", fmt.Sprintf("-%d", int(syscall.SIGUSR2)), "1"}
+				mockExec(pc, ctx, "p1", command, "", "", nil)
+				mockExec(pc, ctx, "p2", command, "", "", nil)
+			},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			signal: syscall.SIGUSR2,
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+			},
+		},
+		{
+			name:   "unsupported signal",
+			signal: testSignal{},
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+			},
+			expectError:      true,
+			expectedErrorMsg: "unsupported signal test",
+		},
+		{
+			name:   "exec error",
+			signal: syscall.SIGTERM,
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1")},
+				}, nil)
+				command := []string{"kill", fmt.Sprintf("-%d", int(syscall.SIGTERM)), "1"}
+				mockExec(pc, ctx, "p1", command, "", "", errors.New("exec err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to execute command kill in pod p1: exec err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			fndMock.On("DryRun").Return(tt.dryRun)
+			podClientMock := k8sClientMocks.NewMockPodClient(t)
+			ctx := context.Background()
+			e := &kubernetesEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: fndMock,
+					},
+				},
+				podClient: podClientMock,
+			}
+			target := &kubernetesTask{serviceName: "sn1"}
+
+			tt.setupMocks(t, ctx, podClientMock)
+			err := e.ExecTaskSignal(ctx, &environment.ServiceSettings{}, target, tt.signal)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_kubernetesEnvironment_ExecTaskProcessesSignal(t *testing.T) {
+	processes := []environment.Process{
+		{Pid: 7, PPid: 1, Name: "php-fpm", Instance: "p1"},
+		{Pid: 8, PPid: 1, Name: "php-fpm", Instance: "p1"},
+		{Pid: 7, PPid: 1, Name: "php-fpm", Instance: "p2"},
+	}
+	tests := []struct {
+		name             string
+		dryRun           bool
+		setupMocks       func(*testing.T, context.Context, *k8sClientMocks.MockPodClient)
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "successful signal to processes in their pods",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1"), runningPod("p2", "10.0.0.2")},
+				}, nil)
+				mockExec(pc, ctx, "p1", []string{"kill", "-10", "7", "8"}, "", "", nil)
+				mockExec(pc, ctx, "p2", []string{"kill", "-10", "7"}, "", "", nil)
+			},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+			},
+		},
+		{
+			name: "pod of processes not running",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1")},
+				}, nil)
+				mockExec(pc, ctx, "p1", []string{"kill", "-10", "7", "8"}, "", "", nil)
+			},
+			expectError:      true,
+			expectedErrorMsg: "some signaled processes of sn1 are in pods that are not running",
+		},
+		{
+			name: "exec error",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1"), runningPod("p2", "10.0.0.2")},
+				}, nil)
+				mockExec(pc, ctx, "p1", []string{"kill", "-10", "7", "8"}, "", "", errors.New("exec err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to execute command kill in pod p1: exec err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			fndMock.On("DryRun").Return(tt.dryRun)
+			podClientMock := k8sClientMocks.NewMockPodClient(t)
+			ctx := context.Background()
+			e := &kubernetesEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: fndMock,
+					},
+				},
+				podClient: podClientMock,
+			}
+			target := &kubernetesTask{serviceName: "sn1"}
+
+			tt.setupMocks(t, ctx, podClientMock)
+			err := e.ExecTaskProcessesSignal(ctx, &environment.ServiceSettings{}, target, processes, syscall.SIGUSR1)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_kubernetesEnvironment_TaskProcesses(t *testing.T) {
+	tests := []struct {
+		name             string
+		setupMocks       func(*testing.T, context.Context, *k8sClientMocks.MockPodClient)
+		want             []environment.Process
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "processes from all running pods",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1"), runningPod("p2", "10.0.0.2")},
+				}, nil)
+				mockExec(pc, ctx, "p1", environment.ProcStatCommand,
+					"1 (php-fpm) S 0 1 1\n7 (php-fpm) S 1 1 1\n8 (php-fpm) S 1 1 1\n", "", nil)
+				mockExec(pc, ctx, "p2", environment.ProcStatCommand,
+					"1 (php-fpm) S 0 1 1\n7 (php-fpm) S 1 1 1\n", "", nil)
+			},
+			want: []environment.Process{
+				{Pid: 1, PPid: 0, Name: "php-fpm", Instance: "p1", Main: true},
+				{Pid: 7, PPid: 1, Name: "php-fpm", Instance: "p1"},
+				{Pid: 8, PPid: 1, Name: "php-fpm", Instance: "p1"},
+				{Pid: 1, PPid: 0, Name: "php-fpm", Instance: "p2", Main: true},
+				{Pid: 7, PPid: 1, Name: "php-fpm", Instance: "p2"},
+			},
+		},
+		{
+			name: "no running pods",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{}, nil)
+			},
+			expectError:      true,
+			expectedErrorMsg: "no running pod found for sn1",
+		},
+		{
+			name: "list error",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(nil, errors.New("list err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to list pods: list err",
+		},
+		{
+			name: "exec error",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1")},
+				}, nil)
+				mockExec(pc, ctx, "p1", environment.ProcStatCommand, "", "", errors.New("exec err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to execute command sh in pod p1: exec err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podClientMock := k8sClientMocks.NewMockPodClient(t)
+			ctx := context.Background()
+			e := &kubernetesEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: appMocks.NewMockFoundation(t),
+					},
+				},
+				podClient: podClientMock,
+			}
+			target := &kubernetesTask{serviceName: "sn1"}
+
+			tt.setupMocks(t, ctx, podClientMock)
+			got, err := e.TaskProcesses(ctx, &environment.ServiceSettings{}, target)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_kubernetesEnvironment_TaskFile(t *testing.T) {
+	command := environment.FileStatCommand("/var/run/fpm.sock", 0)
+	tests := []struct {
+		name             string
+		setupMocks       func(*testing.T, context.Context, *k8sClientMocks.MockPodClient)
+		want             *environment.File
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "file from first running pod",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1"), runningPod("p2", "10.0.0.2")},
+				}, nil)
+				mockExec(pc, ctx, "p1", command, "stat c1b0 0 www-data www-data\n", "", nil)
+			},
+			want: &environment.File{
+				Mode:  os.ModeSocket | 0660,
+				Owner: "www-data",
+				Group: "www-data",
+			},
+		},
+		{
+			name: "missing file",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1")},
+				}, nil)
+				mockExec(pc, ctx, "p1", command, "missing\n", "", nil)
+			},
+			want: nil,
+		},
+		{
+			name: "no running pods",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{}, nil)
+			},
+			expectError:      true,
+			expectedErrorMsg: "no running pod found for sn1",
+		},
+		{
+			name: "exec error",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1")},
+				}, nil)
+				mockExec(pc, ctx, "p1", command, "", "", errors.New("exec err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to execute command sh in pod p1: exec err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podClientMock := k8sClientMocks.NewMockPodClient(t)
+			ctx := context.Background()
+			e := &kubernetesEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: appMocks.NewMockFoundation(t),
+					},
+				},
+				podClient: podClientMock,
+			}
+			target := &kubernetesTask{serviceName: "sn1"}
+
+			tt.setupMocks(t, ctx, podClientMock)
+			got, err := e.TaskFile(ctx, &environment.ServiceSettings{}, target, "/var/run/fpm.sock", 0)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
 	}
-	tsk := &kubernetesTask{}
-	err := env.ExecTaskSignal(ctx, ss, tsk, os.Interrupt)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "executing signal is not currently supported in Kubernetes environment")
 }
 
 type pullReaderCloser struct {
@@ -2553,6 +2973,290 @@ func (t *invalidTask) PrivateUrl(scheme string) string {
 	return scheme
 }
 
+func runningPod(name, ip string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			PodIP: ip,
+		},
+	}
+}
+
+func mockPathTest(pc *k8sClientMocks.MockPodClient, ctx context.Context, pod, flag, path, result string, err error) {
+	pc.On("Exec", ctx, pod, &corev1.PodExecOptions{
+		Container: "sn1",
+		Command:   []string{"sh", "-c", `if test "$1" "$2"; then echo 1; else echo 0; fi`, "sh", flag, path},
+		Stdout:    true,
+		Stderr:    true,
+	}, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		_, _ = args.Get(3).(io.Writer).Write([]byte(result))
+	}).Return(err)
+}
+
+func Test_kubernetesEnvironment_FileExists(t *testing.T) {
+	tests := []struct {
+		name             string
+		setupMocks       func(*testing.T, context.Context, *k8sClientMocks.MockPodClient)
+		want             bool
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "file exists in all pods",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1"), runningPod("p2", "10.0.0.2")},
+				}, nil)
+				mockPathTest(pc, ctx, "p1", "-e", "/var/log/slow.log", "1\n", nil)
+				mockPathTest(pc, ctx, "p2", "-e", "/var/log/slow.log", "1\n", nil)
+			},
+			want: true,
+		},
+		{
+			name: "file missing in one pod",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1"), runningPod("p2", "10.0.0.2")},
+				}, nil)
+				mockPathTest(pc, ctx, "p1", "-e", "/var/log/slow.log", "0\n", nil)
+			},
+			want: false,
+		},
+		{
+			name: "no running pods",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pending := runningPod("p1", "")
+				pending.Status.Phase = corev1.PodPending
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{pending},
+				}, nil)
+			},
+			want: false,
+		},
+		{
+			name: "exec error",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1")},
+				}, nil)
+				mockPathTest(pc, ctx, "p1", "-e", "/var/log/slow.log", "", errors.New("exec err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to check path /var/log/slow.log in pod p1: exec err",
+		},
+		{
+			name: "list error",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(nil, errors.New("list err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to list pods: list err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podClientMock := k8sClientMocks.NewMockPodClient(t)
+			ctx := context.Background()
+			e := &kubernetesEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: appMocks.NewMockFoundation(t),
+					},
+				},
+				podClient: podClientMock,
+			}
+			target := &kubernetesTask{serviceName: "sn1"}
+
+			tt.setupMocks(t, ctx, podClientMock)
+			got, err := e.FileExists(ctx, &environment.ServiceSettings{}, target, "/var/log/slow.log")
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_kubernetesEnvironment_PortReady(t *testing.T) {
+	procNetTcp := "  sl  local_address rem_address   st\n" +
+		"   0: 00000000:2328 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1\n"
+	tests := []struct {
+		name       string
+		setupMocks func(*testing.T, context.Context, *k8sClientMocks.MockPodClient)
+		want       bool
+	}{
+		{
+			name: "port is listening in all pods",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1"), runningPod("p2", "10.0.0.2")},
+				}, nil)
+				mockExec(pc, ctx, "p1", environment.PortListenCommand, procNetTcp, "", nil)
+				mockExec(pc, ctx, "p2", environment.PortListenCommand, procNetTcp, "", nil)
+			},
+			want: true,
+		},
+		{
+			name: "port is not listening in one pod",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1"), runningPod("p2", "10.0.0.2")},
+				}, nil)
+				mockExec(pc, ctx, "p1", environment.PortListenCommand, procNetTcp, "", nil)
+				mockExec(pc, ctx, "p2", environment.PortListenCommand, "  sl  local_address rem_address   st\n", "", nil)
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podClientMock := k8sClientMocks.NewMockPodClient(t)
+			ctx := context.Background()
+			e := &kubernetesEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: appMocks.NewMockFoundation(t),
+					},
+				},
+				podClient: podClientMock,
+			}
+			target := &kubernetesTask{serviceName: "sn1"}
+
+			tt.setupMocks(t, ctx, podClientMock)
+			got, err := e.PortReady(ctx, &environment.ServiceSettings{}, target, 9000)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_kubernetesEnvironment_UdsReady(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMocks func(*testing.T, context.Context, *k8sClientMocks.MockPodClient)
+		want       bool
+	}{
+		{
+			name: "socket exists",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1")},
+				}, nil)
+				mockPathTest(pc, ctx, "p1", "-S", "/run/fpm.sock", "1\n", nil)
+			},
+			want: true,
+		},
+		{
+			name: "socket does not exist",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1")},
+				}, nil)
+				mockPathTest(pc, ctx, "p1", "-S", "/run/fpm.sock", "0\n", nil)
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podClientMock := k8sClientMocks.NewMockPodClient(t)
+			ctx := context.Background()
+			e := &kubernetesEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: appMocks.NewMockFoundation(t),
+					},
+				},
+				podClient: podClientMock,
+			}
+			target := &kubernetesTask{serviceName: "sn1"}
+
+			tt.setupMocks(t, ctx, podClientMock)
+			got, err := e.UdsReady(ctx, &environment.ServiceSettings{}, target, "/run/fpm.sock")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_kubernetesEnvironment_TaskRunning(t *testing.T) {
+	tests := []struct {
+		name             string
+		setupMocks       func(*testing.T, context.Context, *k8sClientMocks.MockPodClient)
+		want             bool
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "pod is running",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{runningPod("p1", "10.0.0.1")},
+				}, nil)
+			},
+			want: true,
+		},
+		{
+			name: "no pod is running",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				failed := runningPod("p1", "10.0.0.1")
+				failed.Status.Phase = corev1.PodFailed
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{failed},
+				}, nil)
+			},
+			want: false,
+		},
+		{
+			name: "list error",
+			setupMocks: func(t *testing.T, ctx context.Context, pc *k8sClientMocks.MockPodClient) {
+				pc.On("List", ctx, metav1.ListOptions{LabelSelector: "app=sn1"}).Return(nil, errors.New("list err"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to list pods: list err",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podClientMock := k8sClientMocks.NewMockPodClient(t)
+			ctx := context.Background()
+			e := &kubernetesEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: appMocks.NewMockFoundation(t),
+					},
+				},
+				podClient: podClientMock,
+			}
+			target := &kubernetesTask{serviceName: "sn1"}
+
+			tt.setupMocks(t, ctx, podClientMock)
+			got, err := e.TaskRunning(ctx, &environment.ServiceSettings{}, target)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
 func Test_kubernetesEnvironment_Output(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -2721,6 +3425,163 @@ func Test_kubernetesEnvironment_Output(t *testing.T) {
 	}
 }
 
+func Test_kubernetesEnvironment_OutputSince(t *testing.T) {
+	since := time.Date(2024, 5, 1, 10, 20, 30, 0, time.UTC)
+	sinceTime := metav1.NewTime(since)
+	tests := []struct {
+		name       string
+		outputType output.Type
+		target     task.Task
+		setupMocks func(
+			*testing.T,
+			context.Context,
+			*appMocks.MockFoundation,
+			*k8sClientMocks.MockPodClient,
+		)
+		expectedLogData  string
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name:       "successful output since time",
+			outputType: output.Any,
+			target: &kubernetesTask{
+				serviceName: "sn1",
+				// The cached reader is not used as the new reader is created.
+				outputReader: &CombinedReader{readers: []io.ReadCloser{&app.DummyReaderCloser{}}},
+			},
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				fnd *appMocks.MockFoundation,
+				pc *k8sClientMocks.MockPodClient,
+			) {
+				fnd.On("DryRun").Return(false)
+				pl := &corev1.PodList{Items: []corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "p1"}},
+				}}
+				pc.On("List", ctx, metav1.ListOptions{
+					LabelSelector: "app=sn1",
+				}).Return(pl, nil)
+				pc.On("StreamLogs", ctx, "p1", &corev1.PodLogOptions{
+					Follow:    true,
+					SinceTime: &sinceTime,
+				}).Return(&pullReaderCloser{msg: "data"}, nil)
+			},
+			expectedLogData: "data",
+		},
+		{
+			name:       "successful output since time for dry run",
+			outputType: output.Any,
+			target: &kubernetesTask{
+				serviceName: "sn1",
+			},
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				fnd *appMocks.MockFoundation,
+				pc *k8sClientMocks.MockPodClient,
+			) {
+				fnd.On("DryRun").Return(true)
+			},
+			expectedLogData: "",
+		},
+		{
+			name:       "failed output since time due to failed log streaming",
+			outputType: output.Any,
+			target: &kubernetesTask{
+				serviceName: "sn1",
+			},
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				fnd *appMocks.MockFoundation,
+				pc *k8sClientMocks.MockPodClient,
+			) {
+				fnd.On("DryRun").Return(false)
+				pl := &corev1.PodList{Items: []corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "p1"}},
+				}}
+				pc.On("List", ctx, metav1.ListOptions{
+					LabelSelector: "app=sn1",
+				}).Return(pl, nil)
+				pc.On("StreamLogs", ctx, "p1", &corev1.PodLogOptions{
+					Follow:    true,
+					SinceTime: &sinceTime,
+				}).Return(nil, errors.New("stream fail"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "error in opening stream: stream fail",
+		},
+		{
+			name:       "failed output since time due to failed listing of pods",
+			outputType: output.Any,
+			target: &kubernetesTask{
+				serviceName: "sn1",
+			},
+			setupMocks: func(
+				t *testing.T,
+				ctx context.Context,
+				fnd *appMocks.MockFoundation,
+				pc *k8sClientMocks.MockPodClient,
+			) {
+				fnd.On("DryRun").Return(false)
+				pc.On("List", ctx, metav1.ListOptions{
+					LabelSelector: "app=sn1",
+				}).Return(nil, errors.New("pod listing fail"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to list pods: pod listing fail",
+		},
+		{
+			name:             "failed output since time due to invalid task type",
+			outputType:       output.Any,
+			target:           &invalidTask{},
+			expectError:      true,
+			expectedErrorMsg: "task in not a Kubernetes task",
+		},
+		{
+			name:             "failed output since time due to unsupported output type",
+			outputType:       output.Stdout,
+			target:           &kubernetesTask{serviceName: "sn1"},
+			expectError:      true,
+			expectedErrorMsg: "only any output type is supported by Kubernetes environment",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			podClientMock := k8sClientMocks.NewMockPodClient(t)
+			ctx := context.Background()
+			e := &kubernetesEnvironment{
+				ContainerEnvironment: environment.ContainerEnvironment{
+					CommonEnvironment: environment.CommonEnvironment{
+						Fnd: fndMock,
+					},
+				},
+				podClient: podClientMock,
+			}
+
+			if tt.setupMocks != nil {
+				tt.setupMocks(t, ctx, fndMock, podClientMock)
+			}
+			actualReader, err := e.OutputSince(ctx, tt.target, tt.outputType, since)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				buf := new(strings.Builder)
+				_, err := io.Copy(buf, actualReader)
+				require.Nil(t, err)
+				assert.Equal(t, tt.expectedLogData, buf.String())
+			}
+		})
+	}
+}
+
 func Test_kubernetesEnvironment_RootPath(t *testing.T) {
 	env := &kubernetesEnvironment{}
 	assert.Equal(t, "", env.RootPath("/www/ws"))
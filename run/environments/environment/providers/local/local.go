@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 	"github.com/wstool/wst/app"
 	"github.com/wstool/wst/conf/types"
 	"github.com/wstool/wst/run/environments/environment"
@@ -27,10 +28,14 @@ import (
 	"github.com/wstool/wst/run/resources"
 	"io"
 	"os"
+	"os/user"
 	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync/atomic"
 	"syscall"
+	"time"
 )
 
 type Maker interface {
@@ -275,7 +280,7 @@ func (l *localEnvironment) RunTask(ctx context.Context, ss *environment.ServiceS
 	return t, nil
 }
 
-func convertTask(target task.Task) (*localTask, error) {
+func castTask(target task.Task) (*localTask, error) {
 	if target == nil || reflect.ValueOf(target).IsNil() {
 		return nil, errors.Errorf("target task is not set")
 	}
@@ -287,6 +292,14 @@ func convertTask(target task.Task) (*localTask, error) {
 		// this should not happen
 		return nil, errors.Errorf("target task is not of type *localTask")
 	}
+	return t, nil
+}
+
+func convertTask(target task.Task) (*localTask, error) {
+	t, err := castTask(target)
+	if err != nil {
+		return nil, err
+	}
 	if !t.IsRunning() {
 		return nil, errors.Errorf("task %s is not running", t.id)
 	}
@@ -329,6 +342,186 @@ func (l *localEnvironment) ExecTaskSignal(ctx context.Context, ss *environment.S
 	return nil
 }
 
+// ExecTaskProcessesSignal sends the signal to the supplied processes of the task.
+func (l *localEnvironment) ExecTaskProcessesSignal(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	processes []environment.Process,
+	signal os.Signal,
+) error {
+	_, err := convertTask(target)
+	if err != nil {
+		return err
+	}
+
+	command, err := environment.SignalCommand(signal, environment.ProcessPids(processes))
+	if err != nil {
+		return err
+	}
+
+	return l.Fnd.ExecCommand(ctx, command[0], command[1:]).Run()
+}
+
+func (l *localEnvironment) FileExists(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	path string,
+) (bool, error) {
+	_, err := l.Fnd.Fs().Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, errors.Errorf("failed to check file %s: %v", path, err)
+}
+
+// dialReady checks whether the address accepts connections. Any connection failure means not ready.
+func (l *localEnvironment) dialReady(ctx context.Context, network, address string) bool {
+	conn, err := l.Fnd.Dial(ctx, network, address)
+	if err != nil {
+		l.Fnd.Logger().Debugf("Connecting to %s address %s failed: %v", network, address, err)
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+func (l *localEnvironment) PortReady(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	port int32,
+) (bool, error) {
+	return l.dialReady(ctx, "tcp", fmt.Sprintf("127.0.0.1:%d", port)), nil
+}
+
+func (l *localEnvironment) UdsReady(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	path string,
+) (bool, error) {
+	return l.dialReady(ctx, "unix", path), nil
+}
+
+func (l *localEnvironment) TaskRunning(ctx context.Context, ss *environment.ServiceSettings, target task.Task) (bool, error) {
+	t, err := castTask(target)
+	if err != nil {
+		return false, err
+	}
+
+	return t.IsRunning(), nil
+}
+
+// TaskProcesses returns the task process and all its descendants found in /proc. The descriptors count and uptime
+// are marked unknown if they cannot be read.
+func (l *localEnvironment) TaskProcesses(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+) ([]environment.Process, error) {
+	t, err := convertTask(target)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := l.Fnd.Fs()
+	paths, err := afero.Glob(fs, "/proc/[0-9]*/stat")
+	if err != nil {
+		return nil, errors.Errorf("failed to list processes: %v", err)
+	}
+	processes := make([]environment.Process, 0, len(paths))
+	for _, path := range paths {
+		// Processes can exit in the meantime so failures are ignored.
+		content, err := afero.ReadFile(fs, path)
+		if err != nil {
+			continue
+		}
+		process, err := environment.ParseProcStat(strings.TrimSpace(string(content)))
+		if err != nil {
+			continue
+		}
+		processes = append(processes, *process)
+	}
+
+	tree := environment.ProcessTree(processes, t.Pid())
+	for i := range tree {
+		if entries, err := afero.ReadDir(fs, fmt.Sprintf("/proc/%d/fd", tree[i].Pid)); err == nil {
+			tree[i].FDs = len(entries)
+			tree[i].FDsKnown = true
+		}
+	}
+	if content, err := afero.ReadFile(fs, "/proc/uptime"); err == nil {
+		if uptime, err := environment.ParseProcUptime(string(content)); err == nil {
+			environment.SetProcessesUptime(tree, uptime)
+		}
+	}
+
+	return tree, nil
+}
+
+// TaskFile returns the file stat and content from the offset. It returns nil if the file does not exist. The owner and
+// group are left unset if the file system does not provide them.
+func (l *localEnvironment) TaskFile(
+	ctx context.Context,
+	ss *environment.ServiceSettings,
+	target task.Task,
+	path string,
+	offset int64,
+) (*environment.File, error) {
+	fs := l.Fnd.Fs()
+	info, err := fs.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Errorf("failed to stat file %s: %v", path, err)
+	}
+	file := &environment.File{
+		Mode: info.Mode(),
+		Size: info.Size(),
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		file.Owner, file.Group = fileOwner(stat.Uid, stat.Gid)
+	}
+	if info.Mode().IsRegular() && offset >= 0 && offset < file.Size {
+		if file.Content, err = readFileFrom(fs, path, offset); err != nil {
+			return nil, errors.Errorf("failed to read file %s: %v", path, err)
+		}
+	}
+	return file, nil
+}
+
+// readFileFrom reads the file content from the offset.
+func readFileFrom(fs afero.Fs, path string, offset int64) ([]byte, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err = f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(f)
+}
+
+// fileOwner returns the user and group names of the ids or the ids if the names cannot be found.
+func fileOwner(uid, gid uint32) (string, string) {
+	owner := strconv.FormatUint(uint64(uid), 10)
+	if u, err := user.LookupId(owner); err == nil {
+		owner = u.Username
+	}
+	group := strconv.FormatUint(uint64(gid), 10)
+	if g, err := user.LookupGroupId(group); err == nil {
+		group = g.Name
+	}
+	return owner, group
+}
+
 func (l *localEnvironment) Output(ctx context.Context, target task.Task, outputType output.Type) (io.Reader, error) {
 	t, err := convertTask(target)
 	if err != nil {
@@ -338,6 +531,20 @@ func (l *localEnvironment) Output(ctx context.Context, target task.Task, outputT
 	return t.outputCollector.Reader(ctx, outputType)
 }
 
+func (l *localEnvironment) OutputSince(
+	ctx context.Context,
+	target task.Task,
+	outputType output.Type,
+	since time.Time,
+) (io.Reader, error) {
+	t, err := convertTask(target)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.outputCollector.ReaderSince(ctx, outputType, since)
+}
+
 type localTask struct {
 	id              string
 	cmd             app.Command
@@ -26,7 +26,9 @@ import (
 	"github.com/wstool/wst/run/resources/certificates"
 	"github.com/wstool/wst/run/resources/scripts"
 	"io"
+	"net"
 	"os"
+	"os/user"
 	"path/filepath"
 	"strings"
 	"syscall"
@@ -1728,6 +1730,466 @@ func Test_localEnvironment_ExecTaskSignal(t *testing.T) {
 	}
 }
 
+func Test_localEnvironment_ExecTaskProcessesSignal(t *testing.T) {
+	processes := []environment.Process{
+		{Pid: 23, PPid: 22, Name: "php-fpm"},
+		{Pid: 24, PPid: 22, Name: "php-fpm"},
+	}
+	tests := []struct {
+		name             string
+		setupMocks       func(*testing.T, *appMocks.MockFoundation)
+		running          bool
+		signal           os.Signal
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "successful signal execution",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation) {
+				cmdMock := appMocks.NewMockCommand(t)
+				cmdMock.On("Run").Return(nil)
+				fnd.On("ExecCommand", mock.Anything, "kill", []string{"-10", "23", "24"}).Return(cmdMock)
+			},
+			running: true,
+			signal:  syscall.SIGUSR1,
+		},
+		{
+			name: "error during kill execution",
+			setupMocks: func(t *testing.T, fnd *appMocks.MockFoundation) {
+				cmdMock := appMocks.NewMockCommand(t)
+				cmdMock.On("Run").Return(fmt.Errorf("kill failed"))
+				fnd.On("ExecCommand", mock.Anything, "kill", []string{"-10", "23", "24"}).Return(cmdMock)
+			},
+			running:          true,
+			signal:           syscall.SIGUSR1,
+			expectError:      true,
+			expectedErrorMsg: "kill failed",
+		},
+		{
+			name:             "task is not running",
+			setupMocks:       func(t *testing.T, fnd *appMocks.MockFoundation) {},
+			signal:           syscall.SIGUSR1,
+			expectError:      true,
+			expectedErrorMsg: "task uuid-tid is not running",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			tt.setupMocks(t, fndMock)
+			lt := &localTask{
+				id:          "uuid-tid",
+				serviceName: "local-service",
+				cmd:         appMocks.NewMockCommand(t),
+			}
+			lt.serviceRunning.Store(tt.running)
+			env := &localEnvironment{
+				CommonEnvironment: environment.CommonEnvironment{Fnd: fndMock},
+			}
+
+			err := env.ExecTaskProcessesSignal(
+				context.Background(), &environment.ServiceSettings{}, lt, processes, tt.signal)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_localEnvironment_FileExists(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		setupFs func(*testing.T, afero.Fs)
+		want    bool
+	}{
+		{
+			name: "file exists",
+			path: "/run/fpm/slow.log",
+			setupFs: func(t *testing.T, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/run/fpm/slow.log", []byte("log"), 0644))
+			},
+			want: true,
+		},
+		{
+			name: "file does not exist",
+			path: "/run/fpm/slow.log",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			fs := afero.NewMemMapFs()
+			if tt.setupFs != nil {
+				tt.setupFs(t, fs)
+			}
+			fndMock.On("Fs").Return(fs)
+
+			env := &localEnvironment{
+				CommonEnvironment: environment.CommonEnvironment{Fnd: fndMock},
+			}
+
+			got, err := env.FileExists(context.Background(), &environment.ServiceSettings{}, getTestTask(t), tt.path)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_localEnvironment_TaskFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		offset  int64
+		setupFs func(*testing.T, afero.Fs)
+		want    *environment.File
+	}{
+		{
+			name: "regular file with content",
+			path: "/run/fpm/slow.log",
+			setupFs: func(t *testing.T, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/run/fpm/slow.log", []byte("slow log\n"), 0640))
+			},
+			want: &environment.File{
+				Mode:    0640,
+				Size:    9,
+				Content: []byte("slow log\n"),
+			},
+		},
+		{
+			name:   "regular file with content from offset",
+			path:   "/run/fpm/slow.log",
+			offset: 5,
+			setupFs: func(t *testing.T, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/run/fpm/slow.log", []byte("slow log\n"), 0640))
+			},
+			want: &environment.File{
+				Mode:    0640,
+				Size:    9,
+				Content: []byte("log\n"),
+			},
+		},
+		{
+			name:   "regular file with offset at the end",
+			path:   "/run/fpm/slow.log",
+			offset: 9,
+			setupFs: func(t *testing.T, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/run/fpm/slow.log", []byte("slow log\n"), 0640))
+			},
+			want: &environment.File{
+				Mode: 0640,
+				Size: 9,
+			},
+		},
+		{
+			name:   "regular file without content",
+			path:   "/run/fpm/slow.log",
+			offset: environment.FileNoContent,
+			setupFs: func(t *testing.T, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/run/fpm/slow.log", []byte("slow log\n"), 0640))
+			},
+			want: &environment.File{
+				Mode: 0640,
+				Size: 9,
+			},
+		},
+		{
+			name: "directory without content",
+			path: "/run/fpm",
+			setupFs: func(t *testing.T, fs afero.Fs) {
+				require.NoError(t, fs.MkdirAll("/run/fpm", 0755))
+			},
+			want: &environment.File{
+				Mode: os.ModeDir | 0755,
+				// The memory file system reports this constant size for directories.
+				Size: 42,
+			},
+		},
+		{
+			name: "file does not exist",
+			path: "/run/fpm/slow.log",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			fs := afero.NewMemMapFs()
+			if tt.setupFs != nil {
+				tt.setupFs(t, fs)
+			}
+			fndMock.On("Fs").Return(fs)
+
+			env := &localEnvironment{
+				CommonEnvironment: environment.CommonEnvironment{Fnd: fndMock},
+			}
+
+			got, err := env.TaskFile(context.Background(), &environment.ServiceSettings{}, getTestTask(t), tt.path,
+				tt.offset)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_fileOwner(t *testing.T) {
+	current, err := user.Current()
+	require.NoError(t, err)
+	owner, _ := fileOwner(uint32(os.Getuid()), uint32(os.Getgid()))
+	assert.Equal(t, current.Username, owner)
+
+	owner, group := fileOwner(4294967290, 4294967290)
+	assert.Equal(t, "4294967290", owner)
+	assert.Equal(t, "4294967290", group)
+}
+
+func Test_localEnvironment_PortReady(t *testing.T) {
+	tests := []struct {
+		name    string
+		port    int32
+		dialErr error
+		want    bool
+	}{
+		{
+			name: "port accepts connections",
+			port: 8080,
+			want: true,
+		},
+		{
+			name:    "port does not accept connections",
+			port:    8080,
+			dialErr: errors.New("connection refused"),
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fndMock := appMocks.NewMockFoundation(t)
+			if tt.dialErr != nil {
+				fndMock.On("Dial", ctx, "tcp", "127.0.0.1:8080").Return(nil, tt.dialErr)
+				mockLogger := external.NewMockLogger()
+				fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+			} else {
+				conn, peer := net.Pipe()
+				defer peer.Close()
+				fndMock.On("Dial", ctx, "tcp", "127.0.0.1:8080").Return(conn, nil)
+			}
+
+			env := &localEnvironment{
+				CommonEnvironment: environment.CommonEnvironment{Fnd: fndMock},
+			}
+
+			got, err := env.PortReady(ctx, &environment.ServiceSettings{}, getTestTask(t), tt.port)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_localEnvironment_UdsReady(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		dialErr error
+		want    bool
+	}{
+		{
+			name: "socket accepts connections",
+			path: "/run/fpm.sock",
+			want: true,
+		},
+		{
+			name:    "socket does not accept connections",
+			path:    "/run/fpm.sock",
+			dialErr: errors.New("no such file or directory"),
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fndMock := appMocks.NewMockFoundation(t)
+			if tt.dialErr != nil {
+				fndMock.On("Dial", ctx, "unix", tt.path).Return(nil, tt.dialErr)
+				mockLogger := external.NewMockLogger()
+				fndMock.On("Logger").Return(mockLogger.SugaredLogger)
+			} else {
+				conn, peer := net.Pipe()
+				defer peer.Close()
+				fndMock.On("Dial", ctx, "unix", tt.path).Return(conn, nil)
+			}
+
+			env := &localEnvironment{
+				CommonEnvironment: environment.CommonEnvironment{Fnd: fndMock},
+			}
+
+			got, err := env.UdsReady(ctx, &environment.ServiceSettings{}, getTestTask(t), tt.path)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_localEnvironment_TaskRunning(t *testing.T) {
+	tests := []struct {
+		name             string
+		target           func(*testing.T) task.Task
+		want             bool
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "task is running",
+			target: func(t *testing.T) task.Task {
+				return getTestTask(t)
+			},
+			want: true,
+		},
+		{
+			name: "task has exited",
+			target: func(t *testing.T) task.Task {
+				lt := getTestTask(t)
+				lt.serviceRunning.Store(false)
+				return lt
+			},
+			want: false,
+		},
+		{
+			name: "task is nil",
+			target: func(t *testing.T) task.Task {
+				return nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "target task is not set",
+		},
+		{
+			name: "task type mismatch",
+			target: func(t *testing.T) task.Task {
+				wrongTask := taskMocks.NewMockTask(t)
+				wrongTask.On("Type").Return(providers.DockerType)
+				return wrongTask
+			},
+			expectError:      true,
+			expectedErrorMsg: "local environment can process only local task",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &localEnvironment{
+				CommonEnvironment: environment.CommonEnvironment{Fnd: appMocks.NewMockFoundation(t)},
+			}
+
+			got, err := env.TaskRunning(context.Background(), &environment.ServiceSettings{}, tt.target(t))
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_localEnvironment_TaskProcesses(t *testing.T) {
+	tests := []struct {
+		name             string
+		target           func(*testing.T) task.Task
+		setupFs          func(*testing.T, afero.Fs)
+		want             []environment.Process
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "task with children",
+			target: func(t *testing.T) task.Task {
+				return getTestTask(t)
+			},
+			setupFs: func(t *testing.T, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/proc/1/stat", []byte("1 (init) S 0 1 1\n"), 0444))
+				require.NoError(t, afero.WriteFile(fs, "/proc/22/stat", []byte("22 (php-fpm) S 1 22 22\n"), 0444))
+				require.NoError(t, afero.WriteFile(fs, "/proc/23/stat", []byte("23 (php-fpm) S 22 22 22\n"), 0444))
+				require.NoError(t, afero.WriteFile(fs, "/proc/30/stat", []byte("invalid"), 0444))
+				require.NoError(t, afero.WriteFile(fs, "/proc/self/stat", []byte("40 (wst) S 1 40 40\n"), 0444))
+			},
+			want: []environment.Process{
+				{Pid: 22, PPid: 1, Name: "php-fpm", Main: true},
+				{Pid: 23, PPid: 22, Name: "php-fpm"},
+			},
+		},
+		{
+			name: "task with descriptors and uptime",
+			target: func(t *testing.T) task.Task {
+				return getTestTask(t)
+			},
+			setupFs: func(t *testing.T, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/proc/uptime", []byte("60.00 10.00\n"), 0444))
+				require.NoError(t, afero.WriteFile(fs, "/proc/22/stat",
+					[]byte("22 (php-fpm) S 1 22 22 0 -1 4194560 1000 0 0 0 0 0 0 0 20 0 1 0 1000 2703360 5 0\n"), 0444))
+				require.NoError(t, afero.WriteFile(fs, "/proc/22/fd/0", []byte(""), 0444))
+				require.NoError(t, afero.WriteFile(fs, "/proc/22/fd/1", []byte(""), 0444))
+				require.NoError(t, afero.WriteFile(fs, "/proc/23/stat", []byte("23 (php-fpm) S 22 22 22\n"), 0444))
+			},
+			want: []environment.Process{
+				{Pid: 22, PPid: 1, Name: "php-fpm", Main: true, RSS: 5 * int64(os.Getpagesize()), FDs: 2,
+					FDsKnown: true, StartTime: 10 * time.Second, Uptime: 50 * time.Second, UptimeKnown: true},
+				{Pid: 23, PPid: 22, Name: "php-fpm"},
+			},
+		},
+		{
+			name: "task is not running",
+			target: func(t *testing.T) task.Task {
+				lt := getTestTask(t)
+				lt.serviceRunning.Store(false)
+				return lt
+			},
+			expectError:      true,
+			expectedErrorMsg: "task lid is not running",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fndMock := appMocks.NewMockFoundation(t)
+			if tt.setupFs != nil {
+				fs := afero.NewMemMapFs()
+				tt.setupFs(t, fs)
+				fndMock.On("Fs").Return(fs)
+			}
+			env := &localEnvironment{
+				CommonEnvironment: environment.CommonEnvironment{Fnd: fndMock},
+			}
+
+			got, err := env.TaskProcesses(context.Background(), &environment.ServiceSettings{}, tt.target(t))
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
 func Test_localEnvironment_Output(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -1807,6 +2269,77 @@ func Test_localEnvironment_Output(t *testing.T) {
 	}
 }
 
+func Test_localEnvironment_OutputSince(t *testing.T) {
+	since := time.Date(2024, 5, 1, 10, 20, 30, 0, time.UTC)
+	tests := []struct {
+		name             string
+		outputType       output.Type
+		setupMocks       func(*testing.T, context.Context, *outputMocks.MockCollector)
+		nilTask          bool
+		expectError      bool
+		expectedOutput   string
+		expectedErrorMsg string
+	}{
+		{
+			name:       "successful stdout output since time",
+			outputType: output.Stdout,
+			setupMocks: func(t *testing.T, ctx context.Context, om *outputMocks.MockCollector) {
+				stdout := io.NopCloser(strings.NewReader("Hello, stdout!"))
+				om.On("ReaderSince", ctx, output.Stdout, since).Return(stdout, nil)
+			},
+			expectedOutput: "Hello, stdout!",
+		},
+		{
+			name:       "failed any output since time",
+			outputType: output.Any,
+			setupMocks: func(t *testing.T, ctx context.Context, om *outputMocks.MockCollector) {
+				om.On("ReaderSince", ctx, output.Any, since).Return(nil, errors.New("failed to read any output"))
+			},
+			expectError:      true,
+			expectedErrorMsg: "failed to read any output",
+		},
+		{
+			name:             "nil task",
+			outputType:       output.Any,
+			nilTask:          true,
+			expectError:      true,
+			expectedErrorMsg: "target task is not set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ocMock := outputMocks.NewMockCollector(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(t, ctx, ocMock)
+			}
+
+			var testTask *localTask = nil
+			if !tt.nilTask {
+				testTask = &localTask{
+					outputCollector: ocMock,
+				}
+				testTask.serviceRunning.Store(true)
+			}
+
+			env := &localEnvironment{}
+
+			reader, err := env.OutputSince(ctx, testTask, tt.outputType, since)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+				buf := new(strings.Builder)
+				_, err = io.Copy(buf, reader)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedOutput, buf.String())
+			}
+		})
+	}
+}
+
 func getTestTask(t *testing.T) *localTask {
 	cmdMock := appMocks.NewMockCommand(t)
 	cmdMock.On("ProcessPid").Maybe().Return(22)
@@ -23,7 +23,11 @@ import (
 type Maker interface {
 	MakeMetricsExpectation(config *types.MetricsExpectation) (*MetricsExpectation, error)
 	MakeOutputExpectation(config *types.OutputExpectation) (*OutputExpectation, error)
+	MakeReceivedExpectation(config *types.ReceivedExpectation) (*ReceivedExpectation, error)
 	MakeResponseExpectation(config *types.ResponseExpectation) (*ResponseExpectation, error)
+	MakeSnapshotExpectation(config *types.SnapshotExpectation) (*SnapshotExpectation, error)
+	MakeProcessExpectation(config *types.ProcessExpectation) (*ProcessExpectation, error)
+	MakeFileExpectation(config *types.FileExpectation) (*FileExpectation, error)
 }
 
 type nativeMaker struct {
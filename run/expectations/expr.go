@@ -0,0 +1,122 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expectations
+
+import (
+	"github.com/google/cel-go/cel"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/parameters/parameter"
+)
+
+// Expression variables available in the respective expectations. The parameters variable is always available.
+const (
+	ExprVariableResponse   = "response"
+	ExprVariableOutput     = "output"
+	ExprVariableMetrics    = "metrics"
+	ExprVariableReceived   = "received"
+	ExprVariableSnapshot   = "snapshot"
+	ExprVariableProcess    = "process"
+	ExprVariableFile       = "file"
+	ExprVariableParameters = "parameters"
+)
+
+// Expr is a compiled Common Expression Language (CEL) expression that evaluates to bool.
+type Expr struct {
+	source  string
+	program cel.Program
+}
+
+// CompileExpr compiles the expression with the parameters and the passed variables declared as maps.
+func CompileExpr(source string, variables ...string) (*Expr, error) {
+	options := []cel.EnvOption{
+		cel.CrossTypeNumericComparisons(true),
+		cel.Variable(ExprVariableParameters, cel.MapType(cel.StringType, cel.DynType)),
+	}
+	for _, variable := range variables {
+		options = append(options, cel.Variable(variable, cel.MapType(cel.StringType, cel.DynType)))
+	}
+	env, err := cel.NewEnv(options...)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, errors.Errorf("invalid expression %s: %v", source, issues.Err())
+	}
+	if outputType := ast.OutputType(); !outputType.IsExactType(cel.BoolType) && !outputType.IsExactType(cel.DynType) {
+		return nil, errors.Errorf("expression %s must evaluate to bool but it evaluates to %s", source, outputType)
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, errors.Errorf("invalid expression %s: %v", source, err)
+	}
+	return &Expr{source: source, program: program}, nil
+}
+
+// Evaluate evaluates the expression with the variables and the parameters.
+func (e *Expr) Evaluate(variables map[string]interface{}, params parameters.Parameters) (bool, error) {
+	activation := make(map[string]interface{}, len(variables)+1)
+	for name, value := range variables {
+		activation[name] = value
+	}
+	paramValues := make(map[string]interface{}, len(params))
+	for name, param := range params {
+		paramValues[name] = parameterValue(param)
+	}
+	activation[ExprVariableParameters] = paramValues
+
+	result, _, err := e.program.Eval(activation)
+	if err != nil {
+		return false, errors.Errorf("evaluating expression %s failed: %v", e.source, err)
+	}
+	matched, ok := result.Value().(bool)
+	if !ok {
+		return false, errors.Errorf("expression %s evaluated to %v instead of bool", e.source, result.Value())
+	}
+	return matched, nil
+}
+
+func (e *Expr) String() string {
+	return e.source
+}
+
+func parameterValue(param parameter.Parameter) interface{} {
+	switch param.Type() {
+	case parameter.BoolType:
+		return param.BoolValue()
+	case parameter.IntType:
+		return param.IntValue()
+	case parameter.FloatType:
+		return param.FloatValue()
+	case parameter.StringType:
+		return param.StringValue()
+	case parameter.ArrayType:
+		array := param.ArrayValue()
+		values := make([]interface{}, 0, len(array))
+		for _, item := range array {
+			values = append(values, parameterValue(item))
+		}
+		return values
+	case parameter.MapType:
+		values := make(map[string]interface{})
+		for key, item := range param.MapValue() {
+			values[key] = parameterValue(item)
+		}
+		return values
+	default:
+		return nil
+	}
+}
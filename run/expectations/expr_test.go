@@ -0,0 +1,198 @@
+package expectations
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	parameterMocks "github.com/wstool/wst/mocks/generated/run/parameters/parameter"
+	"github.com/wstool/wst/run/parameters"
+	"github.com/wstool/wst/run/parameters/parameter"
+	"testing"
+	"time"
+)
+
+func TestCompileExpr(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		variables []string
+		errorMsg  string
+	}{
+		{
+			name:      "valid bool expression",
+			source:    "response.status in [200, 204] && size(response.headers['Set-Cookie']) == 2",
+			variables: []string{ExprVariableResponse},
+		},
+		{
+			name:      "valid dynamic expression",
+			source:    "metrics.Success",
+			variables: []string{ExprVariableMetrics},
+		},
+		{
+			name:   "parameters are always declared",
+			source: "parameters.enabled == true",
+		},
+		{
+			name:      "syntax error",
+			source:    "response.status ==",
+			variables: []string{ExprVariableResponse},
+			errorMsg:  "invalid expression response.status ==",
+		},
+		{
+			name:      "undeclared variable",
+			source:    "output.lines[0] == 'test'",
+			variables: []string{ExprVariableResponse},
+			errorMsg:  "undeclared reference to 'output'",
+		},
+		{
+			name:      "non bool result",
+			source:    "size(output.lines)",
+			variables: []string{ExprVariableOutput},
+			errorMsg:  "expression size(output.lines) must evaluate to bool but it evaluates to int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := CompileExpr(tt.source, tt.variables...)
+			if tt.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				assert.Nil(t, expr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.source, expr.String())
+			}
+		})
+	}
+}
+
+func createParameterMock(t *testing.T, paramType parameter.Type, setup func(*parameterMocks.MockParameter)) parameter.Parameter {
+	param := parameterMocks.NewMockParameter(t)
+	param.On("Type").Return(paramType)
+	setup(param)
+	return param
+}
+
+func TestExpr_Evaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		variables map[string]interface{}
+		params    func(t *testing.T) parameters.Parameters
+		expected  bool
+		errorMsg  string
+	}{
+		{
+			name:   "response expression matched",
+			source: "response.status in [200, 204] && size(response.headers['Set-Cookie']) == 2",
+			variables: map[string]interface{}{
+				ExprVariableResponse: map[string]interface{}{
+					"status":  200,
+					"headers": map[string][]string{"Set-Cookie": {"a=1", "b=2"}},
+				},
+			},
+			expected: true,
+		},
+		{
+			name:   "metrics expression with duration and cross type comparison",
+			source: "metrics.LatencyP99 < duration('100ms') && metrics.Requests > 10 && metrics.Rate >= 5",
+			variables: map[string]interface{}{
+				ExprVariableMetrics: map[string]interface{}{
+					"LatencyP99": 50 * time.Millisecond,
+					"Requests":   uint64(20),
+					"Rate":       5.5,
+				},
+			},
+			expected: true,
+		},
+		{
+			name:   "output expression not matched",
+			source: "output.lines.exists(l, l.contains('error'))",
+			variables: map[string]interface{}{
+				ExprVariableOutput: map[string]interface{}{"lines": []string{"ok", "done"}},
+			},
+			expected: false,
+		},
+		{
+			name: "parameters expression",
+			source: "parameters.name == 'wst' && parameters.count == 2 && parameters.ratio > 0.5 && " +
+				"parameters.enabled && parameters.list[1] == 'b' && parameters.map.key == 'value'",
+			params: func(t *testing.T) parameters.Parameters {
+				return parameters.Parameters{
+					"name": createParameterMock(t, parameter.StringType, func(p *parameterMocks.MockParameter) {
+						p.On("StringValue").Return("wst")
+					}),
+					"count": createParameterMock(t, parameter.IntType, func(p *parameterMocks.MockParameter) {
+						p.On("IntValue").Return(2)
+					}),
+					"ratio": createParameterMock(t, parameter.FloatType, func(p *parameterMocks.MockParameter) {
+						p.On("FloatValue").Return(0.75)
+					}),
+					"enabled": createParameterMock(t, parameter.BoolType, func(p *parameterMocks.MockParameter) {
+						p.On("BoolValue").Return(true)
+					}),
+					"list": createParameterMock(t, parameter.ArrayType, func(p *parameterMocks.MockParameter) {
+						p.On("ArrayValue").Return([]parameter.Parameter{
+							createParameterMock(t, parameter.StringType, func(p *parameterMocks.MockParameter) {
+								p.On("StringValue").Return("a")
+							}),
+							createParameterMock(t, parameter.StringType, func(p *parameterMocks.MockParameter) {
+								p.On("StringValue").Return("b")
+							}),
+						})
+					}),
+					"map": createParameterMock(t, parameter.MapType, func(p *parameterMocks.MockParameter) {
+						p.On("MapValue").Return(map[string]parameter.Parameter{
+							"key": createParameterMock(t, parameter.StringType, func(p *parameterMocks.MockParameter) {
+								p.On("StringValue").Return("value")
+							}),
+						})
+					}),
+					"none": createParameterMock(t, parameter.NilType, func(p *parameterMocks.MockParameter) {}),
+				}
+			},
+			expected: true,
+		},
+		{
+			name:   "missing key",
+			source: "size(response.headers['Set-Cookie']) == 2",
+			variables: map[string]interface{}{
+				ExprVariableResponse: map[string]interface{}{"headers": map[string][]string{}},
+			},
+			errorMsg: "evaluating expression size(response.headers['Set-Cookie']) == 2 failed: no such key: Set-Cookie",
+		},
+		{
+			name:   "dynamic non bool result",
+			source: "metrics.Success",
+			variables: map[string]interface{}{
+				ExprVariableMetrics: map[string]interface{}{"Success": 0.5},
+			},
+			errorMsg: "expression metrics.Success evaluated to 0.5 instead of bool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variableNames := make([]string, 0, len(tt.variables))
+			for name := range tt.variables {
+				variableNames = append(variableNames, name)
+			}
+			expr, err := CompileExpr(tt.source, variableNames...)
+			require.NoError(t, err)
+			var params parameters.Parameters
+			if tt.params != nil {
+				params = tt.params(t)
+			}
+
+			matched, err := expr.Evaluate(tt.variables, params)
+
+			if tt.errorMsg != "" {
+				assert.EqualError(t, err, tt.errorMsg)
+				assert.False(t, matched)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, matched)
+			}
+		})
+	}
+}
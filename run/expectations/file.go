@@ -0,0 +1,175 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expectations
+
+import (
+	"fmt"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/metrics"
+	"github.com/wstool/wst/run/sandboxes/dir"
+	"os"
+	"strconv"
+)
+
+func (m *nativeMaker) MakeFileExpectation(
+	config *types.FileExpectation,
+) (*FileExpectation, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("file path is required")
+	}
+
+	dirType := dir.DirType(config.Dir)
+	switch dirType {
+	case "":
+		dirType = dir.RunDirType
+	case dir.ConfDirType, dir.RunDirType, dir.ScriptDirType:
+	default:
+		return nil, fmt.Errorf("invalid file dir: %v", config.Dir)
+	}
+
+	fileType := FileType(config.Type)
+	switch fileType {
+	case "":
+		fileType = FileTypeAny
+	case FileTypeAny, FileTypeFile, FileTypeDir, FileTypeSocket:
+	default:
+		return nil, fmt.Errorf("invalid file type: %v", config.Type)
+	}
+
+	var mode os.FileMode
+	if config.Mode != "" {
+		value, err := strconv.ParseUint(config.Mode, 8, 32)
+		if err != nil || value > 0777 {
+			return nil, fmt.Errorf("invalid file mode: %v", config.Mode)
+		}
+		mode = os.FileMode(value)
+	}
+
+	var size int64
+	var sizeOperator metrics.MetricOperator
+	if config.Size >= 0 {
+		size = int64(config.Size)
+		operator := config.SizeOperator
+		if operator == "" {
+			operator = string(metrics.MetricEqOperator)
+		}
+		var err error
+		if sizeOperator, err = metrics.ConvertToOperator(operator); err != nil {
+			return nil, err
+		}
+	}
+
+	orderType := OrderType(config.Order)
+	switch orderType {
+	case "":
+		orderType = OrderTypeFixed
+	case OrderTypeFixed, OrderTypeRandom:
+	default:
+		return nil, fmt.Errorf("invalid order type: %v", config.Order)
+	}
+
+	matchType := MatchType(config.Match)
+	switch matchType {
+	case "":
+		matchType = MatchTypeExact
+	case MatchTypeExact, MatchTypeRegexp, MatchTypePrefix, MatchTypeSuffix, MatchTypeInfix:
+	default:
+		return nil, fmt.Errorf("invalid match type: %v", config.Match)
+	}
+
+	if len(config.Messages) > 0 && fileType != FileTypeAny && fileType != FileTypeFile {
+		return nil, fmt.Errorf("messages can be checked only for regular files")
+	}
+	if config.Tail && len(config.Messages) == 0 {
+		return nil, fmt.Errorf("tail requires messages")
+	}
+	// The tailed file is read from the action start by default so the messages written before are not matched.
+	from := config.From
+	if from == "" {
+		from = FileFromStart
+		if config.Tail {
+			from = FileFromNow
+		}
+	}
+	if from != FileFromStart && len(config.Messages) == 0 && config.Expr == "" {
+		return nil, fmt.Errorf("from requires messages or expression")
+	}
+	if config.Checkpoint == FileFromStart || config.Checkpoint == FileFromNow {
+		return nil, fmt.Errorf("checkpoint name %s is reserved", config.Checkpoint)
+	}
+	if !config.Exists && (fileType != FileTypeAny || config.Mode != "" || config.Owner != "" ||
+		config.Group != "" || sizeOperator != "" || len(config.Messages) > 0) {
+		return nil, fmt.Errorf("file %s that must not exist cannot have other checks", config.Path)
+	}
+
+	var expr *Expr
+	if config.Expr != "" {
+		var err error
+		if expr, err = CompileExpr(config.Expr, ExprVariableFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return &FileExpectation{
+		Path:           config.Path,
+		Dir:            dirType,
+		Exists:         config.Exists,
+		Type:           fileType,
+		Mode:           mode,
+		CheckMode:      config.Mode != "",
+		Owner:          config.Owner,
+		Group:          config.Group,
+		Size:           size,
+		SizeOperator:   sizeOperator,
+		OrderType:      orderType,
+		MatchType:      matchType,
+		Messages:       config.Messages,
+		RenderTemplate: config.RenderTemplate,
+		Tail:           config.Tail,
+		From:           from,
+		Checkpoint:     config.Checkpoint,
+		Expr:           expr,
+	}, nil
+}
+
+type FileExpectation struct {
+	// Path is the file path that is resolved from the Dir service directory if it is not absolute.
+	Path string
+	Dir  dir.DirType
+	// Exists is false if the file must not exist.
+	Exists bool
+	Type   FileType
+	// Mode is the expected permission bits that are checked only if CheckMode is set.
+	Mode      os.FileMode
+	CheckMode bool
+	Owner     string
+	Group     string
+	// Size is the file size in bytes compared by the size operator.
+	Size int64
+	// SizeOperator is the operator for comparing the file size or empty if the size is not checked.
+	SizeOperator   metrics.MetricOperator
+	OrderType      OrderType
+	MatchType      MatchType
+	Messages       []string
+	RenderTemplate bool
+	// Tail is set if the file content is followed until all messages are found.
+	Tail bool
+	// From is the file position to read the content from which is either start, now or a checkpoint name.
+	From string
+	// Checkpoint is the name of the checkpoint that the file size is stored to on success.
+	Checkpoint string
+	// Expr is the expression over the file that has to evaluate to true or nil if not set.
+	Expr *Expr
+}
@@ -0,0 +1,233 @@
+package expectations
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/metrics"
+	"github.com/wstool/wst/run/sandboxes/dir"
+	"testing"
+)
+
+func Test_nativeMaker_MakeFileExpectation(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *types.FileExpectation
+		expectError bool
+		expected    *FileExpectation
+		errorMsg    string
+	}{
+		{
+			name: "valid file expectation with all checks",
+			config: &types.FileExpectation{
+				Path:           "slow.log",
+				Dir:            "run",
+				Exists:         true,
+				Type:           "file",
+				Mode:           "0640",
+				Owner:          "www-data",
+				Group:          "www-data",
+				Size:           0,
+				SizeOperator:   "gt",
+				Order:          "random",
+				Match:          "regexp",
+				RenderTemplate: true,
+				Messages:       []string{"script_filename = .*index.php"},
+				Tail:           true,
+				Checkpoint:     "slow",
+			},
+			expected: &FileExpectation{
+				Path:           "slow.log",
+				Dir:            dir.RunDirType,
+				Exists:         true,
+				Type:           FileTypeFile,
+				Mode:           0640,
+				CheckMode:      true,
+				Owner:          "www-data",
+				Group:          "www-data",
+				Size:           0,
+				SizeOperator:   metrics.MetricGtOperator,
+				OrderType:      OrderTypeRandom,
+				MatchType:      MatchTypeRegexp,
+				RenderTemplate: true,
+				Messages:       []string{"script_filename = .*index.php"},
+				Tail:           true,
+				From:           FileFromNow,
+				Checkpoint:     "slow",
+			},
+		},
+		{
+			name: "valid tailed file expectation from checkpoint",
+			config: &types.FileExpectation{
+				Path:     "slow.log",
+				Exists:   true,
+				Size:     -1,
+				Messages: []string{"pool www"},
+				Tail:     true,
+				From:     "before",
+			},
+			expected: &FileExpectation{
+				Path:      "slow.log",
+				Dir:       dir.RunDirType,
+				Exists:    true,
+				Type:      FileTypeAny,
+				OrderType: OrderTypeFixed,
+				MatchType: MatchTypeExact,
+				Messages:  []string{"pool www"},
+				Tail:      true,
+				From:      "before",
+			},
+		},
+		{
+			name: "valid file expectation with defaults",
+			config: &types.FileExpectation{
+				Path:   "php-fpm.conf",
+				Dir:    "conf",
+				Exists: true,
+				Size:   -1,
+			},
+			expected: &FileExpectation{
+				Path:      "php-fpm.conf",
+				Dir:       dir.ConfDirType,
+				Exists:    true,
+				Type:      FileTypeAny,
+				OrderType: OrderTypeFixed,
+				MatchType: MatchTypeExact,
+				From:      FileFromStart,
+			},
+		},
+		{
+			name: "valid missing file expectation",
+			config: &types.FileExpectation{
+				Path:   "/tmp/fpm.pid",
+				Exists: false,
+				Size:   -1,
+			},
+			expected: &FileExpectation{
+				Path:      "/tmp/fpm.pid",
+				Dir:       dir.RunDirType,
+				Type:      FileTypeAny,
+				OrderType: OrderTypeFixed,
+				MatchType: MatchTypeExact,
+				From:      FileFromStart,
+			},
+		},
+		{
+			name:        "missing path",
+			config:      &types.FileExpectation{Exists: true, Size: -1},
+			expectError: true,
+			errorMsg:    "file path is required",
+		},
+		{
+			name:        "invalid dir",
+			config:      &types.FileExpectation{Path: "a", Dir: "cert", Exists: true, Size: -1},
+			expectError: true,
+			errorMsg:    "invalid file dir: cert",
+		},
+		{
+			name:        "invalid type",
+			config:      &types.FileExpectation{Path: "a", Type: "link", Exists: true, Size: -1},
+			expectError: true,
+			errorMsg:    "invalid file type: link",
+		},
+		{
+			name:        "invalid mode",
+			config:      &types.FileExpectation{Path: "a", Mode: "0689", Exists: true, Size: -1},
+			expectError: true,
+			errorMsg:    "invalid file mode: 0689",
+		},
+		{
+			name:        "mode with special bits",
+			config:      &types.FileExpectation{Path: "a", Mode: "4755", Exists: true, Size: -1},
+			expectError: true,
+			errorMsg:    "invalid file mode: 4755",
+		},
+		{
+			name:        "invalid size operator",
+			config:      &types.FileExpectation{Path: "a", Exists: true, Size: 10, SizeOperator: "xx"},
+			expectError: true,
+			errorMsg:    "invalid operator xx",
+		},
+		{
+			name:        "invalid order",
+			config:      &types.FileExpectation{Path: "a", Exists: true, Size: -1, Order: "sorted"},
+			expectError: true,
+			errorMsg:    "invalid order type: sorted",
+		},
+		{
+			name:        "invalid match",
+			config:      &types.FileExpectation{Path: "a", Exists: true, Size: -1, Match: "glob"},
+			expectError: true,
+			errorMsg:    "invalid match type: glob",
+		},
+		{
+			name: "messages for socket",
+			config: &types.FileExpectation{
+				Path:     "a",
+				Exists:   true,
+				Size:     -1,
+				Type:     "socket",
+				Messages: []string{"x"},
+			},
+			expectError: true,
+			errorMsg:    "messages can be checked only for regular files",
+		},
+		{
+			name:        "tail without messages",
+			config:      &types.FileExpectation{Path: "a", Exists: true, Size: -1, Tail: true},
+			expectError: true,
+			errorMsg:    "tail requires messages",
+		},
+		{
+			name:        "from without messages",
+			config:      &types.FileExpectation{Path: "a", Exists: true, Size: -1, From: "now"},
+			expectError: true,
+			errorMsg:    "from requires messages or expression",
+		},
+		{
+			name:        "reserved checkpoint name",
+			config:      &types.FileExpectation{Path: "a", Exists: true, Size: -1, Checkpoint: "now"},
+			expectError: true,
+			errorMsg:    "checkpoint name now is reserved",
+		},
+		{
+			name:        "missing file with other checks",
+			config:      &types.FileExpectation{Path: "a", Exists: false, Size: -1, Owner: "root"},
+			expectError: true,
+			errorMsg:    "file a that must not exist cannot have other checks",
+		},
+		{
+			name:        "invalid expression",
+			config:      &types.FileExpectation{Path: "a", Exists: true, Size: -1, Expr: "output.text == ''"},
+			expectError: true,
+			errorMsg:    "undeclared reference to 'output'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maker := &nativeMaker{}
+			result, err := maker.MakeFileExpectation(tt.config)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_nativeMaker_MakeFileExpectation_Expr(t *testing.T) {
+	maker := &nativeMaker{}
+	result, err := maker.MakeFileExpectation(&types.FileExpectation{
+		Path:   "a",
+		Exists: true,
+		Size:   -1,
+		Expr:   "file.size < 1024",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Expr)
+	assert.Equal(t, "file.size < 1024", result.Expr.String())
+}
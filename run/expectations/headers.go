@@ -0,0 +1,118 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expectations
+
+import (
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/metrics"
+	"regexp"
+	"sort"
+)
+
+type HeaderMode string
+
+const (
+	// HeaderModeAny requires each expected value to match at least one of the header values.
+	HeaderModeAny HeaderMode = "any"
+	// HeaderModeAll requires each header value to match at least one of the expected values.
+	HeaderModeAll HeaderMode = "all"
+)
+
+func (m *nativeMaker) makeHeaderExpectations(config types.ResponseHeaders) ([]HeaderExpectation, error) {
+	names := make([]string, 0, len(config))
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var headers []HeaderExpectation
+	for _, name := range names {
+		configHeader := config[name]
+		header := HeaderExpectation{
+			Name:       name,
+			Match:      MatchType(configHeader.Match),
+			Mode:       HeaderMode(configHeader.Mode),
+			IgnoreCase: configHeader.IgnoreCase,
+			Absent:     configHeader.Absent,
+			Count:      configHeader.Count,
+		}
+		if configHeader.Value != "" {
+			header.Values = append(header.Values, configHeader.Value)
+		}
+		header.Values = append(header.Values, configHeader.Values...)
+
+		switch header.Match {
+		case MatchTypeNone:
+			header.Match = MatchTypeExact
+		case MatchTypeExact, MatchTypeRegexp, MatchTypePrefix, MatchTypeSuffix, MatchTypeInfix:
+		default:
+			return nil, errors.Errorf("invalid match type %s for header %s", configHeader.Match, name)
+		}
+		switch header.Mode {
+		case "":
+			header.Mode = HeaderModeAny
+		case HeaderModeAny, HeaderModeAll:
+		default:
+			return nil, errors.Errorf("invalid mode %s for header %s", configHeader.Mode, name)
+		}
+		if header.Absent && (len(header.Values) > 0 || header.Count >= 0) {
+			return nil, errors.Errorf("absent header %s cannot have values or count", name)
+		}
+		if header.Count >= 0 {
+			operator := configHeader.CountOperator
+			if operator == "" {
+				operator = string(metrics.MetricEqOperator)
+			}
+			var err error
+			if header.CountOperator, err = metrics.ConvertToOperator(operator); err != nil {
+				return nil, err
+			}
+		}
+		if header.Match == MatchTypeRegexp {
+			for _, value := range header.Values {
+				if header.IgnoreCase {
+					value = "(?i)" + value
+				}
+				pattern, err := regexp.Compile(value)
+				if err != nil {
+					return nil, errors.Errorf("invalid regexp for header %s: %v", name, err)
+				}
+				header.Patterns = append(header.Patterns, pattern)
+			}
+		}
+		headers = append(headers, header)
+	}
+
+	return headers, nil
+}
+
+// HeaderExpectation holds checks of all values of the header. The header name is matched case-insensitively.
+type HeaderExpectation struct {
+	Name string
+	// Values are the expected values that are matched based on the match type and mode. No values means that only
+	// the header presence and count is checked.
+	Values     []string
+	Match      MatchType
+	Mode       HeaderMode
+	IgnoreCase bool
+	// Absent specifies that the header must not be present in the response.
+	Absent bool
+	// Count is the number of header values compared by the count operator or -1 if not checked.
+	Count         int
+	CountOperator metrics.MetricOperator
+	// Patterns are the compiled values for the regexp match.
+	Patterns []*regexp.Regexp
+}
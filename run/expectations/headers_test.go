@@ -0,0 +1,161 @@
+package expectations
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/metrics"
+	"regexp"
+	"testing"
+)
+
+func Test_nativeMaker_makeHeaderExpectations(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      types.ResponseHeaders
+		expectError bool
+		expected    []HeaderExpectation
+		errorMsg    string
+	}{
+		{
+			name:     "no headers",
+			config:   types.ResponseHeaders{},
+			expected: nil,
+		},
+		{
+			name: "sorted headers with merged values",
+			config: types.ResponseHeaders{
+				"Server": {Value: "nginx", Match: "prefix", Mode: "all", IgnoreCase: true, Count: -1},
+				"Set-Cookie": {
+					Value:         "a=1",
+					Values:        []string{"b=2"},
+					Match:         "exact",
+					Mode:          "any",
+					Count:         2,
+					CountOperator: "ge",
+				},
+				"X-Powered-By": {Match: "exact", Mode: "any", Absent: true, Count: -1},
+			},
+			expected: []HeaderExpectation{
+				{
+					Name:       "Server",
+					Values:     []string{"nginx"},
+					Match:      MatchTypePrefix,
+					Mode:       HeaderModeAll,
+					IgnoreCase: true,
+					Count:      -1,
+				},
+				{
+					Name:          "Set-Cookie",
+					Values:        []string{"a=1", "b=2"},
+					Match:         MatchTypeExact,
+					Mode:          HeaderModeAny,
+					Count:         2,
+					CountOperator: metrics.MetricGeOperator,
+				},
+				{
+					Name:   "X-Powered-By",
+					Match:  MatchTypeExact,
+					Mode:   HeaderModeAny,
+					Absent: true,
+					Count:  -1,
+				},
+			},
+		},
+		{
+			name: "defaults for empty match, mode and count operator",
+			config: types.ResponseHeaders{
+				"Content-Type": {Value: "text/html"},
+			},
+			expected: []HeaderExpectation{
+				{
+					Name:          "Content-Type",
+					Values:        []string{"text/html"},
+					Match:         MatchTypeExact,
+					Mode:          HeaderModeAny,
+					Count:         0,
+					CountOperator: metrics.MetricEqOperator,
+				},
+			},
+		},
+		{
+			name: "regexp values compiled",
+			config: types.ResponseHeaders{
+				"Server": {Values: []string{"^nginx/", "^apache"}, Match: "regexp", IgnoreCase: true, Count: -1},
+			},
+			expected: []HeaderExpectation{
+				{
+					Name:       "Server",
+					Values:     []string{"^nginx/", "^apache"},
+					Match:      MatchTypeRegexp,
+					Mode:       HeaderModeAny,
+					IgnoreCase: true,
+					Count:      -1,
+					Patterns:   []*regexp.Regexp{regexp.MustCompile("(?i)^nginx/"), regexp.MustCompile("(?i)^apache")},
+				},
+			},
+		},
+		{
+			name: "invalid match type",
+			config: types.ResponseHeaders{
+				"Server": {Value: "nginx", Match: "invalid", Count: -1},
+			},
+			expectError: true,
+			errorMsg:    "invalid match type invalid for header Server",
+		},
+		{
+			name: "invalid mode",
+			config: types.ResponseHeaders{
+				"Server": {Value: "nginx", Mode: "some", Count: -1},
+			},
+			expectError: true,
+			errorMsg:    "invalid mode some for header Server",
+		},
+		{
+			name: "absent header with value",
+			config: types.ResponseHeaders{
+				"X-Powered-By": {Value: "PHP", Absent: true, Count: -1},
+			},
+			expectError: true,
+			errorMsg:    "absent header X-Powered-By cannot have values or count",
+		},
+		{
+			name: "absent header with count",
+			config: types.ResponseHeaders{
+				"X-Powered-By": {Absent: true, Count: 1},
+			},
+			expectError: true,
+			errorMsg:    "absent header X-Powered-By cannot have values or count",
+		},
+		{
+			name: "invalid count operator",
+			config: types.ResponseHeaders{
+				"Set-Cookie": {Count: 1, CountOperator: "xx"},
+			},
+			expectError: true,
+			errorMsg:    "invalid operator",
+		},
+		{
+			name: "invalid regexp",
+			config: types.ResponseHeaders{
+				"Server": {Value: "[a-", Match: "regexp", Count: -1},
+			},
+			expectError: true,
+			errorMsg:    "invalid regexp for header Server",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maker := &nativeMaker{}
+			result, err := maker.makeHeaderExpectations(tt.config)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
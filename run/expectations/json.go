@@ -0,0 +1,285 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expectations
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/metrics"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type JSONType string
+
+const (
+	JSONTypeNull    JSONType = "null"
+	JSONTypeBoolean JSONType = "boolean"
+	JSONTypeNumber  JSONType = "number"
+	JSONTypeString  JSONType = "string"
+	JSONTypeArray   JSONType = "array"
+	JSONTypeObject  JSONType = "object"
+)
+
+func (m *nativeMaker) makeJSONExpectation(config *types.ResponseJSON) (*JSONExpectation, error) {
+	checks := make([]JSONCheck, 0, len(config.Checks))
+	for _, configCheck := range config.Checks {
+		path, err := ParseJSONPath(configCheck.Path)
+		if err != nil {
+			return nil, err
+		}
+		check := JSONCheck{
+			Path:   path,
+			Exists: configCheck.Exists,
+			Type:   JSONType(configCheck.Type),
+			Length: configCheck.Length,
+			Value:  configCheck.Value,
+		}
+		switch check.Type {
+		case "", JSONTypeNull, JSONTypeBoolean, JSONTypeNumber, JSONTypeString, JSONTypeArray, JSONTypeObject:
+		default:
+			return nil, errors.Errorf("invalid JSON type %s for path %s", configCheck.Type, configCheck.Path)
+		}
+		if configCheck.Equals.Set {
+			// The expected value is normalized so it can be compared with the decoded body value.
+			check.CompareEquals = true
+			data, err := json.Marshal(configCheck.Equals.Value)
+			if err != nil {
+				return nil, errors.Errorf("invalid expected JSON value for path %s: %v", configCheck.Path, err)
+			}
+			if err = json.Unmarshal(data, &check.Equals); err != nil {
+				return nil, errors.Errorf("invalid expected JSON value for path %s: %v", configCheck.Path, err)
+			}
+		}
+		if configCheck.Regexp != "" {
+			if check.Regexp, err = regexp.Compile(configCheck.Regexp); err != nil {
+				return nil, errors.Errorf("invalid regexp for path %s: %v", configCheck.Path, err)
+			}
+		}
+		if configCheck.Operator != "" {
+			if check.Operator, err = metrics.ConvertToOperator(configCheck.Operator); err != nil {
+				return nil, err
+			}
+		}
+		checks = append(checks, check)
+	}
+
+	return &JSONExpectation{
+		Checks: checks,
+		Schema: config.Schema,
+	}, nil
+}
+
+// JSONExpectation holds checks of values selected from the JSON body and the name of the script resource with
+// the JSON Schema that the whole body is validated against.
+type JSONExpectation struct {
+	Checks []JSONCheck
+	Schema string
+}
+
+// JSONCheck holds checks for a value selected by the path. Only the set fields are checked.
+type JSONCheck struct {
+	Path *JSONPath
+	// Exists specifies whether the value should exist. Other checks are not done if it should not exist.
+	Exists bool
+	// CompareEquals specifies whether the value is compared with Equals.
+	CompareEquals bool
+	// Equals is the expected value decoded from JSON where nil is the JSON null.
+	Equals interface{}
+	Type   JSONType
+	// Length is the expected length of string, array or object value or -1 if not checked.
+	Length   int
+	Regexp   *regexp.Regexp
+	Operator metrics.MetricOperator
+	Value    float64
+}
+
+// JSONTypeOf returns the JSON type of the decoded value.
+func JSONTypeOf(value interface{}) JSONType {
+	switch value.(type) {
+	case nil:
+		return JSONTypeNull
+	case bool:
+		return JSONTypeBoolean
+	case float64, json.Number:
+		return JSONTypeNumber
+	case string:
+		return JSONTypeString
+	case []interface{}:
+		return JSONTypeArray
+	default:
+		return JSONTypeObject
+	}
+}
+
+// JSONLength returns the length of string (in characters), array or object value.
+func JSONLength(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case string:
+		return utf8.RuneCountInString(v), nil
+	case []interface{}:
+		return len(v), nil
+	case map[string]interface{}:
+		return len(v), nil
+	default:
+		return 0, errors.Errorf("length is not supported for JSON type %s", JSONTypeOf(value))
+	}
+}
+
+type jsonPathSegmentType int
+
+const (
+	jsonPathName jsonPathSegmentType = iota
+	jsonPathIndex
+	jsonPathWildcard
+)
+
+type jsonPathSegment struct {
+	segmentType jsonPathSegmentType
+	name        string
+	index       int
+}
+
+// JSONPath is a selector supporting a subset of JSONPath. It consists of optional root `$` followed by child
+// names (`.name` or `['name']`), array indexes that can be negative to count from the end (`[0]`, `[-1]`) and
+// wildcards selecting all array elements or object values (`.*` or `[*]`). The leading dot can be omitted
+// (`items[0].name`). If the path contains a wildcard, the selected value is an array of all matched values.
+type JSONPath struct {
+	expr     string
+	segments []jsonPathSegment
+}
+
+// ParseJSONPath parses the JSONPath expression.
+func ParseJSONPath(expr string) (*JSONPath, error) {
+	path := &JSONPath{expr: expr}
+	s := strings.TrimPrefix(strings.TrimSpace(expr), "$")
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, errors.Errorf("invalid JSON path %s: missing closing bracket", expr)
+			}
+			content := s[i+1 : i+end]
+			if len(content) > 0 && (content[0] == '\'' || content[0] == '"') {
+				// Quoted names can contain brackets so the closing quote needs to be found first.
+				quoteEnd := strings.IndexByte(s[i+2:], content[0])
+				if quoteEnd < 0 || i+2+quoteEnd+1 >= len(s) || s[i+2+quoteEnd+1] != ']' {
+					return nil, errors.Errorf("invalid JSON path %s: invalid quoted name", expr)
+				}
+				path.segments = append(path.segments, jsonPathSegment{
+					segmentType: jsonPathName,
+					name:        s[i+2 : i+2+quoteEnd],
+				})
+				i += quoteEnd + 4
+				continue
+			}
+			if content == "*" {
+				path.segments = append(path.segments, jsonPathSegment{segmentType: jsonPathWildcard})
+			} else {
+				index, err := strconv.Atoi(strings.TrimSpace(content))
+				if err != nil {
+					return nil, errors.Errorf("invalid JSON path %s: invalid index %s", expr, content)
+				}
+				path.segments = append(path.segments, jsonPathSegment{segmentType: jsonPathIndex, index: index})
+			}
+			i += end + 1
+		case s[i] == '.' || i == 0:
+			if s[i] == '.' {
+				i++
+			}
+			end := strings.IndexAny(s[i:], ".[")
+			if end < 0 {
+				end = len(s) - i
+			}
+			name := s[i : i+end]
+			if name == "" {
+				return nil, errors.Errorf("invalid JSON path %s: empty name", expr)
+			}
+			if name == "*" {
+				path.segments = append(path.segments, jsonPathSegment{segmentType: jsonPathWildcard})
+			} else {
+				path.segments = append(path.segments, jsonPathSegment{segmentType: jsonPathName, name: name})
+			}
+			i += end
+		default:
+			return nil, errors.Errorf("invalid JSON path %s: unexpected character %c", expr, s[i])
+		}
+	}
+	return path, nil
+}
+
+// Select returns the value selected from the decoded JSON data and whether it was found.
+func (p *JSONPath) Select(data interface{}) (interface{}, bool) {
+	nodes := []interface{}{data}
+	projection := false
+	for _, segment := range p.segments {
+		var next []interface{}
+		for _, node := range nodes {
+			switch segment.segmentType {
+			case jsonPathName:
+				if object, ok := node.(map[string]interface{}); ok {
+					if value, ok := object[segment.name]; ok {
+						next = append(next, value)
+					}
+				}
+			case jsonPathIndex:
+				if array, ok := node.([]interface{}); ok {
+					index := segment.index
+					if index < 0 {
+						index += len(array)
+					}
+					if index >= 0 && index < len(array) {
+						next = append(next, array[index])
+					}
+				}
+			case jsonPathWildcard:
+				projection = true
+				switch container := node.(type) {
+				case []interface{}:
+					next = append(next, container...)
+				case map[string]interface{}:
+					keys := make([]string, 0, len(container))
+					for key := range container {
+						keys = append(keys, key)
+					}
+					sort.Strings(keys)
+					for _, key := range keys {
+						next = append(next, container[key])
+					}
+				}
+			}
+		}
+		nodes = next
+	}
+	if projection {
+		if nodes == nil {
+			nodes = []interface{}{}
+		}
+		return nodes, true
+	}
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return nodes[0], true
+}
+
+func (p *JSONPath) String() string {
+	return p.expr
+}
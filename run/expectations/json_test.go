@@ -0,0 +1,249 @@
+package expectations
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected []jsonPathSegment
+		errorMsg string
+	}{
+		{
+			name: "root only",
+			expr: "$",
+		},
+		{
+			name:     "dot notation",
+			expr:     "$.user.name",
+			expected: []jsonPathSegment{{name: "user"}, {name: "name"}},
+		},
+		{
+			name:     "without root",
+			expr:     "items[0].name",
+			expected: []jsonPathSegment{{name: "items"}, {segmentType: jsonPathIndex}, {name: "name"}},
+		},
+		{
+			name: "bracket notation with quoted names and negative index",
+			expr: `$['a.b']["c[d]"][-1]`,
+			expected: []jsonPathSegment{
+				{name: "a.b"},
+				{name: "c[d]"},
+				{segmentType: jsonPathIndex, index: -1},
+			},
+		},
+		{
+			name: "wildcards",
+			expr: "$.items[*].*",
+			expected: []jsonPathSegment{
+				{name: "items"},
+				{segmentType: jsonPathWildcard},
+				{segmentType: jsonPathWildcard},
+			},
+		},
+		{
+			name:     "missing closing bracket",
+			expr:     "$.items[0",
+			errorMsg: "invalid JSON path $.items[0: missing closing bracket",
+		},
+		{
+			name:     "invalid index",
+			expr:     "$.items[first]",
+			errorMsg: "invalid JSON path $.items[first]: invalid index first",
+		},
+		{
+			name:     "invalid quoted name",
+			expr:     "$['items]",
+			errorMsg: "invalid JSON path $['items]: invalid quoted name",
+		},
+		{
+			name:     "empty name",
+			expr:     "$.items..name",
+			errorMsg: "invalid JSON path $.items..name: empty name",
+		},
+		{
+			name:     "unexpected character",
+			expr:     "$.items[0]name",
+			errorMsg: "invalid JSON path $.items[0]name: unexpected character n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := ParseJSONPath(tt.expr)
+			if tt.errorMsg != "" {
+				assert.EqualError(t, err, tt.errorMsg)
+				assert.Nil(t, path)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, path.segments)
+				assert.Equal(t, tt.expr, path.String())
+			}
+		})
+	}
+}
+
+func TestJSONPath_Select(t *testing.T) {
+	var data interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"user": {"name": "John", "roles": ["admin", "dev"], "manager": null},
+		"items": [{"id": 1, "tags": ["a"]}, {"id": 2, "tags": []}, {"id": 3}],
+		"empty": [],
+		"a.b": true
+	}`), &data))
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected interface{}
+		found    bool
+	}{
+		{
+			name:     "root",
+			expr:     "$",
+			expected: data,
+			found:    true,
+		},
+		{
+			name:     "nested name",
+			expr:     "$.user.name",
+			expected: "John",
+			found:    true,
+		},
+		{
+			name:     "null value",
+			expr:     "$.user.manager",
+			expected: nil,
+			found:    true,
+		},
+		{
+			name:     "index",
+			expr:     "$.user.roles[1]",
+			expected: "dev",
+			found:    true,
+		},
+		{
+			name:     "negative index",
+			expr:     "$.items[-1].id",
+			expected: float64(3),
+			found:    true,
+		},
+		{
+			name:     "quoted name",
+			expr:     "$['a.b']",
+			expected: true,
+			found:    true,
+		},
+		{
+			name:     "array wildcard",
+			expr:     "$.items[*].id",
+			expected: []interface{}{float64(1), float64(2), float64(3)},
+			found:    true,
+		},
+		{
+			name:     "object wildcard sorted by keys",
+			expr:     "$.items[0].*",
+			expected: []interface{}{float64(1), []interface{}{"a"}},
+			found:    true,
+		},
+		{
+			name:     "wildcard on empty array",
+			expr:     "$.empty[*]",
+			expected: []interface{}{},
+			found:    true,
+		},
+		{
+			name:  "missing name",
+			expr:  "$.user.email",
+			found: false,
+		},
+		{
+			name:  "index out of range",
+			expr:  "$.items[3]",
+			found: false,
+		},
+		{
+			name:  "index on object",
+			expr:  "$.user[0]",
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := ParseJSONPath(tt.expr)
+			require.NoError(t, err)
+			value, found := path.Select(data)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestJSONTypeOf(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected JSONType
+	}{
+		{nil, JSONTypeNull},
+		{true, JSONTypeBoolean},
+		{float64(1), JSONTypeNumber},
+		{json.Number("1"), JSONTypeNumber},
+		{"str", JSONTypeString},
+		{[]interface{}{}, JSONTypeArray},
+		{map[string]interface{}{}, JSONTypeObject},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.expected), func(t *testing.T) {
+			assert.Equal(t, tt.expected, JSONTypeOf(tt.value))
+		})
+	}
+}
+
+func TestJSONLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected int
+		errorMsg string
+	}{
+		{
+			name:     "string length in characters",
+			value:    "čaj",
+			expected: 3,
+		},
+		{
+			name:     "array length",
+			value:    []interface{}{1, 2},
+			expected: 2,
+		},
+		{
+			name:     "object length",
+			value:    map[string]interface{}{"a": 1},
+			expected: 1,
+		},
+		{
+			name:     "unsupported type",
+			value:    float64(1),
+			errorMsg: "length is not supported for JSON type number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			length, err := JSONLength(tt.value)
+			if tt.errorMsg != "" {
+				assert.EqualError(t, err, tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, length)
+			}
+		})
+	}
+}
@@ -15,8 +15,10 @@
 package expectations
 
 import (
+	"github.com/pkg/errors"
 	"github.com/wstool/wst/conf/types"
 	"github.com/wstool/wst/run/metrics"
+	"time"
 )
 
 func (m *nativeMaker) MakeMetricsExpectation(
@@ -28,16 +30,41 @@ func (m *nativeMaker) MakeMetricsExpectation(
 		if err != nil {
 			return nil, err
 		}
-		rules = append(rules, MetricRule{
+		rule := MetricRule{
 			Metric:   configRule.Metric,
 			Operator: operator,
 			Value:    configRule.Value,
-		})
+		}
+		if configRule.Aggregation != "" {
+			if rule.Aggregation, err = metrics.ConvertToAggregation(configRule.Aggregation); err != nil {
+				return nil, err
+			}
+			rule.Window = metrics.Window{
+				From: time.Duration(configRule.From) * time.Millisecond,
+				To:   time.Duration(configRule.To) * time.Millisecond,
+			}
+			if configRule.From < 0 || configRule.To < 0 || (configRule.To != 0 && configRule.To <= configRule.From) {
+				return nil, errors.Errorf("invalid time window from %d to %d for metric %s",
+					configRule.From, configRule.To, configRule.Metric)
+			}
+		} else if configRule.From != 0 || configRule.To != 0 {
+			return nil, errors.Errorf("time window for metric %s requires aggregation", configRule.Metric)
+		}
+		rules = append(rules, rule)
+	}
+
+	var expr *Expr
+	if config.Expr != "" {
+		var err error
+		if expr, err = CompileExpr(config.Expr, ExprVariableMetrics); err != nil {
+			return nil, err
+		}
 	}
 
 	return &MetricsExpectation{
 		Id:    config.Id,
 		Rules: rules,
+		Expr:  expr,
 	}, nil
 }
 
@@ -45,9 +72,14 @@ type MetricRule struct {
 	Metric   string
 	Operator metrics.MetricOperator
 	Value    float64
+	// Aggregation is set for rules checking time series values in the window.
+	Aggregation metrics.Aggregation
+	Window      metrics.Window
 }
 
 type MetricsExpectation struct {
 	Id    string
 	Rules []MetricRule
+	// Expr is the expression over the metric values that has to evaluate to true or nil if not set.
+	Expr *Expr
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/wstool/wst/conf/types"
 	"github.com/wstool/wst/run/metrics"
 	"testing"
+	"time"
 )
 
 func Test_nativeMaker_MakeMetricsExpectation(t *testing.T) {
@@ -13,6 +14,7 @@ func Test_nativeMaker_MakeMetricsExpectation(t *testing.T) {
 		name        string
 		config      *types.MetricsExpectation
 		expectError bool
+		expectedErr string
 		expected    *MetricsExpectation
 	}{
 		{
@@ -39,6 +41,84 @@ func Test_nativeMaker_MakeMetricsExpectation(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid configuration with time window",
+			config: &types.MetricsExpectation{
+				Id: "test-id",
+				Rules: []types.MetricRule{
+					{
+						Metric:      "LatencyP99",
+						Operator:    "lt",
+						Value:       50000000,
+						From:        1000,
+						To:          5000,
+						Aggregation: "max",
+					},
+					{
+						Metric:      "Rate",
+						Operator:    "gt",
+						Value:       10,
+						Aggregation: "min",
+					},
+				},
+			},
+			expected: &MetricsExpectation{
+				Id: "test-id",
+				Rules: []MetricRule{
+					{
+						Metric:      "LatencyP99",
+						Operator:    metrics.MetricLtOperator,
+						Value:       50000000,
+						Aggregation: metrics.AggregationMax,
+						Window:      metrics.Window{From: time.Second, To: 5 * time.Second},
+					},
+					{
+						Metric:      "Rate",
+						Operator:    metrics.MetricGtOperator,
+						Value:       10,
+						Aggregation: metrics.AggregationMin,
+					},
+				},
+			},
+		},
+		{
+			name: "invalid aggregation",
+			config: &types.MetricsExpectation{
+				Rules: []types.MetricRule{
+					{Metric: "Rate", Operator: "gt", Aggregation: "sum"},
+				},
+			},
+			expectError: true,
+			expectedErr: "invalid aggregation sum",
+		},
+		{
+			name: "invalid time window",
+			config: &types.MetricsExpectation{
+				Rules: []types.MetricRule{
+					{Metric: "Rate", Operator: "gt", From: 5000, To: 1000, Aggregation: "avg"},
+				},
+			},
+			expectError: true,
+			expectedErr: "invalid time window from 5000 to 1000 for metric Rate",
+		},
+		{
+			name: "time window without aggregation",
+			config: &types.MetricsExpectation{
+				Rules: []types.MetricRule{
+					{Metric: "Rate", Operator: "gt", From: 1000},
+				},
+			},
+			expectError: true,
+			expectedErr: "time window for metric Rate requires aggregation",
+		},
+		{
+			name: "invalid expression",
+			config: &types.MetricsExpectation{
+				Id:   "test",
+				Expr: "metrics.Rate >",
+			},
+			expectError: true,
+		},
 		{
 			name: "invalid operator",
 			config: &types.MetricsExpectation{
@@ -61,6 +141,9 @@ func Test_nativeMaker_MakeMetricsExpectation(t *testing.T) {
 			result, err := maker.MakeMetricsExpectation(tt.config)
 			if tt.expectError {
 				assert.Error(t, err)
+				if tt.expectedErr != "" {
+					assert.EqualError(t, err, tt.expectedErr)
+				}
 			} else {
 				require.NoError(t, err)
 				assert.Equal(t, tt.expected, result)
@@ -68,3 +151,14 @@ func Test_nativeMaker_MakeMetricsExpectation(t *testing.T) {
 		})
 	}
 }
+
+func Test_nativeMaker_MakeMetricsExpectation_Expr(t *testing.T) {
+	maker := &nativeMaker{}
+	result, err := maker.MakeMetricsExpectation(&types.MetricsExpectation{
+		Id:   "test",
+		Expr: "metrics.LatencyP99 < duration('100ms')",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Expr)
+	assert.Equal(t, "metrics.LatencyP99 < duration('100ms')", result.Expr.String())
+}
@@ -17,6 +17,8 @@ package expectations
 import (
 	"fmt"
 	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/metrics"
+	"time"
 )
 
 func (m *nativeMaker) MakeOutputExpectation(
@@ -41,6 +43,62 @@ func (m *nativeMaker) MakeOutputExpectation(
 		return nil, fmt.Errorf("invalid output type: %v", config.Type)
 	}
 
+	mode := OutputMode(config.Mode)
+	switch mode {
+	case "", OutputModeExpect:
+	case OutputModeForbid:
+		if len(config.Messages) == 0 {
+			return nil, fmt.Errorf("forbid mode requires messages")
+		}
+		if config.Expr != "" {
+			return nil, fmt.Errorf("expression cannot be used in forbid mode")
+		}
+		if config.From == OutputFromStart {
+			return nil, fmt.Errorf("forbid mode cannot read from start")
+		}
+	default:
+		return nil, fmt.Errorf("invalid output mode: %v", config.Mode)
+	}
+	if config.Duration < 0 {
+		return nil, fmt.Errorf("invalid output duration: %d", config.Duration)
+	}
+	count := 0
+	var countOperator metrics.MetricOperator
+	if config.Count >= 0 {
+		count = config.Count
+		if mode == OutputModeForbid {
+			return nil, fmt.Errorf("count cannot be used in forbid mode")
+		}
+		if config.Expr != "" {
+			return nil, fmt.Errorf("expression cannot be used with count")
+		}
+		if len(config.Messages) == 0 {
+			return nil, fmt.Errorf("count requires messages")
+		}
+		operator := config.CountOperator
+		if operator == "" {
+			operator = string(metrics.MetricEqOperator)
+		}
+		var err error
+		if countOperator, err = metrics.ConvertToOperator(operator); err != nil {
+			return nil, err
+		}
+	}
+	if config.Duration > 0 && mode != OutputModeForbid && countOperator == "" {
+		return nil, fmt.Errorf("duration can be used only in forbid mode or with count")
+	}
+	if config.Checkpoint == OutputFromStart || config.Checkpoint == OutputFromNow {
+		return nil, fmt.Errorf("checkpoint name %s is reserved", config.Checkpoint)
+	}
+
+	var expr *Expr
+	if config.Expr != "" {
+		var err error
+		if expr, err = CompileExpr(config.Expr, ExprVariableOutput); err != nil {
+			return nil, err
+		}
+	}
+
 	return &OutputExpectation{
 		Command:        config.Command,
 		OrderType:      orderType,
@@ -48,6 +106,13 @@ func (m *nativeMaker) MakeOutputExpectation(
 		OutputType:     outputType,
 		Messages:       config.Messages,
 		RenderTemplate: config.RenderTemplate,
+		Expr:           expr,
+		Mode:           mode,
+		Duration:       time.Duration(config.Duration) * time.Millisecond,
+		From:           config.From,
+		Checkpoint:     config.Checkpoint,
+		Count:          count,
+		CountOperator:  countOperator,
 	}, nil
 }
 
@@ -58,4 +123,19 @@ type OutputExpectation struct {
 	OutputType     OutputType
 	Messages       []string
 	RenderTemplate bool
+	// Expr is the expression over the output lines that has to evaluate to true or nil if not set.
+	Expr *Expr
+	// Mode is the forbid mode if the messages must not appear in the output.
+	Mode OutputMode
+	// Duration is the time for checking that forbidden messages do not appear or the matching lines are counted. It
+	// is 0 if limited by the timeout only.
+	Duration time.Duration
+	// From is the output position to read from which is either start (if empty), now or a checkpoint name.
+	From string
+	// Checkpoint is the name of the checkpoint storing the output position after the expectation succeeds.
+	Checkpoint string
+	// Count is the number of lines matching each message compared by the count operator.
+	Count int
+	// CountOperator is the operator for comparing the lines count or empty if the count is not checked.
+	CountOperator metrics.MetricOperator
 }
@@ -4,7 +4,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/metrics"
 	"testing"
+	"time"
 )
 
 func Test_nativeMaker_MakeOutputExpectation(t *testing.T) {
@@ -22,6 +24,7 @@ func Test_nativeMaker_MakeOutputExpectation(t *testing.T) {
 				Match:    "exact",
 				Type:     "stdout",
 				Messages: []string{"Hello, world!"},
+				Count:    -1,
 			},
 			expectError: false,
 			expected: &OutputExpectation{
@@ -40,6 +43,7 @@ func Test_nativeMaker_MakeOutputExpectation(t *testing.T) {
 				Match:    "prefix",
 				Type:     "stdout",
 				Messages: []string{"Hello"},
+				Count:    -1,
 			},
 			expectError: false,
 			expected: &OutputExpectation{
@@ -58,6 +62,7 @@ func Test_nativeMaker_MakeOutputExpectation(t *testing.T) {
 				Match:    "suffix",
 				Type:     "stdout",
 				Messages: []string{"world!"},
+				Count:    -1,
 			},
 			expectError: false,
 			expected: &OutputExpectation{
@@ -75,6 +80,7 @@ func Test_nativeMaker_MakeOutputExpectation(t *testing.T) {
 				Match:    "infix",
 				Type:     "stdout",
 				Messages: []string{"llo, wor"},
+				Count:    -1,
 			},
 			expectError: false,
 			expected: &OutputExpectation{
@@ -85,12 +91,222 @@ func Test_nativeMaker_MakeOutputExpectation(t *testing.T) {
 				RenderTemplate: false,
 			},
 		},
+		{
+			name: "valid configuration with forbid mode and duration",
+			config: &types.OutputExpectation{
+				Order:    "fixed",
+				Match:    "regexp",
+				Type:     "stderr",
+				Messages: []string{"exited on signal 11"},
+				Mode:     "forbid",
+				Duration: 1500,
+				Count:    -1,
+			},
+			expected: &OutputExpectation{
+				OrderType:  OrderTypeFixed,
+				MatchType:  MatchTypeRegexp,
+				OutputType: OutputTypeStderr,
+				Messages:   []string{"exited on signal 11"},
+				Mode:       OutputModeForbid,
+				Duration:   1500 * time.Millisecond,
+			},
+		},
+		{
+			name: "invalid output mode",
+			config: &types.OutputExpectation{
+				Order:    "fixed",
+				Match:    "exact",
+				Type:     "any",
+				Messages: []string{"test"},
+				Mode:     "unknown",
+				Count:    -1,
+			},
+			expectError: true,
+			errorMsg:    "invalid output mode: unknown",
+		},
+		{
+			name: "forbid mode without messages",
+			config: &types.OutputExpectation{
+				Order: "fixed",
+				Match: "exact",
+				Type:  "any",
+				Mode:  "forbid",
+				Count: -1,
+			},
+			expectError: true,
+			errorMsg:    "forbid mode requires messages",
+		},
+		{
+			name: "forbid mode with expression",
+			config: &types.OutputExpectation{
+				Order:    "fixed",
+				Match:    "exact",
+				Type:     "any",
+				Messages: []string{"test"},
+				Mode:     "forbid",
+				Expr:     "true",
+				Count:    -1,
+			},
+			expectError: true,
+			errorMsg:    "expression cannot be used in forbid mode",
+		},
+		{
+			name: "negative duration",
+			config: &types.OutputExpectation{
+				Order:    "fixed",
+				Match:    "exact",
+				Type:     "any",
+				Messages: []string{"test"},
+				Mode:     "forbid",
+				Duration: -1,
+				Count:    -1,
+			},
+			expectError: true,
+			errorMsg:    "invalid output duration: -1",
+		},
+		{
+			name: "duration in expect mode",
+			config: &types.OutputExpectation{
+				Order:    "fixed",
+				Match:    "exact",
+				Type:     "any",
+				Messages: []string{"test"},
+				Mode:     "expect",
+				Duration: 1000,
+				Count:    -1,
+			},
+			expectError: true,
+			errorMsg:    "duration can be used only in forbid mode or with count",
+		},
+		{
+			name: "valid configuration with count, duration and cursors",
+			config: &types.OutputExpectation{
+				Order:         "fixed",
+				Match:         "regexp",
+				Type:          "stderr",
+				Messages:      []string{`child \d+ started`},
+				Duration:      2000,
+				From:          "respawn",
+				Checkpoint:    "started",
+				Count:         2,
+				CountOperator: "ge",
+			},
+			expected: &OutputExpectation{
+				OrderType:     OrderTypeFixed,
+				MatchType:     MatchTypeRegexp,
+				OutputType:    OutputTypeStderr,
+				Messages:      []string{`child \d+ started`},
+				Duration:      2 * time.Second,
+				From:          "respawn",
+				Checkpoint:    "started",
+				Count:         2,
+				CountOperator: metrics.MetricGeOperator,
+			},
+		},
+		{
+			name: "valid configuration with zero count and default operator",
+			config: &types.OutputExpectation{
+				Order:    "fixed",
+				Match:    "infix",
+				Type:     "any",
+				Messages: []string{"exited on signal"},
+				From:     "now",
+				Count:    0,
+			},
+			expected: &OutputExpectation{
+				OrderType:     OrderTypeFixed,
+				MatchType:     MatchTypeInfix,
+				OutputType:    OutputTypeAny,
+				Messages:      []string{"exited on signal"},
+				From:          "now",
+				Count:         0,
+				CountOperator: metrics.MetricEqOperator,
+			},
+		},
+		{
+			name: "from start in forbid mode",
+			config: &types.OutputExpectation{
+				Order:    "fixed",
+				Match:    "exact",
+				Type:     "any",
+				Messages: []string{"test"},
+				Mode:     "forbid",
+				From:     "start",
+				Count:    -1,
+			},
+			expectError: true,
+			errorMsg:    "forbid mode cannot read from start",
+		},
+		{
+			name: "count in forbid mode",
+			config: &types.OutputExpectation{
+				Order:    "fixed",
+				Match:    "exact",
+				Type:     "any",
+				Messages: []string{"test"},
+				Mode:     "forbid",
+				Count:    1,
+			},
+			expectError: true,
+			errorMsg:    "count cannot be used in forbid mode",
+		},
+		{
+			name: "count with expression",
+			config: &types.OutputExpectation{
+				Order:    "fixed",
+				Match:    "exact",
+				Type:     "any",
+				Messages: []string{"test"},
+				Expr:     "size(output.lines) > 0",
+				Count:    1,
+			},
+			expectError: true,
+			errorMsg:    "expression cannot be used with count",
+		},
+		{
+			name: "count without messages",
+			config: &types.OutputExpectation{
+				Order: "fixed",
+				Match: "exact",
+				Type:  "any",
+				Count: 1,
+			},
+			expectError: true,
+			errorMsg:    "count requires messages",
+		},
+		{
+			name: "invalid count operator",
+			config: &types.OutputExpectation{
+				Order:         "fixed",
+				Match:         "exact",
+				Type:          "any",
+				Messages:      []string{"test"},
+				Count:         1,
+				CountOperator: "between",
+			},
+			expectError: true,
+			errorMsg:    "invalid operator between",
+		},
+		{
+			name: "reserved checkpoint name",
+			config: &types.OutputExpectation{
+				Order:      "fixed",
+				Match:      "exact",
+				Type:       "any",
+				Messages:   []string{"test"},
+				Checkpoint: "now",
+				Count:      -1,
+			},
+			expectError: true,
+			errorMsg:    "checkpoint name now is reserved",
+		},
 		{
 			name: "invalid order type",
 			config: &types.OutputExpectation{
 				Order: "unknown",
 				Match: "exact",
 				Type:  "stdout",
+				Count: -1,
 			},
 			expectError: true,
 			errorMsg:    "invalid order type: unknown",
@@ -101,16 +317,30 @@ func Test_nativeMaker_MakeOutputExpectation(t *testing.T) {
 				Order: "fixed",
 				Match: "unknown",
 				Type:  "stdout",
+				Count: -1,
 			},
 			expectError: true,
 			errorMsg:    "invalid match type: unknown",
 		},
+		{
+			name: "invalid expression",
+			config: &types.OutputExpectation{
+				Order: "fixed",
+				Match: "exact",
+				Type:  "any",
+				Expr:  "size(output.lines)",
+				Count: -1,
+			},
+			expectError: true,
+			errorMsg:    "expression size(output.lines) must evaluate to bool but it evaluates to int",
+		},
 		{
 			name: "invalid output type",
 			config: &types.OutputExpectation{
 				Order: "fixed",
 				Match: "exact",
 				Type:  "unknown",
+				Count: -1,
 			},
 			expectError: true,
 			errorMsg:    "invalid output type: unknown",
@@ -131,3 +361,17 @@ func Test_nativeMaker_MakeOutputExpectation(t *testing.T) {
 		})
 	}
 }
+
+func Test_nativeMaker_MakeOutputExpectation_Expr(t *testing.T) {
+	maker := &nativeMaker{}
+	result, err := maker.MakeOutputExpectation(&types.OutputExpectation{
+		Order: "fixed",
+		Match: "exact",
+		Type:  "any",
+		Expr:  "output.lines.exists(l, l == 'ready')",
+		Count: -1,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Expr)
+	assert.Equal(t, "output.lines.exists(l, l == 'ready')", result.Expr.String())
+}
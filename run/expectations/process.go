@@ -0,0 +1,128 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expectations
+
+import (
+	"fmt"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/metrics"
+	"regexp"
+)
+
+func (m *nativeMaker) MakeProcessExpectation(
+	config *types.ProcessExpectation,
+) (*ProcessExpectation, error) {
+	scope := ProcessScope(config.Scope)
+	switch scope {
+	case "":
+		scope = ProcessScopeChildren
+	case ProcessScopeAll, ProcessScopeMain, ProcessScopeChildren, ProcessScopeDescendants:
+	default:
+		return nil, fmt.Errorf("invalid process scope: %v", config.Scope)
+	}
+
+	var name *regexp.Regexp
+	if config.Name != "" {
+		var err error
+		if name, err = regexp.Compile(config.Name); err != nil {
+			return nil, fmt.Errorf("invalid process name pattern %s: %v", config.Name, err)
+		}
+	}
+
+	var processMetrics []ProcessMetric
+	for _, configMetric := range config.Metrics {
+		metricType := ProcessMetricType(configMetric.Metric)
+		switch metricType {
+		case ProcessMetricCount, ProcessMetricRSS, ProcessMetricFDs, ProcessMetricUptime:
+		default:
+			return nil, fmt.Errorf("invalid process metric: %v", configMetric.Metric)
+		}
+		operator, err := metrics.ConvertToOperator(configMetric.Operator)
+		if err != nil {
+			return nil, err
+		}
+		aggregation := metrics.AggregationMax
+		switch metrics.Aggregation(configMetric.Aggregation) {
+		case "", metrics.AggregationMax:
+		case metrics.AggregationMin, metrics.AggregationAvg:
+			aggregation = metrics.Aggregation(configMetric.Aggregation)
+		default:
+			return nil, fmt.Errorf("invalid process metric aggregation: %v", configMetric.Aggregation)
+		}
+		processMetrics = append(processMetrics, ProcessMetric{
+			Metric:      metricType,
+			Aggregation: aggregation,
+			Operator:    operator,
+			Value:       configMetric.Value,
+		})
+	}
+
+	pids := ProcessPidsCheck(config.Pids)
+	switch pids {
+	case "":
+		pids = ProcessPidsAny
+	case ProcessPidsAny, ProcessPidsChanged, ProcessPidsUnchanged:
+	default:
+		return nil, fmt.Errorf("invalid process pids check: %v", config.Pids)
+	}
+	if pids == ProcessPidsAny && config.Since != "" {
+		return nil, fmt.Errorf("since checkpoint %s requires pids check", config.Since)
+	}
+	if pids != ProcessPidsAny && config.Since == "" {
+		return nil, fmt.Errorf("pids check %s requires since checkpoint", pids)
+	}
+
+	var expr *Expr
+	if config.Expr != "" {
+		var err error
+		if expr, err = CompileExpr(config.Expr, ExprVariableProcess); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ProcessExpectation{
+		Scope:      scope,
+		Name:       name,
+		Metrics:    processMetrics,
+		Checkpoint: config.Checkpoint,
+		Since:      config.Since,
+		Pids:       pids,
+		Expr:       expr,
+	}, nil
+}
+
+// ProcessMetric compares the aggregated value of the selected processes with the expected value. The count metric
+// is the number of the selected processes, rss is in bytes and uptime is in milliseconds.
+type ProcessMetric struct {
+	Metric      ProcessMetricType
+	Aggregation metrics.Aggregation
+	Operator    metrics.MetricOperator
+	Value       float64
+}
+
+type ProcessExpectation struct {
+	// Scope selects the processes of the service task tree that are checked.
+	Scope ProcessScope
+	// Name is the pattern that the selected process names have to match or nil if all processes are selected.
+	Name    *regexp.Regexp
+	Metrics []ProcessMetric
+	// Checkpoint is the name under which the selected process pids are stored if the expectation matches.
+	Checkpoint string
+	// Since is the checkpoint name whose pids are compared with the selected pids using Pids check.
+	Since string
+	Pids  ProcessPidsCheck
+	// Expr is the expression over the selected processes that has to evaluate to true or nil if not set.
+	Expr *Expr
+}
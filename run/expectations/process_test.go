@@ -0,0 +1,161 @@
+package expectations
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/metrics"
+	"regexp"
+	"testing"
+)
+
+func Test_nativeMaker_MakeProcessExpectation(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *types.ProcessExpectation
+		expectError bool
+		expected    *ProcessExpectation
+		errorMsg    string
+	}{
+		{
+			name: "valid process expectation with metrics and checkpoint",
+			config: &types.ProcessExpectation{
+				Scope: "children",
+				Name:  "^php-fpm: pool",
+				Metrics: []types.ProcessMetric{
+					{Metric: "count", Aggregation: "max", Operator: "eq", Value: 5},
+					{Metric: "rss", Aggregation: "avg", Operator: "lt", Value: 67108864},
+				},
+				Checkpoint: "before",
+				Pids:       "any",
+			},
+			expected: &ProcessExpectation{
+				Scope: ProcessScopeChildren,
+				Name:  regexp.MustCompile("^php-fpm: pool"),
+				Metrics: []ProcessMetric{
+					{
+						Metric:      ProcessMetricCount,
+						Aggregation: metrics.AggregationMax,
+						Operator:    metrics.MetricEqOperator,
+						Value:       5,
+					},
+					{
+						Metric:      ProcessMetricRSS,
+						Aggregation: metrics.AggregationAvg,
+						Operator:    metrics.MetricLtOperator,
+						Value:       67108864,
+					},
+				},
+				Checkpoint: "before",
+				Pids:       ProcessPidsAny,
+			},
+		},
+		{
+			name: "valid process expectation with defaults and pids check",
+			config: &types.ProcessExpectation{
+				Metrics: []types.ProcessMetric{
+					{Metric: "uptime", Operator: "ge", Value: 1000},
+				},
+				Since: "before",
+				Pids:  "changed",
+			},
+			expected: &ProcessExpectation{
+				Scope: ProcessScopeChildren,
+				Metrics: []ProcessMetric{
+					{
+						Metric:      ProcessMetricUptime,
+						Aggregation: metrics.AggregationMax,
+						Operator:    metrics.MetricGeOperator,
+						Value:       1000,
+					},
+				},
+				Since: "before",
+				Pids:  ProcessPidsChanged,
+			},
+		},
+		{
+			name:        "invalid scope",
+			config:      &types.ProcessExpectation{Scope: "siblings"},
+			expectError: true,
+			errorMsg:    "invalid process scope: siblings",
+		},
+		{
+			name:        "invalid name pattern",
+			config:      &types.ProcessExpectation{Name: "php-fpm("},
+			expectError: true,
+			errorMsg:    "invalid process name pattern php-fpm(",
+		},
+		{
+			name: "invalid metric",
+			config: &types.ProcessExpectation{
+				Metrics: []types.ProcessMetric{{Metric: "cpu", Operator: "lt", Value: 1}},
+			},
+			expectError: true,
+			errorMsg:    "invalid process metric: cpu",
+		},
+		{
+			name: "invalid metric operator",
+			config: &types.ProcessExpectation{
+				Metrics: []types.ProcessMetric{{Metric: "fds", Operator: "lte", Value: 1}},
+			},
+			expectError: true,
+			errorMsg:    "invalid operator lte",
+		},
+		{
+			name: "invalid metric aggregation",
+			config: &types.ProcessExpectation{
+				Metrics: []types.ProcessMetric{{Metric: "fds", Aggregation: "last", Operator: "lt", Value: 1}},
+			},
+			expectError: true,
+			errorMsg:    "invalid process metric aggregation: last",
+		},
+		{
+			name:        "invalid pids check",
+			config:      &types.ProcessExpectation{Since: "before", Pids: "same"},
+			expectError: true,
+			errorMsg:    "invalid process pids check: same",
+		},
+		{
+			name:        "since without pids check",
+			config:      &types.ProcessExpectation{Since: "before", Pids: "any"},
+			expectError: true,
+			errorMsg:    "since checkpoint before requires pids check",
+		},
+		{
+			name:        "pids check without since",
+			config:      &types.ProcessExpectation{Pids: "unchanged"},
+			expectError: true,
+			errorMsg:    "pids check unchanged requires since checkpoint",
+		},
+		{
+			name:        "invalid expression",
+			config:      &types.ProcessExpectation{Expr: "size(process.processes)"},
+			expectError: true,
+			errorMsg:    "expression size(process.processes) must evaluate to bool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maker := &nativeMaker{}
+			result, err := maker.MakeProcessExpectation(tt.config)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_nativeMaker_MakeProcessExpectation_Expr(t *testing.T) {
+	maker := &nativeMaker{}
+	result, err := maker.MakeProcessExpectation(&types.ProcessExpectation{
+		Expr: "process.processes.all(p, p.fds < 100)",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Expr)
+	assert.Equal(t, "process.processes.all(p, p.fds < 100)", result.Expr.String())
+}
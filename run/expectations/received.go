@@ -0,0 +1,71 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expectations
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/conf/types"
+)
+
+func (m *nativeMaker) MakeReceivedExpectation(
+	config *types.ReceivedExpectation,
+) (*ReceivedExpectation, error) {
+	matchType := MatchType(config.Body.Match)
+	if matchType != MatchTypeNone &&
+		matchType != MatchTypeExact &&
+		matchType != MatchTypeRegexp &&
+		matchType != MatchTypePrefix &&
+		matchType != MatchTypeSuffix &&
+		matchType != MatchTypeInfix {
+		return nil, fmt.Errorf("invalid match type: %v", config.Body.Match)
+	}
+	if config.Body.SHA256 != "" || config.Body.File != "" || config.Body.Raw {
+		return nil, errors.New("received body can be checked only by content")
+	}
+
+	var expr *Expr
+	if config.Expr != "" {
+		var err error
+		if expr, err = CompileExpr(config.Expr, ExprVariableReceived); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ReceivedExpectation{
+		Method:             config.Method,
+		Path:               config.Path,
+		Count:              config.Count,
+		Headers:            config.Headers,
+		BodyContent:        config.Body.Content,
+		BodyMatch:          matchType,
+		BodyRenderTemplate: config.Body.RenderTemplate,
+		Expr:               expr,
+	}, nil
+}
+
+// ReceivedExpectation defines the requests that the mock server should have received.
+type ReceivedExpectation struct {
+	Method string
+	Path   string
+	// Count is the expected number of matching requests. Negative value means at least one.
+	Count              int
+	Headers            types.Headers
+	BodyContent        string
+	BodyMatch          MatchType
+	BodyRenderTemplate bool
+	// Expr is the expression over the matching requests that has to evaluate to true or nil if not set.
+	Expr *Expr
+}
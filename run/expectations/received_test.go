@@ -0,0 +1,110 @@
+package expectations
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/conf/types"
+	"testing"
+)
+
+func Test_nativeMaker_MakeReceivedExpectation(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *types.ReceivedExpectation
+		expectError bool
+		expected    *ReceivedExpectation
+		errorMsg    string
+	}{
+		{
+			name: "valid expectation with body match",
+			config: &types.ReceivedExpectation{
+				Method:  "POST",
+				Path:    "/api/data",
+				Count:   2,
+				Headers: map[string]string{"X-Forwarded-For": "127.0.0.1"},
+				Body: types.ResponseBody{
+					Match:          "infix",
+					Content:        "data",
+					RenderTemplate: true,
+				},
+			},
+			expected: &ReceivedExpectation{
+				Method:             "POST",
+				Path:               "/api/data",
+				Count:              2,
+				Headers:            map[string]string{"X-Forwarded-For": "127.0.0.1"},
+				BodyContent:        "data",
+				BodyMatch:          MatchTypeInfix,
+				BodyRenderTemplate: true,
+			},
+		},
+		{
+			name: "valid expectation without body",
+			config: &types.ReceivedExpectation{
+				Path:  "/",
+				Count: -1,
+			},
+			expected: &ReceivedExpectation{
+				Path:  "/",
+				Count: -1,
+			},
+		},
+		{
+			name: "invalid match type",
+			config: &types.ReceivedExpectation{
+				Body: types.ResponseBody{
+					Match: "invalid",
+				},
+			},
+			expectError: true,
+			errorMsg:    "invalid match type: invalid",
+		},
+		{
+			name: "invalid body SHA-256",
+			config: &types.ReceivedExpectation{
+				Body: types.ResponseBody{
+					SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+				},
+			},
+			expectError: true,
+			errorMsg:    "received body can be checked only by content",
+		},
+		{
+			name: "invalid expression",
+			config: &types.ReceivedExpectation{
+				Count: -1,
+				Expr:  "size(received.requests)",
+			},
+			expectError: true,
+			errorMsg:    "expression size(received.requests) must evaluate to bool but it evaluates to int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &nativeMaker{}
+
+			result, err := m.MakeReceivedExpectation(tt.config)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Equal(t, tt.errorMsg, err.Error())
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_nativeMaker_MakeReceivedExpectation_Expr(t *testing.T) {
+	maker := &nativeMaker{}
+	result, err := maker.MakeReceivedExpectation(&types.ReceivedExpectation{
+		Count: -1,
+		Expr:  "received.count > 0",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Expr)
+	assert.Equal(t, "received.count > 0", result.Expr.String())
+}
@@ -15,8 +15,12 @@
 package expectations
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/metrics"
+	"strings"
 )
 
 func (m *nativeMaker) MakeResponseExpectation(
@@ -32,21 +36,164 @@ func (m *nativeMaker) MakeResponseExpectation(
 		return nil, fmt.Errorf("invalid match type: %v", config.Body.Match)
 	}
 
+	bodySHA256 := strings.ToLower(config.Body.SHA256)
+	if bodySHA256 != "" || config.Body.File != "" {
+		if config.Body.Content != "" || (bodySHA256 != "" && config.Body.File != "") {
+			return nil, fmt.Errorf("body can set only one of content, sha256 and file")
+		}
+		if matchType != MatchTypeNone && matchType != MatchTypeExact {
+			return nil, fmt.Errorf("body match %v can be used only with content", config.Body.Match)
+		}
+		matchType = MatchTypeNone
+	}
+	if bodySHA256 != "" {
+		if digest, err := hex.DecodeString(bodySHA256); err != nil || len(digest) != sha256.Size {
+			return nil, fmt.Errorf("invalid body SHA-256: %v", config.Body.SHA256)
+		}
+	}
+
+	switch config.Encoding {
+	case "", "identity", "gzip", "deflate", "br", "zstd":
+	default:
+		return nil, fmt.Errorf("invalid content encoding: %v", config.Encoding)
+	}
+
+	connectionType := ConnectionType(config.Connection)
+	if connectionType != "" &&
+		connectionType != ConnectionTypeAny &&
+		connectionType != ConnectionTypeReused &&
+		connectionType != ConnectionTypeNew {
+		return nil, fmt.Errorf("invalid connection type: %v", config.Connection)
+	}
+
+	var proto string
+	switch config.Protocol {
+	case "":
+	case "http1.1":
+		proto = "HTTP/1.1"
+	case "http2":
+		proto = "HTTP/2.0"
+	case "http3":
+		proto = "HTTP/3.0"
+	default:
+		return nil, fmt.Errorf("invalid protocol: %v", config.Protocol)
+	}
+
+	var tlsExpectation *TLSExpectation
+	if config.TLS != (types.ResponseTLSExpectation{}) {
+		tlsExpectation = &TLSExpectation{
+			CipherSuite: config.TLS.CipherSuite,
+			ALPN:        config.TLS.ALPN,
+			PeerSubject: config.TLS.PeerSubject,
+		}
+		switch config.TLS.Version {
+		case "":
+		case "1.0", "1.1", "1.2", "1.3":
+			tlsExpectation.Version = "TLS " + config.TLS.Version
+		default:
+			return nil, fmt.Errorf("invalid TLS version: %v", config.TLS.Version)
+		}
+	}
+
+	headers, err := m.makeHeaderExpectations(config.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonExpectation *JSONExpectation
+	if len(config.JSON.Checks) > 0 || config.JSON.Schema != "" {
+		if jsonExpectation, err = m.makeJSONExpectation(&config.JSON); err != nil {
+			return nil, err
+		}
+	}
+
+	var responseMetrics []ResponseMetric
+	for _, configMetric := range config.Metrics {
+		metricType := ResponseMetricType(configMetric.Metric)
+		switch metricType {
+		case ResponseMetricDNS, ResponseMetricConnect, ResponseMetricTLSHandshake, ResponseMetricTTFB,
+			ResponseMetricTotal, ResponseMetricBodySize:
+		default:
+			return nil, fmt.Errorf("invalid response metric: %v", configMetric.Metric)
+		}
+		operator, err := metrics.ConvertToOperator(configMetric.Operator)
+		if err != nil {
+			return nil, err
+		}
+		responseMetrics = append(responseMetrics, ResponseMetric{
+			Metric:   metricType,
+			Operator: operator,
+			Value:    configMetric.Value,
+		})
+	}
+
+	var expr *Expr
+	if config.Expr != "" {
+		if expr, err = CompileExpr(config.Expr, ExprVariableResponse); err != nil {
+			return nil, err
+		}
+	}
+
 	return &ResponseExpectation{
 		Request:            config.Request,
-		Headers:            config.Headers,
+		Headers:            headers,
 		BodyContent:        config.Body.Content,
 		BodyMatch:          matchType,
 		BodyRenderTemplate: config.Body.RenderTemplate,
+		BodySHA256:         bodySHA256,
+		BodyFile:           config.Body.File,
+		BodyRaw:            config.Body.Raw,
+		JSON:               jsonExpectation,
 		StatusCode:         config.Status,
+		Connection:         connectionType,
+		Proto:              proto,
+		Encoding:           config.Encoding,
+		TLS:                tlsExpectation,
+		Metrics:            responseMetrics,
+		Expr:               expr,
 	}, nil
 }
 
 type ResponseExpectation struct {
 	Request            string
-	Headers            types.Headers
+	Headers            []HeaderExpectation
 	BodyContent        string
 	BodyMatch          MatchType
 	BodyRenderTemplate bool
-	StatusCode         int
+	// BodySHA256 is the expected lower case hex encoded SHA-256 digest of the body.
+	BodySHA256 string
+	// BodyFile is the path of the file with the expected body content.
+	BodyFile string
+	// BodyRaw selects the body as it was received before decoding for the body checks.
+	BodyRaw bool
+	// JSON holds checks of the body decoded as JSON or nil if the body is not checked as JSON.
+	JSON       *JSONExpectation
+	StatusCode int
+	Connection ConnectionType
+	// Proto is the expected negotiated protocol in the response format (e.g. HTTP/3.0).
+	Proto string
+	// Encoding is the expected content encoding where identity means no encoding.
+	Encoding string
+	TLS      *TLSExpectation
+	// Metrics holds the timing and size checks of the response.
+	Metrics []ResponseMetric
+	// Expr is the expression over the response that has to evaluate to true or nil if not set.
+	Expr *Expr
+}
+
+// ResponseMetric compares the measured response value with the expected value. The timing metrics values are
+// in milliseconds and the body size value is in bytes.
+type ResponseMetric struct {
+	Metric   ResponseMetricType
+	Operator metrics.MetricOperator
+	Value    float64
+}
+
+// TLSExpectation holds the expected negotiated TLS details. Empty fields are not checked.
+type TLSExpectation struct {
+	// Version is in the TLS connection state format (e.g. TLS 1.3).
+	Version     string
+	CipherSuite string
+	ALPN        string
+	PeerSubject string
 }
@@ -4,6 +4,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/run/metrics"
+	"regexp"
 	"testing"
 )
 
@@ -19,7 +21,9 @@ func Test_nativeMaker_MakeResponseExpectation(t *testing.T) {
 			name: "valid exact match",
 			config: &types.ResponseExpectation{
 				Request: "/api/data",
-				Headers: map[string]string{"Content-Type": "application/json"},
+				Headers: types.ResponseHeaders{
+					"Content-Type": {Value: "application/json", Match: "exact", Mode: "any", Count: -1},
+				},
 				Body: types.ResponseBody{
 					Match:          "exact",
 					Content:        "Expected content",
@@ -29,8 +33,16 @@ func Test_nativeMaker_MakeResponseExpectation(t *testing.T) {
 			},
 			expectError: false,
 			expected: &ResponseExpectation{
-				Request:            "/api/data",
-				Headers:            map[string]string{"Content-Type": "application/json"},
+				Request: "/api/data",
+				Headers: []HeaderExpectation{
+					{
+						Name:   "Content-Type",
+						Values: []string{"application/json"},
+						Match:  MatchTypeExact,
+						Mode:   HeaderModeAny,
+						Count:  -1,
+					},
+				},
 				BodyContent:        "Expected content",
 				BodyMatch:          MatchTypeExact,
 				BodyRenderTemplate: false,
@@ -41,7 +53,9 @@ func Test_nativeMaker_MakeResponseExpectation(t *testing.T) {
 			name: "valid regexp match",
 			config: &types.ResponseExpectation{
 				Request: "/api/data",
-				Headers: map[string]string{"Content-Type": "application/json"},
+				Headers: types.ResponseHeaders{
+					"Content-Type": {Value: "application/json", Match: "exact", Mode: "any", Count: -1},
+				},
 				Body: types.ResponseBody{
 					Match:          "regexp",
 					Content:        "^Expected.*content$",
@@ -50,8 +64,16 @@ func Test_nativeMaker_MakeResponseExpectation(t *testing.T) {
 			},
 			expectError: false,
 			expected: &ResponseExpectation{
-				Request:            "/api/data",
-				Headers:            map[string]string{"Content-Type": "application/json"},
+				Request: "/api/data",
+				Headers: []HeaderExpectation{
+					{
+						Name:   "Content-Type",
+						Values: []string{"application/json"},
+						Match:  MatchTypeExact,
+						Mode:   HeaderModeAny,
+						Count:  -1,
+					},
+				},
 				BodyContent:        "^Expected.*content$",
 				BodyMatch:          MatchTypeRegexp,
 				BodyRenderTemplate: true,
@@ -61,7 +83,9 @@ func Test_nativeMaker_MakeResponseExpectation(t *testing.T) {
 			name: "valid prefix match",
 			config: &types.ResponseExpectation{
 				Request: "/api/data",
-				Headers: map[string]string{"Content-Type": "application/json"},
+				Headers: types.ResponseHeaders{
+					"Content-Type": {Value: "application/json", Match: "exact", Mode: "any", Count: -1},
+				},
 				Body: types.ResponseBody{
 					Match:          "prefix",
 					Content:        "Expected",
@@ -71,8 +95,16 @@ func Test_nativeMaker_MakeResponseExpectation(t *testing.T) {
 			},
 			expectError: false,
 			expected: &ResponseExpectation{
-				Request:            "/api/data",
-				Headers:            map[string]string{"Content-Type": "application/json"},
+				Request: "/api/data",
+				Headers: []HeaderExpectation{
+					{
+						Name:   "Content-Type",
+						Values: []string{"application/json"},
+						Match:  MatchTypeExact,
+						Mode:   HeaderModeAny,
+						Count:  -1,
+					},
+				},
 				BodyContent:        "Expected",
 				BodyMatch:          MatchTypePrefix,
 				BodyRenderTemplate: false,
@@ -83,7 +115,9 @@ func Test_nativeMaker_MakeResponseExpectation(t *testing.T) {
 			name: "valid suffix match",
 			config: &types.ResponseExpectation{
 				Request: "/api/data",
-				Headers: map[string]string{"Content-Type": "application/json"},
+				Headers: types.ResponseHeaders{
+					"Content-Type": {Value: "application/json", Match: "exact", Mode: "any", Count: -1},
+				},
 				Body: types.ResponseBody{
 					Match:          "suffix",
 					Content:        "content",
@@ -93,8 +127,16 @@ func Test_nativeMaker_MakeResponseExpectation(t *testing.T) {
 			},
 			expectError: false,
 			expected: &ResponseExpectation{
-				Request:            "/api/data",
-				Headers:            map[string]string{"Content-Type": "application/json"},
+				Request: "/api/data",
+				Headers: []HeaderExpectation{
+					{
+						Name:   "Content-Type",
+						Values: []string{"application/json"},
+						Match:  MatchTypeExact,
+						Mode:   HeaderModeAny,
+						Count:  -1,
+					},
+				},
 				BodyContent:        "content",
 				BodyMatch:          MatchTypeSuffix,
 				BodyRenderTemplate: false,
@@ -105,7 +147,9 @@ func Test_nativeMaker_MakeResponseExpectation(t *testing.T) {
 			name: "valid infix match",
 			config: &types.ResponseExpectation{
 				Request: "/api/data",
-				Headers: map[string]string{"Content-Type": "application/json"},
+				Headers: types.ResponseHeaders{
+					"Content-Type": {Value: "application/json", Match: "exact", Mode: "any", Count: -1},
+				},
 				Body: types.ResponseBody{
 					Match:          "infix",
 					Content:        "pected cont",
@@ -115,8 +159,16 @@ func Test_nativeMaker_MakeResponseExpectation(t *testing.T) {
 			},
 			expectError: false,
 			expected: &ResponseExpectation{
-				Request:            "/api/data",
-				Headers:            map[string]string{"Content-Type": "application/json"},
+				Request: "/api/data",
+				Headers: []HeaderExpectation{
+					{
+						Name:   "Content-Type",
+						Values: []string{"application/json"},
+						Match:  MatchTypeExact,
+						Mode:   HeaderModeAny,
+						Count:  -1,
+					},
+				},
 				BodyContent:        "pected cont",
 				BodyMatch:          MatchTypeInfix,
 				BodyRenderTemplate: false,
@@ -127,7 +179,9 @@ func Test_nativeMaker_MakeResponseExpectation(t *testing.T) {
 			name: "valid none match",
 			config: &types.ResponseExpectation{
 				Request: "/api/data",
-				Headers: map[string]string{"Content-Type": "application/json"},
+				Headers: types.ResponseHeaders{
+					"Content-Type": {Value: "application/json", Match: "exact", Mode: "any", Count: -1},
+				},
 				Body: types.ResponseBody{
 					Match:          "",
 					Content:        "",
@@ -136,13 +190,373 @@ func Test_nativeMaker_MakeResponseExpectation(t *testing.T) {
 			},
 			expectError: false,
 			expected: &ResponseExpectation{
-				Request:            "/api/data",
-				Headers:            map[string]string{"Content-Type": "application/json"},
+				Request: "/api/data",
+				Headers: []HeaderExpectation{
+					{
+						Name:   "Content-Type",
+						Values: []string{"application/json"},
+						Match:  MatchTypeExact,
+						Mode:   HeaderModeAny,
+						Count:  -1,
+					},
+				},
 				BodyContent:        "",
 				BodyMatch:          MatchTypeNone,
 				BodyRenderTemplate: false,
 			},
 		},
+		{
+			name: "valid reused connection",
+			config: &types.ResponseExpectation{
+				Request:    "last",
+				Connection: "reused",
+			},
+			expectError: false,
+			expected: &ResponseExpectation{
+				Request:    "last",
+				BodyMatch:  MatchTypeNone,
+				Connection: ConnectionTypeReused,
+			},
+		},
+		{
+			name: "valid http3 protocol",
+			config: &types.ResponseExpectation{
+				Request:  "last",
+				Protocol: "http3",
+			},
+			expectError: false,
+			expected: &ResponseExpectation{
+				Request:   "last",
+				BodyMatch: MatchTypeNone,
+				Proto:     "HTTP/3.0",
+			},
+		},
+		{
+			name: "invalid protocol",
+			config: &types.ResponseExpectation{
+				Request:  "last",
+				Protocol: "http4",
+			},
+			expectError: true,
+			errorMsg:    "invalid protocol: http4",
+		},
+		{
+			name: "valid TLS expectation",
+			config: &types.ResponseExpectation{
+				Request: "last",
+				TLS: types.ResponseTLSExpectation{
+					Version: "1.3",
+					ALPN:    "h2",
+				},
+			},
+			expectError: false,
+			expected: &ResponseExpectation{
+				Request:   "last",
+				BodyMatch: MatchTypeNone,
+				TLS: &TLSExpectation{
+					Version: "TLS 1.3",
+					ALPN:    "h2",
+				},
+			},
+		},
+		{
+			name: "invalid TLS version",
+			config: &types.ResponseExpectation{
+				Request: "last",
+				TLS: types.ResponseTLSExpectation{
+					Version: "2.0",
+				},
+			},
+			expectError: true,
+			errorMsg:    "invalid TLS version: 2.0",
+		},
+		{
+			name: "valid body SHA-256 with encoding",
+			config: &types.ResponseExpectation{
+				Request: "last",
+				Body: types.ResponseBody{
+					SHA256: "9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08",
+					Match:  "exact",
+					Raw:    true,
+				},
+				Encoding: "br",
+			},
+			expectError: false,
+			expected: &ResponseExpectation{
+				Request:    "last",
+				BodyMatch:  MatchTypeNone,
+				BodySHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+				BodyRaw:    true,
+				Encoding:   "br",
+			},
+		},
+		{
+			name: "valid body file",
+			config: &types.ResponseExpectation{
+				Request: "last",
+				Body: types.ResponseBody{
+					File:  "/test/image.png",
+					Match: "exact",
+				},
+			},
+			expectError: false,
+			expected: &ResponseExpectation{
+				Request:   "last",
+				BodyMatch: MatchTypeNone,
+				BodyFile:  "/test/image.png",
+			},
+		},
+		{
+			name: "invalid body with content and file",
+			config: &types.ResponseExpectation{
+				Request: "last",
+				Body: types.ResponseBody{
+					Content: "test",
+					File:    "/test/image.png",
+				},
+			},
+			expectError: true,
+			errorMsg:    "body can set only one of content, sha256 and file",
+		},
+		{
+			name: "invalid body with SHA-256 and file",
+			config: &types.ResponseExpectation{
+				Request: "last",
+				Body: types.ResponseBody{
+					SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+					File:   "/test/image.png",
+				},
+			},
+			expectError: true,
+			errorMsg:    "body can set only one of content, sha256 and file",
+		},
+		{
+			name: "invalid body match with SHA-256",
+			config: &types.ResponseExpectation{
+				Request: "last",
+				Body: types.ResponseBody{
+					SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+					Match:  "prefix",
+				},
+			},
+			expectError: true,
+			errorMsg:    "body match prefix can be used only with content",
+		},
+		{
+			name: "invalid body SHA-256",
+			config: &types.ResponseExpectation{
+				Request: "last",
+				Body: types.ResponseBody{
+					SHA256: "9f86d081",
+				},
+			},
+			expectError: true,
+			errorMsg:    "invalid body SHA-256: 9f86d081",
+		},
+		{
+			name: "invalid content encoding",
+			config: &types.ResponseExpectation{
+				Request:  "last",
+				Encoding: "compress",
+			},
+			expectError: true,
+			errorMsg:    "invalid content encoding: compress",
+		},
+		{
+			name: "valid metrics",
+			config: &types.ResponseExpectation{
+				Request: "last",
+				Metrics: []types.ResponseMetric{
+					{Metric: "total", Operator: "ge", Value: 2000},
+					{Metric: "body_size", Operator: "lt", Value: 10240},
+				},
+			},
+			expectError: false,
+			expected: &ResponseExpectation{
+				Request:   "last",
+				BodyMatch: MatchTypeNone,
+				Metrics: []ResponseMetric{
+					{Metric: ResponseMetricTotal, Operator: metrics.MetricGeOperator, Value: 2000},
+					{Metric: ResponseMetricBodySize, Operator: metrics.MetricLtOperator, Value: 10240},
+				},
+			},
+		},
+		{
+			name: "invalid metric",
+			config: &types.ResponseExpectation{
+				Request: "last",
+				Metrics: []types.ResponseMetric{
+					{Metric: "latency", Operator: "gt", Value: 1},
+				},
+			},
+			expectError: true,
+			errorMsg:    "invalid response metric: latency",
+		},
+		{
+			name: "invalid metric operator",
+			config: &types.ResponseExpectation{
+				Request: "last",
+				Metrics: []types.ResponseMetric{
+					{Metric: "ttfb", Operator: "gte", Value: 1},
+				},
+			},
+			expectError: true,
+			errorMsg:    "invalid operator gte",
+		},
+		{
+			name: "valid JSON expectation",
+			config: &types.ResponseExpectation{
+				Request: "last",
+				JSON: types.ResponseJSON{
+					Checks: []types.ResponseJSONCheck{
+						{
+							Path:   "$.user.name",
+							Exists: true,
+							Equals: types.JSONValue{Value: "John", Set: true},
+							Type:   "string",
+							Length: 4,
+							Regexp: "^J",
+						},
+						{
+							Path:     "$.items",
+							Exists:   true,
+							Equals:   types.JSONValue{Value: []interface{}{1, map[string]interface{}{"id": 2}}, Set: true},
+							Length:   -1,
+							Operator: "ge",
+							Value:    1,
+						},
+						{
+							Path:   "$.deleted",
+							Exists: true,
+							Equals: types.JSONValue{Value: nil, Set: true},
+							Length: -1,
+						},
+						{
+							Path:   "error",
+							Exists: false,
+							Length: -1,
+						},
+					},
+					Schema: "user_schema",
+				},
+			},
+			expected: &ResponseExpectation{
+				Request: "last",
+				JSON: &JSONExpectation{
+					Checks: []JSONCheck{
+						{
+							Path: &JSONPath{
+								expr:     "$.user.name",
+								segments: []jsonPathSegment{{name: "user"}, {name: "name"}},
+							},
+							Exists:        true,
+							CompareEquals: true,
+							Equals:        "John",
+							Type:          JSONTypeString,
+							Length:        4,
+							Regexp:        regexp.MustCompile("^J"),
+						},
+						{
+							Path: &JSONPath{
+								expr:     "$.items",
+								segments: []jsonPathSegment{{name: "items"}},
+							},
+							Exists:        true,
+							CompareEquals: true,
+							Equals:        []interface{}{float64(1), map[string]interface{}{"id": float64(2)}},
+							Length:        -1,
+							Operator:      metrics.MetricGeOperator,
+							Value:         1,
+						},
+						{
+							Path: &JSONPath{
+								expr:     "$.deleted",
+								segments: []jsonPathSegment{{name: "deleted"}},
+							},
+							Exists:        true,
+							CompareEquals: true,
+							Length:        -1,
+						},
+						{
+							Path: &JSONPath{
+								expr:     "error",
+								segments: []jsonPathSegment{{name: "error"}},
+							},
+							Length: -1,
+						},
+					},
+					Schema: "user_schema",
+				},
+			},
+		},
+		{
+			name: "invalid JSON path",
+			config: &types.ResponseExpectation{
+				JSON: types.ResponseJSON{
+					Checks: []types.ResponseJSONCheck{{Path: "$.items[x]", Exists: true}},
+				},
+			},
+			expectError: true,
+			errorMsg:    "invalid JSON path $.items[x]: invalid index x",
+		},
+		{
+			name: "invalid JSON type",
+			config: &types.ResponseExpectation{
+				JSON: types.ResponseJSON{
+					Checks: []types.ResponseJSONCheck{{Path: "$.id", Exists: true, Type: "integer"}},
+				},
+			},
+			expectError: true,
+			errorMsg:    "invalid JSON type integer for path $.id",
+		},
+		{
+			name: "invalid JSON regexp",
+			config: &types.ResponseExpectation{
+				JSON: types.ResponseJSON{
+					Checks: []types.ResponseJSONCheck{{Path: "$.id", Exists: true, Regexp: "("}},
+				},
+			},
+			expectError: true,
+			errorMsg:    "invalid regexp for path $.id",
+		},
+		{
+			name: "invalid JSON expected value",
+			config: &types.ResponseExpectation{
+				JSON: types.ResponseJSON{
+					Checks: []types.ResponseJSONCheck{{Path: "$.id", Exists: true, Equals: types.JSONValue{Value: make(chan int), Set: true}}},
+				},
+			},
+			expectError: true,
+			errorMsg:    "invalid expected JSON value for path $.id",
+		},
+		{
+			name: "invalid JSON operator",
+			config: &types.ResponseExpectation{
+				JSON: types.ResponseJSON{
+					Checks: []types.ResponseJSONCheck{{Path: "$.id", Exists: true, Operator: "between"}},
+				},
+			},
+			expectError: true,
+			errorMsg:    "invalid operator between",
+		},
+		{
+			name: "invalid expression",
+			config: &types.ResponseExpectation{
+				Request: "last",
+				Expr:    "output.lines == []",
+			},
+			expectError: true,
+			errorMsg:    "undeclared reference to 'output'",
+		},
+		{
+			name: "invalid connection type",
+			config: &types.ResponseExpectation{
+				Request:    "last",
+				Connection: "invalid",
+			},
+			expectError: true,
+			errorMsg:    "invalid connection type: invalid",
+		},
 		{
 			name: "invalid match type",
 			config: &types.ResponseExpectation{
@@ -171,3 +585,14 @@ func Test_nativeMaker_MakeResponseExpectation(t *testing.T) {
 		})
 	}
 }
+
+func Test_nativeMaker_MakeResponseExpectation_Expr(t *testing.T) {
+	maker := &nativeMaker{}
+	result, err := maker.MakeResponseExpectation(&types.ResponseExpectation{
+		Request: "last",
+		Expr:    "response.status in [200, 204]",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Expr)
+	assert.Equal(t, "response.status in [200, 204]", result.Expr.String())
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expectations
+
+import (
+	"fmt"
+	"github.com/wstool/wst/conf/types"
+	"regexp"
+)
+
+func (m *nativeMaker) MakeSnapshotExpectation(
+	config *types.SnapshotExpectation,
+) (*SnapshotExpectation, error) {
+	if config.File == "" {
+		return nil, fmt.Errorf("snapshot file is not set")
+	}
+
+	source := SnapshotSource(config.Source)
+	switch source {
+	case "":
+		source = SnapshotSourceResponse
+	case SnapshotSourceResponse:
+	case SnapshotSourceOutput:
+		if config.Command == "" {
+			return nil, fmt.Errorf("snapshot of output requires command")
+		}
+	default:
+		return nil, fmt.Errorf("invalid snapshot source: %v", config.Source)
+	}
+
+	outputType := OutputType(config.Type)
+	if outputType == "" {
+		outputType = OutputTypeAny
+	}
+	if outputType != OutputTypeAny && outputType != OutputTypeStdout && outputType != OutputTypeStderr {
+		return nil, fmt.Errorf("invalid output type: %v", config.Type)
+	}
+
+	var normalizations []SnapshotNormalization
+	for _, normalization := range config.Normalize {
+		pattern, err := regexp.Compile(normalization.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot normalization pattern %s: %v", normalization.Pattern, err)
+		}
+		normalizations = append(normalizations, SnapshotNormalization{
+			Pattern:     pattern,
+			Replacement: normalization.Replacement,
+		})
+	}
+
+	var expr *Expr
+	if config.Expr != "" {
+		var err error
+		if expr, err = CompileExpr(config.Expr, ExprVariableSnapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	return &SnapshotExpectation{
+		File:           config.File,
+		Source:         source,
+		Request:        config.Request,
+		Command:        config.Command,
+		OutputType:     outputType,
+		Normalizations: normalizations,
+		Expr:           expr,
+	}, nil
+}
+
+// SnapshotNormalization replaces the content matching the pattern before it is compared with the snapshot.
+type SnapshotNormalization struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// SnapshotExpectation defines the golden file that the response body or the command output is compared with.
+type SnapshotExpectation struct {
+	File   string
+	Source SnapshotSource
+	// Request is the request id of the response that is compared for the response source.
+	Request string
+	// Command is the command id of the output that is compared for the output source.
+	Command        string
+	OutputType     OutputType
+	Normalizations []SnapshotNormalization
+	// Expr is the expression over the normalized content that has to evaluate to true or nil if not set.
+	Expr *Expr
+}
+
+// Normalize applies all normalizations to the content in the defined order.
+func (e *SnapshotExpectation) Normalize(content string) string {
+	for _, normalization := range e.Normalizations {
+		content = normalization.Pattern.ReplaceAllString(content, normalization.Replacement)
+	}
+	return content
+}
@@ -0,0 +1,167 @@
+package expectations
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/conf/types"
+	"regexp"
+	"testing"
+)
+
+func Test_nativeMaker_MakeSnapshotExpectation(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *types.SnapshotExpectation
+		expectError bool
+		expected    *SnapshotExpectation
+		errorMsg    string
+	}{
+		{
+			name: "valid response snapshot with normalizations",
+			config: &types.SnapshotExpectation{
+				File:    "/test/snapshots/info.html",
+				Source:  "response",
+				Request: "last",
+				Type:    "any",
+				Normalize: []types.SnapshotNormalization{
+					{Pattern: `\d{4}-\d{2}-\d{2}`, Replacement: "DATE"},
+					{Pattern: `pid=\d+`, Replacement: "pid=PID"},
+				},
+			},
+			expected: &SnapshotExpectation{
+				File:       "/test/snapshots/info.html",
+				Source:     SnapshotSourceResponse,
+				Request:    "last",
+				OutputType: OutputTypeAny,
+				Normalizations: []SnapshotNormalization{
+					{Pattern: regexp.MustCompile(`\d{4}-\d{2}-\d{2}`), Replacement: "DATE"},
+					{Pattern: regexp.MustCompile(`pid=\d+`), Replacement: "pid=PID"},
+				},
+			},
+		},
+		{
+			name: "valid output snapshot with default source and type",
+			config: &types.SnapshotExpectation{
+				File:    "/test/snapshots/php-i.txt",
+				Command: "php-i",
+			},
+			expected: &SnapshotExpectation{
+				File:       "/test/snapshots/php-i.txt",
+				Source:     SnapshotSourceResponse,
+				Command:    "php-i",
+				OutputType: OutputTypeAny,
+			},
+		},
+		{
+			name: "valid output snapshot",
+			config: &types.SnapshotExpectation{
+				File:    "/test/snapshots/php-i.txt",
+				Source:  "output",
+				Command: "php-i",
+				Type:    "stdout",
+			},
+			expected: &SnapshotExpectation{
+				File:       "/test/snapshots/php-i.txt",
+				Source:     SnapshotSourceOutput,
+				Command:    "php-i",
+				OutputType: OutputTypeStdout,
+			},
+		},
+		{
+			name: "missing file",
+			config: &types.SnapshotExpectation{
+				Source: "response",
+			},
+			expectError: true,
+			errorMsg:    "snapshot file is not set",
+		},
+		{
+			name: "output source without command",
+			config: &types.SnapshotExpectation{
+				File:   "/test/snapshots/out.txt",
+				Source: "output",
+			},
+			expectError: true,
+			errorMsg:    "snapshot of output requires command",
+		},
+		{
+			name: "invalid source",
+			config: &types.SnapshotExpectation{
+				File:   "/test/snapshots/out.txt",
+				Source: "metrics",
+			},
+			expectError: true,
+			errorMsg:    "invalid snapshot source: metrics",
+		},
+		{
+			name: "invalid output type",
+			config: &types.SnapshotExpectation{
+				File:    "/test/snapshots/out.txt",
+				Source:  "output",
+				Command: "cmd",
+				Type:    "unknown",
+			},
+			expectError: true,
+			errorMsg:    "invalid output type: unknown",
+		},
+		{
+			name: "invalid normalization pattern",
+			config: &types.SnapshotExpectation{
+				File: "/test/snapshots/out.txt",
+				Normalize: []types.SnapshotNormalization{
+					{Pattern: "[a-", Replacement: "X"},
+				},
+			},
+			expectError: true,
+			errorMsg:    "invalid snapshot normalization pattern [a-",
+		},
+		{
+			name: "invalid expression",
+			config: &types.SnapshotExpectation{
+				File: "/test/snapshots/out.txt",
+				Expr: "snapshot.content +",
+			},
+			expectError: true,
+			errorMsg:    "invalid expression snapshot.content +",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maker := &nativeMaker{}
+			result, err := maker.MakeSnapshotExpectation(tt.config)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_nativeMaker_MakeSnapshotExpectation_Expr(t *testing.T) {
+	maker := &nativeMaker{}
+	result, err := maker.MakeSnapshotExpectation(&types.SnapshotExpectation{
+		File: "/test/snapshots/index.html",
+		Expr: "snapshot.content.contains('<html>')",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Expr)
+	assert.Equal(t, "snapshot.content.contains('<html>')", result.Expr.String())
+}
+
+func TestSnapshotExpectation_Normalize(t *testing.T) {
+	expectation := &SnapshotExpectation{
+		Normalizations: []SnapshotNormalization{
+			{Pattern: regexp.MustCompile(`\d{2}:\d{2}:\d{2}`), Replacement: "TIME"},
+			{Pattern: regexp.MustCompile(`127\.0\.0\.1:\d+`), Replacement: "127.0.0.1:PORT"},
+			{Pattern: regexp.MustCompile(`pid (\d+)`), Replacement: "pid N"},
+		},
+	}
+
+	normalized := expectation.Normalize("12:30:01 listening on 127.0.0.1:43210 with pid 1234\n")
+
+	assert.Equal(t, "TIME listening on 127.0.0.1:PORT with pid N\n", normalized)
+}
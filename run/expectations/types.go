@@ -39,3 +39,81 @@ const (
 	OutputTypeStderr OutputType = "stderr"
 	OutputTypeAny    OutputType = "any"
 )
+
+type OutputMode string
+
+const (
+	OutputModeExpect OutputMode = "expect"
+	OutputModeForbid OutputMode = "forbid"
+)
+
+const (
+	OutputFromStart = "start"
+	OutputFromNow   = "now"
+)
+
+const (
+	FileFromStart = "start"
+	FileFromNow   = "now"
+)
+
+type SnapshotSource string
+
+const (
+	SnapshotSourceResponse SnapshotSource = "response"
+	SnapshotSourceOutput   SnapshotSource = "output"
+)
+
+type ConnectionType string
+
+const (
+	ConnectionTypeAny    ConnectionType = "any"
+	ConnectionTypeReused ConnectionType = "reused"
+	ConnectionTypeNew    ConnectionType = "new"
+)
+
+type ResponseMetricType string
+
+const (
+	ResponseMetricDNS          ResponseMetricType = "dns"
+	ResponseMetricConnect      ResponseMetricType = "connect"
+	ResponseMetricTLSHandshake ResponseMetricType = "tls_handshake"
+	ResponseMetricTTFB         ResponseMetricType = "ttfb"
+	ResponseMetricTotal        ResponseMetricType = "total"
+	ResponseMetricBodySize     ResponseMetricType = "body_size"
+)
+
+type ProcessScope string
+
+const (
+	ProcessScopeAll         ProcessScope = "all"
+	ProcessScopeMain        ProcessScope = "main"
+	ProcessScopeChildren    ProcessScope = "children"
+	ProcessScopeDescendants ProcessScope = "descendants"
+)
+
+type ProcessMetricType string
+
+const (
+	ProcessMetricCount  ProcessMetricType = "count"
+	ProcessMetricRSS    ProcessMetricType = "rss"
+	ProcessMetricFDs    ProcessMetricType = "fds"
+	ProcessMetricUptime ProcessMetricType = "uptime"
+)
+
+type ProcessPidsCheck string
+
+const (
+	ProcessPidsAny       ProcessPidsCheck = "any"
+	ProcessPidsChanged   ProcessPidsCheck = "changed"
+	ProcessPidsUnchanged ProcessPidsCheck = "unchanged"
+)
+
+type FileType string
+
+const (
+	FileTypeAny    FileType = "any"
+	FileTypeFile   FileType = "file"
+	FileTypeDir    FileType = "dir"
+	FileTypeSocket FileType = "socket"
+)
@@ -29,6 +29,7 @@ import (
 	"github.com/wstool/wst/run/parameters"
 	"github.com/wstool/wst/run/resources"
 	"github.com/wstool/wst/run/servers"
+	"github.com/wstool/wst/run/servers/mockserver"
 	"github.com/wstool/wst/run/services"
 	"github.com/wstool/wst/run/spec/defaults"
 	"path/filepath"
@@ -364,6 +365,10 @@ func (i *nativeInstance) Run() error {
 			initializedEnvs[envName] = true
 		}
 	}
+	if err = i.registerMockServers(); err != nil {
+		_ = i.destroyEnvironments(ctx, initializedEnvs)
+		return err
+	}
 
 	ictx, cancel := i.runtimeMaker.MakeContextWithTimeout(ctx, i.instanceTimeout)
 	defer cancel()
@@ -373,8 +378,15 @@ func (i *nativeInstance) Run() error {
 		actionErr = i.executeAction(ictx, act, actionErr)
 	}
 
+	closeErr := i.runData.Close()
+	if closeErr != nil {
+		i.fnd.Logger().Errorf("Failed to close runtime data: %v", closeErr)
+	}
 	destroyErr := i.destroyEnvironments(ctx, initializedEnvs)
 	if actionErr == nil {
+		if destroyErr == nil {
+			return closeErr
+		}
 		return destroyErr
 	}
 
@@ -385,6 +397,27 @@ func (i *nativeInstance) Run() error {
 	return actionErr
 }
 
+// registerMockServers stores the received requests record and the mock server of each mock service in the runtime
+// data. The requests can be then checked by expectations and the server is closed together with the runtime data when
+// the instance finishes.
+func (i *nativeInstance) registerMockServers() error {
+	for name, svc := range i.services {
+		mockServer := svc.MockServer()
+		if mockServer == nil {
+			continue
+		}
+		requests := &mockserver.Requests{}
+		if err := i.runData.Store(mockserver.RequestsDataKey(name), requests); err != nil {
+			return err
+		}
+		if err := i.runData.Store(mockserver.ServerDataKey(name), mockServer); err != nil {
+			return err
+		}
+		mockServer.SetRequests(requests)
+	}
+	return nil
+}
+
 func (i *nativeInstance) destroyEnvironments(ctx context.Context, initializedEnvs map[providers.Type]bool) error {
 	var err error
 	for envName := range initializedEnvs {
@@ -405,6 +438,7 @@ func (i *nativeInstance) executeAction(actionsCtx context.Context, act action.Ac
 		return nil
 	}
 
+	i.runData.MarkActionStart(time.Now())
 	ctx, cancel := i.runtimeMaker.MakeContextWithTimeout(actionsCtx, act.Timeout())
 	defer cancel()
 	success, err := act.Execute(ctx, i.runData)
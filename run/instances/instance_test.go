@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/wstool/wst/conf/types"
 	externalMocks "github.com/wstool/wst/mocks/authored/external"
@@ -24,12 +25,15 @@ import (
 	"github.com/wstool/wst/run/actions/action"
 	"github.com/wstool/wst/run/environments"
 	"github.com/wstool/wst/run/environments/environment/providers"
+	"github.com/wstool/wst/run/instances/runtime"
 	"github.com/wstool/wst/run/parameters"
 	"github.com/wstool/wst/run/resources"
 	"github.com/wstool/wst/run/resources/scripts"
 	"github.com/wstool/wst/run/servers"
+	"github.com/wstool/wst/run/servers/mockserver"
 	"github.com/wstool/wst/run/services"
 	"github.com/wstool/wst/run/spec/defaults"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -1145,6 +1149,7 @@ func Test_nativeInstance_Run(t *testing.T) {
 			context.CancelFunc,
 		)
 		expectedCancellations int
+		dataCloseErr          error
 		expectError           bool
 		expectedErrorMsg      string
 	}{
@@ -1192,6 +1197,52 @@ func Test_nativeInstance_Run(t *testing.T) {
 			},
 			expectedCancellations: 2,
 		},
+		{
+			name:        "failed run due to runtime data close error",
+			count:       1,
+			initialized: true,
+			setupMocks: func(
+				inst *nativeInstance,
+				fnd *appMocks.MockFoundation,
+				rm *runtimeMocks.MockMaker,
+				acts []*actionMocks.MockAction,
+				cancelFunc context.CancelFunc,
+			) {
+				fsMock := appMocks.NewMockFs(t)
+				fsMock.On("RemoveAll", "/fake/workspace").Return(nil)
+				fnd.On("Fs").Return(fsMock)
+
+				ctx := context.Background()
+				rm.On("MakeBackgroundContext").Return(ctx)
+
+				localEnv := inst.envs[providers.LocalType].(*environmentMocks.MockEnvironment)
+				localEnv.On("IsUsed").Return(true)
+				localEnv.On("Init", ctx).Return(nil)
+
+				dockerEnv := inst.envs[providers.DockerType].(*environmentMocks.MockEnvironment)
+				dockerEnv.On("IsUsed").Return(true)
+				dockerEnv.On("Init", ctx).Return(nil)
+
+				tctx, cancel := context.WithTimeout(ctx, inst.instanceTimeout)
+				defer cancel()
+				rm.On("MakeContextWithTimeout", ctx, inst.instanceTimeout).Return(tctx, cancelFunc)
+
+				actTimeout := 1 * time.Second
+				acts[0].On("Timeout").Return(actTimeout)
+				acts[0].On("When").Return(action.OnSuccess)
+				actx, cancel := context.WithTimeout(ctx, inst.instanceTimeout)
+				defer cancel()
+				rm.On("MakeContextWithTimeout", tctx, actTimeout).Return(actx, cancelFunc)
+
+				acts[0].On("Execute", actx, inst.runData).Return(true, nil)
+
+				localEnv.On("Destroy", ctx).Return(nil)
+				dockerEnv.On("Destroy", ctx).Return(nil)
+			},
+			dataCloseErr:     errors.New("close failed"),
+			expectError:      true,
+			expectedErrorMsg: "close failed",
+		},
 		{
 			name:        "successful run of two success actions",
 			count:       2,
@@ -1914,6 +1965,8 @@ func Test_nativeInstance_Run(t *testing.T) {
 			}
 
 			tt.setupMocks(instance, fndMock, runtimeMakerMock, actMocks, cancelFunc)
+			instance.runData.(*runtimeMocks.MockData).On("Close").Maybe().Return(tt.dataCloseErr)
+			instance.runData.(*runtimeMocks.MockData).On("MarkActionStart", mock.Anything).Maybe()
 
 			err := instance.Run()
 
@@ -1935,6 +1988,96 @@ func Test_nativeInstance_Run(t *testing.T) {
 	}
 }
 
+func Test_nativeInstance_registerMockServers(t *testing.T) {
+	tests := []struct {
+		name             string
+		setupMocks       func(*testing.T, *runtimeMocks.MockData, *mockserver.Server) services.Services
+		expectError      bool
+		expectedErrorMsg string
+	}{
+		{
+			name: "mock server registered",
+			setupMocks: func(t *testing.T, rd *runtimeMocks.MockData, ms *mockserver.Server) services.Services {
+				mockSvc := servicesMocks.NewMockService(t)
+				mockSvc.On("MockServer").Return(ms)
+				otherSvc := servicesMocks.NewMockService(t)
+				otherSvc.On("MockServer").Return(nil)
+				rd.On("Store", "mock/backend/requests", mock.AnythingOfType("*mockserver.Requests")).Return(nil)
+				rd.On("Store", "mock/backend/server", ms).Return(nil)
+				return services.Services{"backend": mockSvc, "fpm": otherSvc}
+			},
+		},
+		{
+			name: "requests storing error",
+			setupMocks: func(t *testing.T, rd *runtimeMocks.MockData, ms *mockserver.Server) services.Services {
+				mockSvc := servicesMocks.NewMockService(t)
+				mockSvc.On("MockServer").Return(ms)
+				rd.On("Store", "mock/backend/requests", mock.AnythingOfType("*mockserver.Requests")).
+					Return(errors.New("store failed"))
+				return services.Services{"backend": mockSvc}
+			},
+			expectError:      true,
+			expectedErrorMsg: "store failed",
+		},
+		{
+			name: "server storing error",
+			setupMocks: func(t *testing.T, rd *runtimeMocks.MockData, ms *mockserver.Server) services.Services {
+				mockSvc := servicesMocks.NewMockService(t)
+				mockSvc.On("MockServer").Return(ms)
+				rd.On("Store", "mock/backend/requests", mock.AnythingOfType("*mockserver.Requests")).Return(nil)
+				rd.On("Store", "mock/backend/server", ms).Return(errors.New("store failed"))
+				return services.Services{"backend": mockSvc}
+			},
+			expectError:      true,
+			expectedErrorMsg: "store failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runDataMock := runtimeMocks.NewMockData(t)
+			ms := mockserver.NewServer(externalMocks.NewMockLogger().SugaredLogger, &mockserver.Config{})
+			instance := &nativeInstance{
+				runData:  runDataMock,
+				services: tt.setupMocks(t, runDataMock, ms),
+			}
+
+			err := instance.registerMockServers()
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_nativeInstance_registerMockServers_closedWithRuntimeData(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+	ms := mockserver.NewServer(externalMocks.NewMockLogger().SugaredLogger, &mockserver.Config{})
+	require.NoError(t, ms.Start("127.0.0.1:0"))
+	mockSvc := servicesMocks.NewMockService(t)
+	mockSvc.On("MockServer").Return(ms)
+	instance := &nativeInstance{
+		runData:  runtime.CreateMaker(fndMock).MakeData(),
+		services: services.Services{"backend": mockSvc},
+	}
+
+	require.NoError(t, instance.registerMockServers())
+	ms.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users", nil))
+	data, ok := instance.runData.Load("mock/backend/requests")
+	require.True(t, ok)
+	requests, ok := data.(*mockserver.Requests)
+	require.True(t, ok)
+	require.Len(t, requests.All(), 1)
+	assert.Equal(t, "/users", requests.All()[0].Path)
+	require.NoError(t, instance.runData.Close())
+
+	assert.False(t, ms.IsRunning())
+}
+
 func Test_skipError(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -15,20 +15,38 @@
 package runtime
 
 import (
+	"github.com/pkg/errors"
 	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/run/parameters"
+	"io"
 	"sync"
+	"time"
 )
 
 // Data is the interface type to allow storage and retrieval of data across different actions.
 type Data interface {
 	Store(key string, value interface{}) error
 	Load(key string) (interface{}, bool)
+	// Parameters returns runtime parameters (e.g. loop variables) that are available for template rendering.
+	Parameters() parameters.Parameters
+	// WithParameters creates a child data scope that shares the stored values but extends the runtime parameters.
+	WithParameters(params parameters.Parameters) Data
+	// WithActionSequence creates a child data scope that shares the stored values and parameters but has its own
+	// action start cursor so nested and parallel sequences do not overwrite each other's cursor.
+	WithActionSequence() Data
+	// MarkActionStart records the start time of the action in the current sequence that is about to be executed.
+	MarkActionStart(start time.Time)
+	// PreviousActionStart returns the start time of the previously executed action in the current sequence.
+	PreviousActionStart() (time.Time, bool)
+	// Close closes all stored values that hold resources (implement io.Closer).
+	Close() error
 }
 
 // runtimeDataImpl is an implementation of the RuntimeData interface.
 type syncData struct {
-	fnd  app.Foundation
-	data sync.Map
+	fnd    app.Foundation
+	data   sync.Map
+	cursor actionCursor
 }
 
 // Store stores the value for a key.
@@ -41,3 +59,123 @@ func (rt *syncData) Store(key string, value interface{}) error {
 func (rt *syncData) Load(key string) (interface{}, bool) {
 	return rt.data.Load(key)
 }
+
+func (rt *syncData) Close() error {
+	var err error
+	rt.data.Range(func(key, value interface{}) bool {
+		if closer, ok := value.(io.Closer); ok {
+			if closeErr := closer.Close(); closeErr != nil {
+				err = errors.Errorf("failed to close runtime data %v: %v", key, closeErr)
+			}
+		}
+		return true
+	})
+	return err
+}
+
+func (rt *syncData) Parameters() parameters.Parameters {
+	return parameters.Parameters{}
+}
+
+func (rt *syncData) WithParameters(params parameters.Parameters) Data {
+	return &scopedData{
+		parent: rt,
+		params: params,
+	}
+}
+
+func (rt *syncData) WithActionSequence() Data {
+	return &scopedData{
+		parent: rt,
+		cursor: &actionCursor{},
+	}
+}
+
+func (rt *syncData) MarkActionStart(start time.Time) {
+	rt.cursor.mark(start)
+}
+
+func (rt *syncData) PreviousActionStart() (time.Time, bool) {
+	return rt.cursor.previous()
+}
+
+type scopedData struct {
+	parent Data
+	params parameters.Parameters
+	// cursor is the action start cursor of the sequence scope or nil if the parent cursor is used.
+	cursor *actionCursor
+}
+
+func (sd *scopedData) Store(key string, value interface{}) error {
+	return sd.parent.Store(key, value)
+}
+
+func (sd *scopedData) Load(key string) (interface{}, bool) {
+	return sd.parent.Load(key)
+}
+
+func (sd *scopedData) Close() error {
+	return sd.parent.Close()
+}
+
+func (sd *scopedData) Parameters() parameters.Parameters {
+	return make(parameters.Parameters).Inherit(sd.params).Inherit(sd.parent.Parameters())
+}
+
+func (sd *scopedData) WithParameters(params parameters.Parameters) Data {
+	return &scopedData{
+		parent: sd,
+		params: params,
+	}
+}
+
+func (sd *scopedData) WithActionSequence() Data {
+	return &scopedData{
+		parent: sd,
+		cursor: &actionCursor{},
+	}
+}
+
+func (sd *scopedData) MarkActionStart(start time.Time) {
+	if sd.cursor == nil {
+		sd.parent.MarkActionStart(start)
+		return
+	}
+	sd.cursor.mark(start)
+}
+
+func (sd *scopedData) PreviousActionStart() (time.Time, bool) {
+	if sd.cursor == nil {
+		return sd.parent.PreviousActionStart()
+	}
+	return sd.cursor.previous()
+}
+
+// actionCursor holds the start times of the current and the previously executed action in a sequence. The start
+// time of the previously executed action is kept as it is the output position that the current action can read from.
+type actionCursor struct {
+	mu            sync.Mutex
+	start         time.Time
+	previousStart time.Time
+}
+
+func (c *actionCursor) mark(start time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.previousStart = c.start
+	c.start = start
+}
+
+// previous returns the start time of the previously executed action. If there is no previous action, the start time
+// of the current action is returned.
+func (c *actionCursor) previous() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.previousStart.IsZero() {
+		return c.previousStart, true
+	}
+	if !c.start.IsZero() {
+		return c.start, true
+	}
+	return time.Time{}, false
+}
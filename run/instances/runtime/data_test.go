@@ -1,14 +1,18 @@
 package runtime
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appMocks "github.com/wstool/wst/mocks/generated/app"
+	parameterMocks "github.com/wstool/wst/mocks/generated/run/parameters/parameter"
+	"github.com/wstool/wst/run/parameters"
 	"testing"
+	"time"
 )
 
 func TestSyncData_StoreAndLoad(t *testing.T) {
-	data := syncData{
+	data := &syncData{
 		fnd: appMocks.NewMockFoundation(t),
 	}
 	require.NotNil(t, data, "Data instance should not be nil")
@@ -63,3 +67,165 @@ func TestSyncData_StoreAndLoad(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncData_Parameters(t *testing.T) {
+	data := &syncData{
+		fnd: appMocks.NewMockFoundation(t),
+	}
+	assert.Equal(t, parameters.Parameters{}, data.Parameters())
+}
+
+func TestSyncData_WithParameters(t *testing.T) {
+	data := &syncData{
+		fnd: appMocks.NewMockFoundation(t),
+	}
+	outerParam := parameterMocks.NewMockParameter(t)
+	overriddenParam := parameterMocks.NewMockParameter(t)
+	innerParam := parameterMocks.NewMockParameter(t)
+
+	outer := data.WithParameters(parameters.Parameters{
+		"outer": outerParam,
+		"index": overriddenParam,
+	})
+	inner := outer.WithParameters(parameters.Parameters{
+		"index": innerParam,
+	})
+
+	assert.Equal(t, parameters.Parameters{
+		"outer": outerParam,
+		"index": overriddenParam,
+	}, outer.Parameters())
+	assert.Equal(t, parameters.Parameters{
+		"outer": outerParam,
+		"index": innerParam,
+	}, inner.Parameters())
+	assert.Equal(t, parameters.Parameters{}, data.Parameters())
+
+	// Values are shared between all scopes.
+	require.NoError(t, inner.Store("key", "value"))
+	value, found := data.Load("key")
+	assert.True(t, found)
+	assert.Equal(t, "value", value)
+	value, found = outer.Load("key")
+	assert.True(t, found)
+	assert.Equal(t, "value", value)
+}
+
+type testCloser struct {
+	closed bool
+	err    error
+}
+
+func (c *testCloser) Close() error {
+	c.closed = true
+	return c.err
+}
+
+func TestSyncData_Close(t *testing.T) {
+	tests := []struct {
+		name        string
+		closeErr    error
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name: "closes stored closers",
+		},
+		{
+			name:        "returns close error",
+			closeErr:    errors.New("close failed"),
+			expectError: true,
+			errorMsg:    "failed to close runtime data closer: close failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &syncData{
+				fnd: appMocks.NewMockFoundation(t),
+			}
+			closer := &testCloser{err: tt.closeErr}
+			require.NoError(t, data.Store("value", "not closer"))
+			require.NoError(t, data.Store("closer", closer))
+
+			// Closing through a scope closes the shared values.
+			err := data.WithParameters(parameters.Parameters{}).Close()
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.True(t, closer.closed)
+		})
+	}
+}
+
+func TestSyncData_MarkActionStart(t *testing.T) {
+	data := &syncData{
+		fnd: appMocks.NewMockFoundation(t),
+	}
+	first := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	second := first.Add(time.Second)
+	third := second.Add(time.Second)
+
+	_, found := data.PreviousActionStart()
+	assert.False(t, found)
+
+	data.MarkActionStart(first)
+	start, found := data.PreviousActionStart()
+	assert.True(t, found)
+	assert.Equal(t, first, start)
+
+	data.MarkActionStart(second)
+	start, found = data.PreviousActionStart()
+	assert.True(t, found)
+	assert.Equal(t, first, start)
+
+	// The parameters scope shares the cursor with its parent.
+	data.WithParameters(parameters.Parameters{}).MarkActionStart(third)
+	start, found = data.PreviousActionStart()
+	assert.True(t, found)
+	assert.Equal(t, second, start)
+}
+
+func TestData_WithActionSequence(t *testing.T) {
+	data := &syncData{
+		fnd: appMocks.NewMockFoundation(t),
+	}
+	first := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	data.MarkActionStart(first)
+
+	// Each sequence has its own cursor that does not change the parent or sibling cursors.
+	seq1 := data.WithActionSequence()
+	seq2 := data.WithActionSequence()
+	_, found := seq1.PreviousActionStart()
+	assert.False(t, found)
+	seq1.MarkActionStart(first.Add(time.Second))
+	seq1.MarkActionStart(first.Add(2 * time.Second))
+	seq2.MarkActionStart(first.Add(3 * time.Second))
+
+	// The nested sequence in the parameters scope does not change the enclosing sequence cursor.
+	nested := seq1.WithParameters(parameters.Parameters{}).WithActionSequence()
+	nested.MarkActionStart(first.Add(4 * time.Second))
+	nested.MarkActionStart(first.Add(5 * time.Second))
+
+	start, found := data.PreviousActionStart()
+	assert.True(t, found)
+	assert.Equal(t, first, start)
+	start, found = seq1.PreviousActionStart()
+	assert.True(t, found)
+	assert.Equal(t, first.Add(time.Second), start)
+	start, found = seq2.PreviousActionStart()
+	assert.True(t, found)
+	assert.Equal(t, first.Add(3*time.Second), start)
+	start, found = nested.PreviousActionStart()
+	assert.True(t, found)
+	assert.Equal(t, first.Add(4*time.Second), start)
+
+	// The sequence scope shares the stored values.
+	require.NoError(t, seq1.Store("key", "value"))
+	value, found := data.Load("key")
+	assert.True(t, found)
+	assert.Equal(t, "value", value)
+}
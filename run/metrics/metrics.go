@@ -79,12 +79,76 @@ func (g GenericMetric[T]) Compare(operator MetricOperator, value float64) (bool,
 		return g.Value < typedValue, nil
 	case MetricLeOperator:
 		return g.Value <= typedValue, nil
+	case MetricNeOperator:
+		return g.Value != typedValue, nil
 	default:
 		return false, fmt.Errorf("invalid metric operator %s", operator)
 	}
 }
 
+type Aggregation string
+
+const (
+	AggregationMax  Aggregation = "max"
+	AggregationMin  Aggregation = "min"
+	AggregationAvg  Aggregation = "avg"
+	AggregationLast Aggregation = "last"
+)
+
+func ConvertToAggregation(agg string) (Aggregation, error) {
+	aggregation := Aggregation(agg)
+	switch aggregation {
+	case AggregationMax, AggregationMin, AggregationAvg, AggregationLast:
+		return aggregation, nil
+	default:
+		return "", fmt.Errorf("invalid aggregation %s", agg)
+	}
+}
+
+// Aggregate reduces the time ordered values using the aggregation.
+func Aggregate(aggregation Aggregation, values []float64) (float64, error) {
+	if len(values) == 0 {
+		return 0, fmt.Errorf("no values to aggregate")
+	}
+	result := values[0]
+	switch aggregation {
+	case AggregationMax:
+		for _, value := range values[1:] {
+			result = max(result, value)
+		}
+	case AggregationMin:
+		for _, value := range values[1:] {
+			result = min(result, value)
+		}
+	case AggregationAvg:
+		for _, value := range values[1:] {
+			result += value
+		}
+		result /= float64(len(values))
+	case AggregationLast:
+		result = values[len(values)-1]
+	default:
+		return 0, fmt.Errorf("invalid aggregation %s", aggregation)
+	}
+	return result, nil
+}
+
+// Window is a time window relative to the start of the measurement. Zero To means the end of the measurement.
+type Window struct {
+	From time.Duration
+	To   time.Duration
+}
+
+// Overlaps returns true if the interval starting at start with the length overlaps the window.
+func (w Window) Overlaps(start, length time.Duration) bool {
+	return start+length > w.From && (w.To == 0 || start < w.To)
+}
+
 type Metrics interface {
 	Find(name string) (Metric, error)
+	// FindWindowed finds the metric aggregated over time series values in the window.
+	FindWindowed(name string, window Window, aggregation Aggregation) (Metric, error)
+	// Values returns all metric values by the metric name.
+	Values() map[string]interface{}
 	String() string
 }
@@ -3,6 +3,7 @@ package metrics
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestConvertToOperator(t *testing.T) {
@@ -53,6 +54,8 @@ func TestGenericMetric_Compare(t *testing.T) {
 		{"Less Than False", 200, MetricLtOperator, 100, false, false},
 		{"Less Or Equal True", 100, MetricLeOperator, 100, true, false},
 		{"Less Or Equal True 2", 100, MetricLeOperator, 200, true, false},
+		{"Not Equal True", 100, MetricNeOperator, 101, true, false},
+		{"Not Equal False", 100, MetricNeOperator, 100, false, false},
 		{"Invalid Operator", 100, "invalid", 100, false, true},
 	}
 
@@ -69,3 +72,83 @@ func TestGenericMetric_Compare(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertToAggregation(t *testing.T) {
+	tests := []struct {
+		name           string
+		aggregation    string
+		expectedAgg    Aggregation
+		expectingError bool
+	}{
+		{"Valid Max", "max", AggregationMax, false},
+		{"Valid Min", "min", AggregationMin, false},
+		{"Valid Avg", "avg", AggregationAvg, false},
+		{"Valid Last", "last", AggregationLast, false},
+		{"Invalid Aggregation", "sum", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			agg, err := ConvertToAggregation(test.aggregation)
+			if test.expectingError {
+				assert.EqualError(t, err, "invalid aggregation sum")
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedAgg, agg)
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	values := []float64{3, 1, 4, 2}
+	tests := []struct {
+		name        string
+		aggregation Aggregation
+		values      []float64
+		expected    float64
+		expectedErr string
+	}{
+		{"Max", AggregationMax, values, 4, ""},
+		{"Min", AggregationMin, values, 1, ""},
+		{"Avg", AggregationAvg, values, 2.5, ""},
+		{"Last", AggregationLast, values, 2, ""},
+		{"Single value", AggregationAvg, []float64{5}, 5, ""},
+		{"No values", AggregationMax, nil, 0, "no values to aggregate"},
+		{"Invalid aggregation", Aggregation("sum"), values, 0, "invalid aggregation sum"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := Aggregate(test.aggregation, test.values)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, result)
+			}
+		})
+	}
+}
+
+func TestWindow_Overlaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   Window
+		start    time.Duration
+		expected bool
+	}{
+		{"Whole measurement", Window{}, 5 * time.Second, true},
+		{"Interval before window", Window{From: 2 * time.Second}, 0, false},
+		{"Interval ending at window start", Window{From: 2 * time.Second}, time.Second, false},
+		{"Interval partially in window", Window{From: 1500 * time.Millisecond}, time.Second, true},
+		{"Interval in window", Window{From: time.Second, To: 3 * time.Second}, 2 * time.Second, true},
+		{"Interval starting at window end", Window{From: time.Second, To: 3 * time.Second}, 3 * time.Second, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.window.Overlaps(test.start, time.Second))
+		})
+	}
+}
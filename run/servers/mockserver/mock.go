@@ -0,0 +1,142 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"github.com/wstool/wst/app"
+	"github.com/wstool/wst/conf/types"
+	"net/http"
+	"regexp"
+	"text/template"
+	"time"
+)
+
+// Route defines a canned response returned for requests matching the method and path pattern.
+type Route struct {
+	Method  string
+	Path    string
+	Status  int
+	Headers types.Headers
+	Body    *template.Template
+	Delay   time.Duration
+	// params contains names of the path pattern wildcards.
+	params []string
+}
+
+// TemplateData is the data available in the route body template.
+type TemplateData struct {
+	Request *Request
+	// Params contains values of the path pattern wildcards.
+	Params map[string]string
+}
+
+func (r *Route) renderBody(req *Request, httpReq *http.Request) (string, error) {
+	if r.Body == nil {
+		return "", nil
+	}
+	data := &TemplateData{
+		Request: req,
+		Params:  make(map[string]string, len(r.params)),
+	}
+	for _, param := range r.params {
+		data.Params[param] = httpReq.PathValue(param)
+	}
+	var buf bytes.Buffer
+	if err := r.Body.Execute(&buf, data); err != nil {
+		return "", errors.Errorf("failed to render mock route %s body: %v", r.pattern(), err)
+	}
+	return buf.String(), nil
+}
+
+func (r *Route) pattern() string {
+	if r.Method == "" {
+		return r.Path
+	}
+	return r.Method + " " + r.Path
+}
+
+// Config is the mock server configuration with routes in the matching order.
+type Config struct {
+	Routes []*Route
+}
+
+type Maker interface {
+	Make(config *types.MockServer) (*Config, error)
+}
+
+type nativeMaker struct {
+	fnd app.Foundation
+}
+
+func CreateMaker(fnd app.Foundation) Maker {
+	return &nativeMaker{
+		fnd: fnd,
+	}
+}
+
+var paramRegexp = regexp.MustCompile(`\{([^}.]+)(\.\.\.)?}`)
+
+// Make creates the mock server config. It returns nil if no routes are defined as the server is not a mock.
+func (m *nativeMaker) Make(config *types.MockServer) (*Config, error) {
+	if len(config.Routes) == 0 {
+		return nil, nil
+	}
+	routes := make([]*Route, len(config.Routes))
+	for i, routeConfig := range config.Routes {
+		route := &Route{
+			Method:  routeConfig.Method,
+			Path:    routeConfig.Path,
+			Status:  routeConfig.Status,
+			Headers: routeConfig.Headers,
+			Delay:   time.Duration(routeConfig.Delay) * time.Millisecond,
+		}
+		if route.Path == "" {
+			route.Path = "/"
+		}
+		if route.Status == 0 {
+			route.Status = http.StatusOK
+		}
+		if routeConfig.Body != "" {
+			body, err := template.New(route.pattern()).Parse(routeConfig.Body)
+			if err != nil {
+				return nil, errors.Errorf("invalid mock route %s body template: %v", route.pattern(), err)
+			}
+			route.Body = body
+		}
+		if _, err := newRouteMux(route, http.NotFoundHandler()); err != nil {
+			return nil, err
+		}
+		for _, match := range paramRegexp.FindAllStringSubmatch(route.Path, -1) {
+			route.params = append(route.params, match[1])
+		}
+		routes[i] = route
+	}
+	return &Config{Routes: routes}, nil
+}
+
+// newRouteMux creates a mux for a single route so the routes can be matched in the configured order.
+func newRouteMux(route *Route, handler http.Handler) (mux *http.ServeMux, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			mux = nil
+			err = errors.Errorf("invalid mock route pattern %s: %v", route.pattern(), r)
+		}
+	}()
+	mux = http.NewServeMux()
+	mux.Handle(route.pattern(), handler)
+	return mux, nil
+}
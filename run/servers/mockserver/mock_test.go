@@ -0,0 +1,115 @@
+package mockserver
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/conf/types"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	"testing"
+	"time"
+)
+
+func TestCreateMaker(t *testing.T) {
+	fndMock := appMocks.NewMockFoundation(t)
+
+	got := CreateMaker(fndMock)
+
+	maker, ok := got.(*nativeMaker)
+	require.True(t, ok)
+	assert.Equal(t, fndMock, maker.fnd)
+}
+
+func Test_nativeMaker_Make(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *types.MockServer
+		want     []*Route
+		wantBody []string
+		wantNil  bool
+		errMsg   string
+	}{
+		{
+			name:    "no routes",
+			config:  &types.MockServer{},
+			wantNil: true,
+		},
+		{
+			name: "routes with defaults and parameters",
+			config: &types.MockServer{
+				Routes: []types.MockRoute{
+					{
+						Method:  "GET",
+						Path:    "/users/{id}/{rest...}",
+						Status:  201,
+						Headers: types.Headers{"Content-Type": "text/plain"},
+						Body:    "user {{ .Params.id }}",
+						Delay:   100,
+					},
+					{},
+				},
+			},
+			want: []*Route{
+				{
+					Method:  "GET",
+					Path:    "/users/{id}/{rest...}",
+					Status:  201,
+					Headers: types.Headers{"Content-Type": "text/plain"},
+					Delay:   100 * time.Millisecond,
+					params:  []string{"id", "rest"},
+				},
+				{
+					Path:   "/",
+					Status: 200,
+				},
+			},
+			wantBody: []string{"user {{.Params.id}}", ""},
+		},
+		{
+			name: "invalid body template",
+			config: &types.MockServer{
+				Routes: []types.MockRoute{
+					{Path: "/", Body: "{{ .Request"},
+				},
+			},
+			errMsg: "invalid mock route / body template",
+		},
+		{
+			name: "invalid path pattern",
+			config: &types.MockServer{
+				Routes: []types.MockRoute{
+					{Method: "GET", Path: "/{id"},
+				},
+			},
+			errMsg: "invalid mock route pattern GET /{id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &nativeMaker{fnd: appMocks.NewMockFoundation(t)}
+
+			got, err := m.Make(tt.config)
+
+			if tt.errMsg != "" {
+				assert.ErrorContains(t, err, tt.errMsg)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantNil {
+				assert.Nil(t, got)
+				return
+			}
+			require.Len(t, got.Routes, len(tt.want))
+			for i, route := range got.Routes {
+				if tt.wantBody[i] == "" {
+					assert.Nil(t, route.Body)
+				} else {
+					require.NotNil(t, route.Body)
+					assert.Equal(t, tt.wantBody[i], route.Body.Root.String())
+				}
+				route.Body = nil
+				assert.Equal(t, tt.want[i], route)
+			}
+		})
+	}
+}
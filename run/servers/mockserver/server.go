@@ -0,0 +1,236 @@
+// Copyright 2024 Jakub Zelenka and The WST Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockserver
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Request is a request received by the mock server.
+type Request struct {
+	Method  string
+	Path    string
+	Query   string
+	Headers http.Header
+	Body    string
+	// Route is the pattern of the matched route or empty if no route matched.
+	Route string
+	Time  time.Time
+}
+
+// RequestsDataKey returns the runtime data key of the requests received by the mock server of the service.
+func RequestsDataKey(serviceName string) string {
+	return fmt.Sprintf("mock/%s/requests", serviceName)
+}
+
+// ServerDataKey returns the runtime data key of the mock server of the service.
+func ServerDataKey(serviceName string) string {
+	return fmt.Sprintf("mock/%s/server", serviceName)
+}
+
+// Requests is the record of the received requests. It is safe for concurrent use.
+type Requests struct {
+	mu       sync.Mutex
+	requests []Request
+}
+
+// Add appends the request to the record.
+func (r *Requests) Add(req Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+}
+
+// All returns a copy of all recorded requests in the receiving order.
+func (r *Requests) All() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	requests := make([]Request, len(r.requests))
+	copy(requests, r.requests)
+	return requests
+}
+
+// Server is an HTTP server returning canned responses of the configured routes and recording all received requests.
+type Server struct {
+	logger   *zap.SugaredLogger
+	config   *Config
+	handlers []routeHandler
+	mu       sync.Mutex
+	requests *Requests
+	server   *http.Server
+	address  string
+	done     chan struct{}
+}
+
+type routeHandler struct {
+	route *Route
+	mux   *http.ServeMux
+}
+
+func NewServer(logger *zap.SugaredLogger, config *Config) *Server {
+	s := &Server{
+		logger:   logger,
+		config:   config,
+		requests: &Requests{},
+	}
+	for _, route := range config.Routes {
+		// The pattern has been already validated when making the config.
+		mux, _ := newRouteMux(route, s.routeHandlerFunc(route))
+		s.handlers = append(s.handlers, routeHandler{route: route, mux: mux})
+	}
+	return s
+}
+
+// Start starts listening on the address. The server can be started again after it has been closed.
+func (s *Server) Start(address string) error {
+	if s.IsRunning() {
+		return errors.Errorf("mock server is already running on %s", s.address)
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Errorf("failed to start mock server on %s: %v", address, err)
+	}
+	server := &http.Server{Handler: s}
+	done := make(chan struct{})
+	s.mu.Lock()
+	s.server = server
+	s.address = listener.Addr().String()
+	s.done = done
+	s.mu.Unlock()
+	go func() {
+		defer close(done)
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Errorf("Mock server on %s failed: %v", listener.Addr().String(), err)
+		}
+	}()
+	return nil
+}
+
+// Address returns the address that the server listens on.
+func (s *Server) Address() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.address
+}
+
+// IsRunning returns whether the server is started and not closed.
+func (s *Server) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.server != nil
+}
+
+// SetRequests sets the record that the received requests are added to. It is used for recording them in the runtime
+// data of the instance.
+func (s *Server) SetRequests(requests *Requests) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = requests
+}
+
+// Requests returns a copy of all received requests in the receiving order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests.All()
+}
+
+// Close stops the server. The received requests are kept.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	server := s.server
+	done := s.done
+	s.server = nil
+	s.mu.Unlock()
+	if server == nil {
+		return nil
+	}
+	err := server.Close()
+	<-done
+	if err != nil {
+		return errors.Errorf("failed to close mock server: %v", err)
+	}
+	return nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.logger.Debugf("Mock server failed to read request body: %v", err)
+	}
+	req := Request{
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Query:   r.URL.RawQuery,
+		Headers: r.Header.Clone(),
+		Body:    string(body),
+		Time:    time.Now(),
+	}
+	var matched *routeHandler
+	for i := range s.handlers {
+		if _, pattern := s.handlers[i].mux.Handler(r); pattern != "" {
+			matched = &s.handlers[i]
+			req.Route = pattern
+			break
+		}
+	}
+	s.mu.Lock()
+	requests := s.requests
+	s.mu.Unlock()
+	requests.Add(req)
+	s.logger.Debugf("Mock server received request %s %s matching route %q", req.Method, req.Path, req.Route)
+
+	if matched == nil {
+		http.NotFound(w, r)
+		return
+	}
+	matched.mux.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestKey{}, &req)))
+}
+
+type requestKey struct{}
+
+func (s *Server) routeHandlerFunc(route *Route) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, _ := r.Context().Value(requestKey{}).(*Request)
+		body, err := route.renderBody(req, r)
+		if err != nil {
+			s.logger.Errorf("Mock server error: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if route.Delay > 0 {
+			timer := time.NewTimer(route.Delay)
+			select {
+			case <-timer.C:
+			case <-r.Context().Done():
+				timer.Stop()
+				return
+			}
+		}
+		for name, value := range route.Headers {
+			w.Header().Set(name, value)
+		}
+		w.WriteHeader(route.Status)
+		_, _ = io.WriteString(w, body)
+	}
+}
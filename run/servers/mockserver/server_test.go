@@ -0,0 +1,182 @@
+package mockserver
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
+	appMocks "github.com/wstool/wst/mocks/generated/app"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startTestServer(t *testing.T, config *types.MockServer) *Server {
+	m := &nativeMaker{fnd: appMocks.NewMockFoundation(t)}
+	cfg, err := m.Make(config)
+	require.NoError(t, err)
+	s := NewServer(external.NewMockLogger().SugaredLogger, cfg)
+	require.NoError(t, s.Start("127.0.0.1:0"))
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	config := &types.MockServer{
+		Routes: []types.MockRoute{
+			{
+				Method:  "POST",
+				Path:    "/items/{id}",
+				Status:  201,
+				Headers: types.Headers{"X-Mock": "items"},
+				Body:    "{{ .Request.Method }} item {{ .Params.id }}: {{ .Request.Body }}",
+			},
+			{
+				Path: "/items/{id}",
+				Body: "any item {{ .Params.id }}",
+			},
+			{
+				Path:  "/slow",
+				Delay: 100,
+			},
+			{
+				Path: "/broken",
+				Body: "{{ .Request.Missing }}",
+			},
+		},
+	}
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		wantStatus  int
+		wantHeader  string
+		wantBody    string
+		wantRoute   string
+		wantElapsed time.Duration
+	}{
+		{
+			name:       "first matching route",
+			method:     "POST",
+			path:       "/items/5?debug=1",
+			body:       "data",
+			wantStatus: 201,
+			wantHeader: "items",
+			wantBody:   "POST item 5: data",
+			wantRoute:  "POST /items/{id}",
+		},
+		{
+			name:       "route without method",
+			method:     "GET",
+			path:       "/items/7",
+			wantStatus: 200,
+			wantBody:   "any item 7",
+			wantRoute:  "/items/{id}",
+		},
+		{
+			name:        "delayed route",
+			method:      "GET",
+			path:        "/slow",
+			wantStatus:  200,
+			wantRoute:   "/slow",
+			wantElapsed: 100 * time.Millisecond,
+		},
+		{
+			name:       "failed body rendering",
+			method:     "GET",
+			path:       "/broken",
+			wantStatus: 500,
+			wantBody:   "failed to render mock route /broken body",
+			wantRoute:  "/broken",
+		},
+		{
+			name:       "no matching route",
+			method:     "GET",
+			path:       "/unknown",
+			wantStatus: 404,
+			wantBody:   "404 page not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := startTestServer(t, config)
+			req, err := http.NewRequest(tt.method, "http://"+s.Address()+tt.path, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("X-Forwarded-For", "10.0.0.1")
+
+			start := time.Now()
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantHeader, resp.Header.Get("X-Mock"))
+			assert.Contains(t, string(body), tt.wantBody)
+			assert.GreaterOrEqual(t, time.Since(start), tt.wantElapsed)
+			requests := s.Requests()
+			require.Len(t, requests, 1)
+			assert.Equal(t, tt.method, requests[0].Method)
+			assert.Equal(t, strings.Split(tt.path, "?")[0], requests[0].Path)
+			assert.Equal(t, tt.body, requests[0].Body)
+			assert.Equal(t, "10.0.0.1", requests[0].Headers.Get("X-Forwarded-For"))
+			assert.Equal(t, tt.wantRoute, requests[0].Route)
+		})
+	}
+}
+
+func TestServer_Lifecycle(t *testing.T) {
+	s := startTestServer(t, &types.MockServer{Routes: []types.MockRoute{{Path: "/"}}})
+	address := s.Address()
+	assert.True(t, s.IsRunning())
+	assert.ErrorContains(t, s.Start("127.0.0.1:0"), "mock server is already running on "+address)
+
+	resp, err := http.Get("http://" + address + "/")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.NoError(t, s.Close())
+	assert.False(t, s.IsRunning())
+	assert.NoError(t, s.Close())
+	_, err = http.Get("http://" + address + "/")
+	assert.Error(t, err)
+
+	require.NoError(t, s.Start(address))
+	resp, err = http.Get("http://" + address + "/")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Len(t, s.Requests(), 2)
+}
+
+func TestServer_SetRequests(t *testing.T) {
+	s := startTestServer(t, &types.MockServer{Routes: []types.MockRoute{{Path: "/"}}})
+	requests := &Requests{}
+	s.SetRequests(requests)
+
+	resp, err := http.Get("http://" + s.Address() + "/users")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	all := requests.All()
+	require.Len(t, all, 1)
+	assert.Equal(t, "/users", all[0].Path)
+	assert.Equal(t, all, s.Requests())
+}
+
+func TestDataKeys(t *testing.T) {
+	assert.Equal(t, "mock/backend/requests", RequestsDataKey("backend"))
+	assert.Equal(t, "mock/backend/server", ServerDataKey("backend"))
+}
+
+func TestServer_StartError(t *testing.T) {
+	s := NewServer(external.NewMockLogger().SugaredLogger, &Config{})
+
+	err := s.Start("127.0.0.1:-1")
+
+	assert.ErrorContains(t, err, "failed to start mock server on 127.0.0.1:-1")
+	assert.False(t, s.IsRunning())
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/wstool/wst/run/sandboxes/sandbox"
 	"github.com/wstool/wst/run/servers/actions"
 	"github.com/wstool/wst/run/servers/configs"
+	"github.com/wstool/wst/run/servers/mockserver"
 	"github.com/wstool/wst/run/servers/templates"
 	"os/user"
 	"strings"
@@ -43,6 +44,8 @@ type Server interface {
 	Parameters() parameters.Parameters
 	Templates() templates.Templates
 	Template(name string) (templates.Template, bool)
+	// MockServer returns the mock server config or nil if the server is not a mock.
+	MockServer() *mockserver.Config
 }
 
 type Servers map[string]map[string]Server
@@ -72,6 +75,7 @@ type nativeMaker struct {
 	fnd             app.Foundation
 	actionsMaker    actions.Maker
 	configsMaker    configs.Maker
+	mockMaker       mockserver.Maker
 	sandboxesMaker  sandboxes.Maker
 	templatesMaker  templates.Maker
 	parametersMaker parameters.Maker
@@ -86,6 +90,7 @@ func CreateMaker(
 		fnd:             fnd,
 		actionsMaker:    actions.CreateMaker(fnd, expectationsMaker, parametersMaker),
 		configsMaker:    configs.CreateMaker(fnd, parametersMaker),
+		mockMaker:       mockserver.CreateMaker(fnd),
 		sandboxesMaker:  sandboxes.CreateMaker(fnd),
 		templatesMaker:  templates.CreateMaker(fnd),
 		parametersMaker: parametersMaker,
@@ -122,6 +127,11 @@ func (m *nativeMaker) Make(config *types.Spec) (Servers, error) {
 			return nil, err
 		}
 
+		serverMock, err := m.mockMaker.Make(&server.Mock)
+		if err != nil {
+			return nil, err
+		}
+
 		if _, ok := srvs[name]; !ok {
 			srvs[name] = make(map[string]Server)
 		}
@@ -138,6 +148,7 @@ func (m *nativeMaker) Make(config *types.Spec) (Servers, error) {
 			templates:  serverTemplates,
 			parameters: serverParameters,
 			sandboxes:  serverSandboxes,
+			mock:       serverMock,
 		}
 	}
 
@@ -194,6 +205,7 @@ type nativeServer struct {
 	templates  templates.Templates
 	parameters parameters.Parameters
 	sandboxes  sandboxes.Sandboxes
+	mock       *mockserver.Config
 }
 
 func (s *nativeServer) inherit() error {
@@ -252,6 +264,10 @@ func (s *nativeServer) inherit() error {
 		s.port = s.parent.port
 	}
 
+	if s.mock == nil {
+		s.mock = s.parent.mock
+	}
+
 	s.actions.Inherit(s.parent.actions)
 	s.configs.Inherit(s.parent.configs)
 	s.templates.Inherit(s.parent.templates)
@@ -300,6 +316,10 @@ func (s *nativeServer) SequentialAction(name string) (actions.SequentialAction,
 	return act, ok
 }
 
+func (s *nativeServer) MockServer() *mockserver.Config {
+	return s.mock
+}
+
 func (s *nativeServer) Config(name string) (configs.Config, bool) {
 	cfg, ok := s.configs[name]
 	return cfg, ok
@@ -21,6 +21,7 @@ import (
 	"github.com/wstool/wst/run/sandboxes"
 	"github.com/wstool/wst/run/servers/actions"
 	"github.com/wstool/wst/run/servers/configs"
+	"github.com/wstool/wst/run/servers/mockserver"
 	"github.com/wstool/wst/run/servers/templates"
 	"os/user"
 	"testing"
@@ -2620,6 +2621,7 @@ func Test_nativeMaker_Make(t *testing.T) {
 				fnd:             fndMock,
 				actionsMaker:    actionsMock,
 				configsMaker:    configsMock,
+				mockMaker:       mockserver.CreateMaker(fndMock),
 				templatesMaker:  templatesMock,
 				parametersMaker: parametersMock,
 				sandboxesMaker:  sandboxesMock,
@@ -2768,6 +2770,14 @@ func Test_nativeServer_SequentialAction(t *testing.T) {
 	assert.Equal(t, sas[1], sa)
 }
 
+func Test_nativeServer_MockServer(t *testing.T) {
+	s := testNativeServer(t)
+	assert.Nil(t, s.MockServer())
+	cfg := &mockserver.Config{Routes: []*mockserver.Route{{Path: "/"}}}
+	s.mock = cfg
+	assert.Same(t, cfg, s.MockServer())
+}
+
 func Test_nativeServer_Config(t *testing.T) {
 	confs := testConfigs(t, 2)
 	s := testNativeServer(t)
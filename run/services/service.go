@@ -34,19 +34,25 @@ import (
 	"github.com/wstool/wst/run/sandboxes/sandbox"
 	"github.com/wstool/wst/run/servers"
 	"github.com/wstool/wst/run/servers/configs"
+	"github.com/wstool/wst/run/servers/mockserver"
 	"github.com/wstool/wst/run/services/template"
 	"github.com/wstool/wst/run/spec/defaults"
 	"io"
+	"net"
 	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
+	"sync"
+	"time"
 )
 
 type Service interface {
 	LocalAddress() string
 	LocalPort() int32
 	PrivateAddress() string
+	SetProxyAddress(address string) error
 	PrivateUrl(scheme string) (string, error)
 	PublicUrl(scheme string, path string) (string, error)
 	UdsPath(...string) (string, error)
@@ -67,13 +73,24 @@ type Service interface {
 	WorkspaceScriptPaths() map[string]string
 	Environment() environment.Environment
 	FindCertificate(name string) (*certificates.RenderedCertificate, error)
+	FindScript(name string) (scripts.Script, error)
 	Task() task.Task
 	RenderTemplate(text string, params parameters.Parameters) (string, error)
 	OutputReader(ctx context.Context, outputType output.Type) (io.Reader, error)
+	OutputReaderSince(ctx context.Context, outputType output.Type, since time.Time) (io.Reader, error)
 	Sandbox() sandbox.Sandbox
+	SandboxType() providers.Type
 	Server() servers.Server
 	ServerParameters() parameters.Parameters
 	ExecCommand(ctx context.Context, cmd *environment.Command, oc output.Collector) error
+	FileExists(ctx context.Context, path string) (bool, error)
+	PortReady(ctx context.Context, port int32) (bool, error)
+	UdsReady(ctx context.Context, path string) (bool, error)
+	IsRunning(ctx context.Context) (bool, error)
+	Processes(ctx context.Context) ([]environment.Process, error)
+	File(ctx context.Context, path string, offset int64) (*environment.File, error)
+	Signal(ctx context.Context, signal os.Signal) error
+	SignalProcesses(ctx context.Context, processes []environment.Process, signal os.Signal) error
 	Reload(ctx context.Context) error
 	Restart(ctx context.Context) error
 	Start(ctx context.Context) error
@@ -81,6 +98,8 @@ type Service interface {
 	IsPublic() bool
 	Workspace() string
 	SetTemplate(template template.Template)
+	// MockServer returns the in-process mock server or nil if the service server is not a mock.
+	MockServer() *mockserver.Server
 }
 
 type Services map[string]Service
@@ -191,6 +210,18 @@ func (m *nativeMaker) Make(
 			return nil, errors.Errorf("sandbox %s is not available for service %s", sandboxName, serviceName)
 		}
 
+		var mockServer *mockserver.Server
+		if mockConfig := server.MockServer(); mockConfig != nil {
+			if providerType != providers.LocalType {
+				return nil, errors.Errorf(
+					"mock server service %s supports only local environment but it uses %s environment",
+					serviceName,
+					providerType,
+				)
+			}
+			mockServer = mockserver.NewServer(m.fnd.Logger(), mockConfig)
+		}
+
 		env, ok := environments[providerType]
 		if !ok {
 			return nil, errors.Errorf("environment %s not found for service %s", sandboxName, serviceName)
@@ -262,12 +293,15 @@ func (m *nativeMaker) Make(
 			server:           server,
 			serverParameters: serverParameters,
 			sandbox:          sb,
+			sandboxType:      providerType,
 			configs:          nativeConfigs,
 			workspace:        filepath.Join(instanceWorkspace, serviceName),
+			proxy:            &consumerProxy{},
+			mock:             mockServer,
 		}
 
 		svcs[serviceName] = service
-		tmplSvcs[serviceName] = service
+		tmplSvcs[serviceName] = &consumerService{service}
 	}
 
 	for _, svc := range svcs {
@@ -294,6 +328,7 @@ type nativeService struct {
 	server                 servers.Server
 	serverParameters       parameters.Parameters
 	sandbox                sandbox.Sandbox
+	sandboxType            providers.Type
 	task                   task.Task
 	environment            environment.Environment
 	configs                map[string]nativeServiceConfig
@@ -304,6 +339,8 @@ type nativeService struct {
 	workspaceScriptPaths   map[string]string
 	workspace              string
 	template               template.Template
+	proxy                  *consumerProxy
+	mock                   *mockserver.Server
 }
 
 func (s *nativeService) Port() int32 {
@@ -390,6 +427,19 @@ func (s *nativeService) OutputReader(ctx context.Context, outputType output.Type
 	return reader, nil
 }
 
+// OutputReaderSince returns a reader of the service output since the time that does not affect other readers.
+func (s *nativeService) OutputReaderSince(
+	ctx context.Context,
+	outputType output.Type,
+	since time.Time,
+) (io.Reader, error) {
+	if s.task == nil || reflect.ValueOf(s.task).IsNil() {
+		return nil, errors.Errorf("service has not started yet")
+	}
+
+	return s.environment.OutputSince(ctx, s.task, outputType, since)
+}
+
 func (s *nativeService) renderingPaths(path string, dirType dir.DirType) (string, string, error) {
 	environmentRootPath := s.environment.RootPath(s.workspace)
 	sandboxDir, err := s.sandbox.Dir(dirType)
@@ -544,7 +594,78 @@ func (s *nativeService) ExecCommand(ctx context.Context, cmd *environment.Comman
 	return s.environment.ExecTaskCommand(ctx, s.makeEnvServiceSettings(), s.task, cmd, oc)
 }
 
+func (s *nativeService) FileExists(ctx context.Context, path string) (bool, error) {
+	if s.task == nil || reflect.ValueOf(s.task).IsNil() {
+		return false, errors.Errorf("service has not started yet")
+	}
+
+	return s.environment.FileExists(ctx, s.makeEnvServiceSettings(), s.task, path)
+}
+
+func (s *nativeService) PortReady(ctx context.Context, port int32) (bool, error) {
+	if s.task == nil || reflect.ValueOf(s.task).IsNil() {
+		return false, errors.Errorf("service has not started yet")
+	}
+
+	return s.environment.PortReady(ctx, s.makeEnvServiceSettings(), s.task, port)
+}
+
+func (s *nativeService) UdsReady(ctx context.Context, path string) (bool, error) {
+	if s.task == nil || reflect.ValueOf(s.task).IsNil() {
+		return false, errors.Errorf("service has not started yet")
+	}
+
+	return s.environment.UdsReady(ctx, s.makeEnvServiceSettings(), s.task, path)
+}
+
+func (s *nativeService) IsRunning(ctx context.Context) (bool, error) {
+	if s.mock != nil {
+		return s.mock.IsRunning(), nil
+	}
+	if s.task == nil || reflect.ValueOf(s.task).IsNil() {
+		return false, errors.Errorf("service has not started yet")
+	}
+
+	return s.environment.TaskRunning(ctx, s.makeEnvServiceSettings(), s.task)
+}
+
+func (s *nativeService) Processes(ctx context.Context) ([]environment.Process, error) {
+	if s.task == nil || reflect.ValueOf(s.task).IsNil() {
+		return nil, errors.Errorf("service has not started yet")
+	}
+
+	return s.environment.TaskProcesses(ctx, s.makeEnvServiceSettings(), s.task)
+}
+
+func (s *nativeService) File(ctx context.Context, path string, offset int64) (*environment.File, error) {
+	if s.task == nil || reflect.ValueOf(s.task).IsNil() {
+		return nil, errors.Errorf("service has not started yet")
+	}
+
+	return s.environment.TaskFile(ctx, s.makeEnvServiceSettings(), s.task, path, offset)
+}
+
+func (s *nativeService) Signal(ctx context.Context, signal os.Signal) error {
+	if s.task == nil || reflect.ValueOf(s.task).IsNil() {
+		return errors.Errorf("service has not started yet")
+	}
+
+	return s.environment.ExecTaskSignal(ctx, s.makeEnvServiceSettings(), s.task, signal)
+}
+
+func (s *nativeService) SignalProcesses(ctx context.Context, processes []environment.Process, signal os.Signal) error {
+	if s.task == nil || reflect.ValueOf(s.task).IsNil() {
+		return errors.Errorf("service has not started yet")
+	}
+
+	return s.environment.ExecTaskProcessesSignal(ctx, s.makeEnvServiceSettings(), s.task, processes, signal)
+}
+
 func (s *nativeService) Reload(ctx context.Context) error {
+	if s.mock != nil {
+		// Mock server has no configuration to reload.
+		return nil
+	}
 	hook, err := s.sandbox.Hook(hooks.ReloadHookType)
 	if err != nil {
 		return err
@@ -557,6 +678,12 @@ func (s *nativeService) Reload(ctx context.Context) error {
 }
 
 func (s *nativeService) Restart(ctx context.Context) error {
+	if s.mock != nil {
+		if err := s.mock.Close(); err != nil {
+			return err
+		}
+		return s.startMock()
+	}
 	hook, err := s.sandbox.Hook(hooks.RestartHookType)
 	if err != nil {
 		return err
@@ -568,7 +695,17 @@ func (s *nativeService) Restart(ctx context.Context) error {
 	return err
 }
 
+func (s *nativeService) startMock() error {
+	if s.fnd.DryRun() {
+		return nil
+	}
+	return s.mock.Start(s.environment.ServiceLocalAddress(s.name, s.port, s.server.Port()))
+}
+
 func (s *nativeService) Start(ctx context.Context) error {
+	if s.mock != nil {
+		return s.startMock()
+	}
 	hook, err := s.sandbox.Hook(hooks.StartHookType)
 	if err != nil {
 		return err
@@ -604,6 +741,9 @@ func (s *nativeService) Start(ctx context.Context) error {
 }
 
 func (s *nativeService) Stop(ctx context.Context) error {
+	if s.mock != nil {
+		return s.mock.Close()
+	}
 	hook, err := s.sandbox.Hook(hooks.StopHookType)
 	if err != nil {
 		return err
@@ -635,6 +775,25 @@ func (s *nativeService) LocalPort() int32 {
 	return s.environment.ServiceLocalPort(s.port, s.server.Port())
 }
 
+// SetProxyAddress makes the service addresses seen by its consumers point to the proxy in front of the service. The
+// service itself keeps using its real addresses. Empty address removes the proxy.
+func (s *nativeService) SetProxyAddress(address string) error {
+	if address == "" {
+		s.proxy.set("", 0)
+		return nil
+	}
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Errorf("invalid proxy address %s: %v", address, err)
+	}
+	proxyPort, err := strconv.ParseInt(port, 10, 32)
+	if err != nil {
+		return errors.Errorf("invalid proxy address %s port: %v", address, err)
+	}
+	s.proxy.set(address, int32(proxyPort))
+	return nil
+}
+
 func (s *nativeService) Executable() (string, error) {
 	if s.task == nil || reflect.ValueOf(s.task).IsNil() {
 		return "", errors.Errorf("service has not started yet")
@@ -688,10 +847,18 @@ func (s *nativeService) RenderTemplate(text string, params parameters.Parameters
 	return s.template.RenderToString(text, params)
 }
 
+func (s *nativeService) MockServer() *mockserver.Server {
+	return s.mock
+}
+
 func (s *nativeService) Sandbox() sandbox.Sandbox {
 	return s.sandbox
 }
 
+func (s *nativeService) SandboxType() providers.Type {
+	return s.sandboxType
+}
+
 func (s *nativeService) Environment() environment.Environment {
 	return s.environment
 }
@@ -707,3 +874,69 @@ func (s *nativeService) FindCertificate(name string) (*certificates.RenderedCert
 	}
 	return cert, nil
 }
+
+func (s *nativeService) FindScript(name string) (scripts.Script, error) {
+	script, ok := s.scripts[name]
+	if !ok {
+		return nil, errors.Errorf("script %s not found", name)
+	}
+	return script, nil
+}
+
+// consumerProxy is the proxy in front of the service that the service consumers connect to.
+type consumerProxy struct {
+	mu      sync.RWMutex
+	address string
+	port    int32
+}
+
+func (p *consumerProxy) get() (string, int32) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.address, p.port
+}
+
+func (p *consumerProxy) set(address string, port int32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.address = address
+	p.port = port
+}
+
+// consumerService is the template view of the service used by other services. Its addresses point to the proxy in
+// front of the service if it is set.
+type consumerService struct {
+	*nativeService
+}
+
+func (c *consumerService) LocalAddress() string {
+	if address, _ := c.proxy.get(); address != "" {
+		return address
+	}
+	return c.nativeService.LocalAddress()
+}
+
+func (c *consumerService) LocalPort() int32 {
+	if address, port := c.proxy.get(); address != "" {
+		return port
+	}
+	return c.nativeService.LocalPort()
+}
+
+func (c *consumerService) PrivateAddress() string {
+	if address, _ := c.proxy.get(); address != "" {
+		return address
+	}
+	return c.nativeService.PrivateAddress()
+}
+
+func (c *consumerService) PrivateUrl(scheme string) (string, error) {
+	privateUrl, err := c.nativeService.PrivateUrl(scheme)
+	if err != nil {
+		return "", err
+	}
+	if address, _ := c.proxy.get(); address != "" {
+		return fmt.Sprintf("%s://%s", scheme, address), nil
+	}
+	return privateUrl, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/wstool/wst/conf/types"
+	"github.com/wstool/wst/mocks/authored/external"
 	appMocks "github.com/wstool/wst/mocks/generated/app"
 	environmentMocks "github.com/wstool/wst/mocks/generated/run/environments/environment"
 	outputMocks "github.com/wstool/wst/mocks/generated/run/environments/environment/output"
@@ -36,12 +37,15 @@ import (
 	"github.com/wstool/wst/run/sandboxes/dir"
 	"github.com/wstool/wst/run/sandboxes/hooks"
 	"github.com/wstool/wst/run/servers"
+	"github.com/wstool/wst/run/servers/mockserver"
 	"github.com/wstool/wst/run/servers/templates"
 	"github.com/wstool/wst/run/services/template"
 	"github.com/wstool/wst/run/spec/defaults"
 	"io"
 	"os"
+	"syscall"
 	"testing"
+	"time"
 )
 
 func TestServices_FindService(t *testing.T) {
@@ -342,6 +346,7 @@ func Test_nativeMaker_Make(t *testing.T) {
 				// Server mocks
 				fpmDebSrv := serversMocks.NewMockServer(t)
 				fpmDebSrv.On("Sandbox", providers.DockerType).Return(sb, true)
+				fpmDebSrv.On("MockServer").Return(nil)
 				fpmDebSrv.On("Config", "php.ini").Return(fpmPhpIniConfig, true)
 				fpmDebSrv.On("Config", "fpm.conf").Return(fpmConfConfig, true)
 				fpmDebSrv.On("Templates").Return(fpmTemplates)
@@ -351,6 +356,7 @@ func Test_nativeMaker_Make(t *testing.T) {
 
 				nginxDebSrv := serversMocks.NewMockServer(t)
 				nginxDebSrv.On("Sandbox", providers.DockerType).Return(sb, true)
+				nginxDebSrv.On("MockServer").Return(nil)
 				nginxDebSrv.On("Config", "nginx.conf").Return(nginxConfConfig, true)
 				nginxDebSrv.On("Templates").Return(nginxTemplates)
 				nginxDebSrv.On("Parameters").Return(parameters.Parameters{
@@ -533,6 +539,7 @@ func Test_nativeMaker_Make(t *testing.T) {
 				}
 				debSrv := serversMocks.NewMockServer(t)
 				debSrv.On("Sandbox", providers.LocalType).Return(sb, true)
+				debSrv.On("MockServer").Return(nil)
 				debSrv.On("Config", "c").Return(cfg, true)
 				debSrv.On("Templates").Return(tmpls)
 				debSrv.On("Parameters").Return(parameters.Parameters{
@@ -571,6 +578,7 @@ func Test_nativeMaker_Make(t *testing.T) {
 						"ps": createParamMock(t, "ps"),
 					},
 					sandbox:     sb,
+					sandboxType: providers.LocalType,
 					task:        nil,
 					environment: localEnv,
 					configs: map[string]nativeServiceConfig{
@@ -592,8 +600,9 @@ func Test_nativeMaker_Make(t *testing.T) {
 					workspaceScriptPaths:   nil,
 					workspace:              "/test/workspace/svc",
 					template:               nil,
+					proxy:                  &consumerProxy{},
 				}
-				tm.On("Make", svc, template.Services{"svc": svc}, tmpls).Return(tmpl)
+				tm.On("Make", svc, template.Services{"svc": &consumerService{svc}}, tmpls).Return(tmpl)
 				finalSvc := *svc
 				finalSvc.template = tmpl
 				svcs := Services{
@@ -668,6 +677,7 @@ func Test_nativeMaker_Make(t *testing.T) {
 				sb.On("Available").Return(true)
 				debSrv := serversMocks.NewMockServer(t)
 				debSrv.On("Sandbox", providers.LocalType).Return(sb, true)
+				debSrv.On("MockServer").Return(nil)
 				debSrv.On("Parameters").Return(parameters.Parameters{})
 				srvs := servers.Servers{
 					"php": {
@@ -758,6 +768,7 @@ func Test_nativeMaker_Make(t *testing.T) {
 				cfg := configsMocks.NewMockConfig(t)
 				debSrv := serversMocks.NewMockServer(t)
 				debSrv.On("Sandbox", providers.LocalType).Return(sb, true)
+				debSrv.On("MockServer").Return(nil)
 				debSrv.On("Config", "c").Return(cfg, true)
 				debSrv.On("Parameters").Return(parameters.Parameters{})
 				srvs := servers.Servers{
@@ -844,6 +855,7 @@ func Test_nativeMaker_Make(t *testing.T) {
 				sb.On("Available").Return(true)
 				debSrv := serversMocks.NewMockServer(t)
 				debSrv.On("Sandbox", providers.LocalType).Return(sb, true)
+				debSrv.On("MockServer").Return(nil)
 				debSrv.On("Config", "c").Return(nil, false)
 				debSrv.On("Parameters").Return(parameters.Parameters{})
 				srvs := servers.Servers{
@@ -926,6 +938,7 @@ func Test_nativeMaker_Make(t *testing.T) {
 				sb.On("Available").Return(true)
 				debSrv := serversMocks.NewMockServer(t)
 				debSrv.On("Sandbox", providers.LocalType).Return(sb, true)
+				debSrv.On("MockServer").Return(nil)
 				debSrv.On("Parameters").Return(parameters.Parameters{})
 				srvs := servers.Servers{
 					"php": {
@@ -1031,6 +1044,90 @@ func Test_nativeMaker_Make(t *testing.T) {
 			expectError:      true,
 			expectedErrorMsg: "sandbox local is not available for service svc",
 		},
+		{
+			name: "errors on mock server in non local sandbox",
+			config: map[string]types.Service{
+				"svc": {
+					Server: types.ServiceServer{
+						Name:    "php/debian",
+						Sandbox: "docker",
+						Configs: map[string]types.ServiceConfig{
+							"c": {
+								Parameters: types.Parameters{
+									"p0": 10,
+									"p1": 2,
+								},
+								Include: true,
+							},
+						},
+						Parameters: types.Parameters{
+							"p1": 1,
+							"p2": "data",
+						},
+					},
+					Resources: types.ServiceResources{
+						Scripts: types.ServiceResource{
+							IncludeList: []string{"s1"},
+						},
+						Certificates: types.ServiceResource{
+							IncludeAll:  false,
+							IncludeList: []string{},
+						},
+					},
+					Public: true,
+				},
+			},
+			instanceName: "testInstance",
+			instanceWs:   "/test/workspace",
+			instanceIdx:  1,
+			setupMocks: func(
+				t *testing.T,
+				pm *parametersMocks.MockMaker,
+				tm *templateMocks.MockMaker,
+			) (environments.Environments, servers.Servers, *resources.Resources, Services) {
+				localEnv := environmentMocks.NewMockEnvironment(t)
+				dockerEnv := environmentMocks.NewMockEnvironment(t)
+				kubeEnv := environmentMocks.NewMockEnvironment(t)
+				envs := environments.Environments{
+					providers.LocalType:      localEnv,
+					providers.DockerType:     dockerEnv,
+					providers.KubernetesType: kubeEnv,
+				}
+				serverParams := parameters.Parameters{
+					"p1": parameterMocks.NewMockParameter(t),
+					"p2": parameterMocks.NewMockParameter(t),
+				}
+				pm.On("Make", types.Parameters{
+					"p1": 1,
+					"p2": "data",
+				}).Return(serverParams, nil)
+				sb := sandboxMocks.NewMockSandbox(t)
+				sb.On("Available").Return(true)
+				debSrv := serversMocks.NewMockServer(t)
+				debSrv.On("Sandbox", providers.DockerType).Return(sb, true)
+				debSrv.On("MockServer").Return(&mockserver.Config{})
+				debSrv.On("Parameters").Return(parameters.Parameters{})
+				srvs := servers.Servers{
+					"php": {
+						"debian": debSrv,
+					},
+				}
+				scrs := scripts.Scripts{
+					"s1": scriptsMocks.NewMockScript(t),
+					"s2": scriptsMocks.NewMockScript(t),
+				}
+				certs := certificates.Certificates{
+					"ssl-cert": createCertificateMock(t, "ssl"),
+				}
+
+				return envs, srvs, &resources.Resources{
+					Scripts:      scrs,
+					Certificates: certs,
+				}, nil
+			},
+			expectError:      true,
+			expectedErrorMsg: "mock server service svc supports only local environment but it uses docker environment",
+		},
 		{
 			name: "errors on sandbox not found",
 			config: map[string]types.Service{
@@ -1326,6 +1423,7 @@ func Test_nativeMaker_Make(t *testing.T) {
 
 				debSrv := serversMocks.NewMockServer(t)
 				debSrv.On("Sandbox", providers.LocalType).Return(sb, true)
+				debSrv.On("MockServer").Return(nil)
 				debSrv.On("Parameters").Return(parameters.Parameters{})
 
 				srvs := servers.Servers{
@@ -1398,6 +1496,7 @@ func testingNativeService(t *testing.T) *nativeService {
 		server:                 serversMocks.NewMockServer(t),
 		serverParameters:       parameters.Parameters{"p1": parameterMocks.NewMockParameter(t)},
 		sandbox:                sandboxMocks.NewMockSandbox(t),
+		sandboxType:            providers.DockerType,
 		environment:            environmentMocks.NewMockEnvironment(t),
 		template:               templateMocks.NewMockTemplate(t),
 		environmentConfigPaths: map[string]string{"env": "/path/to/env"},
@@ -1405,6 +1504,7 @@ func testingNativeService(t *testing.T) *nativeService {
 		environmentScriptPaths: map[string]string{"script_env": "/path/to/script_env"},
 		workspaceScriptPaths:   map[string]string{"script_ws": "/path/to/script_ws"},
 		task:                   taskMocks.NewMockTask(t),
+		proxy:                  &consumerProxy{},
 	}
 }
 
@@ -1467,6 +1567,109 @@ func Test_nativeService_LocalPort(t *testing.T) {
 	assert.Equal(t, int32(80), svc.LocalPort())
 }
 
+func Test_nativeService_SetProxyAddress(t *testing.T) {
+	tests := []struct {
+		name           string
+		address        string
+		wantAddress    string
+		wantPort       int32
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name:        "proxy address set",
+			address:     "127.0.0.1:9100",
+			wantAddress: "127.0.0.1:9100",
+			wantPort:    9100,
+		},
+		{
+			name:           "address without port",
+			address:        "127.0.0.1",
+			expectError:    true,
+			expectedErrMsg: "invalid proxy address 127.0.0.1",
+		},
+		{
+			name:           "address with invalid port",
+			address:        "127.0.0.1:port",
+			expectError:    true,
+			expectedErrMsg: "invalid proxy address 127.0.0.1:port port",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testingNativeService(t)
+			consumer := &consumerService{svc}
+
+			err := svc.SetProxyAddress(tt.address)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+				svc.task.(*taskMocks.MockTask).On("PrivateUrl", "http").Return("http://svc:80")
+				assert.Equal(t, tt.wantAddress, consumer.LocalAddress())
+				assert.Equal(t, tt.wantAddress, consumer.PrivateAddress())
+				assert.Equal(t, tt.wantPort, consumer.LocalPort())
+				privateUrl, err := consumer.PrivateUrl("http")
+				assert.NoError(t, err)
+				assert.Equal(t, "http://"+tt.wantAddress, privateUrl)
+			}
+		})
+	}
+}
+
+func Test_nativeService_SetProxyAddress_Reset(t *testing.T) {
+	svc := testingNativeService(t)
+	consumer := &consumerService{svc}
+	envMock := svc.environment.(*environmentMocks.MockEnvironment)
+	envMock.On("ServiceLocalAddress", "svc", int32(8500), int32(80)).Return("127.0.0.1:8500")
+	envMock.On("ServiceLocalPort", int32(8500), int32(80)).Return(int32(8500))
+	envMock.On("ServicePrivateAddress", "svc", int32(8500), int32(80)).Return("svc:80")
+	svc.server.(*serversMocks.MockServer).On("Port").Return(int32(80))
+	svc.task.(*taskMocks.MockTask).On("PrivateUrl", "http").Return("http://svc:80")
+
+	assert.NoError(t, svc.SetProxyAddress("127.0.0.1:9100"))
+	assert.Equal(t, "127.0.0.1:9100", consumer.LocalAddress())
+	assert.NoError(t, svc.SetProxyAddress(""))
+	assert.Equal(t, "127.0.0.1:8500", consumer.LocalAddress())
+	assert.Equal(t, int32(8500), consumer.LocalPort())
+	assert.Equal(t, "svc:80", consumer.PrivateAddress())
+	privateUrl, err := consumer.PrivateUrl("http")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://svc:80", privateUrl)
+}
+
+func Test_nativeService_Restart_WithProxyAddress(t *testing.T) {
+	ctx := context.Background()
+	svc := testingNativeService(t)
+	consumer := &consumerService{svc}
+	envMock := svc.environment.(*environmentMocks.MockEnvironment)
+	envMock.On("ServiceLocalAddress", "svc", int32(8500), int32(12345)).Return("127.0.0.1:8500")
+	envMock.On("ServiceLocalPort", int32(8500), int32(12345)).Return(int32(8500))
+	envMock.On("ServicePrivateAddress", "svc", int32(8500), int32(12345)).Return("svc:12345")
+	hookMock := hooksMocks.NewMockHook(t)
+	svc.sandbox.(*sandboxMocks.MockSandbox).On("Hook", hooks.RestartHookType).Return(hookMock, nil)
+	hookMock.On(
+		"Execute",
+		ctx,
+		testingServiceSettings(svc),
+		svc.template,
+		svc.environment,
+		svc.task,
+	).Run(func(args mock.Arguments) {
+		// The restarted service templates must keep using the real service address.
+		assert.Equal(t, "127.0.0.1:8500", svc.LocalAddress())
+		assert.Equal(t, int32(8500), svc.LocalPort())
+		assert.Equal(t, "svc:12345", svc.PrivateAddress())
+	}).Return(nil, nil)
+
+	assert.NoError(t, svc.SetProxyAddress("127.0.0.1:9100"))
+	assert.NoError(t, svc.Restart(ctx))
+	assert.Equal(t, "127.0.0.1:9100", consumer.LocalAddress())
+	assert.Equal(t, int32(9100), consumer.LocalPort())
+}
+
 func Test_nativeService_UdsPath(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1553,131 +1756,818 @@ func Test_nativeService_WorkspaceScriptPaths(t *testing.T) {
 	assert.Equal(t, expected, svc.WorkspaceScriptPaths())
 }
 
-func Test_nativeService_User(t *testing.T) {
-	svc := testingNativeService(t)
-	svc.server.(*serversMocks.MockServer).On("User").Return("username")
-	assert.Equal(t, "username", svc.User())
-}
+func Test_nativeService_User(t *testing.T) {
+	svc := testingNativeService(t)
+	svc.server.(*serversMocks.MockServer).On("User").Return("username")
+	assert.Equal(t, "username", svc.User())
+}
+
+func Test_nativeService_Group(t *testing.T) {
+	svc := testingNativeService(t)
+	svc.server.(*serversMocks.MockServer).On("Group").Return("usergroup")
+	assert.Equal(t, "usergroup", svc.Group())
+}
+
+func testSandboxDirs() map[dir.DirType]string {
+	return map[dir.DirType]string{
+		dir.ConfDirType:   "/conf/dir",
+		dir.RunDirType:    "/run/dir",
+		dir.ScriptDirType: "/script/dir",
+	}
+}
+
+func Test_nativeService_Dirs(t *testing.T) {
+	svc := testingNativeService(t)
+	sandboxDirs := testSandboxDirs()
+	svc.sandbox.(*sandboxMocks.MockSandbox).On("Dirs").Return(sandboxDirs)
+	assert.Equal(t, sandboxDirs, svc.Dirs())
+}
+
+func testDirsGetters(t *testing.T, expectedDir string, cb func(svc *nativeService) (string, error)) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "successful result",
+			err:  nil,
+		},
+		{
+			name: "error during mkdir",
+			err:  errors.New("mkdir fail"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testingNativeService(t)
+			svc.sandbox.(*sandboxMocks.MockSandbox).On("Dirs").Return(testSandboxDirs())
+			envMock := svc.environment.(*environmentMocks.MockEnvironment)
+			envMock.On("RootPath", "/tmp/ws/svc").Return("/ws")
+			envMock.On("Mkdir", "svc", expectedDir, os.FileMode(0755)).Return(tt.err)
+			result, err := cb(svc)
+			assert.Equal(t, expectedDir, result)
+			assert.Equal(t, err, tt.err)
+		})
+	}
+}
+
+func Test_nativeService_ConfDir(t *testing.T) {
+	testDirsGetters(t, "/ws/conf/dir/svc", func(svc *nativeService) (string, error) {
+		return svc.ConfDir()
+	})
+}
+
+func Test_nativeService_RunDir(t *testing.T) {
+	testDirsGetters(t, "/ws/run/dir/svc", func(svc *nativeService) (string, error) {
+		return svc.RunDir()
+	})
+}
+
+func Test_nativeService_ScriptDir(t *testing.T) {
+	testDirsGetters(t, "/ws/script/dir/svc", func(svc *nativeService) (string, error) {
+		return svc.ScriptDir()
+	})
+}
+
+func Test_nativeService_Server(t *testing.T) {
+	svc := testingNativeService(t)
+	assert.Equal(t, svc.server, svc.Server())
+}
+
+func Test_nativeService_ServerParameters(t *testing.T) {
+	svc := testingNativeService(t)
+	assert.Equal(t, parameters.Parameters{"p1": parameterMocks.NewMockParameter(t)}, svc.ServerParameters())
+}
+
+func Test_nativeService_SetTemplate(t *testing.T) {
+	svc := testingNativeService(t)
+	mockTemplate := templateMocks.NewMockTemplate(t)
+	mockTemplate.TestData().Set("new", true)
+	svc.SetTemplate(mockTemplate)
+	assert.Equal(t, mockTemplate, svc.template)
+}
+
+func Test_nativeService_Workspace(t *testing.T) {
+	svc := testingNativeService(t)
+	assert.Equal(t, "/tmp/ws/svc", svc.Workspace())
+}
+
+func Test_nativeService_OutputReader(t *testing.T) {
+	ctx := context.Background()
+	outputType := output.Stdout
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*environmentMocks.MockEnvironment, task.Task) io.Reader
+		taskNotSet     bool
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "successful output scanner creation",
+			setupMocks: func(env *environmentMocks.MockEnvironment, tsk task.Task) io.Reader {
+				reader := bytes.NewReader([]byte("test output"))
+				env.On("Output", ctx, tsk, outputType).Return(reader, nil)
+				return reader
+			},
+			expectError: false,
+		},
+		{
+			name: "error during output fetching",
+			setupMocks: func(env *environmentMocks.MockEnvironment, tsk task.Task) io.Reader {
+				env.On("Output", ctx, tsk, outputType).Return(nil, errors.New("out err"))
+				return nil
+			},
+			expectError:    true,
+			expectedErrMsg: "out err",
+		},
+		{
+			name:           "error when task not set",
+			taskNotSet:     true,
+			expectError:    true,
+			expectedErrMsg: "service has not started yet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testingNativeService(t)
+			var expectedReader io.Reader
+			if tt.taskNotSet {
+				svc.task = nil
+			} else {
+				expectedReader = tt.setupMocks(svc.environment.(*environmentMocks.MockEnvironment), svc.task)
+			}
+
+			reader, err := svc.OutputReader(ctx, outputType)
+
+			if tt.expectError {
+				assert.Nil(t, reader)
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.Equal(t, expectedReader, reader)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_nativeService_OutputReaderSince(t *testing.T) {
+	ctx := context.Background()
+	outputType := output.Stdout
+	since := time.Date(2024, 5, 1, 10, 20, 30, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*environmentMocks.MockEnvironment, task.Task) io.Reader
+		taskNotSet     bool
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "successful output reader since time creation",
+			setupMocks: func(env *environmentMocks.MockEnvironment, tsk task.Task) io.Reader {
+				reader := bytes.NewReader([]byte("test output"))
+				env.On("OutputSince", ctx, tsk, outputType, since).Return(reader, nil)
+				return reader
+			},
+		},
+		{
+			name: "error during output since time fetching",
+			setupMocks: func(env *environmentMocks.MockEnvironment, tsk task.Task) io.Reader {
+				env.On("OutputSince", ctx, tsk, outputType, since).Return(nil, errors.New("out err"))
+				return nil
+			},
+			expectError:    true,
+			expectedErrMsg: "out err",
+		},
+		{
+			name:           "error when task not set",
+			taskNotSet:     true,
+			expectError:    true,
+			expectedErrMsg: "service has not started yet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testingNativeService(t)
+			var expectedReader io.Reader
+			if tt.taskNotSet {
+				svc.task = nil
+			} else {
+				expectedReader = tt.setupMocks(svc.environment.(*environmentMocks.MockEnvironment), svc.task)
+			}
+
+			reader, err := svc.OutputReaderSince(ctx, outputType, since)
+
+			if tt.expectError {
+				assert.Nil(t, reader)
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.Equal(t, expectedReader, reader)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_nativeService_ExecCommand(t *testing.T) {
+	ctx := context.Background()
+	cmd := &environment.Command{
+		Name: "test",
+		Args: []string{"arg1", "arg2"},
+	}
+
+	expectedServerPort := int32(8080)
+	expectedContainerConfig := &containers.ContainerConfig{
+		ImageName: "test-image",
+	}
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*environmentMocks.MockEnvironment, *serversMocks.MockServer, *sandboxMocks.MockSandbox, task.Task, output.Collector)
+		taskNotSet     bool
+		withCollector  bool
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "successful command execution with collector",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task, oc output.Collector) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("ExecTaskCommand", ctx, mock.MatchedBy(func(s *environment.ServiceSettings) bool {
+					return s.ServerPort == expectedServerPort && s.ContainerConfig == expectedContainerConfig
+				}), tsk, cmd, oc).Return(nil)
+
+			},
+			withCollector: true,
+			expectError:   false,
+		},
+		{
+			name: "successful command execution without collector",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task, oc output.Collector) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("ExecTaskCommand", ctx, mock.MatchedBy(func(s *environment.ServiceSettings) bool {
+					return s.ServerPort == expectedServerPort && s.ContainerConfig == expectedContainerConfig
+				}), tsk, cmd, nil).Return(nil)
+
+			},
+			withCollector: false,
+			expectError:   false,
+		},
+		{
+			name: "error during command execution",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task, oc output.Collector) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("ExecTaskCommand", ctx, mock.Anything, tsk, cmd, oc).Return(errors.New("execution error"))
+			},
+			withCollector:  true,
+			expectError:    true,
+			expectedErrMsg: "execution error",
+		},
+		{
+			name:           "error when task not set",
+			taskNotSet:     true,
+			expectError:    true,
+			expectedErrMsg: "service has not started yet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testingNativeService(t)
+
+			// Create server and sandbox mocks
+			serverMock := serversMocks.NewMockServer(t)
+			sandboxMock := sandboxMocks.NewMockSandbox(t)
+
+			// Set mocks on service
+			svc.server = serverMock
+			svc.sandbox = sandboxMock
+
+			if tt.taskNotSet {
+				svc.task = nil
+			}
+
+			var oc output.Collector
+			if tt.withCollector {
+				oc = outputMocks.NewMockCollector(t)
+			}
+
+			if tt.setupMocks != nil {
+				tt.setupMocks(svc.environment.(*environmentMocks.MockEnvironment), serverMock, sandboxMock, svc.task, oc)
+			}
+
+			err := svc.ExecCommand(ctx, cmd, oc)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Verify all mock expectations
+			serverMock.AssertExpectations(t)
+			sandboxMock.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_nativeService_FileExists(t *testing.T) {
+	ctx := context.Background()
+
+	expectedServerPort := int32(8080)
+	expectedContainerConfig := &containers.ContainerConfig{
+		ImageName: "test-image",
+	}
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*environmentMocks.MockEnvironment, *serversMocks.MockServer, *sandboxMocks.MockSandbox, task.Task)
+		taskNotSet     bool
+		want           bool
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "file exists",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("FileExists", ctx, mock.MatchedBy(func(s *environment.ServiceSettings) bool {
+					return s.ServerPort == expectedServerPort && s.ContainerConfig == expectedContainerConfig
+				}), tsk, "/run/svc/slow.log").Return(true, nil)
+			},
+			want: true,
+		},
+		{
+			name: "error during file check",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("FileExists", ctx, mock.Anything, tsk, "/run/svc/slow.log").Return(false, errors.New("check error"))
+			},
+			expectError:    true,
+			expectedErrMsg: "check error",
+		},
+		{
+			name:           "error when task not set",
+			taskNotSet:     true,
+			expectError:    true,
+			expectedErrMsg: "service has not started yet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testingNativeService(t)
+			serverMock := serversMocks.NewMockServer(t)
+			sandboxMock := sandboxMocks.NewMockSandbox(t)
+			svc.server = serverMock
+			svc.sandbox = sandboxMock
+
+			if tt.taskNotSet {
+				svc.task = nil
+			}
+
+			if tt.setupMocks != nil {
+				tt.setupMocks(svc.environment.(*environmentMocks.MockEnvironment), serverMock, sandboxMock, svc.task)
+			}
+
+			got, err := svc.FileExists(ctx, "/run/svc/slow.log")
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_nativeService_PortReady(t *testing.T) {
+	ctx := context.Background()
+
+	expectedServerPort := int32(8080)
+	expectedContainerConfig := &containers.ContainerConfig{
+		ImageName: "test-image",
+	}
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*environmentMocks.MockEnvironment, *serversMocks.MockServer, *sandboxMocks.MockSandbox, task.Task)
+		taskNotSet     bool
+		want           bool
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "port is ready",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("PortReady", ctx, mock.MatchedBy(func(s *environment.ServiceSettings) bool {
+					return s.ServerPort == expectedServerPort && s.ContainerConfig == expectedContainerConfig
+				}), tsk, int32(9000)).Return(true, nil)
+			},
+			want: true,
+		},
+		{
+			name: "error during port check",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("PortReady", ctx, mock.Anything, tsk, int32(9000)).Return(false, errors.New("check error"))
+			},
+			expectError:    true,
+			expectedErrMsg: "check error",
+		},
+		{
+			name:           "error when task not set",
+			taskNotSet:     true,
+			expectError:    true,
+			expectedErrMsg: "service has not started yet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testingNativeService(t)
+			serverMock := serversMocks.NewMockServer(t)
+			sandboxMock := sandboxMocks.NewMockSandbox(t)
+			svc.server = serverMock
+			svc.sandbox = sandboxMock
+
+			if tt.taskNotSet {
+				svc.task = nil
+			}
+
+			if tt.setupMocks != nil {
+				tt.setupMocks(svc.environment.(*environmentMocks.MockEnvironment), serverMock, sandboxMock, svc.task)
+			}
+
+			got, err := svc.PortReady(ctx, 9000)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_nativeService_UdsReady(t *testing.T) {
+	ctx := context.Background()
+
+	expectedServerPort := int32(8080)
+	expectedContainerConfig := &containers.ContainerConfig{
+		ImageName: "test-image",
+	}
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*environmentMocks.MockEnvironment, *serversMocks.MockServer, *sandboxMocks.MockSandbox, task.Task)
+		taskNotSet     bool
+		want           bool
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "socket is ready",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("UdsReady", ctx, mock.MatchedBy(func(s *environment.ServiceSettings) bool {
+					return s.ServerPort == expectedServerPort && s.ContainerConfig == expectedContainerConfig
+				}), tsk, "/run/svc/svc.sock").Return(true, nil)
+			},
+			want: true,
+		},
+		{
+			name: "error during socket check",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("UdsReady", ctx, mock.Anything, tsk, "/run/svc/svc.sock").Return(false, errors.New("check error"))
+			},
+			expectError:    true,
+			expectedErrMsg: "check error",
+		},
+		{
+			name:           "error when task not set",
+			taskNotSet:     true,
+			expectError:    true,
+			expectedErrMsg: "service has not started yet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testingNativeService(t)
+			serverMock := serversMocks.NewMockServer(t)
+			sandboxMock := sandboxMocks.NewMockSandbox(t)
+			svc.server = serverMock
+			svc.sandbox = sandboxMock
+
+			if tt.taskNotSet {
+				svc.task = nil
+			}
+
+			if tt.setupMocks != nil {
+				tt.setupMocks(svc.environment.(*environmentMocks.MockEnvironment), serverMock, sandboxMock, svc.task)
+			}
+
+			got, err := svc.UdsReady(ctx, "/run/svc/svc.sock")
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_nativeService_IsRunning(t *testing.T) {
+	ctx := context.Background()
+
+	expectedServerPort := int32(8080)
+	expectedContainerConfig := &containers.ContainerConfig{
+		ImageName: "test-image",
+	}
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*environmentMocks.MockEnvironment, *serversMocks.MockServer, *sandboxMocks.MockSandbox, task.Task)
+		taskNotSet     bool
+		want           bool
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "task is running",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("TaskRunning", ctx, mock.MatchedBy(func(s *environment.ServiceSettings) bool {
+					return s.ServerPort == expectedServerPort && s.ContainerConfig == expectedContainerConfig
+				}), tsk).Return(true, nil)
+			},
+			want: true,
+		},
+		{
+			name: "error during task check",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("TaskRunning", ctx, mock.Anything, tsk).Return(false, errors.New("check error"))
+			},
+			expectError:    true,
+			expectedErrMsg: "check error",
+		},
+		{
+			name:           "error when task not set",
+			taskNotSet:     true,
+			expectError:    true,
+			expectedErrMsg: "service has not started yet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testingNativeService(t)
+			serverMock := serversMocks.NewMockServer(t)
+			sandboxMock := sandboxMocks.NewMockSandbox(t)
+			svc.server = serverMock
+			svc.sandbox = sandboxMock
+
+			if tt.taskNotSet {
+				svc.task = nil
+			}
+
+			if tt.setupMocks != nil {
+				tt.setupMocks(svc.environment.(*environmentMocks.MockEnvironment), serverMock, sandboxMock, svc.task)
+			}
+
+			got, err := svc.IsRunning(ctx)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_nativeService_Processes(t *testing.T) {
+	ctx := context.Background()
+
+	expectedServerPort := int32(8080)
+	expectedContainerConfig := &containers.ContainerConfig{
+		ImageName: "test-image",
+	}
+	expectedProcesses := []environment.Process{
+		{Pid: 1, PPid: 0, Name: "php-fpm"},
+		{Pid: 7, PPid: 1, Name: "php-fpm"},
+	}
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*environmentMocks.MockEnvironment, *serversMocks.MockServer, *sandboxMocks.MockSandbox, task.Task)
+		taskNotSet     bool
+		want           []environment.Process
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "processes found",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("TaskProcesses", ctx, mock.MatchedBy(func(s *environment.ServiceSettings) bool {
+					return s.ServerPort == expectedServerPort && s.ContainerConfig == expectedContainerConfig
+				}), tsk).Return(expectedProcesses, nil)
+			},
+			want: expectedProcesses,
+		},
+		{
+			name: "error during processes listing",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("TaskProcesses", ctx, mock.Anything, tsk).Return(nil, errors.New("list error"))
+			},
+			expectError:    true,
+			expectedErrMsg: "list error",
+		},
+		{
+			name:           "error when task not set",
+			taskNotSet:     true,
+			expectError:    true,
+			expectedErrMsg: "service has not started yet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := testingNativeService(t)
+			serverMock := serversMocks.NewMockServer(t)
+			sandboxMock := sandboxMocks.NewMockSandbox(t)
+			svc.server = serverMock
+			svc.sandbox = sandboxMock
+
+			if tt.taskNotSet {
+				svc.task = nil
+			}
 
-func Test_nativeService_Group(t *testing.T) {
-	svc := testingNativeService(t)
-	svc.server.(*serversMocks.MockServer).On("Group").Return("usergroup")
-	assert.Equal(t, "usergroup", svc.Group())
-}
+			if tt.setupMocks != nil {
+				tt.setupMocks(svc.environment.(*environmentMocks.MockEnvironment), serverMock, sandboxMock, svc.task)
+			}
 
-func testSandboxDirs() map[dir.DirType]string {
-	return map[dir.DirType]string{
-		dir.ConfDirType:   "/conf/dir",
-		dir.RunDirType:    "/run/dir",
-		dir.ScriptDirType: "/script/dir",
+			got, err := svc.Processes(ctx)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
 	}
 }
 
-func Test_nativeService_Dirs(t *testing.T) {
-	svc := testingNativeService(t)
-	sandboxDirs := testSandboxDirs()
-	svc.sandbox.(*sandboxMocks.MockSandbox).On("Dirs").Return(sandboxDirs)
-	assert.Equal(t, sandboxDirs, svc.Dirs())
-}
+func Test_nativeService_File(t *testing.T) {
+	ctx := context.Background()
+
+	expectedServerPort := int32(8080)
+	expectedContainerConfig := &containers.ContainerConfig{
+		ImageName: "test-image",
+	}
+	expectedFile := &environment.File{
+		Mode:    0644,
+		Size:    4,
+		Content: []byte("log\n"),
+	}
 
-func testDirsGetters(t *testing.T, expectedDir string, cb func(svc *nativeService) (string, error)) {
 	tests := []struct {
-		name string
-		err  error
+		name           string
+		setupMocks     func(*environmentMocks.MockEnvironment, *serversMocks.MockServer, *sandboxMocks.MockSandbox, task.Task)
+		taskNotSet     bool
+		want           *environment.File
+		expectError    bool
+		expectedErrMsg string
 	}{
 		{
-			name: "successful result",
-			err:  nil,
+			name: "file found",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("TaskFile", ctx, mock.MatchedBy(func(s *environment.ServiceSettings) bool {
+					return s.ServerPort == expectedServerPort && s.ContainerConfig == expectedContainerConfig
+				}), tsk, "/run/fpm.log", int64(5)).Return(expectedFile, nil)
+			},
+			want: expectedFile,
 		},
 		{
-			name: "error during mkdir",
-			err:  errors.New("mkdir fail"),
+			name: "error during file reading",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("TaskFile", ctx, mock.Anything, tsk, "/run/fpm.log", int64(5)).Return(nil, errors.New("read error"))
+			},
+			expectError:    true,
+			expectedErrMsg: "read error",
+		},
+		{
+			name:           "error when task not set",
+			taskNotSet:     true,
+			expectError:    true,
+			expectedErrMsg: "service has not started yet",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			svc := testingNativeService(t)
-			svc.sandbox.(*sandboxMocks.MockSandbox).On("Dirs").Return(testSandboxDirs())
-			envMock := svc.environment.(*environmentMocks.MockEnvironment)
-			envMock.On("RootPath", "/tmp/ws/svc").Return("/ws")
-			envMock.On("Mkdir", "svc", expectedDir, os.FileMode(0755)).Return(tt.err)
-			result, err := cb(svc)
-			assert.Equal(t, expectedDir, result)
-			assert.Equal(t, err, tt.err)
-		})
-	}
-}
-
-func Test_nativeService_ConfDir(t *testing.T) {
-	testDirsGetters(t, "/ws/conf/dir/svc", func(svc *nativeService) (string, error) {
-		return svc.ConfDir()
-	})
-}
-
-func Test_nativeService_RunDir(t *testing.T) {
-	testDirsGetters(t, "/ws/run/dir/svc", func(svc *nativeService) (string, error) {
-		return svc.RunDir()
-	})
-}
-
-func Test_nativeService_ScriptDir(t *testing.T) {
-	testDirsGetters(t, "/ws/script/dir/svc", func(svc *nativeService) (string, error) {
-		return svc.ScriptDir()
-	})
-}
+			serverMock := serversMocks.NewMockServer(t)
+			sandboxMock := sandboxMocks.NewMockSandbox(t)
+			svc.server = serverMock
+			svc.sandbox = sandboxMock
 
-func Test_nativeService_Server(t *testing.T) {
-	svc := testingNativeService(t)
-	assert.Equal(t, svc.server, svc.Server())
-}
+			if tt.taskNotSet {
+				svc.task = nil
+			}
 
-func Test_nativeService_ServerParameters(t *testing.T) {
-	svc := testingNativeService(t)
-	assert.Equal(t, parameters.Parameters{"p1": parameterMocks.NewMockParameter(t)}, svc.ServerParameters())
-}
+			if tt.setupMocks != nil {
+				tt.setupMocks(svc.environment.(*environmentMocks.MockEnvironment), serverMock, sandboxMock, svc.task)
+			}
 
-func Test_nativeService_SetTemplate(t *testing.T) {
-	svc := testingNativeService(t)
-	mockTemplate := templateMocks.NewMockTemplate(t)
-	mockTemplate.TestData().Set("new", true)
-	svc.SetTemplate(mockTemplate)
-	assert.Equal(t, mockTemplate, svc.template)
-}
+			got, err := svc.File(ctx, "/run/fpm.log", 5)
 
-func Test_nativeService_Workspace(t *testing.T) {
-	svc := testingNativeService(t)
-	assert.Equal(t, "/tmp/ws/svc", svc.Workspace())
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
 }
 
-func Test_nativeService_OutputReader(t *testing.T) {
+func Test_nativeService_Signal(t *testing.T) {
 	ctx := context.Background()
-	outputType := output.Stdout
+
+	expectedServerPort := int32(8080)
+	expectedContainerConfig := &containers.ContainerConfig{
+		ImageName: "test-image",
+	}
 
 	tests := []struct {
 		name           string
-		setupMocks     func(*environmentMocks.MockEnvironment, task.Task) io.Reader
+		setupMocks     func(*environmentMocks.MockEnvironment, *serversMocks.MockServer, *sandboxMocks.MockSandbox, task.Task)
 		taskNotSet     bool
 		expectError    bool
 		expectedErrMsg string
 	}{
 		{
-			name: "successful output scanner creation",
-			setupMocks: func(env *environmentMocks.MockEnvironment, tsk task.Task) io.Reader {
-				reader := bytes.NewReader([]byte("test output"))
-				env.On("Output", ctx, tsk, outputType).Return(reader, nil)
-				return reader
+			name: "signal sent",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("ExecTaskSignal", ctx, mock.MatchedBy(func(s *environment.ServiceSettings) bool {
+					return s.ServerPort == expectedServerPort && s.ContainerConfig == expectedContainerConfig
+				}), tsk, syscall.SIGUSR1).Return(nil)
 			},
-			expectError: false,
 		},
 		{
-			name: "error during output fetching",
-			setupMocks: func(env *environmentMocks.MockEnvironment, tsk task.Task) io.Reader {
-				env.On("Output", ctx, tsk, outputType).Return(nil, errors.New("out err"))
-				return nil
+			name: "error during signal",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
+				srv.On("Port").Return(expectedServerPort)
+				sb.On("ContainerConfig").Return(expectedContainerConfig)
+				env.On("ExecTaskSignal", ctx, mock.Anything, tsk, syscall.SIGUSR1).Return(errors.New("signal error"))
 			},
 			expectError:    true,
-			expectedErrMsg: "out err",
+			expectedErrMsg: "signal error",
 		},
 		{
 			name:           "error when task not set",
@@ -1690,83 +2580,68 @@ func Test_nativeService_OutputReader(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			svc := testingNativeService(t)
-			var expectedReader io.Reader
+			serverMock := serversMocks.NewMockServer(t)
+			sandboxMock := sandboxMocks.NewMockSandbox(t)
+			svc.server = serverMock
+			svc.sandbox = sandboxMock
+
 			if tt.taskNotSet {
 				svc.task = nil
-			} else {
-				expectedReader = tt.setupMocks(svc.environment.(*environmentMocks.MockEnvironment), svc.task)
 			}
 
-			reader, err := svc.OutputReader(ctx, outputType)
+			if tt.setupMocks != nil {
+				tt.setupMocks(svc.environment.(*environmentMocks.MockEnvironment), serverMock, sandboxMock, svc.task)
+			}
+
+			err := svc.Signal(ctx, syscall.SIGUSR1)
 
 			if tt.expectError {
-				assert.Nil(t, reader)
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tt.expectedErrMsg)
 			} else {
-				assert.Equal(t, expectedReader, reader)
 				assert.NoError(t, err)
 			}
 		})
 	}
 }
 
-func Test_nativeService_ExecCommand(t *testing.T) {
+func Test_nativeService_SignalProcesses(t *testing.T) {
 	ctx := context.Background()
-	cmd := &environment.Command{
-		Name: "test",
-		Args: []string{"arg1", "arg2"},
-	}
 
 	expectedServerPort := int32(8080)
 	expectedContainerConfig := &containers.ContainerConfig{
 		ImageName: "test-image",
 	}
+	processes := []environment.Process{
+		{Pid: 7, PPid: 1, Name: "php-fpm", Instance: "p1"},
+	}
 
 	tests := []struct {
 		name           string
-		setupMocks     func(*environmentMocks.MockEnvironment, *serversMocks.MockServer, *sandboxMocks.MockSandbox, task.Task, output.Collector)
+		setupMocks     func(*environmentMocks.MockEnvironment, *serversMocks.MockServer, *sandboxMocks.MockSandbox, task.Task)
 		taskNotSet     bool
-		withCollector  bool
 		expectError    bool
 		expectedErrMsg string
 	}{
 		{
-			name: "successful command execution with collector",
-			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task, oc output.Collector) {
-				srv.On("Port").Return(expectedServerPort)
-				sb.On("ContainerConfig").Return(expectedContainerConfig)
-				env.On("ExecTaskCommand", ctx, mock.MatchedBy(func(s *environment.ServiceSettings) bool {
-					return s.ServerPort == expectedServerPort && s.ContainerConfig == expectedContainerConfig
-				}), tsk, cmd, oc).Return(nil)
-
-			},
-			withCollector: true,
-			expectError:   false,
-		},
-		{
-			name: "successful command execution without collector",
-			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task, oc output.Collector) {
+			name: "signal sent",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
 				srv.On("Port").Return(expectedServerPort)
 				sb.On("ContainerConfig").Return(expectedContainerConfig)
-				env.On("ExecTaskCommand", ctx, mock.MatchedBy(func(s *environment.ServiceSettings) bool {
+				env.On("ExecTaskProcessesSignal", ctx, mock.MatchedBy(func(s *environment.ServiceSettings) bool {
 					return s.ServerPort == expectedServerPort && s.ContainerConfig == expectedContainerConfig
-				}), tsk, cmd, nil).Return(nil)
-
+				}), tsk, processes, syscall.SIGUSR1).Return(nil)
 			},
-			withCollector: false,
-			expectError:   false,
 		},
 		{
-			name: "error during command execution",
-			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task, oc output.Collector) {
+			name: "error during signal",
+			setupMocks: func(env *environmentMocks.MockEnvironment, srv *serversMocks.MockServer, sb *sandboxMocks.MockSandbox, tsk task.Task) {
 				srv.On("Port").Return(expectedServerPort)
 				sb.On("ContainerConfig").Return(expectedContainerConfig)
-				env.On("ExecTaskCommand", ctx, mock.Anything, tsk, cmd, oc).Return(errors.New("execution error"))
+				env.On("ExecTaskProcessesSignal", ctx, mock.Anything, tsk, processes, syscall.SIGUSR1).Return(errors.New("signal error"))
 			},
-			withCollector:  true,
 			expectError:    true,
-			expectedErrMsg: "execution error",
+			expectedErrMsg: "signal error",
 		},
 		{
 			name:           "error when task not set",
@@ -1779,12 +2654,8 @@ func Test_nativeService_ExecCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			svc := testingNativeService(t)
-
-			// Create server and sandbox mocks
 			serverMock := serversMocks.NewMockServer(t)
 			sandboxMock := sandboxMocks.NewMockSandbox(t)
-
-			// Set mocks on service
 			svc.server = serverMock
 			svc.sandbox = sandboxMock
 
@@ -1792,16 +2663,11 @@ func Test_nativeService_ExecCommand(t *testing.T) {
 				svc.task = nil
 			}
 
-			var oc output.Collector
-			if tt.withCollector {
-				oc = outputMocks.NewMockCollector(t)
-			}
-
 			if tt.setupMocks != nil {
-				tt.setupMocks(svc.environment.(*environmentMocks.MockEnvironment), serverMock, sandboxMock, svc.task, oc)
+				tt.setupMocks(svc.environment.(*environmentMocks.MockEnvironment), serverMock, sandboxMock, svc.task)
 			}
 
-			err := svc.ExecCommand(ctx, cmd, oc)
+			err := svc.SignalProcesses(ctx, processes, syscall.SIGUSR1)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -1809,10 +2675,6 @@ func Test_nativeService_ExecCommand(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 			}
-
-			// Verify all mock expectations
-			serverMock.AssertExpectations(t)
-			sandboxMock.AssertExpectations(t)
 		})
 	}
 }
@@ -3274,6 +4136,11 @@ func Test_nativeService_Sandbox(t *testing.T) {
 	assert.Equal(t, svc.sandbox, svc.Sandbox())
 }
 
+func Test_nativeService_SandboxType(t *testing.T) {
+	svc := testingNativeService(t)
+	assert.Equal(t, providers.DockerType, svc.SandboxType())
+}
+
 func Test_nativeService_Environment(t *testing.T) {
 	svc := testingNativeService(t)
 	assert.Equal(t, svc.environment, svc.Environment())
@@ -3418,3 +4285,99 @@ func Test_nativeService_FindCertificate(t *testing.T) {
 		})
 	}
 }
+
+func Test_nativeService_FindScript(t *testing.T) {
+	schemaScript := createScriptMock(t, "schema")
+	otherScript := createScriptMock(t, "other")
+	tests := []struct {
+		name           string
+		scriptName     string
+		scripts        scripts.Scripts
+		expectedScript scripts.Script
+		expectedErrMsg string
+	}{
+		{
+			name:       "script found",
+			scriptName: "schema",
+			scripts: scripts.Scripts{
+				"schema": schemaScript,
+				"other":  otherScript,
+			},
+			expectedScript: schemaScript,
+		},
+		{
+			name:       "script not found",
+			scriptName: "missing",
+			scripts: scripts.Scripts{
+				"schema": schemaScript,
+			},
+			expectedErrMsg: "script missing not found",
+		},
+		{
+			name:           "nil scripts map",
+			scriptName:     "schema",
+			expectedErrMsg: "script schema not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &nativeService{
+				scripts: tt.scripts,
+			}
+
+			script, err := svc.FindScript(tt.scriptName)
+
+			if tt.expectedErrMsg != "" {
+				assert.EqualError(t, err, tt.expectedErrMsg)
+				assert.Nil(t, script)
+			} else {
+				assert.NoError(t, err)
+				assert.Same(t, tt.expectedScript, script)
+			}
+		})
+	}
+}
+func Test_nativeService_MockServerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	svc := testingNativeService(t)
+	svc.task = nil
+	svc.mock = mockserver.NewServer(
+		external.NewMockLogger().SugaredLogger,
+		&mockserver.Config{Routes: []*mockserver.Route{{Path: "/", Status: 200}}},
+	)
+	fndMock := svc.fnd.(*appMocks.MockFoundation)
+	fndMock.On("DryRun").Return(false)
+	svc.server.(*serversMocks.MockServer).On("Port").Return(int32(80))
+	svc.environment.(*environmentMocks.MockEnvironment).On(
+		"ServiceLocalAddress", "svc", int32(8500), int32(80)).Return("127.0.0.1:0")
+	assert.Same(t, svc.mock, svc.MockServer())
+
+	require.NoError(t, svc.Start(ctx))
+	running, err := svc.IsRunning(ctx)
+	require.NoError(t, err)
+	assert.True(t, running)
+	assert.NoError(t, svc.Reload(ctx))
+
+	require.NoError(t, svc.Restart(ctx))
+	running, err = svc.IsRunning(ctx)
+	require.NoError(t, err)
+	assert.True(t, running)
+
+	require.NoError(t, svc.Stop(ctx))
+	running, err = svc.IsRunning(ctx)
+	require.NoError(t, err)
+	assert.False(t, running)
+}
+
+func Test_nativeService_MockServerDryRun(t *testing.T) {
+	ctx := context.Background()
+	svc := testingNativeService(t)
+	svc.mock = mockserver.NewServer(external.NewMockLogger().SugaredLogger, &mockserver.Config{})
+	svc.fnd.(*appMocks.MockFoundation).On("DryRun").Return(true)
+
+	require.NoError(t, svc.Start(ctx))
+	running, err := svc.IsRunning(ctx)
+	require.NoError(t, err)
+	assert.False(t, running)
+}